@@ -0,0 +1,546 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v3"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// DefaultResponseCacheTTL is the default maximum time a response is
+	// cached for.
+	DefaultResponseCacheTTL = 30 * time.Second
+
+	// DefaultResponseCacheMaxEntries is the default maximum number of
+	// responses cached.
+	DefaultResponseCacheMaxEntries = 10000
+
+	// responseCacheEventType is the pattern of the events invalidating the
+	// cache: the ones sent by the KV secrets engines on writes.
+	responseCacheEventType = "kv*"
+)
+
+// ResponseCacheConfig is the configuration of a ResponseCache.
+type ResponseCacheConfig struct {
+	// TTL is the maximum time a response is cached for. Responses with a
+	// shorter lease duration are cached for their lease duration instead.
+	TTL time.Duration
+
+	// MaxEntries is the maximum number of responses cached. The responses
+	// expiring first are evicted to make room for new ones.
+	MaxEntries int
+
+	// Paths are the prefixes of the paths, relative to the namespace, whose
+	// reads are cached. If empty, the reads of every path outside of sys/
+	// are cached.
+	Paths []string
+
+	// DisableEvents disables the invalidation of the cache by events, so
+	// that responses are only invalidated once their TTL expires, or by
+	// writes sent to the same path through the cache.
+	DisableEvents bool
+
+	// Logger is used to log the errors of the event subscription.
+	Logger hclog.Logger
+}
+
+// ResponseCache caches the responses of read requests, such as KV reads and
+// token lookups, so that identical reads sent by a client are served without
+// a request to Vault.
+//
+// Unless events are disabled, the cache subscribes to the events of the KV
+// secrets engines of the namespace of its client, and invalidates the
+// responses of the secrets written, deleted or destroyed. This requires the
+// events experiment to be enabled, and the token of the client to be allowed
+// to read sys/events/subscribe/kv*. Until the subscription is established,
+// and while it is interrupted, requests bypass the cache. Requests in other
+// namespaces always bypass the cache then.
+//
+// Only successful responses without a lease, nor response wrapping, are
+// cached. Responses are cached per token, so that the cache never serves a
+// response to a token that was not allowed to read it.
+type ResponseCache struct {
+	client        *Client
+	ttl           time.Duration
+	maxEntries    int
+	paths         []string
+	disableEvents bool
+	logger        hclog.Logger
+
+	// namespace is the namespace events are subscribed in.
+	namespace string
+
+	l       sync.RWMutex
+	entries map[responseCacheKey]*responseCacheEntry
+	// live is set when the cache can be used: when events are disabled, or
+	// while the event subscription is established.
+	live bool
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+type responseCacheKey struct {
+	// path is the namespaced path of the request, without its query.
+	path  string
+	query string
+	// tokenHash is the hash of the token of the request.
+	tokenHash string
+}
+
+type responseCacheEntry struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	expiresAt  time.Time
+}
+
+// NewResponseCache returns a cache for the responses of the requests sent by
+// the clients using it, and starts its event subscription with the client,
+// unless disabled. Use Client.WithResponseCache to use the cache, and Stop to
+// stop the subscription.
+func NewResponseCache(client *Client, config *ResponseCacheConfig) (*ResponseCache, error) {
+	if client == nil {
+		return nil, errors.New("client is nil")
+	}
+	if config == nil {
+		config = &ResponseCacheConfig{}
+	}
+	if config.TTL < 0 {
+		return nil, errors.New("TTL must not be negative")
+	}
+	if config.MaxEntries < 0 {
+		return nil, errors.New("max entries must not be negative")
+	}
+
+	rc := &ResponseCache{
+		client:        client,
+		ttl:           config.TTL,
+		maxEntries:    config.MaxEntries,
+		paths:         config.Paths,
+		disableEvents: config.DisableEvents,
+		logger:        config.Logger,
+		namespace:     normalizeNamespace(client.Headers().Get(NamespaceHeaderName)),
+		entries:       make(map[responseCacheKey]*responseCacheEntry),
+		live:          config.DisableEvents,
+		doneCh:        make(chan struct{}),
+	}
+	if rc.ttl == 0 {
+		rc.ttl = DefaultResponseCacheTTL
+	}
+	if rc.maxEntries == 0 {
+		rc.maxEntries = DefaultResponseCacheMaxEntries
+	}
+	if rc.logger == nil {
+		rc.logger = hclog.NewNullLogger()
+	}
+
+	if rc.disableEvents {
+		close(rc.doneCh)
+		return rc, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rc.cancel = cancel
+	go rc.subscribe(ctx)
+	return rc, nil
+}
+
+// WithResponseCache makes a shallow clone of Client, modifies it to serve its
+// read requests from the cache, and returns it. A nil cache disables caching.
+func (c *Client) WithResponseCache(cache *ResponseCache) *Client {
+	c2 := *c
+	c2.modifyLock = sync.RWMutex{}
+	c2.responseCache = cache
+	return &c2
+}
+
+// Stop stops the event subscription of the cache, and purges it. Requests
+// bypass the cache once it is stopped.
+func (rc *ResponseCache) Stop() {
+	if rc.cancel != nil {
+		rc.cancel()
+		<-rc.doneCh
+	}
+
+	rc.l.Lock()
+	defer rc.l.Unlock()
+	rc.live = false
+	rc.entries = make(map[responseCacheKey]*responseCacheEntry)
+}
+
+// Purge removes every response from the cache.
+func (rc *ResponseCache) Purge() {
+	rc.l.Lock()
+	defer rc.l.Unlock()
+	rc.entries = make(map[responseCacheKey]*responseCacheEntry)
+}
+
+// Invalidate removes the responses of the path, relative to the namespace,
+// from the cache.
+func (rc *ResponseCache) Invalidate(namespace, path string) {
+	rc.invalidate(normalizeNamespace(namespace)+strings.TrimPrefix(path, "/"), false)
+}
+
+// Len returns the number of responses in the cache.
+func (rc *ResponseCache) Len() int {
+	rc.l.RLock()
+	defer rc.l.RUnlock()
+	return len(rc.entries)
+}
+
+// invalidate removes the responses of the namespaced path, or of the paths
+// under it if prefix is set, from the cache.
+func (rc *ResponseCache) invalidate(path string, prefix bool) {
+	rc.l.Lock()
+	defer rc.l.Unlock()
+	for key := range rc.entries {
+		if key.path == path || (prefix && strings.HasPrefix(key.path, path)) {
+			delete(rc.entries, key)
+		}
+	}
+}
+
+// request sends the request with the client, serving it from the cache when
+// possible.
+func (rc *ResponseCache) request(ctx context.Context, c *Client, r *Request, send func(context.Context, *Request) (*Response, error)) (*Response, error) {
+	key, cacheable := rc.key(c, r)
+	if !cacheable {
+		resp, err := send(ctx, r)
+		if err == nil && !isReadMethod(r.Method) {
+			// Don't serve the responses of the path changed by the request
+			rc.invalidate(key.path, false)
+		}
+		return resp, err
+	}
+
+	rc.l.RLock()
+	entry, ok := rc.entries[key]
+	live := rc.live
+	rc.l.RUnlock()
+	if !live {
+		return send(ctx, r)
+	}
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.response(r), nil
+	}
+
+	resp, err := send(ctx, r)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+
+	ttl, ok := rc.responseTTL(body)
+	if !ok {
+		return resp, nil
+	}
+	rc.store(key, &responseCacheEntry{
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		body:       body,
+		expiresAt:  time.Now().Add(ttl),
+	})
+	return resp, nil
+}
+
+// key returns the cache key of the request, and whether its response can be
+// cached.
+func (rc *ResponseCache) key(c *Client, r *Request) (responseCacheKey, bool) {
+	c.modifyLock.RLock()
+	ns := c.headers.Get(NamespaceHeaderName)
+	c.modifyLock.RUnlock()
+	ns = normalizeNamespace(ns)
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	key := responseCacheKey{
+		path:  ns + path,
+		query: r.Params.Encode(),
+	}
+
+	switch {
+	case r.Method != http.MethodGet, r.Body != nil, r.WrapTTL != "":
+		return key, false
+	case !rc.disableEvents && ns != rc.namespace:
+		return key, false
+	case !rc.cachedPath(path):
+		return key, false
+	}
+
+	hash := sha256.Sum256([]byte(r.ClientToken))
+	key.tokenHash = hex.EncodeToString(hash[:])
+	return key, true
+}
+
+func (rc *ResponseCache) cachedPath(path string) bool {
+	if len(rc.paths) == 0 {
+		return !strings.HasPrefix(path, "sys/")
+	}
+	for _, prefix := range rc.paths {
+		if strings.HasPrefix(path, strings.TrimPrefix(prefix, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// responseTTL returns how long the response can be cached for, and whether
+// it can be cached at all.
+func (rc *ResponseCache) responseTTL(body []byte) (time.Duration, bool) {
+	var secret struct {
+		LeaseID       string      `json:"lease_id"`
+		LeaseDuration int         `json:"lease_duration"`
+		WrapInfo      interface{} `json:"wrap_info"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return 0, false
+	}
+
+	// Each reader of a secret with a lease must get its own lease
+	if secret.LeaseID != "" || secret.WrapInfo != nil {
+		return 0, false
+	}
+
+	ttl := rc.ttl
+	if lease := time.Duration(secret.LeaseDuration) * time.Second; lease > 0 && lease < ttl {
+		ttl = lease
+	}
+	return ttl, true
+}
+
+func (rc *ResponseCache) store(key responseCacheKey, entry *responseCacheEntry) {
+	rc.l.Lock()
+	defer rc.l.Unlock()
+
+	// The event subscription may have been interrupted since the request
+	if !rc.live {
+		return
+	}
+
+	if _, ok := rc.entries[key]; !ok && len(rc.entries) >= rc.maxEntries {
+		rc.evict()
+	}
+	rc.entries[key] = entry
+}
+
+// evict removes the expired responses, or the one expiring first if none
+// expired.
+func (rc *ResponseCache) evict() {
+	now := time.Now()
+	var first responseCacheKey
+	var firstExpiresAt time.Time
+	for key, entry := range rc.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(rc.entries, key)
+			continue
+		}
+		if firstExpiresAt.IsZero() || entry.expiresAt.Before(firstExpiresAt) {
+			first, firstExpiresAt = key, entry.expiresAt
+		}
+	}
+	if len(rc.entries) >= rc.maxEntries {
+		delete(rc.entries, first)
+	}
+}
+
+func (e *responseCacheEntry) response(r *Request) *Response {
+	return &Response{Response: &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.statusCode, http.StatusText(e.statusCode)),
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request: &http.Request{
+			Method: r.Method,
+			URL:    r.URL,
+			Header: r.Headers,
+		},
+	}}
+}
+
+// setLive sets whether the cache can be used, purging it.
+func (rc *ResponseCache) setLive(live bool) {
+	rc.l.Lock()
+	defer rc.l.Unlock()
+	rc.live = live
+	rc.entries = make(map[responseCacheKey]*responseCacheEntry)
+}
+
+// subscribe subscribes to the events invalidating the cache until the
+// context is canceled, resubscribing when the subscription is interrupted.
+func (rc *ResponseCache) subscribe(ctx context.Context) {
+	defer close(rc.doneCh)
+
+	errorBackoff := &backoff.ExponentialBackOff{
+		InitialInterval:     time.Second,
+		RandomizationFactor: backoff.DefaultRandomizationFactor,
+		Multiplier:          backoff.DefaultMultiplier,
+		MaxInterval:         time.Minute,
+		MaxElapsedTime:      0,
+		Clock:               backoff.SystemClock,
+	}
+	errorBackoff.Reset()
+
+	for {
+		err := rc.subscribeOnce(ctx, errorBackoff.Reset)
+		// Events may be missed until the next subscription
+		rc.setLive(false)
+		if ctx.Err() != nil {
+			return
+		}
+		rc.logger.Warn("response cache event subscription interrupted", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(errorBackoff.NextBackOff()):
+		}
+	}
+}
+
+// subscribeOnce streams the events invalidating the cache as server-sent
+// events, calling established once the subscription is established.
+func (rc *ResponseCache) subscribeOnce(ctx context.Context, established func()) error {
+	r := rc.client.NewRequest(http.MethodGet, "/v1/sys/events/subscribe/"+responseCacheEventType)
+	r.Params.Set("json", "true")
+	req, err := r.ToHTTP()
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The subscription is long-lived, so don't apply the timeout of the
+	// client to it
+	httpClient := rc.client.CloneConfig().HttpClient
+	resp, err := (&http.Client{
+		Transport:     httpClient.Transport,
+		CheckRedirect: httpClient.CheckRedirect,
+		Jar:           httpClient.Jar,
+	}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		result := &Response{Response: resp}
+		if err := result.Error(); err != nil {
+			return err
+		}
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	rc.setLive(true)
+	established()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		rc.handleEvent([]byte(strings.TrimPrefix(line, "data: ")))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// responseCacheEvent is the subset of the CloudEvents JSON format of the
+// events used to invalidate the cache.
+type responseCacheEvent struct {
+	Data struct {
+		Event struct {
+			Metadata map[string]interface{} `json:"metadata"`
+		} `json:"event"`
+		EventType  string `json:"event_type"`
+		Namespace  string `json:"namespace"`
+		PluginInfo struct {
+			MountPath string `json:"mount_path"`
+		} `json:"plugin_info"`
+	} `json:"data"`
+}
+
+// handleEvent invalidates the responses of the secret changed by the event.
+// The responses of the whole mount are invalidated when the secret cannot be
+// told, and the whole cache when the mount cannot be.
+func (rc *ResponseCache) handleEvent(data []byte) {
+	var event responseCacheEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		rc.logger.Warn("error decoding event, purging the response cache", "error", err)
+		rc.Purge()
+		return
+	}
+
+	mount := event.Data.PluginInfo.MountPath
+	if mount == "" {
+		rc.Purge()
+		return
+	}
+	mount = normalizeNamespace(event.Data.Namespace) + strings.TrimSuffix(strings.TrimPrefix(mount, "/"), "/") + "/"
+	path, _ := event.Data.Event.Metadata["path"].(string)
+
+	switch {
+	case path == "":
+		rc.invalidate(mount, true)
+
+	case strings.HasPrefix(event.Data.EventType, "kv-v1/"):
+		rc.invalidate(mount+path, false)
+
+	default:
+		// The KV v2 events are sent with the path of the request, such as
+		// data/<key> or destroy/<key>, which change the data and metadata
+		// of the secret
+		endpoint, key, ok := strings.Cut(path, "/")
+		switch endpoint {
+		case "data", "metadata", "delete", "undelete", "destroy":
+		default:
+			ok = false
+		}
+		if !ok {
+			rc.invalidate(mount, true)
+			return
+		}
+		rc.invalidate(mount+"data/"+key, false)
+		rc.invalidate(mount+"metadata/"+key, false)
+	}
+}
+
+// normalizeNamespace returns the namespace path without a leading slash and
+// with a trailing slash, or an empty string for the root namespace.
+func normalizeNamespace(ns string) string {
+	ns = strings.Trim(ns, "/")
+	if ns == "" {
+		return ""
+	}
+	return ns + "/"
+}
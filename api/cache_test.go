@@ -0,0 +1,221 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testCacheServer struct {
+	reads  int32
+	events chan string
+}
+
+func (s *testCacheServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.URL.Path {
+	case "/v1/sys/events/subscribe/kv*":
+		if req.Header.Get("Accept") != "text/event-stream" || req.Header.Get(AuthHeaderName) == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case <-req.Context().Done():
+				return
+			case event := <-s.events:
+				fmt.Fprintf(w, "event: kv\ndata: %s\n\n", event)
+				w.(http.Flusher).Flush()
+			}
+		}
+
+	case "/v1/secret/data/foo", "/v1/secret/bar", "/v1/sys/mounts":
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		reads := atomic.AddInt32(&s.reads, 1)
+		fmt.Fprintf(w, `{"data": {"reads": %d}, "lease_duration": 60}`, reads)
+
+	case "/v1/database/creds/foo":
+		atomic.AddInt32(&s.reads, 1)
+		w.Write([]byte(`{"lease_id": "database/creds/foo/bar", "data": {}}`))
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func testCacheClient(t *testing.T) (*Client, *testCacheServer) {
+	t.Helper()
+
+	server := &testCacheServer{events: make(chan string)}
+	config, ln := testHTTPServer(t, server)
+	t.Cleanup(func() { ln.Close() })
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetToken("foo")
+	return client, server
+}
+
+func testCacheRead(t *testing.T, client *Client, path string) interface{} {
+	t.Helper()
+
+	secret, err := client.Logical().Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret.Data["reads"]
+}
+
+func TestResponseCache(t *testing.T) {
+	client, server := testCacheClient(t)
+
+	cache, err := NewResponseCache(client, &ResponseCacheConfig{DisableEvents: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+	cached := client.WithResponseCache(cache)
+
+	first := testCacheRead(t, cached, "secret/data/foo")
+	if second := testCacheRead(t, cached, "secret/data/foo"); second != first {
+		t.Fatalf("expected a cached response, got %v after %v", second, first)
+	}
+	if server.reads != 1 {
+		t.Fatalf("expected 1 read, got %d", server.reads)
+	}
+
+	// Responses are cached per token
+	other := cached.WithResponseCache(cache)
+	other.SetToken("bar")
+	testCacheRead(t, other, "secret/data/foo")
+	if server.reads != 2 {
+		t.Fatalf("expected 2 reads, got %d", server.reads)
+	}
+
+	// Writes invalidate the responses of the path
+	if _, err := cached.Logical().Write("secret/data/foo", map[string]interface{}{"foo": "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	if third := testCacheRead(t, cached, "secret/data/foo"); third == first {
+		t.Fatal("expected the response to be invalidated")
+	}
+
+	// Responses with leases and sys/ responses are not cached
+	for _, path := range []string{"database/creds/foo", "sys/mounts"} {
+		reads := server.reads
+		testCacheRead(t, cached, path)
+		testCacheRead(t, cached, path)
+		if server.reads != reads+2 {
+			t.Fatalf("expected the responses of %s not to be cached", path)
+		}
+	}
+
+	cache.Purge()
+	if cache.Len() != 0 {
+		t.Fatalf("expected the cache to be empty, got %d entries", cache.Len())
+	}
+}
+
+func TestResponseCache_TTL(t *testing.T) {
+	client, server := testCacheClient(t)
+
+	cache, err := NewResponseCache(client, &ResponseCacheConfig{
+		TTL:           50 * time.Millisecond,
+		MaxEntries:    1,
+		DisableEvents: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+	client = client.WithResponseCache(cache)
+
+	testCacheRead(t, client, "secret/data/foo")
+	testCacheRead(t, client, "secret/bar")
+	if cache.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", cache.Len())
+	}
+
+	first := testCacheRead(t, client, "secret/bar")
+	time.Sleep(100 * time.Millisecond)
+	if second := testCacheRead(t, client, "secret/bar"); second == first {
+		t.Fatal("expected the response to expire")
+	}
+	if server.reads != 3 {
+		t.Fatalf("expected 3 reads, got %d", server.reads)
+	}
+}
+
+func TestResponseCache_Events(t *testing.T) {
+	client, server := testCacheClient(t)
+
+	cache, err := NewResponseCache(client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Stop()
+	cached := client.WithResponseCache(cache)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		cache.l.RLock()
+		live := cache.live
+		cache.l.RUnlock()
+		if live {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the event subscription was not established")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	first := testCacheRead(t, cached, "secret/data/foo")
+	if second := testCacheRead(t, cached, "secret/data/foo"); second != first {
+		t.Fatalf("expected a cached response, got %v after %v", second, first)
+	}
+	testCacheRead(t, cached, "secret/bar")
+
+	// Requests in other namespaces bypass the cache
+	reads := server.reads
+	testCacheRead(t, cached.WithNamespace("ns1"), "secret/bar")
+	testCacheRead(t, cached.WithNamespace("ns1"), "secret/bar")
+	if server.reads != reads+2 {
+		t.Fatal("expected the responses of other namespaces not to be cached")
+	}
+
+	server.events <- `{"data": {"event": {"metadata": {"path": "destroy/foo"}}, "event_type": "kv-v2/destroy", "plugin_info": {"mount_path": "secret/"}}}`
+	server.events <- `{"data": {"event": {"metadata": {"path": "foo"}}, "event_type": "kv-v1/write", "plugin_info": {"mount_path": "secret/"}}}`
+	testWaitCacheLen(t, cache, 1)
+	if third := testCacheRead(t, cached, "secret/data/foo"); third == first {
+		t.Fatal("expected the response to be invalidated")
+	}
+
+	server.events <- `{"data": {"event": {"metadata": {"path": "config"}}, "event_type": "kv-v2/config-write", "plugin_info": {"mount_path": "secret/"}}}`
+	server.events <- `{"data": {"event": {"metadata": {"path": "foo"}}, "event_type": "kv-v1/write", "plugin_info": {"mount_path": "other/"}}}`
+	testWaitCacheLen(t, cache, 0)
+}
+
+func testWaitCacheLen(t *testing.T, cache *ResponseCache, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cache.Len() != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d entries, got %d", expected, cache.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -579,6 +579,9 @@ type Client struct {
 	requestCallbacks      []RequestCallback
 	responseCallbacks     []ResponseCallback
 	replicationStateStore *replicationStateStore
+	retryPolicy           *RetryPolicy
+	hedgePolicy           *HedgePolicy
+	responseCache         *ResponseCache
 }
 
 // NewClient returns a new client for the given configuration.
@@ -1304,8 +1307,38 @@ func (c *Client) RawRequestWithContext(ctx context.Context, r *Request) (*Respon
 }
 
 func (c *Client) rawRequestWithContext(ctx context.Context, r *Request) (*Response, error) {
+	c.modifyLock.RLock()
+	responseCache := c.responseCache
+	c.config.modifyLock.RLock()
+	outputRequest := c.config.OutputCurlString || c.config.OutputPolicy
+	c.config.modifyLock.RUnlock()
+	c.modifyLock.RUnlock()
+
+	if responseCache != nil && !outputRequest {
+		return responseCache.request(ctx, c, r, c.sendRequest)
+	}
+	return c.sendRequest(ctx, r)
+}
+
+func (c *Client) sendRequest(ctx context.Context, r *Request) (*Response, error) {
+	c.modifyLock.RLock()
+	hedgePolicy := c.hedgePolicy
+	c.config.modifyLock.RLock()
+	outputRequest := c.config.OutputCurlString || c.config.OutputPolicy
+	c.config.modifyLock.RUnlock()
+	c.modifyLock.RUnlock()
+
+	if hedgePolicy != nil && len(hedgePolicy.Addresses) > 0 && !outputRequest &&
+		isReadMethod(r.Method) && r.Body == nil {
+		return c.hedgedRequest(ctx, r, hedgePolicy)
+	}
+	return c.doRequest(ctx, r)
+}
+
+func (c *Client) doRequest(ctx context.Context, r *Request) (*Response, error) {
 	c.modifyLock.RLock()
 	token := c.token
+	retryPolicy := c.retryPolicy
 
 	c.config.modifyLock.RLock()
 	limiter := c.config.Limiter
@@ -1391,6 +1424,20 @@ START:
 		checkRetry = DefaultRetryPolicy
 	}
 
+	if retryPolicy != nil {
+		maxRetries = retryPolicy.MaxRetries
+		if retryPolicy.MinRetryWait != 0 {
+			minRetryWait = retryPolicy.MinRetryWait
+		}
+		if retryPolicy.MaxRetryWait != 0 {
+			maxRetryWait = retryPolicy.MaxRetryWait
+		}
+		if retryPolicy.Backoff != nil {
+			backoff = retryPolicy.Backoff
+		}
+		checkRetry = retryPolicy.checkRetry(r.Method)
+	}
+
 	client := &retryablehttp.Client{
 		HTTPClient:   httpClient,
 		RetryWaitMin: minRetryWait,
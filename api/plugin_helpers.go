@@ -40,7 +40,11 @@ const (
 // path matches that path or not (useful specifically for the paths that
 // contain templated fields.)
 var sudoPaths = map[string]*regexp.Regexp{
-	"/auth/token/accessors/":                        regexp.MustCompile(`^/auth/token/accessors/?$`),
+	"/auth/token/accessors/":                    regexp.MustCompile(`^/auth/token/accessors/?$`),
+	"/auth/token/batch-tokens":                  regexp.MustCompile(`^/auth/token/batch-tokens$`),
+	"/auth/token/batch-tokens/revoke":           regexp.MustCompile(`^/auth/token/batch-tokens/revoke$`),
+	"/auth/token/batch-tokens/revocations":      regexp.MustCompile(`^/auth/token/batch-tokens/revocations/?$`),
+	"/auth/token/batch-tokens/revocations/{id}": regexp.MustCompile(`^/auth/token/batch-tokens/revocations/[^/]+$`),
 	"/pki/root":                                     regexp.MustCompile(`^/pki/root$`),
 	"/pki/root/sign-self-issued":                    regexp.MustCompile(`^/pki/root/sign-self-issued$`),
 	"/sys/audit":                                    regexp.MustCompile(`^/sys/audit$`),
@@ -52,6 +56,7 @@ var sudoPaths = map[string]*regexp.Regexp{
 	"/sys/config/cors":                              regexp.MustCompile(`^/sys/config/cors$`),
 	"/sys/config/ui/headers/":                       regexp.MustCompile(`^/sys/config/ui/headers/?$`),
 	"/sys/config/ui/headers/{header}":               regexp.MustCompile(`^/sys/config/ui/headers/.+$`),
+	"/sys/health/mounts":                            regexp.MustCompile(`^/sys/health/mounts$`),
 	"/sys/leases":                                   regexp.MustCompile(`^/sys/leases$`),
 	"/sys/leases/lookup/":                           regexp.MustCompile(`^/sys/leases/lookup/?$`),
 	"/sys/leases/lookup/{prefix}":                   regexp.MustCompile(`^/sys/leases/lookup/.+$`),
@@ -60,12 +65,17 @@ var sudoPaths = map[string]*regexp.Regexp{
 	"/sys/plugins/catalog/{name}":                   regexp.MustCompile(`^/sys/plugins/catalog/[^/]+$`),
 	"/sys/plugins/catalog/{type}":                   regexp.MustCompile(`^/sys/plugins/catalog/[\w-]+$`),
 	"/sys/plugins/catalog/{type}/{name}":            regexp.MustCompile(`^/sys/plugins/catalog/[\w-]+/[^/]+$`),
+	"/sys/plugins/runtime/status":                   regexp.MustCompile(`^/sys/plugins/runtime/status$`),
 	"/sys/raw":                                      regexp.MustCompile(`^/sys/raw$`),
 	"/sys/raw/{path}":                               regexp.MustCompile(`^/sys/raw/.+$`),
 	"/sys/remount":                                  regexp.MustCompile(`^/sys/remount$`),
 	"/sys/revoke-force/{prefix}":                    regexp.MustCompile(`^/sys/revoke-force/.+$`),
 	"/sys/revoke-prefix/{prefix}":                   regexp.MustCompile(`^/sys/revoke-prefix/.+$`),
 	"/sys/rotate":                                   regexp.MustCompile(`^/sys/rotate$`),
+	"/sys/sealwrap/config":                          regexp.MustCompile(`^/sys/sealwrap/config$`),
+	"/sys/sealwrap/rewrap":                          regexp.MustCompile(`^/sys/sealwrap/rewrap$`),
+	"/sys/storage/raft/compact":                     regexp.MustCompile(`^/sys/storage/raft/compact$`),
+	"/sys/wrapping/deliver":                         regexp.MustCompile(`^/sys/wrapping/deliver$`),
 	"/sys/internal/inspect/router/{tag}":            regexp.MustCompile(`^/sys/internal/inspect/router/.+$`),
 
 	// enterprise-only paths
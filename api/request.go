@@ -53,6 +53,22 @@ func (r *Request) SetJSONBody(val interface{}) error {
 	return nil
 }
 
+// clone returns a copy of the request that can be sent concurrently with it.
+// The body of the request is not copied.
+func (r *Request) clone() *Request {
+	r2 := *r
+	if r.URL != nil {
+		u := *r.URL
+		r2.URL = &u
+	}
+	r2.Params = make(url.Values, len(r.Params))
+	for k, v := range r.Params {
+		r2.Params[k] = append([]string(nil), v...)
+	}
+	r2.Headers = r.Headers.Clone()
+	return &r2
+}
+
 // ResetJSONBody is used to reset the body for a redirect
 func (r *Request) ResetJSONBody() error {
 	if r.BodyBytes == nil {
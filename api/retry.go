@@ -0,0 +1,287 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// RetryPolicy controls how the requests of a client are retried, in place of
+// the retry settings of its configuration. It is set per call with
+// WithRetryPolicy.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of times a request is retried. Zero
+	// disables retrying.
+	MaxRetries int
+
+	// MinRetryWait and MaxRetryWait bound the time to wait between attempts.
+	// They default to the ones of the client configuration when zero.
+	MinRetryWait time.Duration
+	MaxRetryWait time.Duration
+
+	// Backoff computes the time to wait between attempts. It defaults to the
+	// backoff of the client configuration when nil.
+	Backoff retryablehttp.Backoff
+
+	// RetryableStatusCodes are the response status codes retried, in addition
+	// to connection errors. If empty, the responses are retried according to
+	// CheckRetry.
+	RetryableStatusCodes []int
+
+	// CheckRetry decides whether to retry a request when RetryableStatusCodes
+	// is empty. It defaults to DefaultRetryPolicy when nil.
+	CheckRetry retryablehttp.CheckRetry
+
+	// Idempotent hints that the request can safely be repeated. Reads are
+	// always considered idempotent. Other requests are only retried when
+	// Vault did not process them: when the connection could not be made, or
+	// on 412, 429 and 503 responses.
+	Idempotent bool
+}
+
+// WithRetryPolicy makes a shallow clone of Client, modifies it to retry its
+// requests according to the policy instead of the retry settings of its
+// configuration, and returns it. A nil policy restores these settings.
+func (c *Client) WithRetryPolicy(policy *RetryPolicy) *Client {
+	c2 := *c
+	c2.modifyLock = sync.RWMutex{}
+	c2.retryPolicy = policy
+	return &c2
+}
+
+// checkRetry returns the CheckRetry function implementing the policy for
+// requests with the given method.
+func (p *RetryPolicy) checkRetry(method string) retryablehttp.CheckRetry {
+	check := p.CheckRetry
+	switch {
+	case len(p.RetryableStatusCodes) > 0:
+		check = p.checkStatusCodes
+	case check == nil:
+		check = DefaultRetryPolicy
+	}
+
+	if p.Idempotent || isReadMethod(method) {
+		return check
+	}
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		retry, checkErr := check(ctx, resp, err)
+		if !retry || checkErr != nil {
+			return retry, checkErr
+		}
+		return notProcessed(resp, err), nil
+	}
+}
+
+func (p *RetryPolicy) checkStatusCodes(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if err != nil {
+		// Let the default policy tell the connection errors worth retrying
+		// from the permanent ones, such as invalid certificates.
+		return retryablehttp.DefaultRetryPolicy(ctx, nil, err)
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// notProcessed returns whether the request certainly was not processed by
+// Vault, given its response or error.
+func notProcessed(resp *http.Response, err error) bool {
+	if err != nil {
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+	switch resp.StatusCode {
+	case http.StatusPreconditionFailed, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+func isReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, "LIST":
+		return true
+	}
+	return false
+}
+
+// HedgePolicy controls hedged reads: when a read request gets no response
+// within Delay, the same request is also sent to the next of Addresses, and
+// the first response is used. The requests still in flight are canceled.
+//
+// Addresses are usually those of performance standbys, which serve reads
+// locally. Combine hedged reads with ReadYourWrites to make sure standbys
+// don't serve stale data.
+type HedgePolicy struct {
+	// Addresses are the addresses of the Vault nodes hedged requests are
+	// sent to, in order.
+	Addresses []string
+
+	// Delay is how long to wait for a response before sending the next
+	// hedged request. Requests failing with connection errors, 412, 429 or
+	// 5xx responses are hedged immediately.
+	Delay time.Duration
+}
+
+// WithHedgePolicy makes a shallow clone of Client, modifies it to hedge its
+// read requests according to the policy, and returns it. A nil policy
+// disables hedging.
+func (c *Client) WithHedgePolicy(policy *HedgePolicy) *Client {
+	c2 := *c
+	c2.modifyLock = sync.RWMutex{}
+	c2.hedgePolicy = policy
+	return &c2
+}
+
+type hedgeResult struct {
+	// attempt is the index of the request in the hedged requests
+	attempt int
+
+	resp   *Response
+	err    error
+	cancel context.CancelFunc
+}
+
+// hedgedRequest sends the request, and hedged copies of it to the addresses
+// of the policy, and returns the first response.
+func (c *Client) hedgedRequest(ctx context.Context, r *Request, policy *HedgePolicy) (*Response, error) {
+	targets := []*url.URL{nil}
+	for _, addr := range policy.Addresses {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hedge address %q: %w", addr, err)
+		}
+		targets = append(targets, u)
+	}
+
+	results := make(chan hedgeResult, len(targets))
+	var cancels []context.CancelFunc
+	launch := func(target *url.URL) {
+		req := r.clone()
+		if target != nil {
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.Host = target.Host
+		}
+
+		attemptCtx, cancel := context.WithCancel(ctx)
+		attempt := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := c.doRequest(attemptCtx, req)
+			results <- hedgeResult{attempt: attempt, resp: resp, err: err, cancel: cancel}
+		}()
+	}
+
+	launch(targets[0])
+	next, pending := 1, 1
+	timer := time.NewTimer(policy.Delay)
+	defer timer.Stop()
+
+	var last hedgeResult
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if next < len(targets) {
+				launch(targets[next])
+				next++
+				pending++
+				timer.Reset(policy.Delay)
+			}
+
+		case res := <-results:
+			pending--
+			if !hedgeable(res) {
+				// Cancel the requests still in flight, and release the
+				// context of this one once its body is closed.
+				for i, cancel := range cancels {
+					if i != res.attempt {
+						cancel()
+					}
+				}
+				go drainHedgeResults(results, pending)
+				if res.resp != nil {
+					res.resp.Body = &cancelOnClose{ReadCloser: res.resp.Body, cancel: res.cancel}
+				} else {
+					res.cancel()
+				}
+				return res.resp, res.err
+			}
+
+			if last.cancel != nil {
+				last.close()
+			}
+			last = res
+			if next < len(targets) && ctx.Err() == nil {
+				launch(targets[next])
+				next++
+				pending++
+				timer.Reset(policy.Delay)
+			}
+		}
+	}
+
+	// Every request failed, so return the last failure
+	if last.resp != nil {
+		last.resp.Body = &cancelOnClose{ReadCloser: last.resp.Body, cancel: last.cancel}
+	} else {
+		last.cancel()
+	}
+	return last.resp, last.err
+}
+
+// hedgeable returns whether the request should be hedged after this result.
+func hedgeable(res hedgeResult) bool {
+	if res.err == nil {
+		return false
+	}
+	if res.resp == nil {
+		return true
+	}
+	code := res.resp.StatusCode
+	return code == http.StatusPreconditionFailed || code == http.StatusTooManyRequests || code >= 500
+}
+
+func (res hedgeResult) close() {
+	if res.resp != nil {
+		res.resp.Body.Close()
+	}
+	res.cancel()
+}
+
+// drainHedgeResults cancels the requests still in flight, and releases their
+// results.
+func drainHedgeResults(results chan hedgeResult, pending int) {
+	for i := 0; i < pending; i++ {
+		res := <-results
+		res.close()
+	}
+}
+
+// cancelOnClose cancels the context of a request when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
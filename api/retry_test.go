@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	config, ln := testHTTPServer(t, handler)
+	t.Cleanup(func() { ln.Close() })
+	config.MaxRetries = 0
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetToken("foo")
+	return client
+}
+
+func TestClient_WithRetryPolicy(t *testing.T) {
+	var calls int32
+	client := testRetryClient(t, func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"data": {"foo": "bar"}}`))
+	})
+
+	// Without a policy, the configuration disables retries
+	if _, err := client.Logical().Read("secret/foo"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	secret, err := client.WithRetryPolicy(&RetryPolicy{
+		MaxRetries:           3,
+		MinRetryWait:         time.Millisecond,
+		MaxRetryWait:         time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusInternalServerError},
+	}).Logical().Read("secret/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Data["foo"] != "bar" {
+		t.Fatalf("bad: %#v", secret.Data)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestClient_WithRetryPolicy_Writes(t *testing.T) {
+	var calls int32
+	var status int32
+	client := testRetryClient(t, func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(int(atomic.LoadInt32(&status)))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	policy := &RetryPolicy{
+		MaxRetries:   3,
+		MinRetryWait: time.Millisecond,
+		MaxRetryWait: time.Millisecond,
+	}
+	data := map[string]interface{}{"foo": "bar"}
+
+	cases := []struct {
+		name       string
+		status     int32
+		idempotent bool
+		calls      int32
+	}{
+		{"server error", http.StatusInternalServerError, false, 1},
+		{"idempotent server error", http.StatusInternalServerError, true, 2},
+		{"unavailable", http.StatusServiceUnavailable, false, 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
+			atomic.StoreInt32(&status, tc.status)
+			p := *policy
+			p.Idempotent = tc.idempotent
+
+			_, err := client.WithRetryPolicy(&p).Logical().Write("secret/foo", data)
+			if (tc.calls == 1) != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if calls != tc.calls {
+				t.Fatalf("expected %d calls, got %d", tc.calls, calls)
+			}
+		})
+	}
+}
+
+func TestClient_WithHedgePolicy(t *testing.T) {
+	canceled := make(chan struct{})
+	primary := testRetryClient(t, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		select {
+		case <-req.Context().Done():
+			close(canceled)
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	var hedged int32
+	secondaryConfig, ln := testHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&hedged, 1)
+		if req.Header.Get(AuthHeaderName) != "foo" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data": {"foo": "bar"}}`))
+	}))
+	defer ln.Close()
+
+	client := primary.WithHedgePolicy(&HedgePolicy{
+		Addresses: []string{secondaryConfig.Address},
+		Delay:     10 * time.Millisecond,
+	})
+
+	secret, err := client.Logical().Read("secret/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Data["foo"] != "bar" {
+		t.Fatalf("bad: %#v", secret.Data)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the primary request was not canceled")
+	}
+
+	// Writes are not hedged
+	atomic.StoreInt32(&hedged, 0)
+	if _, err := client.Logical().Write("secret/foo", map[string]interface{}{"foo": "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	if hedged != 0 {
+		t.Fatalf("expected no hedged requests, got %d", hedged)
+	}
+}
+
+func TestClient_WithHedgePolicy_Errors(t *testing.T) {
+	primary := testRetryClient(t, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	var status int32 = http.StatusOK
+	secondaryConfig, ln := testHTTPServer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		w.Write([]byte(`{"data": {"foo": "bar"}}`))
+	}))
+	defer ln.Close()
+
+	// The primary failing, the request is hedged without waiting
+	client := primary.WithHedgePolicy(&HedgePolicy{
+		Addresses: []string{secondaryConfig.Address},
+		Delay:     time.Hour,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	secret, err := client.Logical().ReadWithContext(ctx, "secret/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Data["foo"] != "bar" {
+		t.Fatalf("bad: %#v", secret.Data)
+	}
+
+	// Every request failing, the last error is returned
+	atomic.StoreInt32(&status, http.StatusBadGateway)
+	_, err = client.Logical().ReadWithContext(ctx, "secret/foo")
+	respErr, ok := err.(*ResponseError)
+	if !ok || respErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("bad: %v", err)
+	}
+
+	if _, err := primary.WithHedgePolicy(&HedgePolicy{
+		Addresses: []string{"http://[::1"},
+	}).Logical().Read("secret/foo"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
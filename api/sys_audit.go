@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -55,6 +56,46 @@ func (c *Sys) AuditHashWithContext(ctx context.Context, path string, input strin
 	return hashStr, nil
 }
 
+func (c *Sys) RotateAuditHashSalt(path string, overlap time.Duration) (*AuditHashSaltRotation, error) {
+	return c.RotateAuditHashSaltWithContext(context.Background(), path, overlap)
+}
+
+func (c *Sys) RotateAuditHashSaltWithContext(ctx context.Context, path string, overlap time.Duration) (*AuditHashSaltRotation, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	body := map[string]interface{}{
+		"path":    path,
+		"overlap": overlap.String(),
+	}
+
+	r := c.c.NewRequest(http.MethodPut, "/v1/sys/audit-hash/rotate")
+	if err := r.SetJSONBody(body); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result AuditHashSaltRotation
+	if err := mapstructure.WeakDecode(secret.Data, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 func (c *Sys) ListAudit() (map[string]*Audit, error) {
 	return c.ListAuditWithContext(context.Background())
 }
@@ -157,3 +198,8 @@ type Audit struct {
 	Local       bool              `json:"local" mapstructure:"local"`
 	Path        string            `json:"path" mapstructure:"path"`
 }
+
+type AuditHashSaltRotation struct {
+	RotatedAt  string `json:"rotated_at" mapstructure:"rotated_at"`
+	OverlapEnd string `json:"overlap_end" mapstructure:"overlap_end"`
+}
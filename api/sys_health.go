@@ -5,7 +5,10 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
+
+	"github.com/mitchellh/mapstructure"
 )
 
 func (c *Sys) Health() (*HealthResponse, error) {
@@ -37,6 +40,63 @@ func (c *Sys) HealthWithContext(ctx context.Context) (*HealthResponse, error) {
 	return &result, err
 }
 
+func (c *Sys) HealthDetailed() (*HealthDetailedResponse, error) {
+	return c.HealthDetailedWithContext(context.Background())
+}
+
+// HealthDetailedWithContext returns the status of the subsystems of the node.
+func (c *Sys) HealthDetailedWithContext(ctx context.Context) (*HealthDetailedResponse, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	r := c.c.NewRequest(http.MethodGet, "/v1/sys/health/detailed")
+	// Failed nodes are reported with a 5xx code by default, which would turn
+	// into an error
+	r.Params.Add("failedcode", "299")
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result HealthDetailedResponse
+	err = resp.DecodeJSON(&result)
+	return &result, err
+}
+
+// HealthMounts wraps HealthMountsWithContext using context.Background.
+func (c *Sys) HealthMounts() (*HealthMountsResponse, error) {
+	return c.HealthMountsWithContext(context.Background())
+}
+
+// HealthMountsWithContext runs the health checks of the mounted backends
+// implementing one. It requires a sudo-capable token.
+func (c *Sys) HealthMountsWithContext(ctx context.Context) (*HealthMountsResponse, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	r := c.c.NewRequest(http.MethodGet, "/v1/sys/health/mounts")
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result HealthMountsResponse
+	err = mapstructure.Decode(secret.Data, &result)
+	return &result, err
+}
+
 type HealthResponse struct {
 	Initialized                bool   `json:"initialized"`
 	Sealed                     bool   `json:"sealed"`
@@ -50,3 +110,27 @@ type HealthResponse struct {
 	ClusterID                  string `json:"cluster_id,omitempty"`
 	LastWAL                    uint64 `json:"last_wal,omitempty"`
 }
+
+type HealthDetailedResponse struct {
+	Status     string                      `json:"status"`
+	Subsystems map[string]*SubsystemHealth `json:"subsystems"`
+}
+
+type SubsystemHealth struct {
+	Status  string                 `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+type HealthMountsResponse struct {
+	Status string         `mapstructure:"status"`
+	Mounts []*MountHealth `mapstructure:"mounts"`
+}
+
+type MountHealth struct {
+	Path     string `mapstructure:"path"`
+	Accessor string `mapstructure:"accessor"`
+	Type     string `mapstructure:"type"`
+	Status   string `mapstructure:"status"`
+	Error    string `mapstructure:"error"`
+}
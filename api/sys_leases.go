@@ -7,6 +7,8 @@ import (
 	"context"
 	"errors"
 	"net/http"
+
+	"github.com/mitchellh/mapstructure"
 )
 
 func (c *Sys) Renew(id string, increment int) (*Secret, error) {
@@ -36,6 +38,58 @@ func (c *Sys) RenewWithContext(ctx context.Context, id string, increment int) (*
 	return ParseSecret(resp.Body)
 }
 
+// RenewBatch renews several leases with a single request. The returned slice
+// holds the result of the renewal of each lease in the order of ids; a failed
+// renewal is reported in the Error field of its result rather than as an error.
+func (c *Sys) RenewBatch(ids []string, increment int) ([]*LeaseRenewal, error) {
+	return c.RenewBatchWithContext(context.Background(), ids, increment)
+}
+
+func (c *Sys) RenewBatchWithContext(ctx context.Context, ids []string, increment int) ([]*LeaseRenewal, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	r := c.c.NewRequest(http.MethodPut, "/v1/sys/leases/renew-batch")
+
+	body := map[string]interface{}{
+		"increment": increment,
+		"lease_ids": ids,
+	}
+	if err := r.SetJSONBody(body); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result []*LeaseRenewal
+	if err := mapstructure.WeakDecode(secret.Data["leases"], &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// LeaseRenewal is the result of the renewal of a lease by RenewBatch.
+type LeaseRenewal struct {
+	LeaseID       string   `mapstructure:"lease_id"`
+	LeaseDuration int      `mapstructure:"lease_duration"`
+	Renewable     bool     `mapstructure:"renewable"`
+	Warnings      []string `mapstructure:"warnings"`
+	Error         string   `mapstructure:"error"`
+}
+
 func (c *Sys) Lookup(id string) (*Secret, error) {
 	return c.LookupWithContext(context.Background(), id)
 }
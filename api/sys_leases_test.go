@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRenewBatch(t *testing.T) {
+	mockVaultServer := httptest.NewServer(http.HandlerFunc(mockVaultRenewBatchHandler))
+	defer mockVaultServer.Close()
+
+	cfg := DefaultConfig()
+	cfg.Address = mockVaultServer.URL
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Sys().RenewBatch([]string{"database/creds/app/abcd", "database/creds/app/efgh"}, 3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(resp))
+	}
+	if resp[0].LeaseID != "database/creds/app/abcd" || resp[0].LeaseDuration != 3600 || !resp[0].Renewable || resp[0].Error != "" {
+		t.Errorf("unexpected result: %+v", resp[0])
+	}
+	if resp[1].LeaseID != "database/creds/app/efgh" || resp[1].Error != "lease not found" {
+		t.Errorf("unexpected result: %+v", resp[1])
+	}
+}
+
+func mockVaultRenewBatchHandler(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte(renewBatchResponse))
+}
+
+const renewBatchResponse = `{
+  "request_id": "6c1e1a4b-3cde-8d0e-a4d4-c1f3d6c2b1a7",
+  "lease_id": "",
+  "lease_duration": 0,
+  "renewable": false,
+  "data": {
+    "leases": [
+      {
+        "lease_id": "database/creds/app/abcd",
+        "lease_duration": 3600,
+        "renewable": true
+      },
+      {
+        "lease_id": "database/creds/app/efgh",
+        "error": "lease not found"
+      }
+    ]
+  },
+  "wrap_info": null,
+  "warnings": null,
+  "auth": null
+}`
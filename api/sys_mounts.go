@@ -271,6 +271,8 @@ type MountConfigInput struct {
 	AllowedManagedKeys        []string                `json:"allowed_managed_keys,omitempty" mapstructure:"allowed_managed_keys"`
 	PluginVersion             string                  `json:"plugin_version,omitempty"`
 	UserLockoutConfig         *UserLockoutConfigInput `json:"user_lockout_config,omitempty"`
+	DeletionProtection        *bool                   `json:"deletion_protection,omitempty" mapstructure:"deletion_protection"`
+	SoftDeleteRetention       string                  `json:"soft_delete_retention,omitempty" mapstructure:"soft_delete_retention"`
 	// Deprecated: This field will always be blank for newer server responses.
 	PluginName string `json:"plugin_name,omitempty" mapstructure:"plugin_name"`
 }
@@ -303,6 +305,8 @@ type MountConfigOutput struct {
 	TokenType                 string                   `json:"token_type,omitempty" mapstructure:"token_type"`
 	AllowedManagedKeys        []string                 `json:"allowed_managed_keys,omitempty" mapstructure:"allowed_managed_keys"`
 	UserLockoutConfig         *UserLockoutConfigOutput `json:"user_lockout_config,omitempty"`
+	DeletionProtection        bool                     `json:"deletion_protection,omitempty" mapstructure:"deletion_protection"`
+	SoftDeleteRetention       int                      `json:"soft_delete_retention,omitempty" mapstructure:"soft_delete_retention"`
 	// Deprecated: This field will always be blank for newer server responses.
 	PluginName string `json:"plugin_name,omitempty" mapstructure:"plugin_name"`
 }
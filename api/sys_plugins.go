@@ -148,6 +148,9 @@ type GetPluginResponse struct {
 	SHA256            string   `json:"sha256"`
 	DeprecationStatus string   `json:"deprecation_status,omitempty"`
 	Version           string   `json:"version,omitempty"`
+	Runtime           string   `json:"runtime,omitempty"`
+	MemoryLimit       int64    `json:"memory_limit,omitempty"`
+	CPULimit          float64  `json:"cpu_limit,omitempty"`
 }
 
 // GetPlugin wraps GetPluginWithContext using context.Background.
@@ -201,6 +204,17 @@ type RegisterPluginInput struct {
 
 	// Version is the optional version of the plugin being registered
 	Version string `json:"version,omitempty"`
+
+	// Runtime is the optional runtime of the plugin, "wasm" for WebAssembly
+	// modules. Plugin executables don't set it.
+	Runtime string `json:"runtime,omitempty"`
+
+	// MemoryLimit is the optional maximum memory of the plugin processes, in
+	// bytes or with a unit suffix such as "512MiB".
+	MemoryLimit string `json:"memory_limit,omitempty"`
+
+	// CPULimit is the optional maximum number of CPUs of the plugin processes.
+	CPULimit float64 `json:"cpu_limit,omitempty"`
 }
 
 // RegisterPlugin wraps RegisterPluginWithContext using context.Background.
@@ -369,6 +383,77 @@ func (c *Sys) ReloadPluginStatusWithContext(ctx context.Context, reloadStatusInp
 	return nil, nil
 }
 
+// PluginRuntimeStatus is the status of a plugin run by Vault, as returned by
+// the PluginRuntimeStatus call.
+type PluginRuntimeStatus struct {
+	Name         string                `mapstructure:"name"`
+	Type         string                `mapstructure:"type"`
+	Version      string                `mapstructure:"version"`
+	Multiplexed  bool                  `mapstructure:"multiplexed"`
+	Connections  int                   `mapstructure:"connections"`
+	Starts       int                   `mapstructure:"starts"`
+	Restarts     int                   `mapstructure:"restarts"`
+	Crashes      int                   `mapstructure:"crashes"`
+	LastCrash    time.Time             `mapstructure:"last_crash"`
+	BackoffUntil time.Time             `mapstructure:"backoff_until"`
+	MemoryLimit  int64                 `mapstructure:"memory_limit"`
+	CPULimit     float64               `mapstructure:"cpu_limit"`
+	Processes    []PluginProcessStatus `mapstructure:"processes"`
+}
+
+// PluginProcessStatus is the status of a plugin process.
+type PluginProcessStatus struct {
+	PID                 int     `mapstructure:"pid"`
+	Running             bool    `mapstructure:"running"`
+	HealthCheckFailures int     `mapstructure:"health_check_failures"`
+	MemoryUsage         uint64  `mapstructure:"memory_usage"`
+	CPUSeconds          float64 `mapstructure:"cpu_seconds"`
+}
+
+// PluginRuntimeStatus wraps PluginRuntimeStatusWithContext using context.Background.
+func (c *Sys) PluginRuntimeStatus() ([]PluginRuntimeStatus, error) {
+	return c.PluginRuntimeStatusWithContext(context.Background())
+}
+
+// PluginRuntimeStatusWithContext retrieves the restarts and resource usage of
+// the plugins run by the node.
+func (c *Sys) PluginRuntimeStatusWithContext(ctx context.Context) ([]PluginRuntimeStatus, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	req := c.c.NewRequest(http.MethodGet, "/v1/sys/plugins/runtime/status")
+
+	resp, err := c.c.rawRequestWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result struct {
+		Plugins []PluginRuntimeStatus `mapstructure:"plugins"`
+	}
+	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeHookFunc(time.RFC3339),
+		WeaklyTypedInput: true,
+		Result:           &result,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Decode(secret.Data); err != nil {
+		return nil, err
+	}
+	return result.Plugins, nil
+}
+
 // catalogPathByType is a helper to construct the proper API path by plugin type
 func catalogPathByType(pluginType PluginType, name string) string {
 	path := fmt.Sprintf("/v1/sys/plugins/catalog/%s/%s", pluginType, name)
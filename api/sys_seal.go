@@ -6,6 +6,7 @@ package api
 import (
 	"context"
 	"net/http"
+	"time"
 )
 
 func (c *Sys) SealStatus() (*SealStatusResponse, error) {
@@ -113,6 +114,19 @@ type SealStatusResponse struct {
 	HCPLinkStatus     string   `json:"hcp_link_status,omitempty"`
 	HCPLinkResourceID string   `json:"hcp_link_resource_ID,omitempty"`
 	Warnings          []string `json:"warnings,omitempty"`
+
+	Seals []SealBackendStatus `json:"seals,omitempty"`
+}
+
+// SealBackendStatus is the health of one of the seals when several seals
+// are configured.
+type SealBackendStatus struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Priority  int       `json:"priority"`
+	Healthy   bool      `json:"healthy"`
+	LastSeen  time.Time `json:"last_seen"`
+	LastError string    `json:"last_error,omitempty"`
 }
 
 type UnsealOpts struct {
@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// FlushStorageCache flushes the entries of the storage cache of the node with
+// the given key prefixes, and returns the number of flushed entries.
+func (c *Sys) FlushStorageCache(prefixes []string) (int, error) {
+	return c.FlushStorageCacheWithContext(context.Background(), prefixes)
+}
+
+func (c *Sys) FlushStorageCacheWithContext(ctx context.Context, prefixes []string) (int, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	r := c.c.NewRequest(http.MethodPost, "/v1/sys/storage/cache/flush")
+
+	body := map[string]interface{}{
+		"prefixes": prefixes,
+	}
+	if err := r.SetJSONBody(body); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if secret == nil || secret.Data == nil {
+		return 0, errors.New("data from server response is empty")
+	}
+
+	var result struct {
+		FlushedEntries int `mapstructure:"flushed_entries"`
+	}
+	if err := mapstructure.WeakDecode(secret.Data, &result); err != nil {
+		return 0, err
+	}
+
+	return result.FlushedEntries, nil
+}
@@ -56,6 +56,12 @@ type BackendConfig struct {
 
 	// Config is the opaque user configuration provided when mounting
 	Config map[string]string
+
+	// MountPath is the path the audit device is mounted at
+	MountPath string
+
+	// ClusterName is the name of the cluster the audit device logs for
+	ClusterName string
 }
 
 // Factory is the factory function to create an audit backend.
@@ -133,6 +133,7 @@ func (f *AuditFormatter) FormatRequest(ctx context.Context, w io.Writer, config
 			PolicyOverride:                req.PolicyOverride,
 			RemoteAddr:                    getRemoteAddr(req),
 			RemotePort:                    getRemotePort(req),
+			Proxy:                         getProxy(req),
 			ReplicationCluster:            req.ReplicationCluster,
 			Headers:                       req.Headers,
 			ClientCertificateSerialNumber: getClientCertificateSerialNumber(connState),
@@ -339,6 +340,7 @@ func (f *AuditFormatter) FormatResponse(ctx context.Context, w io.Writer, config
 			PolicyOverride:                req.PolicyOverride,
 			RemoteAddr:                    getRemoteAddr(req),
 			RemotePort:                    getRemotePort(req),
+			Proxy:                         getProxy(req),
 			ClientCertificateSerialNumber: getClientCertificateSerialNumber(connState),
 			ReplicationCluster:            req.ReplicationCluster,
 			Headers:                       req.Headers,
@@ -434,6 +436,7 @@ type AuditRequest struct {
 	PolicyOverride                bool                   `json:"policy_override,omitempty"`
 	RemoteAddr                    string                 `json:"remote_address,omitempty"`
 	RemotePort                    int                    `json:"remote_port,omitempty"`
+	Proxy                         *AuditProxy            `json:"proxy,omitempty"`
 	WrapTTL                       int                    `json:"wrap_ttl,omitempty"`
 	Headers                       map[string][]string    `json:"headers,omitempty"`
 	ClientCertificateSerialNumber string                 `json:"client_certificate_serial_number,omitempty"`
@@ -506,6 +509,14 @@ type AuditNamespace struct {
 	Path string `json:"path,omitempty"`
 }
 
+// AuditProxy is the upstream proxy that forwarded the request using the PROXY
+// protocol.
+type AuditProxy struct {
+	Address  string            `json:"address,omitempty"`
+	Port     int               `json:"port,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
 // getRemoteAddr safely gets the remote address avoiding a nil pointer
 func getRemoteAddr(req *logical.Request) string {
 	if req != nil && req.Connection != nil {
@@ -522,6 +533,18 @@ func getRemotePort(req *logical.Request) int {
 	return 0
 }
 
+// getProxy safely gets the upstream proxy of the connection, if any
+func getProxy(req *logical.Request) *AuditProxy {
+	if req == nil || req.Connection == nil || req.Connection.ProxyAddr == "" {
+		return nil
+	}
+	return &AuditProxy{
+		Address:  req.Connection.ProxyAddr,
+		Port:     req.Connection.ProxyPort,
+		Metadata: req.Connection.ProxyMetadata,
+	}
+}
+
 func getClientCertificateSerialNumber(connState *tls.ConnectionState) string {
 	if connState == nil || len(connState.VerifiedChains) == 0 || len(connState.VerifiedChains[0]) == 0 {
 		return ""
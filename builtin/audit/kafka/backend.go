@@ -0,0 +1,607 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/vault/audit"
+	"github.com/hashicorp/vault/sdk/helper/salt"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+const (
+	// unavailableBlock makes requests wait for the brokers to accept their
+	// audit entries, failing them after the write timeout.
+	unavailableBlock = "block"
+
+	// unavailableBuffer makes the audit entries that could not be written to
+	// the brokers be buffered to disk and written once they are reachable
+	// again.
+	unavailableBuffer = "buffer"
+)
+
+// invalidTopicChars matches the characters that are not allowed in Kafka
+// topic names.
+var invalidTopicChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+func Factory(ctx context.Context, conf *audit.BackendConfig) (audit.Backend, error) {
+	if conf.SaltConfig == nil {
+		return nil, fmt.Errorf("nil salt config")
+	}
+	if conf.SaltView == nil {
+		return nil, fmt.Errorf("nil salt view")
+	}
+
+	var brokers []string
+	for _, broker := range strings.Split(conf.Config["brokers"], ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("brokers is required")
+	}
+
+	topic, ok := conf.Config["topic"]
+	if !ok {
+		topic = "vault-audit"
+	}
+	if topicPerMountRaw, ok := conf.Config["topic_per_mount"]; ok {
+		value, err := strconv.ParseBool(topicPerMountRaw)
+		if err != nil {
+			return nil, err
+		}
+		if value {
+			mount := strings.Trim(conf.MountPath, "/")
+			if mount == "" {
+				return nil, fmt.Errorf("topic_per_mount requires the mount path of the audit device")
+			}
+			topic = topic + "." + strings.ReplaceAll(mount, "/", ".")
+		}
+	}
+	topic = invalidTopicChars.ReplaceAllString(topic, "_")
+	if topic == "" || len(topic) > 249 {
+		return nil, fmt.Errorf("invalid topic %q", topic)
+	}
+
+	batchSize := 100
+	if batchSizeRaw, ok := conf.Config["batch_size"]; ok {
+		value, err := strconv.Atoi(batchSizeRaw)
+		if err != nil {
+			return nil, err
+		}
+		if value < 1 {
+			return nil, fmt.Errorf("batch_size must be greater than 0")
+		}
+		batchSize = value
+	}
+
+	batchTimeoutRaw, ok := conf.Config["batch_timeout"]
+	if !ok {
+		batchTimeoutRaw = "10ms"
+	}
+	batchTimeout, err := parseutil.ParseDurationSecond(batchTimeoutRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	writeTimeoutRaw, ok := conf.Config["write_timeout"]
+	if !ok {
+		writeTimeoutRaw = "5s"
+	}
+	writeTimeout, err := parseutil.ParseDurationSecond(writeTimeoutRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	unavailableBehavior, ok := conf.Config["unavailable_behavior"]
+	if !ok {
+		unavailableBehavior = unavailableBlock
+	}
+	switch unavailableBehavior {
+	case unavailableBlock, unavailableBuffer:
+	default:
+		return nil, fmt.Errorf("unknown unavailable_behavior %q", unavailableBehavior)
+	}
+
+	format, ok := conf.Config["format"]
+	if !ok {
+		format = "json"
+	}
+	switch format {
+	case "json", "jsonx":
+	default:
+		return nil, fmt.Errorf("unknown format type %q", format)
+	}
+
+	// Check if hashing of accessor is disabled
+	hmacAccessor := true
+	if hmacAccessorRaw, ok := conf.Config["hmac_accessor"]; ok {
+		value, err := strconv.ParseBool(hmacAccessorRaw)
+		if err != nil {
+			return nil, err
+		}
+		hmacAccessor = value
+	}
+
+	// Check if raw logging is enabled
+	logRaw := false
+	if raw, ok := conf.Config["log_raw"]; ok {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, err
+		}
+		logRaw = b
+	}
+
+	elideListResponses := false
+	if elideListResponsesRaw, ok := conf.Config["elide_list_responses"]; ok {
+		value, err := strconv.ParseBool(elideListResponsesRaw)
+		if err != nil {
+			return nil, err
+		}
+		elideListResponses = value
+	}
+
+	b := &Backend{
+		saltConfig: conf.SaltConfig,
+		saltView:   conf.SaltView,
+		formatConfig: audit.FormatterConfig{
+			Raw:                logRaw,
+			HMACAccessor:       hmacAccessor,
+			ElideListResponses: elideListResponses,
+		},
+
+		config:       conf.Config,
+		brokers:      brokers,
+		topic:        topic,
+		batchSize:    batchSize,
+		batchTimeout: batchTimeout,
+		writeTimeout: writeTimeout,
+		stopCh:       make(chan struct{}),
+	}
+
+	switch format {
+	case "json":
+		b.formatter.AuditFormatWriter = &audit.JSONFormatWriter{
+			Prefix:   conf.Config["prefix"],
+			SaltFunc: b.Salt,
+		}
+	case "jsonx":
+		b.formatter.AuditFormatWriter = &audit.JSONxFormatWriter{
+			Prefix:   conf.Config["prefix"],
+			SaltFunc: b.Salt,
+		}
+	}
+
+	b.writer, err = b.newWriter()
+	if err != nil {
+		return nil, err
+	}
+
+	if unavailableBehavior == unavailableBuffer {
+		bufferPath := conf.Config["buffer_path"]
+		if bufferPath == "" {
+			return nil, fmt.Errorf("buffer_path is required when unavailable_behavior is %q", unavailableBuffer)
+		}
+
+		bufferMaxSize := uint64(1 << 30)
+		if bufferMaxSizeRaw, ok := conf.Config["buffer_max_size"]; ok {
+			bufferMaxSize, err = parseutil.ParseCapacityString(bufferMaxSizeRaw)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		bufferFlushIntervalRaw, ok := conf.Config["buffer_flush_interval"]
+		if !ok {
+			bufferFlushIntervalRaw = "5s"
+		}
+		b.bufferFlushInterval, err = parseutil.ParseDurationSecond(bufferFlushIntervalRaw)
+		if err != nil {
+			return nil, err
+		}
+		if b.bufferFlushInterval <= 0 {
+			return nil, fmt.Errorf("buffer_flush_interval must be greater than 0")
+		}
+
+		b.buffer, err = openDiskBuffer(bufferPath, int64(bufferMaxSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit buffer: %w", err)
+		}
+
+		// Write the entries left over by a previous run
+		if b.buffer.len() > 0 {
+			b.startFlushing()
+		}
+	}
+
+	return b, nil
+}
+
+// Backend is the audit backend for the Kafka audit transport.
+type Backend struct {
+	formatter    audit.AuditFormatter
+	formatConfig audit.FormatterConfig
+
+	config       map[string]string
+	brokers      []string
+	topic        string
+	batchSize    int
+	batchTimeout time.Duration
+	writeTimeout time.Duration
+
+	writerLock sync.RWMutex
+	writer     *kafka.Writer
+
+	// buffer is only set when the unavailable behavior is to buffer the audit
+	// entries to disk.
+	bufferLock          sync.Mutex
+	buffer              *diskBuffer
+	bufferFlushInterval time.Duration
+	flushing            bool
+
+	stopCh    chan struct{}
+	closeOnce sync.Once
+
+	saltMutex  sync.RWMutex
+	salt       *salt.Salt
+	saltConfig *salt.Config
+	saltView   logical.Storage
+}
+
+var _ audit.Backend = (*Backend)(nil)
+
+// newWriter creates a writer for the configured brokers, loading the TLS
+// certificates from disk.
+func (b *Backend) newWriter() (*kafka.Writer, error) {
+	transport := &kafka.Transport{
+		DialTimeout: b.writeTimeout,
+	}
+
+	tlsConfig, err := b.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLS = tlsConfig
+
+	transport.SASL, err = b.saslMechanism()
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafka.Writer{
+		Addr:         kafka.TCP(b.brokers...),
+		Topic:        b.topic,
+		Balancer:     &kafka.LeastBytes{},
+		BatchSize:    b.batchSize,
+		BatchTimeout: b.batchTimeout,
+		RequiredAcks: kafka.RequireAll,
+		Transport:    transport,
+	}, nil
+}
+
+func (b *Backend) tlsConfig() (*tls.Config, error) {
+	tlsEnabled := false
+	if tlsEnabledRaw, ok := b.config["tls_enabled"]; ok {
+		value, err := strconv.ParseBool(tlsEnabledRaw)
+		if err != nil {
+			return nil, err
+		}
+		tlsEnabled = value
+	}
+	caFile := b.config["tls_ca_file"]
+	certFile := b.config["tls_cert_file"]
+	keyFile := b.config["tls_key_file"]
+	if !tlsEnabled && caFile == "" && certFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: b.config["tls_server_name"],
+	}
+
+	if skipVerifyRaw, ok := b.config["tls_skip_verify"]; ok {
+		value, err := strconv.ParseBool(skipVerifyRaw)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.InsecureSkipVerify = value
+	}
+
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls_ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("failed to parse tls_ca_file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("tls_cert_file and tls_key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func (b *Backend) saslMechanism() (sasl.Mechanism, error) {
+	mechanism := b.config["sasl_mechanism"]
+	if mechanism == "" {
+		return nil, nil
+	}
+
+	username := b.config["sasl_username"]
+	password := b.config["sasl_password"]
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("sasl_username and sasl_password are required when sasl_mechanism is set")
+	}
+
+	switch strings.ToLower(mechanism) {
+	case "plain":
+		return plain.Mechanism{
+			Username: username,
+			Password: password,
+		}, nil
+	case "scram-sha-256":
+		return scram.Mechanism(scram.SHA256, username, password)
+	case "scram-sha-512":
+		return scram.Mechanism(scram.SHA512, username, password)
+	default:
+		return nil, fmt.Errorf("unknown sasl_mechanism %q", mechanism)
+	}
+}
+
+func (b *Backend) GetHash(ctx context.Context, data string) (string, error) {
+	salt, err := b.Salt(ctx)
+	if err != nil {
+		return "", err
+	}
+	return audit.HashString(salt, data), nil
+}
+
+func (b *Backend) LogRequest(ctx context.Context, in *logical.LogInput) error {
+	var buf bytes.Buffer
+	if err := b.formatter.FormatRequest(ctx, &buf, b.formatConfig, in); err != nil {
+		return err
+	}
+
+	return b.log(ctx, buf.Bytes())
+}
+
+func (b *Backend) LogResponse(ctx context.Context, in *logical.LogInput) error {
+	var buf bytes.Buffer
+	if err := b.formatter.FormatResponse(ctx, &buf, b.formatConfig, in); err != nil {
+		return err
+	}
+
+	return b.log(ctx, buf.Bytes())
+}
+
+func (b *Backend) LogTestMessage(ctx context.Context, in *logical.LogInput, config map[string]string) error {
+	var buf bytes.Buffer
+	temporaryFormatter := audit.NewTemporaryFormatter(config["format"], config["prefix"])
+	if err := temporaryFormatter.FormatRequest(ctx, &buf, b.formatConfig, in); err != nil {
+		return err
+	}
+
+	// The test message is never buffered, so that enabling the device fails
+	// if the brokers can't be written to.
+	return b.write(ctx, buf.Bytes())
+}
+
+// log writes the audit entry to the brokers. If the entry could not be
+// written and the device buffers entries, it is appended to the buffer
+// instead.
+func (b *Backend) log(ctx context.Context, entry []byte) error {
+	if b.buffer == nil {
+		return b.write(ctx, entry)
+	}
+
+	// Entries queue up behind the buffered ones until the buffer is flushed,
+	// to keep them in order.
+	b.bufferLock.Lock()
+	if b.buffer.len() > 0 {
+		defer b.bufferLock.Unlock()
+		return b.bufferEntry(entry)
+	}
+	b.bufferLock.Unlock()
+
+	err := b.write(ctx, entry)
+	if err == nil {
+		return nil
+	}
+
+	b.bufferLock.Lock()
+	defer b.bufferLock.Unlock()
+	if bErr := b.bufferEntry(entry); bErr != nil {
+		return multierror.Append(err, bErr)
+	}
+	return nil
+}
+
+// bufferEntry appends the entry to the buffer and makes sure it is being
+// flushed. The buffer lock must be held.
+func (b *Backend) bufferEntry(entry []byte) error {
+	if err := b.buffer.append(entry); err != nil {
+		return fmt.Errorf("failed to buffer audit entry: %w", err)
+	}
+	b.startFlushing()
+	return nil
+}
+
+// write writes the entries to the brokers, waiting at most for the write
+// timeout.
+func (b *Backend) write(ctx context.Context, entries ...[]byte) error {
+	if b.writeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.writeTimeout)
+		defer cancel()
+	}
+
+	msgs := make([]kafka.Message, len(entries))
+	for i, entry := range entries {
+		msgs[i] = kafka.Message{Value: entry}
+	}
+
+	b.writerLock.RLock()
+	defer b.writerLock.RUnlock()
+
+	return b.writer.WriteMessages(ctx, msgs...)
+}
+
+// startFlushing starts flushing the buffer in the background if it isn't
+// already. The buffer lock must be held.
+func (b *Backend) startFlushing() {
+	if b.flushing {
+		return
+	}
+	b.flushing = true
+
+	go func() {
+		ticker := time.NewTicker(b.bufferFlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-b.stopCh:
+				return
+			case <-ticker.C:
+			}
+
+			if b.flushBuffer() {
+				return
+			}
+		}
+	}()
+}
+
+// flushBuffer writes the buffered entries to the brokers in batches. It
+// returns true once the buffer is empty.
+func (b *Backend) flushBuffer() bool {
+	b.bufferLock.Lock()
+	defer b.bufferLock.Unlock()
+
+	records, err := b.buffer.records()
+	if err != nil {
+		return false
+	}
+
+	for sent := 0; sent < len(records); sent += b.batchSize {
+		end := sent + b.batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+
+		if err := b.write(context.Background(), records[sent:end]...); err != nil {
+			// Keep the entries that haven't been written for the next attempt
+			if sent > 0 {
+				b.buffer.replace(records[sent:])
+			}
+			return false
+		}
+	}
+
+	if err := b.buffer.replace(nil); err != nil {
+		return false
+	}
+
+	b.flushing = false
+	return true
+}
+
+// Reload recreates the connections to the brokers, reloading the TLS
+// certificates from disk.
+func (b *Backend) Reload(_ context.Context) error {
+	writer, err := b.newWriter()
+	if err != nil {
+		return err
+	}
+
+	b.writerLock.Lock()
+	old := b.writer
+	b.writer = writer
+	b.writerLock.Unlock()
+
+	return old.Close()
+}
+
+// Close stops flushing the buffer and closes the connections to the brokers.
+// The buffered entries are kept on disk and flushed once the device is
+// enabled again.
+func (b *Backend) Close() error {
+	var retErr error
+	b.closeOnce.Do(func() {
+		close(b.stopCh)
+
+		b.writerLock.Lock()
+		if err := b.writer.Close(); err != nil {
+			retErr = multierror.Append(retErr, err)
+		}
+		b.writerLock.Unlock()
+
+		if b.buffer != nil {
+			b.bufferLock.Lock()
+			if err := b.buffer.close(); err != nil {
+				retErr = multierror.Append(retErr, err)
+			}
+			b.bufferLock.Unlock()
+		}
+	})
+	return retErr
+}
+
+func (b *Backend) Salt(ctx context.Context) (*salt.Salt, error) {
+	b.saltMutex.RLock()
+	if b.salt != nil {
+		defer b.saltMutex.RUnlock()
+		return b.salt, nil
+	}
+	b.saltMutex.RUnlock()
+	b.saltMutex.Lock()
+	defer b.saltMutex.Unlock()
+	if b.salt != nil {
+		return b.salt, nil
+	}
+	salt, err := salt.NewSalt(ctx, b.saltView, b.saltConfig)
+	if err != nil {
+		return nil, err
+	}
+	b.salt = salt
+	return salt, nil
+}
+
+func (b *Backend) Invalidate(_ context.Context) {
+	b.saltMutex.Lock()
+	defer b.saltMutex.Unlock()
+	b.salt = nil
+}
@@ -0,0 +1,227 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kafka
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/vault/audit"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/salt"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// unreachableBroker is an address nothing listens on.
+const unreachableBroker = "127.0.0.1:1"
+
+func testBackend(t *testing.T, mountPath string, config map[string]string) *Backend {
+	t.Helper()
+
+	be, err := Factory(context.Background(), &audit.BackendConfig{
+		SaltConfig: &salt.Config{},
+		SaltView:   &logical.InmemStorage{},
+		Config:     config,
+		MountPath:  mountPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := be.(*Backend)
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func testLogInput() *logical.LogInput {
+	return &logical.LogInput{
+		Request: &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "/foo",
+			Connection: &logical.Connection{
+				RemoteAddr: "127.0.0.1",
+			},
+		},
+	}
+}
+
+func TestKafka_Factory(t *testing.T) {
+	cases := map[string]map[string]string{
+		"missing brokers":         {},
+		"empty brokers":           {"brokers": " , "},
+		"invalid batch size":      {"brokers": unreachableBroker, "batch_size": "0"},
+		"invalid write timeout":   {"brokers": unreachableBroker, "write_timeout": "soon"},
+		"unknown behavior":        {"brokers": unreachableBroker, "unavailable_behavior": "drop"},
+		"missing buffer path":     {"brokers": unreachableBroker, "unavailable_behavior": "buffer"},
+		"unknown sasl mechanism":  {"brokers": unreachableBroker, "sasl_mechanism": "gssapi", "sasl_username": "u", "sasl_password": "p"},
+		"missing sasl password":   {"brokers": unreachableBroker, "sasl_mechanism": "plain", "sasl_username": "u"},
+		"missing tls key file":    {"brokers": unreachableBroker, "tls_cert_file": "cert.pem"},
+		"missing tls ca file":     {"brokers": unreachableBroker, "tls_ca_file": "/nonexistent/ca.pem"},
+		"unknown format":          {"brokers": unreachableBroker, "format": "yaml"},
+		"invalid topic per mount": {"brokers": unreachableBroker, "topic_per_mount": "maybe"},
+	}
+
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Factory(context.Background(), &audit.BackendConfig{
+				SaltConfig: &salt.Config{},
+				SaltView:   &logical.InmemStorage{},
+				Config:     config,
+				MountPath:  "kafka/",
+			})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+
+	b := testBackend(t, "kafka/", map[string]string{
+		"brokers":        "broker1:9092, broker2:9092",
+		"sasl_mechanism": "scram-sha-512",
+		"sasl_username":  "vault",
+		"sasl_password":  "secret",
+		"tls_enabled":    "true",
+	})
+	if len(b.brokers) != 2 || b.brokers[1] != "broker2:9092" {
+		t.Fatalf("bad brokers: %v", b.brokers)
+	}
+	if b.topic != "vault-audit" {
+		t.Fatalf("bad topic: %q", b.topic)
+	}
+	if b.buffer != nil {
+		t.Fatal("expected no buffer when blocking")
+	}
+}
+
+func TestKafka_topicPerMount(t *testing.T) {
+	b := testBackend(t, "team a/kafka/", map[string]string{
+		"brokers":         unreachableBroker,
+		"topic":           "audit",
+		"topic_per_mount": "true",
+	})
+	if b.topic != "audit.team_a.kafka" {
+		t.Fatalf("bad topic: %q", b.topic)
+	}
+}
+
+func TestKafka_unavailable(t *testing.T) {
+	ctx := namespace.RootContext(nil)
+
+	t.Run("block", func(t *testing.T) {
+		b := testBackend(t, "kafka/", map[string]string{
+			"brokers":       unreachableBroker,
+			"write_timeout": "200ms",
+		})
+		if err := b.LogRequest(ctx, testLogInput()); err == nil {
+			t.Fatal("expected the request to fail to be logged")
+		}
+	})
+
+	t.Run("buffer", func(t *testing.T) {
+		bufferPath := filepath.Join(t.TempDir(), "audit.buffer")
+		config := map[string]string{
+			"brokers":               unreachableBroker,
+			"write_timeout":         "200ms",
+			"unavailable_behavior":  "buffer",
+			"buffer_path":           bufferPath,
+			"buffer_flush_interval": "1h",
+		}
+		b := testBackend(t, "kafka/", config)
+
+		// The test message is never buffered
+		if err := b.LogTestMessage(ctx, testLogInput(), config); err == nil {
+			t.Fatal("expected the test message to fail to be logged")
+		}
+
+		if err := b.LogRequest(ctx, testLogInput()); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.LogResponse(ctx, testLogInput()); err != nil {
+			t.Fatal(err)
+		}
+		if b.buffer.len() != 2 {
+			t.Fatalf("expected 2 buffered entries, got %d", b.buffer.len())
+		}
+
+		// Buffered entries survive restarts
+		if err := b.Close(); err != nil {
+			t.Fatal(err)
+		}
+		b = testBackend(t, "kafka/", config)
+		records, err := b.buffer.records()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("expected 2 buffered entries, got %d", len(records))
+		}
+	})
+
+	t.Run("buffer full", func(t *testing.T) {
+		b := testBackend(t, "kafka/", map[string]string{
+			"brokers":               unreachableBroker,
+			"write_timeout":         "200ms",
+			"unavailable_behavior":  "buffer",
+			"buffer_path":           filepath.Join(t.TempDir(), "audit.buffer"),
+			"buffer_max_size":       "1kb",
+			"buffer_flush_interval": "1h",
+		})
+
+		var err error
+		for i := 0; i < 10 && err == nil; i++ {
+			err = b.LogRequest(ctx, testLogInput())
+		}
+		if err == nil {
+			t.Fatal("expected the buffer to fill up")
+		}
+	})
+}
+
+func TestDiskBuffer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.buffer")
+	buf, err := openDiskBuffer(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, record := range []string{"one", "two", "three"} {
+		if err := buf.append([]byte(record)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Simulate a record that was only partially written
+	if _, err := buf.file.Write([]byte{0, 0, 0, 10, 'f'}); err != nil {
+		t.Fatal(err)
+	}
+	buf.close()
+
+	buf, err = openDiskBuffer(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.close()
+
+	records, err := buf.records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.len() != 3 || len(records) != 3 || string(records[2]) != "three" {
+		t.Fatalf("bad records: %q", records)
+	}
+
+	if err := buf.replace(records[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := buf.append([]byte("four")); err != nil {
+		t.Fatal(err)
+	}
+	records, err = buf.records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || string(records[0]) != "two" || string(records[2]) != "four" {
+		t.Fatalf("bad records: %q", records)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kafka
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// recordHeaderSize is the size of the big endian length prefixing each
+// buffered record.
+const recordHeaderSize = 4
+
+var errBufferFull = errors.New("audit buffer is full")
+
+// diskBuffer is an append-only file holding the audit entries that could not
+// be written to Kafka, in the order they were logged. Each record is prefixed
+// with its length.
+type diskBuffer struct {
+	file    *os.File
+	maxSize int64
+	size    int64
+	count   int
+}
+
+// openDiskBuffer opens the buffer file at path, creating it if it doesn't
+// exist. Records that were left in the file by a previous run are kept. A
+// record that was only partially written is discarded.
+func openDiskBuffer(path string, maxSize int64) (*diskBuffer, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &diskBuffer{
+		file:    f,
+		maxSize: maxSize,
+	}
+
+	records, size, err := buf.read()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return nil, err
+	}
+	buf.size = size
+	buf.count = len(records)
+
+	return buf, nil
+}
+
+// len returns the number of buffered records.
+func (d *diskBuffer) len() int {
+	return d.count
+}
+
+// append adds a record to the end of the buffer and syncs it to disk.
+func (d *diskBuffer) append(record []byte) error {
+	n := int64(recordHeaderSize + len(record))
+	if d.maxSize > 0 && d.size+n > d.maxSize {
+		return errBufferFull
+	}
+
+	data := make([]byte, n)
+	binary.BigEndian.PutUint32(data, uint32(len(record)))
+	copy(data[recordHeaderSize:], record)
+
+	if _, err := d.file.Write(data); err != nil {
+		// Discard what may have been written of the record
+		d.file.Truncate(d.size)
+		return err
+	}
+	if err := d.file.Sync(); err != nil {
+		return err
+	}
+
+	d.size += n
+	d.count++
+	return nil
+}
+
+// records returns the buffered records, oldest first.
+func (d *diskBuffer) records() ([][]byte, error) {
+	records, _, err := d.read()
+	return records, err
+}
+
+// replace replaces the content of the buffer with the given records.
+func (d *diskBuffer) replace(records [][]byte) error {
+	if err := d.file.Truncate(0); err != nil {
+		return err
+	}
+	d.size = 0
+	d.count = 0
+
+	for _, record := range records {
+		if err := d.append(record); err != nil {
+			return err
+		}
+	}
+	return d.file.Sync()
+}
+
+func (d *diskBuffer) close() error {
+	return d.file.Close()
+}
+
+// read parses the buffer file and returns its complete records along with
+// the offset following the last of them.
+func (d *diskBuffer) read() ([][]byte, int64, error) {
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+
+	var records [][]byte
+	var offset int64
+	r := bufio.NewReader(d.file)
+	header := make([]byte, recordHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return records, offset, nil
+			}
+			return nil, 0, fmt.Errorf("failed to read audit buffer: %w", err)
+		}
+
+		record := make([]byte, binary.BigEndian.Uint32(header))
+		if _, err := io.ReadFull(r, record); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return records, offset, nil
+			}
+			return nil, 0, fmt.Errorf("failed to read audit buffer: %w", err)
+		}
+
+		records = append(records, record)
+		offset += int64(recordHeaderSize + len(record))
+	}
+}
@@ -0,0 +1,446 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otel
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/vault/audit"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/salt"
+	"github.com/hashicorp/vault/sdk/logical"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// scopeName is the name of the instrumentation scope of the log records.
+const scopeName = "vault.audit"
+
+func Factory(ctx context.Context, conf *audit.BackendConfig) (audit.Backend, error) {
+	if conf.SaltConfig == nil {
+		return nil, fmt.Errorf("nil salt config")
+	}
+	if conf.SaltView == nil {
+		return nil, fmt.Errorf("nil salt view")
+	}
+
+	endpoint, ok := conf.Config["endpoint"]
+	if !ok || endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required")
+	}
+
+	writeTimeoutRaw, ok := conf.Config["write_timeout"]
+	if !ok {
+		writeTimeoutRaw = "5s"
+	}
+	writeTimeout, err := parseutil.ParseDurationSecond(writeTimeoutRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := parseKeyValues(conf.Config["headers"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse headers: %w", err)
+	}
+
+	resourceAttributes, err := parseKeyValues(conf.Config["resource_attributes"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse resource_attributes: %w", err)
+	}
+
+	serviceName, ok := conf.Config["service_name"]
+	if !ok {
+		serviceName = "vault"
+	}
+
+	nodeName, ok := conf.Config["node_name"]
+	if !ok {
+		nodeName, err = os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %w", err)
+		}
+	}
+
+	format, ok := conf.Config["format"]
+	if !ok {
+		format = "json"
+	}
+	switch format {
+	case "json", "jsonx":
+	default:
+		return nil, fmt.Errorf("unknown format type %q", format)
+	}
+
+	// Check if hashing of accessor is disabled
+	hmacAccessor := true
+	if hmacAccessorRaw, ok := conf.Config["hmac_accessor"]; ok {
+		value, err := strconv.ParseBool(hmacAccessorRaw)
+		if err != nil {
+			return nil, err
+		}
+		hmacAccessor = value
+	}
+
+	// Check if raw logging is enabled
+	logRaw := false
+	if raw, ok := conf.Config["log_raw"]; ok {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, err
+		}
+		logRaw = b
+	}
+
+	elideListResponses := false
+	if elideListResponsesRaw, ok := conf.Config["elide_list_responses"]; ok {
+		value, err := strconv.ParseBool(elideListResponsesRaw)
+		if err != nil {
+			return nil, err
+		}
+		elideListResponses = value
+	}
+
+	// The resource attributes configured on the device override the ones
+	// describing where the audit entries come from
+	attributes := map[string]string{
+		"service.name":       serviceName,
+		"host.name":          nodeName,
+		"vault.cluster.name": conf.ClusterName,
+		"vault.audit.mount":  conf.MountPath,
+	}
+	for k, v := range resourceAttributes {
+		attributes[k] = v
+	}
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	resource := &resourcepb.Resource{}
+	for _, k := range keys {
+		if attributes[k] != "" {
+			resource.Attributes = append(resource.Attributes, stringAttribute(k, attributes[k]))
+		}
+	}
+
+	b := &Backend{
+		saltConfig: conf.SaltConfig,
+		saltView:   conf.SaltView,
+		formatConfig: audit.FormatterConfig{
+			Raw:                logRaw,
+			HMACAccessor:       hmacAccessor,
+			ElideListResponses: elideListResponses,
+		},
+
+		config:       conf.Config,
+		endpoint:     endpoint,
+		writeTimeout: writeTimeout,
+		headers:      metadata.New(headers),
+		resource:     resource,
+	}
+
+	switch format {
+	case "json":
+		b.formatter.AuditFormatWriter = &audit.JSONFormatWriter{
+			Prefix:   conf.Config["prefix"],
+			SaltFunc: b.Salt,
+		}
+	case "jsonx":
+		b.formatter.AuditFormatWriter = &audit.JSONxFormatWriter{
+			Prefix:   conf.Config["prefix"],
+			SaltFunc: b.Salt,
+		}
+	}
+
+	if err := b.connect(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Backend is the audit backend for the OpenTelemetry audit transport. Each
+// audit entry is exported as an OTLP log record over gRPC.
+type Backend struct {
+	formatter    audit.AuditFormatter
+	formatConfig audit.FormatterConfig
+
+	config       map[string]string
+	endpoint     string
+	writeTimeout time.Duration
+	headers      metadata.MD
+	resource     *resourcepb.Resource
+
+	connLock sync.RWMutex
+	conn     *grpc.ClientConn
+	client   collogspb.LogsServiceClient
+
+	saltMutex  sync.RWMutex
+	salt       *salt.Salt
+	saltConfig *salt.Config
+	saltView   logical.Storage
+}
+
+var _ audit.Backend = (*Backend)(nil)
+
+// parseKeyValues parses a comma separated list of key=value pairs.
+func parseKeyValues(raw string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("invalid key=value pair %q", pair)
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return values, nil
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key: key,
+		Value: &commonpb.AnyValue{
+			Value: &commonpb.AnyValue_StringValue{StringValue: value},
+		},
+	}
+}
+
+// connect creates the connection to the collector, loading the TLS
+// certificates from disk. The connection is established lazily.
+func (b *Backend) connect() error {
+	creds, err := b.transportCredentials()
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.Dial(b.endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return fmt.Errorf("failed to connect to %q: %w", b.endpoint, err)
+	}
+
+	b.connLock.Lock()
+	old := b.conn
+	b.conn = conn
+	b.client = collogspb.NewLogsServiceClient(conn)
+	b.connLock.Unlock()
+
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+func (b *Backend) transportCredentials() (credentials.TransportCredentials, error) {
+	if insecureRaw, ok := b.config["insecure"]; ok {
+		value, err := strconv.ParseBool(insecureRaw)
+		if err != nil {
+			return nil, err
+		}
+		if value {
+			return insecure.NewCredentials(), nil
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: b.config["tls_server_name"],
+	}
+
+	if skipVerifyRaw, ok := b.config["tls_skip_verify"]; ok {
+		value, err := strconv.ParseBool(skipVerifyRaw)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.InsecureSkipVerify = value
+	}
+
+	if caFile := b.config["tls_ca_file"]; caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls_ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("failed to parse tls_ca_file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certFile := b.config["tls_cert_file"]
+	keyFile := b.config["tls_key_file"]
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("tls_cert_file and tls_key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func (b *Backend) GetHash(ctx context.Context, data string) (string, error) {
+	salt, err := b.Salt(ctx)
+	if err != nil {
+		return "", err
+	}
+	return audit.HashString(salt, data), nil
+}
+
+func (b *Backend) LogRequest(ctx context.Context, in *logical.LogInput) error {
+	var buf bytes.Buffer
+	if err := b.formatter.FormatRequest(ctx, &buf, b.formatConfig, in); err != nil {
+		return err
+	}
+
+	return b.export(ctx, "request", in, buf.Bytes())
+}
+
+func (b *Backend) LogResponse(ctx context.Context, in *logical.LogInput) error {
+	var buf bytes.Buffer
+	if err := b.formatter.FormatResponse(ctx, &buf, b.formatConfig, in); err != nil {
+		return err
+	}
+
+	return b.export(ctx, "response", in, buf.Bytes())
+}
+
+func (b *Backend) LogTestMessage(ctx context.Context, in *logical.LogInput, config map[string]string) error {
+	var buf bytes.Buffer
+	temporaryFormatter := audit.NewTemporaryFormatter(config["format"], config["prefix"])
+	if err := temporaryFormatter.FormatRequest(ctx, &buf, b.formatConfig, in); err != nil {
+		return err
+	}
+
+	return b.export(ctx, "request", in, buf.Bytes())
+}
+
+// export sends the audit entry to the collector as a log record, waiting at
+// most for the write timeout. The type of the entry and the request it
+// belongs to are recorded as attributes of the log record.
+func (b *Backend) export(ctx context.Context, entryType string, in *logical.LogInput, entry []byte) error {
+	now := uint64(time.Now().UnixNano())
+	record := &logspb.LogRecord{
+		TimeUnixNano:         now,
+		ObservedTimeUnixNano: now,
+		SeverityNumber:       logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+		SeverityText:         "INFO",
+		Body: &commonpb.AnyValue{
+			Value: &commonpb.AnyValue_StringValue{StringValue: string(bytes.TrimSuffix(entry, []byte("\n")))},
+		},
+		Attributes: []*commonpb.KeyValue{
+			stringAttribute("vault.audit.type", entryType),
+		},
+	}
+	if in.Request != nil {
+		record.Attributes = append(record.Attributes,
+			stringAttribute("vault.request.id", in.Request.ID),
+			stringAttribute("vault.request.path", in.Request.Path),
+			stringAttribute("vault.request.operation", string(in.Request.Operation)),
+		)
+	}
+	if ns, err := namespace.FromContext(ctx); err == nil {
+		record.Attributes = append(record.Attributes, stringAttribute("vault.namespace.path", ns.Path))
+	}
+
+	req := &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{
+			{
+				Resource: b.resource,
+				ScopeLogs: []*logspb.ScopeLogs{
+					{
+						Scope:      &commonpb.InstrumentationScope{Name: scopeName},
+						LogRecords: []*logspb.LogRecord{record},
+					},
+				},
+			},
+		},
+	}
+
+	if b.writeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.writeTimeout)
+		defer cancel()
+	}
+	if len(b.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, b.headers)
+	}
+
+	b.connLock.RLock()
+	defer b.connLock.RUnlock()
+
+	resp, err := b.client.Export(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to export audit entry: %w", err)
+	}
+	if rejected := resp.GetPartialSuccess().GetRejectedLogRecords(); rejected > 0 {
+		return fmt.Errorf("collector rejected the audit entry: %s", resp.GetPartialSuccess().GetErrorMessage())
+	}
+
+	return nil
+}
+
+// Reload reconnects to the collector, reloading the TLS certificates from
+// disk.
+func (b *Backend) Reload(_ context.Context) error {
+	return b.connect()
+}
+
+// Close closes the connection to the collector.
+func (b *Backend) Close() error {
+	b.connLock.Lock()
+	defer b.connLock.Unlock()
+
+	return b.conn.Close()
+}
+
+func (b *Backend) Salt(ctx context.Context) (*salt.Salt, error) {
+	b.saltMutex.RLock()
+	if b.salt != nil {
+		defer b.saltMutex.RUnlock()
+		return b.salt, nil
+	}
+	b.saltMutex.RUnlock()
+	b.saltMutex.Lock()
+	defer b.saltMutex.Unlock()
+	if b.salt != nil {
+		return b.salt, nil
+	}
+	salt, err := salt.NewSalt(ctx, b.saltView, b.saltConfig)
+	if err != nil {
+		return nil, err
+	}
+	b.salt = salt
+	return salt, nil
+}
+
+func (b *Backend) Invalidate(_ context.Context) {
+	b.saltMutex.Lock()
+	defer b.saltMutex.Unlock()
+	b.salt = nil
+}
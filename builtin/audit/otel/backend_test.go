@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/vault/audit"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/salt"
+	"github.com/hashicorp/vault/sdk/logical"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// testCollector records the log exports it receives.
+type testCollector struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	lock     sync.Mutex
+	requests []*collogspb.ExportLogsServiceRequest
+	metadata []metadata.MD
+}
+
+func (c *testCollector) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.requests = append(c.requests, req)
+	c.metadata = append(c.metadata, md)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func startTestCollector(t *testing.T) (*testCollector, string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collector := &testCollector{}
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, collector)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	return collector, ln.Addr().String()
+}
+
+func attributes(kvs []*commonpb.KeyValue) map[string]string {
+	m := make(map[string]string)
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value.GetStringValue()
+	}
+	return m
+}
+
+func TestOTel_Factory(t *testing.T) {
+	cases := map[string]map[string]string{
+		"missing endpoint":    {},
+		"invalid headers":     {"endpoint": "127.0.0.1:4317", "headers": "authorization"},
+		"invalid attributes":  {"endpoint": "127.0.0.1:4317", "resource_attributes": "=value"},
+		"invalid timeout":     {"endpoint": "127.0.0.1:4317", "write_timeout": "soon"},
+		"missing tls ca file": {"endpoint": "127.0.0.1:4317", "tls_ca_file": "/nonexistent/ca.pem"},
+		"missing tls key":     {"endpoint": "127.0.0.1:4317", "tls_cert_file": "cert.pem"},
+		"unknown format":      {"endpoint": "127.0.0.1:4317", "format": "yaml"},
+	}
+
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Factory(context.Background(), &audit.BackendConfig{
+				SaltConfig: &salt.Config{},
+				SaltView:   &logical.InmemStorage{},
+				Config:     config,
+			})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestOTel_export(t *testing.T) {
+	collector, endpoint := startTestCollector(t)
+
+	be, err := Factory(context.Background(), &audit.BackendConfig{
+		SaltConfig: &salt.Config{},
+		SaltView:   &logical.InmemStorage{},
+		Config: map[string]string{
+			"endpoint":            endpoint,
+			"insecure":            "true",
+			"headers":             "authorization=Bearer collector-token",
+			"node_name":           "node1",
+			"resource_attributes": "deployment.environment=test, service.name=vault-prod",
+		},
+		MountPath:   "otel/",
+		ClusterName: "cluster1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer be.(*Backend).Close()
+
+	in := &logical.LogInput{
+		Request: &logical.Request{
+			ID:        "request-id",
+			Operation: logical.UpdateOperation,
+			Path:      "secret/foo",
+			Connection: &logical.Connection{
+				RemoteAddr: "127.0.0.1",
+			},
+		},
+	}
+	ctx := namespace.RootContext(nil)
+	if err := be.LogRequest(ctx, in); err != nil {
+		t.Fatal(err)
+	}
+	if err := be.LogResponse(ctx, in); err != nil {
+		t.Fatal(err)
+	}
+
+	collector.lock.Lock()
+	defer collector.lock.Unlock()
+
+	if len(collector.requests) != 2 {
+		t.Fatalf("expected 2 exports, got %d", len(collector.requests))
+	}
+	if auth := collector.metadata[0].Get("authorization"); len(auth) != 1 || auth[0] != "Bearer collector-token" {
+		t.Fatalf("bad authorization header: %v", auth)
+	}
+
+	resourceLogs := collector.requests[0].ResourceLogs[0]
+	expected := map[string]string{
+		"service.name":           "vault-prod",
+		"host.name":              "node1",
+		"vault.cluster.name":     "cluster1",
+		"vault.audit.mount":      "otel/",
+		"deployment.environment": "test",
+	}
+	resource := attributes(resourceLogs.Resource.Attributes)
+	if len(resource) != len(expected) {
+		t.Fatalf("bad resource attributes: %v", resource)
+	}
+	for k, v := range expected {
+		if resource[k] != v {
+			t.Fatalf("bad resource attribute %q: %q", k, resource[k])
+		}
+	}
+
+	record := resourceLogs.ScopeLogs[0].LogRecords[0]
+	recordAttributes := attributes(record.Attributes)
+	if recordAttributes["vault.audit.type"] != "request" || recordAttributes["vault.request.path"] != "secret/foo" || recordAttributes["vault.request.id"] != "request-id" {
+		t.Fatalf("bad log record attributes: %v", recordAttributes)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(record.Body.GetStringValue()), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["type"] != "request" {
+		t.Fatalf("bad audit entry: %v", entry)
+	}
+
+	record = collector.requests[1].ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	if attributes(record.Attributes)["vault.audit.type"] != "response" {
+		t.Fatalf("bad log record attributes: %v", record.Attributes)
+	}
+}
@@ -109,10 +109,12 @@ func Backend(conf *logical.BackendConfig) (*backend, error) {
 			LocalStorage: []string{
 				secretIDLocalPrefix,
 				secretIDAccessorLocalPrefix,
+				deliveryStatusLocalPrefix,
 			},
 		},
 		Paths: framework.PathAppend(
 			rolePaths(b),
+			secretIDDeliveryPaths(b),
 			[]*framework.Path{
 				pathLogin(b),
 				pathTidySecretID(b),
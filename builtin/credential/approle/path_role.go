@@ -80,6 +80,10 @@ type roleStorageEntry struct {
 	// SecretIDPrefix is the storage prefix for persisting secret IDs. This
 	// differs based on whether the secret IDs are cluster local or not.
 	SecretIDPrefix string `json:"secret_id_prefix" mapstructure:"secret_id_prefix"`
+
+	// SecretIDDelivery, if set, is the broker that secret IDs pushed for the
+	// role are delivered to.
+	SecretIDDelivery *secretIDDeliveryConfig `json:"secret_id_delivery" mapstructure:"secret_id_delivery"`
 }
 
 // roleIDStorageEntry represents the reverse mapping from RoleID to Role
@@ -108,6 +112,9 @@ type roleIDStorageEntry struct {
 // role/<role_name>/secret-id/destroy - For deleting a secret_id
 // role/<role_name>/secret-id-accessor/lookup - For reading secret_id using accessor
 // role/<role_name>/secret-id-accessor/destroy - For deleting secret_id using accessor
+// role/<role_name>/secret-id/push - For issuing a secret_id and delivering it to the role's broker
+// role/<role_name>/secret-id-delivery - For configuring the broker secret_ids are pushed to
+// role/<role_name>/secret-id-delivery/status - For listing and reading the statuses of pushed secret_ids
 func rolePaths(b *backend) []*framework.Path {
 	defTokenFields := tokenutil.TokenFields()
 
@@ -1808,6 +1815,10 @@ func (b *backend) pathRoleDelete(ctx context.Context, req *logical.Request, data
 		return nil, fmt.Errorf("failed to invalidate the secrets belonging to role %q: %w", role.name, err)
 	}
 
+	if err = b.flushDeliveryStatuses(ctx, req.Storage, role); err != nil {
+		return nil, fmt.Errorf("failed to delete the secret ID delivery statuses of role %q: %w", role.name, err)
+	}
+
 	// Delete the reverse mapping from RoleID to the role
 	if err = b.roleIDEntryDelete(ctx, req.Storage, role.RoleID); err != nil {
 		return nil, fmt.Errorf("failed to delete the mapping from RoleID to role %q: %w", role.name, err)
@@ -3335,6 +3346,26 @@ the role. This SecretID will behave similarly to the SecretIDs generated by
 the backend. The properties of this SecretID will be based on the options
 set on the role. It will expire after a period defined by the 'ttl' field
 or 'secret_id_ttl' option on the role, and/or the backend mount's maximum TTL value.`,
+	},
+	"role-secret-id-push": {
+		"Generate a SecretID against this role and deliver it to the role's broker.",
+		`The SecretID is generated like by the 'secret-id' endpoint, but instead of
+being returned to the caller it is delivered to the broker configured by the
+'secret-id-delivery' endpoint of the role. Only the accessor of the SecretID
+and the ID of the delivery are returned. If the delivery fails, the SecretID
+is destroyed.`,
+	},
+	"role-secret-id-delivery": {
+		"Configure the broker that SecretIDs pushed for the role are delivered to.",
+		`SecretIDs pushed using the 'secret-id/push' endpoint of the role are sent
+as JSON in a POST request to the configured HTTPS URL. If 'wrap_ttl' is set,
+the SecretID is response-wrapped and only the wrapping token is delivered.`,
+	},
+	"role-secret-id-delivery-status": {
+		"Read the status of the deliveries of pushed SecretIDs.",
+		`Each push of a SecretID records whether it was delivered to the broker of
+the role. The statuses are kept for 72 hours, after which they are removed by
+the tidy operation.`,
 	},
 	"role-period": {
 		"Updates the value of 'period' on the role",
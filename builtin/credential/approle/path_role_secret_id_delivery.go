@@ -0,0 +1,589 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package approle
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/tokenutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	deliveryStatusPrefix      = "secret_id_delivery/"
+	deliveryStatusLocalPrefix = "secret_id_delivery_local/"
+
+	deliveryStatusDelivered = "delivered"
+	deliveryStatusFailed    = "failed"
+
+	// deliveryStatusRetention is how long the status of a delivery is kept
+	// before it is removed by the tidy operation.
+	deliveryStatusRetention = 72 * time.Hour
+
+	deliveryTimeout = 30 * time.Second
+)
+
+// secretIDDeliveryConfig is the configuration of the broker that secret IDs
+// pushed for a role are delivered to.
+type secretIDDeliveryConfig struct {
+	URL     string            `json:"url" mapstructure:"url"`
+	CACert  string            `json:"ca_cert" mapstructure:"ca_cert"`
+	Headers map[string]string `json:"headers" mapstructure:"headers"`
+	WrapTTL time.Duration     `json:"wrap_ttl" mapstructure:"wrap_ttl"`
+}
+
+// deliveryStatusEntry is the outcome of delivering a secret ID to the broker.
+type deliveryStatusEntry struct {
+	DeliveryID       string    `json:"delivery_id"`
+	SecretIDAccessor string    `json:"secret_id_accessor"`
+	Status           string    `json:"status"`
+	Error            string    `json:"error"`
+	Wrapped          bool      `json:"wrapped"`
+	DeliveryTime     time.Time `json:"delivery_time"`
+}
+
+func secretIDDeliveryPaths(b *backend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "role/" + framework.GenericNameRegex("role_name") + "/secret-id-delivery$",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixAppRole,
+				OperationSuffix: "secret-id-delivery",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"role_name": {
+					Type:        framework.TypeString,
+					Description: fmt.Sprintf("Name of the role. Must be less than %d bytes.", maxHmacInputLength),
+				},
+				"url": {
+					Type:        framework.TypeString,
+					Description: "HTTPS URL of the broker that pushed secret IDs are delivered to.",
+				},
+				"ca_cert": {
+					Type:        framework.TypeString,
+					Description: "PEM encoded CA certificate used to verify the TLS certificate of the broker. Defaults to the system roots.",
+				},
+				"headers": {
+					Type:        framework.TypeKVPairs,
+					Description: "Headers to send along with deliveries, e.g. to authenticate to the broker.",
+				},
+				"wrap_ttl": {
+					Type: framework.TypeDurationSecond,
+					Description: `If set, pushed secret IDs are response-wrapped with this TTL and only the
+wrapping token is delivered to the broker.`,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.pathRoleSecretIDDeliveryUpdate,
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathRoleSecretIDDeliveryRead,
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.pathRoleSecretIDDeliveryDelete,
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(roleHelp["role-secret-id-delivery"][0]),
+			HelpDescription: strings.TrimSpace(roleHelp["role-secret-id-delivery"][1]),
+		},
+		{
+			Pattern: "role/" + framework.GenericNameRegex("role_name") + "/secret-id/push$",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixAppRole,
+				OperationVerb:   "push",
+				OperationSuffix: "secret-id",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"role_name": {
+					Type:        framework.TypeString,
+					Description: fmt.Sprintf("Name of the role. Must be less than %d bytes.", maxHmacInputLength),
+				},
+				"metadata": {
+					Type: framework.TypeString,
+					Description: `Metadata to be tied to the SecretID. This should be a JSON
+formatted string containing the metadata in key value pairs.`,
+				},
+				"cidr_list": {
+					Type: framework.TypeCommaStringSlice,
+					Description: `Comma separated string or list of CIDR blocks enforcing secret IDs to be used from
+specific set of IP addresses. If 'bound_cidr_list' is set on the role, then the
+list of CIDR blocks listed here should be a subset of the CIDR blocks listed on
+the role.`,
+				},
+				"token_bound_cidrs": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: tokenutil.TokenFields()["token_bound_cidrs"].Description,
+				},
+				"num_uses": {
+					Type: framework.TypeInt,
+					Description: `Number of times this SecretID can be used, after which the SecretID expires.
+Overrides secret_id_num_uses role option when supplied. May not be higher than role's secret_id_num_uses.`,
+				},
+				"ttl": {
+					Type: framework.TypeDurationSecond,
+					Description: `Duration in seconds after which this SecretID expires.
+Overrides secret_id_ttl role option when supplied. May not be longer than role's secret_id_ttl.`,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.pathRoleSecretIDPushUpdate,
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(roleHelp["role-secret-id-push"][0]),
+			HelpDescription: strings.TrimSpace(roleHelp["role-secret-id-push"][1]),
+		},
+		{
+			Pattern: "role/" + framework.GenericNameRegex("role_name") + "/secret-id-delivery/status/?$",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixAppRole,
+				OperationSuffix: "secret-id-deliveries",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"role_name": {
+					Type:        framework.TypeString,
+					Description: fmt.Sprintf("Name of the role. Must be less than %d bytes.", maxHmacInputLength),
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.pathRoleSecretIDDeliveryStatusList,
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(roleHelp["role-secret-id-delivery-status"][0]),
+			HelpDescription: strings.TrimSpace(roleHelp["role-secret-id-delivery-status"][1]),
+		},
+		{
+			Pattern: "role/" + framework.GenericNameRegex("role_name") + "/secret-id-delivery/status/" + framework.GenericNameRegex("delivery_id") + "$",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixAppRole,
+				OperationSuffix: "secret-id-delivery-status",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"role_name": {
+					Type:        framework.TypeString,
+					Description: fmt.Sprintf("Name of the role. Must be less than %d bytes.", maxHmacInputLength),
+				},
+				"delivery_id": {
+					Type:        framework.TypeString,
+					Description: "ID of the delivery returned when the secret ID was pushed.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathRoleSecretIDDeliveryStatusRead,
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(roleHelp["role-secret-id-delivery-status"][0]),
+			HelpDescription: strings.TrimSpace(roleHelp["role-secret-id-delivery-status"][1]),
+		},
+	}
+}
+
+func (b *backend) pathRoleSecretIDDeliveryUpdate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	roleName := data.Get("role_name").(string)
+	if roleName == "" {
+		return logical.ErrorResponse("missing role_name"), nil
+	}
+
+	lock := b.roleLock(roleName)
+	lock.Lock()
+	defer lock.Unlock()
+
+	role, err := b.roleEntry(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse(fmt.Sprintf("role %q does not exist", roleName)), logical.ErrUnsupportedPath
+	}
+
+	delivery := role.SecretIDDelivery
+	if delivery == nil {
+		delivery = &secretIDDeliveryConfig{}
+	}
+
+	if urlRaw, ok := data.GetOk("url"); ok {
+		delivery.URL = urlRaw.(string)
+	}
+	u, err := url.Parse(delivery.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return logical.ErrorResponse("url must be a valid https URL"), nil
+	}
+	if caCertRaw, ok := data.GetOk("ca_cert"); ok {
+		delivery.CACert = caCertRaw.(string)
+	}
+	if delivery.CACert != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(delivery.CACert)) {
+			return logical.ErrorResponse("failed to parse ca_cert"), nil
+		}
+	}
+	if headersRaw, ok := data.GetOk("headers"); ok {
+		delivery.Headers = headersRaw.(map[string]string)
+	}
+	if wrapTTLRaw, ok := data.GetOk("wrap_ttl"); ok {
+		delivery.WrapTTL = time.Second * time.Duration(wrapTTLRaw.(int))
+		if delivery.WrapTTL < 0 {
+			return logical.ErrorResponse("wrap_ttl cannot be negative"), nil
+		}
+	}
+
+	role.SecretIDDelivery = delivery
+	return nil, b.setRoleEntry(ctx, req.Storage, role.name, role, "")
+}
+
+func (b *backend) pathRoleSecretIDDeliveryRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	roleName := data.Get("role_name").(string)
+	if roleName == "" {
+		return logical.ErrorResponse("missing role_name"), nil
+	}
+
+	lock := b.roleLock(roleName)
+	lock.RLock()
+	defer lock.RUnlock()
+
+	role, err := b.roleEntry(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil || role.SecretIDDelivery == nil {
+		return nil, nil
+	}
+
+	// Header values may hold credentials of the broker, only return their names
+	headers := make([]string, 0, len(role.SecretIDDelivery.Headers))
+	for name := range role.SecretIDDelivery.Headers {
+		headers = append(headers, name)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"url":      role.SecretIDDelivery.URL,
+			"ca_cert":  role.SecretIDDelivery.CACert,
+			"headers":  headers,
+			"wrap_ttl": int64(role.SecretIDDelivery.WrapTTL.Seconds()),
+		},
+	}, nil
+}
+
+func (b *backend) pathRoleSecretIDDeliveryDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	roleName := data.Get("role_name").(string)
+	if roleName == "" {
+		return logical.ErrorResponse("missing role_name"), nil
+	}
+
+	lock := b.roleLock(roleName)
+	lock.Lock()
+	defer lock.Unlock()
+
+	role, err := b.roleEntry(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+
+	role.SecretIDDelivery = nil
+	return nil, b.setRoleEntry(ctx, req.Storage, role.name, role, "")
+}
+
+func (b *backend) pathRoleSecretIDPushUpdate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	roleName := data.Get("role_name").(string)
+	if roleName == "" {
+		return logical.ErrorResponse("missing role_name"), nil
+	}
+
+	lock := b.roleLock(roleName)
+	lock.RLock()
+	role, err := b.roleEntry(ctx, req.Storage, roleName)
+	lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse(fmt.Sprintf("role %q does not exist", roleName)), logical.ErrUnsupportedPath
+	}
+	if role.SecretIDDelivery == nil {
+		return logical.ErrorResponse("secret ID delivery is not configured on the role"), nil
+	}
+
+	resp, err := b.pathRoleSecretIDUpdate(ctx, req, data)
+	if err != nil || resp.IsError() {
+		return resp, err
+	}
+
+	// The secret ID is only ever handed to the broker
+	secretID := resp.Data["secret_id"].(string)
+	delete(resp.Data, "secret_id")
+
+	deliveryID, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate delivery ID: %w", err)
+	}
+
+	status := &deliveryStatusEntry{
+		DeliveryID:       deliveryID,
+		SecretIDAccessor: resp.Data["secret_id_accessor"].(string),
+		Wrapped:          role.SecretIDDelivery.WrapTTL > 0,
+	}
+
+	deliveryErr := b.deliverSecretID(ctx, role, secretID, deliveryID, resp.Data)
+	status.DeliveryTime = time.Now().UTC()
+	if deliveryErr != nil {
+		status.Status = deliveryStatusFailed
+		status.Error = deliveryErr.Error()
+
+		// Secret IDs that didn't reach the broker must not stay usable
+		if err := b.destroySecretID(ctx, req.Storage, role, secretID, status.SecretIDAccessor); err != nil {
+			return nil, fmt.Errorf("failed to destroy secret ID after failed delivery: %w", err)
+		}
+	} else {
+		status.Status = deliveryStatusDelivered
+	}
+
+	if err := b.setDeliveryStatus(ctx, req.Storage, role, status); err != nil {
+		return nil, err
+	}
+
+	if deliveryErr != nil {
+		return logical.ErrorResponse(fmt.Sprintf("failed to deliver secret ID (delivery ID %s): %v", deliveryID, deliveryErr)), nil
+	}
+
+	resp.Data["delivery_id"] = deliveryID
+	resp.Data["delivery_status"] = status.Status
+	return resp, nil
+}
+
+// deliverSecretID sends the secret ID to the broker configured on the role,
+// response-wrapping it first if configured to.
+func (b *backend) deliverSecretID(ctx context.Context, role *roleStorageEntry, secretID, deliveryID string, secretIDData map[string]interface{}) error {
+	delivery := role.SecretIDDelivery
+
+	payload := map[string]interface{}{
+		"delivery_id": deliveryID,
+		"role_name":   role.name,
+	}
+	for k, v := range secretIDData {
+		payload[k] = v
+	}
+
+	if delivery.WrapTTL > 0 {
+		wrapInfo, err := b.System().ResponseWrapData(ctx, map[string]interface{}{
+			"secret_id":          secretID,
+			"secret_id_accessor": secretIDData["secret_id_accessor"],
+		}, delivery.WrapTTL, false)
+		if err != nil {
+			return fmt.Errorf("failed to wrap secret ID: %w", err)
+		}
+		payload["wrapping_token"] = wrapInfo.Token
+		payload["wrapping_token_ttl"] = int64(wrapInfo.TTL.Seconds())
+		payload["wrapping_token_accessor"] = wrapInfo.Accessor
+	} else {
+		payload["secret_id"] = secretID
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := cleanhttp.DefaultClient()
+	client.Timeout = deliveryTimeout
+	if delivery.CACert != "" {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM([]byte(delivery.CACert))
+		client.Transport.(*http.Transport).TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for name, value := range delivery.Headers {
+		httpReq.Header.Set(name, value)
+	}
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(httpResp.Body, 4096))
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return fmt.Errorf("broker responded with status code %d", httpResp.StatusCode)
+	}
+	return nil
+}
+
+// destroySecretID removes a secret ID and its accessor.
+func (b *backend) destroySecretID(ctx context.Context, s logical.Storage, role *roleStorageEntry, secretID, secretIDAccessor string) error {
+	roleNameHMAC, err := createHMAC(role.HMACKey, role.name)
+	if err != nil {
+		return fmt.Errorf("failed to create HMAC of role_name: %w", err)
+	}
+	secretIDHMAC, err := createHMAC(role.HMACKey, secretID)
+	if err != nil {
+		return fmt.Errorf("failed to create HMAC of secret_id: %w", err)
+	}
+
+	lock := b.secretIDLock(secretIDHMAC)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := b.deleteSecretIDAccessorEntry(ctx, s, secretIDAccessor, role.SecretIDPrefix); err != nil {
+		return err
+	}
+	return s.Delete(ctx, fmt.Sprintf("%s%s/%s", role.SecretIDPrefix, roleNameHMAC, secretIDHMAC))
+}
+
+// deliveryStatusPrefixForRole returns the storage prefix of the delivery
+// statuses of the role, which are local if its secret IDs are.
+func deliveryStatusPrefixForRole(role *roleStorageEntry) string {
+	prefix := deliveryStatusPrefix
+	if role.SecretIDPrefix == secretIDLocalPrefix {
+		prefix = deliveryStatusLocalPrefix
+	}
+	return prefix + strings.ToLower(role.name) + "/"
+}
+
+func (b *backend) setDeliveryStatus(ctx context.Context, s logical.Storage, role *roleStorageEntry, status *deliveryStatusEntry) error {
+	entry, err := logical.StorageEntryJSON(deliveryStatusPrefixForRole(role)+status.DeliveryID, status)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, entry)
+}
+
+func (b *backend) pathRoleSecretIDDeliveryStatusList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	roleName := data.Get("role_name").(string)
+	if roleName == "" {
+		return logical.ErrorResponse("missing role_name"), nil
+	}
+
+	role, err := b.roleEntry(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse(fmt.Sprintf("role %q does not exist", roleName)), nil
+	}
+
+	deliveryIDs, err := req.Storage.List(ctx, deliveryStatusPrefixForRole(role))
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(deliveryIDs), nil
+}
+
+func (b *backend) pathRoleSecretIDDeliveryStatusRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	roleName := data.Get("role_name").(string)
+	if roleName == "" {
+		return logical.ErrorResponse("missing role_name"), nil
+	}
+
+	role, err := b.roleEntry(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse(fmt.Sprintf("role %q does not exist", roleName)), nil
+	}
+
+	entry, err := req.Storage.Get(ctx, deliveryStatusPrefixForRole(role)+data.Get("delivery_id").(string))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var status deliveryStatusEntry
+	if err := entry.DecodeJSON(&status); err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"delivery_id":        status.DeliveryID,
+			"secret_id_accessor": status.SecretIDAccessor,
+			"status":             status.Status,
+			"error":              status.Error,
+			"wrapped":            status.Wrapped,
+			"delivery_time":      status.DeliveryTime.Format(time.RFC3339),
+		},
+	}, nil
+}
+
+// flushDeliveryStatuses removes all the delivery statuses of the role.
+func (b *backend) flushDeliveryStatuses(ctx context.Context, s logical.Storage, role *roleStorageEntry) error {
+	prefix := deliveryStatusPrefixForRole(role)
+	deliveryIDs, err := s.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for _, deliveryID := range deliveryIDs {
+		if err := s.Delete(ctx, prefix+deliveryID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tidyDeliveryStatuses removes the delivery statuses under the prefix that
+// are older than the retention period.
+func (b *backend) tidyDeliveryStatuses(ctx context.Context, s logical.Storage, prefix string) error {
+	roleNames, err := s.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, roleName := range roleNames {
+		deliveryIDs, err := s.List(ctx, prefix+roleName)
+		if err != nil {
+			return err
+		}
+		for _, deliveryID := range deliveryIDs {
+			key := prefix + roleName + deliveryID
+			entry, err := s.Get(ctx, key)
+			if err != nil {
+				return err
+			}
+			if entry == nil {
+				continue
+			}
+
+			var status deliveryStatusEntry
+			if err := entry.DecodeJSON(&status); err != nil {
+				return err
+			}
+			if time.Since(status.DeliveryTime) < deliveryStatusRetention {
+				continue
+			}
+			if err := s.Delete(ctx, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
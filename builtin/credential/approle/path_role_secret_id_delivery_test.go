@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package approle
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/helper/wrapping"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+type wrappingSystemView struct {
+	logical.StaticSystemView
+}
+
+func (wrappingSystemView) ResponseWrapData(_ context.Context, data map[string]interface{}, ttl time.Duration, _ bool) (*wrapping.ResponseWrapInfo, error) {
+	return &wrapping.ResponseWrapInfo{
+		Token:    "wrapping-token-" + data["secret_id_accessor"].(string),
+		Accessor: "wrapping-accessor",
+		TTL:      ttl,
+	}, nil
+}
+
+// testBroker records the deliveries it receives.
+type testBroker struct {
+	lock       sync.Mutex
+	deliveries []map[string]interface{}
+	headers    []http.Header
+	status     int
+}
+
+func (tb *testBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tb.lock.Lock()
+	defer tb.lock.Unlock()
+
+	var delivery map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&delivery); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	tb.deliveries = append(tb.deliveries, delivery)
+	tb.headers = append(tb.headers, r.Header.Clone())
+	w.WriteHeader(tb.status)
+}
+
+func TestAppRole_SecretIDPush(t *testing.T) {
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	config.System = wrappingSystemView{logical.StaticSystemView{
+		DefaultLeaseTTLVal: 24 * time.Hour,
+		MaxLeaseTTLVal:     32 * 24 * time.Hour,
+	}}
+	b, err := Backend(config)
+	require.NoError(t, err)
+	require.NoError(t, b.Setup(context.Background(), config))
+	s := config.StorageView
+
+	broker := &testBroker{status: http.StatusAccepted}
+	srv := httptest.NewTLSServer(broker)
+	defer srv.Close()
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	request := func(operation logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation: operation,
+			Path:      path,
+			Storage:   s,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := request(logical.CreateOperation, "role/role1", map[string]interface{}{
+		"token_policies": "p",
+	})
+	require.False(t, resp.IsError())
+
+	// Pushing requires a configured broker
+	resp = request(logical.UpdateOperation, "role/role1/secret-id/push", nil)
+	require.True(t, resp.IsError())
+
+	resp = request(logical.UpdateOperation, "role/role1/secret-id-delivery", map[string]interface{}{
+		"url": "http://broker.example.com",
+	})
+	require.True(t, resp.IsError())
+
+	resp = request(logical.UpdateOperation, "role/role1/secret-id-delivery", map[string]interface{}{
+		"url":     srv.URL,
+		"ca_cert": caCert,
+		"headers": map[string]interface{}{"Authorization": "Bearer broker-token"},
+	})
+	require.Nil(t, resp)
+
+	resp = request(logical.ReadOperation, "role/role1/secret-id-delivery", nil)
+	require.Equal(t, srv.URL, resp.Data["url"])
+	require.Equal(t, []string{"Authorization"}, resp.Data["headers"])
+
+	// The secret ID is delivered to the broker and not returned
+	resp = request(logical.UpdateOperation, "role/role1/secret-id/push", map[string]interface{}{
+		"metadata": `{"host": "web1"}`,
+	})
+	require.False(t, resp.IsError())
+	require.NotContains(t, resp.Data, "secret_id")
+	require.Equal(t, deliveryStatusDelivered, resp.Data["delivery_status"])
+	deliveryID := resp.Data["delivery_id"].(string)
+	accessor := resp.Data["secret_id_accessor"].(string)
+
+	require.Len(t, broker.deliveries, 1)
+	delivery := broker.deliveries[0]
+	require.Equal(t, "Bearer broker-token", broker.headers[0].Get("Authorization"))
+	require.Equal(t, deliveryID, delivery["delivery_id"])
+	require.Equal(t, "role1", delivery["role_name"])
+	require.Equal(t, accessor, delivery["secret_id_accessor"])
+	require.NotEmpty(t, delivery["secret_id"])
+
+	resp = request(logical.UpdateOperation, "role/role1/secret-id/lookup", map[string]interface{}{
+		"secret_id": delivery["secret_id"],
+	})
+	require.NotNil(t, resp)
+	require.Equal(t, map[string]string{"host": "web1"}, resp.Data["metadata"])
+
+	resp = request(logical.ReadOperation, "role/role1/secret-id-delivery/status/"+deliveryID, nil)
+	require.Equal(t, deliveryStatusDelivered, resp.Data["status"])
+	require.Equal(t, accessor, resp.Data["secret_id_accessor"])
+	require.Equal(t, false, resp.Data["wrapped"])
+
+	// Wrapped secret IDs only deliver the wrapping token
+	resp = request(logical.UpdateOperation, "role/role1/secret-id-delivery", map[string]interface{}{
+		"wrap_ttl": "5m",
+	})
+	require.Nil(t, resp)
+
+	resp = request(logical.UpdateOperation, "role/role1/secret-id/push", nil)
+	require.False(t, resp.IsError())
+	require.Len(t, broker.deliveries, 2)
+	delivery = broker.deliveries[1]
+	require.NotContains(t, delivery, "secret_id")
+	require.Equal(t, "wrapping-token-"+resp.Data["secret_id_accessor"].(string), delivery["wrapping_token"])
+	require.Equal(t, float64(300), delivery["wrapping_token_ttl"])
+
+	// Secret IDs that failed to be delivered are destroyed
+	broker.status = http.StatusInternalServerError
+	resp = request(logical.UpdateOperation, "role/role1/secret-id/push", nil)
+	require.True(t, resp.IsError())
+
+	resp = request(logical.ListOperation, "role/role1/secret-id-delivery/status/", nil)
+	require.Len(t, resp.Data["keys"], 3)
+
+	var failed map[string]interface{}
+	for _, id := range resp.Data["keys"].([]string) {
+		status := request(logical.ReadOperation, "role/role1/secret-id-delivery/status/"+id, nil)
+		if status.Data["status"] == deliveryStatusFailed {
+			failed = status.Data
+		}
+	}
+	require.NotNil(t, failed)
+	require.Contains(t, failed["error"], "500")
+
+	resp = request(logical.ListOperation, "role/role1/secret-id", nil)
+	require.Len(t, resp.Data["keys"], 2)
+	require.NotContains(t, resp.Data["keys"], failed["secret_id_accessor"])
+
+	// Deleting the role removes the delivery statuses
+	request(logical.DeleteOperation, "role/role1", nil)
+	keys, err := s.List(context.Background(), deliveryStatusPrefix+"role1/")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
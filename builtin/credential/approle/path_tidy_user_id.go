@@ -272,6 +272,13 @@ func (b *backend) tidySecretIDinternal(s logical.Storage) {
 		logger.Error("error tidying local secret IDs", "error", err)
 		return
 	}
+
+	for _, prefix := range []string{deliveryStatusPrefix, deliveryStatusLocalPrefix} {
+		if err := b.tidyDeliveryStatuses(ctx, s, prefix); err != nil {
+			logger.Error("error tidying secret ID delivery statuses", "error", err)
+			return
+		}
+	}
 }
 
 // pathTidySecretIDUpdate is used to delete the expired SecretID entries
@@ -16,7 +16,9 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/nonce"
 	"github.com/hashicorp/vault/sdk/helper/ocsp"
 	"github.com/hashicorp/vault/sdk/logical"
 )
@@ -38,11 +40,13 @@ func Backend() *backend {
 		PathsSpecial: &logical.Paths{
 			Unauthenticated: []string{
 				"login",
+				"nonce",
 			},
 		},
 		Paths: []*framework.Path{
 			pathConfig(&b),
 			pathLogin(&b),
+			pathLoginNonce(&b),
 			pathListCerts(&b),
 			pathCerts(&b),
 			pathListCRLs(&b),
@@ -52,10 +56,11 @@ func Backend() *backend {
 		Invalidate:     b.invalidate,
 		BackendType:    logical.TypeCredential,
 		InitializeFunc: b.initialize,
-		PeriodicFunc:   b.updateCRLs,
+		PeriodicFunc:   b.periodicFunc,
 	}
 
 	b.crlUpdateMutex = &sync.RWMutex{}
+	b.nonces = nonce.NewStorageService(loginNoncePrefix, loginNonceValidity)
 	return &b
 }
 
@@ -67,7 +72,18 @@ type backend struct {
 	crlUpdateMutex  *sync.RWMutex
 	ocspClientMutex sync.RWMutex
 	ocspClient      *ocsp.Client
+	cdpCRLMutex     sync.RWMutex
+	cdpCRLs         *lru.Cache
 	configUpdated   atomic.Bool
+	nonces          nonce.Service
+
+	// nonceLock guards the count of the outstanding login nonces, which is
+	// kept in memory so that the unauthenticated nonce endpoint doesn't list
+	// the storage on every call.
+	nonceLock         sync.Mutex
+	noncesCounted     bool
+	outstandingNonces int
+	nonceRecountAfter time.Time
 }
 
 func (b *backend) initialize(ctx context.Context, req *logical.InitializationRequest) error {
@@ -108,8 +124,13 @@ func (b *backend) initOCSPClient(cacheSize int) {
 
 func (b *backend) updatedConfig(config *config) {
 	b.ocspClientMutex.Lock()
-	defer b.ocspClientMutex.Unlock()
 	b.initOCSPClient(config.OcspCacheSize)
+	b.ocspClientMutex.Unlock()
+
+	b.cdpCRLMutex.Lock()
+	b.initCDPCRLCache(config.CRLCacheSize)
+	b.cdpCRLMutex.Unlock()
+
 	b.configUpdated.Store(false)
 	return
 }
@@ -134,6 +155,17 @@ func (b *backend) fetchCRL(ctx context.Context, storage logical.Storage, name st
 	return fmt.Errorf("unexpected response code %d fetching CRL from %s", response.StatusCode, crl.CDP.Url)
 }
 
+func (b *backend) periodicFunc(ctx context.Context, req *logical.Request) error {
+	var errs *multierror.Error
+	if err := b.updateCRLs(ctx, req); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := b.tidyLoginNonces(ctx, req); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs.ErrorOrNil()
+}
+
 func (b *backend) updateCRLs(ctx context.Context, req *logical.Request) error {
 	b.crlUpdateMutex.Lock()
 	defer b.crlUpdateMutex.Unlock()
@@ -17,6 +17,7 @@ func (h *CLIHandler) Auth(c *api.Client, m map[string]string) (*api.Secret, erro
 	var data struct {
 		Mount string `mapstructure:"mount"`
 		Name  string `mapstructure:"name"`
+		Nonce bool   `mapstructure:"nonce"`
 	}
 	if err := mapstructure.WeakDecode(m, &data); err != nil {
 		return nil, err
@@ -29,6 +30,16 @@ func (h *CLIHandler) Auth(c *api.Client, m map[string]string) (*api.Secret, erro
 	options := map[string]interface{}{
 		"name": data.Name,
 	}
+	if data.Nonce {
+		nonce, err := c.Logical().Write(fmt.Sprintf("auth/%s/nonce", data.Mount), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching login nonce: %w", err)
+		}
+		if nonce == nil || nonce.Data["nonce"] == nil {
+			return nil, fmt.Errorf("empty response fetching login nonce")
+		}
+		options["nonce"] = nonce.Data["nonce"]
+	}
 	path := fmt.Sprintf("auth/%s/login", data.Mount)
 	secret, err := c.Logical().Write(path, options)
 	if err != nil {
@@ -58,6 +69,10 @@ Configuration:
 
   name=<string>
       Certificate role to authenticate against.
+
+  nonce=<bool>
+      Fetch a single use nonce and pass it along with the login request.
+      Required when the auth method is configured with require_login_nonce.
 `
 
 	return strings.TrimSpace(help)
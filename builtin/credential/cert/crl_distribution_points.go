@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// defaultCDPCRLMaxAge is how long a CRL fetched from a distribution point
+	// is cached when it doesn't specify when the next update is due.
+	defaultCDPCRLMaxAge = time.Hour
+
+	// maxCDPCRLSize is the maximum size of a CRL fetched from a distribution
+	// point.
+	maxCDPCRLSize = 32 * 1024 * 1024
+
+	cdpCRLFetchTimeout = 10 * time.Second
+)
+
+// cdpCRL is a CRL fetched from a CRL distribution point.
+type cdpCRL struct {
+	crl        *x509.RevocationList
+	validUntil time.Time
+}
+
+func (b *backend) initCDPCRLCache(cacheSize int) {
+	if cacheSize < 2 {
+		cacheSize = 100
+	}
+	cache, err := lru.New(cacheSize)
+	if err != nil {
+		b.Logger().Error("failed to create CRL distribution point cache", "error", err)
+		return
+	}
+	b.cdpCRLs = cache
+}
+
+// checkForChainInCDPCRLs checks every certificate of the chain against the
+// CRLs published at the distribution points listed in the certificate. It
+// returns true if any certificate has been revoked. Certificates whose issuer
+// is not part of the chain are skipped, as their CRLs can't be verified.
+func (b *backend) checkForChainInCDPCRLs(ctx context.Context, chain []*x509.Certificate) (bool, error) {
+	for i := 0; i < len(chain)-1; i++ {
+		cert, issuer := chain[i], chain[i+1]
+		if len(cert.CRLDistributionPoints) == 0 || !bytes.Equal(cert.RawIssuer, issuer.RawSubject) {
+			continue
+		}
+
+		revoked, err := b.checkForCertInCDPCRLs(ctx, cert, issuer)
+		if err != nil {
+			return false, err
+		}
+		if revoked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (b *backend) checkForCertInCDPCRLs(ctx context.Context, cert, issuer *x509.Certificate) (bool, error) {
+	var lastErr error
+	for _, url := range cert.CRLDistributionPoints {
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			continue
+		}
+
+		crl, err := b.cdpCRL(ctx, url, issuer)
+		if err != nil {
+			b.Logger().Debug("failed to fetch CRL from distribution point", "url", url, "error", err)
+			lastErr = err
+			continue
+		}
+
+		for _, revoked := range crl.RevokedCertificates {
+			if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return true, nil
+			}
+		}
+
+		// Distribution points of a certificate publish the same CRL, so a
+		// single one is enough to determine the status
+		return false, nil
+	}
+
+	if lastErr != nil {
+		return false, fmt.Errorf("failed to check revocation status of certificate with serial %s: %w", cert.SerialNumber, lastErr)
+	}
+	return false, nil
+}
+
+// cdpCRL returns the CRL published at the given URL, fetching it if it isn't
+// cached or the cached one is stale.
+func (b *backend) cdpCRL(ctx context.Context, url string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	b.cdpCRLMutex.RLock()
+	cache := b.cdpCRLs
+	b.cdpCRLMutex.RUnlock()
+
+	if cache != nil {
+		if cached, ok := cache.Get(url); ok {
+			entry := cached.(*cdpCRL)
+			if time.Now().Before(entry.validUntil) && entry.crl.CheckSignatureFrom(issuer) == nil {
+				return entry.crl, nil
+			}
+		}
+	}
+
+	crl, err := fetchCDPCRL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		return nil, fmt.Errorf("invalid signature of CRL from %s: %w", url, err)
+	}
+
+	now := time.Now()
+	validUntil := now.Add(defaultCDPCRLMaxAge)
+	if !crl.NextUpdate.IsZero() {
+		if now.After(crl.NextUpdate) {
+			return nil, fmt.Errorf("CRL from %s expired at %s", url, crl.NextUpdate.Format(time.RFC3339))
+		}
+		validUntil = crl.NextUpdate
+	}
+
+	if cache != nil {
+		cache.Add(url, &cdpCRL{
+			crl:        crl,
+			validUntil: validUntil,
+		})
+	}
+
+	return crl, nil
+}
+
+func fetchCDPCRL(ctx context.Context, url string) (*x509.RevocationList, error) {
+	ctx, cancel := context.WithTimeout(ctx, cdpCRLFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code %d fetching CRL from %s", response.StatusCode, url)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxCDPCRLSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxCDPCRLSize {
+		return nil, fmt.Errorf("CRL from %s exceeds the maximum size of %d bytes", url, maxCDPCRLSize)
+	}
+
+	return x509.ParseRevocationList(body)
+}
@@ -91,6 +91,16 @@ from the AuthorityInformationAccess extension on the certificate being inspected
 				Default:     false,
 				Description: "If set to true, rather than accepting the first successful OCSP response, query all servers and consider the certificate valid only if all servers agree.",
 			},
+			"crl_distribution_points_enabled": {
+				Type:        framework.TypeBool,
+				Default:     false,
+				Description: "Whether to check the certificates of the chain against the CRLs published at the CRL distribution points listed in them.",
+			},
+			"crl_fail_open": {
+				Type:        framework.TypeBool,
+				Default:     false,
+				Description: "If set to true, if a CRL cannot be fetched from the CRL distribution points of a certificate, login will proceed rather than failing.  If false, failing to fetch a CRL fails the request.",
+			},
 			"allowed_names": {
 				Type: framework.TypeCommaStringSlice,
 				Description: `A comma-separated list of names.
@@ -293,21 +303,23 @@ func (b *backend) pathCertRead(ctx context.Context, req *logical.Request, d *fra
 	}
 
 	data := map[string]interface{}{
-		"certificate":                  cert.Certificate,
-		"display_name":                 cert.DisplayName,
-		"allowed_names":                cert.AllowedNames,
-		"allowed_common_names":         cert.AllowedCommonNames,
-		"allowed_dns_sans":             cert.AllowedDNSSANs,
-		"allowed_email_sans":           cert.AllowedEmailSANs,
-		"allowed_uri_sans":             cert.AllowedURISANs,
-		"allowed_organizational_units": cert.AllowedOrganizationalUnits,
-		"required_extensions":          cert.RequiredExtensions,
-		"allowed_metadata_extensions":  cert.AllowedMetadataExtensions,
-		"ocsp_ca_certificates":         cert.OcspCaCertificates,
-		"ocsp_enabled":                 cert.OcspEnabled,
-		"ocsp_servers_override":        cert.OcspServersOverride,
-		"ocsp_fail_open":               cert.OcspFailOpen,
-		"ocsp_query_all_servers":       cert.OcspQueryAllServers,
+		"certificate":                     cert.Certificate,
+		"display_name":                    cert.DisplayName,
+		"allowed_names":                   cert.AllowedNames,
+		"allowed_common_names":            cert.AllowedCommonNames,
+		"allowed_dns_sans":                cert.AllowedDNSSANs,
+		"allowed_email_sans":              cert.AllowedEmailSANs,
+		"allowed_uri_sans":                cert.AllowedURISANs,
+		"allowed_organizational_units":    cert.AllowedOrganizationalUnits,
+		"required_extensions":             cert.RequiredExtensions,
+		"allowed_metadata_extensions":     cert.AllowedMetadataExtensions,
+		"ocsp_ca_certificates":            cert.OcspCaCertificates,
+		"ocsp_enabled":                    cert.OcspEnabled,
+		"ocsp_servers_override":           cert.OcspServersOverride,
+		"ocsp_fail_open":                  cert.OcspFailOpen,
+		"ocsp_query_all_servers":          cert.OcspQueryAllServers,
+		"crl_distribution_points_enabled": cert.CRLDistributionPointsEnabled,
+		"crl_fail_open":                   cert.CRLFailOpen,
 	}
 	cert.PopulateTokenData(data)
 
@@ -365,6 +377,12 @@ func (b *backend) pathCertWrite(ctx context.Context, req *logical.Request, d *fr
 	if ocspQueryAll, ok := d.GetOk("ocsp_query_all_servers"); ok {
 		cert.OcspQueryAllServers = ocspQueryAll.(bool)
 	}
+	if crlDistributionPointsEnabled, ok := d.GetOk("crl_distribution_points_enabled"); ok {
+		cert.CRLDistributionPointsEnabled = crlDistributionPointsEnabled.(bool)
+	}
+	if crlFailOpen, ok := d.GetOk("crl_fail_open"); ok {
+		cert.CRLFailOpen = crlFailOpen.(bool)
+	}
 	if displayNameRaw, ok := d.GetOk("display_name"); ok {
 		cert.DisplayName = displayNameRaw.(string)
 	}
@@ -515,6 +533,9 @@ type CertEntry struct {
 	OcspServersOverride []string
 	OcspFailOpen        bool
 	OcspQueryAllServers bool
+
+	CRLDistributionPointsEnabled bool
+	CRLFailOpen                  bool
 }
 
 const pathCertHelpSyn = `
@@ -37,6 +37,16 @@ func pathConfig(b *backend) *framework.Path {
 				Default:     100,
 				Description: `The size of the in memory OCSP response cache, shared by all configured certs`,
 			},
+			"crl_cache_size": {
+				Type:        framework.TypeInt,
+				Default:     100,
+				Description: `The size of the in memory cache of CRLs fetched from CRL distribution points, shared by all configured certs`,
+			},
+			"require_login_nonce": {
+				Type:        framework.TypeBool,
+				Default:     false,
+				Description: `If set, login requests must pass a single use nonce obtained from the nonce endpoint, preventing captured login requests from being replayed. Defaults to false.`,
+			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -75,6 +85,16 @@ func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, dat
 		}
 		config.OcspCacheSize = cacheSize
 	}
+	if cacheSizeRaw, ok := data.GetOk("crl_cache_size"); ok {
+		cacheSize := cacheSizeRaw.(int)
+		if cacheSize < 2 || cacheSize > maxCacheSize {
+			return logical.ErrorResponse("invalid CRL cache size, must be >= 2 and <= %d", maxCacheSize), nil
+		}
+		config.CRLCacheSize = cacheSize
+	}
+	if requireLoginNonceRaw, ok := data.GetOk("require_login_nonce"); ok {
+		config.RequireLoginNonce = requireLoginNonceRaw.(bool)
+	}
 	if err := b.storeConfig(ctx, req.Storage, config); err != nil {
 		return nil, err
 	}
@@ -91,6 +111,8 @@ func (b *backend) pathConfigRead(ctx context.Context, req *logical.Request, d *f
 		"disable_binding":                cfg.DisableBinding,
 		"enable_identity_alias_metadata": cfg.EnableIdentityAliasMetadata,
 		"ocsp_cache_size":                cfg.OcspCacheSize,
+		"crl_cache_size":                 cfg.CRLCacheSize,
+		"require_login_nonce":            cfg.RequireLoginNonce,
 	}
 
 	return &logical.Response{
@@ -119,4 +141,6 @@ type config struct {
 	DisableBinding              bool `json:"disable_binding"`
 	EnableIdentityAliasMetadata bool `json:"enable_identity_alias_metadata"`
 	OcspCacheSize               int  `json:"ocsp_cache_size"`
+	CRLCacheSize                int  `json:"crl_cache_size"`
+	RequireLoginNonce           bool `json:"require_login_nonce"`
 }
@@ -45,6 +45,10 @@ func pathLogin(b *backend) *framework.Path {
 				Type:        framework.TypeString,
 				Description: "The name of the certificate role to authenticate against.",
 			},
+			"nonce": {
+				Type:        framework.TypeString,
+				Description: "Single use nonce obtained from the nonce endpoint. Required when the mount is configured with require_login_nonce.",
+			},
 		},
 		Callbacks: map[logical.Operation]framework.OperationFunc{
 			logical.UpdateOperation:         b.loginPathWrapper(b.pathLogin),
@@ -110,6 +114,16 @@ func (b *backend) pathLogin(ctx context.Context, req *logical.Request, data *fra
 		b.updatedConfig(config)
 	}
 
+	if config.RequireLoginNonce {
+		redeemed, err := b.redeemLoginNonce(ctx, req, data)
+		if err != nil {
+			return nil, err
+		}
+		if !redeemed {
+			return logical.ErrorResponse("missing, invalid or already used nonce"), logical.ErrPermissionDenied
+		}
+	}
+
 	var matched *ParsedCert
 	if verifyResp, resp, err := b.verifyCredentials(ctx, req, data); err != nil {
 		return nil, err
@@ -365,6 +379,16 @@ func (b *backend) matchesConstraints(ctx context.Context, clientCert *x509.Certi
 		}
 		soFar = soFar && ocspGood
 	}
+	if soFar && config.Entry.CRLDistributionPointsEnabled {
+		revoked, err := b.checkForChainInCDPCRLs(ctx, trustedChain)
+		if err != nil {
+			if !config.Entry.CRLFailOpen {
+				return false, err
+			}
+			b.Logger().Warn("failed to check CRL distribution points, proceeding since crl_fail_open is set", "error", err)
+		}
+		soFar = !revoked
+	}
 	return soFar, nil
 }
 
@@ -5,6 +5,7 @@ package cert
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -14,9 +15,11 @@ import (
 	mathrand "math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,6 +30,7 @@ import (
 	logicaltest "github.com/hashicorp/vault/helper/testhelpers/logical"
 
 	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
 )
 
 var ocspPort int
@@ -98,6 +102,187 @@ func TestCert_RoleResolve(t *testing.T) {
 	})
 }
 
+func TestCert_RequireLoginNonce(t *testing.T) {
+	certTemplate := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName: "example.com",
+		},
+		DNSNames:     []string{"example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		SerialNumber: big.NewInt(mathrand.Int63()),
+		NotBefore:    time.Now().Add(-30 * time.Second),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	tempDir, connState, err := generateTestCertAndConnState(t, certTemplate)
+	if tempDir != "" {
+		defer os.RemoveAll(tempDir)
+	}
+	if err != nil {
+		t.Fatalf("error testing connection state: %v", err)
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(tempDir, "ca_cert.pem"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	ctx := context.Background()
+	storage := &logical.InmemStorage{}
+	config := logical.TestBackendConfig()
+	config.StorageView = storage
+	b, err := Factory(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writes := map[string]map[string]interface{}{
+		"certs/web": {"certificate": string(ca), "policies": "foo"},
+		"config":    {"require_login_nonce": true},
+	}
+	for path, data := range writes {
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      path,
+			Storage:   storage,
+			Data:      data,
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("failed to write %v: resp: %#v, err: %v", path, resp, err)
+		}
+	}
+
+	getNonce := func() string {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation:       logical.UpdateOperation,
+			Path:            "nonce",
+			Storage:         storage,
+			Unauthenticated: true,
+		})
+		if err != nil || resp == nil || resp.IsError() {
+			t.Fatalf("failed to get a nonce: resp: %#v, err: %v", resp, err)
+		}
+		return resp.Data["nonce"].(string)
+	}
+	login := func(nonce string) (*logical.Response, error) {
+		return b.HandleRequest(ctx, &logical.Request{
+			Operation:       logical.UpdateOperation,
+			Path:            "login",
+			Storage:         storage,
+			Unauthenticated: true,
+			Connection:      &logical.Connection{ConnState: &connState},
+			Data:            map[string]interface{}{"nonce": nonce},
+		})
+	}
+
+	if _, err := login(""); err != logical.ErrPermissionDenied {
+		t.Fatalf("expected login without a nonce to be denied, got: %v", err)
+	}
+	if _, err := login("bogus"); err != logical.ErrPermissionDenied {
+		t.Fatalf("expected login with an unknown nonce to be denied, got: %v", err)
+	}
+
+	nonce := getNonce()
+	resp, err := login(nonce)
+	if err != nil || resp == nil || resp.Auth == nil {
+		t.Fatalf("expected login with a nonce to succeed: resp: %#v, err: %v", resp, err)
+	}
+	if _, err := login(nonce); err != logical.ErrPermissionDenied {
+		t.Fatalf("expected replayed login to be denied, got: %v", err)
+	}
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "config",
+		Storage:   storage,
+		Data:      map[string]interface{}{"require_login_nonce": false},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("failed to write config: resp: %#v, err: %v", resp, err)
+	}
+	resp, err = login("")
+	if err != nil || resp == nil || resp.Auth == nil {
+		t.Fatalf("expected login without a nonce to succeed: resp: %#v, err: %v", resp, err)
+	}
+}
+
+func TestCert_LoginNonce(t *testing.T) {
+	ctx := context.Background()
+	storage := &logical.InmemStorage{}
+	config := logical.TestBackendConfig()
+	config.StorageView = storage
+	b, err := Factory(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getNonce := func() (*logical.Response, error) {
+		return b.HandleRequest(ctx, &logical.Request{
+			Operation:       logical.UpdateOperation,
+			Path:            "nonce",
+			Storage:         storage,
+			Unauthenticated: true,
+		})
+	}
+
+	// Nonces are only handed out when logins require them.
+	resp, err := getNonce()
+	if err != logical.ErrInvalidRequest || resp == nil || !resp.IsError() {
+		t.Fatalf("expected nonce request to fail: resp: %#v, err: %v", resp, err)
+	}
+	if keys, err := storage.List(ctx, loginNoncePrefix); err != nil || len(keys) != 0 {
+		t.Fatalf("expected no nonce to be stored: keys: %v, err: %v", keys, err)
+	}
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "config",
+		Storage:   storage,
+		Data:      map[string]interface{}{"require_login_nonce": true},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("failed to write config: resp: %#v, err: %v", resp, err)
+	}
+
+	for i := 0; i < maxOutstandingLoginNonces; i++ {
+		resp, err := getNonce()
+		if err != nil || resp == nil || resp.IsError() {
+			t.Fatalf("failed to get a nonce: resp: %#v, err: %v", resp, err)
+		}
+	}
+
+	// Outstanding nonces are bounded.
+	resp, err = getNonce()
+	if err != nil || resp == nil || resp.Data[logical.HTTPStatusCode] != http.StatusTooManyRequests {
+		t.Fatalf("expected nonce request to be rejected: resp: %#v, err: %v", resp, err)
+	}
+
+	// The nonces are counted in memory, and only counted again in storage
+	// once per interval or when they are tidied.
+	keys, err := storage.List(ctx, loginNoncePrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if err := storage.Delete(ctx, loginNoncePrefix+key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err = getNonce()
+	if err != nil || resp == nil || resp.Data[logical.HTTPStatusCode] != http.StatusTooManyRequests {
+		t.Fatalf("expected nonce request to be rejected: resp: %#v, err: %v", resp, err)
+	}
+	if err := b.(*backend).periodicFunc(ctx, &logical.Request{Storage: storage}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = getNonce()
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("failed to get a nonce: resp: %#v, err: %v", resp, err)
+	}
+}
+
 func testAccStepResolveRoleWithName(t *testing.T, connState tls.ConnectionState, certName string) logicaltest.TestStep {
 	return logicaltest.TestStep{
 		Operation:       logical.ResolveRoleOperation,
@@ -357,6 +542,107 @@ func TestCert_RoleResolveOCSP(t *testing.T) {
 	}
 }
 
+func TestCert_CRLDistributionPoints(t *testing.T) {
+	var crlLock sync.Mutex
+	var crlBytes []byte
+	crlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		crlLock.Lock()
+		defer crlLock.Unlock()
+		if crlBytes == nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(crlBytes)
+	}))
+	defer crlServer.Close()
+
+	certTemplate := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName: "example.com",
+		},
+		DNSNames:    []string{"example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+			x509.ExtKeyUsageClientAuth,
+		},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageKeyAgreement,
+		SerialNumber:          big.NewInt(mathrand.Int63()),
+		NotBefore:             time.Now().Add(-30 * time.Second),
+		NotAfter:              time.Now().Add(262980 * time.Hour),
+		CRLDistributionPoints: []string{crlServer.URL},
+	}
+	tempDir, connState, err := generateTestCertAndConnState(t, certTemplate)
+	if tempDir != "" {
+		defer os.RemoveAll(tempDir)
+	}
+	require.NoError(t, err)
+	ca, err := ioutil.ReadFile(filepath.Join(tempDir, "ca_cert.pem"))
+	require.NoError(t, err)
+	issuer := parsePEM(ca)
+	pkf, err := ioutil.ReadFile(filepath.Join(tempDir, "ca_key.pem"))
+	require.NoError(t, err)
+	pk, err := certutil.ParsePEMBundle(string(pkf))
+	require.NoError(t, err)
+
+	createCRL := func(t *testing.T, serials ...*big.Int) []byte {
+		t.Helper()
+		var revoked []pkix.RevokedCertificate
+		for _, serial := range serials {
+			revoked = append(revoked, pkix.RevokedCertificate{
+				SerialNumber:   serial,
+				RevocationTime: time.Now(),
+			})
+		}
+		crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			RevokedCertificates: revoked,
+			Number:              big.NewInt(1),
+			ThisUpdate:          time.Now(),
+			NextUpdate:          time.Now().Add(time.Hour),
+		}, issuer[0], pk.PrivateKey)
+		require.NoError(t, err)
+		return crl
+	}
+
+	cases := []struct {
+		name        string
+		failOpen    bool
+		crl         []byte
+		errExpected bool
+	}{
+		{"failFalseGoodCert", false, createCRL(t, big.NewInt(1)), false},
+		{"failFalseRevokedCert", false, createCRL(t, certTemplate.SerialNumber), true},
+		{"failFalseUnavailableCRL", false, nil, true},
+		{"failTrueGoodCert", true, createCRL(t, big.NewInt(1)), false},
+		{"failTrueRevokedCert", true, createCRL(t, certTemplate.SerialNumber), true},
+		{"failTrueUnavailableCRL", true, nil, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			crlLock.Lock()
+			crlBytes = c.crl
+			crlLock.Unlock()
+
+			var loginStep logicaltest.TestStep
+			if c.errExpected {
+				loginStep = testAccStepLoginWithNameInvalid(t, connState, "web")
+			} else {
+				loginStep = testAccStepLoginWithName(t, connState, "web")
+			}
+			logicaltest.Test(t, logicaltest.TestCase{
+				CredentialBackend: testFactory(t),
+				Steps: []logicaltest.TestStep{
+					testAccStepCertWithExtraParams(t, "web", ca, "foo", allowed{dns: "example.com"}, false,
+						map[string]interface{}{"crl_distribution_points_enabled": true, "crl_fail_open": c.failOpen}),
+					testAccStepReadCertPolicy(t, "web", false, map[string]interface{}{"crl_distribution_points_enabled": true, "crl_fail_open": c.failOpen}),
+					loginStep,
+				},
+			})
+		})
+	}
+}
+
 func serialFromBigInt(serial *big.Int) string {
 	return strings.TrimSpace(certutil.GetHexFormatted(serial.Bytes(), ":"))
 }
@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cert
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	loginNoncePrefix   = "login-nonces/"
+	loginNonceValidity = 5 * time.Minute
+
+	// maxOutstandingLoginNonces bounds the number of unredeemed nonces kept
+	// in storage, since anyone can ask for one.
+	maxOutstandingLoginNonces = 1000
+
+	// loginNonceRecountInterval is the minimum interval between the counts of
+	// the nonces in storage when too many nonces are outstanding.
+	loginNonceRecountInterval = 10 * time.Second
+)
+
+func pathLoginNonce(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "nonce",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixCert,
+			OperationVerb:   "generate",
+			OperationSuffix: "login-nonce",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathLoginNonceWrite,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"nonce": {
+								Type:        framework.TypeString,
+								Description: "Single use nonce to pass to the login endpoint",
+								Required:    true,
+							},
+							"expiration": {
+								Type:        framework.TypeTime,
+								Description: "Time after which the nonce is no longer accepted",
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathLoginNonceHelpSyn,
+		HelpDescription: pathLoginNonceHelpDesc,
+	}
+}
+
+func (b *backend) pathLoginNonceWrite(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	config, err := b.Config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if !config.RequireLoginNonce {
+		return logical.ErrorResponse("login nonces are not enabled on this mount"), logical.ErrInvalidRequest
+	}
+
+	b.nonceLock.Lock()
+	defer b.nonceLock.Unlock()
+
+	if !b.noncesCounted {
+		if err := b.countLoginNonces(ctx, req.Storage); err != nil {
+			return nil, err
+		}
+	}
+	if b.outstandingNonces >= maxOutstandingLoginNonces && time.Now().After(b.nonceRecountAfter) {
+		// The count doesn't track the nonces which expire or fail to be
+		// redeemed, so tidy and count them again before turning the request
+		// down, but not more than once per interval.
+		if err := b.nonces.Tidy(ctx, req.Storage); err != nil {
+			return nil, err
+		}
+		if err := b.countLoginNonces(ctx, req.Storage); err != nil {
+			return nil, err
+		}
+		b.nonceRecountAfter = time.Now().Add(loginNonceRecountInterval)
+	}
+	if b.outstandingNonces >= maxOutstandingLoginNonces {
+		return logical.RespondWithStatusCode(logical.ErrorResponse("too many outstanding login nonces, retry later"), req, http.StatusTooManyRequests)
+	}
+
+	nonce, expiration, err := b.nonces.Get(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	b.outstandingNonces++
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"nonce":      nonce,
+			"expiration": expiration.Format(time.RFC3339),
+		},
+	}, nil
+}
+
+// countLoginNonces counts the login nonces in storage. It must be called
+// with the nonce lock held.
+func (b *backend) countLoginNonces(ctx context.Context, s logical.Storage) error {
+	keys, err := s.List(ctx, loginNoncePrefix)
+	if err != nil {
+		return err
+	}
+	b.outstandingNonces = len(keys)
+	b.noncesCounted = true
+	return nil
+}
+
+// redeemLoginNonce consumes the nonce passed to the login endpoint, failing
+// if it is missing, unknown, expired or was already used.
+func (b *backend) redeemLoginNonce(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
+	nonce := data.Get("nonce").(string)
+	if nonce == "" {
+		return false, nil
+	}
+	redeemed, err := b.nonces.Redeem(ctx, req.Storage, nonce)
+	if err != nil || !redeemed {
+		return redeemed, err
+	}
+
+	b.nonceLock.Lock()
+	if b.outstandingNonces > 0 {
+		b.outstandingNonces--
+	}
+	b.nonceLock.Unlock()
+
+	return true, nil
+}
+
+// tidyLoginNonces removes expired login nonces and counts the remaining ones
+// again. Only the active node of the primary cluster writes to storage, so the
+// other nodes leave them alone.
+func (b *backend) tidyLoginNonces(ctx context.Context, req *logical.Request) error {
+	if b.System().ReplicationState().HasState(consts.ReplicationDRSecondary|consts.ReplicationPerformanceStandby) ||
+		b.System().ReplicationState().HasState(consts.ReplicationPerformanceSecondary) && !b.System().LocalMount() {
+		return nil
+	}
+
+	b.nonceLock.Lock()
+	defer b.nonceLock.Unlock()
+
+	if err := b.nonces.Tidy(ctx, req.Storage); err != nil {
+		return err
+	}
+	return b.countLoginNonces(ctx, req.Storage)
+}
+
+const pathLoginNonceHelpSyn = `
+Generate a single use nonce for logging in.
+`
+
+const pathLoginNonceHelpDesc = `
+This endpoint returns a nonce which is accepted once by the login endpoint
+before it expires. It is only available when the mount is configured with
+require_login_nonce, in which case every login request must carry a fresh
+nonce, so a captured login request can't be replayed.
+`
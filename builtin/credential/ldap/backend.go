@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/vault/sdk/framework"
@@ -44,6 +45,7 @@ func Backend() *backend {
 
 		Paths: []*framework.Path{
 			pathConfig(&b),
+			pathConfigHealth(&b),
 			pathGroups(&b),
 			pathGroupsList(&b),
 			pathUsers(&b),
@@ -51,8 +53,11 @@ func Backend() *backend {
 			pathLogin(&b),
 		},
 
-		AuthRenew:   b.pathLoginRenew,
-		BackendType: logical.TypeCredential,
+		AuthRenew:       b.pathLoginRenew,
+		Invalidate:      b.invalidate,
+		Clean:           b.cleanup,
+		HealthCheckFunc: b.healthCheck,
+		BackendType:     logical.TypeCredential,
 	}
 
 	return &b
@@ -60,6 +65,48 @@ func Backend() *backend {
 
 type backend struct {
 	*framework.Backend
+
+	poolLock sync.Mutex
+	pool     *ldaputil.Pool
+}
+
+func (b *backend) invalidate(_ context.Context, key string) {
+	switch key {
+	case "config":
+		b.resetPool()
+	}
+}
+
+func (b *backend) cleanup(_ context.Context) {
+	b.resetPool()
+}
+
+// getPool returns the connection pool to the LDAP servers of the
+// configuration, creating it if needed.
+func (b *backend) getPool(cfg *ldapConfigEntry) *ldaputil.Pool {
+	b.poolLock.Lock()
+	defer b.poolLock.Unlock()
+
+	if b.pool == nil {
+		client := &ldaputil.Client{
+			Logger: b.Logger(),
+			LDAP:   ldaputil.NewLDAP(),
+		}
+		b.pool = ldaputil.NewPool(client, cfg.ConfigEntry, cfg.ConnectionPoolSize, cfg.HealthCheckInterval)
+	}
+	return b.pool
+}
+
+// resetPool closes the connection pool, so that the next login creates a new
+// one from the current configuration.
+func (b *backend) resetPool() {
+	b.poolLock.Lock()
+	defer b.poolLock.Unlock()
+
+	if b.pool != nil {
+		b.pool.Close()
+		b.pool = nil
+	}
 }
 
 func (b *backend) Login(ctx context.Context, req *logical.Request, username string, password string, usernameAsAlias bool) (string, []string, *logical.Response, []string, error) {
@@ -80,7 +127,8 @@ func (b *backend) Login(ctx context.Context, req *logical.Request, username stri
 		LDAP:   ldaputil.NewLDAP(),
 	}
 
-	c, err := ldapClient.DialLDAP(cfg.ConfigEntry)
+	pool := b.getPool(cfg)
+	c, err := pool.Get()
 	if err != nil {
 		return "", nil, logical.ErrorResponse(err.Error()), nil, nil
 	}
@@ -136,7 +184,7 @@ func (b *backend) Login(ctx context.Context, req *logical.Request, username stri
 	}
 
 	if cfg.AnonymousGroupSearch {
-		c, err = ldapClient.DialLDAP(cfg.ConfigEntry)
+		c, err = pool.Dial()
 		if err != nil {
 			return "", nil, logical.ErrorResponse("ldap operation failed: failed to connect to LDAP server"), nil, nil
 		}
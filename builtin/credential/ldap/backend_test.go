@@ -6,8 +6,10 @@ package ldap
 import (
 	"context"
 	"fmt"
+	"net"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -816,6 +818,79 @@ func TestBackend_configDefaultsAfterUpdate(t *testing.T) {
 	})
 }
 
+func TestLdapAuthBackend_ConfigHealth(t *testing.T) {
+	b, storage := createBackendWithStorage(t)
+	defer b.Cleanup(context.Background())
+	ctx := context.Background()
+
+	resp, err := b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "config/health",
+		Storage:   storage,
+	})
+	if err != nil || !resp.IsError() {
+		t.Fatalf("expected error for missing config, err:%v resp:%#v", err, resp)
+	}
+	if err := b.HealthCheck(ctx, &logical.HealthCheckRequest{Storage: storage}); err != nil {
+		t.Fatalf("expected unconfigured backend to be healthy, err:%v", err)
+	}
+
+	// Reserve a port that nothing listens on
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	downURL := "ldap://" + l.Addr().String()
+	l.Close()
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "config",
+		Data: map[string]interface{}{
+			"url":                  downURL,
+			"connection_pool_size": 4,
+			"connection_timeout":   1,
+		},
+		Storage: storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "config",
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	if resp.Data["connection_pool_size"] != 4 || resp.Data["health_check_interval"] != int64(0) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "config/health",
+		Storage:   storage,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	if resp.Data["healthy"] != false {
+		t.Fatalf("expected server to be unhealthy: %#v", resp.Data)
+	}
+	servers := resp.Data["servers"].([]map[string]interface{})
+	if len(servers) != 1 || servers[0]["url"] != downURL || servers[0]["healthy"] != false || servers[0]["last_error"] == "" {
+		t.Fatalf("bad: %#v", servers)
+	}
+
+	err = b.HealthCheck(ctx, &logical.HealthCheckRequest{Storage: storage})
+	if err == nil || !strings.Contains(err.Error(), downURL) {
+		t.Fatalf("expected health check to fail for %s, err:%v", downURL, err)
+	}
+}
+
 func testAccStepConfigUrl(t *testing.T, cfg *ldaputil.ConfigEntry) logicaltest.TestStep {
 	return logicaltest.TestStep{
 		Operation: logical.UpdateOperation,
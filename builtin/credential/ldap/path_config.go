@@ -6,6 +6,7 @@ package ldap
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/consts"
@@ -46,6 +47,23 @@ func pathConfig(b *backend) *framework.Path {
 		HelpDescription: pathConfigHelpDesc,
 	}
 
+	p.Fields["connection_pool_size"] = &framework.FieldSchema{
+		Type:        framework.TypeInt,
+		Default:     0,
+		Description: "Maximum number of idle connections kept open to each LDAP server. Connections are only reused if binddn and bindpass are set. Defaults to 0, which disables connection pooling.",
+		DisplayAttrs: &framework.DisplayAttributes{
+			Name: "Connection pool size",
+		},
+	}
+	p.Fields["health_check_interval"] = &framework.FieldSchema{
+		Type:        framework.TypeDurationSecond,
+		Default:     0,
+		Description: "Interval at which the LDAP servers are checked in the background. Unhealthy servers are only tried after the healthy ones. Defaults to 0, which disables background health checks.",
+		DisplayAttrs: &framework.DisplayAttributes{
+			Name: "Health check interval",
+		},
+	}
+
 	tokenutil.AddTokenFields(p.Fields)
 	p.Fields["token_policies"].Description += ". This will apply to all tokens generated by this auth method, in addition to any configured for specific users/groups."
 	return p
@@ -127,6 +145,8 @@ func (b *backend) pathConfigRead(ctx context.Context, req *logical.Request, d *f
 	}
 
 	data := cfg.PasswordlessMap()
+	data["connection_pool_size"] = cfg.ConnectionPoolSize
+	data["health_check_interval"] = int64(cfg.HealthCheckInterval.Seconds())
 	cfg.PopulateTokenData(data)
 
 	resp := &logical.Response{
@@ -190,6 +210,19 @@ func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, d *
 		*cfg.UsePre111GroupCNBehavior = false
 	}
 
+	if poolSizeRaw, ok := d.GetOk("connection_pool_size"); ok {
+		cfg.ConnectionPoolSize = poolSizeRaw.(int)
+		if cfg.ConnectionPoolSize < 0 {
+			return logical.ErrorResponse("connection_pool_size must not be negative"), nil
+		}
+	}
+	if intervalRaw, ok := d.GetOk("health_check_interval"); ok {
+		cfg.HealthCheckInterval = time.Duration(intervalRaw.(int)) * time.Second
+		if cfg.HealthCheckInterval < 0 {
+			return logical.ErrorResponse("health_check_interval must not be negative"), nil
+		}
+	}
+
 	if err := cfg.ParseTokenFields(req, d); err != nil {
 		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
 	}
@@ -201,6 +234,7 @@ func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, d *
 	if err := req.Storage.Put(ctx, entry); err != nil {
 		return nil, err
 	}
+	b.resetPool()
 
 	if warnings := b.checkConfigUserFilter(cfg); len(warnings) > 0 {
 		return &logical.Response{
@@ -234,6 +268,9 @@ func (b *backend) getConfigFieldData() (*framework.FieldData, error) {
 type ldapConfigEntry struct {
 	tokenutil.TokenParams
 	*ldaputil.ConfigEntry
+
+	ConnectionPoolSize  int           `json:"connection_pool_size"`
+	HealthCheckInterval time.Duration `json:"health_check_interval"`
 }
 
 const pathConfigHelpSyn = `
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/ldaputil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathConfigHealth(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `config/health`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixLDAP,
+			OperationVerb:   "read",
+			OperationSuffix: "health",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathConfigHealthRead,
+			},
+		},
+
+		HelpSynopsis:    pathConfigHealthHelpSyn,
+		HelpDescription: pathConfigHealthHelpDesc,
+	}
+}
+
+func (b *backend) pathConfigHealthRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	storedConfig, err := req.Storage.Get(ctx, "config")
+	if err != nil {
+		return nil, err
+	}
+	if storedConfig == nil {
+		return logical.ErrorResponse("ldap backend not configured"), nil
+	}
+
+	cfg, err := b.Config(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	healthy := false
+	servers := make([]map[string]interface{}, 0)
+	for _, server := range b.serverHealth(cfg) {
+		s := map[string]interface{}{
+			"url":              server.URL,
+			"healthy":          server.Healthy,
+			"idle_connections": server.IdleConnections,
+			"last_error":       server.LastError,
+		}
+		if !server.LastCheck.IsZero() {
+			s["last_check"] = server.LastCheck.Format(time.RFC3339)
+		}
+		servers = append(servers, s)
+		healthy = healthy || server.Healthy
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"healthy": healthy,
+			"servers": servers,
+		},
+	}, nil
+}
+
+// healthCheck reports the backend as unhealthy if none of the configured LDAP
+// servers is healthy. An unconfigured backend has nothing to check.
+func (b *backend) healthCheck(ctx context.Context, req *logical.HealthCheckRequest) error {
+	storedConfig, err := req.Storage.Get(ctx, "config")
+	if err != nil {
+		return err
+	}
+	if storedConfig == nil {
+		return nil
+	}
+
+	cfg, err := b.Config(ctx, &logical.Request{Storage: req.Storage})
+	if err != nil {
+		return err
+	}
+
+	var serverErrs []string
+	for _, server := range b.serverHealth(cfg) {
+		if server.Healthy {
+			return nil
+		}
+		serverErrs = append(serverErrs, fmt.Sprintf("%s: %s", server.URL, server.LastError))
+	}
+
+	return fmt.Errorf("no healthy LDAP server: %s", strings.Join(serverErrs, "; "))
+}
+
+// serverHealth returns the health of the servers in the connection pool.
+func (b *backend) serverHealth(cfg *ldapConfigEntry) []ldaputil.ServerHealth {
+	pool := b.getPool(cfg)
+
+	// Without background health checks, the status would only reflect the
+	// last logins
+	if cfg.HealthCheckInterval == 0 {
+		pool.HealthCheck()
+	}
+
+	return pool.Health()
+}
+
+const pathConfigHealthHelpSyn = `
+Report the health of the configured LDAP servers.
+`
+
+const pathConfigHealthHelpDesc = `
+This endpoint reports whether each of the configured LDAP servers can be
+connected to, and bound to as the binddn if one is configured, along with the
+number of idle connections pooled for it. Logins try the healthy servers
+first, in the order they are configured in.
+
+If no health_check_interval is configured, the servers are checked when this
+endpoint is read.
+`
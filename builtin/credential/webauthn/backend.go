@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webauthn
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const operationPrefixWebAuthn = "webauthn"
+
+func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+	b := Backend()
+	if err := b.Setup(ctx, conf); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func Backend() *backend {
+	var b backend
+	b.Backend = &framework.Backend{
+		Help: backendHelp,
+
+		PathsSpecial: &logical.Paths{
+			Unauthenticated: []string{
+				"login",
+				"login/*",
+			},
+			SealWrapStorage: []string{
+				"config",
+			},
+		},
+
+		Paths: []*framework.Path{
+			pathConfig(&b),
+			pathUsers(&b),
+			pathUsersList(&b),
+			pathUserCredentials(&b),
+			pathRegisterBegin(&b),
+			pathRegisterFinish(&b),
+			pathLoginBegin(&b),
+			pathLogin(&b),
+		},
+
+		PeriodicFunc: b.tidySessions,
+		AuthRenew:    b.pathLoginRenew,
+		BackendType:  logical.TypeCredential,
+	}
+
+	return &b
+}
+
+type backend struct {
+	*framework.Backend
+
+	// userLock serializes modifications of users, since logins update the
+	// signature counters of their credentials.
+	userLock sync.Mutex
+}
+
+const backendHelp = `
+The "webauthn" credential provider allows authentication using WebAuthn
+credentials, such as passkeys and security keys, as the primary factor.
+
+The relying party is configured using the "config" endpoint. Users are
+created using the "users/" endpoints, and register credentials through
+the registration ceremony at "users/<username>/register/begin" and
+"users/<username>/register/finish". Authentication is then done through
+the login ceremony at "login/begin" and "login".
+`
@@ -0,0 +1,324 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webauthn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "vault.example.com"
+	testOrigin = "https://vault.example.com"
+)
+
+func createBackendWithStorage(t *testing.T) (*backend, logical.Storage) {
+	t.Helper()
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+
+	b := Backend()
+	if err := b.Setup(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+	return b, config.StorageView
+}
+
+// testAuthenticator is a software authenticator holding a single ES256
+// credential.
+type testAuthenticator struct {
+	key        *ecdsa.PrivateKey
+	id         []byte
+	userHandle []byte
+	counter    uint32
+}
+
+func newTestAuthenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+
+	return &testAuthenticator{key: key, id: id}
+}
+
+func (a *testAuthenticator) clientData(t *testing.T, ceremony string, challenge protocol.URLEncodedBase64) []byte {
+	t.Helper()
+
+	clientData, err := json.Marshal(map[string]interface{}{
+		"type":      ceremony,
+		"challenge": challenge.String(),
+		"origin":    testOrigin,
+	})
+	require.NoError(t, err)
+	return clientData
+}
+
+func (a *testAuthenticator) authData(flags protocol.AuthenticatorFlags) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	authData := append([]byte{}, rpIDHash[:]...)
+	authData = append(authData, byte(flags))
+	return binary.BigEndian.AppendUint32(authData, a.counter)
+}
+
+func (a *testAuthenticator) create(t *testing.T, options *protocol.CredentialCreation) map[string]interface{} {
+	t.Helper()
+
+	a.userHandle = options.Response.User.ID.(protocol.URLEncodedBase64)
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	authData := a.authData(protocol.FlagUserPresent | protocol.FlagUserVerified | protocol.FlagAttestedCredentialData)
+	authData = append(authData, make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.id)))
+	authData = append(authData, a.id...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	require.NoError(t, err)
+
+	return map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.id),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.id),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(a.clientData(t, "webauthn.create", options.Response.Challenge)),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+		},
+	}
+}
+
+func (a *testAuthenticator) get(t *testing.T, options *protocol.CredentialAssertion) map[string]interface{} {
+	t.Helper()
+
+	a.counter++
+	authData := a.authData(protocol.FlagUserPresent | protocol.FlagUserVerified)
+	clientData := a.clientData(t, "webauthn.get", options.Response.Challenge)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	return map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.id),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.id),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+			"signature":         base64.RawURLEncoding.EncodeToString(signature),
+			"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+		},
+	}
+}
+
+func testRequest(t *testing.T, b *backend, s logical.Storage, op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+	t.Helper()
+
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: op,
+		Path:      path,
+		Storage:   s,
+		Data:      data,
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+func testRegister(t *testing.T, b *backend, s logical.Storage, username string, a *testAuthenticator) string {
+	t.Helper()
+
+	resp := testRequest(t, b, s, logical.UpdateOperation, "users/"+username+"/register/begin", nil)
+	require.False(t, resp.IsError(), resp.Error())
+
+	resp = testRequest(t, b, s, logical.UpdateOperation, "users/"+username+"/register/finish", map[string]interface{}{
+		"session_id": resp.Data["session_id"],
+		"credential": a.create(t, resp.Data["options"].(*protocol.CredentialCreation)),
+	})
+	require.False(t, resp.IsError(), resp.Error())
+	return resp.Data["credential_id"].(string)
+}
+
+func testLogin(t *testing.T, b *backend, s logical.Storage, username string, a *testAuthenticator) *logical.Response {
+	t.Helper()
+
+	resp := testRequest(t, b, s, logical.UpdateOperation, "login/begin", map[string]interface{}{
+		"username": username,
+	})
+	require.False(t, resp.IsError(), resp.Error())
+
+	return testRequest(t, b, s, logical.UpdateOperation, "login", map[string]interface{}{
+		"session_id": resp.Data["session_id"],
+		"credential": a.get(t, resp.Data["options"].(*protocol.CredentialAssertion)),
+	})
+}
+
+func testConfigure(t *testing.T, b *backend, s logical.Storage) {
+	t.Helper()
+
+	resp := testRequest(t, b, s, logical.UpdateOperation, "config", map[string]interface{}{
+		"rp_id":             testRPID,
+		"rp_origins":        testOrigin,
+		"user_verification": "required",
+	})
+	require.Nil(t, resp)
+}
+
+func TestWebAuthn_Config(t *testing.T) {
+	b, s := createBackendWithStorage(t)
+
+	resp := testRequest(t, b, s, logical.UpdateOperation, "config", map[string]interface{}{
+		"rp_origins": testOrigin,
+	})
+	require.True(t, resp.IsError())
+
+	testConfigure(t, b, s)
+
+	resp = testRequest(t, b, s, logical.ReadOperation, "config", nil)
+	require.Equal(t, map[string]interface{}{
+		"rp_id":             testRPID,
+		"rp_display_name":   "Vault",
+		"rp_origins":        []string{testOrigin},
+		"attestation":       "none",
+		"resident_key":      "preferred",
+		"user_verification": "required",
+		"ceremony_timeout":  int64(300),
+	}, resp.Data)
+}
+
+func TestWebAuthn_RegisterAndLogin(t *testing.T) {
+	b, s := createBackendWithStorage(t)
+	testConfigure(t, b, s)
+
+	resp := testRequest(t, b, s, logical.CreateOperation, "users/alice", map[string]interface{}{
+		"token_policies": "dev",
+	})
+	require.Nil(t, resp)
+
+	// Users without credentials can't log in
+	resp = testRequest(t, b, s, logical.UpdateOperation, "login/begin", map[string]interface{}{
+		"username": "alice",
+	})
+	require.True(t, resp.IsError())
+
+	a := newTestAuthenticator(t)
+	credentialID := testRegister(t, b, s, "alice", a)
+
+	resp = testRequest(t, b, s, logical.ReadOperation, "users/alice", nil)
+	credentials := resp.Data["credentials"].([]map[string]interface{})
+	require.Len(t, credentials, 1)
+	require.Equal(t, credentialID, credentials[0]["id"])
+	require.Equal(t, true, credentials[0]["user_verified"])
+
+	// Log in with the username
+	resp = testLogin(t, b, s, "alice", a)
+	require.False(t, resp.IsError(), resp.Error())
+	require.Equal(t, "alice", resp.Auth.Alias.Name)
+	require.Equal(t, []string{"dev"}, resp.Auth.Policies)
+	require.Equal(t, credentialID, resp.Auth.Metadata["credential_id"])
+
+	// Log in with a discoverable credential
+	resp = testRequest(t, b, s, logical.UpdateOperation, "login/begin", nil)
+	require.False(t, resp.IsError(), resp.Error())
+	options := resp.Data["options"].(*protocol.CredentialAssertion)
+	require.Empty(t, options.Response.AllowedCredentials)
+
+	sessionID := resp.Data["session_id"]
+	assertion := a.get(t, options)
+	resp = testRequest(t, b, s, logical.UpdateOperation, "login", map[string]interface{}{
+		"session_id": sessionID,
+		"credential": assertion,
+	})
+	require.False(t, resp.IsError(), resp.Error())
+	require.Equal(t, "alice", resp.Auth.Alias.Name)
+
+	// Sessions can only be used once
+	resp = testRequest(t, b, s, logical.UpdateOperation, "login", map[string]interface{}{
+		"session_id": sessionID,
+		"credential": assertion,
+	})
+	require.True(t, resp.IsError())
+
+	// Renewals succeed while the credential is registered
+	resp = testLogin(t, b, s, "alice", a)
+	require.False(t, resp.IsError(), resp.Error())
+	auth := resp.Auth
+	auth.TokenPolicies = auth.Policies
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.RenewOperation,
+		Path:      "login",
+		Storage:   s,
+		Auth:      auth,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Auth)
+
+	// Remove the credential
+	resp = testRequest(t, b, s, logical.DeleteOperation, "users/alice/credentials/"+credentialID, nil)
+	require.Nil(t, resp)
+
+	resp = testRequest(t, b, s, logical.UpdateOperation, "login/begin", map[string]interface{}{
+		"username": "alice",
+	})
+	require.True(t, resp.IsError())
+
+	_, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.RenewOperation,
+		Path:      "login",
+		Storage:   s,
+		Auth:      auth,
+	})
+	require.Error(t, err)
+}
+
+func TestWebAuthn_ClonedAuthenticator(t *testing.T) {
+	b, s := createBackendWithStorage(t)
+	testConfigure(t, b, s)
+
+	resp := testRequest(t, b, s, logical.CreateOperation, "users/bob", nil)
+	require.Nil(t, resp)
+
+	a := newTestAuthenticator(t)
+	testRegister(t, b, s, "bob", a)
+
+	a.counter = 10
+	resp = testLogin(t, b, s, "bob", a)
+	require.False(t, resp.IsError(), resp.Error())
+
+	// A clone of the authenticator has a lower signature counter
+	a.counter = 5
+	resp = testLogin(t, b, s, "bob", a)
+	require.True(t, resp.IsError())
+}
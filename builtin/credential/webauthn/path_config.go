@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webauthn
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	configPath = "config"
+
+	defaultCeremonyTimeout = 5 * time.Minute
+)
+
+func pathConfig(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "config$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixWebAuthn,
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"rp_id": {
+				Type:        framework.TypeString,
+				Description: "Relying party ID, which is the domain of the origins that users log in from.",
+			},
+			"rp_display_name": {
+				Type:        framework.TypeString,
+				Description: "Relying party name displayed by authenticators.",
+				Default:     "Vault",
+			},
+			"rp_origins": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Origins that users are allowed to register credentials and log in from.",
+			},
+			"attestation": {
+				Type:          framework.TypeString,
+				Description:   `Attestation conveyance preference for registrations. One of "none", "indirect", "direct" or "enterprise".`,
+				Default:       string(protocol.PreferNoAttestation),
+				AllowedValues: []interface{}{"none", "indirect", "direct", "enterprise"},
+			},
+			"resident_key": {
+				Type:          framework.TypeString,
+				Description:   `Whether registered credentials must be discoverable (resident keys). One of "discouraged", "preferred" or "required".`,
+				Default:       string(protocol.ResidentKeyRequirementPreferred),
+				AllowedValues: []interface{}{"discouraged", "preferred", "required"},
+			},
+			"user_verification": {
+				Type:          framework.TypeString,
+				Description:   `Whether authenticators must verify users, e.g. using a PIN or biometrics. One of "discouraged", "preferred" or "required".`,
+				Default:       string(protocol.VerificationPreferred),
+				AllowedValues: []interface{}{"discouraged", "preferred", "required"},
+			},
+			"ceremony_timeout": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Time allowed to complete a registration or login ceremony.",
+				Default:     int(defaultCeremonyTimeout.Seconds()),
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathConfigRead,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationSuffix: "configuration",
+				},
+			},
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathConfigWrite,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationVerb: "configure",
+				},
+			},
+		},
+
+		HelpSynopsis:    pathConfigHelpSyn,
+		HelpDescription: pathConfigHelpDesc,
+	}
+}
+
+type configEntry struct {
+	RPID             string        `json:"rp_id"`
+	RPDisplayName    string        `json:"rp_display_name"`
+	RPOrigins        []string      `json:"rp_origins"`
+	Attestation      string        `json:"attestation"`
+	ResidentKey      string        `json:"resident_key"`
+	UserVerification string        `json:"user_verification"`
+	CeremonyTimeout  time.Duration `json:"ceremony_timeout"`
+}
+
+// webAuthn returns the relying party for the configuration.
+func (c *configEntry) webAuthn() (*webauthn.WebAuthn, error) {
+	residentKey := protocol.ResidentKeyRequirement(c.ResidentKey)
+	requireResidentKey := residentKey == protocol.ResidentKeyRequirementRequired
+
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    c.CeremonyTimeout,
+		TimeoutUVD: c.CeremonyTimeout,
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:                  c.RPID,
+		RPDisplayName:         c.RPDisplayName,
+		RPOrigins:             c.RPOrigins,
+		AttestationPreference: protocol.ConveyancePreference(c.Attestation),
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			RequireResidentKey: &requireResidentKey,
+			ResidentKey:        residentKey,
+			UserVerification:   protocol.UserVerificationRequirement(c.UserVerification),
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+}
+
+func (b *backend) config(ctx context.Context, s logical.Storage) (*configEntry, error) {
+	entry, err := s.Get(ctx, configPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result configEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, fmt.Errorf("error reading configuration: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (b *backend) pathConfigRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cfg, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"rp_id":             cfg.RPID,
+			"rp_display_name":   cfg.RPDisplayName,
+			"rp_origins":        cfg.RPOrigins,
+			"attestation":       cfg.Attestation,
+			"resident_key":      cfg.ResidentKey,
+			"user_verification": cfg.UserVerification,
+			"ceremony_timeout":  int64(cfg.CeremonyTimeout.Seconds()),
+		},
+	}, nil
+}
+
+func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cfg, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		cfg = &configEntry{
+			RPDisplayName:    d.GetDefaultOrZero("rp_display_name").(string),
+			Attestation:      d.GetDefaultOrZero("attestation").(string),
+			ResidentKey:      d.GetDefaultOrZero("resident_key").(string),
+			UserVerification: d.GetDefaultOrZero("user_verification").(string),
+			CeremonyTimeout:  defaultCeremonyTimeout,
+		}
+	}
+
+	if rpID, ok := d.GetOk("rp_id"); ok {
+		cfg.RPID = rpID.(string)
+	}
+	if rpDisplayName, ok := d.GetOk("rp_display_name"); ok {
+		cfg.RPDisplayName = rpDisplayName.(string)
+	}
+	if rpOrigins, ok := d.GetOk("rp_origins"); ok {
+		cfg.RPOrigins = strutil.RemoveDuplicates(rpOrigins.([]string), false)
+	}
+	if attestation, ok := d.GetOk("attestation"); ok {
+		cfg.Attestation = attestation.(string)
+	}
+	if residentKey, ok := d.GetOk("resident_key"); ok {
+		cfg.ResidentKey = residentKey.(string)
+	}
+	if userVerification, ok := d.GetOk("user_verification"); ok {
+		cfg.UserVerification = userVerification.(string)
+	}
+	if ceremonyTimeout, ok := d.GetOk("ceremony_timeout"); ok {
+		cfg.CeremonyTimeout = time.Duration(ceremonyTimeout.(int)) * time.Second
+	}
+
+	if cfg.RPID == "" {
+		return logical.ErrorResponse("rp_id is required"), nil
+	}
+	if len(cfg.RPOrigins) == 0 {
+		return logical.ErrorResponse("rp_origins is required"), nil
+	}
+	if cfg.CeremonyTimeout <= 0 {
+		return logical.ErrorResponse("ceremony_timeout must be positive"), nil
+	}
+	if _, err := cfg.webAuthn(); err != nil {
+		return logical.ErrorResponse("invalid configuration: %s", err), nil
+	}
+
+	entry, err := logical.StorageEntryJSON(configPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+const pathConfigHelpSyn = `
+Configure the WebAuthn relying party.
+`
+
+const pathConfigHelpDesc = `
+This endpoint configures the relying party that credentials are registered
+with and the policies that apply to registration and login ceremonies,
+such as whether credentials must be discoverable and whether authenticators
+must verify users.
+`
@@ -0,0 +1,292 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/cidrutil"
+	"github.com/hashicorp/vault/sdk/helper/policyutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathLoginBegin(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "login/begin$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixWebAuthn,
+			OperationVerb:   "begin",
+			OperationSuffix: "login",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"username": {
+				Type:        framework.TypeString,
+				Description: "Username of the user logging in. If not provided, users log in using a discoverable credential.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathLoginBegin,
+		},
+
+		HelpSynopsis:    pathLoginHelpSyn,
+		HelpDescription: pathLoginHelpDesc,
+	}
+}
+
+func pathLogin(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "login$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixWebAuthn,
+			OperationVerb:   "login",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"session_id": {
+				Type:        framework.TypeString,
+				Description: "ID of the login session returned when beginning the login.",
+			},
+			"credential": {
+				Type:        framework.TypeMap,
+				Description: "The assertion of the authenticator, as serialized by the client.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathLogin,
+		},
+
+		HelpSynopsis:    pathLoginHelpSyn,
+		HelpDescription: pathLoginHelpDesc,
+	}
+}
+
+func (b *backend) pathLoginBegin(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cfg, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return logical.ErrorResponse("webauthn is not configured"), nil
+	}
+	w, err := cfg.webAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []webauthn.LoginOption{
+		webauthn.WithUserVerification(protocol.UserVerificationRequirement(cfg.UserVerification)),
+	}
+
+	var assertion *protocol.CredentialAssertion
+	var sessionData *webauthn.SessionData
+	username := strings.ToLower(d.Get("username").(string))
+	if username == "" {
+		assertion, sessionData, err = w.BeginDiscoverableLogin(opts...)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		user, err := b.user(ctx, req.Storage, username)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || len(user.Credentials) == 0 {
+			return logical.ErrorResponse("invalid username or no registered credentials"), nil
+		}
+
+		assertion, sessionData, err = w.BeginLogin(&webAuthnUser{name: username, entry: user}, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sessionID, err := b.createSession(ctx, req.Storage, &sessionEntry{
+		Ceremony: ceremonyLogin,
+		Username: username,
+		Data:     *sessionData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"session_id": sessionID,
+			"options":    assertion,
+		},
+	}, nil
+}
+
+func (b *backend) pathLogin(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cfg, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return logical.ErrorResponse("webauthn is not configured"), nil
+	}
+	w, err := cfg.webAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := b.takeSession(ctx, req.Storage, d.Get("session_id").(string), ceremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return logical.ErrorResponse("invalid or expired session_id"), nil
+	}
+
+	credentialRaw, ok := d.GetOk("credential")
+	if !ok {
+		return logical.ErrorResponse("missing credential"), nil
+	}
+	body, err := json.Marshal(credentialRaw)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(body))
+	if err != nil {
+		return logical.ErrorResponse("invalid credential: %s", webAuthnErrorMessage(err)), nil
+	}
+
+	b.userLock.Lock()
+	defer b.userLock.Unlock()
+
+	username := session.Username
+	var user *UserEntry
+	var credential *webauthn.Credential
+	if username == "" {
+		credential, err = w.ValidateDiscoverableLogin(func(_, userHandle []byte) (webauthn.User, error) {
+			name, entry, err := b.userByHandle(ctx, req.Storage, userHandle)
+			if err != nil {
+				return nil, err
+			}
+			if entry == nil {
+				return nil, fmt.Errorf("unknown user handle")
+			}
+			username, user = name, entry
+			return &webAuthnUser{name: name, entry: entry}, nil
+		}, session.Data, parsed)
+	} else {
+		user, err = b.user(ctx, req.Storage, username)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return logical.ErrorResponse("invalid credential"), nil
+		}
+		credential, err = w.ValidateLogin(&webAuthnUser{name: username, entry: user}, session.Data, parsed)
+	}
+	if err != nil {
+		b.Logger().Debug("failed to validate login", "error", webAuthnErrorMessage(err))
+		return logical.ErrorResponse("invalid credential"), nil
+	}
+
+	// A signature counter that didn't increase indicates that the credential
+	// may have been cloned
+	if credential.Authenticator.CloneWarning {
+		b.Logger().Warn("signature counter of credential didn't increase, it may have been cloned", "username", username, "credential_id", encodeCredentialID(credential.ID))
+		return logical.ErrorResponse("invalid credential"), nil
+	}
+
+	for _, entry := range user.Credentials {
+		if bytes.Equal(entry.ID, credential.ID) {
+			entry.Credential.Flags = credential.Flags
+			entry.Credential.Authenticator.SignCount = credential.Authenticator.SignCount
+			entry.LastUsedTime = time.Now().UTC()
+		}
+	}
+	if err := b.setUser(ctx, req.Storage, username, user); err != nil {
+		return nil, err
+	}
+
+	// Check for a CIDR match.
+	if len(user.TokenBoundCIDRs) > 0 {
+		if req.Connection == nil {
+			b.Logger().Warn("token bound CIDRs found but no connection information available for validation")
+			return nil, logical.ErrPermissionDenied
+		}
+		if !cidrutil.RemoteAddrIsOk(req.Connection.RemoteAddr, user.TokenBoundCIDRs) {
+			return nil, logical.ErrPermissionDenied
+		}
+	}
+
+	auth := &logical.Auth{
+		Metadata: map[string]string{
+			"username":      username,
+			"credential_id": encodeCredentialID(credential.ID),
+		},
+		DisplayName: username,
+		Alias: &logical.Alias{
+			Name: username,
+		},
+	}
+	user.PopulateTokenAuth(auth)
+
+	return &logical.Response{
+		Auth: auth,
+	}, nil
+}
+
+func (b *backend) pathLoginRenew(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	// Get the user
+	user, err := b.user(ctx, req.Storage, req.Auth.Metadata["username"])
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		// User no longer exists, do not renew
+		return nil, nil
+	}
+
+	// Tokens of removed credentials are not renewed
+	credentialFound := false
+	for _, credential := range user.Credentials {
+		if encodeCredentialID(credential.ID) == req.Auth.Metadata["credential_id"] {
+			credentialFound = true
+			break
+		}
+	}
+	if !credentialFound {
+		return nil, fmt.Errorf("credential has been removed, not renewing")
+	}
+
+	if !policyutil.EquivalentPolicies(user.TokenPolicies, req.Auth.TokenPolicies) {
+		return nil, fmt.Errorf("policies have changed, not renewing")
+	}
+
+	resp := &logical.Response{Auth: req.Auth}
+	resp.Auth.Period = user.TokenPeriod
+	resp.Auth.TTL = user.TokenTTL
+	resp.Auth.MaxTTL = user.TokenMaxTTL
+	return resp, nil
+}
+
+const pathLoginHelpSyn = `
+Log in with a WebAuthn credential.
+`
+
+const pathLoginHelpDesc = `
+Login is a ceremony of two steps. The "login/begin" endpoint returns the
+options to pass to navigator.credentials.get() along with the ID of the
+login session. If no username is provided, users log in using a discoverable
+credential (passkey) and are identified by the authenticator. The assertion
+of the authenticator is then submitted to the "login" endpoint along with
+the session ID.
+`
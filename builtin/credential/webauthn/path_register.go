@@ -0,0 +1,223 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webauthn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func pathRegisterBegin(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "users/" + framework.GenericNameRegex("username") + "/register/begin$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixWebAuthn,
+			OperationVerb:   "begin",
+			OperationSuffix: "registration",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"username": {
+				Type:        framework.TypeString,
+				Description: "Username of the user registering a credential.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathRegisterBegin,
+		},
+
+		HelpSynopsis:    pathRegisterHelpSyn,
+		HelpDescription: pathRegisterHelpDesc,
+	}
+}
+
+func pathRegisterFinish(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "users/" + framework.GenericNameRegex("username") + "/register/finish$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixWebAuthn,
+			OperationVerb:   "finish",
+			OperationSuffix: "registration",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"username": {
+				Type:        framework.TypeString,
+				Description: "Username of the user registering a credential.",
+			},
+			"session_id": {
+				Type:        framework.TypeString,
+				Description: "ID of the registration session returned when beginning the registration.",
+			},
+			"credential": {
+				Type:        framework.TypeMap,
+				Description: "The public key credential created by the authenticator, as serialized by the client.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathRegisterFinish,
+		},
+
+		HelpSynopsis:    pathRegisterHelpSyn,
+		HelpDescription: pathRegisterHelpDesc,
+	}
+}
+
+func (b *backend) pathRegisterBegin(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cfg, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return logical.ErrorResponse("webauthn is not configured"), nil
+	}
+	w, err := cfg.webAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	username := strings.ToLower(d.Get("username").(string))
+	user, err := b.user(ctx, req.Storage, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return logical.ErrorResponse("unknown user %q", username), nil
+	}
+
+	// Authenticators that already hold a credential of the user shouldn't
+	// register another one
+	waUser := &webAuthnUser{name: username, entry: user}
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.Credentials))
+	for _, credential := range waUser.WebAuthnCredentials() {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	creation, sessionData, err := w.BeginRegistration(waUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return logical.ErrorResponse("failed to begin registration: %s", webAuthnErrorMessage(err)), nil
+	}
+
+	sessionID, err := b.createSession(ctx, req.Storage, &sessionEntry{
+		Ceremony: ceremonyRegistration,
+		Username: username,
+		Data:     *sessionData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"session_id": sessionID,
+			"options":    creation,
+		},
+	}, nil
+}
+
+func (b *backend) pathRegisterFinish(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cfg, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return logical.ErrorResponse("webauthn is not configured"), nil
+	}
+	w, err := cfg.webAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	username := strings.ToLower(d.Get("username").(string))
+	session, err := b.takeSession(ctx, req.Storage, d.Get("session_id").(string), ceremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.Username != username {
+		return logical.ErrorResponse("invalid or expired session_id"), nil
+	}
+
+	credentialRaw, ok := d.GetOk("credential")
+	if !ok {
+		return logical.ErrorResponse("missing credential"), nil
+	}
+	body, err := json.Marshal(credentialRaw)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(body))
+	if err != nil {
+		return logical.ErrorResponse("invalid credential: %s", webAuthnErrorMessage(err)), nil
+	}
+
+	b.userLock.Lock()
+	defer b.userLock.Unlock()
+
+	user, err := b.user(ctx, req.Storage, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return logical.ErrorResponse("unknown user %q", username), nil
+	}
+
+	credential, err := w.CreateCredential(&webAuthnUser{name: username, entry: user}, session.Data, parsed)
+	if err != nil {
+		return logical.ErrorResponse("failed to verify credential: %s", webAuthnErrorMessage(err)), nil
+	}
+	for _, existing := range user.Credentials {
+		if bytes.Equal(existing.ID, credential.ID) {
+			return logical.ErrorResponse("credential is already registered"), nil
+		}
+	}
+
+	user.Credentials = append(user.Credentials, &CredentialEntry{
+		Credential:   *credential,
+		CreationTime: time.Now().UTC(),
+	})
+	if err := b.setUser(ctx, req.Storage, username, user); err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"credential_id": encodeCredentialID(credential.ID),
+		},
+	}, nil
+}
+
+// webAuthnErrorMessage returns the message of an error returned by the
+// WebAuthn library, including its debugging information if there is any.
+func webAuthnErrorMessage(err error) string {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.DevInfo != "" {
+		return fmt.Sprintf("%s: %s", protocolErr.Details, protocolErr.DevInfo)
+	}
+	return err.Error()
+}
+
+const pathRegisterHelpSyn = `
+Register a WebAuthn credential for a user.
+`
+
+const pathRegisterHelpDesc = `
+Registration is a ceremony of two steps. The "begin" endpoint returns the
+options to pass to navigator.credentials.create() along with the ID of the
+registration session. The credential created by the authenticator is then
+submitted to the "finish" endpoint along with the session ID.
+`
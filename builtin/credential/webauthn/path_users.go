@@ -0,0 +1,386 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webauthn
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/tokenutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	userPrefix       = "user/"
+	userHandlePrefix = "user_handle/"
+
+	userHandleLength = 32
+)
+
+func pathUsersList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "users/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixWebAuthn,
+			OperationSuffix: "users",
+			Navigation:      true,
+			ItemType:        "User",
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathUserList,
+		},
+
+		HelpSynopsis:    pathUserHelpSyn,
+		HelpDescription: pathUserHelpDesc,
+	}
+}
+
+func pathUsers(b *backend) *framework.Path {
+	p := &framework.Path{
+		Pattern: "users/" + framework.GenericNameRegex("username"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixWebAuthn,
+			OperationSuffix: "user",
+			Action:          "Create",
+			ItemType:        "User",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"username": {
+				Type:        framework.TypeString,
+				Description: "Username for this user.",
+			},
+
+			"display_name": {
+				Type:        framework.TypeString,
+				Description: "Name of the user displayed by authenticators. Defaults to the username.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathUserDelete,
+			logical.ReadOperation:   b.pathUserRead,
+			logical.UpdateOperation: b.pathUserWrite,
+			logical.CreateOperation: b.pathUserWrite,
+		},
+
+		ExistenceCheck: b.userExistenceCheck,
+
+		HelpSynopsis:    pathUserHelpSyn,
+		HelpDescription: pathUserHelpDesc,
+	}
+
+	tokenutil.AddTokenFields(p.Fields)
+	return p
+}
+
+func pathUserCredentials(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "users/" + framework.GenericNameRegex("username") + "/credentials/" + framework.GenericNameRegex("credential_id"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixWebAuthn,
+			OperationSuffix: "user-credential",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"username": {
+				Type:        framework.TypeString,
+				Description: "Username of the user.",
+			},
+			"credential_id": {
+				Type:        framework.TypeString,
+				Description: "ID of the credential, encoded as unpadded base64url.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathUserCredentialDelete,
+		},
+
+		HelpSynopsis:    pathUserCredentialsHelpSyn,
+		HelpDescription: pathUserCredentialsHelpDesc,
+	}
+}
+
+// UserEntry is a user along with the WebAuthn credentials registered for it.
+type UserEntry struct {
+	tokenutil.TokenParams
+
+	DisplayName string
+
+	// UserHandle is the random WebAuthn user handle of the user, which
+	// authenticators return during discoverable logins.
+	UserHandle []byte
+
+	Credentials []*CredentialEntry
+}
+
+// CredentialEntry is a registered WebAuthn credential.
+type CredentialEntry struct {
+	webauthn.Credential
+
+	CreationTime time.Time
+	LastUsedTime time.Time
+}
+
+// webAuthnUser adapts a user entry to the user interface of the WebAuthn
+// library.
+type webAuthnUser struct {
+	name  string
+	entry *UserEntry
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return u.entry.UserHandle
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.name
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	if u.entry.DisplayName != "" {
+		return u.entry.DisplayName
+	}
+	return u.name
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.entry.Credentials))
+	for _, credential := range u.entry.Credentials {
+		credentials = append(credentials, credential.Credential)
+	}
+	return credentials
+}
+
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func encodeCredentialID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+func (b *backend) userExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	userEntry, err := b.user(ctx, req.Storage, d.Get("username").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return userEntry != nil, nil
+}
+
+func (b *backend) user(ctx context.Context, s logical.Storage, username string) (*UserEntry, error) {
+	if username == "" {
+		return nil, fmt.Errorf("missing username")
+	}
+
+	entry, err := s.Get(ctx, userPrefix+strings.ToLower(username))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result UserEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// userByHandle returns the name and entry of the user with the given user
+// handle, which authenticators return during discoverable logins.
+func (b *backend) userByHandle(ctx context.Context, s logical.Storage, userHandle []byte) (string, *UserEntry, error) {
+	entry, err := s.Get(ctx, userHandlePrefix+base64.RawURLEncoding.EncodeToString(userHandle))
+	if err != nil {
+		return "", nil, err
+	}
+	if entry == nil {
+		return "", nil, nil
+	}
+
+	username := string(entry.Value)
+	user, err := b.user(ctx, s, username)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return username, user, nil
+}
+
+func (b *backend) setUser(ctx context.Context, s logical.Storage, username string, userEntry *UserEntry) error {
+	entry, err := logical.StorageEntryJSON(userPrefix+username, userEntry)
+	if err != nil {
+		return err
+	}
+
+	return s.Put(ctx, entry)
+}
+
+func (b *backend) pathUserList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	users, err := req.Storage.List(ctx, userPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(users), nil
+}
+
+func (b *backend) pathUserDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.userLock.Lock()
+	defer b.userLock.Unlock()
+
+	username := strings.ToLower(d.Get("username").(string))
+	user, err := b.user(ctx, req.Storage, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, nil
+	}
+
+	if err := req.Storage.Delete(ctx, userHandlePrefix+base64.RawURLEncoding.EncodeToString(user.UserHandle)); err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Delete(ctx, userPrefix+username); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathUserRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	user, err := b.user(ctx, req.Storage, strings.ToLower(d.Get("username").(string)))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, nil
+	}
+
+	credentials := make([]map[string]interface{}, 0, len(user.Credentials))
+	for _, credential := range user.Credentials {
+		c := map[string]interface{}{
+			"id":               encodeCredentialID(credential.ID),
+			"attestation_type": credential.AttestationType,
+			"transports":       credential.Transport,
+			"user_verified":    credential.Flags.UserVerified,
+			"backup_eligible":  credential.Flags.BackupEligible,
+			"sign_count":       credential.Authenticator.SignCount,
+			"creation_time":    credential.CreationTime.Format(time.RFC3339),
+		}
+		if !credential.LastUsedTime.IsZero() {
+			c["last_used_time"] = credential.LastUsedTime.Format(time.RFC3339)
+		}
+		credentials = append(credentials, c)
+	}
+
+	data := map[string]interface{}{
+		"display_name": user.DisplayName,
+		"credentials":  credentials,
+	}
+	user.PopulateTokenData(data)
+
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+func (b *backend) pathUserWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.userLock.Lock()
+	defer b.userLock.Unlock()
+
+	username := strings.ToLower(d.Get("username").(string))
+	userEntry, err := b.user(ctx, req.Storage, username)
+	if err != nil {
+		return nil, err
+	}
+	// Due to existence check, user will only be nil if it's a create operation
+	if userEntry == nil {
+		userHandle := make([]byte, userHandleLength)
+		if _, err := rand.Read(userHandle); err != nil {
+			return nil, fmt.Errorf("failed to generate user handle: %w", err)
+		}
+		userEntry = &UserEntry{
+			UserHandle: userHandle,
+		}
+
+		if err := req.Storage.Put(ctx, &logical.StorageEntry{
+			Key:   userHandlePrefix + base64.RawURLEncoding.EncodeToString(userHandle),
+			Value: []byte(username),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := userEntry.ParseTokenFields(req, d); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if displayName, ok := d.GetOk("display_name"); ok {
+		userEntry.DisplayName = displayName.(string)
+	}
+
+	return nil, b.setUser(ctx, req.Storage, username, userEntry)
+}
+
+func (b *backend) pathUserCredentialDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.userLock.Lock()
+	defer b.userLock.Unlock()
+
+	username := strings.ToLower(d.Get("username").(string))
+	user, err := b.user(ctx, req.Storage, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return logical.ErrorResponse("unknown user %q", username), nil
+	}
+
+	credentialID := d.Get("credential_id").(string)
+	credentials := user.Credentials[:0]
+	for _, credential := range user.Credentials {
+		if encodeCredentialID(credential.ID) != credentialID {
+			credentials = append(credentials, credential)
+		}
+	}
+	user.Credentials = credentials
+
+	return nil, b.setUser(ctx, req.Storage, username, user)
+}
+
+const pathUserHelpSyn = `
+Manage users allowed to authenticate.
+`
+
+const pathUserHelpDesc = `
+This endpoint allows you to create, read, update, and delete users
+that are allowed to authenticate, and to list the WebAuthn credentials
+registered for them.
+
+Deleting a user will not revoke auth for prior authenticated users
+with that name, but their tokens will no longer be renewed.
+`
+
+const pathUserCredentialsHelpSyn = `
+Remove a WebAuthn credential of a user.
+`
+
+const pathUserCredentialsHelpDesc = `
+This endpoint removes a registered WebAuthn credential of a user, e.g. when
+the authenticator has been lost. The credential can no longer be used to
+log in.
+`
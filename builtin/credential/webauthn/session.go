@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package webauthn
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	sessionPrefix = "session/"
+
+	ceremonyRegistration = "registration"
+	ceremonyLogin        = "login"
+)
+
+// sessionEntry is the state of a registration or login ceremony between its
+// begin and finish steps.
+type sessionEntry struct {
+	Ceremony string               `json:"ceremony"`
+	Username string               `json:"username"`
+	Data     webauthn.SessionData `json:"data"`
+}
+
+func (b *backend) createSession(ctx context.Context, s logical.Storage, session *sessionEntry) (string, error) {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return "", err
+	}
+
+	entry, err := logical.StorageEntryJSON(sessionPrefix+id, session)
+	if err != nil {
+		return "", err
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// takeSession returns the session of the given ceremony and deletes it, so
+// that each ceremony can only be finished once. Nil is returned if there is
+// no such session or it has expired.
+func (b *backend) takeSession(ctx context.Context, s logical.Storage, id string, ceremony string) (*sessionEntry, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	entry, err := s.Get(ctx, sessionPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	if err := s.Delete(ctx, sessionPrefix+id); err != nil {
+		return nil, err
+	}
+
+	var session sessionEntry
+	if err := entry.DecodeJSON(&session); err != nil {
+		return nil, err
+	}
+	if session.Ceremony != ceremony || time.Now().After(session.Data.Expires) {
+		return nil, nil
+	}
+
+	return &session, nil
+}
+
+// tidySessions removes the sessions of ceremonies that were never finished.
+func (b *backend) tidySessions(ctx context.Context, req *logical.Request) error {
+	if !b.System().LocalMount() && b.System().ReplicationState().HasState(consts.ReplicationPerformanceSecondary|consts.ReplicationPerformanceStandby) {
+		return nil
+	}
+
+	ids, err := req.Storage.List(ctx, sessionPrefix)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		entry, err := req.Storage.Get(ctx, sessionPrefix+id)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			continue
+		}
+
+		var session sessionEntry
+		if err := entry.DecodeJSON(&session); err != nil {
+			b.Logger().Warn("failed to decode session, removing it", "id", id, "error", err)
+		} else if now.Before(session.Data.Expires) {
+			continue
+		}
+
+		if err := req.Storage.Delete(ctx, sessionPrefix+id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -125,6 +125,14 @@ token, separated by semicolons. Available in Consul 1.5 or above.`,
 				Description: `List of Node Identities to attach to the
 token. Available in Consul 1.8.1 or above.`,
 			},
+
+			"templated_policies": {
+				Type: framework.TypeStringSlice,
+				Description: `List of Templated Policies to attach to the
+token, in the form "<template name>[:<name>[:<dc1>,<dc2>]]". Templated policies
+are created within the role's namespace and partition. Available in Consul 1.17
+or above.`,
+			},
 		},
 
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -191,6 +199,9 @@ func (b *backend) pathRolesRead(ctx context.Context, req *logical.Request, d *fr
 	if len(roleConfigData.NodeIdentities) > 0 {
 		resp.Data["node_identities"] = roleConfigData.NodeIdentities
 	}
+	if len(roleConfigData.TemplatedPolicies) > 0 {
+		resp.Data["templated_policies"] = roleConfigData.TemplatedPolicies
+	}
 
 	return resp, nil
 }
@@ -203,13 +214,15 @@ func (b *backend) pathRolesWrite(ctx context.Context, req *logical.Request, d *f
 	roles := d.Get("consul_roles").([]string)
 	serviceIdentities := d.Get("service_identities").([]string)
 	nodeIdentities := d.Get("node_identities").([]string)
+	templatedPolicies := d.Get("templated_policies").([]string)
 
 	switch tokenType {
 	case "client":
 		if policy == "" && len(policies) == 0 && len(consulPolicies) == 0 &&
-			len(roles) == 0 && len(serviceIdentities) == 0 && len(nodeIdentities) == 0 {
+			len(roles) == 0 && len(serviceIdentities) == 0 && len(nodeIdentities) == 0 &&
+			len(templatedPolicies) == 0 {
 			return logical.ErrorResponse(
-				"Use either a policy document, a list of policies or roles, or a set of service or node identities, depending on your Consul version"), nil
+				"Use either a policy document, a list of policies or roles, a set of service or node identities, or a set of templated policies, depending on your Consul version"), nil
 		}
 	case "management":
 	default:
@@ -220,6 +233,13 @@ func (b *backend) pathRolesWrite(ctx context.Context, req *logical.Request, d *f
 		consulPolicies = policies
 	}
 
+	for i, templatedPolicy := range parseTemplatedPolicies(templatedPolicies) {
+		if templatedPolicy.TemplateName == "" {
+			return logical.ErrorResponse(fmt.Sprintf(
+				"templated policy %q is missing a template name", templatedPolicies[i])), nil
+		}
+	}
+
 	policyRaw, err := base64.StdEncoding.DecodeString(policy)
 	if err != nil {
 		return logical.ErrorResponse(fmt.Sprintf(
@@ -253,6 +273,7 @@ func (b *backend) pathRolesWrite(ctx context.Context, req *logical.Request, d *f
 		ConsulRoles:       roles,
 		ServiceIdentities: serviceIdentities,
 		NodeIdentities:    nodeIdentities,
+		TemplatedPolicies: templatedPolicies,
 		TokenType:         tokenType,
 		TTL:               ttl,
 		MaxTTL:            maxTTL,
@@ -285,6 +306,7 @@ type roleConfig struct {
 	ConsulRoles       []string      `json:"consul_roles"`
 	ServiceIdentities []string      `json:"service_identities"`
 	NodeIdentities    []string      `json:"node_identities"`
+	TemplatedPolicies []string      `json:"templated_policies"`
 	TTL               time.Duration `json:"lease"`
 	MaxTTL            time.Duration `json:"max_ttl"`
 	TokenType         string        `json:"token_type"`
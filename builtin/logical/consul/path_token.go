@@ -117,7 +117,7 @@ func (b *backend) pathTokenRead(ctx context.Context, req *logical.Request, d *fr
 	aclServiceIdentities := parseServiceIdentities(roleConfigData.ServiceIdentities)
 	aclNodeIdentities := parseNodeIdentities(roleConfigData.NodeIdentities)
 
-	token, _, err := c.ACL().TokenCreate(&api.ACLToken{
+	token := &api.ACLToken{
 		Description:       tokenName,
 		Policies:          policyLinks,
 		Roles:             roleLinks,
@@ -126,7 +126,13 @@ func (b *backend) pathTokenRead(ctx context.Context, req *logical.Request, d *fr
 		Local:             roleConfigData.Local,
 		Namespace:         roleConfigData.ConsulNamespace,
 		Partition:         roleConfigData.Partition,
-	}, writeOpts)
+	}
+
+	if len(roleConfigData.TemplatedPolicies) > 0 {
+		token, err = createTokenWithTemplatedPolicies(c, token, parseTemplatedPolicies(roleConfigData.TemplatedPolicies), writeOpts)
+	} else {
+		token, _, err = c.ACL().TokenCreate(token, writeOpts)
+	}
 	if err != nil {
 		return logical.ErrorResponse(err.Error()), nil
 	}
@@ -149,6 +155,40 @@ func (b *backend) pathTokenRead(ctx context.Context, req *logical.Request, d *fr
 	return s, nil
 }
 
+// aclTemplatedPolicy mirrors the templated policy structure accepted by the
+// Consul 1.17+ token endpoint, which is not yet exposed by the Consul API
+// client version Vault depends on.
+type aclTemplatedPolicy struct {
+	TemplateName      string
+	TemplateVariables *aclTemplatedPolicyVariables `json:",omitempty"`
+	Datacenters       []string                     `json:",omitempty"`
+}
+
+type aclTemplatedPolicyVariables struct {
+	Name string
+}
+
+// aclTokenWithTemplatedPolicies extends api.ACLToken with templated policies
+// so that it can be sent to Consul using the raw client.
+type aclTokenWithTemplatedPolicies struct {
+	*api.ACLToken
+	TemplatedPolicies []*aclTemplatedPolicy `json:",omitempty"`
+}
+
+func createTokenWithTemplatedPolicies(c *api.Client, token *api.ACLToken, templatedPolicies []*aclTemplatedPolicy, q *api.WriteOptions) (*api.ACLToken, error) {
+	in := &aclTokenWithTemplatedPolicies{
+		ACLToken:          token,
+		TemplatedPolicies: templatedPolicies,
+	}
+
+	var out api.ACLToken
+	if _, err := c.Raw().Write("/v1/acl/token", in, &out, q); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
 func parseServiceIdentities(data []string) []*api.ACLServiceIdentity {
 	aclServiceIdentities := []*api.ACLServiceIdentity{}
 
@@ -180,3 +220,24 @@ func parseNodeIdentities(data []string) []*api.ACLNodeIdentity {
 
 	return aclNodeIdentities
 }
+
+func parseTemplatedPolicies(data []string) []*aclTemplatedPolicy {
+	aclTemplatedPolicies := []*aclTemplatedPolicy{}
+
+	for _, templatedPolicy := range data {
+		entry := &aclTemplatedPolicy{}
+		components := strings.SplitN(templatedPolicy, ":", 3)
+		entry.TemplateName = components[0]
+		if len(components) > 1 && components[1] != "" {
+			entry.TemplateVariables = &aclTemplatedPolicyVariables{
+				Name: components[1],
+			}
+		}
+		if len(components) > 2 {
+			entry.Datacenters = strings.Split(components[2], ",")
+		}
+		aclTemplatedPolicies = append(aclTemplatedPolicies, entry)
+	}
+
+	return aclTemplatedPolicies
+}
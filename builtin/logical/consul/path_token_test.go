@@ -105,3 +105,63 @@ func TestToken_parseNodeIdentities(t *testing.T) {
 		})
 	}
 }
+
+func TestToken_parseTemplatedPolicies(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []*aclTemplatedPolicy
+	}{
+		{
+			name: "No variables",
+			args: []string{"builtin/dns"},
+			want: []*aclTemplatedPolicy{{TemplateName: "builtin/dns"}},
+		},
+		{
+			name: "Name variable",
+			args: []string{"builtin/service:web"},
+			want: []*aclTemplatedPolicy{{
+				TemplateName:      "builtin/service",
+				TemplateVariables: &aclTemplatedPolicyVariables{Name: "web"},
+			}},
+		},
+		{
+			name: "Name variable and datacenters",
+			args: []string{"builtin/node:server-1:dc1,dc2"},
+			want: []*aclTemplatedPolicy{{
+				TemplateName:      "builtin/node",
+				TemplateVariables: &aclTemplatedPolicyVariables{Name: "server-1"},
+				Datacenters:       []string{"dc1", "dc2"},
+			}},
+		},
+		{
+			name: "Datacenters without name variable",
+			args: []string{"builtin/dns::dc1"},
+			want: []*aclTemplatedPolicy{{TemplateName: "builtin/dns", Datacenters: []string{"dc1"}}},
+		},
+		{
+			name: "Missing template name",
+			args: []string{""},
+			want: []*aclTemplatedPolicy{{TemplateName: ""}},
+		},
+		{
+			name: "Multiple templated policies",
+			args: []string{"builtin/dns", "builtin/service:web:dc1"},
+			want: []*aclTemplatedPolicy{
+				{TemplateName: "builtin/dns"},
+				{
+					TemplateName:      "builtin/service",
+					TemplateVariables: &aclTemplatedPolicyVariables{Name: "web"},
+					Datacenters:       []string{"dc1"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTemplatedPolicies(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTemplatedPolicies() = %#v, want %#v", got[0], tt.want[0])
+			}
+		})
+	}
+}
@@ -706,3 +706,131 @@ BEsMUc/DHYslZebbF1zAWnkKdTt+URhtHAFB2tYRDgkZfwW+wr/w12dJTIkX965o
 HO7tI4FgpU9b0i8FTuwYkBfjwp2j0Xd2/VBR8Qpd17qKl3I6NXDsf3ykjGZAvldH
 Tll+qwEZpXSRa5OWWTpGV8I=
 -----END PRIVATE KEY-----`
+
+func TestBackend_roles(t *testing.T) {
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b, err := Factory(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "role/test")
+	req.Storage = config.StorageView
+	req.Data = map[string]interface{}{
+		"nomad_roles":   "role-a,role-b",
+		"ttl":           "1h",
+		"max_ttl":       "2h",
+		"expire_tokens": true,
+	}
+	resp, err := b.HandleRequest(context.Background(), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	req.Operation = logical.ReadOperation
+	resp, err = b.HandleRequest(context.Background(), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	expected := map[string]interface{}{
+		"type":          "client",
+		"global":        false,
+		"policies":      []string(nil),
+		"nomad_roles":   []string{"role-a", "role-b"},
+		"ttl":           int64(3600),
+		"max_ttl":       int64(7200),
+		"expire_tokens": true,
+	}
+	if !reflect.DeepEqual(expected, resp.Data) {
+		t.Fatalf("bad: expected:%#v\nactual:%#v\n", expected, resp.Data)
+	}
+
+	role, err := b.(*backend).Role(context.Background(), config.StorageView, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lease := role.leaseConfig(&configLease{TTL: time.Minute, MaxTTL: 24 * time.Hour})
+	if lease.TTL != time.Hour || lease.MaxTTL != 2*time.Hour {
+		t.Fatalf("bad: role TTLs should take precedence over the lease configuration: %#v", lease)
+	}
+
+	// Management tokens cannot be bound to roles
+	req.Operation = logical.UpdateOperation
+	req.Data = map[string]interface{}{
+		"type": "management",
+	}
+	resp, err = b.HandleRequest(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("expected error response, got: %#v", resp)
+	}
+
+	// TTL must not exceed max TTL
+	req.Path = "role/invalid"
+	req.Data = map[string]interface{}{
+		"policies": "policy",
+		"ttl":      "3h",
+		"max_ttl":  "2h",
+	}
+	resp, err = b.HandleRequest(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("expected error response, got: %#v", resp)
+	}
+}
+
+func TestBackend_tokenExpirationTTL(t *testing.T) {
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b, err := Factory(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "role/test")
+	req.Storage = config.StorageView
+	req.Data = map[string]interface{}{
+		"policies":      "policy",
+		"expire_tokens": true,
+	}
+	resp, err := b.HandleRequest(context.Background(), req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	role, err := b.(*backend).Role(context.Background(), config.StorageView, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// With the default configuration there is no explicit max TTL, so the
+	// tokens must not expire in Nomad rather than expire after the system max
+	// lease TTL, which is above Nomad's default maximum token TTL.
+	leaseConfig, err := b.(*backend).LeaseConfig(context.Background(), config.StorageView)
+	if err != nil {
+		t.Fatal(err)
+	}
+	systemMaxTTL := b.System().MaxLeaseTTL()
+	if ttl := role.tokenExpirationTTL(role.leaseConfig(leaseConfig), systemMaxTTL); ttl != 0 {
+		t.Fatalf("expected no expiration with the default config, got %s", ttl)
+	}
+
+	if ttl := role.tokenExpirationTTL(role.leaseConfig(&configLease{MaxTTL: 2 * time.Hour}), systemMaxTTL); ttl != 2*time.Hour {
+		t.Fatalf("expected the max TTL of config/lease, got %s", ttl)
+	}
+
+	if ttl := role.tokenExpirationTTL(role.leaseConfig(&configLease{MaxTTL: systemMaxTTL + time.Hour}), systemMaxTTL); ttl != systemMaxTTL {
+		t.Fatalf("expected the system max lease TTL, got %s", ttl)
+	}
+
+	role.ExpireTokens = false
+	if ttl := role.tokenExpirationTTL(role.leaseConfig(&configLease{MaxTTL: 2 * time.Hour}), systemMaxTTL); ttl != 0 {
+		t.Fatalf("expected no expiration without expire_tokens, got %s", ttl)
+	}
+}
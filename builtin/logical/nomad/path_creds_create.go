@@ -62,9 +62,7 @@ func (b *backend) pathTokenRead(ctx context.Context, req *logical.Request, d *fr
 	if err != nil {
 		return nil, err
 	}
-	if leaseConfig == nil {
-		leaseConfig = &configLease{}
-	}
+	leaseConfig = role.leaseConfig(leaseConfig)
 
 	// Get the nomad client
 	c, err := b.client(ctx, req.Storage)
@@ -82,12 +80,25 @@ func (b *backend) pathTokenRead(ctx context.Context, req *logical.Request, d *fr
 		tokenName = tokenName[:tokenNameLength]
 	}
 
+	roleLinks := make([]*api.ACLTokenRoleLink, 0, len(role.Roles))
+	for _, roleName := range role.Roles {
+		roleLinks = append(roleLinks, &api.ACLTokenRoleLink{
+			Name: roleName,
+		})
+	}
+
+	// Let Nomad expire the token on its own once the lease can no longer be
+	// renewed, so that it is cleaned up even if the revocation fails.
+	expirationTTL := role.tokenExpirationTTL(leaseConfig, b.System().MaxLeaseTTL())
+
 	// Create it
 	token, _, err := c.ACLTokens().Create(&api.ACLToken{
-		Name:     tokenName,
-		Type:     role.TokenType,
-		Policies: role.Policies,
-		Global:   role.Global,
+		Name:          tokenName,
+		Type:          role.TokenType,
+		Policies:      role.Policies,
+		Roles:         roleLinks,
+		Global:        role.Global,
+		ExpirationTTL: expirationTTL,
 	}, nil)
 	if err != nil {
 		return nil, err
@@ -99,6 +110,7 @@ func (b *backend) pathTokenRead(ctx context.Context, req *logical.Request, d *fr
 		"accessor_id": token.AccessorID,
 	}, map[string]interface{}{
 		"accessor_id": token.AccessorID,
+		"role":        name,
 	})
 	resp.Secret.TTL = leaseConfig.TTL
 	resp.Secret.MaxTTL = leaseConfig.MaxTTL
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -44,7 +45,31 @@ func pathRoles(b *backend) *framework.Path {
 
 			"policies": {
 				Type:        framework.TypeCommaStringSlice,
-				Description: "Comma-separated string or list of policies as previously created in Nomad. Required for 'client' token.",
+				Description: "Comma-separated string or list of policies as previously created in Nomad. Either policies or nomad_roles are required for 'client' token.",
+			},
+
+			"nomad_roles": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Comma-separated string or list of ACL roles as previously created in Nomad. Either policies or nomad_roles are required for 'client' token. Available in Nomad 1.4 and above.",
+			},
+
+			"ttl": {
+				Type:        framework.TypeDurationSecond,
+				Description: "TTL for the Nomad token created from the role. Overrides the ttl in config/lease.",
+			},
+
+			"max_ttl": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Max TTL for the Nomad token created from the role. Overrides the max_ttl in config/lease.",
+			},
+
+			"expire_tokens": {
+				Type: framework.TypeBool,
+				Description: `Boolean value describing if the token should also be
+given an expiration time in Nomad, matching the effective max_ttl of the role,
+so that Nomad removes it even if Vault is unable to revoke it. Only applies if
+a max_ttl is set on the role or in config/lease. Available in Nomad 1.4 and
+above. Defaults to false.`,
 			},
 
 			"global": {
@@ -127,9 +152,13 @@ func (b *backend) pathRolesRead(ctx context.Context, req *logical.Request, d *fr
 	// Generate the response
 	resp := &logical.Response{
 		Data: map[string]interface{}{
-			"type":     role.TokenType,
-			"global":   role.Global,
-			"policies": role.Policies,
+			"type":          role.TokenType,
+			"global":        role.Global,
+			"policies":      role.Policies,
+			"nomad_roles":   role.Roles,
+			"ttl":           int64(role.TTL.Seconds()),
+			"max_ttl":       int64(role.MaxTTL.Seconds()),
+			"expire_tokens": role.ExpireTokens,
 		},
 	}
 	return resp, nil
@@ -151,17 +180,22 @@ func (b *backend) pathRolesWrite(ctx context.Context, req *logical.Request, d *f
 		role.Policies = policies.([]string)
 	}
 
+	roles, ok := d.GetOk("nomad_roles")
+	if ok {
+		role.Roles = roles.([]string)
+	}
+
 	role.TokenType = d.Get("type").(string)
 	switch role.TokenType {
 	case "client":
-		if len(role.Policies) == 0 {
+		if len(role.Policies) == 0 && len(role.Roles) == 0 {
 			return logical.ErrorResponse(
-				"policies cannot be empty when using client tokens"), nil
+				"policies and nomad_roles cannot both be empty when using client tokens"), nil
 		}
 	case "management":
-		if len(role.Policies) != 0 {
+		if len(role.Policies) != 0 || len(role.Roles) != 0 {
 			return logical.ErrorResponse(
-				"policies should be empty when using management tokens"), nil
+				"policies and nomad_roles should be empty when using management tokens"), nil
 		}
 	default:
 		return logical.ErrorResponse(
@@ -173,6 +207,23 @@ func (b *backend) pathRolesWrite(ctx context.Context, req *logical.Request, d *f
 		role.Global = global.(bool)
 	}
 
+	if ttlRaw, ok := d.GetOk("ttl"); ok {
+		role.TTL = time.Second * time.Duration(ttlRaw.(int))
+	}
+
+	if maxTTLRaw, ok := d.GetOk("max_ttl"); ok {
+		role.MaxTTL = time.Second * time.Duration(maxTTLRaw.(int))
+	}
+
+	if role.MaxTTL > 0 && role.TTL > role.MaxTTL {
+		return logical.ErrorResponse("ttl cannot be greater than max_ttl"), nil
+	}
+
+	expireTokens, ok := d.GetOk("expire_tokens")
+	if ok {
+		role.ExpireTokens = expireTokens.(bool)
+	}
+
 	entry, err := logical.StorageEntryJSON("role/"+name, role)
 	if err != nil {
 		return nil, err
@@ -194,7 +245,44 @@ func (b *backend) pathRolesDelete(ctx context.Context, req *logical.Request, d *
 }
 
 type roleConfig struct {
-	Policies  []string `json:"policies"`
-	TokenType string   `json:"type"`
-	Global    bool     `json:"global"`
+	Policies     []string      `json:"policies"`
+	Roles        []string      `json:"nomad_roles"`
+	TokenType    string        `json:"type"`
+	Global       bool          `json:"global"`
+	TTL          time.Duration `json:"ttl"`
+	MaxTTL       time.Duration `json:"max_ttl"`
+	ExpireTokens bool          `json:"expire_tokens"`
+}
+
+// leaseConfig returns the lease configuration for tokens created from the
+// role, with the role's TTLs taking precedence over the backend defaults.
+func (r *roleConfig) leaseConfig(defaults *configLease) *configLease {
+	lease := &configLease{}
+	if defaults != nil {
+		*lease = *defaults
+	}
+	if r == nil {
+		return lease
+	}
+	if r.TTL > 0 {
+		lease.TTL = r.TTL
+	}
+	if r.MaxTTL > 0 {
+		lease.MaxTTL = r.MaxTTL
+	}
+	return lease
+}
+
+// tokenExpirationTTL returns the expiration TTL to set on tokens created from
+// the role in Nomad. Tokens only expire if the role asks for it and a max TTL
+// is explicitly configured, as Nomad rejects expirations above its own
+// maximum token TTL, which is lower than Vault's default max lease TTL.
+func (r *roleConfig) tokenExpirationTTL(lease *configLease, systemMaxTTL time.Duration) time.Duration {
+	if r == nil || !r.ExpireTokens || lease == nil || lease.MaxTTL == 0 {
+		return 0
+	}
+	if systemMaxTTL > 0 && lease.MaxTTL > systemMaxTTL {
+		return systemMaxTTL
+	}
+	return lease.MaxTTL
 }
@@ -36,9 +36,18 @@ func (b *backend) secretTokenRenew(ctx context.Context, req *logical.Request, d
 	if err != nil {
 		return nil, err
 	}
-	if lease == nil {
-		lease = &configLease{}
+
+	// Tokens issued before roles carried their own TTLs do not record the
+	// role, in which case only the backend lease configuration applies.
+	var role *roleConfig
+	if roleName, ok := req.Secret.InternalData["role"].(string); ok && roleName != "" {
+		role, err = b.Role(ctx, req.Storage, roleName)
+		if err != nil {
+			return nil, err
+		}
 	}
+	lease = role.leaseConfig(lease)
+
 	resp := &logical.Response{Secret: req.Secret}
 	resp.Secret.TTL = lease.TTL
 	resp.Secret.MaxTTL = lease.MaxTTL
@@ -78,6 +78,28 @@ const (
 	ACMEAuthorizationRevoked     ACMEAuthorizationStatusType = "revoked"
 )
 
+type ACMEAuthorizationReusePolicy string
+
+const (
+	// ACMEAuthorizationReuseNever always creates new authorizations for
+	// the identifiers of an order.
+	ACMEAuthorizationReuseNever ACMEAuthorizationReusePolicy = "never"
+
+	// ACMEAuthorizationReuseValid satisfies the identifiers of an order with
+	// the unexpired valid authorizations of the account, if any, such as
+	// those completed through pre-authorization or by previous orders.
+	ACMEAuthorizationReuseValid ACMEAuthorizationReusePolicy = "valid"
+)
+
+func getAcmeAuthorizationReusePolicy(policy string) (ACMEAuthorizationReusePolicy, error) {
+	switch ACMEAuthorizationReusePolicy(policy) {
+	case ACMEAuthorizationReuseNever, ACMEAuthorizationReuseValid:
+		return ACMEAuthorizationReusePolicy(policy), nil
+	default:
+		return "", fmt.Errorf("unknown authorization reuse policy: %s", policy)
+	}
+}
+
 type ACMEOrderStatusType string
 
 const (
@@ -164,6 +186,31 @@ func (aa *ACMEAuthorization) GetExpires() (time.Time, error) {
 	return time.Parse(time.RFC3339, aa.Expires)
 }
 
+// IsValidAt returns whether the authorization is valid and not yet expired
+// at the given time.
+func (aa *ACMEAuthorization) IsValidAt(now time.Time) bool {
+	if aa.Status != ACMEAuthorizationValid {
+		return false
+	}
+
+	expires, err := aa.GetExpires()
+	if err != nil {
+		return false
+	}
+
+	return expires.IsZero() || now.Before(expires)
+}
+
+// MatchesIdentifier returns whether the authorization can satisfy the
+// identifier of an order. Wildcard identifiers are only satisfied by
+// wildcard authorizations, and vice versa.
+func (aa *ACMEAuthorization) MatchesIdentifier(identifier *ACMEIdentifier) bool {
+	return aa.Identifier != nil &&
+		aa.Identifier.Type == identifier.Type &&
+		aa.Identifier.Value == identifier.Value &&
+		aa.Wildcard == identifier.IsWildcard
+}
+
 func (aa *ACMEAuthorization) NetworkMarshal(acmeCtx *acmeContext) map[string]interface{} {
 	resp := map[string]interface{}{
 		"identifier": aa.Identifier.NetworkMarshal( /* use value, not original value */ false),
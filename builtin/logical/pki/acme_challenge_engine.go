@@ -417,7 +417,7 @@ func (ace *ACMEChallengeEngine) _verifyChallenge(sc *storageContext, id string,
 
 	// If we got here, the challenge verification was successful. Update
 	// the authorization appropriately.
-	expires := now.Add(15 * 24 * time.Hour)
+	expires := now.Add(config.AuthorizationLifetime)
 	challenge.Status = ACMEChallengeValid
 	challenge.Validated = now.Format(time.RFC3339)
 	authz.Status = ACMEAuthorizationValid
@@ -4,6 +4,7 @@
 package pki
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
@@ -17,6 +18,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/nonce"
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
@@ -40,9 +42,8 @@ const (
 )
 
 type acmeState struct {
-	nextExpiry *atomic.Int64
-	nonces     *sync.Map // map[string]time.Time
-	validator  *ACMEChallengeEngine
+	nonces    nonce.Service
+	validator *ACMEChallengeEngine
 
 	configDirty *atomic.Bool
 	_config     sync.RWMutex
@@ -56,8 +57,7 @@ type acmeThumbprint struct {
 
 func NewACMEState() *acmeState {
 	state := &acmeState{
-		nextExpiry:  new(atomic.Int64),
-		nonces:      new(sync.Map),
+		nonces:      nonce.NewMemoryService(nonceExpiry),
 		validator:   NewACMEChallengeEngine(),
 		configDirty: new(atomic.Bool),
 	}
@@ -124,10 +124,6 @@ func (a *acmeState) getConfigWithUpdate(sc *storageContext) (*acmeConfigEntry, e
 	return &configCopy, nil
 }
 
-func generateNonce() (string, error) {
-	return generateRandomBase64(21)
-}
-
 func generateRandomBase64(srcBytes int) (string, error) {
 	data := make([]byte, 21)
 	if _, err := io.ReadFull(rand.Reader, data); err != nil {
@@ -137,67 +133,19 @@ func generateRandomBase64(srcBytes int) (string, error) {
 	return base64.RawURLEncoding.EncodeToString(data), nil
 }
 
+// GetNonce issues a nonce for the Replay-Nonce header. ACME nonces are kept
+// in memory, as one is issued with every response.
 func (a *acmeState) GetNonce() (string, time.Time, error) {
-	now := time.Now()
-	nonce, err := generateNonce()
-	if err != nil {
-		return "", now, err
-	}
-
-	then := now.Add(nonceExpiry)
-	a.nonces.Store(nonce, then)
-
-	nextExpiry := a.nextExpiry.Load()
-	next := time.Unix(nextExpiry, 0)
-	if now.After(next) || then.Before(next) {
-		a.nextExpiry.Store(then.Unix())
-	}
-
-	return nonce, then, nil
+	return a.nonces.Get(context.Background(), nil)
 }
 
 func (a *acmeState) RedeemNonce(nonce string) bool {
-	rawTimeout, present := a.nonces.LoadAndDelete(nonce)
-	if !present {
-		return false
-	}
-
-	timeout := rawTimeout.(time.Time)
-	if time.Now().After(timeout) {
-		return false
-	}
-
-	return true
-}
-
-func (a *acmeState) DoTidyNonces() {
-	now := time.Now()
-	expiry := a.nextExpiry.Load()
-	then := time.Unix(expiry, 0)
-
-	if expiry == 0 || now.After(then) {
-		a.TidyNonces()
-	}
+	ok, _ := a.nonces.Redeem(context.Background(), nil, nonce)
+	return ok
 }
 
 func (a *acmeState) TidyNonces() {
-	now := time.Now()
-	nextRun := now.Add(nonceExpiry)
-
-	a.nonces.Range(func(key, value any) bool {
-		timeout := value.(time.Time)
-		if now.After(timeout) {
-			a.nonces.Delete(key)
-		}
-
-		if timeout.Before(nextRun) {
-			nextRun = timeout
-		}
-
-		return false /* don't quit looping */
-	})
-
-	a.nextExpiry.Store(nextRun.Unix())
+	_ = a.nonces.Tidy(context.Background(), nil)
 }
 
 type ACMEAccountStatus string
@@ -590,6 +538,25 @@ func (a *acmeState) ListOrderIds(ac *acmeContext, accountId string) ([]string, e
 	return orderIds, nil
 }
 
+func (a *acmeState) ListAuthorizationIds(ac *acmeContext, accountId string) ([]string, error) {
+	accountAuthorizationPrefixPath := acmeAccountPrefix + accountId + "/authorizations/"
+
+	rawAuthorizationIds, err := ac.sc.Storage.List(ac.sc.Context, accountAuthorizationPrefixPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing authorization ids for account %s: %w", accountId, err)
+	}
+
+	authorizationIds := []string{}
+	for _, authorization := range rawAuthorizationIds {
+		if strings.HasSuffix(authorization, "/") {
+			// skip any folders we might have for some reason
+			continue
+		}
+		authorizationIds = append(authorizationIds, authorization)
+	}
+	return authorizationIds, nil
+}
+
 type acmeCertEntry struct {
 	Serial  string `json:"-"`
 	Account string `json:"-"`
@@ -26,6 +26,7 @@ type acmeContext struct {
 	// if something needs to remain locked into a directory path structure.
 	acmeDirectory string
 	eabPolicy     EabPolicy
+	config        *acmeConfigEntry
 }
 
 type (
@@ -93,6 +94,7 @@ func (b *backend) acmeWrapper(op acmeOperation) framework.OperationFunc {
 			issuer:        issuer,
 			acmeDirectory: acmeDirectory,
 			eabPolicy:     eabPolicy,
+			config:        config,
 		}
 
 		return op(acmeCtx, r, data)
@@ -277,6 +279,19 @@ func getAcmeDirectory(data *framework.FieldData) string {
 	return fmt.Sprintf("issuer-%s::role-%s", requestedIssuer, requestedRole)
 }
 
+// getAcmeDirectoryPath returns the path of the ACME directory of the request
+// relative to the mount, such as acme or issuer/:issuer_ref/roles/:role/acme.
+func getAcmeDirectoryPath(data *framework.FieldData) string {
+	directory := "acme"
+	if requestedRole := getRequestedAcmeRoleFromPath(data); requestedRole != "" {
+		directory = "roles/" + requestedRole + "/" + directory
+	}
+	if requestedIssuer := getRequestedAcmeIssuerFromPath(data); requestedIssuer != "" {
+		directory = "issuer/" + requestedIssuer + "/" + directory
+	}
+	return directory
+}
+
 func getAcmeRoleAndIssuer(sc *storageContext, data *framework.FieldData, config *acmeConfigEntry) (*roleEntry, *issuerEntry, error) {
 	requestedIssuer := getRequestedAcmeIssuerFromPath(data)
 	requestedRole := getRequestedAcmeRoleFromPath(data)
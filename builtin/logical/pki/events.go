@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pki
+
+import (
+	"context"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// sendEvent sends an event about the mount to the event bus. Events are best
+// effort, so failures are logged rather than returned.
+func (b *backend) sendEvent(ctx context.Context, eventType string, metadataPairs ...string) {
+	ev, err := logical.NewEvent()
+	if err != nil {
+		b.Logger().Warn("Error creating event", "error", err)
+		return
+	}
+
+	metadata := make(map[string]interface{}, len(metadataPairs)/2)
+	for i := 0; i+1 < len(metadataPairs); i += 2 {
+		metadata[metadataPairs[i]] = metadataPairs[i+1]
+	}
+	ev.Metadata, err = structpb.NewStruct(metadata)
+	if err != nil {
+		b.Logger().Warn("Error encoding event metadata", "error", err)
+		return
+	}
+
+	err = b.SendEvent(ctx, logical.EventType("pki/"+eventType), ev)
+	// ignore events are disabled error
+	if err == framework.ErrNoEvents {
+		return
+	} else if err != nil {
+		b.Logger().Warn("Error sending event", "error", err)
+	}
+}
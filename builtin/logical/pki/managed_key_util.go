@@ -8,38 +8,164 @@ package pki
 import (
 	"context"
 	"crypto"
+	"encoding/pem"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/hashicorp/vault/sdk/helper/certutil"
+	"github.com/hashicorp/vault/sdk/helper/errutil"
+	"github.com/hashicorp/vault/sdk/logical"
 )
 
-var errEntOnly = errors.New("managed keys are supported within enterprise edition only")
+var errManagedKeysUnsupported = errors.New("managed keys are not supported by the system view of this mount")
+
+func managedKeySystemView(b *backend) (logical.ManagedKeySystemView, error) {
+	view, ok := b.System().(logical.ManagedKeySystemView)
+	if !ok {
+		return nil, errManagedKeysUnsupported
+	}
+	return view, nil
+}
+
+// withManagedSigningKey calls f with the managed key, fetched by name or
+// UUID depending on the key id.
+func withManagedSigningKey(ctx context.Context, b *backend, keyId managedKeyId, f logical.ManagedSigningKeyConsumer) error {
+	view, err := managedKeySystemView(b)
+	if err != nil {
+		return err
+	}
+
+	switch id := keyId.(type) {
+	case NameKey:
+		return view.WithManagedSigningKeyByName(ctx, string(id), b.backendUUID, f)
+	case UUIDKey:
+		return view.WithManagedSigningKeyByUUID(ctx, string(id), b.backendUUID, f)
+	default:
+		return fmt.Errorf("unknown type of managed key id: %T", keyId)
+	}
+}
+
+// managedKeySigner is a crypto.Signer backed by a managed key. The issuers
+// bundles are kept around longer than a request, so the key is looked up
+// again on every signature rather than bound to a request context.
+type managedKeySigner struct {
+	b         *backend
+	uuid      UUIDKey
+	publicKey crypto.PublicKey
+}
+
+func (s *managedKeySigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+func (s *managedKeySigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var signature []byte
+	err := withManagedSigningKey(context.Background(), s.b, s.uuid, func(ctx context.Context, key logical.ManagedSigningKey) error {
+		var err error
+		signature, err = key.Sign(ctx, digest, rand, opts)
+		return err
+	})
+	return signature, err
+}
+
+// managedKeyGenerator returns a key generator handing the managed key to the
+// certificate creation instead of generating a key.
+func managedKeyGenerator(ctx context.Context, b *backend, keyId managedKeyId) certutil.KeyGenerator {
+	return func(_ string, _ int, container certutil.ParsedPrivateKeyContainer, _ io.Reader) error {
+		info, err := getManagedKeyInfo(ctx, b, keyId)
+		if err != nil {
+			return err
+		}
+		signer := &managedKeySigner{b: b, uuid: info.uuid, publicKey: info.publicKey}
+		container.SetParsedPrivateKey(signer, certutil.ManagedPrivateKey, []byte(info.uuid))
+		return nil
+	}
+}
 
 func generateManagedKeyCABundle(ctx context.Context, b *backend, keyId managedKeyId, data *certutil.CreationBundle, randomSource io.Reader) (bundle *certutil.ParsedCertBundle, err error) {
-	return nil, errEntOnly
+	return certutil.CreateCertificateWithKeyGenerator(data, randomSource, managedKeyGenerator(ctx, b, keyId))
 }
 
 func generateManagedKeyCSRBundle(ctx context.Context, b *backend, keyId managedKeyId, data *certutil.CreationBundle, addBasicConstraints bool, randomSource io.Reader) (bundle *certutil.ParsedCSRBundle, err error) {
-	return nil, errEntOnly
+	return certutil.CreateCSRWithKeyGenerator(data, addBasicConstraints, randomSource, managedKeyGenerator(ctx, b, keyId))
 }
 
 func getManagedKeyPublicKey(ctx context.Context, b *backend, keyId managedKeyId) (crypto.PublicKey, error) {
-	return nil, errEntOnly
+	info, err := getManagedKeyInfo(ctx, b, keyId)
+	if err != nil {
+		return nil, err
+	}
+	return info.publicKey, nil
 }
 
 func parseManagedKeyCABundle(ctx context.Context, b *backend, bundle *certutil.CertBundle) (*certutil.ParsedCertBundle, error) {
-	return nil, errEntOnly
+	keyId, err := extractManagedKeyId([]byte(bundle.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	info, err := getManagedKeyInfo(ctx, b, keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key of the bundle only holds the UUID of the managed key
+	return bundle.ToParsedCertBundleWithExtractor(func(_ *certutil.CertBundle, parsedBundle *certutil.ParsedCertBundle) error {
+		parsedBundle.PrivateKeyType = certutil.ManagedPrivateKey
+		parsedBundle.PrivateKeyBytes = []byte(info.uuid)
+		parsedBundle.PrivateKey = &managedKeySigner{b: b, uuid: info.uuid, publicKey: info.publicKey}
+		return nil
+	})
 }
 
 func extractManagedKeyId(privateKeyBytes []byte) (UUIDKey, error) {
-	return "", errEntOnly
+	block, _ := pem.Decode(privateKeyBytes)
+	if block == nil {
+		return "", errutil.InternalError{Err: "no PEM data found in managed key"}
+	}
+	if len(block.Bytes) == 0 {
+		return "", errutil.InternalError{Err: "no managed key UUID found in managed key"}
+	}
+	return UUIDKey(block.Bytes), nil
 }
 
 func createKmsKeyBundle(ctx context.Context, b *backend, keyId managedKeyId) (certutil.KeyBundle, certutil.PrivateKeyType, error) {
-	return certutil.KeyBundle{}, certutil.UnknownPrivateKey, errEntOnly
+	info, err := getManagedKeyInfo(ctx, b, keyId)
+	if err != nil {
+		return certutil.KeyBundle{}, certutil.UnknownPrivateKey, err
+	}
+
+	return certutil.KeyBundle{
+		PrivateKeyType:  certutil.ManagedPrivateKey,
+		PrivateKeyBytes: []byte(info.uuid),
+	}, info.keyType, nil
 }
 
 func getManagedKeyInfo(ctx context.Context, b *backend, keyId managedKeyId) (*managedKeyInfo, error) {
-	return nil, errEntOnly
+	var info *managedKeyInfo
+	err := withManagedSigningKey(ctx, b, keyId, func(ctx context.Context, key logical.ManagedSigningKey) error {
+		if !key.AllowsAll([]logical.KeyUsage{logical.KeyUsageSign}) {
+			return fmt.Errorf("managed key %q does not allow signing", key.Name())
+		}
+		publicKey, err := key.GetPublicKey(ctx)
+		if err != nil {
+			return err
+		}
+		keyType := certutil.GetPrivateKeyTypeFromSigner(&managedKeySigner{publicKey: publicKey})
+		if keyType == certutil.UnknownPrivateKey {
+			return fmt.Errorf("unsupported public key type %T of managed key %q", publicKey, key.Name())
+		}
+
+		info = &managedKeyInfo{
+			publicKey: publicKey,
+			keyType:   keyType,
+			name:      NameKey(key.Name()),
+			uuid:      UUIDKey(key.UUID()),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to use managed key %v: %w", keyId, err)
+	}
+	return info, nil
 }
@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !enterprise
+
+package pki
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+// testManagedKey is a managed signing key held in memory.
+type testManagedKey struct {
+	name string
+	uuid string
+	key  *ecdsa.PrivateKey
+}
+
+func (k *testManagedKey) Name() string                          { return k.name }
+func (k *testManagedKey) UUID() string                          { return k.uuid }
+func (k *testManagedKey) Present(context.Context) (bool, error) { return true, nil }
+func (k *testManagedKey) AllowsAll([]logical.KeyUsage) bool     { return true }
+
+func (k *testManagedKey) GetPublicKey(context.Context) (crypto.PublicKey, error) {
+	return k.key.Public(), nil
+}
+
+func (k *testManagedKey) Sign(_ context.Context, value []byte, randomSource io.Reader, opts crypto.SignerOpts) ([]byte, error) {
+	return k.key.Sign(randomSource, value, opts)
+}
+
+func (k *testManagedKey) Verify(_ context.Context, signature, value []byte, _ crypto.SignerOpts) (bool, error) {
+	return ecdsa.VerifyASN1(&k.key.PublicKey, value, signature), nil
+}
+
+func (k *testManagedKey) GetSigner(context.Context) (crypto.Signer, error) {
+	return k.key, nil
+}
+
+// testManagedKeySystemView hands a single managed key to the backend.
+type testManagedKeySystemView struct {
+	logical.StaticSystemView
+	key *testManagedKey
+}
+
+func (v *testManagedKeySystemView) signingKey(name, uuid string) (logical.ManagedSigningKey, error) {
+	if name == v.key.name || uuid == v.key.uuid {
+		return v.key, nil
+	}
+	return nil, errors.New("managed key not found")
+}
+
+func (v *testManagedKeySystemView) WithManagedKeyByName(ctx context.Context, keyName, _ string, f logical.ManagedKeyConsumer) error {
+	key, err := v.signingKey(keyName, "")
+	if err != nil {
+		return err
+	}
+	return f(ctx, key)
+}
+
+func (v *testManagedKeySystemView) WithManagedKeyByUUID(ctx context.Context, keyUUID, _ string, f logical.ManagedKeyConsumer) error {
+	key, err := v.signingKey("", keyUUID)
+	if err != nil {
+		return err
+	}
+	return f(ctx, key)
+}
+
+func (v *testManagedKeySystemView) WithManagedSigningKeyByName(ctx context.Context, keyName, _ string, f logical.ManagedSigningKeyConsumer) error {
+	key, err := v.signingKey(keyName, "")
+	if err != nil {
+		return err
+	}
+	return f(ctx, key)
+}
+
+func (v *testManagedKeySystemView) WithManagedSigningKeyByUUID(ctx context.Context, keyUUID, _ string, f logical.ManagedSigningKeyConsumer) error {
+	key, err := v.signingKey("", keyUUID)
+	if err != nil {
+		return err
+	}
+	return f(ctx, key)
+}
+
+func (v *testManagedKeySystemView) WithManagedEncryptingKeyByName(context.Context, string, string, logical.ManagedEncryptingKeyConsumer) error {
+	return errors.New("unsupported")
+}
+
+func (v *testManagedKeySystemView) WithManagedEncryptingKeyByUUID(context.Context, string, string, logical.ManagedEncryptingKeyConsumer) error {
+	return errors.New("unsupported")
+}
+
+func (v *testManagedKeySystemView) WithManagedMACKeyByName(context.Context, string, string, logical.ManagedMACKeyConsumer) error {
+	return errors.New("unsupported")
+}
+
+func (v *testManagedKeySystemView) WithManagedMACKeyByUUID(context.Context, string, string, logical.ManagedMACKeyConsumer) error {
+	return errors.New("unsupported")
+}
+
+func TestManagedKeyIssuer(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	managedKey := &testManagedKey{name: "test-key", uuid: "c0d3b84b-7f1e-4c67-9f0b-95d2b3f1a4a1", key: key}
+
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	config.System = &testManagedKeySystemView{
+		StaticSystemView: *config.System.(*logical.StaticSystemView),
+		key:              managedKey,
+	}
+	b := Backend(config)
+	require.NoError(t, b.Setup(context.Background(), config))
+	b.pkiStorageVersion.Store(1)
+	s := config.StorageView
+
+	// Mounts without managed keys support can't use them
+	noKeysBackend, noKeysStorage := CreateBackendWithStorage(t)
+	_, err = CBWrite(noKeysBackend, noKeysStorage, "root/generate/kms", map[string]interface{}{
+		"common_name":      "root.com",
+		"managed_key_name": "test-key",
+	})
+	require.ErrorContains(t, err, errManagedKeysUnsupported.Error())
+
+	resp, err := CBWrite(b, s, "root/generate/kms", map[string]interface{}{
+		"common_name":      "root.com",
+		"managed_key_name": "test-key",
+		"ttl":              "48h",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "failed generating a managed key root")
+	root := parseCert(t, resp.Data["certificate"].(string))
+	require.Equal(t, key.Public(), root.PublicKey)
+	require.NoError(t, root.CheckSignatureFrom(root))
+
+	resp, err = CBRead(b, s, "key/"+string(resp.Data["key_id"].(keyID)))
+	requireSuccessNonNilResponse(t, resp, err, "failed reading the managed key")
+	require.Equal(t, "test-key", resp.Data["managed_key_name"])
+	require.Equal(t, managedKey.uuid, resp.Data["managed_key_id"])
+
+	// Issuing loads the issuer back from storage
+	resp, err = CBWrite(b, s, "roles/test", map[string]interface{}{
+		"allow_any_name": true,
+	})
+	requireSuccessNonNilResponse(t, resp, err, "failed creating a role")
+	resp, err = CBWrite(b, s, "issue/test", map[string]interface{}{
+		"common_name": "leaf.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "failed issuing a leaf")
+	leaf := parseCert(t, resp.Data["certificate"].(string))
+	require.NoError(t, leaf.CheckSignatureFrom(root))
+
+	// CRLs are signed by the managed key too
+	resp, err = CBRead(b, s, "crl/rotate")
+	requireSuccessNonNilResponse(t, resp, err, "failed rotating the CRL")
+	resp, err = CBRead(b, s, "cert/crl")
+	requireSuccessNonNilResponse(t, resp, err, "failed reading the CRL")
+	ToCRL(t, resp.Data["certificate"].(string), root)
+
+	resp, err = CBWrite(b, s, "intermediate/generate/kms", map[string]interface{}{
+		"common_name":      "intermediate.com",
+		"managed_key_name": "test-key",
+	})
+	requireSuccessNonNilResponse(t, resp, err, "failed generating a managed key CSR")
+	block, _ := pem.Decode([]byte(resp.Data["csr"].(string)))
+	require.NotNil(t, block)
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	require.NoError(t, err)
+	require.Equal(t, key.Public(), csr.PublicKey)
+	require.NoError(t, csr.CheckSignature())
+}
@@ -428,6 +428,11 @@ func (b *backend) tidyAcmeAccountByThumbprint(as *acmeState, ac *acmeContext, ke
 		}
 	}
 
+	// Tidy the authorizations no order relies on anymore
+	if err := b.acmeTidyAuthorizations(ac, thumbprint.Kid); err != nil {
+		return err
+	}
+
 	now := time.Now()
 	if allOrdersTidied &&
 		now.After(account.AccountCreatedDate.Add(accountTidyBuffer)) &&
@@ -5,6 +5,8 @@ package pki
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -14,6 +16,10 @@ func pathAcmeAuthorization(b *backend) []*framework.Path {
 	return buildAcmeFrameworkPaths(b, patternAcmeAuthorization, "/authorization/"+framework.MatchAllRegex("auth_id"))
 }
 
+func pathAcmeNewAuthorization(b *backend) []*framework.Path {
+	return buildAcmeFrameworkPaths(b, patternAcmeNewAuthorization, "/new-authz")
+}
+
 func addFieldsForACMEAuthorization(fields map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
 	fields["auth_id"] = &framework.FieldSchema{
 		Type:        framework.TypeString,
@@ -46,6 +52,27 @@ func patternAcmeAuthorization(b *backend, pattern string) *framework.Path {
 	}
 }
 
+func patternAcmeNewAuthorization(b *backend, pattern string) *framework.Path {
+	fields := map[string]*framework.FieldSchema{}
+	addFieldsForACMEPath(fields, pattern)
+	addFieldsForACMERequest(fields)
+
+	return &framework.Path{
+		Pattern: pattern,
+		Fields:  fields,
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback:                    b.acmeAccountRequiredWrapper(b.acmeNewAuthorizationHandler),
+				ForwardPerformanceSecondary: false,
+				ForwardPerformanceStandby:   true,
+			},
+		},
+
+		HelpSynopsis:    pathAcmeHelpSync,
+		HelpDescription: pathAcmeHelpDesc,
+	}
+}
+
 func (b *backend) acmeAuthorizationHandler(acmeCtx *acmeContext, r *logical.Request, fields *framework.FieldData, userCtx *jwsCtx, data map[string]interface{}, _ *acmeAccount) (*logical.Response, error) {
 	authId := fields.Get("auth_id").(string)
 	authz, err := b.acmeState.LoadAuthorization(acmeCtx, userCtx, authId)
@@ -98,3 +125,64 @@ func (b *backend) acmeAuthorizationDeactivateHandler(acmeCtx *acmeContext, r *lo
 		Data: authz.NetworkMarshal(acmeCtx),
 	}, nil
 }
+
+func (b *backend) acmeNewAuthorizationHandler(ac *acmeContext, _ *logical.Request, _ *framework.FieldData, _ *jwsCtx, data map[string]interface{}, account *acmeAccount) (*logical.Response, error) {
+	if !ac.config.AllowPreAuthorization {
+		return nil, fmt.Errorf("%w: pre-authorization is disabled", ErrUnauthorized)
+	}
+
+	rawIdentifier, present := data["identifier"]
+	if !present {
+		return nil, fmt.Errorf("missing required identifier argument: %w", ErrMalformed)
+	}
+
+	identifier, err := parseAcmeIdentifier(rawIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// Per RFC 8555 Section 7.4.1. Pre-authorization:
+	//
+	// > Note that because the identifier in a pre-authorization request is
+	// > the exact identifier to be included in the authorization object,
+	// > pre-authorization cannot be used to authorize issuance of
+	// > certificates containing wildcard domain names.
+	if identifier.IsWildcard {
+		return nil, fmt.Errorf("%w: pre-authorization can not be used for wildcard identifier %s", ErrRejectedIdentifier, identifier.OriginalValue)
+	}
+
+	err = b.validateIdentifiersAgainstRole(ac.role, []*ACMEIdentifier{identifier})
+	if err != nil {
+		return nil, err
+	}
+
+	authz, err := generateAuthorization(account, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("error generating authorization: %w", err)
+	}
+
+	// Unlike those of orders, which expire with their order, pre-authorizations
+	// stand on their own: bound how long their challenges may be completed.
+	authz.Expires = time.Now().Add(ac.config.AuthorizationLifetime).Format(time.RFC3339)
+
+	err = b.acmeState.SaveAuthorization(ac, authz)
+	if err != nil {
+		return nil, fmt.Errorf("failed storing authorization: %w", err)
+	}
+
+	// Per RFC 8555 Section 7.4.1. Pre-authorization:
+	//
+	// > If the server is willing to proceed, it builds a pending
+	// > authorization object from the inputs submitted by the client [...]
+	// > and returns a 201 (Created) response with the authorization URL in
+	// > the Location header field and the JSON authorization object in the
+	// > body.
+	resp := &logical.Response{
+		Data: authz.NetworkMarshal(ac),
+		Headers: map[string][]string{
+			"Location": {buildAuthorizationUrl(ac, authz.Id)},
+		},
+	}
+	resp.Data[logical.HTTPStatusCode] = http.StatusCreated
+	return resp, nil
+}
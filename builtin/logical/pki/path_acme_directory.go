@@ -45,17 +45,24 @@ func patternAcmeDirectory(b *backend, pattern string) *framework.Path {
 }
 
 func (b *backend) acmeDirectoryHandler(acmeCtx *acmeContext, r *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
-	rawBody, err := json.Marshal(map[string]interface{}{
+	directory := map[string]interface{}{
 		"newNonce":   acmeCtx.baseUrl.JoinPath("new-nonce").String(),
 		"newAccount": acmeCtx.baseUrl.JoinPath("new-account").String(),
 		"newOrder":   acmeCtx.baseUrl.JoinPath("new-order").String(),
 		"revokeCert": acmeCtx.baseUrl.JoinPath("revoke-cert").String(),
 		"keyChange":  acmeCtx.baseUrl.JoinPath("key-change").String(),
-		// This is purposefully missing newAuthz as we don't support pre-authorization
 		"meta": map[string]interface{}{
 			"externalAccountRequired": acmeCtx.eabPolicy.IsExternalAccountRequired(),
 		},
-	})
+	}
+
+	// Per RFC 8555 Section 7.1.1. Directory, servers not supporting
+	// pre-authorization omit newAuthz.
+	if acmeCtx.config.AllowPreAuthorization {
+		directory["newAuthz"] = acmeCtx.baseUrl.JoinPath("new-authz").String()
+	}
+
+	rawBody, err := json.Marshal(directory)
 	if err != nil {
 		return nil, fmt.Errorf("failed encoding response: %w", err)
 	}
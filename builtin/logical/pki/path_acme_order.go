@@ -4,6 +4,7 @@
 package pki
 
 import (
+	"bytes"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
@@ -42,6 +43,10 @@ func pathAcmeFetchOrderCert(b *backend) []*framework.Path {
 	return buildAcmeFrameworkPaths(b, patternAcmeFetchOrderCert, "/order/"+uuidNameRegex("order_id")+"/cert")
 }
 
+func pathAcmeFetchOrderCertAlternate(b *backend) []*framework.Path {
+	return buildAcmeFrameworkPaths(b, patternAcmeFetchOrderCertAlternate, "/order/"+uuidNameRegex("order_id")+"/cert/"+uuidNameRegex("chain_issuer_id"))
+}
+
 func patternAcmeNewOrder(b *backend, pattern string) *framework.Path {
 	fields := map[string]*framework.FieldSchema{}
 	addFieldsForACMEPath(fields, pattern)
@@ -150,6 +155,33 @@ func patternAcmeFetchOrderCert(b *backend, pattern string) *framework.Path {
 	}
 }
 
+func patternAcmeFetchOrderCertAlternate(b *backend, pattern string) *framework.Path {
+	fields := map[string]*framework.FieldSchema{}
+	addFieldsForACMEPath(fields, pattern)
+	addFieldsForACMERequest(fields)
+	addFieldsForACMEOrder(fields)
+	fields["chain_issuer_id"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: `The issuer whose alternate certificate chain to fetch`,
+		Required:    true,
+	}
+
+	return &framework.Path{
+		Pattern: pattern,
+		Fields:  fields,
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback:                    b.acmeAccountRequiredWrapper(b.acmeFetchCertOrderHandler),
+				ForwardPerformanceSecondary: false,
+				ForwardPerformanceStandby:   true,
+			},
+		},
+
+		HelpSynopsis:    pathAcmeHelpSync,
+		HelpDescription: pathAcmeHelpDesc,
+	}
+}
+
 func addFieldsForACMEOrder(fields map[string]*framework.FieldSchema) {
 	fields["order_id"] = &framework.FieldSchema{
 		Type:        framework.TypeString,
@@ -192,15 +224,45 @@ func (b *backend) acmeFetchCertOrderHandler(ac *acmeContext, _ *logical.Request,
 		return nil, fmt.Errorf("failed loading certificate issuer %s from storage: %w", order.IssuerId, err)
 	}
 
+	// Per RFC 8555 Section 7.4.2. Downloading the Certificate:
+	//
+	// > The server MAY provide one or more link relation header fields
+	// > [RFC8288] with relation "alternate".  Each such field SHOULD express
+	// > an alternative certificate chain starting with the same end-entity
+	// > certificate.
+	//
+	// Those are the chains of the issuers sharing the key and subject of the
+	// signing issuer, such as the reissued or cross-signed intermediates of a
+	// root rotation.
+	chainIssuers, err := b.getAcmeChainIssuers(ac, fields, issuer, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	chainIssuer := chainIssuers[0]
+	if chainIssuerIdRaw, ok := fields.GetOk("chain_issuer_id"); ok {
+		chainIssuer = nil
+		for _, candidate := range chainIssuers {
+			if candidate.ID == issuerID(chainIssuerIdRaw.(string)) {
+				chainIssuer = candidate
+				break
+			}
+		}
+
+		if chainIssuer == nil {
+			return nil, fmt.Errorf("%w: no alternate chain %s for the certificate", ErrMalformed, chainIssuerIdRaw)
+		}
+	}
+
 	allPems, err := func() ([]byte, error) {
 		leafPEM := pem.EncodeToMemory(&pem.Block{
 			Type:  "CERTIFICATE",
 			Bytes: cert.Raw,
 		})
 
-		chains := []byte(issuer.Certificate)
-		for _, chainVal := range issuer.CAChain {
-			if chainVal == issuer.Certificate {
+		chains := []byte(chainIssuer.Certificate)
+		for _, chainVal := range chainIssuer.CAChain {
+			if chainVal == chainIssuer.Certificate {
 				continue
 			}
 			chains = append(chains, []byte(chainVal)...)
@@ -212,13 +274,106 @@ func (b *backend) acmeFetchCertOrderHandler(ac *acmeContext, _ *logical.Request,
 		return nil, fmt.Errorf("failed encoding certificate ca chain: %w", err)
 	}
 
-	return &logical.Response{
+	var links []string
+	for index, candidate := range chainIssuers {
+		if candidate.ID == chainIssuer.ID {
+			continue
+		}
+
+		url := buildOrderUrl(ac, orderId) + "/cert"
+		if index > 0 {
+			url += "/" + candidate.ID.String()
+		}
+		links = append(links, fmt.Sprintf("<%s>;rel=\"alternate\"", url))
+	}
+
+	resp := &logical.Response{
 		Data: map[string]interface{}{
 			logical.HTTPContentType: "application/pem-certificate-chain",
 			logical.HTTPStatusCode:  http.StatusOK,
 			logical.HTTPRawBody:     allPems,
 		},
-	}, nil
+	}
+	if len(links) > 0 {
+		resp.Headers = map[string][]string{
+			"Link": append(links, genAcmeLinkHeader(ac)...),
+		}
+	}
+
+	return resp, nil
+}
+
+// getAcmeChainIssuers returns the issuers whose chain the certificate signed
+// by the issuer can be served with: the issuers sharing its key and subject.
+// The issuer serving the default chain of the ACME directory comes first,
+// per default_chain_issuers, falling back to the signing issuer.
+func (b *backend) getAcmeChainIssuers(ac *acmeContext, fields *framework.FieldData, signer *issuerEntry, cert *x509.Certificate) ([]*issuerEntry, error) {
+	signerCert, err := signer.GetCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing certificate of issuer %s: %w", signer.ID, err)
+	}
+
+	issuerIds, err := ac.sc.listIssuers()
+	if err != nil {
+		return nil, fmt.Errorf("failed listing issuers: %w", err)
+	}
+	sort.Slice(issuerIds, func(i, j int) bool {
+		return issuerIds[i] < issuerIds[j]
+	})
+
+	defaultId := signer.ID
+	if preferred := getAcmeDefaultChainIssuer(ac, fields); preferred != "" {
+		preferredId, err := ac.sc.resolveIssuerReference(preferred)
+		if err != nil {
+			b.Logger().Warn("failed to resolve the default chain issuer of ACME directory, serving the chain of the signing issuer", "directory", ac.acmeDirectory, "issuer", preferred, "error", err)
+		} else {
+			defaultId = preferredId
+		}
+	}
+
+	chainIssuers := []*issuerEntry{signer}
+	for _, id := range issuerIds {
+		if id == signer.ID {
+			continue
+		}
+
+		candidate, err := ac.sc.fetchIssuerById(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading issuer %s from storage: %w", id, err)
+		}
+		if len(signer.KeyID) == 0 || candidate.KeyID != signer.KeyID {
+			continue
+		}
+
+		candidateCert, err := candidate.GetCertificate()
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing certificate of issuer %s: %w", id, err)
+		}
+		if !bytes.Equal(candidateCert.RawSubject, signerCert.RawSubject) || cert.CheckSignatureFrom(candidateCert) != nil {
+			continue
+		}
+
+		if id == defaultId {
+			chainIssuers = append([]*issuerEntry{candidate}, chainIssuers...)
+		} else {
+			chainIssuers = append(chainIssuers, candidate)
+		}
+	}
+
+	return chainIssuers, nil
+}
+
+// getAcmeDefaultChainIssuer returns the reference to the issuer serving the
+// default chain of the ACME directory of the request, if configured.
+func getAcmeDefaultChainIssuer(ac *acmeContext, fields *framework.FieldData) string {
+	if len(ac.config.DefaultChainIssuers) == 0 {
+		return ""
+	}
+
+	if name, ok := ac.config.DefaultChainIssuers[getAcmeDirectoryPath(fields)]; ok {
+		return name
+	}
+	return ac.config.DefaultChainIssuers["*"]
 }
 
 func (b *backend) acmeFinalizeOrderHandler(ac *acmeContext, _ *logical.Request, fields *framework.FieldData, uc *jwsCtx, data map[string]interface{}, account *acmeAccount) (*logical.Response, error) {
@@ -298,13 +453,16 @@ func requiredAuthorizationsCompleted(b *backend, ac *acmeContext, uc *jwsCtx, or
 		return false
 	}
 
+	now := time.Now()
 	for _, authId := range order.AuthorizationIds {
 		authorization, err := b.acmeState.LoadAuthorization(ac, uc, authId)
 		if err != nil {
 			return false
 		}
 
-		if authorization.Status != ACMEAuthorizationValid {
+		// Authorizations may be shared with other orders, and thus expire
+		// before the order does.
+		if !authorization.IsValidAt(now) {
 			return false
 		}
 	}
@@ -603,7 +761,7 @@ func (b *backend) acmeListOrdersHandler(ac *acmeContext, _ *logical.Request, _ *
 	return resp, nil
 }
 
-func (b *backend) acmeNewOrderHandler(ac *acmeContext, _ *logical.Request, _ *framework.FieldData, _ *jwsCtx, data map[string]interface{}, account *acmeAccount) (*logical.Response, error) {
+func (b *backend) acmeNewOrderHandler(ac *acmeContext, _ *logical.Request, _ *framework.FieldData, uc *jwsCtx, data map[string]interface{}, account *acmeAccount) (*logical.Response, error) {
 	identifiers, err := parseOrderIdentifiers(data)
 	if err != nil {
 		return nil, err
@@ -639,17 +797,40 @@ func (b *backend) acmeNewOrderHandler(ac *acmeContext, _ *logical.Request, _ *fr
 	// unexpired authorizations that the client has completed in the past
 	// for identifiers specified in the order.
 	//
-	// Since we are generating all authorizations here, there is no need to filter them out
-	// IF/WHEN we support pre-authz workflows and associate existing authorizations to this
-	// order they will need filtering.
-	var authorizations []*ACMEAuthorization
+	// Depending on the reuse policy, identifiers with a valid authorization, completed
+	// through pre-authorization or by a previous order, are satisfied by it; we generate
+	// new authorizations for the others.
+	now := time.Now()
+	orderExpires := now.Add(24 * time.Hour)
+
+	var reusableAuthorizations []*ACMEAuthorization
+	if ac.config.AuthorizationReusePolicy == ACMEAuthorizationReuseValid {
+		reusableAuthorizations, err = b.loadValidAuthorizations(ac, account.KeyId, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var authorizationIds []string
 	for _, identifier := range identifiers {
+		if authz := findAuthorizationForIdentifier(reusableAuthorizations, identifier); authz != nil {
+			// The order can't outlive the authorizations it relies on.
+			expires, err := authz.GetExpires()
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing expiry of authorization %s: %w", authz.Id, err)
+			}
+			if !expires.IsZero() && expires.Before(orderExpires) {
+				orderExpires = expires
+			}
+
+			authorizationIds = append(authorizationIds, authz.Id)
+			continue
+		}
+
 		authz, err := generateAuthorization(account, identifier)
 		if err != nil {
 			return nil, fmt.Errorf("error generating authorizations: %w", err)
 		}
-		authorizations = append(authorizations, authz)
 
 		err = b.acmeState.SaveAuthorization(ac, authz)
 		if err != nil {
@@ -663,11 +844,16 @@ func (b *backend) acmeNewOrderHandler(ac *acmeContext, _ *logical.Request, _ *fr
 		OrderId:          genUuid(),
 		AccountId:        account.KeyId,
 		Status:           ACMEOrderPending,
-		Expires:          time.Now().Add(24 * time.Hour), // TODO: Readjust this based on authz and/or config
+		Expires:          orderExpires,
 		Identifiers:      identifiers,
 		AuthorizationIds: authorizationIds,
 	}
 
+	// All the identifiers may have been satisfied by existing authorizations.
+	if requiredAuthorizationsCompleted(b, ac, uc, order) {
+		order.Status = ACMEOrderReady
+	}
+
 	err = b.acmeState.SaveOrder(ac, order)
 	if err != nil {
 		return nil, fmt.Errorf("failed storing order: %w", err)
@@ -683,6 +869,53 @@ func (b *backend) acmeNewOrderHandler(ac *acmeContext, _ *logical.Request, _ *fr
 	return resp, nil
 }
 
+// loadValidAuthorizations returns the authorizations of the account that are
+// valid at the given time.
+func (b *backend) loadValidAuthorizations(ac *acmeContext, accountId string, now time.Time) ([]*ACMEAuthorization, error) {
+	authIds, err := b.acmeState.ListAuthorizationIds(ac, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	var authorizations []*ACMEAuthorization
+	for _, authId := range authIds {
+		authz, err := loadAuthorizationAtPath(ac.sc, getAuthorizationPath(accountId, authId))
+		if err != nil {
+			return nil, err
+		}
+
+		if authz.IsValidAt(now) {
+			authorizations = append(authorizations, authz)
+		}
+	}
+
+	return authorizations, nil
+}
+
+// findAuthorizationForIdentifier returns the authorization matching the
+// identifier that expires last, or nil if none matches.
+func findAuthorizationForIdentifier(authorizations []*ACMEAuthorization, identifier *ACMEIdentifier) *ACMEAuthorization {
+	var found *ACMEAuthorization
+	var foundExpires time.Time
+	for _, authz := range authorizations {
+		if !authz.MatchesIdentifier(identifier) {
+			continue
+		}
+
+		expires, err := authz.GetExpires()
+		if err != nil {
+			continue
+		}
+
+		if found == nil || expires.After(foundExpires) {
+			found = authz
+			foundExpires = expires
+		}
+	}
+
+	return found
+}
+
 func validateAcmeProvidedOrderDates(notBefore time.Time, notAfter time.Time) error {
 	if !notBefore.IsZero() && !notAfter.IsZero() {
 		if notBefore.Equal(notAfter) {
@@ -823,92 +1056,101 @@ func parseOrderIdentifiers(data map[string]interface{}) ([]*ACMEIdentifier, erro
 
 	var identifiers []*ACMEIdentifier
 	for _, rawIdentifier := range listIdentifiers {
-		mapIdentifier, ok := rawIdentifier.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("invalid type (%T) for value in 'identifiers': %w", rawIdentifier, ErrMalformed)
+		identifier, err := parseAcmeIdentifier(rawIdentifier)
+		if err != nil {
+			return nil, err
 		}
 
-		typeVal, present := mapIdentifier["type"]
-		if !present {
-			return nil, fmt.Errorf("missing type argument for value in 'identifiers': %w", ErrMalformed)
-		}
-		typeStr, ok := typeVal.(string)
-		if !ok {
-			return nil, fmt.Errorf("invalid type for type argument (%T) for value in 'identifiers': %w", typeStr, ErrMalformed)
-		}
+		identifiers = append(identifiers, identifier)
+	}
 
-		valueVal, present := mapIdentifier["value"]
-		if !present {
-			return nil, fmt.Errorf("missing value argument for value in 'identifiers': %w", ErrMalformed)
-		}
-		valueStr, ok := valueVal.(string)
-		if !ok {
-			return nil, fmt.Errorf("invalid type for value argument (%T) for value in 'identifiers': %w", valueStr, ErrMalformed)
-		}
+	return identifiers, nil
+}
 
-		if len(valueStr) == 0 {
-			return nil, fmt.Errorf("value argument for value in 'identifiers' can not be blank: %w", ErrMalformed)
-		}
+func parseAcmeIdentifier(rawIdentifier interface{}) (*ACMEIdentifier, error) {
+	mapIdentifier, ok := rawIdentifier.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid type (%T) for value in 'identifiers': %w", rawIdentifier, ErrMalformed)
+	}
 
-		identifier := &ACMEIdentifier{
-			Value:         valueStr,
-			OriginalValue: valueStr,
-		}
+	typeVal, present := mapIdentifier["type"]
+	if !present {
+		return nil, fmt.Errorf("missing type argument for value in 'identifiers': %w", ErrMalformed)
+	}
+	typeStr, ok := typeVal.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for type argument (%T) for value in 'identifiers': %w", typeStr, ErrMalformed)
+	}
 
-		switch typeStr {
-		case string(ACMEIPIdentifier):
-			identifier.Type = ACMEIPIdentifier
-			ip := net.ParseIP(valueStr)
-			if ip == nil {
-				return nil, fmt.Errorf("value argument (%s) failed validation: failed parsing as IP: %w", valueStr, ErrMalformed)
-			}
-		case string(ACMEDNSIdentifier):
-			identifier.Type = ACMEDNSIdentifier
-
-			// This check modifies the identifier if it is a wildcard,
-			// removing the non-wildcard portion. We do this before the
-			// IP address checks, in case of an attempt to bypass the IP/DNS
-			// check via including a leading wildcard (e.g., *.127.0.0.1).
-			//
-			// Per RFC 8555 Section 7.1.4. Authorization Objects:
-			//
-			// > Wildcard domain names (with "*" as the first label) MUST NOT
-			// > be included in authorization objects.
-			if _, _, err := identifier.MaybeParseWildcard(); err != nil {
-				return nil, fmt.Errorf("value argument (%s) failed validation: invalid wildcard: %v: %w", valueStr, err, ErrMalformed)
-			}
+	valueVal, present := mapIdentifier["value"]
+	if !present {
+		return nil, fmt.Errorf("missing value argument for value in 'identifiers': %w", ErrMalformed)
+	}
+	valueStr, ok := valueVal.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for value argument (%T) for value in 'identifiers': %w", valueStr, ErrMalformed)
+	}
 
-			if isIP := net.ParseIP(identifier.Value); isIP != nil {
-				return nil, fmt.Errorf("refusing to accept argument (%s) as DNS type identifier: parsed OK as IP address: %w", valueStr, ErrMalformed)
-			}
+	if len(valueStr) == 0 {
+		return nil, fmt.Errorf("value argument for value in 'identifiers' can not be blank: %w", ErrMalformed)
+	}
 
-			// Use the reduced (identifier.Value) in case this was a wildcard
-			// domain.
-			p := idna.New(idna.ValidateForRegistration())
-			converted, err := p.ToASCII(identifier.Value)
-			if err != nil {
-				return nil, fmt.Errorf("value argument (%s) failed validation: %s: %w", valueStr, err.Error(), ErrMalformed)
-			}
+	identifier := &ACMEIdentifier{
+		Value:         valueStr,
+		OriginalValue: valueStr,
+	}
 
-			// Per RFC 8555 Section 7.1.4. Authorization Objects:
-			//
-			// > The domain name MUST be encoded in the form in which it
-			// > would appear in a certificate.  That is, it MUST be encoded
-			// > according to the rules in Section 7 of [RFC5280]. Servers
-			// > MUST verify any identifier values that begin with the
-			// > ASCII-Compatible Encoding prefix "xn--" as defined in
-			// > [RFC5890] are properly encoded.
-			if identifier.Value != converted {
-				return nil, fmt.Errorf("value argument (%s) failed IDNA round-tripping to ASCII: %w", valueStr, ErrMalformed)
-			}
-		default:
-			return nil, fmt.Errorf("unsupported identifier type %s: %w", typeStr, ErrUnsupportedIdentifier)
+	switch typeStr {
+	case string(ACMEIPIdentifier):
+		identifier.Type = ACMEIPIdentifier
+		ip := net.ParseIP(valueStr)
+		if ip == nil {
+			return nil, fmt.Errorf("value argument (%s) failed validation: failed parsing as IP: %w", valueStr, ErrMalformed)
+		}
+	case string(ACMEDNSIdentifier):
+		identifier.Type = ACMEDNSIdentifier
+
+		// This check modifies the identifier if it is a wildcard,
+		// removing the non-wildcard portion. We do this before the
+		// IP address checks, in case of an attempt to bypass the IP/DNS
+		// check via including a leading wildcard (e.g., *.127.0.0.1).
+		//
+		// Per RFC 8555 Section 7.1.4. Authorization Objects:
+		//
+		// > Wildcard domain names (with "*" as the first label) MUST NOT
+		// > be included in authorization objects.
+		if _, _, err := identifier.MaybeParseWildcard(); err != nil {
+			return nil, fmt.Errorf("value argument (%s) failed validation: invalid wildcard: %v: %w", valueStr, err, ErrMalformed)
 		}
 
-		identifiers = append(identifiers, identifier)
+		if isIP := net.ParseIP(identifier.Value); isIP != nil {
+			return nil, fmt.Errorf("refusing to accept argument (%s) as DNS type identifier: parsed OK as IP address: %w", valueStr, ErrMalformed)
+		}
+
+		// Use the reduced (identifier.Value) in case this was a wildcard
+		// domain.
+		p := idna.New(idna.ValidateForRegistration())
+		converted, err := p.ToASCII(identifier.Value)
+		if err != nil {
+			return nil, fmt.Errorf("value argument (%s) failed validation: %s: %w", valueStr, err.Error(), ErrMalformed)
+		}
+
+		// Per RFC 8555 Section 7.1.4. Authorization Objects:
+		//
+		// > The domain name MUST be encoded in the form in which it
+		// > would appear in a certificate.  That is, it MUST be encoded
+		// > according to the rules in Section 7 of [RFC5280]. Servers
+		// > MUST verify any identifier values that begin with the
+		// > ASCII-Compatible Encoding prefix "xn--" as defined in
+		// > [RFC5890] are properly encoded.
+		if identifier.Value != converted {
+			return nil, fmt.Errorf("value argument (%s) failed IDNA round-tripping to ASCII: %w", valueStr, ErrMalformed)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported identifier type %s: %w", typeStr, ErrUnsupportedIdentifier)
 	}
 
-	return identifiers, nil
+	return identifier, nil
 }
 
 func (b *backend) acmeTidyOrder(ac *acmeContext, accountId string, orderPath string, certTidyBuffer time.Duration) (bool, time.Time, error) {
@@ -958,9 +1200,17 @@ func (b *backend) acmeTidyOrder(ac *acmeContext, accountId string, orderPath str
 	// That includes any certificate acme/<account_id>/orders/orderPath/cert
 	// That also includes any related authorizations: acme/<account_id>/authorizations/<auth_id>
 
-	// First Authorizations
+	// First Authorizations; those still valid may be reused by other orders
+	// and are left for acmeTidyAuthorizations to clean up once expired.
+	now := time.Now()
 	for _, authorizationId := range order.AuthorizationIds {
-		err = ac.sc.Storage.Delete(ac.sc.Context, getAuthorizationPath(accountId, authorizationId))
+		authorizationPath := getAuthorizationPath(accountId, authorizationId)
+		authz, err := loadAuthorizationAtPath(ac.sc, authorizationPath)
+		if err == nil && authz.IsValidAt(now) {
+			continue
+		}
+
+		err = ac.sc.Storage.Delete(ac.sc.Context, authorizationPath)
 		if err != nil {
 			return false, orderExpiry, err
 		}
@@ -981,3 +1231,70 @@ func (b *backend) acmeTidyOrder(ac *acmeContext, accountId string, orderPath str
 
 	return true, orderExpiry, nil
 }
+
+// acmeTidyAuthorizations deletes the authorizations of the account that are
+// no longer referenced by any of its orders and are no longer valid, such as
+// expired pre-authorizations or authorizations outliving their orders.
+func (b *backend) acmeTidyAuthorizations(ac *acmeContext, accountId string) error {
+	orderIds, err := b.acmeState.ListOrderIds(ac, accountId)
+	if err != nil {
+		return err
+	}
+
+	referenced := make(map[string]struct{})
+	for _, orderId := range orderIds {
+		entry, err := ac.sc.Storage.Get(ac.sc.Context, getOrderPath(accountId, orderId))
+		if err != nil {
+			return fmt.Errorf("error loading order: %w", err)
+		}
+		if entry == nil {
+			continue
+		}
+
+		var order acmeOrder
+		if err := entry.DecodeJSON(&order); err != nil {
+			return fmt.Errorf("error decoding order: %w", err)
+		}
+
+		for _, authId := range order.AuthorizationIds {
+			referenced[authId] = struct{}{}
+		}
+	}
+
+	authIds, err := b.acmeState.ListAuthorizationIds(ac, accountId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, authId := range authIds {
+		if _, ok := referenced[authId]; ok {
+			continue
+		}
+
+		authorizationPath := getAuthorizationPath(accountId, authId)
+		authz, err := loadAuthorizationAtPath(ac.sc, authorizationPath)
+		if err != nil {
+			return err
+		}
+
+		// Pending pre-authorizations carry an expiry too, until which their
+		// challenges may still be completed.
+		expires, err := authz.GetExpires()
+		if err != nil {
+			return fmt.Errorf("failed parsing expiry of authorization %s: %w", authId, err)
+		}
+		if authz.Status == ACMEAuthorizationPending && (expires.IsZero() || now.Before(expires)) {
+			continue
+		}
+		if authz.IsValidAt(now) {
+			continue
+		}
+
+		if err := ac.sc.Storage.Delete(ac.sc.Context, authorizationPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -511,6 +511,238 @@ func TestAcmeAccountsCrossingDirectoryPath(t *testing.T) {
 	// swallows the error we are sending back to a no account error
 }
 
+// TestAcmePreAuthorization verifies identifiers can be authorized ahead of
+// their orders, and that new orders reuse valid authorizations per the
+// configured policy.
+func TestAcmePreAuthorization(t *testing.T) {
+	t.Parallel()
+	cluster, client, _ := setupAcmeBackend(t)
+	defer cluster.Cleanup()
+
+	testCtx := context.Background()
+	baseAcmeURL := "/v1/pki/acme/"
+	accountKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed creating rsa key")
+
+	acmeClient := getAcmeClientForCluster(t, cluster, baseAcmeURL, accountKey)
+	discovery, err := acmeClient.Discover(testCtx)
+	require.NoError(t, err, "failed acme discovery call")
+	require.Equal(t, client.Address()+baseAcmeURL+"new-authz", discovery.AuthzURL)
+
+	acct, err := acmeClient.Register(testCtx, &acme.Account{}, func(tosURL string) bool { return true })
+	require.NoError(t, err, "failed registering account")
+
+	// Wildcards can't be pre-authorized
+	_, err = acmeClient.Authorize(testCtx, "*.localdomain")
+	require.Error(t, err, "should have rejected pre-authorization of a wildcard")
+
+	authz, err := acmeClient.Authorize(testCtx, "localhost.localdomain")
+	require.NoError(t, err, "failed pre-authorizing identifier")
+	require.Equal(t, acme.StatusPending, authz.Status)
+	require.Equal(t, "localhost.localdomain", authz.Identifier.Value)
+	require.False(t, authz.Expires.IsZero(), "pre-authorization should have an expiry")
+	require.Len(t, authz.Challenges, 2, "expected two challenges")
+
+	// Pending authorizations aren't reused
+	order, err := acmeClient.AuthorizeOrder(testCtx, acme.DomainIDs("localhost.localdomain"))
+	require.NoError(t, err, "failed creating order")
+	require.Equal(t, acme.StatusPending, order.Status)
+	require.NotContains(t, order.AuthzURLs, authz.URI)
+
+	markAcmeAuthorizationValid(t, client, acct.URI, authz.URI)
+
+	// Once valid, new orders reuse it and are immediately ready
+	order, err = acmeClient.AuthorizeOrder(testCtx, acme.DomainIDs("localhost.localdomain"))
+	require.NoError(t, err, "failed creating order")
+	require.Equal(t, acme.StatusReady, order.Status)
+	require.Equal(t, []string{authz.URI}, order.AuthzURLs)
+
+	csrKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "failed generated key for CSR")
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		DNSNames: []string{"localhost.localdomain"},
+	}, csrKey)
+	require.NoError(t, err, "failed generating csr")
+	_, _, err = acmeClient.CreateOrderCert(testCtx, order.FinalizeURL, csr, true)
+	require.NoError(t, err, "failed finalizing order")
+
+	// Without reuse, orders always get new authorizations
+	_, err = client.Logical().WriteWithContext(testCtx, "pki/config/acme", map[string]interface{}{
+		"authorization_reuse_policy": "never",
+	})
+	require.NoError(t, err)
+
+	order, err = acmeClient.AuthorizeOrder(testCtx, acme.DomainIDs("localhost.localdomain"))
+	require.NoError(t, err, "failed creating order")
+	require.Equal(t, acme.StatusPending, order.Status)
+	require.NotContains(t, order.AuthzURLs, authz.URI)
+
+	// Disabling pre-authorization removes it from the directory
+	_, err = client.Logical().WriteWithContext(testCtx, "pki/config/acme", map[string]interface{}{
+		"allow_pre_authorization": false,
+	})
+	require.NoError(t, err)
+
+	acmeClient = getAcmeClientForCluster(t, cluster, baseAcmeURL, accountKey)
+	discovery, err = acmeClient.Discover(testCtx)
+	require.NoError(t, err, "failed acme discovery call")
+	require.Empty(t, discovery.AuthzURL)
+
+	_, err = acmeClient.Authorize(testCtx, "localhost.localdomain")
+	require.Error(t, err, "should have rejected pre-authorization once disabled")
+}
+
+// TestAcmeAlternateChains verifies certificates are served with the chains
+// of all the issuers sharing the signing key, the default one being
+// configurable per ACME directory.
+func TestAcmeAlternateChains(t *testing.T) {
+	t.Parallel()
+	cluster, client, _ := setupAcmeBackend(t)
+	defer cluster.Cleanup()
+
+	testCtx := context.Background()
+
+	// Cross-sign the intermediate by a second root, as during a root rotation
+	_, err := client.Logical().WriteWithContext(testCtx, "pki/issuers/generate/root/internal", map[string]interface{}{
+		"issuer_name": "root-ca-2",
+		"key_name":    "root-key-2",
+		"key_type":    "ec",
+		"common_name": "root2.com",
+		"ttl":         "7200h",
+	})
+	require.NoError(t, err, "failed creating second root CA")
+
+	resp, err := client.Logical().WriteWithContext(testCtx, "pki/issuers/generate/intermediate/existing", map[string]interface{}{
+		"key_ref":     "int-key",
+		"common_name": "test.com",
+	})
+	require.NoError(t, err, "failed creating intermediary CSR")
+
+	resp, err = client.Logical().WriteWithContext(testCtx, "pki/issuer/root-ca-2/sign-intermediate", map[string]interface{}{
+		"csr": resp.Data["csr"],
+		"ttl": "720h",
+	})
+	require.NoError(t, err, "failed signing intermediary CSR")
+
+	resp, err = client.Logical().WriteWithContext(testCtx, "pki/issuers/import/cert", map[string]interface{}{
+		"pem_bundle": resp.Data["certificate"],
+	})
+	require.NoError(t, err, "failed importing intermediary cert")
+	require.Len(t, resp.Data["imported_issuers"], 1)
+	crossSignedId := resp.Data["imported_issuers"].([]interface{})[0].(string)
+
+	_, err = client.Logical().WriteWithContext(testCtx, "pki/issuer/"+crossSignedId, map[string]interface{}{
+		"issuer_name":  "int-ca-2",
+		"manual_chain": []string{"self", "root-ca-2"},
+	})
+	require.NoError(t, err, "failed updating issuer")
+
+	_, err = client.Logical().WriteWithContext(testCtx, "pki/issuer/int-ca", map[string]interface{}{
+		"manual_chain": []string{"self", "root-ca"},
+	})
+	require.NoError(t, err, "failed updating issuer")
+
+	// Issue a certificate
+	accountKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed creating rsa key")
+	acmeClient := getAcmeClientForCluster(t, cluster, "/v1/pki/acme/", accountKey)
+	acct, err := acmeClient.Register(testCtx, &acme.Account{}, func(tosURL string) bool { return true })
+	require.NoError(t, err, "failed registering account")
+
+	order, err := acmeClient.AuthorizeOrder(testCtx, acme.DomainIDs("localhost.localdomain"))
+	require.NoError(t, err, "failed creating order")
+	markAcmeAuthorizationValid(t, client, acct.URI, order.AuthzURLs[0])
+
+	csrKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "failed generated key for CSR")
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		DNSNames: []string{"localhost.localdomain"},
+	}, csrKey)
+	require.NoError(t, err, "failed generating csr")
+	certs, certURL, err := acmeClient.CreateOrderCert(testCtx, order.FinalizeURL, csr, true)
+	require.NoError(t, err, "failed finalizing order")
+
+	getRootName := func(certs [][]byte) string {
+		t.Helper()
+		require.Len(t, certs, 3, "expected leaf, intermediate and root")
+		root, err := x509.ParseCertificate(certs[2])
+		require.NoError(t, err)
+		return root.Subject.CommonName
+	}
+
+	// By default, the chain of the signing issuer is served
+	require.Equal(t, "root.com", getRootName(certs))
+
+	alternates, err := acmeClient.ListCertAlternates(testCtx, certURL)
+	require.NoError(t, err, "failed listing alternate chains")
+	require.Len(t, alternates, 1)
+	require.Equal(t, certURL+"/"+crossSignedId, alternates[0])
+
+	certs, err = acmeClient.FetchCert(testCtx, alternates[0], true)
+	require.NoError(t, err, "failed fetching alternate chain")
+	require.Equal(t, "root2.com", getRootName(certs))
+
+	alternates, err = acmeClient.ListCertAlternates(testCtx, alternates[0])
+	require.NoError(t, err, "failed listing alternate chains")
+	require.Equal(t, []string{certURL}, alternates)
+
+	// Prefer the chain of the cross-signed intermediate
+	_, err = client.Logical().WriteWithContext(testCtx, "pki/config/acme", map[string]interface{}{
+		"default_chain_issuers": map[string]string{"acme": "int-ca-2"},
+	})
+	require.NoError(t, err)
+
+	certs, err = acmeClient.FetchCert(testCtx, certURL, true)
+	require.NoError(t, err, "failed fetching default chain")
+	require.Equal(t, "root2.com", getRootName(certs))
+
+	alternates, err = acmeClient.ListCertAlternates(testCtx, certURL)
+	require.NoError(t, err, "failed listing alternate chains")
+	require.Len(t, alternates, 1)
+
+	certs, err = acmeClient.FetchCert(testCtx, alternates[0], true)
+	require.NoError(t, err, "failed fetching alternate chain")
+	require.Equal(t, "root.com", getRootName(certs))
+
+	// Directories are validated
+	_, err = client.Logical().WriteWithContext(testCtx, "pki/config/acme", map[string]interface{}{
+		"default_chain_issuers": map[string]string{"roles/test-role": "int-ca-2"},
+	})
+	require.Error(t, err, "should have rejected an invalid ACME directory")
+}
+
+// markAcmeAuthorizationValid updates an authorization and its challenges to
+// valid through raw storage, as the challenges can't be completed in tests.
+func markAcmeAuthorizationValid(t *testing.T, client *api.Client, accountURI, authURI string) {
+	t.Helper()
+
+	pkiMount := findStorageMountUuid(t, client, "pki")
+	accountId := accountURI[strings.LastIndex(accountURI, "/"):]
+	authId := authURI[strings.LastIndex(authURI, "/"):]
+
+	rawPath := path.Join("/sys/raw/logical/", pkiMount, getAuthorizationPath(accountId, authId))
+	resp, err := client.Logical().ReadWithContext(context.Background(), rawPath)
+	require.NoError(t, err, "failed looking up authorization storage")
+	require.NotNil(t, resp, "sys raw response was nil")
+
+	var authz ACMEAuthorization
+	err = jsonutil.DecodeJSON([]byte(resp.Data["value"].(string)), &authz)
+	require.NoError(t, err, "error decoding authorization")
+	authz.Status = ACMEAuthorizationValid
+	authz.Expires = time.Now().Add(time.Hour).Format(time.RFC3339)
+	for _, challenge := range authz.Challenges {
+		challenge.Status = ACMEChallengeValid
+	}
+
+	encodeJSON, err := jsonutil.EncodeJSON(authz)
+	require.NoError(t, err, "failed encoding authz json")
+	_, err = client.Logical().WriteWithContext(context.Background(), rawPath, map[string]interface{}{
+		"value":    base64.StdEncoding.EncodeToString(encodeJSON),
+		"encoding": "base64",
+	})
+	require.NoError(t, err, "failed writing authorization storage")
+}
+
 // TestAcmeDisabledWithEnvVar verifies if VAULT_DISABLE_PUBLIC_ACME is set that we completely
 // disable the ACME service
 func TestAcmeDisabledWithEnvVar(t *testing.T) {
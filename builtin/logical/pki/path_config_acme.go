@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/errutil"
@@ -15,7 +17,7 @@ import (
 const (
 	storageAcmeConfig      = "config/acme"
 	pathConfigAcmeHelpSyn  = "Configuration of ACME Endpoints"
-	pathConfigAcmeHelpDesc = "Here we configure:\n\nenabled=false, whether ACME is enabled, defaults to false meaning that clusters will by default not get ACME support,\nallowed_issuers=\"default\", which issuers are allowed for use with ACME; by default, this will only be the primary (default) issuer,\nallowed_roles=\"*\", which roles are allowed for use with ACME; by default these will be all roles matching our selection criteria,\ndefault_role=\"\", if not empty, the role to be used for non-role-qualified ACME requests; by default this will be empty, meaning ACME issuance will be equivalent to sign-verbatim.,\ndns_resolver=\"\", which specifies a custom DNS resolver to use for all ACME-related DNS lookups"
+	pathConfigAcmeHelpDesc = "Here we configure:\n\nenabled=false, whether ACME is enabled, defaults to false meaning that clusters will by default not get ACME support,\nallowed_issuers=\"default\", which issuers are allowed for use with ACME; by default, this will only be the primary (default) issuer,\nallowed_roles=\"*\", which roles are allowed for use with ACME; by default these will be all roles matching our selection criteria,\ndefault_role=\"\", if not empty, the role to be used for non-role-qualified ACME requests; by default this will be empty, meaning ACME issuance will be equivalent to sign-verbatim.,\ndns_resolver=\"\", which specifies a custom DNS resolver to use for all ACME-related DNS lookups,\nallow_pre_authorization=true, whether clients may authorize identifiers ahead of their orders through newAuthz,\nauthorization_lifetime=\"360h\", how long completed authorizations remain valid,\nauthorization_reuse_policy=\"valid\", whether new orders reuse the valid authorizations of the account,\ndefault_chain_issuers={}, which issuer chain ACME directories serve by default when several issuers share the signing key, the others being served as alternate chains"
 	disableAcmeEnvVar      = "VAULT_DISABLE_PUBLIC_ACME"
 )
 
@@ -26,8 +28,18 @@ type acmeConfigEntry struct {
 	DefaultRole    string        `json:"default_role"`
 	DNSResolver    string        `json:"dns_resolver"`
 	EabPolicyName  EabPolicyName `json:"eab_policy_name"`
+
+	AllowPreAuthorization    bool                         `json:"allow_pre_authorization"`
+	AuthorizationLifetime    time.Duration                `json:"authorization_lifetime"`
+	AuthorizationReusePolicy ACMEAuthorizationReusePolicy `json:"authorization_reuse_policy"`
+
+	DefaultChainIssuers map[string]string `json:"default_chain_issuers"`
 }
 
+// acmeDirectoryChainKeyRegex matches the keys of default_chain_issuers, the
+// paths of the ACME directories relative to the mount.
+var acmeDirectoryChainKeyRegex = regexp.MustCompile(`^(issuer/[^/]+/)?(roles/[^/]+/)?acme$`)
+
 var defaultAcmeConfig = acmeConfigEntry{
 	Enabled:        false,
 	AllowedIssuers: []string{"*"},
@@ -35,6 +47,10 @@ var defaultAcmeConfig = acmeConfigEntry{
 	DefaultRole:    "",
 	DNSResolver:    "",
 	EabPolicyName:  eabPolicyNotRequired,
+
+	AllowPreAuthorization:    true,
+	AuthorizationLifetime:    15 * 24 * time.Hour,
+	AuthorizationReusePolicy: ACMEAuthorizationReuseValid,
 }
 
 func (sc *storageContext) getAcmeConfig() (*acmeConfigEntry, error) {
@@ -43,12 +59,13 @@ func (sc *storageContext) getAcmeConfig() (*acmeConfigEntry, error) {
 		return nil, err
 	}
 
-	var mapping acmeConfigEntry
+	mapping := defaultAcmeConfig
 	if entry == nil {
-		mapping = defaultAcmeConfig
 		return &mapping, nil
 	}
 
+	// Decode on top of the defaults, so fields introduced after the
+	// configuration was written keep their default values.
 	if err := entry.DecodeJSON(&mapping); err != nil {
 		return nil, errutil.InternalError{Err: fmt.Sprintf("unable to decode ACME configuration: %v", err)}
 	}
@@ -109,6 +126,25 @@ func pathAcmeConfig(b *backend) *framework.Path {
 				Description: `Specify the policy to use for external account binding behaviour, 'not-required', 'new-account-required' or 'always-required'`,
 				Default:     "always-required",
 			},
+			"allow_pre_authorization": {
+				Type:        framework.TypeBool,
+				Description: `whether ACME clients may authorize identifiers ahead of their orders through the newAuthz endpoint; defaults to true`,
+				Default:     true,
+			},
+			"authorization_lifetime": {
+				Type:        framework.TypeDurationSecond,
+				Description: `how long an authorization remains valid once its challenge has been completed; defaults to 15 days`,
+				Default:     "360h",
+			},
+			"authorization_reuse_policy": {
+				Type:        framework.TypeString,
+				Description: `Specify whether new orders reuse the unexpired valid authorizations of the account for their identifiers, 'valid', or always create new authorizations, 'never'`,
+				Default:     "valid",
+			},
+			"default_chain_issuers": {
+				Type:        framework.TypeKVPairs,
+				Description: `Map of ACME directories, as their path relative to the mount such as 'acme' or 'roles/:role/acme', or '*' for every directory, to the issuer whose chain certificates are served with by default. The issuer must share the key and subject of the issuer signing the certificates; the chains of the other such issuers are served as alternate chains.`,
+			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -154,6 +190,11 @@ func genResponseFromAcmeConfig(config *acmeConfigEntry, warnings []string) *logi
 			"enabled":         config.Enabled,
 			"dns_resolver":    config.DNSResolver,
 			"eab_policy":      config.EabPolicyName,
+
+			"allow_pre_authorization":    config.AllowPreAuthorization,
+			"authorization_lifetime":     int64(config.AuthorizationLifetime.Seconds()),
+			"authorization_reuse_policy": config.AuthorizationReusePolicy,
+			"default_chain_issuers":      config.DefaultChainIssuers,
 		},
 		Warnings: warnings,
 	}
@@ -218,6 +259,40 @@ func (b *backend) pathAcmeWrite(ctx context.Context, req *logical.Request, d *fr
 		config.EabPolicyName = eabPolicy.Name
 	}
 
+	if allowPreAuthorizationRaw, ok := d.GetOk("allow_pre_authorization"); ok {
+		config.AllowPreAuthorization = allowPreAuthorizationRaw.(bool)
+	}
+
+	if authorizationLifetimeRaw, ok := d.GetOk("authorization_lifetime"); ok {
+		config.AuthorizationLifetime = time.Duration(authorizationLifetimeRaw.(int)) * time.Second
+		if config.AuthorizationLifetime < time.Minute {
+			return nil, fmt.Errorf("authorization_lifetime must be at least one minute")
+		}
+	}
+
+	if authorizationReusePolicyRaw, ok := d.GetOk("authorization_reuse_policy"); ok {
+		policy, err := getAcmeAuthorizationReusePolicy(authorizationReusePolicyRaw.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid authorization reuse policy provided, valid values are '%s', '%s'",
+				ACMEAuthorizationReuseNever, ACMEAuthorizationReuseValid)
+		}
+		config.AuthorizationReusePolicy = policy
+	}
+
+	if defaultChainIssuersRaw, ok := d.GetOk("default_chain_issuers"); ok {
+		config.DefaultChainIssuers = defaultChainIssuersRaw.(map[string]string)
+		for directory, name := range config.DefaultChainIssuers {
+			if directory != "*" && !acmeDirectoryChainKeyRegex.MatchString(directory) {
+				return nil, fmt.Errorf("invalid ACME directory %v in default_chain_issuers; expected '*' or a path such as 'acme' or 'roles/:role/acme'", directory)
+			}
+
+			_, err := sc.resolveIssuerReference(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed validating default_chain_issuers: unable to fetch issuer: %v: %w", name, err)
+			}
+		}
+	}
+
 	allowAnyRole := len(config.AllowedRoles) == 1 && config.AllowedRoles[0] == "*"
 	if !allowAnyRole {
 		foundDefault := len(config.DefaultRole) == 0
@@ -507,6 +507,12 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 		}
 	}
 
+	eventType := "issue"
+	if useCSR {
+		eventType = "sign"
+	}
+	b.sendEvent(ctx, eventType, "path", req.Path, "role", role.Name, "serial_number", cb.SerialNumber)
+
 	resp = addWarnings(resp, warnings)
 
 	return resp, nil
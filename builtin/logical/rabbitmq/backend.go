@@ -34,6 +34,7 @@ func Backend() *backend {
 		PathsSpecial: &logical.Paths{
 			SealWrapStorage: []string{
 				"config/connection",
+				staticRolePath,
 			},
 		},
 
@@ -43,15 +44,20 @@ func Backend() *backend {
 			pathListRoles(&b),
 			pathCreds(&b),
 			pathRoles(&b),
+			pathListStaticRoles(&b),
+			pathStaticRoles(&b),
+			pathStaticCreds(&b),
+			pathRotateStaticRole(&b),
 		},
 
 		Secrets: []*framework.Secret{
 			secretCreds(&b),
 		},
 
-		Clean:       b.resetClient,
-		Invalidate:  b.invalidate,
-		BackendType: logical.TypeLogical,
+		Clean:        b.resetClient,
+		Invalidate:   b.invalidate,
+		PeriodicFunc: b.rotateExpiredStaticRoles,
+		BackendType:  logical.TypeLogical,
 	}
 
 	return &b
@@ -62,6 +68,9 @@ type backend struct {
 
 	client *rabbithole.Client
 	lock   sync.RWMutex
+
+	// staticRoleLock serializes password rotations of static roles
+	staticRoleLock sync.Mutex
 }
 
 // DB returns the database connection.
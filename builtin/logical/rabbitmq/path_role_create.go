@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/template"
@@ -136,7 +137,12 @@ func (b *backend) pathCredsRead(ctx context.Context, req *logical.Request, d *fr
 	// If the role had vhost permissions specified, assign those permissions
 	// to the created username for respective vhosts.
 	for vhost, permission := range role.VHosts {
-		err := func() error {
+		vhost, err := b.renderVHost(role, vhost, req.EntityID)
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+
+		err = func() error {
 			resp, err := client.UpdatePermissionsIn(vhost, username, rabbithole.Permissions{
 				Configure: permission.Configure,
 				Write:     permission.Write,
@@ -164,6 +170,11 @@ func (b *backend) pathCredsRead(ctx context.Context, req *logical.Request, d *fr
 	// If the role had vhost topic permissions specified, assign those permissions
 	// to the created username for respective vhosts and exchange.
 	for vhost, permissions := range role.VHostTopics {
+		vhost, err := b.renderVHost(role, vhost, req.EntityID)
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+
 		for exchange, permission := range permissions {
 			err := func() error {
 				resp, err := client.UpdateTopicPermissionsIn(vhost, username, rabbithole.TopicPermissions{
@@ -214,6 +225,23 @@ func (b *backend) pathCredsRead(ctx context.Context, req *logical.Request, d *fr
 	return response, nil
 }
 
+// renderVHost returns the name of the vhost to grant permissions on, rendering
+// identity templates for the requesting entity if the role enables them.
+func (b *backend) renderVHost(role *roleEntry, vhost string, entityID string) (string, error) {
+	if !role.VHostTemplating || !strings.Contains(vhost, "{{") {
+		return vhost, nil
+	}
+	if entityID == "" {
+		return "", fmt.Errorf("vhost template %q requires an identity entity, but the request has none", vhost)
+	}
+
+	rendered, err := framework.PopulateIdentityTemplate(vhost, entityID, b.System())
+	if err != nil {
+		return "", fmt.Errorf("vhost template %q could not be rendered: %w", vhost, err)
+	}
+	return rendered, nil
+}
+
 func isIn200s(respStatus int) bool {
 	return respStatus >= 200 && respStatus < 300
 }
@@ -162,3 +162,67 @@ func TestBackend_RoleCreate_CustomUsernameTemplate(t *testing.T) {
 
 	require.Regexp(t, `^foo-token$`, username)
 }
+
+func TestBackend_RenderVHost(t *testing.T) {
+	config := logical.TestBackendConfig()
+	sysView := logical.TestSystemView()
+	sysView.EntityVal = &logical.Entity{
+		ID:   "entity-id",
+		Name: "entity-name",
+		Metadata: map[string]string{
+			"team": "payments",
+		},
+	}
+	config.System = sysView
+	config.StorageView = &logical.InmemStorage{}
+	b := Backend()
+	if err := b.Setup(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	role := &roleEntry{VHostTemplating: true}
+
+	vhost, err := b.renderVHost(role, "{{identity.entity.metadata.team}}-vhost", "entity-id")
+	require.NoError(t, err)
+	require.Equal(t, "payments-vhost", vhost)
+
+	vhost, err = b.renderVHost(role, "/", "entity-id")
+	require.NoError(t, err)
+	require.Equal(t, "/", vhost)
+
+	_, err = b.renderVHost(role, "{{identity.entity.name}}", "")
+	require.Error(t, err)
+
+	// Templates are left as-is unless the role enables templating
+	role.VHostTemplating = false
+	vhost, err = b.renderVHost(role, "{{identity.entity.name}}", "entity-id")
+	require.NoError(t, err)
+	require.Equal(t, "{{identity.entity.name}}", vhost)
+}
+
+func TestBackend_RoleValidateVHostTemplates(t *testing.T) {
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b := Backend()
+	if err := b.Setup(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	roleReq := &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "roles/foo",
+		Storage:   config.StorageView,
+		Data: map[string]interface{}{
+			"vhosts":           `{"{{identity.entity.name": {"configure": ".*", "write": ".*", "read": ".*"}}`,
+			"vhost_templating": true,
+		},
+	}
+	resp, err := b.HandleRequest(context.Background(), roleReq)
+	require.NoError(t, err)
+	require.True(t, resp.IsError(), "expected an error for an invalid vhost template")
+
+	roleReq.Data["vhosts"] = `{"{{identity.entity.name}}": {"configure": ".*", "write": ".*", "read": ".*"}}`
+	resp, err = b.HandleRequest(context.Background(), roleReq)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
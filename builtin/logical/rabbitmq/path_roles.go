@@ -52,6 +52,11 @@ func pathRoles(b *backend) *framework.Path {
 				Type:        framework.TypeString,
 				Description: "A nested map of virtual hosts and exchanges to topic permissions.",
 			},
+			"vhost_templating": {
+				Type:        framework.TypeBool,
+				Description: "If set, virtual host names in vhosts and vhost_topics can contain identity template policies. Non-templated names are also permitted.",
+				Default:     false,
+			},
 		},
 		Callbacks: map[logical.Operation]framework.OperationFunc{
 			logical.ReadOperation:   b.pathRoleRead,
@@ -131,6 +136,7 @@ func (b *backend) pathRoleUpdate(ctx context.Context, req *logical.Request, d *f
 	tags := d.Get("tags").(string)
 	rawVHosts := d.Get("vhosts").(string)
 	rawVHostTopics := d.Get("vhost_topics").(string)
+	vhostTemplating := d.Get("vhost_templating").(bool)
 
 	// Either tags or VHost permissions are always required, but topic permissions are always optional.
 	if tags == "" && rawVHosts == "" {
@@ -151,11 +157,25 @@ func (b *backend) pathRoleUpdate(ctx context.Context, req *logical.Request, d *f
 		}
 	}
 
+	if vhostTemplating {
+		for vhost := range vhosts {
+			if _, err := framework.ValidateIdentityTemplate(vhost); err != nil {
+				return logical.ErrorResponse(fmt.Sprintf("invalid vhost template %q: %s", vhost, err)), nil
+			}
+		}
+		for vhost := range vhostTopics {
+			if _, err := framework.ValidateIdentityTemplate(vhost); err != nil {
+				return logical.ErrorResponse(fmt.Sprintf("invalid vhost template %q: %s", vhost, err)), nil
+			}
+		}
+	}
+
 	// Store it
 	entry, err := logical.StorageEntryJSON("role/"+name, &roleEntry{
-		Tags:        tags,
-		VHosts:      vhosts,
-		VHostTopics: vhostTopics,
+		Tags:            tags,
+		VHosts:          vhosts,
+		VHostTopics:     vhostTopics,
+		VHostTemplating: vhostTemplating,
 	})
 	if err != nil {
 		return nil, err
@@ -171,11 +191,13 @@ func (b *backend) pathRoleUpdate(ctx context.Context, req *logical.Request, d *f
 // Maps are used because the names of vhosts and exchanges will vary widely.
 // VHosts is a map with a vhost name as key and the permissions as value.
 // VHostTopics is a nested map with vhost name and exchange name as keys and
-// the topic permissions as value.
+// the topic permissions as value. When VHostTemplating is set, the vhost
+// names may be identity templates rendered for the requesting entity.
 type roleEntry struct {
-	Tags        string                                     `json:"tags" structs:"tags" mapstructure:"tags"`
-	VHosts      map[string]vhostPermission                 `json:"vhosts" structs:"vhosts" mapstructure:"vhosts"`
-	VHostTopics map[string]map[string]vhostTopicPermission `json:"vhost_topics" structs:"vhost_topics" mapstructure:"vhost_topics"`
+	Tags            string                                     `json:"tags" structs:"tags" mapstructure:"tags"`
+	VHosts          map[string]vhostPermission                 `json:"vhosts" structs:"vhosts" mapstructure:"vhosts"`
+	VHostTopics     map[string]map[string]vhostTopicPermission `json:"vhost_topics" structs:"vhost_topics" mapstructure:"vhost_topics"`
+	VHostTemplating bool                                       `json:"vhost_templating" structs:"vhost_templating" mapstructure:"vhost_templating"`
 }
 
 // Structure representing the permissions of a vhost
@@ -234,4 +256,8 @@ will be granted. This is a JSON object passed as a string in the form:
 		}
 	}
 }
+When "vhost_templating" is set, virtual host names in both "vhosts" and
+"vhost_topics" may use identity templates, such as
+"{{identity.entity.metadata.team}}", which are rendered using the entity of
+the token requesting the credentials.
 `
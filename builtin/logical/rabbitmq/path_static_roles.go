@@ -0,0 +1,439 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/logical"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+)
+
+const (
+	staticRolePath = "static-role/"
+
+	// minRotationPeriod is the shortest rotation period accepted for a static
+	// role, preventing the periodic function from rotating constantly.
+	minRotationPeriod = 5 * time.Second
+)
+
+func pathListStaticRoles(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "static-roles/?$",
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixRabbitMQ,
+			OperationSuffix: "static-roles",
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathStaticRoleList,
+		},
+		HelpSynopsis:    pathStaticRoleHelpSyn,
+		HelpDescription: pathStaticRoleHelpDesc,
+	}
+}
+
+func pathStaticRoles(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "static-roles/" + framework.GenericNameRegex("name"),
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixRabbitMQ,
+			OperationSuffix: "static-role",
+		},
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the static role.",
+			},
+			"username": {
+				Type:        framework.TypeString,
+				Description: "Name of the existing RabbitMQ user whose password is managed by this role.",
+			},
+			"rotation_period": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Period for automatic password rotation. If not set, the password is only rotated through the rotate-role endpoint.",
+			},
+		},
+		ExistenceCheck: b.pathStaticRoleExistenceCheck,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   b.pathStaticRoleRead,
+			logical.CreateOperation: b.pathStaticRoleWrite,
+			logical.UpdateOperation: b.pathStaticRoleWrite,
+			logical.DeleteOperation: b.pathStaticRoleDelete,
+		},
+		HelpSynopsis:    pathStaticRoleHelpSyn,
+		HelpDescription: pathStaticRoleHelpDesc,
+	}
+}
+
+func pathStaticCreds(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "static-creds/" + framework.GenericNameRegex("name"),
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixRabbitMQ,
+			OperationVerb:   "request",
+			OperationSuffix: "static-role-credentials",
+		},
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the static role.",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: b.pathStaticCredsRead,
+		},
+		HelpSynopsis:    pathStaticCredsHelpSyn,
+		HelpDescription: pathStaticCredsHelpDesc,
+	}
+}
+
+func pathRotateStaticRole(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "rotate-role/" + framework.GenericNameRegex("name"),
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixRabbitMQ,
+			OperationVerb:   "rotate",
+			OperationSuffix: "static-role-credentials",
+		},
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the static role.",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathRotateStaticRoleUpdate,
+		},
+		HelpSynopsis:    pathRotateStaticRoleHelpSyn,
+		HelpDescription: pathRotateStaticRoleHelpDesc,
+	}
+}
+
+// Reads the static role configuration from the storage
+func (b *backend) StaticRole(ctx context.Context, s logical.Storage, n string) (*staticRoleEntry, error) {
+	entry, err := s.Get(ctx, staticRolePath+n)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result staticRoleEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (b *backend) putStaticRole(ctx context.Context, s logical.Storage, name string, role *staticRoleEntry) error {
+	entry, err := logical.StorageEntryJSON(staticRolePath+name, role)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, entry)
+}
+
+func (b *backend) pathStaticRoleExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	role, err := b.StaticRole(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+	return role != nil, nil
+}
+
+// Lists all the static roles registered with the backend
+func (b *backend) pathStaticRoleList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	roles, err := req.Storage.List(ctx, staticRolePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(roles), nil
+}
+
+// Reads an existing static role, without its credentials
+func (b *backend) pathStaticRoleRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	role, err := b.StaticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: role.responseData(),
+	}, nil
+}
+
+// Registers a new static role with the backend, or updates an existing one.
+// Creating a static role immediately rotates the user's password so that only
+// Vault knows it.
+func (b *backend) pathStaticRoleWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	b.staticRoleLock.Lock()
+	defer b.staticRoleLock.Unlock()
+
+	role, err := b.StaticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+
+	isCreate := role == nil
+	if isCreate {
+		role = &staticRoleEntry{}
+	}
+
+	if username, ok := d.GetOk("username"); ok {
+		if !isCreate && username.(string) != role.Username {
+			return logical.ErrorResponse("username cannot be changed for an existing static role"), nil
+		}
+		role.Username = username.(string)
+	}
+	if role.Username == "" {
+		return logical.ErrorResponse("missing username"), nil
+	}
+
+	if rotationPeriod, ok := d.GetOk("rotation_period"); ok {
+		role.RotationPeriod = time.Duration(rotationPeriod.(int)) * time.Second
+	}
+	if role.RotationPeriod != 0 && role.RotationPeriod < minRotationPeriod {
+		return logical.ErrorResponse(fmt.Sprintf("rotation_period must be at least %s", minRotationPeriod)), nil
+	}
+
+	if isCreate {
+		if err := b.rotateStaticRole(ctx, req.Storage, role); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := b.putStaticRole(ctx, req.Storage, name, role); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Deletes an existing static role. The RabbitMQ user is left in place.
+func (b *backend) pathStaticRoleDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	b.staticRoleLock.Lock()
+	defer b.staticRoleLock.Unlock()
+
+	return nil, req.Storage.Delete(ctx, staticRolePath+name)
+}
+
+// Returns the current credentials of a static role
+func (b *backend) pathStaticCredsRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	role, err := b.StaticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse(fmt.Sprintf("unknown static role: %s", name)), nil
+	}
+
+	data := role.responseData()
+	data["password"] = role.Password
+	if role.RotationPeriod > 0 {
+		data["ttl"] = int64(time.Until(role.nextRotation()).Seconds())
+	}
+
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+// Rotates the password of a static role immediately
+func (b *backend) pathRotateStaticRoleUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	b.staticRoleLock.Lock()
+	defer b.staticRoleLock.Unlock()
+
+	role, err := b.StaticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse(fmt.Sprintf("unknown static role: %s", name)), nil
+	}
+
+	if err := b.rotateStaticRole(ctx, req.Storage, role); err != nil {
+		return nil, err
+	}
+
+	if err := b.putStaticRole(ctx, req.Storage, name, role); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// rotateStaticRole generates a new password for the static role's user and
+// sets it in RabbitMQ, keeping the user's existing tags. The role is updated
+// in place but not persisted.
+func (b *backend) rotateStaticRole(ctx context.Context, s logical.Storage, role *staticRoleEntry) error {
+	config, err := readConfig(ctx, s)
+	if err != nil {
+		return fmt.Errorf("unable to read configuration: %w", err)
+	}
+
+	client, err := b.Client(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	user, err := client.GetUser(role.Username)
+	if err != nil {
+		return fmt.Errorf("failed to look up user %s: %w", role.Username, err)
+	}
+
+	password, err := b.generatePassword(ctx, config.PasswordPolicy)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.PutUser(role.Username, rabbithole.UserSettings{
+		Password: password,
+		Tags:     user.Tags,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the password of user %s: %w", role.Username, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			b.Logger().Error(fmt.Sprintf("unable to close response body: %s", err))
+		}
+	}()
+	if !isIn200s(resp.StatusCode) {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("error updating the password of user %s - %d: %s", role.Username, resp.StatusCode, body)
+	}
+
+	role.Password = password
+	role.LastVaultRotation = time.Now()
+
+	return nil
+}
+
+// rotateExpiredStaticRoles is called periodically and rotates the passwords of
+// all static roles whose rotation period has elapsed.
+func (b *backend) rotateExpiredStaticRoles(ctx context.Context, req *logical.Request) error {
+	// Only the primary, or the local mount on a secondary, owns the passwords
+	replicationState := b.System().ReplicationState()
+	if (!b.System().LocalMount() && replicationState.HasState(consts.ReplicationPerformanceSecondary)) ||
+		replicationState.HasState(consts.ReplicationDRSecondary|consts.ReplicationPerformanceStandby) {
+		return nil
+	}
+
+	names, err := req.Storage.List(ctx, staticRolePath)
+	if err != nil {
+		return err
+	}
+
+	b.staticRoleLock.Lock()
+	defer b.staticRoleLock.Unlock()
+
+	for _, name := range names {
+		role, err := b.StaticRole(ctx, req.Storage, name)
+		if err != nil {
+			return err
+		}
+		if role == nil || role.RotationPeriod == 0 || time.Now().Before(role.nextRotation()) {
+			continue
+		}
+
+		if err := b.rotateStaticRole(ctx, req.Storage, role); err != nil {
+			// Keep going so that one unreachable user does not block the others
+			b.Logger().Error("failed to rotate static role password", "role", name, "error", err)
+			continue
+		}
+
+		if err := b.putStaticRole(ctx, req.Storage, name, role); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Static role that manages the password of an existing RabbitMQ user.
+type staticRoleEntry struct {
+	Username          string        `json:"username"`
+	Password          string        `json:"password"`
+	RotationPeriod    time.Duration `json:"rotation_period"`
+	LastVaultRotation time.Time     `json:"last_vault_rotation"`
+}
+
+func (r *staticRoleEntry) nextRotation() time.Time {
+	return r.LastVaultRotation.Add(r.RotationPeriod)
+}
+
+func (r *staticRoleEntry) responseData() map[string]interface{} {
+	return map[string]interface{}{
+		"username":            r.Username,
+		"rotation_period":     int64(r.RotationPeriod.Seconds()),
+		"last_vault_rotation": r.LastVaultRotation,
+	}
+}
+
+const pathStaticRoleHelpSyn = `
+Manage the static roles that can be created with this backend.
+`
+
+const pathStaticRoleHelpDesc = `
+This path lets you manage the static roles of this backend. A static role
+manages the password of a pre-existing RabbitMQ user, given by the "username"
+parameter. The password is rotated when the role is created, and afterwards
+every "rotation_period" if one is set. Deleting a static role does not delete
+the RabbitMQ user.
+`
+
+const pathStaticCredsHelpSyn = `
+Request the current RabbitMQ credentials of a static role.
+`
+
+const pathStaticCredsHelpDesc = `
+This path reads the current username and password of a static role. The
+returned "ttl" is the time remaining until the password is rotated next, if the
+role has a rotation period.
+`
+
+const pathRotateStaticRoleHelpSyn = `
+Rotate the password of a static role.
+`
+
+const pathRotateStaticRoleHelpDesc = `
+This path immediately rotates the password of the RabbitMQ user managed by a
+static role, independently of its rotation period.
+`
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package rabbitmq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend_StaticRole(t *testing.T) {
+	cleanup, connectionURI := prepareRabbitMQTestContainer(t)
+	defer cleanup()
+
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b := Backend()
+	if err := b.Setup(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	configReq := &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "config/connection",
+		Storage:   config.StorageView,
+		Data: map[string]interface{}{
+			"connection_uri": connectionURI,
+			"username":       "guest",
+			"password":       "guest",
+		},
+	}
+	resp, err := b.HandleRequest(context.Background(), configReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr:%s", resp, err)
+	}
+
+	// Create the pre-existing user managed by the static role
+	client, err := rabbithole.NewClient(connectionURI, "guest", "guest")
+	require.NoError(t, err)
+	_, err = client.PutUser("static-user", rabbithole.UserSettings{
+		Password: "initial",
+		Tags:     []string{"management"},
+	})
+	require.NoError(t, err)
+
+	roleReq := &logical.Request{
+		Operation: logical.CreateOperation,
+		Path:      "static-roles/foo",
+		Storage:   config.StorageView,
+		Data: map[string]interface{}{
+			"username":        "static-user",
+			"rotation_period": "1h",
+		},
+	}
+	resp, err = b.HandleRequest(context.Background(), roleReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr:%s", resp, err)
+	}
+
+	credsReq := &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "static-creds/foo",
+		Storage:   config.StorageView,
+	}
+	resp, err = b.HandleRequest(context.Background(), credsReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr:%s", resp, err)
+	}
+	require.Equal(t, "static-user", resp.Data["username"])
+	password := resp.Data["password"].(string)
+	require.NotEqual(t, "initial", password)
+
+	// The rotated password is usable and the tags are kept
+	userClient, err := rabbithole.NewClient(connectionURI, "static-user", password)
+	require.NoError(t, err)
+	user, err := userClient.Whoami()
+	require.NoError(t, err)
+	require.Equal(t, rabbithole.UserTags{"management"}, user.Tags)
+
+	rotateReq := &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "rotate-role/foo",
+		Storage:   config.StorageView,
+	}
+	resp, err = b.HandleRequest(context.Background(), rotateReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr:%s", resp, err)
+	}
+
+	resp, err = b.HandleRequest(context.Background(), credsReq)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr:%s", resp, err)
+	}
+	require.NotEqual(t, password, resp.Data["password"])
+
+	// The username of an existing static role cannot be changed
+	roleReq.Operation = logical.UpdateOperation
+	roleReq.Data = map[string]interface{}{
+		"username": "other-user",
+	}
+	resp, err = b.HandleRequest(context.Background(), roleReq)
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+}
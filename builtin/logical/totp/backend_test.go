@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/mitchellh/mapstructure"
 	otplib "github.com/pquerna/otp"
+	hotplib "github.com/pquerna/otp/hotp"
 	totplib "github.com/pquerna/otp/totp"
 )
 
@@ -1241,3 +1242,155 @@ func testAccStepValidateCode(t *testing.T, name string, code string, valid, expe
 		},
 	}
 }
+
+func TestBackend_hotp(t *testing.T) {
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b, err := Factory(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, _ := createKey()
+
+	hotpCode := func(counter uint64) string {
+		t.Helper()
+		code, err := hotplib.GenerateCode(key, counter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	request := func(operation logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+			Operation: operation,
+			Path:      path,
+			Storage:   config.StorageView,
+			Data:      data,
+		})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		return resp
+	}
+
+	validate := func(code string) bool {
+		t.Helper()
+		resp := request(logical.UpdateOperation, "code/test", map[string]interface{}{"code": code})
+		if resp == nil || resp.IsError() {
+			t.Fatalf("bad: %#v", resp)
+		}
+		return resp.Data["valid"].(bool)
+	}
+
+	resp := request(logical.UpdateOperation, "keys/test", map[string]interface{}{
+		"type":    "hotp",
+		"key":     key,
+		"counter": 5,
+	})
+	if resp != nil && resp.IsError() {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	resp = request(logical.ReadOperation, "keys/test", nil)
+	if resp.Data["type"] != "hotp" || resp.Data["counter"] != uint64(5) || resp.Data["look_ahead"] != uint(defaultLookAhead) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Generating codes advances the counter
+	for _, counter := range []uint64{5, 6} {
+		resp = request(logical.ReadOperation, "code/test", nil)
+		if resp.Data["code"] != hotpCode(counter) || resp.Data["counter"] != counter {
+			t.Fatalf("bad: expected code for counter %d, got: %#v", counter, resp.Data)
+		}
+	}
+
+	// Codes within the look-ahead window are valid, but only once
+	if !validate(hotpCode(9)) {
+		t.Fatal("expected code within the look-ahead window to be valid")
+	}
+	if validate(hotpCode(9)) {
+		t.Fatal("expected code to be invalid once used")
+	}
+	if validate(hotpCode(8)) {
+		t.Fatal("expected code behind the counter to be invalid")
+	}
+	if validate(hotpCode(40)) {
+		t.Fatal("expected code past the look-ahead window to be invalid")
+	}
+
+	// Resynchronizing requires two consecutive codes
+	resp = request(logical.UpdateOperation, "keys/test/resync", map[string]interface{}{
+		"code1": hotpCode(50),
+		"code2": hotpCode(52),
+	})
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("expected error for non-consecutive codes, got: %#v", resp)
+	}
+	resp = request(logical.UpdateOperation, "keys/test/resync", map[string]interface{}{
+		"code1": hotpCode(50),
+		"code2": hotpCode(51),
+	})
+	if resp == nil || resp.IsError() || resp.Data["counter"] != uint64(52) {
+		t.Fatalf("bad: %#v", resp)
+	}
+	if !validate(hotpCode(52)) {
+		t.Fatal("expected code after the resynchronized counter to be valid")
+	}
+}
+
+func TestBackend_hotpURL(t *testing.T) {
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b, err := Factory(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "keys/imported",
+		Storage:   config.StorageView,
+		Data: map[string]interface{}{
+			"url": "otpauth://hotp/Vault:test@email.com?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&algorithm=SHA1&digits=6&counter=3",
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	key, err := b.(*backend).Key(context.Background(), config.StorageView, "imported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.isHOTP() || key.Counter != 3 || key.Issuer != "Vault" || key.AccountName != "test@email.com" {
+		t.Fatalf("bad: %#v", key)
+	}
+
+	// Generated keys carry their counter in the url
+	resp, err = b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "keys/generated",
+		Storage:   config.StorageView,
+		Data: map[string]interface{}{
+			"type":         "hotp",
+			"generate":     true,
+			"issuer":       "Vault",
+			"account_name": "test@email.com",
+			"counter":      7,
+		},
+	})
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("bad: resp: %#v\nerr: %v", resp, err)
+	}
+
+	keyURL, err := url.Parse(resp.Data["url"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyURL.Host != "hotp" || keyURL.Query().Get("counter") != "7" {
+		t.Fatalf("bad: %s", keyURL)
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/locksutil"
 	"github.com/hashicorp/vault/sdk/logical"
 	otplib "github.com/pquerna/otp"
+	hotplib "github.com/pquerna/otp/hotp"
 	totplib "github.com/pquerna/otp/totp"
 )
 
@@ -30,7 +32,7 @@ func pathCode(b *backend) *framework.Path {
 			},
 			"code": {
 				Type:        framework.TypeString,
-				Description: "TOTP code to be validated.",
+				Description: "TOTP or HOTP code to be validated.",
 			},
 		},
 
@@ -66,6 +68,10 @@ func (b *backend) pathReadCode(ctx context.Context, req *logical.Request, data *
 		return logical.ErrorResponse(fmt.Sprintf("unknown key: %s", name)), nil
 	}
 
+	if key.isHOTP() {
+		return b.readHOTPCode(ctx, req, name)
+	}
+
 	// Generate password using totp library
 	totpToken, err := totplib.GenerateCodeCustom(key.Key, time.Now(), totplib.ValidateOpts{
 		Period:    key.Period,
@@ -102,6 +108,10 @@ func (b *backend) pathValidateCode(ctx context.Context, req *logical.Request, da
 		return logical.ErrorResponse(fmt.Sprintf("unknown key: %s", name)), nil
 	}
 
+	if key.isHOTP() {
+		return b.validateHOTPCode(ctx, req, name, code)
+	}
+
 	usedName := fmt.Sprintf("%s_%s", name, code)
 
 	_, ok := b.usedCodes.Get(usedName)
@@ -136,11 +146,109 @@ func (b *backend) pathValidateCode(ctx context.Context, req *logical.Request, da
 	}, nil
 }
 
+// readHOTPCode generates the code for the current counter of an HOTP key and
+// advances the counter, so that every code is only ever returned once.
+func (b *backend) readHOTPCode(ctx context.Context, req *logical.Request, name string) (*logical.Response, error) {
+	lock := locksutil.LockForKey(b.keyLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Re-read the key under the lock to get the latest counter
+	key, err := b.Key(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return logical.ErrorResponse(fmt.Sprintf("unknown key: %s", name)), nil
+	}
+
+	hotpToken, err := hotplib.GenerateCodeCustom(key.Key, key.Counter, hotplib.ValidateOpts{
+		Digits:    key.Digits,
+		Algorithm: key.Algorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counter := key.Counter
+	key.Counter++
+	if err := b.putKey(ctx, req.Storage, name, key); err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"code":    hotpToken,
+			"counter": counter,
+		},
+	}, nil
+}
+
+// validateHOTPCode validates a code against the current counter of an HOTP
+// key and the look-ahead window following it. On success, the counter is moved
+// past the matching value so that the code cannot be used again.
+func (b *backend) validateHOTPCode(ctx context.Context, req *logical.Request, name string, code string) (*logical.Response, error) {
+	lock := locksutil.LockForKey(b.keyLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Re-read the key under the lock to get the latest counter
+	key, err := b.Key(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return logical.ErrorResponse(fmt.Sprintf("unknown key: %s", name)), nil
+	}
+
+	counter, valid, err := findHOTPCounter(key, key.LookAhead, code)
+	if err != nil && err != otplib.ErrValidateInputInvalidLength {
+		return logical.ErrorResponse("an error occurred while validating the code"), err
+	}
+
+	if valid {
+		key.Counter = counter + 1
+		if err := b.putKey(ctx, req.Storage, name, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"valid": valid,
+		},
+	}, nil
+}
+
+// findHOTPCounter returns the first counter value in the window starting at
+// the key's current counter for which the given code is valid.
+func findHOTPCounter(key *keyEntry, window uint, code string) (uint64, bool, error) {
+	opts := hotplib.ValidateOpts{
+		Digits:    key.Digits,
+		Algorithm: key.Algorithm,
+	}
+
+	for i := uint64(0); i <= uint64(window); i++ {
+		counter := key.Counter + i
+		valid, err := hotplib.ValidateCustom(code, counter, key.Key, opts)
+		if err != nil {
+			return 0, false, err
+		}
+		if valid {
+			return counter, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
 const pathCodeHelpSyn = `
-Request time-based one-time use password or validate a password for a certain key .
+Request a one-time use password or validate a password for a certain key.
 `
 
 const pathCodeHelpDesc = `
-This path generates and validates time-based one-time use passwords for a certain key. 
+This path generates and validates time-based or counter-based one-time use
+passwords for a certain key. Generating or successfully validating a password
+for a counter-based key advances its counter.
 
 `
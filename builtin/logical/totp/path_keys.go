@@ -15,11 +15,22 @@ import (
 	"strings"
 
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/locksutil"
 	"github.com/hashicorp/vault/sdk/logical"
 	otplib "github.com/pquerna/otp"
+	hotplib "github.com/pquerna/otp/hotp"
 	totplib "github.com/pquerna/otp/totp"
 )
 
+const (
+	keyTypeTOTP = "totp"
+	keyTypeHOTP = "hotp"
+
+	// defaultLookAhead is the default number of counter values past the
+	// current one that are accepted when validating an HOTP code.
+	defaultLookAhead = 10
+)
+
 func pathListKeys(b *backend) *framework.Path {
 	return &framework.Path{
 		Pattern: "keys/?$",
@@ -53,6 +64,24 @@ func pathKeys(b *backend) *framework.Path {
 				Description: "Name of the key.",
 			},
 
+			"type": {
+				Type:        framework.TypeString,
+				Default:     keyTypeTOTP,
+				Description: `The type of one-time password the key is used for: "totp" for time-based or "hotp" for counter-based passwords. Read from the url if one is given.`,
+			},
+
+			"counter": {
+				Type:        framework.TypeInt,
+				Default:     0,
+				Description: `The initial counter value of an HOTP key. Only used if type is hotp.`,
+			},
+
+			"look_ahead": {
+				Type:        framework.TypeInt,
+				Default:     defaultLookAhead,
+				Description: `The number of counter values past the current one that are accepted when validating an HOTP code. Only used if type is hotp.`,
+			},
+
 			"generate": {
 				Type:        framework.TypeBool,
 				Default:     false,
@@ -186,9 +215,24 @@ func (b *backend) pathKeyRead(ctx context.Context, req *logical.Request, data *f
 	// Translate algorithm back to string
 	algorithm := key.Algorithm.String()
 
+	if key.isHOTP() {
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"type":         keyTypeHOTP,
+				"issuer":       key.Issuer,
+				"account_name": key.AccountName,
+				"algorithm":    algorithm,
+				"digits":       key.Digits,
+				"counter":      key.Counter,
+				"look_ahead":   key.LookAhead,
+			},
+		}, nil
+	}
+
 	// Return values of key
 	return &logical.Response{
 		Data: map[string]interface{}{
+			"type":         keyTypeTOTP,
 			"issuer":       key.Issuer,
 			"account_name": key.AccountName,
 			"period":       key.Period,
@@ -221,6 +265,9 @@ func (b *backend) pathKeyCreate(ctx context.Context, req *logical.Request, data
 	qrSize := data.Get("qr_size").(int)
 	keySize := data.Get("key_size").(int)
 	inputURL := data.Get("url").(string)
+	keyType := data.Get("type").(string)
+	counter := data.Get("counter").(int)
+	lookAhead := data.Get("look_ahead").(int)
 
 	if generate {
 		if keyString != "" {
@@ -239,6 +286,13 @@ func (b *backend) pathKeyCreate(ctx context.Context, req *logical.Request, data
 			return logical.ErrorResponse("an error occurred while parsing url string"), err
 		}
 
+		// Read the key type from the url host, e.g. otpauth://hotp/...
+		if urlObject.Host == keyTypeHOTP {
+			keyType = keyTypeHOTP
+		} else {
+			keyType = keyTypeTOTP
+		}
+
 		// Set up query object
 		urlQuery := urlObject.Query()
 		path := strings.TrimPrefix(urlObject.Path, "/")
@@ -289,6 +343,22 @@ func (b *backend) pathKeyCreate(ctx context.Context, req *logical.Request, data
 		if algorithmQuery != "" {
 			algorithm = algorithmQuery
 		}
+
+		// Read counter
+		counterQuery := urlQuery.Get("counter")
+		if counterQuery != "" {
+			counterInt, err := strconv.Atoi(counterQuery)
+			if err != nil {
+				return logical.ErrorResponse("an error occurred while parsing counter value in url"), err
+			}
+			counter = counterInt
+		}
+	}
+
+	switch keyType {
+	case keyTypeTOTP, keyTypeHOTP:
+	default:
+		return logical.ErrorResponse(`the type value must be "totp" or "hotp"`), nil
 	}
 
 	// Translate digits and algorithm to a format the totp library understands
@@ -335,6 +405,14 @@ func (b *backend) pathKeyCreate(ctx context.Context, req *logical.Request, data
 		return logical.ErrorResponse("the key_size value must be greater than zero"), nil
 	}
 
+	if counter < 0 {
+		return logical.ErrorResponse("the counter value must be greater than or equal to zero"), nil
+	}
+
+	if lookAhead < 0 {
+		return logical.ErrorResponse("the look_ahead value must be greater than or equal to zero"), nil
+	}
+
 	// Period, Skew and Key Size need to be unsigned ints
 	uintPeriod := uint(period)
 	uintSkew := uint(skew)
@@ -354,15 +432,28 @@ func (b *backend) pathKeyCreate(ctx context.Context, req *logical.Request, data
 		}
 
 		// Generate a new key
-		keyObject, err := totplib.Generate(totplib.GenerateOpts{
-			Issuer:      issuer,
-			AccountName: accountName,
-			Period:      uintPeriod,
-			Digits:      keyDigits,
-			Algorithm:   keyAlgorithm,
-			SecretSize:  uintKeySize,
-			Rand:        b.GetRandomReader(),
-		})
+		var keyObject *otplib.Key
+		var err error
+		if keyType == keyTypeHOTP {
+			keyObject, err = generateHOTPKey(hotplib.GenerateOpts{
+				Issuer:      issuer,
+				AccountName: accountName,
+				Digits:      keyDigits,
+				Algorithm:   keyAlgorithm,
+				SecretSize:  uintKeySize,
+				Rand:        b.GetRandomReader(),
+			}, uint64(counter))
+		} else {
+			keyObject, err = totplib.Generate(totplib.GenerateOpts{
+				Issuer:      issuer,
+				AccountName: accountName,
+				Period:      uintPeriod,
+				Digits:      keyDigits,
+				Algorithm:   keyAlgorithm,
+				SecretSize:  uintKeySize,
+				Rand:        b.GetRandomReader(),
+			})
+		}
 		if err != nil {
 			return logical.ErrorResponse("an error occurred while generating a key"), err
 		}
@@ -415,8 +506,8 @@ func (b *backend) pathKeyCreate(ctx context.Context, req *logical.Request, data
 		}
 	}
 
-	// Store it
-	entry, err := logical.StorageEntryJSON("key/"+name, &keyEntry{
+	key := &keyEntry{
+		Type:        keyType,
 		Key:         keyString,
 		Issuer:      issuer,
 		AccountName: accountName,
@@ -424,7 +515,20 @@ func (b *backend) pathKeyCreate(ctx context.Context, req *logical.Request, data
 		Algorithm:   keyAlgorithm,
 		Digits:      keyDigits,
 		Skew:        uintSkew,
-	})
+	}
+	if keyType == keyTypeHOTP {
+		key.Period = 0
+		key.Skew = 0
+		key.Counter = uint64(counter)
+		key.LookAhead = uint(lookAhead)
+	}
+
+	// Store it
+	lock := locksutil.LockForKey(b.keyLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	entry, err := logical.StorageEntryJSON("key/"+name, key)
 	if err != nil {
 		return nil, err
 	}
@@ -436,6 +540,7 @@ func (b *backend) pathKeyCreate(ctx context.Context, req *logical.Request, data
 }
 
 type keyEntry struct {
+	Type        string           `json:"type" mapstructure:"type" structs:"type"`
 	Key         string           `json:"key" mapstructure:"key" structs:"key"`
 	Issuer      string           `json:"issuer" mapstructure:"issuer" structs:"issuer"`
 	AccountName string           `json:"account_name" mapstructure:"account_name" structs:"account_name"`
@@ -443,6 +548,41 @@ type keyEntry struct {
 	Algorithm   otplib.Algorithm `json:"algorithm" mapstructure:"algorithm" structs:"algorithm"`
 	Digits      otplib.Digits    `json:"digits" mapstructure:"digits" structs:"digits"`
 	Skew        uint             `json:"skew" mapstructure:"skew" structs:"skew"`
+	Counter     uint64           `json:"counter" mapstructure:"counter" structs:"counter"`
+	LookAhead   uint             `json:"look_ahead" mapstructure:"look_ahead" structs:"look_ahead"`
+}
+
+// isHOTP reports whether the key is counter-based. Keys created before HOTP
+// support have no type and are time-based.
+func (k *keyEntry) isHOTP() bool {
+	return k.Type == keyTypeHOTP
+}
+
+func (b *backend) putKey(ctx context.Context, s logical.Storage, name string, key *keyEntry) error {
+	entry, err := logical.StorageEntryJSON("key/"+name, key)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, entry)
+}
+
+// generateHOTPKey generates a new HOTP key whose url carries the initial
+// counter, which authenticator apps require for counter-based keys.
+func generateHOTPKey(opts hotplib.GenerateOpts, counter uint64) (*otplib.Key, error) {
+	keyObject, err := hotplib.Generate(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	keyURL, err := url.Parse(keyObject.String())
+	if err != nil {
+		return nil, err
+	}
+	query := keyURL.Query()
+	query.Set("counter", strconv.FormatUint(counter, 10))
+	keyURL.RawQuery = query.Encode()
+
+	return otplib.NewKeyFromURL(keyURL.String())
 }
 
 const pathKeyHelpSyn = `
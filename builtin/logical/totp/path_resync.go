@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package totp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/locksutil"
+	"github.com/hashicorp/vault/sdk/logical"
+	otplib "github.com/pquerna/otp"
+	hotplib "github.com/pquerna/otp/hotp"
+)
+
+// defaultResyncWindow is the default number of counter values searched when
+// resynchronizing an HOTP key.
+const defaultResyncWindow = 100
+
+func pathKeyResync(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "keys/" + framework.GenericNameWithAtRegex("name") + "/resync",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixTOTP,
+			OperationVerb:   "resync",
+			OperationSuffix: "key",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the key.",
+			},
+			"code1": {
+				Type:        framework.TypeString,
+				Description: "The first of two consecutive HOTP codes generated by the token.",
+			},
+			"code2": {
+				Type:        framework.TypeString,
+				Description: "The second of two consecutive HOTP codes generated by the token.",
+			},
+			"window": {
+				Type:        framework.TypeInt,
+				Default:     defaultResyncWindow,
+				Description: "The number of counter values past the current one searched for the codes.",
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathKeyResync,
+			},
+		},
+
+		HelpSynopsis:    pathKeyResyncHelpSyn,
+		HelpDescription: pathKeyResyncHelpDesc,
+	}
+}
+
+func (b *backend) pathKeyResync(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+	code1 := data.Get("code1").(string)
+	code2 := data.Get("code2").(string)
+	window := data.Get("window").(int)
+
+	// Enforce input value requirements
+	if code1 == "" || code2 == "" {
+		return logical.ErrorResponse("the code1 and code2 values are required"), nil
+	}
+	if window < 0 {
+		return logical.ErrorResponse("the window value must be greater than or equal to zero"), nil
+	}
+
+	lock := locksutil.LockForKey(b.keyLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	key, err := b.Key(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return logical.ErrorResponse(fmt.Sprintf("unknown key: %s", name)), nil
+	}
+	if !key.isHOTP() {
+		return logical.ErrorResponse("only hotp keys can be resynchronized"), nil
+	}
+
+	counter, valid, err := findHOTPConsecutiveCounters(key, uint(window), code1, code2)
+	if err != nil && err != otplib.ErrValidateInputInvalidLength {
+		return logical.ErrorResponse("an error occurred while validating the codes"), err
+	}
+	if !valid {
+		return logical.ErrorResponse("the codes could not be matched to consecutive counter values within the window"), nil
+	}
+
+	key.Counter = counter + 2
+	if err := b.putKey(ctx, req.Storage, name, key); err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"counter": key.Counter,
+		},
+	}, nil
+}
+
+// findHOTPConsecutiveCounters returns the first counter value in the window
+// starting at the key's current counter for which code1 is valid and code2 is
+// valid for the following counter. Requiring two consecutive codes makes it
+// very unlikely that a guessed code moves the counter within the much larger
+// resync window.
+func findHOTPConsecutiveCounters(key *keyEntry, window uint, code1, code2 string) (uint64, bool, error) {
+	opts := hotplib.ValidateOpts{
+		Digits:    key.Digits,
+		Algorithm: key.Algorithm,
+	}
+
+	for i := uint64(0); i <= uint64(window); i++ {
+		counter := key.Counter + i
+		valid, err := hotplib.ValidateCustom(code1, counter, key.Key, opts)
+		if err != nil {
+			return 0, false, err
+		}
+		if !valid {
+			continue
+		}
+
+		valid, err = hotplib.ValidateCustom(code2, counter+1, key.Key, opts)
+		if err != nil {
+			return 0, false, err
+		}
+		if valid {
+			return counter, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+const pathKeyResyncHelpSyn = `
+Resynchronize the counter of an HOTP key.
+`
+
+const pathKeyResyncHelpDesc = `
+This path resynchronizes the counter of an HOTP key with a token whose counter
+has moved past the look-ahead window, for example because its button was
+pressed many times. Given two consecutive codes generated by the token, the
+counter values following the current one are searched up to the given window,
+and the key's counter is set to the value after the second code.
+`
@@ -70,6 +70,7 @@ func Backend(ctx context.Context, conf *logical.BackendConfig) (*backend, error)
 			b.pathBackup(),
 			b.pathRestore(),
 			b.pathTrim(),
+			b.pathAttestation(),
 			b.pathCacheConfig(),
 			b.pathConfigKeys(),
 		},
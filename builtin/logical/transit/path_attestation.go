@@ -0,0 +1,227 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package transit
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/keysutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const attestationFormat = "vault-transit-key-attestation/v1"
+
+const (
+	keyOriginGenerated = "generated"
+	keyOriginImported  = "imported"
+	keyOriginManaged   = "managed"
+	keyOriginUnknown   = "unknown"
+)
+
+// keyAttestation is the statement signed by the cluster about a version of
+// an asymmetric key
+type keyAttestation struct {
+	Format               string    `json:"format"`
+	ClusterID            string    `json:"cluster_id"`
+	MountAccessor        string    `json:"mount_accessor"`
+	Name                 string    `json:"name"`
+	Type                 string    `json:"type"`
+	Version              int       `json:"version"`
+	Origin               string    `json:"origin"`
+	CreationTime         time.Time `json:"creation_time"`
+	Exportable           bool      `json:"exportable"`
+	AllowPlaintextBackup bool      `json:"allow_plaintext_backup"`
+	NeverExported        bool      `json:"never_exported"`
+	Restored             bool      `json:"restored"`
+	PublicKey            string    `json:"public_key,omitempty"`
+	ManagedKeyID         string    `json:"managed_key_id,omitempty"`
+	Nonce                string    `json:"nonce,omitempty"`
+	IssueTime            time.Time `json:"issue_time"`
+}
+
+func (b *backend) pathAttestation() *framework.Path {
+	return &framework.Path{
+		Pattern: "keys/" + framework.GenericNameRegex("name") + "/attestation",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixTransit,
+			OperationVerb:   "read",
+			OperationSuffix: "key-attestation",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the key",
+			},
+			"version": {
+				Type:        framework.TypeInt,
+				Description: `Version of the key to attest. Defaults to the latest version.`,
+			},
+			"nonce": {
+				Type: framework.TypeString,
+				Description: `
+Value included as is in the statement, letting the verifier check that the
+statement was issued for its request.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: b.pathAttestationRead,
+		},
+
+		HelpSynopsis:    pathAttestationHelpSyn,
+		HelpDescription: pathAttestationHelpDesc,
+	}
+}
+
+func (b *backend) pathAttestationRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	attester, ok := b.System().(logical.AttestationSystemView)
+	if !ok {
+		return nil, fmt.Errorf("key attestation is not supported by the system view")
+	}
+
+	name := d.Get("name").(string)
+	p, _, err := b.GetPolicy(ctx, keysutil.PolicyRequest{
+		Storage: req.Storage,
+		Name:    name,
+	}, b.GetRandomReader())
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return logical.ErrorResponse("invalid key name"), logical.ErrInvalidRequest
+	}
+	if !b.System().CachingDisabled() {
+		p.Lock(false)
+	}
+	defer p.Unlock()
+
+	switch p.Type {
+	case keysutil.KeyType_ECDSA_P256, keysutil.KeyType_ECDSA_P384, keysutil.KeyType_ECDSA_P521, keysutil.KeyType_ED25519,
+		keysutil.KeyType_RSA2048, keysutil.KeyType_RSA3072, keysutil.KeyType_RSA4096, keysutil.KeyType_MANAGED_KEY:
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("key type %v does not support attestation", p.Type)), logical.ErrInvalidRequest
+	}
+	if p.Derived {
+		return logical.ErrorResponse("derived keys do not support attestation"), logical.ErrInvalidRequest
+	}
+
+	ver := d.Get("version").(int)
+	if ver == 0 {
+		ver = p.LatestVersion
+	}
+	entry, ok := p.Keys[strconv.Itoa(ver)]
+	if !ok || ver < p.MinAvailableVersion {
+		return logical.ErrorResponse(fmt.Sprintf("invalid key version %d", ver)), logical.ErrInvalidRequest
+	}
+
+	clusterID, err := b.System().ClusterID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &keyAttestation{
+		Format:               attestationFormat,
+		ClusterID:            clusterID,
+		MountAccessor:        req.MountAccessor,
+		Name:                 p.Name,
+		Type:                 p.Type.String(),
+		Version:              ver,
+		CreationTime:         entry.CreationTime,
+		Exportable:           p.Exportable,
+		AllowPlaintextBackup: p.AllowPlaintextBackup,
+		// Neither can be unset once set, so the key could never have left
+		// Vault unless one of them is set
+		NeverExported: !p.Exportable && !p.AllowPlaintextBackup,
+		Restored:      p.RestoreInfo != nil,
+		ManagedKeyID:  entry.ManagedKeyUUID,
+		Nonce:         d.Get("nonce").(string),
+		IssueTime:     time.Now().UTC(),
+	}
+	if statement.CreationTime.IsZero() {
+		statement.CreationTime = time.Unix(entry.DeprecatedCreationTime, 0)
+	}
+	statement.CreationTime = statement.CreationTime.UTC()
+
+	switch {
+	case p.Type == keysutil.KeyType_MANAGED_KEY:
+		statement.Origin = keyOriginManaged
+	case entry.Generated:
+		statement.Origin = keyOriginGenerated
+	case p.Imported:
+		statement.Origin = keyOriginImported
+	default:
+		// Versions generated before the origin was recorded can't be told
+		// apart from imported ones
+		statement.Origin = keyOriginUnknown
+	}
+
+	if p.Type != keysutil.KeyType_MANAGED_KEY {
+		statement.PublicKey, err = attestedPublicKey(p, entry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := json.Marshal(statement)
+	if err != nil {
+		return nil, err
+	}
+	sig, cert, err := attester.SignAttestation(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"statement":           string(raw),
+			"signature":           base64.StdEncoding.EncodeToString(sig),
+			"signature_algorithm": x509.ECDSAWithSHA384.String(),
+			"certificate": string(pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: cert,
+			})),
+		},
+	}, nil
+}
+
+// attestedPublicKey returns the public key of the version the way it is
+// returned when reading the key
+func attestedPublicKey(p *keysutil.Policy, entry keysutil.KeyEntry) (string, error) {
+	switch p.Type {
+	case keysutil.KeyType_RSA2048, keysutil.KeyType_RSA3072, keysutil.KeyType_RSA4096:
+		publicKey := entry.RSAPublicKey
+		if !entry.IsPrivateKeyMissing() {
+			publicKey = &entry.RSAKey.PublicKey
+		}
+		derBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+		if err != nil {
+			return "", fmt.Errorf("error marshaling RSA public key: %w", err)
+		}
+		return string(pem.EncodeToMemory(&pem.Block{
+			Type:  "PUBLIC KEY",
+			Bytes: derBytes,
+		})), nil
+	default:
+		return entry.FormattedPublicKey, nil
+	}
+}
+
+const pathAttestationHelpSyn = `Generate an attestation statement for a key`
+
+const pathAttestationHelpDesc = `
+This path returns a statement about the origin, creation time and
+exportability of a version of an asymmetric key, signed by the attestation
+key of the Vault cluster. The statement can be verified against the
+certificate returned by sys/attestation/certificate.
+`
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package transit_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/builtin/logical/transit"
+	vaulthttp "github.com/hashicorp/vault/http"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault"
+)
+
+func TestTransit_KeyAttestation(t *testing.T) {
+	coreConfig := &vault.CoreConfig{
+		LogicalBackends: map[string]logical.Factory{
+			"transit": transit.Factory,
+		},
+	}
+	cluster := vault.NewTestCluster(t, coreConfig, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	vault.TestWaitActive(t, cluster.Cores[0].Core)
+	client := cluster.Cores[0].Client
+
+	if err := client.Sys().Mount("transit", &api.MountInput{Type: "transit"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Logical().Write("transit/keys/signing", map[string]interface{}{
+		"type": "ecdsa-p256",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Logical().Write("transit/keys/aes", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := client.Logical().ReadWithData("transit/keys/signing/attestation", map[string][]string{
+		"nonce": {"abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certSecret, err := client.Logical().Read("sys/attestation/certificate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if certSecret.Data["certificate"] != secret.Data["certificate"] {
+		t.Fatalf("expected the certificate of the cluster, got: %v", secret.Data["certificate"])
+	}
+	block, _ := pem.Decode([]byte(certSecret.Data["certificate"].(string)))
+	if block == nil {
+		t.Fatal("failed to decode certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := []byte(secret.Data["statement"].(string))
+	sig, err := base64.StdEncoding.DecodeString(secret.Data["signature"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha512.Sum384(raw)
+	if !ecdsa.VerifyASN1(cert.PublicKey.(*ecdsa.PublicKey), digest[:], sig) {
+		t.Fatal("failed to verify the statement")
+	}
+
+	var statement map[string]interface{}
+	if err := json.Unmarshal(raw, &statement); err != nil {
+		t.Fatal(err)
+	}
+	keySecret, err := client.Logical().Read("transit/keys/signing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := keySecret.Data["keys"].(map[string]interface{})["1"].(map[string]interface{})["public_key"]
+	for k, v := range map[string]interface{}{
+		"name":           "signing",
+		"type":           "ecdsa-p256",
+		"version":        float64(1),
+		"origin":         "generated",
+		"never_exported": true,
+		"nonce":          "abc",
+		"public_key":     publicKey,
+	} {
+		if statement[k] != v {
+			t.Fatalf("expected %s to be %v, got: %v", k, v, statement[k])
+		}
+	}
+
+	// Making the key exportable is reflected in the statements
+	if _, err := client.Logical().Write("transit/keys/signing/config", map[string]interface{}{
+		"exportable": true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	secret, err = client.Logical().Read("transit/keys/signing/attestation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement = nil
+	if err := json.Unmarshal([]byte(secret.Data["statement"].(string)), &statement); err != nil {
+		t.Fatal(err)
+	}
+	if statement["never_exported"] != false || statement["exportable"] != true {
+		t.Fatalf("unexpected statement: %v", statement)
+	}
+
+	if _, err := client.Logical().Read("transit/keys/aes/attestation"); err == nil {
+		t.Fatal("expected symmetric keys not to be attested")
+	}
+	if _, err := client.Logical().ReadWithData("transit/keys/signing/attestation", map[string][]string{
+		"version": {"2"},
+	}); err == nil {
+		t.Fatal("expected missing versions not to be attested")
+	}
+}
@@ -14,8 +14,10 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-uuid"
 	v5 "github.com/hashicorp/vault/builtin/plugin/v5"
+	"github.com/hashicorp/vault/builtin/plugin/wasm"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
 	"github.com/hashicorp/vault/sdk/logical"
 	bplugin "github.com/hashicorp/vault/sdk/plugin"
 )
@@ -28,10 +30,27 @@ var (
 // Factory returns a configured plugin logical.Backend.
 func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
 	merr := &multierror.Error{}
-	_, ok := conf.Config["plugin_name"]
+	name, ok := conf.Config["plugin_name"]
 	if !ok {
 		return nil, fmt.Errorf("plugin_name not provided")
 	}
+
+	// WASM plugins are run in-process rather than with go-plugin
+	if pluginType, err := consts.ParsePluginType(conf.Config["plugin_type"]); err == nil {
+		runner, err := conf.System.LookupPluginVersion(ctx, name, pluginType, conf.Config["plugin_version"])
+		if err == nil && runner.Runtime == pluginutil.RuntimeWASM {
+			b, err := wasm.Backend(ctx, conf, runner)
+			if err != nil {
+				return nil, err
+			}
+			if err := b.Setup(ctx, conf); err != nil {
+				b.Cleanup(ctx)
+				return nil, err
+			}
+			return b, nil
+		}
+	}
+
 	b, err := v5.Backend(ctx, conf)
 	if err == nil {
 		if err := b.Setup(ctx, conf); err != nil {
@@ -239,6 +258,17 @@ func (b *PluginBackend) HandleExistenceCheck(ctx context.Context, req *logical.R
 	return
 }
 
+// HealthCheck is a thin wrapper implementation of HealthCheck that includes
+// automatic plugin reload.
+func (b *PluginBackend) HealthCheck(ctx context.Context, req *logical.HealthCheckRequest) error {
+	return b.lazyLoadBackend(ctx, req.Storage, func() error {
+		if checker, ok := b.Backend.(logical.HealthChecker); ok {
+			return checker.HealthCheck(ctx, req)
+		}
+		return logical.ErrUnsupportedOperation
+	})
+}
+
 // Initialize is intentionally a no-op here, the backend will instead be
 // initialized when it is lazily loaded.
 func (b *PluginBackend) Initialize(ctx context.Context, req *logical.InitializationRequest) error {
@@ -161,6 +161,16 @@ func (b *backend) InvalidateKey(ctx context.Context, key string) {
 	b.Backend.InvalidateKey(ctx, key)
 }
 
+// HealthCheck is a thin wrapper used to ensure we grab the lock for race purposes
+func (b *backend) HealthCheck(ctx context.Context, req *logical.HealthCheckRequest) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if checker, ok := b.Backend.(logical.HealthChecker); ok {
+		return checker.HealthCheck(ctx, req)
+	}
+	return logical.ErrUnsupportedOperation
+}
+
 func (b *backend) IsExternal() bool {
 	switch b.Backend.(type) {
 	case *plugin.BackendPluginClientV5:
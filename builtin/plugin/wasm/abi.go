@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package wasm
+
+import (
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// request is the request passed to vault_handle_request.
+type request struct {
+	Operation  logical.Operation      `json:"operation"`
+	Path       string                 `json:"path"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	MountPoint string                 `json:"mount_point"`
+	EntityID   string                 `json:"entity_id,omitempty"`
+}
+
+// response is the response returned by vault_handle_request. A non-empty
+// error is returned to the client as an error response.
+type response struct {
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Warnings []string               `json:"warnings,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+	Auth     *auth                  `json:"auth,omitempty"`
+}
+
+// auth is the result of a login request to an auth plugin. On renewals,
+// only the TTLs are used.
+type auth struct {
+	DisplayName string            `json:"display_name,omitempty"`
+	Policies    []string          `json:"policies,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Alias       string            `json:"alias,omitempty"`
+	TTL         seconds           `json:"ttl,omitempty"`
+	MaxTTL      seconds           `json:"max_ttl,omitempty"`
+	Renewable   bool              `json:"renewable,omitempty"`
+}
+
+func (a *auth) auth() *logical.Auth {
+	ret := &logical.Auth{
+		DisplayName: a.DisplayName,
+		Policies:    a.Policies,
+		Metadata:    a.Metadata,
+		LeaseOptions: logical.LeaseOptions{
+			TTL:       a.TTL.Duration(),
+			MaxTTL:    a.MaxTTL.Duration(),
+			Renewable: a.Renewable,
+		},
+	}
+	if a.Alias != "" {
+		ret.Alias = &logical.Alias{
+			Name:     a.Alias,
+			Metadata: a.Metadata,
+		}
+	}
+	return ret
+}
+
+// specialPaths are the paths returned by vault_special_paths.
+type specialPaths struct {
+	Root            []string `json:"root,omitempty"`
+	Unauthenticated []string `json:"unauthenticated,omitempty"`
+}
+
+// seconds is a duration in seconds.
+type seconds int64
+
+func (s seconds) Duration() time.Duration {
+	return time.Duration(s) * time.Second
+}
@@ -0,0 +1,377 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package wasm runs secrets and auth plugins compiled to WebAssembly modules
+// in-process, sandboxed by the wazero runtime.
+//
+// A plugin module exports the following functions:
+//
+//	vault_alloc(size i32) i32
+//	vault_handle_request(ptr i32, len i32) i64
+//	vault_special_paths() i64 (optional)
+//	vault_initialize() (optional)
+//
+// vault_alloc allocates the memory Vault writes the inputs of the other
+// functions and the results of the host functions to. The module owns that
+// memory. Results longer than a single value are returned as an i64 holding
+// the pointer to the result in its high 32 bits and its length in its low 32
+// bits, and must remain valid until the next call into the module.
+//
+// vault_handle_request takes the JSON encoded request and returns the JSON
+// encoded response. vault_special_paths returns the JSON encoded special
+// paths of the plugin.
+//
+// The module may import the host functions of the "vault" module, which give
+// access to the storage of the mount, to the logger and to cryptographic
+// primitives. The module has no access to the filesystem or to the network.
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+const (
+	// memoryLimitPages caps the memory of each instance of a plugin module
+	// to 64MiB.
+	memoryLimitPages = 1024
+
+	exportAlloc         = "vault_alloc"
+	exportHandleRequest = "vault_handle_request"
+	exportSpecialPaths  = "vault_special_paths"
+	exportInitialize    = "vault_initialize"
+)
+
+// requiredExports are the functions every plugin module must export.
+var requiredExports = []string{exportAlloc, exportHandleRequest}
+
+// Validate checks that the WASM module at the given path matches the SHA256
+// and exports the functions of a plugin.
+func Validate(ctx context.Context, path string, sha256 []byte) error {
+	code, err := readModule(path, sha256)
+	if err != nil {
+		return err
+	}
+
+	r := wazero.NewRuntime(ctx)
+	defer r.Close(ctx)
+
+	_, err = compileModule(ctx, r, code)
+	return err
+}
+
+// Backend returns the backend of the WASM plugin module run by the runner.
+func Backend(ctx context.Context, conf *logical.BackendConfig, runner *pluginutil.PluginRunner) (logical.Backend, error) {
+	var btype logical.BackendType
+	switch runner.Type {
+	case consts.PluginTypeSecrets:
+		btype = logical.TypeLogical
+	case consts.PluginTypeCredential:
+		btype = logical.TypeCredential
+	default:
+		return nil, fmt.Errorf("unsupported WASM plugin type: %s", runner.Type)
+	}
+
+	code, err := readModule(runner.Command, runner.Sha256)
+	if err != nil {
+		return nil, err
+	}
+
+	r := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(memoryLimitPages).
+		WithCloseOnContextDone(true))
+
+	b := &backend{
+		name:      runner.Name,
+		version:   runner.Version,
+		btype:     btype,
+		logger:    conf.Logger.Named(runner.Name),
+		system:    conf.System,
+		runtime:   r,
+		instances: make(chan api.Module, runtime.NumCPU()),
+	}
+
+	if err := b.init(ctx, code, runner); err != nil {
+		r.Close(ctx)
+		return nil, err
+	}
+	return b, nil
+}
+
+func readModule(path string, sum []byte) ([]byte, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if actual := sha256.Sum256(code); !bytes.Equal(actual[:], sum) {
+		return nil, errors.New("checksums did not match")
+	}
+	return code, nil
+}
+
+func compileModule(ctx context.Context, r wazero.Runtime, code []byte) (wazero.CompiledModule, error) {
+	compiled, err := r.CompileModule(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling WASM module: %w", err)
+	}
+
+	exports := compiled.ExportedFunctions()
+	for _, name := range requiredExports {
+		if _, ok := exports[name]; !ok {
+			return nil, fmt.Errorf("WASM module does not export the %q function", name)
+		}
+	}
+	return compiled, nil
+}
+
+// backend runs the requests in instances of the plugin module. Instances are
+// not safe for concurrent use, so idle instances are pooled and new ones are
+// created when none is available.
+type backend struct {
+	name    string
+	version string
+	btype   logical.BackendType
+	logger  log.Logger
+	system  logical.SystemView
+
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	config   wazero.ModuleConfig
+	paths    *logical.Paths
+
+	instances chan api.Module
+}
+
+var (
+	_ logical.Backend         = (*backend)(nil)
+	_ logical.PluginVersioner = (*backend)(nil)
+)
+
+func (b *backend) init(ctx context.Context, code []byte, runner *pluginutil.PluginRunner) error {
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, b.runtime); err != nil {
+		return err
+	}
+	if err := instantiateHostModule(ctx, b.runtime); err != nil {
+		return err
+	}
+
+	compiled, err := compileModule(ctx, b.runtime, code)
+	if err != nil {
+		return err
+	}
+	b.compiled = compiled
+
+	output := b.logger.StandardWriter(&log.StandardLoggerOptions{InferLevels: true})
+	config := wazero.NewModuleConfig().
+		WithName("").
+		WithStartFunctions("_initialize").
+		WithArgs(append([]string{runner.Name}, runner.Args...)...).
+		WithStdout(output).
+		WithStderr(output).
+		WithRandSource(rand.Reader).
+		WithSysWalltime().
+		WithSysNanotime()
+	for _, env := range runner.Env {
+		k, v, _ := strings.Cut(env, "=")
+		config = config.WithEnv(k, v)
+	}
+	b.config = config
+
+	b.paths = &logical.Paths{}
+	if _, ok := compiled.ExportedFunctions()[exportSpecialPaths]; ok {
+		out, err := b.call(ctx, exportSpecialPaths, nil, nil)
+		if err != nil {
+			return fmt.Errorf("error reading the special paths: %w", err)
+		}
+
+		var paths specialPaths
+		if err := json.Unmarshal(out, &paths); err != nil {
+			return fmt.Errorf("error decoding the special paths: %w", err)
+		}
+		b.paths.Root = paths.Root
+		b.paths.Unauthenticated = paths.Unauthenticated
+	}
+
+	return nil
+}
+
+// call calls a function of the plugin module with the input, and returns its
+// output. The storage is made available to the host functions called by the
+// module.
+func (b *backend) call(ctx context.Context, name string, input []byte, storage logical.Storage) ([]byte, error) {
+	var m api.Module
+	select {
+	case m = <-b.instances:
+	default:
+		var err error
+		m, err = b.runtime.InstantiateModule(ctx, b.compiled, b.config)
+		if err != nil {
+			return nil, fmt.Errorf("error instantiating WASM module: %w", err)
+		}
+	}
+
+	state := &callState{
+		storage: storage,
+		logger:  b.logger,
+	}
+	out, err := callModule(withCallState(ctx, state), m, name, input)
+	if state.err != nil {
+		err = state.err
+	}
+
+	// Instances that failed may be left in an inconsistent state, so they're
+	// discarded rather than reused.
+	if err != nil {
+		m.Close(ctx)
+		return nil, err
+	}
+	select {
+	case b.instances <- m:
+	default:
+		m.Close(ctx)
+	}
+	return out, nil
+}
+
+func callModule(ctx context.Context, m api.Module, name string, input []byte) ([]byte, error) {
+	fn := m.ExportedFunction(name)
+	if fn == nil {
+		return nil, fmt.Errorf("WASM module does not export the %q function", name)
+	}
+
+	var params []uint64
+	if input != nil {
+		packed, err := writeGuest(ctx, m, input)
+		if err != nil {
+			return nil, err
+		}
+		params = []uint64{packed >> 32, packed & 0xffffffff}
+	}
+
+	results, err := fn.Call(ctx, params...)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	return readGuest(m, results[0])
+}
+
+func (b *backend) Initialize(ctx context.Context, req *logical.InitializationRequest) error {
+	if _, ok := b.compiled.ExportedFunctions()[exportInitialize]; !ok {
+		return nil
+	}
+	_, err := b.call(ctx, exportInitialize, nil, req.Storage)
+	return err
+}
+
+func (b *backend) HandleRequest(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+	switch req.Operation {
+	case logical.RollbackOperation:
+		// Plugin modules have no state to roll back outside of their storage
+		return nil, nil
+	case logical.RenewOperation:
+		if req.Auth == nil {
+			return nil, logical.ErrUnsupportedOperation
+		}
+	case logical.RevokeOperation:
+		// Plugin modules can't create leases
+		return nil, logical.ErrUnsupportedOperation
+	}
+
+	in, err := json.Marshal(&request{
+		Operation:  req.Operation,
+		Path:       req.Path,
+		Data:       req.Data,
+		MountPoint: req.MountPoint,
+		EntityID:   req.EntityID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := b.call(ctx, exportHandleRequest, in, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	var wresp response
+	if err := json.Unmarshal(out, &wresp); err != nil {
+		return nil, fmt.Errorf("error decoding the response: %w", err)
+	}
+	if wresp.Error != "" {
+		return logical.ErrorResponse(wresp.Error), nil
+	}
+
+	resp := &logical.Response{
+		Data:     wresp.Data,
+		Warnings: wresp.Warnings,
+	}
+	if wresp.Auth != nil {
+		switch req.Operation {
+		case logical.RenewOperation:
+			// Only the lease of the token can be changed on renewal
+			resp.Auth = req.Auth
+			resp.Auth.TTL = wresp.Auth.TTL.Duration()
+			resp.Auth.MaxTTL = wresp.Auth.MaxTTL.Duration()
+		default:
+			resp.Auth = wresp.Auth.auth()
+		}
+	}
+	return resp, nil
+}
+
+func (b *backend) SpecialPaths() *logical.Paths {
+	return b.paths
+}
+
+func (b *backend) System() logical.SystemView {
+	return b.system
+}
+
+func (b *backend) Logger() log.Logger {
+	return b.logger
+}
+
+func (b *backend) HandleExistenceCheck(context.Context, *logical.Request) (bool, bool, error) {
+	return false, false, nil
+}
+
+// Cleanup closes the runtime, and with it every instance of the module.
+func (b *backend) Cleanup(ctx context.Context) {
+	if err := b.runtime.Close(ctx); err != nil {
+		b.logger.Warn("error closing the WASM runtime", "error", err)
+	}
+}
+
+func (b *backend) InvalidateKey(context.Context, string) {}
+
+func (b *backend) Setup(_ context.Context, conf *logical.BackendConfig) error {
+	b.system = conf.System
+	return nil
+}
+
+func (b *backend) Type() logical.BackendType {
+	return b.btype
+}
+
+func (b *backend) PluginVersion() logical.PluginVersion {
+	return logical.PluginVersion{Version: b.version}
+}
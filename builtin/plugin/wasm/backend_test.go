@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package wasm
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+var (
+	testPluginOnce sync.Once
+	testPluginPath string
+	testPluginErr  error
+	testPluginOut  []byte
+)
+
+// testPlugin compiles the kv test plugin, and returns its path and SHA256.
+func testPlugin(t *testing.T) (string, []byte) {
+	t.Helper()
+
+	testPluginOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "vault-wasm-plugin")
+		if err != nil {
+			testPluginErr = err
+			return
+		}
+		testPluginPath = filepath.Join(dir, "kv.wasm")
+
+		cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", testPluginPath, ".")
+		cmd.Dir = filepath.Join("testdata", "kv")
+		cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+		testPluginOut, testPluginErr = cmd.CombinedOutput()
+	})
+	if testPluginErr != nil {
+		// Exporting functions from Go WASM modules requires Go 1.24
+		t.Skipf("error compiling the test plugin: %v\n%s", testPluginErr, testPluginOut)
+	}
+
+	code, err := os.ReadFile(testPluginPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(code)
+	return testPluginPath, sum[:]
+}
+
+func testBackend(t *testing.T, pluginType consts.PluginType) logical.Backend {
+	t.Helper()
+
+	path, sum := testPlugin(t)
+	conf := &logical.BackendConfig{
+		Logger: log.NewNullLogger(),
+		System: logical.TestSystemView(),
+	}
+	b, err := Backend(context.Background(), conf, &pluginutil.PluginRunner{
+		Name:    "kv",
+		Type:    pluginType,
+		Version: "v1.0.0",
+		Command: path,
+		Sha256:  sum,
+		Runtime: pluginutil.RuntimeWASM,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Setup(context.Background(), conf); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { b.Cleanup(context.Background()) })
+	return b
+}
+
+func testRequest(t *testing.T, b logical.Backend, storage logical.Storage, op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+	t.Helper()
+
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: op,
+		Path:      path,
+		Data:      data,
+		Storage:   storage,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.IsError() {
+		t.Fatal(resp.Error())
+	}
+	return resp
+}
+
+func TestBackend_Storage(t *testing.T) {
+	b := testBackend(t, consts.PluginTypeSecrets)
+	storage := &logical.InmemStorage{}
+
+	if b.Type() != logical.TypeLogical {
+		t.Fatalf("bad: %s", b.Type())
+	}
+	if v := b.(logical.PluginVersioner).PluginVersion().Version; v != "v1.0.0" {
+		t.Fatalf("bad: %s", v)
+	}
+
+	data := map[string]interface{}{"foo": "bar"}
+	testRequest(t, b, storage, logical.CreateOperation, "kv/a", data)
+	testRequest(t, b, storage, logical.CreateOperation, "kv/b", data)
+
+	entry, err := storage.Get(context.Background(), "kv/a")
+	if err != nil || entry == nil {
+		t.Fatalf("bad: %v, %v", entry, err)
+	}
+
+	resp := testRequest(t, b, storage, logical.ReadOperation, "kv/a", nil)
+	if !reflect.DeepEqual(resp.Data, data) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	resp = testRequest(t, b, storage, logical.ListOperation, "kv/", nil)
+	if !reflect.DeepEqual(resp.Data["keys"], []interface{}{"a", "b"}) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	testRequest(t, b, storage, logical.DeleteOperation, "kv/a", nil)
+	resp = testRequest(t, b, storage, logical.ReadOperation, "kv/a", nil)
+	if resp.Data != nil {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	resp, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "unknown",
+		Storage:   storage,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsError() || resp.Error().Error() != "unsupported path" {
+		t.Fatalf("bad: %#v", resp)
+	}
+}
+
+func TestBackend_Crypto(t *testing.T) {
+	b := testBackend(t, consts.PluginTypeSecrets)
+	storage := &logical.InmemStorage{}
+
+	first := testRequest(t, b, storage, logical.ReadOperation, "random", nil).Data["random"]
+	second := testRequest(t, b, storage, logical.ReadOperation, "random", nil).Data["random"]
+	if len(first.(string)) != 32 || first == second {
+		t.Fatalf("bad: %v, %v", first, second)
+	}
+
+	resp := testRequest(t, b, storage, logical.UpdateOperation, "hmac", map[string]interface{}{
+		"key":     "key",
+		"message": "message",
+	})
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte("message"))
+	if expected := hex.EncodeToString(mac.Sum(nil)); resp.Data["hmac"] != expected {
+		t.Fatalf("expected %s, got %v", expected, resp.Data["hmac"])
+	}
+}
+
+func TestBackend_Login(t *testing.T) {
+	b := testBackend(t, consts.PluginTypeCredential)
+	storage := &logical.InmemStorage{}
+
+	if b.Type() != logical.TypeCredential {
+		t.Fatalf("bad: %s", b.Type())
+	}
+	if paths := b.SpecialPaths(); !reflect.DeepEqual(paths.Unauthenticated, []string{"login"}) {
+		t.Fatalf("bad: %#v", paths)
+	}
+
+	testRequest(t, b, storage, logical.UpdateOperation, "users/bob", map[string]interface{}{
+		"password": "secret",
+		"policies": []string{"default", "foo"},
+	})
+
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "login",
+		Data:      map[string]interface{}{"username": "bob", "password": "wrong"},
+		Storage:   storage,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsError() {
+		t.Fatalf("expected an error response, got: %#v", resp)
+	}
+
+	resp = testRequest(t, b, storage, logical.UpdateOperation, "login", map[string]interface{}{
+		"username": "bob",
+		"password": "secret",
+	})
+	auth := resp.Auth
+	if auth == nil || auth.DisplayName != "bob" || auth.Alias.Name != "bob" || auth.TTL != time.Hour || !auth.Renewable {
+		t.Fatalf("bad: %#v", auth)
+	}
+	if !reflect.DeepEqual(auth.Policies, []string{"default", "foo"}) {
+		t.Fatalf("bad: %#v", auth.Policies)
+	}
+
+	resp, err = b.HandleRequest(context.Background(), logical.RenewAuthRequest("login", auth, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Auth.TTL != 2*time.Hour || !reflect.DeepEqual(resp.Auth.Policies, auth.Policies) {
+		t.Fatalf("bad: %#v", resp.Auth)
+	}
+}
+
+func TestBackend_Concurrent(t *testing.T) {
+	b := testBackend(t, consts.PluginTypeSecrets)
+	storage := &logical.InmemStorage{}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path := fmt.Sprintf("kv/%d", i)
+			_, err := b.HandleRequest(context.Background(), &logical.Request{
+				Operation: logical.CreateOperation,
+				Path:      path,
+				Data:      map[string]interface{}{"i": i},
+				Storage:   storage,
+			})
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := storage.List(context.Background(), "kv/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 32 {
+		t.Fatalf("bad: %v", keys)
+	}
+}
+
+func TestBackend_Abort(t *testing.T) {
+	b := testBackend(t, consts.PluginTypeSecrets)
+	storage := &logical.InmemStorage{}
+
+	_, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "panic",
+		Storage:   storage,
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// Storage errors abort the request
+	storage.Underlying().FailGet(true)
+	_, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "kv/foo",
+		Storage:   storage,
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	storage.Underlying().FailGet(false)
+
+	// The failed instances are replaced
+	testRequest(t, b, storage, logical.ReadOperation, "kv/foo", nil)
+}
+
+func TestBackend_Checksum(t *testing.T) {
+	path, _ := testPlugin(t)
+
+	if err := Validate(context.Background(), path, []byte("bad")); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	notWASM := filepath.Join(t.TempDir(), "plugin")
+	if err := os.WriteFile(notWASM, []byte("plugin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("plugin"))
+	if err := Validate(context.Background(), notWASM, sum[:]); err == nil {
+		t.Fatal("expected an error")
+	}
+}
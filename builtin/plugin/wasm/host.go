@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package wasm
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// hostModule is the name of the module the host functions are imported from.
+const hostModule = "vault"
+
+var errNoStorage = errors.New("storage is not available")
+
+// callState is the state of a call into a plugin module, made available to
+// the host functions through the context.
+type callState struct {
+	storage logical.Storage
+	logger  log.Logger
+
+	// err is the error that aborted the call.
+	err error
+}
+
+type callStateKey struct{}
+
+func withCallState(ctx context.Context, state *callState) context.Context {
+	return context.WithValue(ctx, callStateKey{}, state)
+}
+
+func getCallState(ctx context.Context) *callState {
+	return ctx.Value(callStateKey{}).(*callState)
+}
+
+// abort stops the call into the module with the error.
+func (s *callState) abort(err error) {
+	s.err = err
+	panic(err)
+}
+
+func (s *callState) mustStorage() logical.Storage {
+	if s.storage == nil {
+		s.abort(errNoStorage)
+	}
+	return s.storage
+}
+
+func (s *callState) mustRead(m api.Module, ptr, size uint32) []byte {
+	buf, ok := m.Memory().Read(ptr, size)
+	if !ok {
+		s.abort(fmt.Errorf("memory read out of range: %d+%d", ptr, size))
+	}
+	return buf
+}
+
+func (s *callState) mustWrite(ctx context.Context, m api.Module, data []byte) uint64 {
+	packed, err := writeGuest(ctx, m, data)
+	if err != nil {
+		s.abort(err)
+	}
+	return packed
+}
+
+// instantiateHostModule instantiates the host functions the plugin modules
+// may import. Errors of the host functions abort the call into the module.
+func instantiateHostModule(ctx context.Context, r wazero.Runtime) error {
+	_, err := r.NewHostModuleBuilder(hostModule).
+		NewFunctionBuilder().WithFunc(storageGet).Export("storage_get").
+		NewFunctionBuilder().WithFunc(storagePut).Export("storage_put").
+		NewFunctionBuilder().WithFunc(storageDelete).Export("storage_delete").
+		NewFunctionBuilder().WithFunc(storageList).Export("storage_list").
+		NewFunctionBuilder().WithFunc(logMessage).Export("log").
+		NewFunctionBuilder().WithFunc(randomBytes).Export("random_bytes").
+		NewFunctionBuilder().WithFunc(hmacSHA256).Export("hmac_sha256").
+		Instantiate(ctx)
+	return err
+}
+
+// storageGet returns the value of the key, or zero if it doesn't exist.
+func storageGet(ctx context.Context, m api.Module, keyPtr, keyLen uint32) uint64 {
+	s := getCallState(ctx)
+	key := s.mustRead(m, keyPtr, keyLen)
+
+	entry, err := s.mustStorage().Get(ctx, string(key))
+	if err != nil {
+		s.abort(err)
+	}
+	if entry == nil {
+		return 0
+	}
+	return s.mustWrite(ctx, m, entry.Value)
+}
+
+func storagePut(ctx context.Context, m api.Module, keyPtr, keyLen, valuePtr, valueLen uint32) {
+	s := getCallState(ctx)
+	key := s.mustRead(m, keyPtr, keyLen)
+	value := s.mustRead(m, valuePtr, valueLen)
+
+	err := s.mustStorage().Put(ctx, &logical.StorageEntry{
+		Key:   string(key),
+		Value: append([]byte(nil), value...),
+	})
+	if err != nil {
+		s.abort(err)
+	}
+}
+
+func storageDelete(ctx context.Context, m api.Module, keyPtr, keyLen uint32) {
+	s := getCallState(ctx)
+	key := s.mustRead(m, keyPtr, keyLen)
+
+	if err := s.mustStorage().Delete(ctx, string(key)); err != nil {
+		s.abort(err)
+	}
+}
+
+// storageList returns the JSON encoded keys under the prefix.
+func storageList(ctx context.Context, m api.Module, prefixPtr, prefixLen uint32) uint64 {
+	s := getCallState(ctx)
+	prefix := s.mustRead(m, prefixPtr, prefixLen)
+
+	keys, err := s.mustStorage().List(ctx, string(prefix))
+	if err != nil {
+		s.abort(err)
+	}
+	if keys == nil {
+		keys = []string{}
+	}
+	out, err := json.Marshal(keys)
+	if err != nil {
+		s.abort(err)
+	}
+	return s.mustWrite(ctx, m, out)
+}
+
+// logMessage logs the message at the level, numbered as the hclog levels.
+func logMessage(ctx context.Context, m api.Module, level, msgPtr, msgLen uint32) {
+	s := getCallState(ctx)
+	msg := s.mustRead(m, msgPtr, msgLen)
+
+	switch lvl := log.Level(level); lvl {
+	case log.Trace, log.Debug, log.Info, log.Warn, log.Error:
+		s.logger.Log(lvl, string(msg))
+	default:
+		s.logger.Info(string(msg))
+	}
+}
+
+// randomBytes fills the buffer with cryptographically secure random bytes.
+func randomBytes(ctx context.Context, m api.Module, ptr, size uint32) {
+	s := getCallState(ctx)
+	buf := s.mustRead(m, ptr, size)
+
+	if _, err := rand.Read(buf); err != nil {
+		s.abort(err)
+	}
+}
+
+// hmacSHA256 writes the HMAC-SHA256 of the message to the 32 bytes at the
+// output pointer.
+func hmacSHA256(ctx context.Context, m api.Module, keyPtr, keyLen, msgPtr, msgLen, outPtr uint32) {
+	s := getCallState(ctx)
+	key := s.mustRead(m, keyPtr, keyLen)
+	msg := s.mustRead(m, msgPtr, msgLen)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(msg)
+	if !m.Memory().Write(outPtr, mac.Sum(nil)) {
+		s.abort(fmt.Errorf("memory write out of range: %d+%d", outPtr, sha256.Size))
+	}
+}
+
+// writeGuest writes the data to memory allocated by the module, and returns
+// its packed pointer and length.
+func writeGuest(ctx context.Context, m api.Module, data []byte) (uint64, error) {
+	results, err := m.ExportedFunction(exportAlloc).Call(ctx, uint64(len(data)))
+	if err != nil {
+		return 0, err
+	}
+	ptr := uint32(results[0])
+	if !m.Memory().Write(ptr, data) {
+		return 0, fmt.Errorf("memory write out of range: %d+%d", ptr, len(data))
+	}
+	return uint64(ptr)<<32 | uint64(len(data)), nil
+}
+
+// readGuest copies the data at the packed pointer and length out of the
+// memory of the module.
+func readGuest(m api.Module, packed uint64) ([]byte, error) {
+	ptr, size := uint32(packed>>32), uint32(packed)
+	buf, ok := m.Memory().Read(ptr, size)
+	if !ok {
+		return nil, fmt.Errorf("memory read out of range: %d+%d", ptr, size)
+	}
+	return append([]byte(nil), buf...), nil
+}
@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build wasip1
+
+// kv is a WASM plugin used to test the WASM plugin runtime. It stores the
+// data written to kv/<key> and logs in the users written to users/<name>.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"unsafe"
+)
+
+//go:wasmimport vault storage_get
+func storageGet(keyPtr, keyLen uint32) uint64
+
+//go:wasmimport vault storage_put
+func storagePut(keyPtr, keyLen, valuePtr, valueLen uint32)
+
+//go:wasmimport vault storage_delete
+func storageDelete(keyPtr, keyLen uint32)
+
+//go:wasmimport vault storage_list
+func storageList(prefixPtr, prefixLen uint32) uint64
+
+//go:wasmimport vault log
+func logMessage(level, msgPtr, msgLen uint32)
+
+//go:wasmimport vault random_bytes
+func randomBytes(ptr, size uint32)
+
+//go:wasmimport vault hmac_sha256
+func hmacSHA256(keyPtr, keyLen, msgPtr, msgLen, outPtr uint32)
+
+// allocated keeps the memory allocated by the host alive.
+var allocated = map[uint32][]byte{}
+
+// output keeps the last returned output alive until the next call.
+var output []byte
+
+//go:wasmexport vault_alloc
+func alloc(size uint32) uint32 {
+	buf := make([]byte, size+1)
+	ptr := uint32(uintptr(unsafe.Pointer(&buf[0])))
+	allocated[ptr] = buf
+	return ptr
+}
+
+func take(packed uint64) []byte {
+	ptr, size := uint32(packed>>32), uint32(packed)
+	buf := allocated[ptr]
+	delete(allocated, ptr)
+	return buf[:size]
+}
+
+func ptrLen(b []byte) (uint32, uint32) {
+	if len(b) == 0 {
+		return 0, 0
+	}
+	return uint32(uintptr(unsafe.Pointer(&b[0]))), uint32(len(b))
+}
+
+func ret(v interface{}) uint64 {
+	output, _ = json.Marshal(v)
+	ptr, size := ptrLen(output)
+	return uint64(ptr)<<32 | uint64(size)
+}
+
+func get(key string) []byte {
+	k, kl := ptrLen([]byte(key))
+	packed := storageGet(k, kl)
+	if packed == 0 {
+		return nil
+	}
+	return take(packed)
+}
+
+func put(key string, value []byte) {
+	k, kl := ptrLen([]byte(key))
+	v, vl := ptrLen(value)
+	storagePut(k, kl, v, vl)
+}
+
+type request struct {
+	Operation string                 `json:"operation"`
+	Path      string                 `json:"path"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+type response struct {
+	Data  map[string]interface{} `json:"data,omitempty"`
+	Error string                 `json:"error,omitempty"`
+	Auth  map[string]interface{} `json:"auth,omitempty"`
+}
+
+//go:wasmexport vault_special_paths
+func specialPaths() uint64 {
+	return ret(map[string][]string{"unauthenticated": {"login"}})
+}
+
+//go:wasmexport vault_handle_request
+func handleRequest(ptr, size uint32) uint64 {
+	var req request
+	if err := json.Unmarshal(take(uint64(ptr)<<32|uint64(size)), &req); err != nil {
+		return ret(response{Error: err.Error()})
+	}
+
+	switch {
+	case req.Path == "random" && req.Operation == "read":
+		buf := make([]byte, 16)
+		p, l := ptrLen(buf)
+		randomBytes(p, l)
+		return ret(response{Data: map[string]interface{}{"random": hex.EncodeToString(buf)}})
+
+	case req.Path == "hmac" && req.Operation == "update":
+		key, _ := req.Data["key"].(string)
+		msg, _ := req.Data["message"].(string)
+		out := make([]byte, 32)
+		k, kl := ptrLen([]byte(key))
+		m, ml := ptrLen([]byte(msg))
+		o, _ := ptrLen(out)
+		hmacSHA256(k, kl, m, ml, o)
+		return ret(response{Data: map[string]interface{}{"hmac": hex.EncodeToString(out)}})
+
+	case req.Path == "log" && req.Operation == "update":
+		msg, _ := req.Data["message"].(string)
+		m, ml := ptrLen([]byte(msg))
+		logMessage(3, m, ml)
+		return ret(response{})
+
+	case req.Path == "panic":
+		panic("panic")
+
+	case req.Path == "kv/" && req.Operation == "list":
+		p, pl := ptrLen([]byte("kv/"))
+		var keys []string
+		json.Unmarshal(take(storageList(p, pl)), &keys)
+		return ret(response{Data: map[string]interface{}{"keys": keys}})
+
+	case strings.HasPrefix(req.Path, "kv/"):
+		switch req.Operation {
+		case "read":
+			value := get(req.Path)
+			if value == nil {
+				return ret(response{})
+			}
+			var data map[string]interface{}
+			json.Unmarshal(value, &data)
+			return ret(response{Data: data})
+		case "create", "update":
+			value, _ := json.Marshal(req.Data)
+			put(req.Path, value)
+			return ret(response{})
+		case "delete":
+			k, kl := ptrLen([]byte(req.Path))
+			storageDelete(k, kl)
+			return ret(response{})
+		}
+
+	case strings.HasPrefix(req.Path, "users/") && req.Operation == "update":
+		value, _ := json.Marshal(req.Data)
+		put(req.Path, value)
+		return ret(response{})
+
+	case req.Path == "login" && req.Operation == "update":
+		username, _ := req.Data["username"].(string)
+		password, _ := req.Data["password"].(string)
+		var user struct {
+			Password string   `json:"password"`
+			Policies []string `json:"policies"`
+		}
+		value := get("users/" + username)
+		if value == nil {
+			return ret(response{Error: "invalid username or password"})
+		}
+		json.Unmarshal(value, &user)
+		if user.Password != password {
+			return ret(response{Error: "invalid username or password"})
+		}
+		return ret(response{Auth: map[string]interface{}{
+			"display_name": username,
+			"policies":     user.Policies,
+			"alias":        username,
+			"ttl":          3600,
+			"renewable":    true,
+		}})
+
+	case req.Path == "login" && req.Operation == "renew":
+		return ret(response{Auth: map[string]interface{}{"ttl": 7200}})
+	}
+
+	return ret(response{Error: "unsupported path"})
+}
+
+func main() {}
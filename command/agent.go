@@ -6,6 +6,7 @@ package command
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -25,7 +26,10 @@ import (
 	"github.com/hashicorp/go-secure-stdlib/parseutil"
 	"github.com/hashicorp/go-secure-stdlib/reloadutil"
 	"github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/command/agent/certstore"
 	agentConfig "github.com/hashicorp/vault/command/agent/config"
+	"github.com/hashicorp/vault/command/agent/exec"
+	"github.com/hashicorp/vault/command/agent/sds"
 	"github.com/hashicorp/vault/command/agent/template"
 	"github.com/hashicorp/vault/command/agentproxyshared"
 	"github.com/hashicorp/vault/command/agentproxyshared/auth"
@@ -703,6 +707,7 @@ func (c *AgentCommand) Run(args []string) int {
 			MaxBackoff:                   config.AutoAuth.Method.MaxBackoff,
 			EnableReauthOnNewCredentials: config.AutoAuth.EnableReauthOnNewCredentials,
 			EnableTemplateTokenCh:        enableTokenCh,
+			EnableExecTokenCh:            config.Exec != nil,
 			Token:                        previousToken,
 			ExitOnError:                  config.AutoAuth.Method.ExitOnError,
 			UserAgent:                    useragent.AgentAutoAuthString(),
@@ -715,6 +720,50 @@ func (c *AgentCommand) Run(args []string) int {
 			ExitAfterAuth: config.ExitAfterAuth,
 		})
 
+		// onRender notifies the subsystems using the files rendered by
+		// templates
+		var onRender []func([]string)
+
+		var sdsServer *sds.Server
+		if config.EnvoySDS != nil {
+			sdsServer = sds.NewServer(&sds.ServerConfig{
+				Logger: c.logger.Named("envoy_sds.server"),
+				Config: config.EnvoySDS,
+			})
+			onRender = append(onRender, sdsServer.Rendered)
+			info["envoy sds"] = "unix://" + config.EnvoySDS.Address
+			infoKeys = append(infoKeys, "envoy sds")
+		}
+
+		var certStoreServer *certstore.Server
+		if config.CertificateStore != nil {
+			certStoreServer = certstore.NewServer(&certstore.ServerConfig{
+				Logger: c.logger.Named("certificate_store.server"),
+				Config: config.CertificateStore,
+			})
+			onRender = append(onRender, certStoreServer.Rendered)
+			var names []string
+			for _, cert := range config.CertificateStore.Certificates {
+				names = append(names, cert.Name)
+			}
+			info["certificate store"] = strings.Join(names, ", ")
+			infoKeys = append(infoKeys, "certificate store")
+		}
+
+		var es *exec.Server
+		if config.Exec != nil {
+			es = exec.NewServer(&exec.ServerConfig{
+				Logger:      c.logger.Named("exec.server"),
+				AgentConfig: c.config,
+				Namespace:   templateNamespace,
+				LogLevel:    c.logger.GetLevel(),
+				LogWriter:   c.logWriter,
+			})
+			onRender = append(onRender, es.TemplatesRendered)
+			info["exec"] = strings.Join(config.Exec.Command, " ")
+			infoKeys = append(infoKeys, "exec")
+		}
+
 		ts := template.NewServer(&template.ServerConfig{
 			Logger:        c.logger.Named("template.server"),
 			LogLevel:      c.logger.GetLevel(),
@@ -722,6 +771,11 @@ func (c *AgentCommand) Run(args []string) int {
 			AgentConfig:   c.config,
 			Namespace:     templateNamespace,
 			ExitAfterAuth: config.ExitAfterAuth,
+			OnRender: func(destinations []string) {
+				for _, fn := range onRender {
+					fn(destinations)
+				}
+			},
 		})
 
 		g.Add(func() error {
@@ -778,6 +832,34 @@ func (c *AgentCommand) Run(args []string) int {
 			ts.Stop()
 		})
 
+		if sdsServer != nil {
+			g.Add(func() error {
+				return sdsServer.Run(ctx)
+			}, func(error) {
+				cancelFunc()
+			})
+		}
+
+		if certStoreServer != nil {
+			g.Add(func() error {
+				return certStoreServer.Run(ctx)
+			}, func(error) {
+				cancelFunc()
+			})
+		}
+
+		if es != nil {
+			g.Add(func() error {
+				return es.Run(ctx, ah.ExecTokenCh)
+			}, func(error) {
+				// Let the lease cache know this is a shutdown; no need to evict
+				// everything
+				if leaseCache != nil {
+					leaseCache.SetShuttingDown(true)
+				}
+				cancelFunc()
+			})
+		}
 	}
 
 	// Server configuration output
@@ -813,7 +895,11 @@ func (c *AgentCommand) Run(args []string) int {
 	}()
 
 	var exitCode int
-	if err := g.Run(); err != nil {
+	var processExitError *exec.ProcessExitError
+	if err := g.Run(); errors.As(err, &processExitError) {
+		// Exit with the exit code of the child process
+		exitCode = processExitError.ExitCode
+	} else if err != nil {
 		c.logger.Error("runtime error encountered", "error", err)
 		c.UI.Error("Error encountered during run, refer to logs for more details.")
 		exitCode = 1
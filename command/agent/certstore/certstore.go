@@ -0,0 +1,232 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package certstore installs the certificates rendered by the templates of
+// Vault Agent, with their private keys, to the certificate store of the
+// operating system: the Windows certificate store, or the macOS keychain.
+// Applications using the certificates of the store, such as Windows services
+// using SChannel, can then use certificates issued by Vault without handling
+// files.
+package certstore
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/vault/command/agent/config"
+)
+
+// removeExpiredInterval is how often the expired certificates are removed
+// from the store, in addition to when certificates are installed.
+const removeExpiredInterval = time.Hour
+
+// store is a certificate store of the operating system.
+type store interface {
+	// Install installs the certificate chain, and the private key of its
+	// first certificate, with the name.
+	Install(name string, chain []*x509.Certificate, key crypto.PrivateKey) error
+
+	// RemoveExpired removes the certificates installed with the name, and
+	// with the common name, that expired before now. It returns the number
+	// of certificates removed.
+	RemoveExpired(name, commonName string, now time.Time) (int, error)
+
+	Close() error
+}
+
+// ServerConfig is the configuration of the certificate store Server
+type ServerConfig struct {
+	Logger hclog.Logger
+	Config *config.CertificateStore
+}
+
+// Server installs the certificates of its configuration to the certificate
+// store
+type Server struct {
+	logger hclog.Logger
+	config *config.CertificateStore
+
+	// openStore opens the certificate store, it's replaced in tests
+	openStore func(*config.CertificateStore) (store, error)
+
+	// installed holds the leaf certificates installed, indexed by name
+	installed map[string]*x509.Certificate
+
+	// rendered holds the destinations rendered since it was last read, and
+	// renderedCh is notified when it changes
+	renderedLock sync.Mutex
+	rendered     []string
+	renderedCh   chan struct{}
+}
+
+// NewServer returns a new configured server
+func NewServer(conf *ServerConfig) *Server {
+	return &Server{
+		logger:     conf.Logger,
+		config:     conf.Config,
+		openStore:  openSystemStore,
+		installed:  make(map[string]*x509.Certificate),
+		renderedCh: make(chan struct{}, 1),
+	}
+}
+
+// Run installs the certificates whose files were already rendered, and then
+// the ones rendered, until the context is done.
+func (s *Server) Run(ctx context.Context) error {
+	s.logger.Info("starting certificate store server")
+	defer s.logger.Info("certificate store server stopped")
+
+	st, err := s.openStore(s.config)
+	if err != nil {
+		return fmt.Errorf("certificate store server: error opening store: %w", err)
+	}
+	defer st.Close()
+
+	for _, cert := range s.config.Certificates {
+		if err := s.install(st, cert); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warn("error installing certificate", "certificate", cert.Name, "error", err)
+		}
+	}
+
+	ticker := time.NewTicker(removeExpiredInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-s.renderedCh:
+			s.renderedLock.Lock()
+			rendered := make(map[string]bool, len(s.rendered))
+			for _, dest := range s.rendered {
+				rendered[filepath.Clean(dest)] = true
+			}
+			s.rendered = nil
+			s.renderedLock.Unlock()
+
+			for _, cert := range s.config.Certificates {
+				if !rendered[filepath.Clean(cert.CertFile)] && !rendered[filepath.Clean(cert.KeyFile)] {
+					continue
+				}
+				if err := s.install(st, cert); err != nil {
+					// The certificate and the key may be rendered by
+					// different templates, so wait for the others to be
+					// rendered
+					s.logger.Warn("error installing certificate, keeping the previous version", "certificate", cert.Name, "error", err)
+				}
+			}
+
+		case <-ticker.C:
+			for _, cert := range s.config.Certificates {
+				s.removeExpired(st, cert.Name)
+			}
+		}
+	}
+}
+
+// Rendered installs the certificates using the files at the destinations. It
+// is called when templates are rendered.
+func (s *Server) Rendered(destinations []string) {
+	s.renderedLock.Lock()
+	s.rendered = append(s.rendered, destinations...)
+	s.renderedLock.Unlock()
+
+	select {
+	case s.renderedCh <- struct{}{}:
+	default:
+	}
+}
+
+// install reads the files of the certificate, and installs it if it changed.
+func (s *Server) install(st store, cert *config.CertificateStoreCertificate) error {
+	chain, key, err := readCertificate(cert)
+	if err != nil {
+		return err
+	}
+
+	if previous, ok := s.installed[cert.Name]; ok && bytes.Equal(previous.Raw, chain[0].Raw) {
+		return nil
+	}
+	if err := st.Install(cert.Name, chain, key); err != nil {
+		return err
+	}
+	s.installed[cert.Name] = chain[0]
+	s.logger.Info("certificate installed", "certificate", cert.Name, "serial_number", chain[0].SerialNumber.String(), "not_after", chain[0].NotAfter)
+
+	s.removeExpired(st, cert.Name)
+	return nil
+}
+
+// removeExpired removes the expired certificates installed with the name, if
+// enabled.
+func (s *Server) removeExpired(st store, name string) {
+	leaf, ok := s.installed[name]
+	if !s.config.RemoveExpired || !ok {
+		return
+	}
+
+	removed, err := st.RemoveExpired(name, leaf.Subject.CommonName, time.Now())
+	if err != nil {
+		s.logger.Warn("error removing expired certificates", "certificate", name, "error", err)
+		return
+	}
+	if removed > 0 {
+		s.logger.Info("expired certificates removed", "certificate", name, "count", removed)
+	}
+}
+
+// readCertificate reads the files of the certificate, and returns its chain
+// and private key.
+func readCertificate(cert *config.CertificateStoreCertificate) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	certPEM, err := os.ReadFile(cert.CertFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM := certPEM
+	if cert.KeyFile != "" {
+		if keyPEM, err = os.ReadFile(cert.KeyFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Catch certificates and keys rendered by different templates that are
+	// not rendered in sync yet
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chain := make([]*x509.Certificate, 0, len(pair.Certificate))
+	for _, der := range pair.Certificate {
+		parsed, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, nil, err
+		}
+		chain = append(chain, parsed)
+	}
+	return chain, pair.PrivateKey, nil
+}
+
+// randomPassword returns a password for the transient PKCS#12 archives used
+// to import the certificates.
+func randomPassword() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package certstore
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/vault/command/agent/config"
+)
+
+// testCertificate returns a PEM encoded self-signed certificate and its key.
+func testCertificate(t *testing.T, commonName string) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+type testStore struct {
+	l         sync.Mutex
+	installed map[string][]string
+	removed   []string
+}
+
+func (s *testStore) Install(name string, chain []*x509.Certificate, _ crypto.PrivateKey) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.installed[name] = append(s.installed[name], chain[0].Subject.CommonName)
+	return nil
+}
+
+func (s *testStore) RemoveExpired(name, commonName string, _ time.Time) (int, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.removed = append(s.removed, commonName)
+	return 0, nil
+}
+
+func (s *testStore) Close() error {
+	return nil
+}
+
+func (s *testStore) waitForInstalled(t *testing.T, name string, expected ...string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.l.Lock()
+		installed := append([]string(nil), s.installed[name]...)
+		s.l.Unlock()
+		if len(installed) == len(expected) {
+			for i := range expected {
+				if installed[i] != expected[i] {
+					t.Fatalf("expected %v to be installed, got %v", expected, installed)
+				}
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %v to be installed, got %v", expected, installed)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "web.pem")
+	keyFile := filepath.Join(dir, "web-key.pem")
+	write := func(commonName string) {
+		cert, key := testCertificate(t, commonName)
+		if err := os.WriteFile(certFile, cert, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(keyFile, key, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("first")
+
+	st := &testStore{installed: make(map[string][]string)}
+	server := NewServer(&ServerConfig{
+		Logger: hclog.NewNullLogger(),
+		Config: &config.CertificateStore{
+			RemoveExpired: true,
+			Certificates: []*config.CertificateStoreCertificate{
+				{Name: "web", CertFile: certFile, KeyFile: keyFile},
+				{Name: "missing", CertFile: filepath.Join(dir, "missing.pem")},
+			},
+		},
+	})
+	server.openStore = func(*config.CertificateStore) (store, error) {
+		return st, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run(ctx)
+	}()
+
+	// The certificates already rendered are installed on start
+	st.waitForInstalled(t, "web", "first")
+
+	// Certificates are only installed again when they change
+	server.Rendered([]string{certFile})
+	write("second")
+	server.Rendered([]string{keyFile})
+	st.waitForInstalled(t, "web", "first", "second")
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	st.l.Lock()
+	defer st.l.Unlock()
+	if len(st.installed["missing"]) != 0 {
+		t.Fatal("expected the missing certificate not to be installed")
+	}
+	if len(st.removed) != 2 || st.removed[1] != "second" {
+		t.Fatalf("expected the expired certificates to be removed after each install, got %v", st.removed)
+	}
+}
+
+func TestReadCertificate(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := testCertificate(t, "web")
+	_, otherKey := testCertificate(t, "other")
+
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	bundleFile := write("bundle.pem", append(append([]byte{}, cert...), key...))
+	certFile := write("cert.pem", cert)
+	keyFile := write("key.pem", key)
+	otherKeyFile := write("other-key.pem", otherKey)
+
+	for _, c := range []*config.CertificateStoreCertificate{
+		{Name: "web", CertFile: bundleFile},
+		{Name: "web", CertFile: certFile, KeyFile: keyFile},
+	} {
+		chain, key, err := readCertificate(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(chain) != 1 || chain[0].Subject.CommonName != "web" {
+			t.Fatalf("bad: %#v", chain)
+		}
+		if _, ok := key.(*ecdsa.PrivateKey); !ok {
+			t.Fatalf("bad: %#v", key)
+		}
+	}
+
+	// The certificate and the key don't match, such as when they are
+	// rendered by different templates that are not in sync yet
+	if _, _, err := readCertificate(&config.CertificateStoreCertificate{Name: "web", CertFile: certFile, KeyFile: otherKeyFile}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if _, _, err := readCertificate(&config.CertificateStoreCertificate{Name: "web", CertFile: certFile}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
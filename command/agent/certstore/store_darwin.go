@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build darwin
+
+package certstore
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/hashicorp/vault/command/agent/config"
+)
+
+// keychainStore is a macOS keychain, managed with the security command. The
+// keychain labels certificates with their common name, so the certificates
+// are identified by their common name.
+type keychainStore struct {
+	keychain            string
+	trustedApplications []string
+	exportable          bool
+}
+
+func openSystemStore(conf *config.CertificateStore) (store, error) {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil, err
+	}
+
+	return &keychainStore{
+		keychain:            conf.Keychain,
+		trustedApplications: conf.TrustedApplications,
+		exportable:          strutil.StrListContains(conf.KeyStorageFlags, "exportable"),
+	}, nil
+}
+
+// Install imports the certificate chain and the private key from a PKCS#12
+// archive.
+func (s *keychainStore) Install(_ string, chain []*x509.Certificate, key crypto.PrivateKey) error {
+	password, err := randomPassword()
+	if err != nil {
+		return err
+	}
+	pfx, err := pkcs12.Encode(rand.Reader, key, chain[0], chain[1:], password)
+	if err != nil {
+		return fmt.Errorf("error encoding certificate: %w", err)
+	}
+
+	f, err := os.CreateTemp("", "vault-agent-*.p12")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(pfx); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	args := []string{"import", f.Name(), "-f", "pkcs12", "-P", password}
+	if s.keychain != "" {
+		args = append(args, "-k", s.keychain)
+	}
+	if !s.exportable {
+		args = append(args, "-x")
+	}
+	for _, app := range s.trustedApplications {
+		args = append(args, "-T", app)
+	}
+	out, err := exec.Command("security", args...).CombinedOutput()
+	if err != nil && !bytes.Contains(out, []byte("already exists")) {
+		return fmt.Errorf("error importing certificate: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RemoveExpired removes the expired certificates with the common name, and
+// their private keys.
+func (s *keychainStore) RemoveExpired(_, commonName string, now time.Time) (int, error) {
+	args := []string{"find-certificate", "-a", "-c", commonName, "-p"}
+	if s.keychain != "" {
+		args = append(args, s.keychain)
+	}
+	out, err := exec.Command("security", args...).Output()
+	if err != nil {
+		// No certificate was found
+		return 0, nil
+	}
+
+	var removed int
+	for {
+		var block *pem.Block
+		block, out = pem.Decode(out)
+		if block == nil {
+			return removed, nil
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || cert.Subject.CommonName != commonName || !cert.NotAfter.Before(now) {
+			continue
+		}
+
+		hash := sha1.Sum(cert.Raw)
+		args := []string{"-Z", strings.ToUpper(hex.EncodeToString(hash[:]))}
+		if s.keychain != "" {
+			args = append(args, s.keychain)
+		}
+		// Delete the private key with the certificate, unless it was
+		// installed without its key
+		if err := exec.Command("security", append([]string{"delete-identity"}, args...)...).Run(); err != nil {
+			out, err := exec.Command("security", append([]string{"delete-certificate"}, args...)...).CombinedOutput()
+			if err != nil {
+				return removed, fmt.Errorf("error deleting certificate: %w: %s", err, strings.TrimSpace(string(out)))
+			}
+		}
+		removed++
+	}
+}
+
+func (s *keychainStore) Close() error {
+	return nil
+}
@@ -0,0 +1,16 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !windows && !darwin
+
+package certstore
+
+import (
+	"errors"
+
+	"github.com/hashicorp/vault/command/agent/config"
+)
+
+func openSystemStore(*config.CertificateStore) (store, error) {
+	return nil, errors.New("certificate stores are only supported on Windows and macOS")
+}
@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build windows
+
+package certstore
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/hashicorp/vault/command/agent/config"
+)
+
+const certFriendlyNamePropID = 11
+
+var (
+	modcrypt32 = windows.NewLazySystemDLL("crypt32.dll")
+	modncrypt  = windows.NewLazySystemDLL("ncrypt.dll")
+
+	procCertGetCertificateContextProperty = modcrypt32.NewProc("CertGetCertificateContextProperty")
+	procCertSetCertificateContextProperty = modcrypt32.NewProc("CertSetCertificateContextProperty")
+	procNCryptDeleteKey                   = modncrypt.NewProc("NCryptDeleteKey")
+	procNCryptFreeObject                  = modncrypt.NewProc("NCryptFreeObject")
+)
+
+// windowsStore is a Windows system certificate store. The certificates are
+// identified by their friendly name.
+type windowsStore struct {
+	handle windows.Handle
+
+	// importFlags are the PFXImportCertStore flags, which control how the
+	// private keys are stored
+	importFlags uint32
+}
+
+func openSystemStore(conf *config.CertificateStore) (store, error) {
+	location := uint32(windows.CERT_SYSTEM_STORE_LOCAL_MACHINE)
+	importFlags := uint32(windows.CRYPT_MACHINE_KEYSET | windows.PKCS12_ALWAYS_CNG_KSP)
+	if conf.Location == "current_user" {
+		location = windows.CERT_SYSTEM_STORE_CURRENT_USER
+		importFlags = windows.CRYPT_USER_KEYSET | windows.PKCS12_ALWAYS_CNG_KSP
+	}
+	for _, flag := range conf.KeyStorageFlags {
+		switch flag {
+		case "exportable":
+			importFlags |= windows.CRYPT_EXPORTABLE
+		case "user_protected":
+			importFlags |= windows.CRYPT_USER_PROTECTED
+		}
+	}
+
+	name, err := windows.UTF16PtrFromString(conf.Store)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := windows.CertOpenStore(windows.CERT_STORE_PROV_SYSTEM, 0, 0, location, uintptr(unsafe.Pointer(name)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &windowsStore{handle: handle, importFlags: importFlags}, nil
+}
+
+// Install imports the certificate and its private key from a PKCS#12
+// archive, and adds the certificate to the store with the name as its
+// friendly name. The certificates of the chain aren't installed.
+func (s *windowsStore) Install(name string, chain []*x509.Certificate, key crypto.PrivateKey) error {
+	password, err := randomPassword()
+	if err != nil {
+		return err
+	}
+	pfx, err := pkcs12.Encode(rand.Reader, key, chain[0], chain[1:], password)
+	if err != nil {
+		return fmt.Errorf("error encoding certificate: %w", err)
+	}
+	pfxPassword, err := windows.UTF16PtrFromString(password)
+	if err != nil {
+		return err
+	}
+
+	pfxStore, err := windows.PFXImportCertStore(&windows.CryptDataBlob{Size: uint32(len(pfx)), Data: &pfx[0]}, pfxPassword, s.importFlags)
+	if err != nil {
+		return fmt.Errorf("error importing certificate: %w", err)
+	}
+	defer windows.CertCloseStore(pfxStore, 0)
+
+	var ctx *windows.CertContext
+	for {
+		ctx, err = windows.CertEnumCertificatesInStore(pfxStore, ctx)
+		if err != nil {
+			return fmt.Errorf("error finding the imported certificate: %w", err)
+		}
+		if bytes.Equal(encodedCert(ctx), chain[0].Raw) {
+			break
+		}
+	}
+	defer windows.CertFreeCertificateContext(ctx)
+
+	if err := setFriendlyName(ctx, name); err != nil {
+		return fmt.Errorf("error setting friendly name: %w", err)
+	}
+	if err := windows.CertAddCertificateContextToStore(s.handle, ctx, windows.CERT_STORE_ADD_REPLACE_EXISTING, nil); err != nil {
+		return fmt.Errorf("error adding certificate to the store: %w", err)
+	}
+	return nil
+}
+
+// RemoveExpired removes the expired certificates with the name as their
+// friendly name, and their private keys.
+func (s *windowsStore) RemoveExpired(name, _ string, now time.Time) (int, error) {
+	var removed int
+	var ctx *windows.CertContext
+	for {
+		var err error
+		ctx, err = windows.CertEnumCertificatesInStore(s.handle, ctx)
+		if err == syscall.Errno(windows.CRYPT_E_NOT_FOUND) {
+			return removed, nil
+		}
+		if err != nil {
+			return removed, err
+		}
+
+		if friendlyName(ctx) != name {
+			continue
+		}
+		cert, err := x509.ParseCertificate(encodedCert(ctx))
+		if err != nil || !cert.NotAfter.Before(now) {
+			continue
+		}
+
+		// The certificate may have been installed without its key
+		_ = deletePrivateKey(ctx)
+
+		// CertDeleteCertificateFromStore frees the context it's passed,
+		// so delete a duplicate to keep enumerating the store
+		if err := windows.CertDeleteCertificateFromStore(windows.CertDuplicateCertificateContext(ctx)); err != nil {
+			windows.CertFreeCertificateContext(ctx)
+			return removed, err
+		}
+		removed++
+	}
+}
+
+func (s *windowsStore) Close() error {
+	return windows.CertCloseStore(s.handle, 0)
+}
+
+func encodedCert(ctx *windows.CertContext) []byte {
+	return unsafe.Slice(ctx.EncodedCert, ctx.Length)
+}
+
+func friendlyName(ctx *windows.CertContext) string {
+	var size uint32
+	r, _, _ := procCertGetCertificateContextProperty.Call(uintptr(unsafe.Pointer(ctx)), certFriendlyNamePropID, 0, uintptr(unsafe.Pointer(&size)))
+	if r == 0 || size < 2 {
+		return ""
+	}
+	buf := make([]uint16, size/2)
+	r, _, _ = procCertGetCertificateContextProperty.Call(uintptr(unsafe.Pointer(ctx)), certFriendlyNamePropID, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if r == 0 {
+		return ""
+	}
+	return windows.UTF16ToString(buf)
+}
+
+func setFriendlyName(ctx *windows.CertContext, name string) error {
+	value, err := windows.UTF16FromString(name)
+	if err != nil {
+		return err
+	}
+	blob := windows.CryptDataBlob{Size: uint32(len(value) * 2), Data: (*byte)(unsafe.Pointer(&value[0]))}
+	r, _, err := procCertSetCertificateContextProperty.Call(uintptr(unsafe.Pointer(ctx)), certFriendlyNamePropID, 0, uintptr(unsafe.Pointer(&blob)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// deletePrivateKey deletes the CNG private key of the certificate.
+func deletePrivateKey(ctx *windows.CertContext) error {
+	var key windows.Handle
+	var keySpec uint32
+	var mustFree bool
+	err := windows.CryptAcquireCertificatePrivateKey(ctx, windows.CRYPT_ACQUIRE_ONLY_NCRYPT_KEY_FLAG|windows.CRYPT_ACQUIRE_SILENT_FLAG, nil, &key, &keySpec, &mustFree)
+	if err != nil {
+		return err
+	}
+
+	// NCryptDeleteKey frees the key handle when it succeeds
+	if r, _, _ := procNCryptDeleteKey.Call(uintptr(key), 0); r != 0 {
+		if mustFree {
+			procNCryptFreeObject.Call(uintptr(key))
+		}
+		return syscall.Errno(r)
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -19,6 +20,7 @@ import (
 	ctsignals "github.com/hashicorp/consul-template/signals"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/mitchellh/mapstructure"
@@ -50,6 +52,8 @@ type Config struct {
 	DisableKeepAlivesAutoAuth   bool                       `hcl:"-"`
 	Exec                        *ExecConfig                `hcl:"exec,optional"`
 	EnvTemplates                []*ctconfig.TemplateConfig `hcl:"env_template,optional"`
+	EnvoySDS                    *EnvoySDS                  `hcl:"envoy_sds"`
+	CertificateStore            *CertificateStore          `hcl:"certificate_store"`
 }
 
 const (
@@ -164,12 +168,92 @@ type TemplateConfig struct {
 	ExitOnRetryFailure       bool          `hcl:"exit_on_retry_failure"`
 	StaticSecretRenderIntRaw interface{}   `hcl:"static_secret_render_interval"`
 	StaticSecretRenderInt    time.Duration `hcl:"-"`
+
+	// RevocationCheckInterval is how often the revocation status of the
+	// certificates rendered by templates is checked, with OCSP or CRLs. The
+	// certificates are re-issued as soon as they are revoked. Disabled when
+	// zero.
+	RevocationCheckIntervalRaw interface{}   `hcl:"revocation_check_interval"`
+	RevocationCheckInterval    time.Duration `hcl:"-"`
 }
 
+// ExecConfig configures the child process Vault Agent runs, with the secrets
+// rendered by env_template as environment variables, and by template as files.
 type ExecConfig struct {
-	Command                []string  `hcl:"command,attr" mapstructure:"command"`
+	Command []string `hcl:"command,attr" mapstructure:"command"`
+
+	// RestartOnSecretChanges is what to do when the secrets of the child
+	// process change: "always" restarts it, "signal" sends it ReloadSignal,
+	// and "never" leaves it running. As environment variables cannot be
+	// changed, "signal" restarts the child process when they change.
 	RestartOnSecretChanges string    `hcl:"restart_on_secret_changes,optional" mapstructure:"restart_on_secret_changes"`
 	RestartStopSignal      os.Signal `hcl:"-" mapstructure:"restart_stop_signal"`
+	ReloadSignal           os.Signal `hcl:"-" mapstructure:"reload_signal"`
+}
+
+// EnvoySDS contains the configuration of the Envoy Secret Discovery Service
+// server, which serves the certificates rendered by templates to Envoy.
+type EnvoySDS struct {
+	// Address is the path of the Unix socket to listen on
+	Address       string      `hcl:"address"`
+	SocketModeRaw interface{} `hcl:"socket_mode"`
+	SocketMode    os.FileMode `hcl:"-"`
+
+	Secrets []*EnvoySDSSecret `hcl:"-"`
+}
+
+// EnvoySDSSecret is a secret served over SDS, sourced from the destinations
+// of templates. Secrets with a certificate are served as TLS certificates,
+// and secrets with only a CA certificate as validation contexts.
+type EnvoySDSSecret struct {
+	Name string `hcl:"-"`
+
+	// CertFile contains the PEM encoded certificate chain, and the private
+	// key unless KeyFile is set
+	CertFile string `hcl:"cert_file"`
+	KeyFile  string `hcl:"key_file"`
+	CAFile   string `hcl:"ca_file"`
+}
+
+// CertificateStore contains the configuration of the operating system
+// certificate store, the Windows certificate store or the macOS keychain, the
+// certificates rendered by templates are installed to.
+type CertificateStore struct {
+	// Location and Store are the Windows system store, such as
+	// "local_machine" and "My"
+	Location string `hcl:"location"`
+	Store    string `hcl:"store"`
+
+	// Keychain is the path of the macOS keychain, and TrustedApplications
+	// are the applications allowed to use the private keys without a prompt
+	Keychain            string   `hcl:"keychain"`
+	TrustedApplications []string `hcl:"trusted_applications"`
+
+	// KeyStorageFlags control how the private keys are stored, see
+	// CertificateStoreKeyStorageFlags
+	KeyStorageFlags []string `hcl:"key_storage_flags"`
+
+	// RemoveExpired removes the expired certificates previously installed
+	// for the same names, which defaults to true
+	RemoveExpiredRaw interface{} `hcl:"remove_expired"`
+	RemoveExpired    bool        `hcl:"-"`
+
+	Certificates []*CertificateStoreCertificate `hcl:"-"`
+}
+
+// CertificateStoreKeyStorageFlags are the valid key_storage_flags of the
+// certificate store. User protected keys are only supported on Windows.
+var CertificateStoreKeyStorageFlags = []string{"exportable", "user_protected"}
+
+// CertificateStoreCertificate is a certificate installed to the certificate
+// store, with its private key, sourced from the destinations of templates.
+type CertificateStoreCertificate struct {
+	Name string `hcl:"-"`
+
+	// CertFile contains the PEM encoded certificate chain, and the private
+	// key unless KeyFile is set
+	CertFile string `hcl:"cert_file"`
+	KeyFile  string `hcl:"key_file"`
 }
 
 func NewConfig() *Config {
@@ -282,6 +366,16 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.EnvTemplates = append(result.EnvTemplates, envTmpl)
 	}
 
+	result.EnvoySDS = c.EnvoySDS
+	if c2.EnvoySDS != nil {
+		result.EnvoySDS = c2.EnvoySDS
+	}
+
+	result.CertificateStore = c.CertificateStore
+	if c2.CertificateStore != nil {
+		result.CertificateStore = c2.CertificateStore
+	}
+
 	return result
 }
 
@@ -351,9 +445,81 @@ func (c *Config) ValidateConfig() error {
 		return fmt.Errorf("no auto_auth, cache, or listener block found in config")
 	}
 
+	if c.Exec != nil {
+		if c.AutoAuth == nil {
+			return fmt.Errorf("exec requires auto_auth to be configured")
+		}
+		if c.ExitAfterAuth {
+			return fmt.Errorf("exec cannot be used with exit_after_auth")
+		}
+	}
+
+	if len(c.EnvTemplates) > 0 && c.Exec == nil {
+		return fmt.Errorf("env_template requires exec to be configured")
+	}
+
+	if c.EnvoySDS != nil {
+		if err := c.validateEnvoySDS(); err != nil {
+			return err
+		}
+	}
+
+	if c.CertificateStore != nil {
+		if err := c.validateCertificateStore(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateEnvoySDS ensures that the files of the secrets served over SDS are
+// rendered by templates, so that the server is notified of their changes.
+func (c *Config) validateEnvoySDS() error {
+	if c.AutoAuth == nil {
+		return fmt.Errorf("envoy_sds requires auto_auth to be configured")
+	}
+
+	destinations := c.templateDestinations()
+	for _, secret := range c.EnvoySDS.Secrets {
+		for _, file := range []string{secret.CertFile, secret.KeyFile, secret.CAFile} {
+			if file != "" && !destinations[filepath.Clean(file)] {
+				return fmt.Errorf("envoy_sds secret %q: %q is not the destination of a template", secret.Name, file)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCertificateStore ensures that the files of the certificates
+// installed to the certificate store are rendered by templates.
+func (c *Config) validateCertificateStore() error {
+	if c.AutoAuth == nil {
+		return fmt.Errorf("certificate_store requires auto_auth to be configured")
+	}
+
+	destinations := c.templateDestinations()
+	for _, cert := range c.CertificateStore.Certificates {
+		for _, file := range []string{cert.CertFile, cert.KeyFile} {
+			if file != "" && !destinations[filepath.Clean(file)] {
+				return fmt.Errorf("certificate_store certificate %q: %q is not the destination of a template", cert.Name, file)
+			}
+		}
+	}
 	return nil
 }
 
+// templateDestinations returns the cleaned destinations of the templates.
+func (c *Config) templateDestinations() map[string]bool {
+	destinations := make(map[string]bool, len(c.Templates))
+	for _, tmpl := range c.Templates {
+		if tmpl.Destination != nil {
+			destinations[filepath.Clean(*tmpl.Destination)] = true
+		}
+	}
+	return destinations
+}
+
 // LoadConfig loads the configuration at the given path, regardless if
 // it's a file or directory.
 func LoadConfig(path string) (*Config, error) {
@@ -524,6 +690,14 @@ func LoadConfigFile(path string) (*Config, error) {
 		return nil, fmt.Errorf("error parsing 'env_template': %w", err)
 	}
 
+	if err := parseEnvoySDS(result, list); err != nil {
+		return nil, fmt.Errorf("error parsing 'envoy_sds': %w", err)
+	}
+
+	if err := parseCertificateStore(result, list); err != nil {
+		return nil, fmt.Errorf("error parsing 'certificate_store': %w", err)
+	}
+
 	if result.Cache != nil && result.APIProxy == nil {
 		result.APIProxy = &APIProxy{
 			UseAutoAuthToken:   result.Cache.UseAutoAuthToken,
@@ -1002,6 +1176,14 @@ func parseTemplateConfig(result *Config, list *ast.ObjectList) error {
 		result.TemplateConfig.StaticSecretRenderIntRaw = nil
 	}
 
+	if result.TemplateConfig.RevocationCheckIntervalRaw != nil {
+		var err error
+		if result.TemplateConfig.RevocationCheckInterval, err = parseutil.ParseDurationSecond(result.TemplateConfig.RevocationCheckIntervalRaw); err != nil {
+			return err
+		}
+		result.TemplateConfig.RevocationCheckIntervalRaw = nil
+	}
+
 	return nil
 }
 
@@ -1125,6 +1307,21 @@ func parseExec(result *Config, list *ast.ObjectList) error {
 		execConfig.RestartOnSecretChanges = "always"
 	}
 
+	switch execConfig.RestartOnSecretChanges {
+	case "always", "never", "signal":
+	default:
+		return fmt.Errorf("invalid value for 'restart_on_secret_changes': %q", execConfig.RestartOnSecretChanges)
+	}
+
+	// if the user does not specify a reload signal, default to SIGHUP
+	if execConfig.ReloadSignal == nil {
+		execConfig.ReloadSignal = syscall.SIGHUP
+	}
+
+	if len(execConfig.Command) == 0 || execConfig.Command[0] == "" {
+		return errors.New("'command' must be specified")
+	}
+
 	result.Exec = &execConfig
 	return nil
 }
@@ -1189,3 +1386,158 @@ func parseEnvTemplates(result *Config, list *ast.ObjectList) error {
 	result.EnvTemplates = envTemplates
 	return nil
 }
+
+func parseEnvoySDS(result *Config, list *ast.ObjectList) error {
+	name := "envoy_sds"
+
+	sdsList := list.Filter(name)
+	if len(sdsList.Items) == 0 {
+		return nil
+	}
+	if len(sdsList.Items) > 1 {
+		return fmt.Errorf("at most one %q block is allowed", name)
+	}
+
+	item := sdsList.Items[0]
+
+	var sds EnvoySDS
+	if err := hcl.DecodeObject(&sds, item.Val); err != nil {
+		return err
+	}
+
+	if sds.Address == "" {
+		return errors.New("address must be specified")
+	}
+
+	sds.SocketMode = 0o600
+	if sds.SocketModeRaw != nil {
+		mode, ok := sds.SocketModeRaw.(string)
+		if !ok {
+			return errors.New("socket_mode must be a string")
+		}
+		parsed, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid socket_mode: %w", err)
+		}
+		sds.SocketMode = os.FileMode(parsed)
+		sds.SocketModeRaw = nil
+	}
+
+	subs, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		return fmt.Errorf("could not parse %q as an object", name)
+	}
+
+	names := make(map[string]bool)
+	for _, secretItem := range subs.List.Filter("secret").Items {
+		var secret EnvoySDSSecret
+		if err := hcl.DecodeObject(&secret, secretItem.Val); err != nil {
+			return err
+		}
+
+		if len(secretItem.Keys) != 1 {
+			return errors.New("secret name must be specified")
+		}
+		secret.Name = secretItem.Keys[0].Token.Value().(string)
+		if names[secret.Name] {
+			return fmt.Errorf("secret %q is defined more than once", secret.Name)
+		}
+		names[secret.Name] = true
+
+		switch {
+		case secret.CertFile == "" && secret.CAFile == "":
+			return fmt.Errorf("secret %q: one of cert_file or ca_file must be specified", secret.Name)
+		case secret.CertFile != "" && secret.CAFile != "":
+			return fmt.Errorf("secret %q: cert_file and ca_file are mutually exclusive", secret.Name)
+		case secret.CertFile == "" && secret.KeyFile != "":
+			return fmt.Errorf("secret %q: key_file requires cert_file", secret.Name)
+		}
+
+		sds.Secrets = append(sds.Secrets, &secret)
+	}
+	if len(sds.Secrets) == 0 {
+		return errors.New("at least one 'secret' block is required")
+	}
+
+	result.EnvoySDS = &sds
+	return nil
+}
+
+func parseCertificateStore(result *Config, list *ast.ObjectList) error {
+	name := "certificate_store"
+
+	storeList := list.Filter(name)
+	if len(storeList.Items) == 0 {
+		return nil
+	}
+	if len(storeList.Items) > 1 {
+		return fmt.Errorf("at most one %q block is allowed", name)
+	}
+
+	item := storeList.Items[0]
+
+	var store CertificateStore
+	if err := hcl.DecodeObject(&store, item.Val); err != nil {
+		return err
+	}
+
+	switch store.Location {
+	case "":
+		store.Location = "local_machine"
+	case "local_machine", "current_user":
+	default:
+		return fmt.Errorf("invalid location %q", store.Location)
+	}
+	if store.Store == "" {
+		store.Store = "My"
+	}
+
+	for _, flag := range store.KeyStorageFlags {
+		if !strutil.StrListContains(CertificateStoreKeyStorageFlags, flag) {
+			return fmt.Errorf("invalid key storage flag %q", flag)
+		}
+	}
+
+	store.RemoveExpired = true
+	if store.RemoveExpiredRaw != nil {
+		var err error
+		if store.RemoveExpired, err = parseutil.ParseBool(store.RemoveExpiredRaw); err != nil {
+			return fmt.Errorf("invalid remove_expired: %w", err)
+		}
+		store.RemoveExpiredRaw = nil
+	}
+
+	subs, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		return fmt.Errorf("could not parse %q as an object", name)
+	}
+
+	names := make(map[string]bool)
+	for _, certItem := range subs.List.Filter("certificate").Items {
+		var cert CertificateStoreCertificate
+		if err := hcl.DecodeObject(&cert, certItem.Val); err != nil {
+			return err
+		}
+
+		if len(certItem.Keys) != 1 {
+			return errors.New("certificate name must be specified")
+		}
+		cert.Name = certItem.Keys[0].Token.Value().(string)
+		if names[cert.Name] {
+			return fmt.Errorf("certificate %q is defined more than once", cert.Name)
+		}
+		names[cert.Name] = true
+
+		if cert.CertFile == "" {
+			return fmt.Errorf("certificate %q: cert_file must be specified", cert.Name)
+		}
+
+		store.Certificates = append(store.Certificates, &cert)
+	}
+	if len(store.Certificates) == 0 {
+		return errors.New("at least one 'certificate' block is required")
+	}
+
+	result.CertificateStore = &store
+	return nil
+}
@@ -1001,8 +1001,9 @@ func TestLoadConfigFile_TemplateConfig(t *testing.T) {
 		"set-true": {
 			"./test-fixtures/config-template_config.hcl",
 			TemplateConfig{
-				ExitOnRetryFailure:    true,
-				StaticSecretRenderInt: 1 * time.Minute,
+				ExitOnRetryFailure:      true,
+				StaticSecretRenderInt:   1 * time.Minute,
+				RevocationCheckInterval: 5 * time.Minute,
 			},
 		},
 		"empty": {
@@ -2175,6 +2176,14 @@ func TestLoadConfigFile_ExecInvalidSignal(t *testing.T) {
 	}
 }
 
+// TestLoadConfigFile_ExecInvalidRestart ensures that an invalid restart_on_secret_changes triggers an error
+func TestLoadConfigFile_ExecInvalidRestart(t *testing.T) {
+	_, err := LoadConfigFile("./test-fixtures/bad-config-exec-invalid-restart.hcl")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
 // TestLoadConfigFile_ExecSimple validates the exec section with default parameters
 func TestLoadConfigFile_ExecSimple(t *testing.T) {
 	cfg, err := LoadConfigFile("./test-fixtures/config-env-templates-simple.hcl")
@@ -2199,6 +2208,10 @@ func TestLoadConfigFile_ExecSimple(t *testing.T) {
 	if cfg.Exec.RestartStopSignal != syscall.SIGTERM {
 		t.Fatalf("expected cfg.Exec.RestartStopSignal to be 'syscall.SIGTERM', got '%s'", cfg.Exec.RestartStopSignal)
 	}
+
+	if cfg.Exec.ReloadSignal != syscall.SIGHUP {
+		t.Fatalf("expected cfg.Exec.ReloadSignal to be 'syscall.SIGHUP', got '%s'", cfg.Exec.ReloadSignal)
+	}
 }
 
 // TestLoadConfigFile_ExecComplex validates the exec section with non-default parameters
@@ -2219,4 +2232,86 @@ func TestLoadConfigFile_ExecComplex(t *testing.T) {
 	if cfg.Exec.RestartStopSignal != syscall.SIGINT {
 		t.Fatalf("expected cfg.Exec.RestartStopSignal to be 'syscall.SIGINT', got %q", cfg.Exec.RestartStopSignal)
 	}
+
+	if cfg.Exec.ReloadSignal != syscall.SIGUSR1 {
+		t.Fatalf("expected cfg.Exec.ReloadSignal to be 'syscall.SIGUSR1', got %q", cfg.Exec.ReloadSignal)
+	}
+}
+
+// TestLoadConfigFile_EnvoySDS validates the envoy_sds section
+func TestLoadConfigFile_EnvoySDS(t *testing.T) {
+	cfg, err := LoadConfigFile("./test-fixtures/config-envoy-sds.hcl")
+	if err != nil {
+		t.Fatalf("error loading config file: %s", err)
+	}
+	if err := cfg.ValidateConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &EnvoySDS{
+		Address:    "/run/vault-agent/sds.sock",
+		SocketMode: 0o660,
+		Secrets: []*EnvoySDSSecret{
+			{
+				Name:     "web",
+				CertFile: "/run/vault-agent/web.pem",
+			},
+			{
+				Name:   "ca",
+				CAFile: "/run/vault-agent/ca.pem",
+			},
+		},
+	}
+	if diff := deep.Equal(cfg.EnvoySDS, expected); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
+// TestLoadConfigFile_EnvoySDSNoTemplate ensures that the files of the secrets
+// must be rendered by templates
+func TestLoadConfigFile_EnvoySDSNoTemplate(t *testing.T) {
+	cfg, err := LoadConfigFile("./test-fixtures/bad-config-envoy-sds-no-template.hcl")
+	if err != nil {
+		t.Fatalf("error loading config file: %s", err)
+	}
+	if err := cfg.ValidateConfig(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// TestLoadConfigFile_CertificateStore validates the certificate_store section
+func TestLoadConfigFile_CertificateStore(t *testing.T) {
+	cfg, err := LoadConfigFile("./test-fixtures/config-certificate-store.hcl")
+	if err != nil {
+		t.Fatalf("error loading config file: %s", err)
+	}
+	if err := cfg.ValidateConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &CertificateStore{
+		Location:        "current_user",
+		Store:           "My",
+		KeyStorageFlags: []string{"exportable"},
+		RemoveExpired:   false,
+		Certificates: []*CertificateStoreCertificate{
+			{
+				Name:     "web",
+				CertFile: "/run/vault-agent/web.pem",
+				KeyFile:  "/run/vault-agent/web-key.pem",
+			},
+		},
+	}
+	if diff := deep.Equal(cfg.CertificateStore, expected); diff != nil {
+		t.Fatal(diff)
+	}
+}
+
+// TestLoadConfigFile_CertificateStoreInvalidFlag ensures that an invalid key
+// storage flag triggers an error
+func TestLoadConfigFile_CertificateStoreInvalidFlag(t *testing.T) {
+	_, err := LoadConfigFile("./test-fixtures/bad-config-certificate-store-flags.hcl")
+	if err == nil {
+		t.Fatal("expected error")
+	}
 }
@@ -0,0 +1,25 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+auto_auth {
+  method {
+    type = "aws"
+
+    config = {
+      role = "foobar"
+    }
+  }
+}
+
+template {
+  contents    = "{{ with pkiCert \"pki/issue/web\" \"common_name=web.example.com\" }}{{ .Cert }}{{ .CA }}{{ .Key }}{{ end }}"
+  destination = "/run/vault-agent/web.pem"
+}
+
+certificate_store {
+  key_storage_flags = ["archivable"]
+
+  certificate "web" {
+    cert_file = "/run/vault-agent/web.pem"
+  }
+}
@@ -0,0 +1,26 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+auto_auth {
+  method {
+    type = "aws"
+
+    config = {
+      role = "foobar"
+    }
+  }
+}
+
+template {
+  contents    = "{{ with pkiCert \"pki/issue/web\" \"common_name=web.example.com\" }}{{ .Cert }}{{ .Key }}{{ end }}"
+  destination = "/run/vault-agent/web.pem"
+}
+
+envoy_sds {
+  address = "/run/vault-agent/sds.sock"
+
+  secret "web" {
+    cert_file = "/run/vault-agent/web.pem"
+    key_file  = "/run/vault-agent/web-key.pem"
+  }
+}
@@ -0,0 +1,33 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+auto_auth {
+  method {
+    type = "aws"
+
+    config = {
+      role = "foobar"
+    }
+  }
+}
+
+template {
+  contents    = "{{ with pkiCert \"pki/issue/web\" \"common_name=web.example.com\" }}{{ .Cert }}{{ .CA }}{{ end }}"
+  destination = "/run/vault-agent/web.pem"
+}
+
+template {
+  contents    = "{{ with pkiCert \"pki/issue/web\" \"common_name=web.example.com\" }}{{ .Key }}{{ end }}"
+  destination = "/run/vault-agent/web-key.pem"
+}
+
+certificate_store {
+  location          = "current_user"
+  key_storage_flags = ["exportable"]
+  remove_expired    = false
+
+  certificate "web" {
+    cert_file = "/run/vault-agent/web.pem"
+    key_file  = "/run/vault-agent/web-key.pem"
+  }
+}
@@ -30,4 +30,5 @@ exec {
   command                   = ["env"]
   restart_on_secret_changes = "never"
   restart_stop_signal       = "SIGINT"
+  reload_signal             = "SIGUSR1"
 }
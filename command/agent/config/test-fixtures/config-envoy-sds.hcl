@@ -0,0 +1,35 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+auto_auth {
+  method {
+    type = "aws"
+
+    config = {
+      role = "foobar"
+    }
+  }
+}
+
+template {
+  contents    = "{{ with pkiCert \"pki/issue/web\" \"common_name=web.example.com\" }}{{ .Cert }}{{ .CA }}{{ .Key }}{{ end }}"
+  destination = "/run/vault-agent/web.pem"
+}
+
+template {
+  contents    = "{{ with secret \"pki/cert/ca\" }}{{ .Data.certificate }}{{ end }}"
+  destination = "/run/vault-agent/ca.pem"
+}
+
+envoy_sds {
+  address     = "/run/vault-agent/sds.sock"
+  socket_mode = "0660"
+
+  secret "web" {
+    cert_file = "/run/vault-agent/web.pem"
+  }
+
+  secret "ca" {
+    ca_file = "/run/vault-agent/ca.pem"
+  }
+}
@@ -11,6 +11,7 @@ vault {
 template_config {
   exit_on_retry_failure = true
   static_secret_render_interval = 60
+  revocation_check_interval = "5m"
 }
 
 template {
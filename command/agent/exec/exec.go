@@ -0,0 +1,377 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package exec is responsible for running a child process with the secrets
+// rendered by Vault Agent. The secrets rendered by env_template are injected
+// as environment variables, and the ones rendered by template are written to
+// files. The child process is restarted, or signaled, when its secrets
+// change, and Vault Agent exits with its exit code once it exits.
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-template/child"
+	ctconfig "github.com/hashicorp/consul-template/config"
+	"github.com/hashicorp/consul-template/manager"
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/exp/slices"
+
+	"github.com/hashicorp/vault/command/agent/config"
+	"github.com/hashicorp/vault/command/agent/internal/ctmanager"
+	"github.com/hashicorp/vault/helper/useragent"
+	"github.com/hashicorp/vault/sdk/helper/pointerutil"
+)
+
+// childKillTimeout is how long the child process is given to exit after
+// being sent the stop signal, before it is killed.
+const childKillTimeout = 30 * time.Second
+
+// ProcessExitError is returned by Run when the child process exits, with its
+// exit code.
+type ProcessExitError struct {
+	ExitCode int
+}
+
+func (e *ProcessExitError) Error() string {
+	return fmt.Sprintf("process exited with %d", e.ExitCode)
+}
+
+// ServerConfig is a config struct for setting up the basic parts of the
+// Server
+type ServerConfig struct {
+	Logger      hclog.Logger
+	AgentConfig *config.Config
+
+	Namespace string
+
+	// LogLevel and LogWriter are passed to the internal Consul Template
+	// Runner, see template.ServerConfig.
+	LogLevel  hclog.Level
+	LogWriter io.Writer
+}
+
+// Server runs the child process, and the Consul Template Runner rendering
+// its environment variables
+type Server struct {
+	config *ServerConfig
+	logger hclog.Logger
+
+	// runner is the consul-template runner rendering the env templates
+	runner *manager.Runner
+
+	// numberOfTemplates is the number of distinct env templates, used to
+	// know when all of them have been rendered
+	numberOfTemplates int
+
+	// lastRenderedEnvVars are the environment variables the child process
+	// was started with
+	lastRenderedEnvVars []string
+
+	// pendingFiles are the destinations of the file templates that have not
+	// been rendered yet. The child process is only started once they all
+	// are.
+	pendingFiles map[string]bool
+
+	// filesRendered holds the destinations of the file templates rendered
+	// since it was last read, and filesRenderedCh is notified when it
+	// changes
+	filesRenderedLock sync.Mutex
+	filesRendered     []string
+	filesRenderedCh   chan struct{}
+
+	childProcess       *child.Child
+	childProcessExitCh chan childExit
+}
+
+// childExit is the exit code of a child process
+type childExit struct {
+	proc     *child.Child
+	exitCode int
+}
+
+// NewServer returns a new configured server
+func NewServer(conf *ServerConfig) *Server {
+	pendingFiles := make(map[string]bool, len(conf.AgentConfig.Templates))
+	for _, tmpl := range conf.AgentConfig.Templates {
+		if tmpl.Destination != nil {
+			pendingFiles[filepath.Clean(*tmpl.Destination)] = true
+		}
+	}
+
+	return &Server{
+		config:             conf,
+		logger:             conf.Logger,
+		pendingFiles:       pendingFiles,
+		filesRenderedCh:    make(chan struct{}, 1),
+		childProcessExitCh: make(chan childExit, 1),
+	}
+}
+
+// TemplatesRendered notifies the server that the file templates with the
+// destinations were rendered. It is called by the template server.
+func (s *Server) TemplatesRendered(destinations []string) {
+	s.filesRenderedLock.Lock()
+	s.filesRendered = append(s.filesRendered, destinations...)
+	s.filesRenderedLock.Unlock()
+
+	select {
+	case s.filesRenderedCh <- struct{}{}:
+	default:
+	}
+}
+
+// Run renders the env templates, with the tokens received from the
+// AuthHandler, and runs the child process once the env and file templates
+// are rendered. If Done() is called on the context, the child process is
+// stopped. A ProcessExitError is returned when the child process exits.
+func (s *Server) Run(ctx context.Context, incoming chan string) error {
+	if incoming == nil {
+		return errors.New("exec server: incoming channel is nil")
+	}
+
+	s.logger.Info("starting exec server")
+	defer func() {
+		s.stopChildProcess()
+		s.logger.Info("exec server stopped")
+	}()
+
+	var runnerConfig *ctconfig.Config
+	envTemplates := s.config.AgentConfig.EnvTemplates
+	if len(envTemplates) > 0 {
+		var err error
+		runnerConfig, err = ctmanager.NewConfig(ctmanager.ManagerConfig{
+			AgentConfig: s.config.AgentConfig,
+			Namespace:   s.config.Namespace,
+			LogLevel:    s.config.LogLevel,
+			LogWriter:   s.config.LogWriter,
+		}, envTemplates)
+		if err != nil {
+			return fmt.Errorf("exec server failed to generate runner config: %w", err)
+		}
+
+		// The env templates are rendered in dry mode, so that they aren't
+		// written anywhere
+		s.runner, err = manager.NewRunner(runnerConfig, true)
+		if err != nil {
+			return fmt.Errorf("exec server failed to create runner: %w", err)
+		}
+		s.runner.SetOutStream(io.Discard)
+		s.numberOfTemplates = len(s.runner.TemplateConfigMapping())
+	}
+
+	// Without templates to wait for, start the child process right away
+	if s.runner == nil && len(s.pendingFiles) == 0 {
+		if err := s.restartChildProcess(nil); err != nil {
+			return err
+		}
+	}
+
+	latestToken := new(string)
+	for {
+		var errCh <-chan error
+		var renderedCh <-chan struct{}
+		if s.runner != nil {
+			errCh = s.runner.ErrCh
+			renderedCh = s.runner.TemplateRenderedCh()
+		}
+
+		select {
+		case <-ctx.Done():
+			if s.runner != nil {
+				s.runner.Stop()
+			}
+			return nil
+
+		case token, ok := <-incoming:
+			if !ok {
+				// The auth handler stopped
+				incoming = nil
+				continue
+			}
+			if s.runner == nil || token == *latestToken {
+				continue
+			}
+			s.logger.Info("exec server received new token")
+
+			s.runner.Stop()
+			*latestToken = token
+			runnerConfig = runnerConfig.Merge(&ctconfig.Config{
+				Vault: &ctconfig.VaultConfig{
+					Token:           latestToken,
+					ClientUserAgent: pointerutil.StringPtr(useragent.AgentTemplatingString()),
+				},
+			})
+			var err error
+			s.runner, err = manager.NewRunner(runnerConfig, true)
+			if err != nil {
+				s.logger.Error("exec server failed with new Vault token", "error", err)
+				continue
+			}
+			s.runner.SetOutStream(io.Discard)
+			go s.runner.Start()
+
+		case err := <-errCh:
+			s.logger.Error("exec server error", "error", err)
+			s.runner.StopImmediately()
+
+			if s.config.AgentConfig.TemplateConfig != nil && s.config.AgentConfig.TemplateConfig.ExitOnRetryFailure {
+				return fmt.Errorf("exec server: %w", err)
+			}
+
+			s.runner, err = manager.NewRunner(runnerConfig, true)
+			if err != nil {
+				return fmt.Errorf("exec server failed to create runner: %w", err)
+			}
+			s.runner.SetOutStream(io.Discard)
+			go s.runner.Start()
+
+		case <-renderedCh:
+			envVars, ok := s.renderedEnvVars()
+			if !ok || slices.Equal(envVars, s.lastRenderedEnvVars) {
+				continue
+			}
+			if err := s.secretsChanged(envVars, false); err != nil {
+				return err
+			}
+
+		case <-s.filesRenderedCh:
+			s.filesRenderedLock.Lock()
+			rendered := s.filesRendered
+			s.filesRendered = nil
+			s.filesRenderedLock.Unlock()
+
+			for _, dest := range rendered {
+				delete(s.pendingFiles, filepath.Clean(dest))
+			}
+			if err := s.secretsChanged(s.lastRenderedEnvVars, true); err != nil {
+				return err
+			}
+
+		case exit := <-s.childProcessExitCh:
+			if exit.proc != s.childProcess {
+				// A child process that was restarted
+				continue
+			}
+			s.childProcess = nil
+			s.logger.Info("child process exited", "exit_code", exit.exitCode)
+			if s.runner != nil {
+				s.runner.Stop()
+			}
+			return &ProcessExitError{ExitCode: exit.exitCode}
+		}
+	}
+}
+
+// renderedEnvVars returns the environment variables rendered by the env
+// templates, and whether all of them have been rendered.
+func (s *Server) renderedEnvVars() ([]string, bool) {
+	events := s.runner.RenderEvents()
+	if len(events) < s.numberOfTemplates {
+		return nil, false
+	}
+
+	var envVars []string
+	for _, event := range events {
+		if event.LastWouldRender.IsZero() {
+			return nil, false
+		}
+		for _, tmpl := range event.TemplateConfigs {
+			envVars = append(envVars, fmt.Sprintf("%s=%s", *tmpl.MapToEnvironmentVariable, event.Contents))
+		}
+	}
+	sort.Strings(envVars)
+	return envVars, true
+}
+
+// secretsChanged starts the child process once all the templates are
+// rendered, and then restarts or signals it according to the configuration.
+func (s *Server) secretsChanged(envVars []string, filesChanged bool) error {
+	envChanged := !slices.Equal(envVars, s.lastRenderedEnvVars)
+	s.lastRenderedEnvVars = envVars
+
+	if len(s.pendingFiles) > 0 || (s.runner != nil && envVars == nil) {
+		// Not all the templates have been rendered yet
+		return nil
+	}
+
+	if s.childProcess == nil {
+		return s.restartChildProcess(envVars)
+	}
+
+	switch s.config.AgentConfig.Exec.RestartOnSecretChanges {
+	case "never":
+		s.logger.Info("secrets changed, but not restarting the child process")
+		return nil
+
+	case "signal":
+		if !envChanged && filesChanged {
+			s.logger.Info("secrets changed, signaling the child process")
+			if err := s.childProcess.Signal(s.config.AgentConfig.Exec.ReloadSignal); err != nil {
+				return fmt.Errorf("exec server failed to signal the child process: %w", err)
+			}
+			return nil
+		}
+		// The environment of a process cannot be changed while it runs
+		fallthrough
+
+	default:
+		s.logger.Info("secrets changed, restarting the child process")
+		return s.restartChildProcess(envVars)
+	}
+}
+
+// restartChildProcess stops the child process if it's running, and starts it
+// with the environment variables, in addition to the environment of Vault
+// Agent.
+func (s *Server) restartChildProcess(envVars []string) error {
+	s.stopChildProcess()
+
+	execConfig := s.config.AgentConfig.Exec
+	proc, err := child.New(&child.NewInput{
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+		Command:     execConfig.Command[0],
+		Args:        execConfig.Command[1:],
+		Env:         append(os.Environ(), envVars...),
+		KillSignal:  execConfig.RestartStopSignal,
+		KillTimeout: childKillTimeout,
+		Logger:      s.logger.StandardLogger(nil),
+	})
+	if err != nil {
+		return fmt.Errorf("exec server failed to create the child process: %w", err)
+	}
+	if err := proc.Start(); err != nil {
+		return fmt.Errorf("exec server failed to start the child process: %w", err)
+	}
+	s.logger.Info("child process started", "pid", proc.Pid())
+	s.childProcess = proc
+
+	// Forward the exit code of the child process, unless it's stopped by
+	// the server
+	go func() {
+		if exitCode, ok := <-proc.ExitCh(); ok {
+			s.childProcessExitCh <- childExit{proc: proc, exitCode: exitCode}
+		}
+	}()
+	return nil
+}
+
+// stopChildProcess stops the child process with the restart stop signal, if
+// it's running.
+func (s *Server) stopChildProcess() {
+	if s.childProcess == nil {
+		return
+	}
+	s.childProcess.Stop()
+	s.childProcess = nil
+}
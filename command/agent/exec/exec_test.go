@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	ctconfig "github.com/hashicorp/consul-template/config"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/vault/command/agent/config"
+	"github.com/hashicorp/vault/sdk/helper/pointerutil"
+)
+
+func testServer(t *testing.T, execConfig *config.ExecConfig, templates ...string) *Server {
+	t.Helper()
+
+	agentConfig := &config.Config{Exec: execConfig}
+	for _, dest := range templates {
+		agentConfig.Templates = append(agentConfig.Templates, &ctconfig.TemplateConfig{
+			Destination: pointerutil.StringPtr(dest),
+		})
+	}
+	if execConfig.RestartStopSignal == nil {
+		execConfig.RestartStopSignal = syscall.SIGTERM
+	}
+	if execConfig.ReloadSignal == nil {
+		execConfig.ReloadSignal = syscall.SIGHUP
+	}
+
+	return NewServer(&ServerConfig{
+		Logger:      hclog.NewNullLogger(),
+		AgentConfig: agentConfig,
+	})
+}
+
+// waitForFile waits for the file to contain the content.
+func waitForFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(path)
+		if strings.TrimSpace(string(data)) == content {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %q in %s, got %q", content, path, data)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServer_ExitCode(t *testing.T) {
+	server := testServer(t, &config.ExecConfig{
+		Command: []string{"sh", "-c", "exit 3"},
+	})
+
+	err := server.Run(context.Background(), make(chan string))
+	var exitErr *ProcessExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected a process exit error, got %v", err)
+	}
+	if exitErr.ExitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d", exitErr.ExitCode)
+	}
+}
+
+func TestServer_Stop(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	server := testServer(t, &config.ExecConfig{
+		Command: []string{"sh", "-c", `trap 'echo stopped > "$0"; exit 0' TERM; echo started > "$0"; while true; do sleep 0.1; done`, out},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run(ctx, make(chan string))
+	}()
+
+	waitForFile(t, out, "started")
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+	waitForFile(t, out, "stopped")
+}
+
+func TestServer_TemplatesRendered(t *testing.T) {
+	for _, tc := range []struct {
+		restart  string
+		expected string
+	}{
+		{"always", "started"},
+		{"signal", "reloaded"},
+		{"never", "running"},
+	} {
+		t.Run(tc.restart, func(t *testing.T) {
+			dir := t.TempDir()
+			out := filepath.Join(dir, "out")
+			secret := filepath.Join(dir, "secret")
+
+			// The child process writes "started" when it starts, and
+			// "reloaded" when it's signaled
+			server := testServer(t, &config.ExecConfig{
+				Command: []string{
+					"sh", "-c",
+					`trap 'echo reloaded > "$0"' HUP; echo started > "$0"; while true; do sleep 0.1; done`,
+					out,
+				},
+				RestartOnSecretChanges: tc.restart,
+			}, secret)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- server.Run(ctx, make(chan string))
+			}()
+
+			// The child process is started once the templates are rendered
+			time.Sleep(100 * time.Millisecond)
+			if _, err := os.Stat(out); !errors.Is(err, os.ErrNotExist) {
+				t.Fatal("expected the child process to wait for the templates")
+			}
+			server.TemplatesRendered([]string{secret})
+			waitForFile(t, out, "started")
+
+			if err := os.WriteFile(out, []byte("running"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			server.TemplatesRendered([]string{secret})
+			waitForFile(t, out, tc.expected)
+
+			// Give the child process time to act on any extra signal
+			time.Sleep(100 * time.Millisecond)
+			waitForFile(t, out, tc.expected)
+
+			cancel()
+			if err := <-errCh; err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package sds implements an Envoy Secret Discovery Service (SDS) server, which
+// serves the certificates rendered by the templates of Vault Agent to Envoy
+// over a Unix socket. Envoy is sent the new certificates as soon as they are
+// rendered, such as when the PKI certificates of templates are rotated.
+package sds
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	secretv3 "github.com/envoyproxy/go-control-plane/envoy/service/secret/v3"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+
+	"github.com/hashicorp/vault/command/agent/config"
+)
+
+// ServerConfig is the configuration of the SDS Server
+type ServerConfig struct {
+	Logger hclog.Logger
+	Config *config.EnvoySDS
+}
+
+// Server serves the secrets of its configuration over SDS
+type Server struct {
+	logger hclog.Logger
+	config *config.EnvoySDS
+
+	// cache holds the secrets served, indexed by name
+	cache *cachev3.LinearCache
+}
+
+// NewServer returns a new configured server
+func NewServer(conf *ServerConfig) *Server {
+	return &Server{
+		logger: conf.Logger,
+		config: conf.Config,
+		cache:  cachev3.NewLinearCache(resourcev3.SecretType),
+	}
+}
+
+// Run serves the secrets on the Unix socket of the configuration until the
+// context is done. The secrets whose files were already rendered are served
+// right away.
+func (s *Server) Run(ctx context.Context) error {
+	s.logger.Info("starting envoy sds server", "address", s.config.Address)
+	defer s.logger.Info("envoy sds server stopped")
+
+	// Remove the socket of a previous run
+	if err := os.Remove(s.config.Address); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("envoy sds server: error removing existing socket: %w", err)
+	}
+	ln, err := net.Listen("unix", s.config.Address)
+	if err != nil {
+		return fmt.Errorf("envoy sds server: error listening: %w", err)
+	}
+	defer ln.Close()
+	if err := os.Chmod(s.config.Address, s.config.SocketMode); err != nil {
+		return fmt.Errorf("envoy sds server: error setting socket mode: %w", err)
+	}
+
+	for _, secret := range s.config.Secrets {
+		if err := s.load(secret); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warn("error loading secret", "secret", secret.Name, "error", err)
+		}
+	}
+
+	grpcServer := grpc.NewServer()
+	secretv3.RegisterSecretDiscoveryServiceServer(grpcServer, serverv3.NewServer(ctx, s.cache, nil))
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- grpcServer.Serve(ln)
+	}()
+
+	select {
+	case <-ctx.Done():
+		grpcServer.Stop()
+		return nil
+	case err := <-errCh:
+		return fmt.Errorf("envoy sds server: %w", err)
+	}
+}
+
+// Rendered reloads the secrets using the files at the destinations, and
+// pushes them to Envoy. It is called when templates are rendered.
+func (s *Server) Rendered(destinations []string) {
+	rendered := make(map[string]bool, len(destinations))
+	for _, dest := range destinations {
+		rendered[filepath.Clean(dest)] = true
+	}
+
+	for _, secret := range s.config.Secrets {
+		if !rendered[filepath.Clean(secret.CertFile)] && !rendered[filepath.Clean(secret.KeyFile)] && !rendered[filepath.Clean(secret.CAFile)] {
+			continue
+		}
+		if err := s.load(secret); err != nil {
+			// The files of the secret may be rendered by different
+			// templates, so wait for the others to be rendered
+			s.logger.Warn("error loading secret, keeping the previous version", "secret", secret.Name, "error", err)
+			continue
+		}
+		s.logger.Info("secret updated", "secret", secret.Name)
+	}
+}
+
+// load reads the files of the secret, and serves it if it changed.
+func (s *Server) load(secret *config.EnvoySDSSecret) error {
+	resource, err := readSecret(secret)
+	if err != nil {
+		return err
+	}
+	return s.cache.UpdateResource(secret.Name, resource)
+}
+
+// readSecret reads the files of the secret, and returns the Envoy secret.
+func readSecret(secret *config.EnvoySDSSecret) (*tlsv3.Secret, error) {
+	if secret.CertFile == "" {
+		ca, _, err := readPEM(secret.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		if len(ca) == 0 {
+			return nil, fmt.Errorf("no certificate found in %q", secret.CAFile)
+		}
+		return &tlsv3.Secret{
+			Name: secret.Name,
+			Type: &tlsv3.Secret_ValidationContext{
+				ValidationContext: &tlsv3.CertificateValidationContext{
+					TrustedCa: inlineBytes(ca),
+				},
+			},
+		}, nil
+	}
+
+	chain, key, err := readPEM(secret.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	if secret.KeyFile != "" {
+		if _, key, err = readPEM(secret.KeyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// Catch certificates and keys rendered by different templates that are
+	// not rendered in sync yet
+	if _, err := tls.X509KeyPair(chain, key); err != nil {
+		return nil, err
+	}
+
+	return &tlsv3.Secret{
+		Name: secret.Name,
+		Type: &tlsv3.Secret_TlsCertificate{
+			TlsCertificate: &tlsv3.TlsCertificate{
+				CertificateChain: inlineBytes(chain),
+				PrivateKey:       inlineBytes(key),
+			},
+		},
+	}, nil
+}
+
+// readPEM returns the PEM encoded certificates and private key in the file.
+func readPEM(path string) (certs []byte, key []byte, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		switch {
+		case block.Type == "CERTIFICATE":
+			certs = append(certs, pem.EncodeToMemory(block)...)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			key = pem.EncodeToMemory(block)
+		}
+	}
+	return certs, key, nil
+}
+
+func inlineBytes(data []byte) *corev3.DataSource {
+	return &corev3.DataSource{
+		Specifier: &corev3.DataSource_InlineBytes{InlineBytes: data},
+	}
+}
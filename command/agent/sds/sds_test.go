@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package sds
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tlsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	secretv3 "github.com/envoyproxy/go-control-plane/envoy/service/secret/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/hashicorp/vault/command/agent/config"
+)
+
+// testCertificate returns a PEM encoded self-signed certificate and its key.
+func testCertificate(t *testing.T, commonName string) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func receiveSecret(t *testing.T, stream secretv3.SecretDiscoveryService_StreamSecretsClient) (*discoveryv3.DiscoveryResponse, *tlsv3.Secret) {
+	t.Helper()
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Resources) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(resp.Resources))
+	}
+	var secret tlsv3.Secret
+	if err := resp.Resources[0].UnmarshalTo(&secret); err != nil {
+		t.Fatal(err)
+	}
+	return resp, &secret
+}
+
+func TestServer(t *testing.T) {
+	// Unix socket paths are limited to about 100 characters
+	dir, err := os.MkdirTemp("", "sds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "web.pem")
+	cert, key := testCertificate(t, "first")
+	if err := os.WriteFile(certFile, append(cert, key...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(&ServerConfig{
+		Logger: hclog.NewNullLogger(),
+		Config: &config.EnvoySDS{
+			Address:    filepath.Join(dir, "sds.sock"),
+			SocketMode: 0o600,
+			Secrets: []*config.EnvoySDSSecret{
+				{Name: "web", CertFile: certFile},
+			},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run(ctx)
+	}()
+
+	conn, err := grpc.DialContext(ctx, "unix://"+filepath.Join(dir, "sds.sock"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stream, err := secretv3.NewSecretDiscoveryServiceClient(conn).StreamSecrets(ctx, grpc.WaitForReady(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&discoveryv3.DiscoveryRequest{
+		TypeUrl:       resourcev3.SecretType,
+		ResourceNames: []string{"web"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, secret := receiveSecret(t, stream)
+	tlsCert := secret.GetTlsCertificate()
+	if secret.Name != "web" || tlsCert == nil {
+		t.Fatalf("bad: %#v", secret)
+	}
+	if string(tlsCert.CertificateChain.GetInlineBytes()) != string(cert) || string(tlsCert.PrivateKey.GetInlineBytes()) != string(key) {
+		t.Fatal("unexpected certificate")
+	}
+
+	// Acknowledge the secret, and rotate it
+	err = stream.Send(&discoveryv3.DiscoveryRequest{
+		TypeUrl:       resourcev3.SecretType,
+		ResourceNames: []string{"web"},
+		VersionInfo:   resp.VersionInfo,
+		ResponseNonce: resp.Nonce,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, key = testCertificate(t, "second")
+	if err := os.WriteFile(certFile, append(cert, key...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	server.Rendered([]string{certFile})
+
+	_, secret = receiveSecret(t, stream)
+	tlsCert = secret.GetTlsCertificate()
+	if string(tlsCert.CertificateChain.GetInlineBytes()) != string(cert) {
+		t.Fatal("expected the rotated certificate")
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadSecret(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := testCertificate(t, "web")
+	_, otherKey := testCertificate(t, "other")
+
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	certFile := write("cert.pem", cert)
+	keyFile := write("key.pem", key)
+	otherKeyFile := write("other-key.pem", otherKey)
+
+	secret, err := readSecret(&config.EnvoySDSSecret{Name: "web", CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.GetTlsCertificate() == nil {
+		t.Fatalf("bad: %#v", secret)
+	}
+
+	// The certificate and the key don't match, such as when they are
+	// rendered by different templates that are not in sync yet
+	if _, err := readSecret(&config.EnvoySDSSecret{Name: "web", CertFile: certFile, KeyFile: otherKeyFile}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	secret, err = readSecret(&config.EnvoySDSSecret{Name: "ca", CAFile: certFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.GetValidationContext().GetTrustedCa().GetInlineBytes()) != string(cert) {
+		t.Fatalf("bad: %#v", secret)
+	}
+
+	if _, err := readSecret(&config.EnvoySDSSecret{Name: "ca", CAFile: keyFile}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package template
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/crypto/ocsp"
+)
+
+// revocationCheckTimeout is the timeout of the OCSP and CRL requests
+const revocationCheckTimeout = 30 * time.Second
+
+// maxRevocationResponseSize is the maximum size of the OCSP responses, CRLs
+// and issuer certificates fetched
+const maxRevocationResponseSize = 32 << 20
+
+// revocationChecker checks the revocation status of the certificates rendered
+// by templates, with OCSP, or with their CRL when OCSP is not available.
+type revocationChecker struct {
+	logger hclog.Logger
+	client *http.Client
+}
+
+func newRevocationChecker(logger hclog.Logger) *revocationChecker {
+	client := cleanhttp.DefaultPooledClient()
+	client.Timeout = revocationCheckTimeout
+	return &revocationChecker{
+		logger: logger,
+		client: client,
+	}
+}
+
+// revoked returns the destinations containing a revoked certificate.
+func (c *revocationChecker) revoked(ctx context.Context, destinations []string) []string {
+	var revoked []string
+	for _, dest := range destinations {
+		isRevoked, err := c.isRevoked(ctx, dest)
+		if err != nil {
+			c.logger.Warn("error checking certificate revocation", "destination", dest, "error", err)
+			continue
+		}
+		if isRevoked {
+			revoked = append(revoked, dest)
+		}
+	}
+	return revoked
+}
+
+// isRevoked returns whether the certificate in the file is revoked. Files
+// without certificates, or whose certificates have no OCSP server or CRL
+// distribution point, are never revoked.
+func (c *revocationChecker) isRevoked(ctx context.Context, path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// The template hasn't been rendered yet
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	leaf, issuer := parseCertificates(data)
+	if leaf == nil || (len(leaf.OCSPServer) == 0 && len(leaf.CRLDistributionPoints) == 0) {
+		return false, nil
+	}
+	if issuer == nil && len(leaf.IssuingCertificateURL) > 0 {
+		if issuer, err = c.fetchIssuer(ctx, leaf); err != nil {
+			c.logger.Debug("error fetching issuer certificate", "destination", path, "error", err)
+		}
+	}
+
+	if len(leaf.OCSPServer) > 0 && issuer != nil {
+		revoked, err := c.checkOCSP(ctx, leaf, issuer)
+		if err == nil || len(leaf.CRLDistributionPoints) == 0 {
+			return revoked, err
+		}
+		c.logger.Debug("error checking certificate revocation with OCSP, falling back to CRL", "destination", path, "error", err)
+	}
+	if len(leaf.CRLDistributionPoints) == 0 {
+		return false, errors.New("issuer certificate required to check the revocation status with OCSP")
+	}
+	return c.checkCRL(ctx, leaf, issuer)
+}
+
+// checkOCSP returns whether the certificate is revoked according to its OCSP
+// server.
+func (c *revocationChecker) checkOCSP(ctx context.Context, leaf, issuer *x509.Certificate) (bool, error) {
+	ocspReq, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, leaf.OCSPServer[0], bytes.NewReader(ocspReq))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("Accept", "application/ocsp-response")
+	body, err := c.do(req)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := ocsp.ParseResponseForCert(body, leaf, issuer)
+	if err != nil {
+		return false, err
+	}
+	switch resp.Status {
+	case ocsp.Good:
+		return false, nil
+	case ocsp.Revoked:
+		return true, nil
+	default:
+		return false, errors.New("unknown OCSP status")
+	}
+}
+
+// checkCRL returns whether the certificate is revoked according to the CRL of
+// its distribution point. The signature of the CRL is only verified when the
+// issuer is known.
+func (c *revocationChecker) checkCRL(ctx context.Context, leaf, issuer *x509.Certificate) (bool, error) {
+	der, err := c.get(ctx, leaf.CRLDistributionPoints[0])
+	if err != nil {
+		return false, err
+	}
+
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return false, err
+	}
+	if issuer != nil {
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			return false, fmt.Errorf("invalid CRL signature: %w", err)
+		}
+	}
+
+	for _, revoked := range crl.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fetchIssuer fetches the issuer of the certificate from its issuing
+// certificate URL.
+func (c *revocationChecker) fetchIssuer(ctx context.Context, leaf *x509.Certificate) (*x509.Certificate, error) {
+	der, err := c.get(ctx, leaf.IssuingCertificateURL[0])
+	if err != nil {
+		return nil, err
+	}
+	issuer, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	if err := leaf.CheckSignatureFrom(issuer); err != nil {
+		return nil, err
+	}
+	return issuer, nil
+}
+
+// get fetches the DER or PEM encoded object at the URL, and returns it DER
+// encoded.
+func (c *revocationChecker) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if block, _ := pem.Decode(body); block != nil {
+		return block.Bytes, nil
+	}
+	return body, nil
+}
+
+func (c *revocationChecker) do(req *http.Request) ([]byte, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, req.URL)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxRevocationResponseSize))
+}
+
+// parseCertificates returns the first certificate in the PEM encoded data
+// that is not a CA, and its issuer if it's in the data as well.
+func parseCertificates(data []byte) (leaf, issuer *x509.Certificate) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if leaf == nil && !cert.IsCA {
+			leaf = cert
+		}
+		certs = append(certs, cert)
+	}
+	if leaf == nil {
+		return nil, nil
+	}
+
+	for _, cert := range certs {
+		if cert != leaf && leaf.CheckSignatureFrom(cert) == nil {
+			return leaf, cert
+		}
+	}
+	return leaf, nil
+}
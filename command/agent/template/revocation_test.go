@@ -0,0 +1,318 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package template
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	ctconfig "github.com/hashicorp/consul-template/config"
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/hashicorp/vault/command/agent/config"
+	"github.com/hashicorp/vault/sdk/helper/pointerutil"
+)
+
+// testPKI is a CA issuing certificates, with an OCSP responder and a CRL
+// distribution point.
+type testPKI struct {
+	server *httptest.Server
+	ca     *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+
+	l       sync.Mutex
+	serial  int64
+	revoked map[int64]bool
+	issued  int
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &testPKI{ca: ca, caKey: caKey, serial: 1, revoked: make(map[int64]bool)}
+	p.server = httptest.NewServer(p)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *testPKI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	switch r.URL.Path {
+	case "/ocsp":
+		body, _ := io.ReadAll(r.Body)
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		template := ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now(),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+		if p.revoked[req.SerialNumber.Int64()] {
+			template.Status = ocsp.Revoked
+			template.RevokedAt = time.Now()
+		}
+		resp, err := ocsp.CreateResponse(p.ca, p.ca, template, p.caKey)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(resp)
+
+	case "/crl":
+		var revoked []pkix.RevokedCertificate
+		for serial := range p.revoked {
+			revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+		}
+		crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:              big.NewInt(1),
+			ThisUpdate:          time.Now(),
+			NextUpdate:          time.Now().Add(time.Hour),
+			RevokedCertificates: revoked,
+		}, p.ca, p.caKey)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(crl)
+
+	case "/ca":
+		w.Write(p.ca.Raw)
+
+	case "/v1/pki/issue/web":
+		cert, key := p.issue(true, true)
+		p.issued++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"certificate": string(cert),
+				"private_key": string(key),
+				"issuing_ca":  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.ca.Raw})),
+			},
+		})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// issue returns a new PEM encoded certificate and its key. It must be called
+// with the lock held.
+func (p *testPKI) issue(withOCSP, withCRL bool) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	p.serial++
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(p.serial),
+		Subject:               pkix.Name{CommonName: "web"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IssuingCertificateURL: []string{p.server.URL + "/ca"},
+	}
+	if withOCSP {
+		template.OCSPServer = []string{p.server.URL + "/ocsp"}
+	}
+	if withCRL {
+		template.CRLDistributionPoints = []string{p.server.URL + "/crl"}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, p.ca, &key.PublicKey, p.caKey)
+	if err != nil {
+		panic(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func (p *testPKI) revoke(serial int64) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.revoked[serial] = true
+}
+
+func TestRevocationChecker(t *testing.T) {
+	p := newTestPKI(t)
+	checker := newRevocationChecker(hclog.NewNullLogger())
+	dir := t.TempDir()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.ca.Raw})
+
+	for name, tc := range map[string]struct {
+		withOCSP, withCRL, withCA bool
+	}{
+		"ocsp":                   {withOCSP: true, withCA: true},
+		"ocsp-fetched-issuer":    {withOCSP: true},
+		"crl":                    {withCRL: true, withCA: true},
+		"ocsp-and-crl":           {withOCSP: true, withCRL: true, withCA: true},
+		"crl-unverified-fetched": {withCRL: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p.l.Lock()
+			cert, key := p.issue(tc.withOCSP, tc.withCRL)
+			serial := p.serial
+			p.l.Unlock()
+
+			data := append(cert, key...)
+			if tc.withCA {
+				data = append(data, caPEM...)
+			}
+			path := filepath.Join(dir, name+".pem")
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			revoked, err := checker.isRevoked(context.Background(), path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if revoked {
+				t.Fatal("expected the certificate not to be revoked")
+			}
+
+			p.revoke(serial)
+			revoked, err = checker.isRevoked(context.Background(), path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !revoked {
+				t.Fatal("expected the certificate to be revoked")
+			}
+		})
+	}
+
+	// Files without certificates with revocation information are skipped
+	p.l.Lock()
+	cert, _ := p.issue(false, false)
+	p.l.Unlock()
+	noRevocation := filepath.Join(dir, "no-revocation.pem")
+	if err := os.WriteFile(noRevocation, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(dir, "secret.json")
+	if err := os.WriteFile(secret, []byte(`{"password": "secret"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	revoked := checker.revoked(context.Background(), []string{noRevocation, secret, filepath.Join(dir, "missing.pem")})
+	if len(revoked) != 0 {
+		t.Fatalf("expected no revoked certificates, got %v", revoked)
+	}
+}
+
+// TestServerRun_RevokedCertificate ensures that certificates rendered by
+// pkiCert are re-issued as soon as they are revoked.
+func TestServerRun_RevokedCertificate(t *testing.T) {
+	p := newTestPKI(t)
+	dest := filepath.Join(t.TempDir(), "web.pem")
+
+	agentConfig := newAgentConfig(nil, false, false)
+	agentConfig.Vault.Address = p.server.URL
+	agentConfig.Vault.CACert = ""
+	agentConfig.Vault.CAPath = ""
+	agentConfig.Vault.ClientCert = ""
+	agentConfig.Vault.ClientKey = ""
+	agentConfig.TemplateConfig = &config.TemplateConfig{
+		RevocationCheckInterval: 100 * time.Millisecond,
+	}
+
+	server := NewServer(&ServerConfig{
+		Logger:      hclog.NewNullLogger(),
+		LogWriter:   hclog.DefaultOutput,
+		AgentConfig: agentConfig,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tokenCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run(ctx, tokenCh, []*ctconfig.TemplateConfig{{
+			Contents:    pointerutil.StringPtr(`{{ with pkiCert "pki/issue/web" "common_name=web" }}{{ .Cert }}{{ .CA }}{{ .Key }}{{ end }}`),
+			Destination: pointerutil.StringPtr(dest),
+		}})
+	}()
+	tokenCh <- "test"
+
+	waitForSerial := func(serial int64) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			data, _ := os.ReadFile(dest)
+			if leaf, _ := parseCertificates(data); leaf != nil && leaf.SerialNumber.Int64() == serial {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the certificate with serial %d to be rendered", serial)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	waitForSerial(2)
+
+	p.revoke(2)
+	waitForSerial(3)
+
+	p.l.Lock()
+	issued := p.issued
+	p.l.Unlock()
+	if issued != 2 {
+		t.Fatalf("expected 2 certificates to be issued, got %d", issued)
+	}
+
+	cancel()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal(fmt.Errorf("template server didn't stop"))
+	}
+}
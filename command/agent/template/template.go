@@ -13,9 +13,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"time"
 
 	"go.uber.org/atomic"
 
+	"github.com/armon/go-metrics"
 	ctconfig "github.com/hashicorp/consul-template/config"
 	"github.com/hashicorp/consul-template/manager"
 	"github.com/hashicorp/go-hclog"
@@ -44,6 +47,10 @@ type ServerConfig struct {
 	// the same io.Writer that Vault Agent itself is using.
 	LogLevel  hclog.Level
 	LogWriter io.Writer
+
+	// OnRender, if set, is called with the destinations of the templates
+	// rendered, each time templates are rendered.
+	OnRender func(destinations []string)
 }
 
 // Server manages the Consul Template Runner which renders templates
@@ -64,6 +71,10 @@ type Server struct {
 	// from the runner in the event we're using exit after auth.
 	lookupMap map[string][]*ctconfig.TemplateConfig
 
+	// lastRendered is the time each template was last rendered, indexed by
+	// consul-template ID
+	lastRendered map[string]time.Time
+
 	DoneCh  chan struct{}
 	stopped *atomic.Bool
 
@@ -145,12 +156,41 @@ func (ts *Server) Run(ctx context.Context, incoming chan string, templates []*ct
 	}
 	ts.lookupMap = lookupMap
 
+	// Check the revocation status of the certificates rendered, if enabled
+	var revokedCh chan []string
+	if tc := ts.config.AgentConfig.TemplateConfig; tc != nil && tc.RevocationCheckInterval > 0 {
+		revokedCh = make(chan []string)
+		go ts.watchRevocation(ctx, tc.RevocationCheckInterval, templates, revokedCh)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			ts.runner.Stop()
 			return nil
 
+		case revoked := <-revokedCh:
+			if !ts.runnerStarted.Load() || ts.exitAfterAuth {
+				continue
+			}
+			ts.logger.Warn("certificates revoked, requesting new certificates", "destinations", revoked)
+			metrics.IncrCounter([]string{"agent", "template", "revocation_renewal"}, float32(len(revoked)))
+
+			// The pkiCert function only issues a new certificate when the
+			// destination doesn't contain a valid one
+			for _, dest := range revoked {
+				if err := os.Remove(dest); err != nil && !errors.Is(err, os.ErrNotExist) {
+					ts.logger.Error("error removing revoked certificate", "destination", dest, "error", err)
+				}
+			}
+
+			ts.runner.Stop()
+			ts.runner, err = manager.NewRunner(runnerConfig, false)
+			if err != nil {
+				return fmt.Errorf("template server failed to create: %w", err)
+			}
+			go ts.runner.Start()
+
 		case token := <-incoming:
 			if token != *latestToken {
 				ts.logger.Info("template server received new token")
@@ -202,6 +242,7 @@ func (ts *Server) Run(ctx context.Context, incoming chan string, templates []*ct
 		case <-ts.runner.TemplateRenderedCh():
 			// A template has been rendered, figure out what to do
 			events := ts.runner.RenderEvents()
+			ts.notifyRendered(events)
 
 			// events are keyed by template ID, and can be matched up to the id's from
 			// the lookupMap
@@ -230,6 +271,66 @@ func (ts *Server) Run(ctx context.Context, incoming chan string, templates []*ct
 	}
 }
 
+// watchRevocation checks the revocation status of the certificates rendered
+// by the templates at each interval, and sends the destinations containing
+// revoked certificates to revokedCh.
+func (ts *Server) watchRevocation(ctx context.Context, interval time.Duration, templates []*ctconfig.TemplateConfig, revokedCh chan<- []string) {
+	var destinations []string
+	for _, tmpl := range templates {
+		if tmpl.Destination != nil {
+			destinations = append(destinations, *tmpl.Destination)
+		}
+	}
+	checker := newRevocationChecker(ts.logger.Named("revocation"))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		revoked := checker.revoked(ctx, destinations)
+		if len(revoked) == 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case revokedCh <- revoked:
+		}
+	}
+}
+
+// notifyRendered calls OnRender with the destinations of the templates
+// rendered since the last call.
+func (ts *Server) notifyRendered(events map[string]*manager.RenderEvent) {
+	if ts.config.OnRender == nil {
+		return
+	}
+	if ts.lastRendered == nil {
+		ts.lastRendered = make(map[string]time.Time)
+	}
+
+	var destinations []string
+	for id, event := range events {
+		if event.LastDidRender.IsZero() || !event.LastDidRender.After(ts.lastRendered[id]) {
+			continue
+		}
+		ts.lastRendered[id] = event.LastDidRender
+		for _, tmpl := range event.TemplateConfigs {
+			if tmpl.Destination != nil {
+				destinations = append(destinations, *tmpl.Destination)
+			}
+		}
+	}
+	if len(destinations) > 0 {
+		ts.config.OnRender(destinations)
+	}
+}
+
 func (ts *Server) Stop() {
 	if ts.stopped.CAS(false, true) {
 		close(ts.DoneCh)
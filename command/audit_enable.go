@@ -96,6 +96,8 @@ func (c *AuditEnableCommand) AutocompleteArgs() complete.Predictor {
 		"file",
 		"syslog",
 		"socket",
+		"kafka",
+		"otel",
 	)
 }
 
@@ -113,7 +115,7 @@ func (c *AuditEnableCommand) Run(args []string) int {
 
 	args = f.Args()
 	if len(args) < 1 {
-		c.UI.Error("Error enabling audit device: audit type missing. Valid types include 'file', 'socket' and 'syslog'.")
+		c.UI.Error("Error enabling audit device: audit type missing. Valid types include 'file', 'kafka', 'otel', 'socket' and 'syslog'.")
 		return 1
 	}
 
@@ -35,7 +35,7 @@ func TestAuditEnableCommand_Run(t *testing.T) {
 		{
 			"empty",
 			nil,
-			"Error enabling audit device: audit type missing. Valid types include 'file', 'socket' and 'syslog'.",
+			"Error enabling audit device: audit type missing. Valid types include 'file', 'kafka', 'otel', 'socket' and 'syslog'.",
 			1,
 		},
 		{
@@ -191,6 +191,12 @@ func TestAuditEnableCommand_Run(t *testing.T) {
 			switch b {
 			case "file":
 				args = append(args, "file_path=discard")
+			case "kafka":
+				args = append(args, "brokers=127.0.0.1:9092",
+					"skip_test=true")
+			case "otel":
+				args = append(args, "endpoint=127.0.0.1:4317",
+					"skip_test=true")
 			case "socket":
 				args = append(args, "address=127.0.0.1:8888",
 					"skip_test=true")
@@ -58,6 +58,7 @@ type BaseCommand struct {
 	flagUnlockKey         string
 
 	flagFormat           string
+	flagTemplate         string
 	flagField            string
 	flagDetailed         bool
 	flagOutputCurlString bool
@@ -521,10 +522,26 @@ func (c *BaseCommand) flagSet(bit FlagSetBit) *FlagSets {
 					Target:     &c.flagFormat,
 					Default:    "table",
 					EnvVar:     EnvVaultFormat,
-					Completion: complete.PredictSet("table", "json", "yaml", "pretty", "raw"),
+					Completion: complete.PredictSet("table", "json", "jsonl", "csv", "yaml", "pretty", "raw"),
 					Usage: `Print the output in the given format. Valid formats
-						are "table", "json", "yaml", or "pretty". "raw" is allowed
-						for 'vault read' operations only.`,
+						are "table", "json", "jsonl", "csv", "yaml", or "pretty".
+						"jsonl" and "csv" print a record for each listed key, or
+						a single record of the response's fields otherwise, with
+						a stable field ordering. "raw" is allowed for 'vault read'
+						operations only.`,
+				})
+
+				outputSet.StringVar(&StringVar{
+					Name:       "template",
+					Target:     &c.flagTemplate,
+					Default:    "",
+					Completion: complete.PredictAnything,
+					Usage: `Print the output with the given Go template, executed
+						once for each record that -format=jsonl would print. For
+						lists, records have a "key" field and the fields of the
+						key's additional information. The "json" and "join"
+						functions are available. This takes precedence over
+						-format.`,
 				})
 			}
 
@@ -115,6 +115,7 @@ func (b *BaseCommand) PredictVaultAvailableAuths() complete.Predictor {
 		"plugin",
 		"radius",
 		"userpass",
+		"webauthn",
 	)
 }
 
@@ -396,6 +396,7 @@ func TestPredict_Plugins(t *testing.T) {
 				"transform",
 				"transit",
 				"userpass",
+				"webauthn",
 			},
 		},
 	}
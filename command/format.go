@@ -5,12 +5,14 @@ package command
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/ghodss/yaml"
@@ -68,12 +70,26 @@ type Formatter interface {
 }
 
 var Formatters = map[string]Formatter{
-	"json":   JsonFormatter{},
-	"table":  TableFormatter{},
-	"yaml":   YamlFormatter{},
-	"yml":    YamlFormatter{},
-	"pretty": PrettyFormatter{},
-	"raw":    RawFormatter{},
+	"json":     JsonFormatter{},
+	"jsonl":    JsonLinesFormatter{},
+	"csv":      CsvFormatter{},
+	"template": TemplateFormatter{},
+	"table":    TableFormatter{},
+	"yaml":     YamlFormatter{},
+	"yml":      YamlFormatter{},
+	"pretty":   PrettyFormatter{},
+	"raw":      RawFormatter{},
+}
+
+// isRecordFormat returns whether the format outputs records: one per listed
+// key, or a single one holding the fields of other responses.
+func isRecordFormat(format string) bool {
+	switch format {
+	case "jsonl", "csv", "template":
+		return true
+	default:
+		return false
+	}
 }
 
 func Format(ui cli.Ui) string {
@@ -99,6 +115,15 @@ func Detailed(ui cli.Ui) bool {
 	return false
 }
 
+func Template(ui cli.Ui) string {
+	switch ui := ui.(type) {
+	case *VaultUI:
+		return ui.template
+	}
+
+	return ""
+}
+
 // An output formatter for json output of an object
 type JsonFormatter struct{}
 
@@ -129,6 +154,294 @@ func (j JsonFormatter) Output(ui cli.Ui, secret *api.Secret, data interface{}) e
 	return nil
 }
 
+// An output formatter for JSON Lines output of records
+type JsonLinesFormatter struct{}
+
+func (j JsonLinesFormatter) Format(data interface{}) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+func (j JsonLinesFormatter) Output(ui cli.Ui, secret *api.Secret, data interface{}) error {
+	TableFormatter{}.printWarnings(ui, secret)
+
+	_, records, err := outputRecords(secret, data)
+	if err != nil {
+		return err
+	}
+
+	lines := make([]string, 0, len(records))
+	for _, record := range records {
+		b, err := j.Format(record)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, string(b))
+	}
+
+	if len(lines) > 0 {
+		ui.Output(strings.Join(lines, "\n"))
+	}
+	return nil
+}
+
+// An output formatter for CSV output of records, with a header row
+type CsvFormatter struct{}
+
+func (c CsvFormatter) Format(data interface{}) ([]byte, error) {
+	columns, records, err := outputRecords(nil, data)
+	if err != nil {
+		// Not a record, such as a single field's value
+		return []byte(csvValue(data)), nil
+	}
+
+	return c.format(columns, records)
+}
+
+func (c CsvFormatter) format(columns []string, records []map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = csvValue(record[column])
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), w.Error()
+}
+
+func (c CsvFormatter) Output(ui cli.Ui, secret *api.Secret, data interface{}) error {
+	TableFormatter{}.printWarnings(ui, secret)
+
+	columns, records, err := outputRecords(secret, data)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	b, err := c.format(columns, records)
+	if err != nil {
+		return err
+	}
+
+	ui.Output(string(b))
+	return nil
+}
+
+// csvValue formats a value for a CSV cell: scalars as is, and everything else
+// as compact JSON.
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number, bool, int, int64, float64:
+		return fmt.Sprintf("%v", v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	}
+}
+
+// An output formatter executing the Go template given with -template against
+// each record
+type TemplateFormatter struct{}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": func(sep string, v interface{}) string {
+		switch v := v.(type) {
+		case []string:
+			return strings.Join(v, sep)
+		case []interface{}:
+			values := make([]string, len(v))
+			for i, value := range v {
+				values[i] = csvValue(value)
+			}
+			return strings.Join(values, sep)
+		default:
+			return csvValue(v)
+		}
+	},
+}
+
+func (t TemplateFormatter) Format(data interface{}) ([]byte, error) {
+	return nil, errors.New("the template format can only be used to output responses")
+}
+
+func (t TemplateFormatter) Output(ui cli.Ui, secret *api.Secret, data interface{}) error {
+	text := Template(ui)
+	if text == "" {
+		return errors.New("the template format requires a template to be given with -template")
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("error parsing template: %w", err)
+	}
+
+	TableFormatter{}.printWarnings(ui, secret)
+
+	_, records, err := outputRecords(secret, data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		if err := tmpl.Execute(&buf, record); err != nil {
+			return fmt.Errorf("error executing template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+	}
+
+	if buf.Len() > 0 {
+		ui.Output(strings.TrimSuffix(buf.String(), "\n"))
+	}
+	return nil
+}
+
+// outputRecords flattens the data to output into records, along with their
+// columns in a stable order. Lists result in a record per key, sorted, with
+// the key's additional information when the response has any; secrets and
+// other objects result in a single record of their fields.
+func outputRecords(secret *api.Secret, data interface{}) ([]string, []map[string]interface{}, error) {
+	switch data := data.(type) {
+	case *api.Secret:
+		return secretRecord(data)
+	case []interface{}:
+		keys := make([]string, len(data))
+		for i, v := range data {
+			key, ok := v.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("%v is not a string", v)
+			}
+			keys[i] = key
+		}
+		sort.Strings(keys)
+
+		var keyInfo map[string]interface{}
+		if secret != nil {
+			keyInfo, _ = secret.Data["key_info"].(map[string]interface{})
+		}
+		return listRecords(keys, keyInfo)
+	case []string:
+		return listRecords(data, nil)
+	case map[string]interface{}:
+		return mapRecord(nil, data)
+	case nil:
+		return nil, nil, nil
+	default:
+		// Fall back to the fields of the JSON output, such as for structs
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return nil, nil, fmt.Errorf("cannot output records for this type: %T", data)
+		}
+		return mapRecord(nil, fields)
+	}
+}
+
+func listRecords(keys []string, keyInfo map[string]interface{}) ([]string, []map[string]interface{}, error) {
+	seen := make(map[string]bool)
+	records := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		record := map[string]interface{}{}
+		if info, ok := keyInfo[key].(map[string]interface{}); ok {
+			for k, v := range info {
+				if k == "key" {
+					continue
+				}
+				record[k] = v
+				seen[k] = true
+			}
+		}
+		record["key"] = key
+		records = append(records, record)
+	}
+
+	columns := make([]string, 0, len(seen))
+	for k := range seen {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+
+	return append([]string{"key"}, columns...), records, nil
+}
+
+func secretRecord(secret *api.Secret) ([]string, []map[string]interface{}, error) {
+	if secret == nil {
+		return nil, nil, nil
+	}
+
+	// As in the table output, lease and wrapping information come first.
+	var columns []string
+	record := map[string]interface{}{}
+	add := func(k string, v interface{}) {
+		columns = append(columns, k)
+		record[k] = v
+	}
+	if secret.LeaseID != "" {
+		add("lease_id", secret.LeaseID)
+		add("lease_duration", secret.LeaseDuration)
+		add("lease_renewable", secret.Renewable)
+	}
+	if secret.WrapInfo != nil {
+		add("wrapping_token", secret.WrapInfo.Token)
+		add("wrapping_accessor", secret.WrapInfo.Accessor)
+		add("wrapping_token_ttl", secret.WrapInfo.TTL)
+		add("wrapping_token_creation_time", secret.WrapInfo.CreationTime.Format(time.RFC3339Nano))
+		add("wrapping_token_creation_path", secret.WrapInfo.CreationPath)
+		if secret.WrapInfo.WrappedAccessor != "" {
+			add("wrapped_accessor", secret.WrapInfo.WrappedAccessor)
+		}
+	}
+
+	return mapRecord(record, secret.Data, columns...)
+}
+
+func mapRecord(record map[string]interface{}, data map[string]interface{}, columns ...string) ([]string, []map[string]interface{}, error) {
+	if record == nil {
+		record = make(map[string]interface{}, len(data))
+	}
+
+	keys := make([]string, 0, len(data))
+	for k, v := range data {
+		if _, present := record[k]; present {
+			continue
+		}
+		keys = append(keys, k)
+		record[k] = v
+	}
+	sort.Strings(keys)
+
+	if len(record) == 0 {
+		return nil, nil, nil
+	}
+	return append(columns, keys...), []map[string]interface{}{record}, nil
+}
+
 // An output formatter for raw output of the original request object
 type RawFormatter struct{}
 
@@ -347,6 +660,14 @@ func (t TableFormatter) OutputSealStatusStruct(ui cli.Ui, secret *api.Secret, da
 		out = append(out, fmt.Sprintf("Seal Migration in Progress | %t", status.Migration))
 	}
 
+	for _, seal := range status.Seals {
+		health := "healthy"
+		if !seal.Healthy {
+			health = fmt.Sprintf("unhealthy since %s", seal.LastSeen.Format(time.RFC3339))
+		}
+		out = append(out, fmt.Sprintf("Seal %q (%s, priority %d) | %s", seal.Name, seal.Type, seal.Priority, health))
+	}
+
 	out = append(out, fmt.Sprintf("Version | %s", status.Version))
 	out = append(out, fmt.Sprintf("Build Date | %s", status.BuildDate))
 	out = append(out, fmt.Sprintf("Storage Type | %s", status.StorageType))
@@ -291,3 +291,198 @@ func Test_Format_Parsing(t *testing.T) {
 		})
 	}
 }
+
+func TestJsonLinesFormatter(t *testing.T) {
+	os.Setenv(EnvVaultFormat, "jsonl")
+	defer os.Setenv(EnvVaultFormat, "")
+	var output string
+	ui := mockUi{t: t, outputData: &output}
+
+	list := &api.Secret{Data: map[string]interface{}{
+		"keys": []interface{}{"foo", "bar"},
+		"key_info": map[string]interface{}{
+			"foo": map[string]interface{}{"type": "kv"},
+		},
+	}}
+	if code := OutputList(ui, list); code != 0 {
+		t.Fatalf("expected 0 to be %d", code)
+	}
+	expected := `{"key":"bar"}` + "\n" + `{"key":"foo","type":"kv"}`
+	if output != expected {
+		t.Fatalf("expected %q to be %q", output, expected)
+	}
+
+	secret := &api.Secret{LeaseID: "lease", LeaseDuration: 60, Data: map[string]interface{}{"b": "2", "a": 1}}
+	if code := OutputSecret(ui, secret); code != 0 {
+		t.Fatalf("expected 0 to be %d", code)
+	}
+	expected = `{"a":1,"b":"2","lease_duration":60,"lease_id":"lease","lease_renewable":false}`
+	if output != expected {
+		t.Fatalf("expected %q to be %q", output, expected)
+	}
+}
+
+func TestCsvFormatter(t *testing.T) {
+	os.Setenv(EnvVaultFormat, "csv")
+	defer os.Setenv(EnvVaultFormat, "")
+	var output string
+	ui := mockUi{t: t, outputData: &output}
+
+	list := &api.Secret{Data: map[string]interface{}{
+		"keys": []interface{}{"foo", "bar"},
+		"key_info": map[string]interface{}{
+			"foo": map[string]interface{}{"type": "kv", "options": map[string]interface{}{"version": "2"}},
+			"bar": map[string]interface{}{"type": "pki, with comma"},
+		},
+	}}
+	if code := OutputList(ui, list); code != 0 {
+		t.Fatalf("expected 0 to be %d", code)
+	}
+	expected := "key,options,type\n" +
+		`bar,,"pki, with comma"` + "\n" +
+		`foo,"{""version"":""2""}",kv`
+	if output != expected {
+		t.Fatalf("expected %q to be %q", output, expected)
+	}
+
+	// Lease information comes first, as in the table output
+	secret := &api.Secret{LeaseID: "lease", LeaseDuration: 60, Renewable: true, Data: map[string]interface{}{"b": "2", "a": 1}}
+	if code := OutputSecret(ui, secret); code != 0 {
+		t.Fatalf("expected 0 to be %d", code)
+	}
+	expected = "lease_id,lease_duration,lease_renewable,a,b\nlease,60,true,1,2"
+	if output != expected {
+		t.Fatalf("expected %q to be %q", output, expected)
+	}
+}
+
+func TestTemplateFormatter(t *testing.T) {
+	var output string
+	ui := &VaultUI{
+		Ui:       mockUi{t: t, outputData: &output},
+		format:   "template",
+		template: `{{ .key }}={{ .type }} {{ json .options }}`,
+	}
+
+	list := &api.Secret{Data: map[string]interface{}{
+		"keys": []interface{}{"foo", "bar"},
+		"key_info": map[string]interface{}{
+			"foo": map[string]interface{}{"type": "kv", "options": map[string]interface{}{"version": "2"}},
+			"bar": map[string]interface{}{"type": "pki"},
+		},
+	}}
+	if code := OutputList(ui, list); code != 0 {
+		t.Fatalf("expected 0 to be %d", code)
+	}
+	expected := "bar=pki null\nfoo=kv {\"version\":\"2\"}"
+	if output != expected {
+		t.Fatalf("expected %q to be %q", output, expected)
+	}
+
+	ui.template = `{{ .username }}:{{ join "," .groups }}`
+	secret := &api.Secret{Data: map[string]interface{}{"username": "alice", "groups": []interface{}{"a", "b"}}}
+	if code := OutputSecret(ui, secret); code != 0 {
+		t.Fatalf("expected 0 to be %d", code)
+	}
+	if output != "alice:a,b" {
+		t.Fatalf("expected %q to be %q", output, "alice:a,b")
+	}
+
+	ui.template = `{{ .username`
+	if code := OutputSecret(ui, secret); code != 1 {
+		t.Fatalf("expected 1 to be %d", code)
+	}
+}
+
+func TestSetupTemplate(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"list", "secret/"}, ""},
+		{[]string{"list", "-template={{.key}}", "secret/"}, "{{.key}}"},
+		{[]string{"list", "--template", "{{.key}}={{.type}}", "secret/"}, "{{.key}}={{.type}}"},
+		{[]string{"write", "secret/foo", "--", "-template=nope"}, ""},
+	}
+
+	for _, tc := range cases {
+		if template := setupTemplate(tc.args); template != tc.expected {
+			t.Errorf("%v: expected %q to be %q", tc.args, template, tc.expected)
+		}
+	}
+}
+
+func Test_Format_Records(t *testing.T) {
+	defer func() {
+		os.Setenv(EnvVaultCLINoColor, "")
+		os.Setenv(EnvVaultFormat, "")
+	}()
+
+	client, closer := testVaultServer(t)
+	defer closer()
+
+	for _, path := range []string{"secret/list/foo", "secret/list/bar"} {
+		if _, err := client.Logical().Write(path, map[string]interface{}{"user": "alice", "pass": "hunter2"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := client.Sys().Mount("kv-v2/", &api.MountInput{Type: "kv-v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Logical().Write("kv-v2/data/foo", map[string]interface{}{
+		"data": map[string]interface{}{"user": "bob"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		args []string
+		out  string
+	}{
+		{
+			"list_csv",
+			[]string{"list", "-format=csv", "secret/list"},
+			"key\nbar\nfoo\n",
+		},
+		{
+			"list_template",
+			[]string{"list", "-template={{.key}}!", "secret/list"},
+			"bar!\nfoo!\n",
+		},
+		{
+			"read_jsonl",
+			[]string{"read", "-format=jsonl", "secret/list/foo"},
+			`{"pass":"hunter2","user":"alice"}` + "\n",
+		},
+		{
+			"kv_get_csv",
+			[]string{"kv", "get", "-format=csv", "kv-v2/foo"},
+			"user\nbob\n",
+		},
+		{
+			"template_without_flag",
+			[]string{"read", "-format=template", "secret/list/foo"},
+			"requires a template",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout := bytes.NewBuffer(nil)
+			stderr := bytes.NewBuffer(nil)
+			runOpts := &RunOptions{
+				Stdout: stdout,
+				Stderr: stderr,
+				Client: client,
+			}
+
+			RunCustom(tc.args, runOpts)
+			combined := stdout.String() + stderr.String()
+			if !strings.Contains(combined, tc.out) {
+				t.Errorf("expected %q to contain %q", combined, tc.out)
+			}
+		})
+	}
+}
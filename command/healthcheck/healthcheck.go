@@ -289,4 +289,8 @@ type Result struct {
 	StatusDisplay string       `json:"status"`
 	Endpoint      string       `json:"endpoint,omitempty"`
 	Message       string       `json:"message,omitempty"`
+
+	// Data holds machine-readable details of the result, such as the
+	// measurements of the probes run in deep mode.
+	Data map[string]interface{} `json:"data,omitempty"`
 }
@@ -0,0 +1,288 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package healthcheck
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"golang.org/x/crypto/acme"
+)
+
+// acmeDirectoryEndpoints are the endpoints every ACME directory must
+// advertise, per RFC 8555 Section 7.1.1.
+var acmeDirectoryEndpoints = []string{"newNonce", "newAccount", "newOrder"}
+
+type ACMEProbe struct {
+	Enabled            bool
+	UnsupportedVersion bool
+
+	Directory  string
+	Identifier string
+
+	ACMEEnabled bool
+	EABRequired bool
+	ACMEConfig  *PathFetch
+
+	DirectoryTook time.Duration
+	DirectoryErr  error
+
+	OrderTook       time.Duration
+	OrderErr        error
+	OrderPermission bool
+	OrderStatus     string
+	ChallengeTypes  []string
+	CleanupErr      error
+}
+
+func NewACMEProbeCheck() Check {
+	return &ACMEProbe{}
+}
+
+func (h *ACMEProbe) Name() string {
+	return "acme_probe"
+}
+
+func (h *ACMEProbe) IsEnabled() bool {
+	return h.Enabled
+}
+
+func (h *ACMEProbe) DefaultConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"directory":  "acme/directory",
+		"identifier": "vault-health-check.example.com",
+	}
+}
+
+func (h *ACMEProbe) LoadConfig(config map[string]interface{}) error {
+	directory, ok := config["directory"].(string)
+	if !ok || !strings.HasSuffix(directory, "acme/directory") {
+		return fmt.Errorf("error parsing %v.directory: expected a path relative to the mount ending in acme/directory, got %v", h.Name(), config["directory"])
+	}
+	h.Directory = strings.Trim(directory, "/")
+
+	identifier, ok := config["identifier"].(string)
+	if !ok || identifier == "" {
+		return fmt.Errorf("error parsing %v.identifier: expected a non-empty string, got %v", h.Name(), config["identifier"])
+	}
+	h.Identifier = identifier
+
+	enabled, err := parseutil.ParseBool(config["enabled"])
+	if err != nil {
+		return fmt.Errorf("error parsing %v.enabled: %w", h.Name(), err)
+	}
+	h.Enabled = enabled
+
+	return nil
+}
+
+func (h *ACMEProbe) FetchResources(e *Executor) error {
+	configRet, err := e.FetchIfNotFetched(logical.ReadOperation, "/{{mount}}/config/acme")
+	if err != nil {
+		return err
+	}
+	h.ACMEConfig = configRet
+
+	if !configRet.IsSecretOK() {
+		if configRet.IsUnsupportedPathError() {
+			h.UnsupportedVersion = true
+			return nil
+		}
+
+		// Without permission to read the configuration, assume ACME is
+		// enabled and let the probes find out.
+		h.ACMEEnabled = true
+	} else {
+		h.ACMEEnabled, _ = configRet.Secret.Data["enabled"].(bool)
+		policy, _ := configRet.Secret.Data["eab_policy"].(string)
+		h.EABRequired = policy != "not-required"
+	}
+
+	if !h.ACMEEnabled {
+		return nil
+	}
+
+	body, took, err := pkiProbe(e, http.MethodGet, "/{{mount}}/"+h.Directory, "", nil)
+	h.DirectoryTook = took
+	if err != nil {
+		h.DirectoryErr = err
+		return nil
+	}
+
+	var directory map[string]interface{}
+	if err := json.Unmarshal(body, &directory); err != nil {
+		h.DirectoryErr = fmt.Errorf("unable to parse directory: %w", err)
+		return nil
+	}
+	for _, endpoint := range acmeDirectoryEndpoints {
+		if url, _ := directory[endpoint].(string); url == "" {
+			h.DirectoryErr = fmt.Errorf("directory lacks the mandatory %v endpoint", endpoint)
+			return nil
+		}
+	}
+
+	start := time.Now()
+	h.OrderErr = h.probeOrder(e)
+	h.OrderTook = time.Since(start)
+
+	return nil
+}
+
+// probeOrder creates an ephemeral account and an order for the test
+// identifier, validates the authorizations of the order and deactivates
+// them, along with the account. The challenges are never fulfilled, so
+// no certificate is issued.
+func (h *ACMEProbe) probeOrder(e *Executor) error {
+	ctx, cancel := context.WithTimeout(context.Background(), e.Client.ClientTimeout())
+	defer cancel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	client := &acme.Client{
+		Key:          key,
+		HTTPClient:   e.Client.CloneConfig().HttpClient,
+		DirectoryURL: pkiProbeURL(e, "/{{mount}}/"+h.Directory),
+		UserAgent:    "vault-pki-health-check",
+	}
+
+	account := &acme.Account{}
+	if h.EABRequired {
+		eab, err := e.Client.Logical().WriteWithContext(ctx, e.templatePath("{{mount}}/acme/eab"), nil)
+		if err != nil {
+			h.OrderPermission = strings.Contains(err.Error(), "permission denied")
+			return fmt.Errorf("unable to create an external account binding: %w", err)
+		}
+		kid, _ := eab.Data["id"].(string)
+		encodedKey, _ := eab.Data["private_key"].(string)
+		macKey, err := base64.RawURLEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return fmt.Errorf("unable to decode the external account binding key: %w", err)
+		}
+		account.ExternalAccountBinding = &acme.ExternalAccountBinding{KID: kid, Key: macKey}
+	}
+
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil {
+		return fmt.Errorf("unable to create an account: %w", err)
+	}
+	defer func() {
+		if err := client.DeactivateReg(ctx); err != nil && h.CleanupErr == nil {
+			h.CleanupErr = fmt.Errorf("unable to deactivate the account: %w", err)
+		}
+	}()
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(h.Identifier))
+	if err != nil {
+		return fmt.Errorf("unable to create an order for %v: %w", h.Identifier, err)
+	}
+	h.OrderStatus = order.Status
+	if order.Status != acme.StatusPending && order.Status != acme.StatusReady {
+		return fmt.Errorf("new order for %v has unexpected status %v", h.Identifier, order.Status)
+	}
+	if len(order.AuthzURLs) == 0 {
+		return fmt.Errorf("new order for %v has no authorizations", h.Identifier)
+	}
+
+	challengeTypes := make(map[string]struct{})
+	for _, authzURL := range order.AuthzURLs {
+		authz, err := client.GetAuthorization(ctx, authzURL)
+		if err != nil {
+			return fmt.Errorf("unable to fetch authorization: %w", err)
+		}
+		for _, challenge := range authz.Challenges {
+			challengeTypes[challenge.Type] = struct{}{}
+		}
+
+		if authz.Status == acme.StatusPending {
+			if err := client.RevokeAuthorization(ctx, authzURL); err != nil && h.CleanupErr == nil {
+				h.CleanupErr = fmt.Errorf("unable to deactivate the authorization: %w", err)
+			}
+		}
+	}
+	for challengeType := range challengeTypes {
+		h.ChallengeTypes = append(h.ChallengeTypes, challengeType)
+	}
+	sort.Strings(h.ChallengeTypes)
+
+	if order.Status == acme.StatusPending && len(h.ChallengeTypes) == 0 {
+		return fmt.Errorf("authorizations of the order for %v offer no challenges", h.Identifier)
+	}
+
+	return nil
+}
+
+func (h *ACMEProbe) Evaluate(e *Executor) (results []*Result, err error) {
+	if h.UnsupportedVersion {
+		ret := Result{
+			Status:   ResultInvalidVersion,
+			Endpoint: "/{{mount}}/config/acme",
+			Message:  "This health check requires Vault 1.14+ but an earlier version of Vault Server was contacted, preventing this health check from running.",
+		}
+		return []*Result{&ret}, nil
+	}
+
+	if !h.ACMEEnabled {
+		ret := Result{
+			Status:   ResultNotApplicable,
+			Endpoint: "/{{mount}}/config/acme",
+			Message:  "ACME is not enabled on this mount.",
+		}
+		return []*Result{&ret}, nil
+	}
+
+	directory := Result{
+		Status:   ResultOK,
+		Endpoint: "/{{mount}}/" + h.Directory,
+		Message:  "ACME directory advertises all mandatory endpoints.",
+		Data: map[string]interface{}{
+			"latency_ms": h.DirectoryTook.Milliseconds(),
+		},
+	}
+	if h.DirectoryErr != nil {
+		directory.Status = ResultCritical
+		directory.Message = fmt.Sprintf("Unable to fetch the ACME directory as an ACME client would: %v. Ensure the mount's cluster path is configured and that the Replay-Nonce, Link and Location response headers are allowed on the mount.", h.DirectoryErr)
+		return []*Result{&directory}, nil
+	}
+	results = append(results, &directory)
+
+	order := Result{
+		Status:   ResultOK,
+		Endpoint: "/{{mount}}/" + strings.TrimSuffix(h.Directory, "directory") + "new-order",
+		Message:  fmt.Sprintf("ACME order for %v was created with %v challenge(s) and cleaned up without issuing a certificate.", h.Identifier, strings.Join(h.ChallengeTypes, ", ")),
+		Data: map[string]interface{}{
+			"identifier":      h.Identifier,
+			"order_status":    h.OrderStatus,
+			"challenge_types": h.ChallengeTypes,
+			"latency_ms":      h.OrderTook.Milliseconds(),
+		},
+	}
+	switch {
+	case h.OrderErr != nil && h.OrderPermission:
+		order.Status = ResultInsufficientPermissions
+		order.Endpoint = "/{{mount}}/acme/eab"
+		order.Message = fmt.Sprintf("The mount requires external account bindings, but this token lacks permission to create one, preventing the ACME order from being probed: %v.", h.OrderErr)
+	case h.OrderErr != nil:
+		order.Status = ResultCritical
+		order.Message = fmt.Sprintf("Unable to complete a dry-run ACME order as an ACME client would: %v. If the identifier isn't allowed by the directory's role, configure acme_probe.identifier.", h.OrderErr)
+	case h.CleanupErr != nil:
+		order.Status = ResultWarning
+		order.Message = fmt.Sprintf("ACME order for %v was created, but cleaning it up failed: %v. The account and authorizations will expire on their own.", h.Identifier, h.CleanupErr)
+	}
+	results = append(results, &order)
+
+	return
+}
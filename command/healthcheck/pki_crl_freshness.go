@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package healthcheck
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+)
+
+// crlProbe is the outcome of fetching a CRL the way a relying party would.
+type crlProbe struct {
+	Endpoint string
+	CRL      *x509.RevocationList
+	Took     time.Duration
+	Err      error
+}
+
+type CRLFreshness struct {
+	Enabled            bool
+	UnsupportedVersion bool
+
+	MaxClockSkew time.Duration
+
+	CRLDisabled   bool
+	DeltasEnabled bool
+
+	IssuerMap map[string]*x509.Certificate
+	CRLs      map[string]*crlProbe
+	DeltaCRLs map[string]*crlProbe
+
+	CRLConfig *PathFetch
+}
+
+func NewCRLFreshnessCheck() Check {
+	return &CRLFreshness{
+		IssuerMap: make(map[string]*x509.Certificate),
+		CRLs:      make(map[string]*crlProbe),
+		DeltaCRLs: make(map[string]*crlProbe),
+	}
+}
+
+func (h *CRLFreshness) Name() string {
+	return "crl_freshness"
+}
+
+func (h *CRLFreshness) IsEnabled() bool {
+	return h.Enabled
+}
+
+func (h *CRLFreshness) DefaultConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"max_clock_skew": "5m",
+	}
+}
+
+func (h *CRLFreshness) LoadConfig(config map[string]interface{}) error {
+	skew, err := parseutil.ParseDurationSecond(config["max_clock_skew"])
+	if err != nil {
+		return fmt.Errorf("error parsing %v.max_clock_skew: %w", h.Name(), err)
+	}
+	h.MaxClockSkew = skew
+
+	enabled, err := parseutil.ParseBool(config["enabled"])
+	if err != nil {
+		return fmt.Errorf("error parsing %v.enabled: %w", h.Name(), err)
+	}
+	h.Enabled = enabled
+
+	return nil
+}
+
+func (h *CRLFreshness) FetchResources(e *Executor) error {
+	configRet, err := e.FetchIfNotFetched(logical.ReadOperation, "/{{mount}}/config/crl")
+	if err != nil {
+		return err
+	}
+	h.CRLConfig = configRet
+	if configRet.IsSecretOK() {
+		if disabled, ok := configRet.Secret.Data["disabled"].(bool); ok {
+			h.CRLDisabled = disabled
+		}
+		if deltas, ok := configRet.Secret.Data["enable_delta"].(bool); ok {
+			h.DeltasEnabled = deltas
+		}
+	}
+
+	exit, _, issuers, err := pkiFetchIssuersList(e, func() {
+		h.UnsupportedVersion = true
+	})
+	if exit || err != nil {
+		return err
+	}
+
+	for _, issuer := range issuers {
+		skip, _, cert, err := pkiFetchIssuer(e, issuer, func() {
+			h.UnsupportedVersion = true
+		})
+		if skip || err != nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		h.IssuerMap[issuer] = cert
+
+		h.CRLs[issuer] = probeCRL(e, "/{{mount}}/issuer/"+issuer+"/crl/der")
+		if h.DeltasEnabled {
+			h.DeltaCRLs[issuer] = probeCRL(e, "/{{mount}}/issuer/"+issuer+"/crl/delta/der")
+		}
+	}
+
+	return nil
+}
+
+func probeCRL(e *Executor, path string) *crlProbe {
+	ret := &crlProbe{Endpoint: path}
+
+	var der []byte
+	der, ret.Took, ret.Err = pkiProbe(e, http.MethodGet, path, "", nil)
+	if ret.Err != nil {
+		return ret
+	}
+
+	ret.CRL, ret.Err = x509.ParseRevocationList(der)
+	return ret
+}
+
+func (h *CRLFreshness) Evaluate(e *Executor) (results []*Result, err error) {
+	if h.UnsupportedVersion {
+		ret := Result{
+			Status:   ResultInvalidVersion,
+			Endpoint: "/{{mount}}/issuers",
+			Message:  "This health check requires Vault 1.11+ but an earlier version of Vault Server was contacted, preventing this health check from running.",
+		}
+		return []*Result{&ret}, nil
+	}
+
+	if h.CRLConfig != nil && h.CRLConfig.IsSecretPermissionsError() {
+		ret := Result{
+			Status:   ResultInsufficientPermissions,
+			Endpoint: "/{{mount}}/config/crl",
+			Message:  "This prevents the health check from seeing if the CRL or delta CRLs are disabled; delta CRLs will not be validated.",
+		}
+
+		if e.Client.Token() == "" {
+			ret.Message = "No token available so unable read authenticated CRL configuration for this mount. " + ret.Message
+		} else {
+			ret.Message = "This token lacks permission to read the CRL configuration for this mount. " + ret.Message
+		}
+
+		results = append(results, &ret)
+	}
+
+	now := time.Now()
+	for issuer, cert := range h.IssuerMap {
+		results = append(results, h.evaluateCRL(h.CRLs[issuer], cert, "CRL", now))
+		if probe, present := h.DeltaCRLs[issuer]; present {
+			results = append(results, h.evaluateCRL(probe, cert, "Delta CRL", now))
+		}
+	}
+
+	return
+}
+
+func (h *CRLFreshness) evaluateCRL(probe *crlProbe, issuer *x509.Certificate, name string, now time.Time) *Result {
+	ret := &Result{
+		Status:   ResultOK,
+		Endpoint: probe.Endpoint,
+		Data: map[string]interface{}{
+			"latency_ms": probe.Took.Milliseconds(),
+		},
+	}
+
+	if probe.Err != nil {
+		ret.Status = ResultCritical
+		ret.Message = fmt.Sprintf("Unable to fetch and parse the %v as an unauthenticated client would: %v.", name, probe.Err)
+		if h.CRLDisabled {
+			ret.Status = ResultInformational
+			ret.Message += " Because the CRL is disabled, this is less of a concern."
+		}
+		return ret
+	}
+
+	crl := probe.CRL
+	ret.Data["this_update"] = crl.ThisUpdate.Format(time.RFC3339)
+	ret.Data["next_update"] = crl.NextUpdate.Format(time.RFC3339)
+	ret.Data["revoked_certificates"] = len(crl.RevokedCertificates)
+	if crl.Number != nil {
+		ret.Data["number"] = crl.Number.String()
+	}
+
+	switch {
+	case crl.CheckSignatureFrom(issuer) != nil:
+		ret.Status = ResultCritical
+		ret.Message = fmt.Sprintf("%v isn't signed by its issuer (%v); relying parties will reject it.", name, issuer.Subject.String())
+	case !crl.NextUpdate.IsZero() && now.After(crl.NextUpdate):
+		ret.Status = ResultCritical
+		ret.Message = fmt.Sprintf("%v is stale: its next update was expected at %v. Relying parties enforcing CRL freshness will reject certificates from this issuer until it is rebuilt.", name, crl.NextUpdate.Format(time.RFC3339))
+	case crl.ThisUpdate.After(now.Add(h.MaxClockSkew)):
+		ret.Status = ResultWarning
+		ret.Message = fmt.Sprintf("%v was issued in the future (%v), more than %v ahead of this client's clock. Check the clocks of the Vault servers.", name, crl.ThisUpdate.Format(time.RFC3339), h.MaxClockSkew)
+	default:
+		ret.Message = fmt.Sprintf("%v is fresh (valid until %v) and correctly signed by its issuer.", name, crl.NextUpdate.Format(time.RFC3339))
+	}
+
+	if ret.Status != ResultOK && h.CRLDisabled {
+		ret.Status = ResultInformational
+		ret.Message += " Because the CRL is disabled, this is less of a concern."
+	}
+
+	return ret
+}
@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package healthcheck
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+)
+
+type IssuerChain struct {
+	Enabled            bool
+	UnsupportedVersion bool
+
+	FetchIssues map[string]*PathFetch
+	IssuerMap   map[string]*x509.Certificate
+	ChainMap    map[string][]*x509.Certificate
+	ChainErrors map[string]error
+}
+
+func NewIssuerChainCheck() Check {
+	return &IssuerChain{
+		FetchIssues: make(map[string]*PathFetch),
+		IssuerMap:   make(map[string]*x509.Certificate),
+		ChainMap:    make(map[string][]*x509.Certificate),
+		ChainErrors: make(map[string]error),
+	}
+}
+
+func (h *IssuerChain) Name() string {
+	return "issuer_chain"
+}
+
+func (h *IssuerChain) IsEnabled() bool {
+	return h.Enabled
+}
+
+func (h *IssuerChain) DefaultConfig() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (h *IssuerChain) LoadConfig(config map[string]interface{}) error {
+	enabled, err := parseutil.ParseBool(config["enabled"])
+	if err != nil {
+		return fmt.Errorf("error parsing %v.enabled: %w", h.Name(), err)
+	}
+	h.Enabled = enabled
+
+	return nil
+}
+
+func (h *IssuerChain) FetchResources(e *Executor) error {
+	exit, _, issuers, err := pkiFetchIssuersList(e, func() {
+		h.UnsupportedVersion = true
+	})
+	if exit || err != nil {
+		return err
+	}
+
+	for _, issuer := range issuers {
+		skip, pathFetch, entry, err := pkiFetchIssuerEntry(e, issuer, func() {
+			h.UnsupportedVersion = true
+		})
+		h.FetchIssues[issuer] = pathFetch
+		if skip || err != nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		h.IssuerMap[issuer] = pathFetch.ParsedCache["certificate"].(*x509.Certificate)
+
+		rawChain, err := StringList(entry["ca_chain"])
+		if err != nil {
+			h.ChainErrors[issuer] = fmt.Errorf("unable to parse ca_chain: %w", err)
+			continue
+		}
+
+		var chain []*x509.Certificate
+		for index, rawCert := range rawChain {
+			cert, err := ParsePEMCert(rawCert)
+			if err != nil {
+				h.ChainErrors[issuer] = fmt.Errorf("unable to parse certificate %v of ca_chain: %w", index, err)
+				break
+			}
+			chain = append(chain, cert)
+		}
+		h.ChainMap[issuer] = chain
+	}
+
+	return nil
+}
+
+func (h *IssuerChain) Evaluate(e *Executor) (results []*Result, err error) {
+	if h.UnsupportedVersion {
+		ret := Result{
+			Status:   ResultInvalidVersion,
+			Endpoint: "/{{mount}}/issuers",
+			Message:  "This health check requires Vault 1.11+ but an earlier version of Vault Server was contacted, preventing this health check from running.",
+		}
+		return []*Result{&ret}, nil
+	}
+
+	for issuer, fetchPath := range h.FetchIssues {
+		if fetchPath != nil && fetchPath.IsSecretPermissionsError() {
+			ret := Result{
+				Status:   ResultInsufficientPermissions,
+				Endpoint: fetchPath.Path,
+				Message:  "Without this information, this health check is unable to function.",
+			}
+
+			if e.Client.Token() == "" {
+				ret.Message = "No token available so unable to read the issuer " + issuer + " for this mount. " + ret.Message
+			} else {
+				ret.Message = "This token lacks permission to read the issuer " + issuer + " for this mount. " + ret.Message
+			}
+
+			results = append(results, &ret)
+		}
+	}
+
+	now := time.Now()
+	for issuer, cert := range h.IssuerMap {
+		ret := Result{
+			Status:   ResultOK,
+			Endpoint: "/{{mount}}/issuer/" + issuer,
+		}
+
+		chain := h.ChainMap[issuer]
+		ret.Data = map[string]interface{}{
+			"chain_length": len(chain),
+		}
+
+		if err := h.ChainErrors[issuer]; err != nil {
+			ret.Status = ResultCritical
+			ret.Message = fmt.Sprintf("Issuer's CA chain is invalid: %v.", err)
+			results = append(results, &ret)
+			continue
+		}
+
+		ret.Status, ret.Message = evaluateIssuerChain(cert, chain, now)
+		results = append(results, &ret)
+	}
+
+	return
+}
+
+// evaluateIssuerChain validates that the chain starts with the issuer, that
+// each certificate is signed by the next and that none has expired.
+func evaluateIssuerChain(cert *x509.Certificate, chain []*x509.Certificate, now time.Time) (ResultStatus, string) {
+	if len(chain) == 0 || !bytes.Equal(chain[0].Raw, cert.Raw) {
+		return ResultCritical, "Issuer's CA chain doesn't start with the issuer's certificate. Clients validating certificates with the chain served by this mount will fail."
+	}
+
+	for index, link := range chain {
+		if now.After(link.NotAfter) {
+			return ResultCritical, fmt.Sprintf("Certificate %v (%v) of the issuer's CA chain expired on %v. Clients validating certificates with this chain will fail; the chain should be updated with a valid certificate.", index, link.Subject.String(), link.NotAfter.Format("2006-01-02"))
+		}
+
+		if index == len(chain)-1 {
+			break
+		}
+
+		parent := chain[index+1]
+		if !bytes.Equal(link.RawIssuer, parent.RawSubject) {
+			return ResultCritical, fmt.Sprintf("Certificate %v (%v) of the issuer's CA chain isn't issued by the next certificate (%v) of the chain. The chain is out of order or contains unrelated certificates.", index, link.Subject.String(), parent.Subject.String())
+		}
+		if err := link.CheckSignatureFrom(parent); err != nil {
+			return ResultCritical, fmt.Sprintf("Certificate %v (%v) of the issuer's CA chain isn't signed by the next certificate (%v) of the chain: %v.", index, link.Subject.String(), parent.Subject.String(), err)
+		}
+	}
+
+	last := chain[len(chain)-1]
+	if !bytes.Equal(last.RawSubject, last.RawIssuer) || last.CheckSignatureFrom(last) != nil {
+		return ResultInformational, fmt.Sprintf("Issuer's CA chain of %v certificate(s) is valid but doesn't end with a root certificate. Clients must already trust the issuer of %v; consider importing the rest of the chain into this mount.", len(chain), last.Subject.String())
+	}
+
+	return ResultOK, fmt.Sprintf("Issuer's CA chain of %v certificate(s) is valid and ends with the root %v.", len(chain), last.Subject.String())
+}
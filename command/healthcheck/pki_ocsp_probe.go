@@ -0,0 +1,310 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package healthcheck
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"golang.org/x/crypto/ocsp"
+)
+
+var ocspStatusNameMap = map[int]string{
+	ocsp.Good:    "good",
+	ocsp.Revoked: "revoked",
+	ocsp.Unknown: "unknown",
+}
+
+// ocspProbe is the outcome of querying the OCSP responder of the mount for a
+// known certificate.
+type ocspProbe struct {
+	Serial  string
+	Cert    *x509.Certificate
+	Revoked bool
+
+	Response *ocsp.Response
+	Took     time.Duration
+	Err      error
+}
+
+type OCSPProbe struct {
+	Enabled            bool
+	UnsupportedVersion bool
+
+	Serial       string
+	CertsToFetch int
+
+	OCSPDisabled bool
+	CRLConfig    *PathFetch
+	LeavesFetch  *PathFetch
+
+	IssuerMap map[string]*x509.Certificate
+	Probes    map[string]*ocspProbe
+}
+
+func NewOCSPProbeCheck() Check {
+	return &OCSPProbe{
+		IssuerMap: make(map[string]*x509.Certificate),
+		Probes:    make(map[string]*ocspProbe),
+	}
+}
+
+func (h *OCSPProbe) Name() string {
+	return "ocsp_probe"
+}
+
+func (h *OCSPProbe) IsEnabled() bool {
+	return h.Enabled
+}
+
+func (h *OCSPProbe) DefaultConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"serial":         "",
+		"certs_to_fetch": 100,
+	}
+}
+
+func (h *OCSPProbe) LoadConfig(config map[string]interface{}) error {
+	serial, ok := config["serial"].(string)
+	if !ok {
+		return fmt.Errorf("error parsing %v.serial: expected a string, got %T", h.Name(), config["serial"])
+	}
+	h.Serial = serial
+
+	count, err := parseutil.SafeParseIntRange(config["certs_to_fetch"], 1, 100000)
+	if err != nil {
+		return fmt.Errorf("error parsing %v.certs_to_fetch: %w", h.Name(), err)
+	}
+	h.CertsToFetch = int(count)
+
+	enabled, err := parseutil.ParseBool(config["enabled"])
+	if err != nil {
+		return fmt.Errorf("error parsing %v.enabled: %w", h.Name(), err)
+	}
+	h.Enabled = enabled
+
+	return nil
+}
+
+func (h *OCSPProbe) FetchResources(e *Executor) error {
+	configRet, err := e.FetchIfNotFetched(logical.ReadOperation, "/{{mount}}/config/crl")
+	if err != nil {
+		return err
+	}
+	h.CRLConfig = configRet
+	if configRet.IsSecretOK() {
+		if disabled, ok := configRet.Secret.Data["ocsp_disable"].(bool); ok && disabled {
+			h.OCSPDisabled = true
+			return nil
+		}
+	}
+
+	exit, _, issuers, err := pkiFetchIssuersList(e, func() {
+		h.UnsupportedVersion = true
+	})
+	if exit || err != nil {
+		return err
+	}
+
+	for _, issuer := range issuers {
+		skip, _, cert, err := pkiFetchIssuer(e, issuer, func() {
+			h.UnsupportedVersion = true
+		})
+		if skip || err != nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		h.IssuerMap[issuer] = cert
+	}
+
+	if h.Serial != "" {
+		return h.probeSerial(e, h.Serial)
+	}
+
+	exit, f, leaves, err := pkiFetchLeavesList(e, func() {
+		h.UnsupportedVersion = true
+	})
+	h.LeavesFetch = f
+	if exit || err != nil {
+		return err
+	}
+
+	// Find a certificate issued by each issuer among the stored ones; roots
+	// are stored too, so self-signed issuers can at least probe themselves.
+	for index, serial := range leaves {
+		if index >= h.CertsToFetch || len(h.Probes) == len(h.IssuerMap) {
+			break
+		}
+
+		if err := h.probeSerial(e, serial); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// probeSerial queries the OCSP responder for the certificate with the serial,
+// if it is issued by one of the issuers of the mount not probed yet.
+func (h *OCSPProbe) probeSerial(e *Executor, serial string) error {
+	skip, leafRet, cert, err := pkiFetchLeaf(e, serial, func() {
+		h.UnsupportedVersion = true
+	})
+	if skip || err != nil {
+		return err
+	}
+
+	for issuer, issuerCert := range h.IssuerMap {
+		if _, probed := h.Probes[issuer]; probed {
+			continue
+		}
+		if !bytes.Equal(cert.RawIssuer, issuerCert.RawSubject) || cert.CheckSignatureFrom(issuerCert) != nil {
+			continue
+		}
+
+		probe := &ocspProbe{
+			Serial: serial,
+			Cert:   cert,
+		}
+		if revocationTime, err := parseutil.ParseInt(leafRet.Secret.Data["revocation_time"]); err == nil && revocationTime > 0 {
+			probe.Revoked = true
+		}
+
+		var req []byte
+		req, probe.Err = ocsp.CreateRequest(cert, issuerCert, nil)
+		if probe.Err == nil {
+			var body []byte
+			body, probe.Took, probe.Err = pkiProbe(e, http.MethodPost, "/{{mount}}/ocsp", "application/ocsp-request", req)
+			if probe.Err == nil {
+				probe.Response, probe.Err = ocsp.ParseResponseForCert(body, cert, issuerCert)
+			}
+		}
+
+		h.Probes[issuer] = probe
+		return nil
+	}
+
+	return nil
+}
+
+func (h *OCSPProbe) Evaluate(e *Executor) (results []*Result, err error) {
+	if h.UnsupportedVersion {
+		ret := Result{
+			Status:   ResultInvalidVersion,
+			Endpoint: "/{{mount}}/issuers",
+			Message:  "This health check requires Vault 1.12+ but an earlier version of Vault Server was contacted, preventing this health check from running.",
+		}
+		return []*Result{&ret}, nil
+	}
+
+	if h.OCSPDisabled {
+		ret := Result{
+			Status:   ResultNotApplicable,
+			Endpoint: "/{{mount}}/config/crl",
+			Message:  "The OCSP responder is disabled on this mount.",
+		}
+		return []*Result{&ret}, nil
+	}
+
+	if h.LeavesFetch != nil && h.LeavesFetch.IsSecretPermissionsError() {
+		ret := Result{
+			Status:   ResultInsufficientPermissions,
+			Endpoint: h.LeavesFetch.Path,
+			Message:  "Without this information, this health check is unable to find certificates to query the OCSP responder for; set ocsp_probe.serial to probe a known certificate instead.",
+		}
+
+		if e.Client.Token() == "" {
+			ret.Message = "No token available so unable to list the certificates of this mount. " + ret.Message
+		} else {
+			ret.Message = "This token lacks permission to list the certificates of this mount. " + ret.Message
+		}
+
+		return []*Result{&ret}, nil
+	}
+
+	now := time.Now()
+	for issuer := range h.IssuerMap {
+		probe, present := h.Probes[issuer]
+		if !present {
+			if h.Serial != "" {
+				continue
+			}
+
+			ret := Result{
+				Status:   ResultInformational,
+				Endpoint: "/{{mount}}/issuer/" + issuer,
+				Message:  fmt.Sprintf("No certificate issued by this issuer was found in the first %v stored certificates, so its OCSP responses weren't validated. Set ocsp_probe.serial to probe a known certificate.", h.CertsToFetch),
+			}
+			results = append(results, &ret)
+			continue
+		}
+
+		results = append(results, evaluateOCSPProbe(probe, now))
+	}
+
+	if h.Serial != "" && len(h.Probes) == 0 {
+		ret := Result{
+			Status:   ResultWarning,
+			Endpoint: "/{{mount}}/cert/" + h.Serial,
+			Message:  "The configured certificate wasn't found or wasn't issued by any issuer of this mount, so the OCSP responder wasn't probed.",
+		}
+		results = append(results, &ret)
+	}
+
+	return
+}
+
+func evaluateOCSPProbe(probe *ocspProbe, now time.Time) *Result {
+	ret := &Result{
+		Status:   ResultOK,
+		Endpoint: "/{{mount}}/ocsp",
+		Data: map[string]interface{}{
+			"serial":     probe.Serial,
+			"latency_ms": probe.Took.Milliseconds(),
+		},
+	}
+
+	if probe.Err != nil {
+		ret.Status = ResultCritical
+		ret.Message = fmt.Sprintf("Unable to query the OCSP responder for certificate %v as a relying party would: %v.", probe.Serial, probe.Err)
+		return ret
+	}
+
+	resp := probe.Response
+	status := ocspStatusNameMap[resp.Status]
+	ret.Data["status"] = status
+	ret.Data["this_update"] = resp.ThisUpdate.Format(time.RFC3339)
+	if !resp.NextUpdate.IsZero() {
+		ret.Data["next_update"] = resp.NextUpdate.Format(time.RFC3339)
+	}
+
+	expected := "good"
+	if probe.Revoked {
+		expected = "revoked"
+	}
+
+	switch {
+	case resp.Status == ocsp.Unknown:
+		ret.Status = ResultWarning
+		ret.Message = fmt.Sprintf("OCSP responder returned an unknown status for certificate %v, which is stored in this mount.", probe.Serial)
+	case status != expected:
+		ret.Status = ResultCritical
+		ret.Message = fmt.Sprintf("OCSP responder returned a %v status for certificate %v, but it is %v in this mount.", status, probe.Serial, expected)
+	case !resp.NextUpdate.IsZero() && now.After(resp.NextUpdate):
+		ret.Status = ResultCritical
+		ret.Message = fmt.Sprintf("OCSP responder returned a stale response for certificate %v: its next update was expected at %v.", probe.Serial, resp.NextUpdate.Format(time.RFC3339))
+	default:
+		ret.Message = fmt.Sprintf("OCSP responder returned a valid, correctly signed %v status for certificate %v.", status, probe.Serial)
+	}
+
+	return ret
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxProbeResponseSize bounds the size of the responses read by the probes.
+const maxProbeResponseSize = 32 << 20
+
+// pkiProbeURL returns the URL of a path of the mount, as accessed by clients
+// other than Vault's: the namespace is part of the path rather than a header
+// and no token is sent.
+func pkiProbeURL(e *Executor, rawPath string) string {
+	path := strings.TrimPrefix(e.templatePath(rawPath), "/")
+	if ns := strings.Trim(e.Client.Namespace(), "/"); ns != "" {
+		path = ns + "/" + path
+	}
+
+	return strings.TrimSuffix(e.Client.Address(), "/") + "/v1/" + path
+}
+
+// pkiProbe issues an unauthenticated request against the mount, returning the
+// raw response body and the time taken by the request. Unlike the results of
+// FetchIfNotFetched, probes are not cached: they actively exercise the mount.
+func pkiProbe(e *Executor, method string, rawPath string, contentType string, body []byte) ([]byte, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.Client.ClientTimeout())
+	defer cancel()
+
+	url := pkiProbeURL(e, rawPath)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	start := time.Now()
+	resp, err := e.Client.CloneConfig().HttpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	contents, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeResponseSize))
+	took := time.Since(start)
+	if err != nil {
+		return nil, took, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return contents, took, fmt.Errorf("unexpected status code %v from %v %v: %v", resp.StatusCode, method, e.templatePath(rawPath), strings.TrimSpace(string(contents)))
+	}
+
+	return contents, took, nil
+}
@@ -205,8 +205,14 @@ func (c *KVGetCommand) Run(args []string) int {
 		}
 	}
 
+	// Records of KV v2 secrets only hold their data, as in the table output.
+	if v2 && secret.WrapInfo == nil && isRecordFormat(Format(c.UI)) {
+		data, _ := secret.Data["data"].(map[string]interface{})
+		return OutputData(c.UI, data)
+	}
+
 	// If we have wrap info print the secret normally.
-	if secret.WrapInfo != nil || c.flagFormat != "table" {
+	if secret.WrapInfo != nil || Format(c.UI) != "table" {
 		return OutputSecret(c.UI, secret)
 	}
 
@@ -156,7 +156,7 @@ func (c *KVMetadataGetCommand) Run(args []string) int {
 	}
 
 	// If we have wrap info print the secret normally.
-	if secret.WrapInfo != nil || c.flagFormat != "table" {
+	if secret.WrapInfo != nil || Format(c.UI) != "table" {
 		return OutputSecret(c.UI, secret)
 	}
 
@@ -25,6 +25,7 @@ type VaultUI struct {
 	cli.Ui
 	format   string
 	detailed bool
+	template string
 }
 
 const (
@@ -32,10 +33,11 @@ const (
 	globalFlagOutputPolicy     = "output-policy"
 	globalFlagFormat           = "format"
 	globalFlagDetailed         = "detailed"
+	globalFlagTemplate         = "template"
 )
 
 var globalFlags = []string{
-	globalFlagOutputCurlString, globalFlagOutputPolicy, globalFlagFormat, globalFlagDetailed,
+	globalFlagOutputCurlString, globalFlagOutputPolicy, globalFlagFormat, globalFlagDetailed, globalFlagTemplate,
 }
 
 // setupEnv parses args and may replace them and sets some env vars to known
@@ -119,6 +121,30 @@ func setupEnv(args []string) (retArgs []string, format string, detailed bool, ou
 	return args, format, detailed, outputCurlString, outputPolicy
 }
 
+// setupTemplate parses the output template from args. A template implies the
+// template output format.
+func setupTemplate(args []string) (template string) {
+	var nextArgTemplate bool
+	for _, arg := range args {
+		if nextArgTemplate {
+			return arg
+		}
+
+		if arg == "--" {
+			break
+		}
+
+		if isGlobalFlagWithValue(arg, globalFlagTemplate) {
+			template = getGlobalFlagValue(arg)
+		}
+		if isGlobalFlag(arg, globalFlagTemplate) {
+			nextArgTemplate = true
+		}
+	}
+
+	return template
+}
+
 func isGlobalFlag(arg string, flag string) bool {
 	return arg == "-"+flag || arg == "--"+flag
 }
@@ -158,6 +184,11 @@ func RunCustom(args []string, runOpts *RunOptions) int {
 	var outputPolicy bool
 	args, format, detailed, outputCurlString, outputPolicy = setupEnv(args)
 
+	template := setupTemplate(args)
+	if template != "" {
+		format = "template"
+	}
+
 	// Don't use color if disabled
 	useColor := true
 	if os.Getenv(EnvVaultCLINoColor) != "" || color.NoColor {
@@ -201,6 +232,7 @@ func RunCustom(args []string, runOpts *RunOptions) int {
 		},
 		format:   format,
 		detailed: detailed,
+		template: template,
 	}
 
 	serverCmdUi := &VaultUI{
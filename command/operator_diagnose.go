@@ -214,6 +214,7 @@ func (c *OperatorDiagnoseCommand) offlineDiagnostics(ctx context.Context) error
 		CredentialBackends:   credentialBackends,
 		LogicalBackends:      logicalBackends,
 		PhysicalBackends:     physicalBackends,
+		GroupResolvers:       groupResolvers,
 		ServiceRegistrations: serviceRegistrations,
 
 		// TODO: other ServerCommand options?
@@ -5,6 +5,7 @@ package command
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -12,6 +13,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
@@ -45,8 +47,14 @@ type OperatorMigrateCommand struct {
 	flagStart        string
 	flagReset        bool
 	flagMaxParallel  int
+	flagCheckpoint   string
+	flagVerify       bool
 	logger           log.Logger
 	ShutdownCh       chan struct{}
+
+	// sourceType and destinationType identify the migration in checkpoints
+	sourceType      string
+	destinationType string
 }
 
 type migratorConfig struct {
@@ -112,6 +120,24 @@ func (c *OperatorMigrateCommand) Flags() *FlagSets {
 			"This can speed up the migration process on slow backends but uses more resources.",
 	})
 
+	f.StringVar(&StringVar{
+		Name:       "checkpoint",
+		Target:     &c.flagCheckpoint,
+		Completion: complete.PredictFiles("*"),
+		Usage: "Path to a file recording the progress of the migration. If the " +
+			"migration is interrupted, running it again with the same checkpoint " +
+			"file resumes it where it stopped. The file is removed once the " +
+			"migration succeeds.",
+	})
+
+	f.BoolVar(&BoolVar{
+		Name:    "verify",
+		Target:  &c.flagVerify,
+		Default: true,
+		Usage: "Verify once the keys are copied that every key of the source " +
+			"exists in the destination with the same value.",
+	})
+
 	f.StringVar(&StringVar{
 		Name:       "log-level",
 		Target:     &c.flagLogLevel,
@@ -201,6 +227,9 @@ func (c *OperatorMigrateCommand) migrate(config *migratorConfig) error {
 		return fmt.Errorf("error mounting 'storage_destination': %w", err)
 	}
 
+	c.sourceType = config.StorageSource.Type
+	c.destinationType = config.StorageDestination.Type
+
 	migrationStatus, err := CheckStorageMigration(from)
 	if err != nil {
 		return fmt.Errorf("error checking migration status: %w", err)
@@ -227,7 +256,22 @@ func (c *OperatorMigrateCommand) migrate(config *migratorConfig) error {
 
 	doneCh := make(chan error)
 	go func() {
-		doneCh <- c.migrateAll(ctx, from, to, c.flagMaxParallel)
+		if err := c.migrateAll(ctx, from, to, c.flagMaxParallel); err != nil {
+			doneCh <- err
+			return
+		}
+		if c.flagVerify {
+			if err := c.verifyAll(ctx, from, to, c.flagMaxParallel); err != nil {
+				doneCh <- err
+				return
+			}
+		}
+		if c.flagCheckpoint != "" {
+			if err := os.Remove(c.flagCheckpoint); err != nil && !os.IsNotExist(err) {
+				c.logger.Warn("failed to remove checkpoint", "path", c.flagCheckpoint, "error", err)
+			}
+		}
+		doneCh <- nil
 	}()
 
 	select {
@@ -242,28 +286,163 @@ func (c *OperatorMigrateCommand) migrate(config *migratorConfig) error {
 	}
 }
 
-// migrateAll copies all keys in lexicographic order.
+// migrateAll copies all keys in lexicographic order. If a checkpoint file is
+// set, the progress is recorded to it and the migration resumes from it.
 func (c *OperatorMigrateCommand) migrateAll(ctx context.Context, from physical.Backend, to physical.Backend, maxParallel int) error {
-	return dfsScan(ctx, from, maxParallel, func(ctx context.Context, path string) error {
-		if path < c.flagStart || path == storageMigrationLock || path == vault.CoreLockPath {
-			return nil
+	start := c.flagStart
+	var resumed *migrationCheckpoint
+	if c.flagCheckpoint != "" {
+		checkpoint, err := readMigrationCheckpoint(c.flagCheckpoint)
+		if err != nil {
+			return err
+		}
+		if checkpoint != nil {
+			if checkpoint.Source != c.sourceType || checkpoint.Destination != c.destinationType {
+				return fmt.Errorf("checkpoint %q is for a migration from %q to %q", c.flagCheckpoint, checkpoint.Source, checkpoint.Destination)
+			}
+			if checkpoint.LastKey > start {
+				start = checkpoint.LastKey
+			}
+			resumed = checkpoint
+			c.logger.Info("resuming migration", "checkpoint", c.flagCheckpoint, "last_key", checkpoint.LastKey, "keys", checkpoint.Keys)
+		}
+	}
+
+	progress := newScanProgress()
+	begin := time.Now()
+
+	reportDone := make(chan struct{})
+	reportStopped := make(chan struct{})
+	go func() {
+		defer close(reportStopped)
+
+		ticker := time.NewTicker(migrationReportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-reportDone:
+				c.checkpointMigration(progress, resumed)
+				return
+			case <-ticker.C:
+				_, keys, bytes := progress.status()
+				elapsed := time.Since(begin)
+				c.logger.Info("migration progress", "keys", keys, "bytes", bytes, "elapsed", elapsed.Round(time.Second),
+					"keys_per_second", fmt.Sprintf("%.1f", float64(keys)/elapsed.Seconds()))
+				c.checkpointMigration(progress, resumed)
+			}
+		}
+	}()
+
+	err := dfsScan(ctx, from, maxParallel, start, progress, func(ctx context.Context, path string) (int, error) {
+		if path < start || path == storageMigrationLock || path == vault.CoreLockPath {
+			return 0, nil
 		}
 
 		entry, err := from.Get(ctx, path)
 		if err != nil {
-			return fmt.Errorf("error reading entry: %w", err)
+			return 0, fmt.Errorf("error reading entry: %w", err)
 		}
 
 		if entry == nil {
-			return nil
+			return 0, nil
 		}
 
 		if err := to.Put(ctx, entry); err != nil {
-			return fmt.Errorf("error writing entry: %w", err)
+			return 0, fmt.Errorf("error writing entry: %w", err)
 		}
 		c.logger.Info("copied key", "path", path)
-		return nil
+		return len(entry.Value), nil
+	})
+
+	close(reportDone)
+	<-reportStopped
+
+	if err != nil {
+		return err
+	}
+
+	_, keys, bytes := progress.status()
+	elapsed := time.Since(begin)
+	c.logger.Info("copied all keys", "keys", keys, "bytes", bytes, "elapsed", elapsed.Round(time.Millisecond),
+		"keys_per_second", fmt.Sprintf("%.1f", float64(keys)/elapsed.Seconds()))
+	return nil
+}
+
+// checkpointMigration records the progress of the migration to the
+// checkpoint file, if one is set. The keys copied by the run that was resumed,
+// if any, are included.
+func (c *OperatorMigrateCommand) checkpointMigration(progress *scanProgress, resumed *migrationCheckpoint) {
+	if c.flagCheckpoint == "" {
+		return
+	}
+
+	lastKey, keys, _ := progress.status()
+	if resumed != nil {
+		if lastKey < resumed.LastKey {
+			return
+		}
+		keys += resumed.Keys
+	}
+	if lastKey == "" {
+		return
+	}
+
+	if err := writeMigrationCheckpoint(c.flagCheckpoint, &migrationCheckpoint{
+		Source:      c.sourceType,
+		Destination: c.destinationType,
+		LastKey:     lastKey,
+		Keys:        keys,
+		UpdatedAt:   time.Now(),
+	}); err != nil {
+		c.logger.Warn("failed to write checkpoint", "path", c.flagCheckpoint, "error", err)
+	}
+}
+
+// verifyAll checks that every key of the source exists in the destination
+// with the same value.
+func (c *OperatorMigrateCommand) verifyAll(ctx context.Context, from physical.Backend, to physical.Backend, maxParallel int) error {
+	c.logger.Info("verifying migrated keys")
+
+	var l sync.Mutex
+	var keys int
+	var mismatched []string
+	err := dfsScan(ctx, from, maxParallel, c.flagStart, nil, func(ctx context.Context, path string) (int, error) {
+		if path < c.flagStart || path == storageMigrationLock || path == vault.CoreLockPath {
+			return 0, nil
+		}
+
+		entry, err := from.Get(ctx, path)
+		if err != nil {
+			return 0, fmt.Errorf("error reading entry: %w", err)
+		}
+		if entry == nil {
+			return 0, nil
+		}
+
+		migrated, err := to.Get(ctx, path)
+		if err != nil {
+			return 0, fmt.Errorf("error reading migrated entry: %w", err)
+		}
+
+		l.Lock()
+		defer l.Unlock()
+		keys++
+		if migrated == nil || sha256.Sum256(entry.Value) != sha256.Sum256(migrated.Value) {
+			mismatched = append(mismatched, path)
+		}
+		return 0, nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if len(mismatched) > 0 {
+		sort.Strings(mismatched)
+		return fmt.Errorf("verification failed: %d of %d keys are missing or different in the destination, including %q", len(mismatched), keys, mismatched[0])
+	}
+
+	c.logger.Info("verified all keys", "keys", keys)
+	return nil
 }
 
 func (c *OperatorMigrateCommand) newBackend(kind string, conf map[string]string) (physical.Backend, error) {
@@ -381,9 +560,11 @@ func parseStorage(result *migratorConfig, list *ast.ObjectList, name string) err
 	return nil
 }
 
-// dfsScan will invoke cb with every key from source.
-// Keys will be traversed in lexicographic, depth-first order.
-func dfsScan(ctx context.Context, source physical.Backend, maxParallel int, cb func(ctx context.Context, path string) error) error {
+// dfsScan will invoke cb with every key from source at or after start.
+// Keys will be traversed in lexicographic, depth-first order. If progress is
+// set, the keys are recorded to it along with the number of bytes returned by
+// cb.
+func dfsScan(ctx context.Context, source physical.Backend, maxParallel int, start string, progress *scanProgress, cb func(ctx context.Context, path string) (int, error)) error {
 	dfs := []string{""}
 
 	eg, ctx := errgroup.WithContext(ctx)
@@ -399,6 +580,12 @@ func dfsScan(ctx context.Context, source physical.Backend, maxParallel int, cb f
 
 		key := dfs[len(dfs)-1]
 		if key == "" || strings.HasSuffix(key, "/") {
+			// Every key under this folder comes before start
+			if key < start && !strings.HasPrefix(start, key) {
+				dfs = dfs[:len(dfs)-1]
+				continue
+			}
+
 			children, err := source.List(ctx, key)
 			if err != nil {
 				return fmt.Errorf("failed to scan for children: %w", err)
@@ -413,9 +600,21 @@ func dfsScan(ctx context.Context, source physical.Backend, maxParallel int, cb f
 				}
 			}
 		} else {
+			var seq uint64
+			if progress != nil {
+				seq = progress.dispatch(key)
+			}
+
 			// Pooling
 			eg.Go(func() error {
-				return cb(ctx, key)
+				n, err := cb(ctx, key)
+				if err != nil {
+					return err
+				}
+				if progress != nil {
+					progress.complete(seq, n)
+				}
+				return nil
 			})
 
 			dfs = dfs[:len(dfs)-1]
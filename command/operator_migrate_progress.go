@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// migrationReportInterval is how often the progress of a migration is logged
+// and checkpointed.
+const migrationReportInterval = 10 * time.Second
+
+// scanProgress tracks the keys of a scan that have been processed. Keys are
+// dispatched in lexicographic order but may complete in any order, so the
+// checkpoint is the greatest key such that it and every key before it were
+// processed.
+type scanProgress struct {
+	l sync.Mutex
+
+	// next is the sequence number of the next dispatched key, and low the
+	// sequence number of the first key that isn't processed yet.
+	next uint64
+	low  uint64
+
+	dispatched map[uint64]string
+	completed  map[uint64]struct{}
+
+	checkpoint string
+	keys       uint64
+	bytes      uint64
+}
+
+func newScanProgress() *scanProgress {
+	return &scanProgress{
+		dispatched: make(map[uint64]string),
+		completed:  make(map[uint64]struct{}),
+	}
+}
+
+// dispatch records that the key is about to be processed, and returns the
+// sequence number to complete it with.
+func (p *scanProgress) dispatch(key string) uint64 {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	seq := p.next
+	p.next++
+	p.dispatched[seq] = key
+	return seq
+}
+
+// complete records that the key with the given sequence number was processed,
+// copying the given number of bytes.
+func (p *scanProgress) complete(seq uint64, bytes int) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	p.keys++
+	p.bytes += uint64(bytes)
+	p.completed[seq] = struct{}{}
+	for {
+		if _, ok := p.completed[p.low]; !ok {
+			break
+		}
+		p.checkpoint = p.dispatched[p.low]
+		delete(p.completed, p.low)
+		delete(p.dispatched, p.low)
+		p.low++
+	}
+}
+
+// status returns the checkpoint, and how many keys and bytes were processed.
+func (p *scanProgress) status() (string, uint64, uint64) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	return p.checkpoint, p.keys, p.bytes
+}
+
+// migrationCheckpoint is persisted while a migration runs so that an
+// interrupted migration can be resumed.
+type migrationCheckpoint struct {
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	LastKey     string    `json:"last_key"`
+	Keys        uint64    `json:"keys"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// readMigrationCheckpoint reads the checkpoint at the given path. A nil
+// checkpoint is returned if there is none.
+func readMigrationCheckpoint(path string) (*migrationCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading checkpoint: %w", err)
+	}
+
+	var checkpoint migrationCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("error parsing checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// writeMigrationCheckpoint atomically replaces the checkpoint at the given
+// path.
+func writeMigrationCheckpoint(path string, checkpoint *migrationCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+	return nil
+}
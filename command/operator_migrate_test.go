@@ -185,6 +185,126 @@ func TestMigration(t *testing.T) {
 		}
 	})
 
+	t.Run("Checkpoint", func(t *testing.T) {
+		data := generateData()
+
+		from, err := physicalBackends["inmem"](map[string]string{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := storeData(from, data); err != nil {
+			t.Fatal(err)
+		}
+
+		to, err := physicalBackends["inmem"](map[string]string{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const start = "m"
+
+		checkpointPath := filepath.Join(t.TempDir(), "checkpoint")
+		if err := writeMigrationCheckpoint(checkpointPath, &migrationCheckpoint{
+			Source:      "inmem",
+			Destination: "inmem",
+			LastKey:     start,
+			Keys:        1,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		cmd := OperatorMigrateCommand{
+			logger:          log.NewNullLogger(),
+			flagCheckpoint:  checkpointPath,
+			sourceType:      "file",
+			destinationType: "inmem",
+		}
+		if err := cmd.migrateAll(context.Background(), from, to, 10); err == nil {
+			t.Fatal("expected an error resuming a migration with a different source")
+		}
+
+		cmd.sourceType = "inmem"
+		if err := cmd.migrateAll(context.Background(), from, to, 10); err != nil {
+			t.Fatal(err)
+		}
+		if err := compareStoredData(to, data, start); err != nil {
+			t.Fatal(err)
+		}
+
+		checkpoint, err := readMigrationCheckpoint(checkpointPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if checkpoint == nil || checkpoint.LastKey < start || checkpoint.Keys <= 1 {
+			t.Fatalf("checkpoint was not updated: %#v", checkpoint)
+		}
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		data := generateData()
+
+		from, err := physicalBackends["inmem"](map[string]string{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := storeData(from, data); err != nil {
+			t.Fatal(err)
+		}
+
+		to, err := physicalBackends["inmem"](map[string]string{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cmd := OperatorMigrateCommand{
+			logger: log.NewNullLogger(),
+		}
+		if err := cmd.migrateAll(context.Background(), from, to, 10); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.verifyAll(context.Background(), from, to, 10); err != nil {
+			t.Fatal(err)
+		}
+
+		keys, err := to.List(context.Background(), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range keys {
+			if strings.HasSuffix(key, "/") {
+				continue
+			}
+			if err := to.Put(context.Background(), &physical.Entry{Key: key, Value: []byte("changed")}); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+		if err := cmd.verifyAll(context.Background(), from, to, 10); err == nil {
+			t.Fatal("expected verification to fail")
+		}
+	})
+
+	t.Run("Scan progress", func(t *testing.T) {
+		p := newScanProgress()
+		a, b, c := p.dispatch("a"), p.dispatch("b"), p.dispatch("c")
+
+		p.complete(b, 2)
+		if checkpoint, _, _ := p.status(); checkpoint != "" {
+			t.Fatalf("unexpected checkpoint %q", checkpoint)
+		}
+
+		p.complete(a, 1)
+		if checkpoint, _, _ := p.status(); checkpoint != "b" {
+			t.Fatalf("expected checkpoint %q, got %q", "b", checkpoint)
+		}
+
+		p.complete(c, 3)
+		checkpoint, keys, n := p.status()
+		if checkpoint != "c" || keys != 3 || n != 6 {
+			t.Fatalf("unexpected status: %q, %d keys, %d bytes", checkpoint, keys, n)
+		}
+	})
+
 	t.Run("Config parsing", func(t *testing.T) {
 		cmd := new(OperatorMigrateCommand)
 		cfgName := filepath.Join(t.TempDir(), "migrator")
@@ -285,12 +405,12 @@ storage_destination "dest_type2" {
 			lock sync.Mutex
 		}
 		outKeys := SafeAppend{}
-		dfsScan(context.Background(), l, 10, func(ctx context.Context, path string) error {
+		dfsScan(context.Background(), l, 10, "", nil, func(ctx context.Context, path string) (int, error) {
 			outKeys.lock.Lock()
 			defer outKeys.lock.Unlock()
 
 			outKeys.out = append(outKeys.out, path)
-			return nil
+			return 0, nil
 		})
 
 		delete(data, trailing_slash_key)
@@ -0,0 +1,429 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/vault/physical/raft"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+var (
+	_ cli.Command             = (*OperatorRaftSnapshotInspectCommand)(nil)
+	_ cli.CommandAutocomplete = (*OperatorRaftSnapshotInspectCommand)(nil)
+)
+
+// These are the storage paths of the core, which are not accessible to the
+// command as they are unexported by the vault package.
+const (
+	snapshotSealConfigPath     = "core/seal-config"
+	snapshotVaultVersionPrefix = "core/versions/"
+	snapshotMountsPrefix       = "logical/"
+	snapshotAuthPrefix         = "auth/"
+)
+
+// snapshotMountTablePaths are the storage paths of the mount tables, the
+// local ones being optional.
+var snapshotMountTablePaths = []string{
+	"core/mounts",
+	"core/local-mounts",
+	"core/auth",
+	"core/local-auth",
+	"core/audit",
+	"core/local-audit",
+}
+
+type OperatorRaftSnapshotInspectCommand struct {
+	*BaseCommand
+
+	flagPrefixDepth   int
+	flagDryRunRestore bool
+}
+
+// SnapshotInspection is the result of the inspection of a snapshot file.
+type SnapshotInspection struct {
+	ID                 string                 `json:"id"`
+	Index              uint64                 `json:"index"`
+	Term               uint64                 `json:"term"`
+	Version            int                    `json:"version"`
+	Size               int64                  `json:"size"`
+	ConfigurationIndex uint64                 `json:"configuration_index"`
+	Servers            []*SnapshotServer      `json:"servers"`
+	TotalKeys          int                    `json:"total_keys"`
+	TotalSize          int64                  `json:"total_size"`
+	StorageVersion     string                 `json:"storage_version"`
+	VaultVersions      []string               `json:"vault_versions"`
+	SealType           string                 `json:"seal_type"`
+	MountTables        map[string]bool        `json:"mount_tables"`
+	Mounts             []*SnapshotMountUsage  `json:"mounts"`
+	Prefixes           []*SnapshotPrefixUsage `json:"prefixes"`
+	DryRunRestore      *SnapshotDryRunRestore `json:"dry_run_restore,omitempty"`
+	Warnings           []string               `json:"warnings,omitempty"`
+}
+
+// SnapshotServer is a server of the raft configuration of a snapshot.
+type SnapshotServer struct {
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	Suffrage string `json:"suffrage"`
+}
+
+// SnapshotMountUsage is the storage used by a mount in a snapshot. Mounts are
+// identified by the UUID of their storage, as their paths are in the mount
+// tables which are encrypted.
+type SnapshotMountUsage struct {
+	Table string `json:"table"`
+	UUID  string `json:"uuid"`
+	Keys  int    `json:"keys"`
+	Size  int64  `json:"size"`
+}
+
+// SnapshotPrefixUsage is the storage used by the keys of a snapshot with a
+// common prefix.
+type SnapshotPrefixUsage struct {
+	Prefix string `json:"prefix"`
+	Keys   int    `json:"keys"`
+	Size   int64  `json:"size"`
+}
+
+// SnapshotDryRunRestore is the result of the restore of a snapshot to an
+// empty node.
+type SnapshotDryRunRestore struct {
+	RestoredKeys int `json:"restored_keys"`
+}
+
+func (c *OperatorRaftSnapshotInspectCommand) Synopsis() string {
+	return "Inspects and validates a snapshot file"
+}
+
+func (c *OperatorRaftSnapshotInspectCommand) Help() string {
+	helpText := `
+Usage: vault operator raft snapshot inspect [options] <snapshot_file>
+
+  Inspects the provided snapshot file, without contacting Vault. The integrity
+  of the snapshot is verified, and its raft metadata, storage version, mount
+  tables and the number of keys per storage prefix are reported. The mount
+  tables are encrypted, so mounts are reported by the UUID of their storage.
+
+  Inspect a snapshot:
+
+      $ vault operator raft snapshot inspect raft.snap
+
+  Inspect a snapshot and verify it can be restored to an empty node:
+
+      $ vault operator raft snapshot inspect -dry-run-restore raft.snap
+
+` + c.Flags().Help()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRaftSnapshotInspectCommand) Flags() *FlagSets {
+	set := c.flagSet(FlagSetOutputFormat)
+
+	f := set.NewFlagSet("Command Options")
+
+	f.IntVar(&IntVar{
+		Name:    "prefix-depth",
+		Target:  &c.flagPrefixDepth,
+		Default: 1,
+		Usage:   "Number of path segments of the storage prefixes the keys are counted by.",
+	})
+
+	f.BoolVar(&BoolVar{
+		Name:    "dry-run-restore",
+		Target:  &c.flagDryRunRestore,
+		Default: false,
+		Usage: "Restore the snapshot to an empty node in a temporary directory, " +
+			"the same way it is installed by the restore command, and verify " +
+			"the restored data matches the snapshot.",
+	})
+
+	return set
+}
+
+func (c *OperatorRaftSnapshotInspectCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorRaftSnapshotInspectCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OperatorRaftSnapshotInspectCommand) Run(args []string) int {
+	f := c.Flags()
+
+	if err := f.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	snapFile := ""
+
+	args = f.Args()
+	switch len(args) {
+	case 1:
+		snapFile = strings.TrimSpace(args[0])
+	default:
+		c.UI.Error(fmt.Sprintf("Incorrect arguments (expected 1, got %d)", len(args)))
+		return 1
+	}
+
+	if len(snapFile) == 0 {
+		c.UI.Error("Snapshot file name is required")
+		return 1
+	}
+
+	if c.flagPrefixDepth < 1 {
+		c.UI.Error("Prefix depth must be at least 1")
+		return 1
+	}
+
+	snapReader, err := os.Open(snapFile)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 2
+	}
+	defer snapReader.Close()
+
+	snap, err := raft.OpenSnapshotFile(log.NewNullLogger(), snapReader)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error verifying the snapshot: %s", err))
+		return 2
+	}
+	defer snap.Close()
+
+	inspection, err := inspectSnapshot(snap, c.flagPrefixDepth)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error inspecting the snapshot: %s", err))
+		return 2
+	}
+
+	if c.flagDryRunRestore {
+		restored, err := snap.DryRunRestore()
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error restoring the snapshot: %s", err))
+			return 2
+		}
+		inspection.DryRunRestore = &SnapshotDryRunRestore{
+			RestoredKeys: restored,
+		}
+	}
+
+	if Format(c.UI) != "table" {
+		return OutputData(c.UI, inspection)
+	}
+
+	c.outputInspection(inspection)
+	return 0
+}
+
+// inspectSnapshot reports the content of the snapshot, counting its keys by
+// prefix of the given depth.
+func inspectSnapshot(snap *raft.SnapshotFile, prefixDepth int) (*SnapshotInspection, error) {
+	inspection := &SnapshotInspection{
+		ID:                 snap.Meta.ID,
+		Index:              snap.Meta.Index,
+		Term:               snap.Meta.Term,
+		Version:            int(snap.Meta.Version),
+		Size:               snap.Meta.Size,
+		ConfigurationIndex: snap.Meta.ConfigurationIndex,
+		MountTables:        make(map[string]bool, len(snapshotMountTablePaths)),
+	}
+
+	prefixes := make(map[string]*SnapshotPrefixUsage)
+	mounts := make(map[string]*SnapshotMountUsage)
+
+	// addMountUsage counts a key of the mount whose UUID is the first
+	// segment of the given path
+	addMountUsage := func(table, path string, size int64) {
+		uuid, _, ok := strings.Cut(path, "/")
+		if !ok {
+			return
+		}
+
+		usage, ok := mounts[table+"/"+uuid]
+		if !ok {
+			usage = &SnapshotMountUsage{Table: table, UUID: uuid}
+			mounts[table+"/"+uuid] = usage
+		}
+		usage.Keys++
+		usage.Size += size
+	}
+
+	for _, server := range snap.Meta.Configuration.Servers {
+		inspection.Servers = append(inspection.Servers, &SnapshotServer{
+			ID:       string(server.ID),
+			Address:  string(server.Address),
+			Suffrage: strings.ToLower(server.Suffrage.String()),
+		})
+	}
+
+	for _, path := range snapshotMountTablePaths {
+		inspection.MountTables[path] = false
+	}
+
+	var versions []*version.Version
+	err := snap.Range(func(key string, value []byte) error {
+		size := int64(len(value))
+		inspection.TotalKeys++
+		inspection.TotalSize += size
+
+		prefix := snapshotKeyPrefix(key, prefixDepth)
+		usage, ok := prefixes[prefix]
+		if !ok {
+			usage = &SnapshotPrefixUsage{Prefix: prefix}
+			prefixes[prefix] = usage
+		}
+		usage.Keys++
+		usage.Size += size
+
+		switch {
+		case key == snapshotSealConfigPath:
+			var sealConfig struct {
+				Type string `json:"type"`
+			}
+			if err := json.Unmarshal(value, &sealConfig); err != nil {
+				inspection.Warnings = append(inspection.Warnings, fmt.Sprintf("failed to decode the seal configuration: %s", err))
+				break
+			}
+			inspection.SealType = sealConfig.Type
+
+		case strings.HasPrefix(key, snapshotVaultVersionPrefix):
+			v, err := version.NewVersion(strings.TrimPrefix(key, snapshotVaultVersionPrefix))
+			if err != nil {
+				inspection.Warnings = append(inspection.Warnings, fmt.Sprintf("invalid Vault version key %q", key))
+				break
+			}
+			versions = append(versions, v)
+
+		case strings.HasPrefix(key, snapshotMountsPrefix):
+			addMountUsage("secrets", strings.TrimPrefix(key, snapshotMountsPrefix), size)
+
+		case strings.HasPrefix(key, snapshotAuthPrefix):
+			addMountUsage("auth", strings.TrimPrefix(key, snapshotAuthPrefix), size)
+		}
+
+		if _, ok := inspection.MountTables[key]; ok {
+			inspection.MountTables[key] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(version.Collection(versions))
+	for _, v := range versions {
+		inspection.VaultVersions = append(inspection.VaultVersions, v.Original())
+	}
+	if len(versions) > 0 {
+		inspection.StorageVersion = versions[len(versions)-1].Original()
+	}
+
+	for _, usage := range prefixes {
+		inspection.Prefixes = append(inspection.Prefixes, usage)
+	}
+	sort.Slice(inspection.Prefixes, func(i, j int) bool {
+		return inspection.Prefixes[i].Prefix < inspection.Prefixes[j].Prefix
+	})
+
+	for _, usage := range mounts {
+		inspection.Mounts = append(inspection.Mounts, usage)
+	}
+	sort.Slice(inspection.Mounts, func(i, j int) bool {
+		if inspection.Mounts[i].Table != inspection.Mounts[j].Table {
+			return inspection.Mounts[i].Table > inspection.Mounts[j].Table
+		}
+		return inspection.Mounts[i].UUID < inspection.Mounts[j].UUID
+	})
+
+	if !inspection.MountTables["core/mounts"] || !inspection.MountTables["core/auth"] {
+		inspection.Warnings = append(inspection.Warnings, "the snapshot has no mount tables, it may not be a snapshot of an initialized Vault cluster")
+	}
+	if inspection.SealType == "" {
+		inspection.Warnings = append(inspection.Warnings, "the snapshot has no seal configuration")
+	}
+
+	return inspection, nil
+}
+
+// snapshotKeyPrefix returns the prefix of the key made of up to the given
+// number of path segments. Keys with fewer segments are their own prefix.
+func snapshotKeyPrefix(key string, depth int) string {
+	var end int
+	for n := 0; n < depth; n++ {
+		i := strings.Index(key[end:], "/")
+		if i < 0 {
+			return key
+		}
+		end += i + 1
+	}
+
+	return key[:end]
+}
+
+func (c *OperatorRaftSnapshotInspectCommand) outputInspection(inspection *SnapshotInspection) {
+	out := []string{
+		fmt.Sprintf("ID | %s", inspection.ID),
+		fmt.Sprintf("Index | %d", inspection.Index),
+		fmt.Sprintf("Term | %d", inspection.Term),
+		fmt.Sprintf("Snapshot Version | %d", inspection.Version),
+		fmt.Sprintf("Size | %d", inspection.Size),
+		fmt.Sprintf("Total Keys | %d", inspection.TotalKeys),
+		fmt.Sprintf("Total Size | %d", inspection.TotalSize),
+		fmt.Sprintf("Storage Version | %s", inspection.StorageVersion),
+		fmt.Sprintf("Seal Type | %s", inspection.SealType),
+	}
+	if inspection.DryRunRestore != nil {
+		out = append(out, fmt.Sprintf("Dry-Run Restored Keys | %d", inspection.DryRunRestore.RestoredKeys))
+	}
+	c.UI.Output(tableOutput(append([]string{"Key | Value"}, out...), nil))
+
+	if len(inspection.Servers) > 0 {
+		out = []string{"Node | Address | Suffrage"}
+		for _, server := range inspection.Servers {
+			out = append(out, fmt.Sprintf("%s | %s | %s", server.ID, server.Address, server.Suffrage))
+		}
+		c.UI.Output("")
+		c.UI.Output(tableOutput(out, nil))
+	}
+
+	out = []string{"Mount Table | Present"}
+	for _, path := range snapshotMountTablePaths {
+		out = append(out, fmt.Sprintf("%s | %t", path, inspection.MountTables[path]))
+	}
+	c.UI.Output("")
+	c.UI.Output(tableOutput(out, nil))
+
+	if len(inspection.Mounts) > 0 {
+		out = []string{"Table | Storage UUID | Keys | Size"}
+		for _, mount := range inspection.Mounts {
+			out = append(out, fmt.Sprintf("%s | %s | %d | %d", mount.Table, mount.UUID, mount.Keys, mount.Size))
+		}
+		c.UI.Output("")
+		c.UI.Output(tableOutput(out, nil))
+	}
+
+	out = []string{"Prefix | Keys | Size"}
+	for _, prefix := range inspection.Prefixes {
+		out = append(out, fmt.Sprintf("%s | %d | %d", prefix.Prefix, prefix.Keys, prefix.Size))
+	}
+	c.UI.Output("")
+	c.UI.Output(tableOutput(out, nil))
+
+	for _, warning := range inspection.Warnings {
+		c.UI.Warn("")
+		c.UI.Warn(fmt.Sprintf("WARNING: %s", warning))
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/physical/raft"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/physical"
+	"github.com/mitchellh/cli"
+)
+
+func testOperatorRaftSnapshotInspectCommand(tb testing.TB) (*cli.MockUi, *OperatorRaftSnapshotInspectCommand) {
+	tb.Helper()
+
+	ui := cli.NewMockUi()
+	return ui, &OperatorRaftSnapshotInspectCommand{
+		BaseCommand: &BaseCommand{
+			UI: ui,
+		},
+	}
+}
+
+// testRaftSnapshotFile writes a snapshot of a raft backend with the given
+// entries to a file, and returns its path.
+func testRaftSnapshotFile(t *testing.T, entries map[string]string) string {
+	t.Helper()
+
+	backend, dir := raft.GetRaft(t, true, false)
+	defer os.RemoveAll(dir)
+
+	for key, value := range entries {
+		err := backend.Put(context.Background(), &physical.Entry{
+			Key:   key,
+			Value: []byte(value),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	if err := backend.Snapshot(logical.NewHTTPResponseWriter(recorder), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "raft.snap")
+	if err := os.WriteFile(path, recorder.Body.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestOperatorRaftSnapshotInspectCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	snapPath := testRaftSnapshotFile(t, map[string]string{
+		"core/seal-config":            `{"type":"shamir","secret_shares":1,"secret_threshold":1}`,
+		"core/versions/1.13.2":        "encrypted",
+		"core/versions/1.14.0":        "encrypted",
+		"core/mounts":                 "encrypted",
+		"core/auth":                   "encrypted",
+		"core/audit":                  "encrypted",
+		"logical/2d2e6a2c-1f2b/foo":   "encrypted",
+		"logical/2d2e6a2c-1f2b/bar":   "encrypted",
+		"auth/9a8b7c6d-5e4f/role/baz": "encrypted",
+		"sys/policy/default":          "encrypted",
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		ui, cmd := testOperatorRaftSnapshotInspectCommand(t)
+		cmd.UI = &VaultUI{Ui: ui, format: "json"}
+		code := cmd.Run([]string{
+			"-dry-run-restore",
+			snapPath,
+		})
+		if exp := 0; code != exp {
+			t.Fatalf("expected %d to be %d: %s", code, exp, ui.ErrorWriter.String())
+		}
+
+		var inspection SnapshotInspection
+		if err := json.Unmarshal(ui.OutputWriter.Bytes(), &inspection); err != nil {
+			t.Fatal(err)
+		}
+
+		if inspection.TotalKeys != 10 {
+			t.Errorf("expected 10 keys, got %d", inspection.TotalKeys)
+		}
+		if inspection.StorageVersion != "1.14.0" {
+			t.Errorf("expected storage version 1.14.0, got %q", inspection.StorageVersion)
+		}
+		if inspection.SealType != "shamir" {
+			t.Errorf("expected seal type shamir, got %q", inspection.SealType)
+		}
+		if !inspection.MountTables["core/mounts"] || !inspection.MountTables["core/auth"] || inspection.MountTables["core/local-mounts"] {
+			t.Errorf("bad mount tables: %v", inspection.MountTables)
+		}
+		if len(inspection.Warnings) != 0 {
+			t.Errorf("expected no warnings, got %v", inspection.Warnings)
+		}
+		if inspection.DryRunRestore == nil || inspection.DryRunRestore.RestoredKeys != 10 {
+			t.Errorf("bad dry-run restore: %#v", inspection.DryRunRestore)
+		}
+
+		expectedMounts := []*SnapshotMountUsage{
+			{Table: "secrets", UUID: "2d2e6a2c-1f2b", Keys: 2, Size: 18},
+			{Table: "auth", UUID: "9a8b7c6d-5e4f", Keys: 1, Size: 9},
+		}
+		if !reflect.DeepEqual(inspection.Mounts, expectedMounts) {
+			t.Errorf("bad mounts: %#v", inspection.Mounts)
+		}
+
+		prefixes := make(map[string]int)
+		for _, prefix := range inspection.Prefixes {
+			prefixes[prefix.Prefix] = prefix.Keys
+		}
+		expectedPrefixes := map[string]int{"core/": 6, "logical/": 2, "auth/": 1, "sys/": 1}
+		if !reflect.DeepEqual(prefixes, expectedPrefixes) {
+			t.Errorf("bad prefixes: %v", prefixes)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+
+		ui, cmd := testOperatorRaftSnapshotInspectCommand(t)
+		code := cmd.Run([]string{
+			"-prefix-depth", "2",
+			snapPath,
+		})
+		if exp := 0; code != exp {
+			t.Fatalf("expected %d to be %d: %s", code, exp, ui.ErrorWriter.String())
+		}
+
+		output := ui.OutputWriter.String()
+		for _, expected := range []string{"Storage Version", "1.14.0", "logical/2d2e6a2c-1f2b/", "core/versions/"} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("expected %q to contain %q", output, expected)
+			}
+		}
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := os.ReadFile(snapPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data[len(data)/2] ^= 0xff
+
+		corruptedPath := filepath.Join(t.TempDir(), "corrupted.snap")
+		if err := os.WriteFile(corruptedPath, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		ui, cmd := testOperatorRaftSnapshotInspectCommand(t)
+		code := cmd.Run([]string{corruptedPath})
+		if exp := 2; code != exp {
+			t.Errorf("expected %d to be %d", code, exp)
+		}
+
+		expected := "Error verifying the snapshot"
+		if !strings.Contains(ui.ErrorWriter.String(), expected) {
+			t.Errorf("expected %q to contain %q", ui.ErrorWriter.String(), expected)
+		}
+	})
+
+	t.Run("no_args", func(t *testing.T) {
+		t.Parallel()
+
+		_, cmd := testOperatorRaftSnapshotInspectCommand(t)
+		if code := cmd.Run(nil); code != 1 {
+			t.Errorf("expected %d to be %d", code, 1)
+		}
+	})
+}
@@ -47,6 +47,7 @@ type PKIHealthCheckCommand struct {
 	flagReturnIndicator string
 	flagDefaultDisabled bool
 	flagList            bool
+	flagDeep            bool
 }
 
 func (c *PKIHealthCheckCommand) Synopsis() string {
@@ -70,6 +71,11 @@ Usage: vault pki health-check [options] MOUNT
 
       $ vault pki health-check -health-config=mycorp-root.json /pki-root
 
+  To also actively exercise the mount's ACME, OCSP and CRL endpoints as
+  clients would, and validate the issuers' chains:
+
+      $ vault pki health-check -deep -format=json pki-root
+
   Return codes indicate failure type:
 
       0 - Everything is good.
@@ -141,6 +147,19 @@ default unless enabled by the configuration file explicitly.`,
 checks are printed.`,
 	})
 
+	f.BoolVar(&BoolVar{
+		Name:    "deep",
+		Target:  &c.flagDeep,
+		Default: false,
+		EnvVar:  "",
+		Usage: `When specified, also runs the deep health checks, which actively
+exercise the mount as its clients would: fetching the ACME directory and
+creating (then deactivating) a dry-run order against a test identifier,
+querying the OCSP responder for a known serial, and validating the
+freshness of the CRLs and the correctness of the issuers' chains. The
+dry-run ACME order may create an external account binding.`,
+	})
+
 	return set
 }
 
@@ -220,6 +239,12 @@ func (c *PKIHealthCheckCommand) Run(args []string) int {
 	executor.AddCheck(healthcheck.NewEnableAutoTidyCheck())
 	executor.AddCheck(healthcheck.NewTidyLastRunCheck())
 	executor.AddCheck(healthcheck.NewTooManyCertsCheck())
+	if c.flagDeep {
+		executor.AddCheck(healthcheck.NewIssuerChainCheck())
+		executor.AddCheck(healthcheck.NewCRLFreshnessCheck())
+		executor.AddCheck(healthcheck.NewOCSPProbeCheck())
+		executor.AddCheck(healthcheck.NewACMEProbeCheck())
+	}
 	if c.flagDefaultDisabled {
 		executor.DefaultEnabled = false
 	}
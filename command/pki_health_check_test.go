@@ -7,6 +7,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -262,6 +264,114 @@ func TestPKIHC_NoPerm(t *testing.T) {
 	validateExpectedPKIHC(t, expectedNoPerm, results)
 }
 
+func TestPKIHC_Deep(t *testing.T) {
+	t.Parallel()
+
+	client, closer := testVaultServer(t)
+	defer closer()
+
+	if err := client.Sys().Mount("pki", &api.MountInput{
+		Type: "pki",
+		Config: api.MountConfigInput{
+			AuditNonHMACRequestKeys:   healthcheck.VisibleReqParams,
+			AuditNonHMACResponseKeys:  healthcheck.VisibleRespParams,
+			PassthroughRequestHeaders: []string{"If-Modified-Since"},
+			AllowedResponseHeaders:    []string{"Last-Modified", "Replay-Nonce", "Link", "Location"},
+			MaxLeaseTTL:               "36500d",
+		},
+	}); err != nil {
+		t.Fatalf("pki mount error: %#v", err)
+	}
+
+	if resp, err := client.Logical().Write("pki/root/generate/internal", map[string]interface{}{
+		"key_type":    "ec",
+		"common_name": "Root X1",
+		"ttl":         "3650d",
+	}); err != nil || resp == nil {
+		t.Fatalf("failed to prime CA: %v", err)
+	}
+
+	if _, err := client.Logical().Write("pki/roles/testing", map[string]interface{}{
+		"allow_any_name": true,
+		"key_type":       "ec",
+	}); err != nil {
+		t.Fatalf("failed to write role: %v", err)
+	}
+
+	resp, err := client.Logical().Write("pki/issue/testing", map[string]interface{}{
+		"common_name": "revoked.example.com",
+	})
+	if err != nil {
+		t.Fatalf("failed to issue leaf cert: %v", err)
+	}
+
+	if _, err := client.Logical().Write("pki/revoke", map[string]interface{}{
+		"serial_number": resp.Data["serial_number"],
+	}); err != nil {
+		t.Fatalf("failed to revoke leaf cert: %v", err)
+	}
+
+	config := writePKIHCConfig(t, map[string]interface{}{
+		"issuer_chain":  map[string]interface{}{"enabled": true},
+		"crl_freshness": map[string]interface{}{"enabled": true},
+		"ocsp_probe":    map[string]interface{}{"enabled": true, "serial": resp.Data["serial_number"]},
+		"acme_probe":    map[string]interface{}{"enabled": true},
+	})
+
+	// Without ACME, the ACME probe doesn't apply; OCSP must report the
+	// configured serial as revoked.
+	_, _, results := execPKIHC(t, client, true, "-deep", "-default-disabled", "-health-config="+config)
+	validateExpectedPKIHC(t, map[string][]map[string]interface{}{
+		"issuer_chain":  {{"status": "ok"}},
+		"crl_freshness": {{"status": "ok"}},
+		"ocsp_probe":    {{"status": "ok"}},
+		"acme_probe":    {{"status": "not_applicable"}},
+	}, results)
+	require.Equal(t, "revoked", results["ocsp_probe"][0]["data"].(map[string]interface{})["status"])
+
+	if _, err := client.Logical().Write("pki/config/cluster", map[string]interface{}{
+		"path": client.Address() + "/v1/pki",
+	}); err != nil {
+		t.Fatalf("failed to write cluster config: %v", err)
+	}
+
+	if _, err := client.Logical().Write("pki/config/acme", map[string]interface{}{
+		"enabled": true,
+	}); err != nil {
+		t.Fatalf("failed to write ACME config: %v", err)
+	}
+
+	// The default EAB policy requires an external account binding, which
+	// the probe creates with the token.
+	_, _, results = execPKIHC(t, client, true, "-deep", "-default-disabled", "-health-config="+config)
+	validateExpectedPKIHC(t, map[string][]map[string]interface{}{
+		"issuer_chain":  {{"status": "ok"}},
+		"crl_freshness": {{"status": "ok"}},
+		"ocsp_probe":    {{"status": "ok"}},
+		"acme_probe":    {{"status": "ok"}, {"status": "ok"}},
+	}, results)
+
+	// The account and its authorizations were deactivated and the external
+	// account binding was consumed.
+	eabs, err := client.Logical().List("pki/acme/eab")
+	require.NoError(t, err)
+	if eabs != nil {
+		require.Empty(t, eabs.Data["keys"])
+	}
+}
+
+func writePKIHCConfig(t *testing.T, config map[string]interface{}) string {
+	t.Helper()
+
+	contents, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "health-check.json")
+	require.NoError(t, os.WriteFile(path, contents, 0o600))
+
+	return path
+}
+
 func testPKIHealthCheckCommand(tb testing.TB) (*cli.MockUi, *PKIHealthCheckCommand) {
 	tb.Helper()
 
@@ -273,7 +383,7 @@ func testPKIHealthCheckCommand(tb testing.TB) (*cli.MockUi, *PKIHealthCheckComma
 	}
 }
 
-func execPKIHC(t *testing.T, client *api.Client, ok bool) (int, string, map[string][]map[string]interface{}) {
+func execPKIHC(t *testing.T, client *api.Client, ok bool, flags ...string) (int, string, map[string][]map[string]interface{}) {
 	t.Helper()
 
 	stdout := bytes.NewBuffer(nil)
@@ -284,7 +394,8 @@ func execPKIHC(t *testing.T, client *api.Client, ok bool) (int, string, map[stri
 		Client: client,
 	}
 
-	code := RunCustom([]string{"pki", "health-check", "-format=json", "pki"}, runOpts)
+	args := append([]string{"pki", "health-check", "-format=json"}, flags...)
+	code := RunCustom(append(args, "pki"), runOpts)
 	combined := stdout.String() + stderr.String()
 
 	var results map[string][]map[string]interface{}
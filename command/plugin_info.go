@@ -131,6 +131,15 @@ func (c *PluginInfoCommand) Run(args []string) int {
 		"deprecation_status": resp.DeprecationStatus,
 		"version":            resp.Version,
 	}
+	if resp.Runtime != "" {
+		data["runtime"] = resp.Runtime
+	}
+	if resp.MemoryLimit != 0 {
+		data["memory_limit"] = resp.MemoryLimit
+	}
+	if resp.CPULimit != 0 {
+		data["cpu_limit"] = resp.CPULimit
+	}
 
 	if c.flagField != "" {
 		return PrintRawField(c.UI, data, c.flagField)
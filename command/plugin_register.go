@@ -20,10 +20,13 @@ var (
 type PluginRegisterCommand struct {
 	*BaseCommand
 
-	flagArgs    []string
-	flagCommand string
-	flagSHA256  string
-	flagVersion string
+	flagArgs        []string
+	flagCommand     string
+	flagSHA256      string
+	flagVersion     string
+	flagRuntime     string
+	flagMemoryLimit string
+	flagCPULimit    float64
 }
 
 func (c *PluginRegisterCommand) Synopsis() string {
@@ -50,6 +53,23 @@ Usage: vault plugin register [options] TYPE NAME
           -args=--with-glibc,--with-cgo \
           auth my-custom-plugin
 
+  Register a plugin compiled to a WebAssembly module:
+
+      $ vault plugin register \
+          -sha256=d3f0a8b... \
+          -runtime=wasm \
+          -command=my-custom-plugin.wasm \
+          secret my-custom-plugin
+
+  Register a plugin whose processes may use up to 512MiB of memory and half
+  a CPU:
+
+      $ vault plugin register \
+          -sha256=d3f0a8b... \
+          -memory-limit=512MiB \
+          -cpu-limit=0.5 \
+          secret my-custom-plugin
+
 ` + c.Flags().Help()
 
 	return strings.TrimSpace(helpText)
@@ -90,6 +110,32 @@ func (c *PluginRegisterCommand) Flags() *FlagSets {
 		Usage:      "Semantic version of the plugin. Optional.",
 	})
 
+	f.StringVar(&StringVar{
+		Name:       "runtime",
+		Target:     &c.flagRuntime,
+		Completion: complete.PredictSet("wasm"),
+		Usage: "Runtime of the plugin. Set to \"wasm\" for plugins compiled to " +
+			"WebAssembly modules, which are run in-process. Optional.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:       "memory-limit",
+		Target:     &c.flagMemoryLimit,
+		Completion: complete.PredictAnything,
+		Usage: "Maximum memory the plugin processes may use, in bytes or with " +
+			"a unit suffix such as \"512MiB\". Requires plugin_cgroup_parent " +
+			"to be configured on the server. Optional.",
+	})
+
+	f.Float64Var(&Float64Var{
+		Name:       "cpu-limit",
+		Target:     &c.flagCPULimit,
+		Completion: complete.PredictAnything,
+		Usage: "Maximum number of CPUs the plugin processes may use, which may " +
+			"be fractional. Requires plugin_cgroup_parent to be configured on " +
+			"the server. Optional.",
+	})
+
 	return set
 }
 
@@ -150,12 +196,15 @@ func (c *PluginRegisterCommand) Run(args []string) int {
 	}
 
 	if err := client.Sys().RegisterPlugin(&api.RegisterPluginInput{
-		Name:    pluginName,
-		Type:    pluginType,
-		Args:    c.flagArgs,
-		Command: command,
-		SHA256:  c.flagSHA256,
-		Version: c.flagVersion,
+		Name:        pluginName,
+		Type:        pluginType,
+		Args:        c.flagArgs,
+		Command:     command,
+		SHA256:      c.flagSHA256,
+		Version:     c.flagVersion,
+		Runtime:     c.flagRuntime,
+		MemoryLimit: c.flagMemoryLimit,
+		CPULimit:    c.flagCPULimit,
 	}); err != nil {
 		c.UI.Error(fmt.Sprintf("Error registering plugin %s: %s", pluginName, err))
 		return 2
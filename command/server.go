@@ -40,6 +40,7 @@ import (
 	"github.com/hashicorp/vault/helper/builtinplugins"
 	"github.com/hashicorp/vault/helper/constants"
 	"github.com/hashicorp/vault/helper/experiments"
+	"github.com/hashicorp/vault/helper/groupresolver"
 	loghelper "github.com/hashicorp/vault/helper/logging"
 	"github.com/hashicorp/vault/helper/metricsutil"
 	"github.com/hashicorp/vault/helper/namespace"
@@ -97,6 +98,7 @@ type ServerCommand struct {
 	CredentialBackends map[string]logical.Factory
 	LogicalBackends    map[string]logical.Factory
 	PhysicalBackends   map[string]physical.Factory
+	GroupResolvers     map[string]groupresolver.Factory
 
 	ServiceRegistrations map[string]sr.Factory
 
@@ -114,10 +116,14 @@ type ServerCommand struct {
 
 	reloadFuncsLock   *sync.RWMutex
 	reloadFuncs       *map[string][]reloadutil.ReloadFunc
+	configReloadCh    chan chan configReloadReply
 	startedCh         chan (struct{}) // for tests
 	reloadedCh        chan (struct{}) // for tests
 	licenseReloadedCh chan (error)    // for tests
 
+	listenersLock sync.Mutex
+	listeners     []*serverListener
+
 	allLoggers []hclog.Logger
 
 	flagConfigs            []string
@@ -864,9 +870,7 @@ func (c *ServerCommand) InitListeners(config *server.Config, disableClustering b
 		}
 
 		if reloadFunc != nil {
-			relSlice := (*c.reloadFuncs)["listener|"+lnConfig.Type]
-			relSlice = append(relSlice, reloadFunc)
-			(*c.reloadFuncs)["listener|"+lnConfig.Type] = relSlice
+			(*c.reloadFuncs)[listenerReloadKey(lnConfig)] = []reloadutil.ReloadFunc{reloadFunc}
 		}
 
 		if !disableClustering && lnConfig.Type == "tcp" {
@@ -894,14 +898,8 @@ func (c *ServerCommand) InitListeners(config *server.Config, disableClustering b
 			props["cluster address"] = addr
 		}
 
-		if lnConfig.MaxRequestSize == 0 {
-			lnConfig.MaxRequestSize = vaulthttp.DefaultMaxRequestSize
-		}
+		setListenerDefaults(lnConfig)
 		props["max_request_size"] = fmt.Sprintf("%d", lnConfig.MaxRequestSize)
-
-		if lnConfig.MaxRequestDuration == 0 {
-			lnConfig.MaxRequestDuration = vault.DefaultMaxRequestDuration
-		}
 		props["max_request_duration"] = lnConfig.MaxRequestDuration.String()
 
 		lns = append(lns, listenerutil.Listener{
@@ -1396,6 +1394,13 @@ func (c *ServerCommand) Run(args []string) int {
 		for _, ln := range lns {
 			ln.Listener.Close()
 		}
+
+		// Listeners may have been added on reload
+		c.listenersLock.Lock()
+		defer c.listenersLock.Unlock()
+		for _, sl := range c.listeners {
+			sl.Listener.Listener.Close()
+		}
 	}
 
 	defer c.cleanupGuard.Do(listenerCloseFunc)
@@ -1536,6 +1541,10 @@ func (c *ServerCommand) Run(args []string) int {
 		c.UI.Output("==> Vault server started! Log data will stream in below:\n")
 	}
 
+	// Reloading the configuration through the API is handled along with SIGHUP
+	c.configReloadCh = make(chan chan configReloadReply)
+	core.SetConfigReloadFunc(c.configReloadFunc())
+
 	// Inform any tests that the server is ready
 	select {
 	case c.startedCh <- struct{}{}:
@@ -1609,90 +1618,18 @@ func (c *ServerCommand) Run(args []string) int {
 		case <-c.SighupCh:
 			c.UI.Output("==> Vault reload triggered")
 
-			// Notify systemd that the server is reloading config
-			c.notifySystemd(systemd.SdNotifyReloading)
-
-			// Check for new log level
-			var config *server.Config
-			var configErrors []configutil.ConfigError
-			for _, path := range c.flagConfigs {
-				current, err := server.LoadConfig(path)
-				if err != nil {
-					c.logger.Error("could not reload config", "path", path, "error", err)
-					goto RUNRELOADFUNCS
-				}
-
-				configErrors = append(configErrors, current.Validate(path)...)
-
-				if config == nil {
-					config = current
-				} else {
-					config = config.Merge(current)
-				}
-			}
-
-			// Ensure at least one config was found.
-			if config == nil {
-				c.logger.Error("no config found at reload time")
-				goto RUNRELOADFUNCS
-			}
-
-			// reporting Errors found in the config
-			for _, cErr := range configErrors {
-				c.logger.Warn(cErr.String())
-			}
-
-			core.SetConfig(config)
-
-			// reloading custom response headers to make sure we have
-			// the most up to date headers after reloading the config file
-			if err = core.ReloadCustomResponseHeaders(); err != nil {
-				c.logger.Error(err.Error())
+			if _, err := c.reloadConfig(core, &hcpLink, hcpLogger); err != nil {
+				c.UI.Error(fmt.Sprintf("Error(s) were encountered during reload: %s", err))
 			}
 
-			// Setting log request with the new value in the config after reload
-			core.ReloadLogRequestsLevel()
+		case replyCh := <-c.configReloadCh:
+			c.UI.Output("==> Vault reload triggered through the API")
 
-			// reloading HCP link
-			hcpLink, err = c.reloadHCPLink(hcpLink, config, core, hcpLogger)
+			result, err := c.reloadConfig(core, &hcpLink, hcpLogger)
 			if err != nil {
-				c.logger.Error(err.Error())
-			}
-
-			// Reload log level for loggers
-			if config.LogLevel != "" {
-				level, err := loghelper.ParseLogLevel(config.LogLevel)
-				if err != nil {
-					c.logger.Error("unknown log level found on reload", "level", config.LogLevel)
-					goto RUNRELOADFUNCS
-				}
-				core.SetLogLevel(level)
-			}
-
-		RUNRELOADFUNCS:
-			if err := c.Reload(c.reloadFuncsLock, c.reloadFuncs, c.flagConfigs, core); err != nil {
 				c.UI.Error(fmt.Sprintf("Error(s) were encountered during reload: %s", err))
 			}
-
-			// Reload license file
-			if err = vault.LicenseReload(core); err != nil {
-				c.UI.Error(err.Error())
-			}
-
-			if err := core.ReloadCensus(); err != nil {
-				c.UI.Error(err.Error())
-			}
-			select {
-			case c.licenseReloadedCh <- err:
-			default:
-			}
-
-			// Let the managedKeyRegistry react to configuration changes (i.e.
-			// changes in kms_libraries)
-			core.ReloadManagedKeyRegistryConfig()
-
-			// Notify systemd that the server has completed reloading config
-			c.notifySystemd(systemd.SdNotifyReady)
+			replyCh <- configReloadReply{result: result, err: err}
 
 		case <-c.SigUSR2Ch:
 			logWriter := c.logger.StandardWriter(&hclog.StandardLoggerOptions{})
@@ -2382,6 +2319,24 @@ func (c *ServerCommand) Reload(lock *sync.RWMutex, reloadFuncs *map[string][]rel
 					}
 				}
 			}
+
+		case strings.HasPrefix(k, "audit_kafka|"):
+			for _, relFunc := range relFuncs {
+				if relFunc != nil {
+					if err := relFunc(); err != nil {
+						reloadErrors = multierror.Append(reloadErrors, fmt.Errorf("error encountered reloading kafka audit device at path %q: %w", strings.TrimPrefix(k, "audit_kafka|"), err))
+					}
+				}
+			}
+
+		case strings.HasPrefix(k, "audit_otel|"):
+			for _, relFunc := range relFuncs {
+				if relFunc != nil {
+					if err := relFunc(); err != nil {
+						reloadErrors = multierror.Append(reloadErrors, fmt.Errorf("error encountered reloading otel audit device at path %q: %w", strings.TrimPrefix(k, "audit_otel|"), err))
+					}
+				}
+			}
 		}
 	}
 
@@ -2518,6 +2473,7 @@ func setSeal(c *ServerCommand, config *server.Config, infoKeys []string, info ma
 		}
 	}
 	var createdSeals []vault.Seal = make([]vault.Seal, len(config.Seals))
+	var multiSeals []*vaultseal.SealWrapper
 	for _, configSeal := range config.Seals {
 		sealType := wrapping.WrapperTypeShamir.String()
 		if !configSeal.Disabled && os.Getenv("VAULT_SEAL_TYPE") != "" {
@@ -2556,6 +2512,20 @@ func setSeal(c *ServerCommand, config *server.Config, infoKeys []string, info ma
 		} else {
 			barrierSeal = seal
 			barrierWrapper = wrapper
+			if wrapper != nil {
+				name := configSeal.Name
+				if name == "" {
+					name = sealType
+				}
+				multiSeals = append(multiSeals, &vaultseal.SealWrapper{
+					Access:   seal.GetAccess(),
+					Name:     name,
+					Priority: configSeal.Priority,
+				})
+				if len(multiSeals) > 1 {
+					infoPrefix = fmt.Sprintf("Seal %q ", name)
+				}
+			}
 		}
 		for _, k := range sealInfoKeys {
 			infoKeys = append(infoKeys, infoPrefix+k)
@@ -2563,6 +2533,28 @@ func setSeal(c *ServerCommand, config *server.Config, infoKeys []string, info ma
 		}
 		createdSeals = append(createdSeals, seal)
 	}
+
+	// When several auto seals are enabled, the keys are encrypted with each
+	// of them so that Vault can be unsealed as long as one is available
+	if len(multiSeals) > 1 {
+		multiLogger := c.logger.ResetNamed("seal.multi")
+		c.allLoggers = append(c.allLoggers, multiLogger)
+		access, err := vaultseal.NewMultiAccess(multiLogger, multiSeals)
+		if err != nil {
+			return nil, nil, nil, nil, nil, fmt.Errorf("Error configuring multiple seals: %w", err)
+		}
+		barrierSeal, err = vault.NewAutoSeal(access)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		barrierWrapper = access.GetWrapper()
+		names := make([]string, 0, len(multiSeals))
+		for _, s := range access.Seals() {
+			names = append(names, s.Name)
+		}
+		infoKeys = append(infoKeys, "Seals")
+		info["Seals"] = strings.Join(names, ", ")
+	}
 	return barrierSeal, barrierWrapper, unwrapSeal, createdSeals, sealConfigError, nil
 }
 
@@ -2769,6 +2761,7 @@ func createCoreConfig(c *ServerCommand, config *server.Config, backend physical.
 		AuditBackends:                  c.AuditBackends,
 		CredentialBackends:             c.CredentialBackends,
 		LogicalBackends:                c.LogicalBackends,
+		GroupResolvers:                 c.GroupResolvers,
 		Logger:                         c.logger,
 		DetectDeadlocks:                config.DetectDeadlocks,
 		DisableSentinelTrace:           config.DisableSentinelTrace,
@@ -2778,9 +2771,12 @@ func createCoreConfig(c *ServerCommand, config *server.Config, backend physical.
 		DefaultLeaseTTL:                config.DefaultLeaseTTL,
 		ClusterName:                    config.ClusterName,
 		CacheSize:                      config.CacheSize,
+		CacheTTL:                       config.Storage.CacheTTL,
+		DisableNegativeCache:           config.Storage.DisableNegativeCache,
 		PluginDirectory:                config.PluginDirectory,
 		PluginFileUid:                  config.PluginFileUid,
 		PluginFilePermissions:          config.PluginFilePermissions,
+		PluginCgroupParent:             config.PluginCgroupParent,
 		EnableUI:                       config.EnableUI,
 		EnableRaw:                      config.EnableRawEndpoint,
 		EnableIntrospection:            config.EnableIntrospectionEndpoint,
@@ -2801,6 +2797,12 @@ func createCoreConfig(c *ServerCommand, config *server.Config, backend physical.
 		Experiments:                    config.Experiments,
 	}
 
+	// The cache size of the storage backend takes precedence over the
+	// top-level one
+	if config.Storage.CacheSize != 0 {
+		coreConfig.CacheSize = config.Storage.CacheSize
+	}
+
 	if c.flagDev {
 		coreConfig.EnableRaw = true
 		coreConfig.EnableIntrospection = true
@@ -2979,46 +2981,18 @@ func startHttpServers(c *ServerCommand, core *vault.Core, config *server.Config,
 			return err
 		}
 
-		handler := vaulthttp.Handler.Handler(&vault.HandlerProperties{
-			Core:                  core,
-			ListenerConfig:        ln.Config,
-			DisablePrintableCheck: config.DisablePrintableCheck,
-			RecoveryMode:          c.flagRecovery,
-		})
-
-		if len(ln.Config.XForwardedForAuthorizedAddrs) > 0 {
-			handler = vaulthttp.WrapForwardedForHandler(handler, ln.Config)
-		}
-
-		// server defaults
-		server := &http.Server{
-			Handler:           handler,
-			ReadHeaderTimeout: 10 * time.Second,
-			ReadTimeout:       30 * time.Second,
-			IdleTimeout:       5 * time.Minute,
-			ErrorLog:          c.logger.StandardLogger(nil),
-		}
-
-		// override server defaults with config values for read/write/idle timeouts if configured
-		if ln.Config.HTTPReadHeaderTimeout > 0 {
-			server.ReadHeaderTimeout = ln.Config.HTTPReadHeaderTimeout
-		}
-		if ln.Config.HTTPReadTimeout > 0 {
-			server.ReadTimeout = ln.Config.HTTPReadTimeout
-		}
-		if ln.Config.HTTPWriteTimeout > 0 {
-			server.WriteTimeout = ln.Config.HTTPWriteTimeout
-		}
-		if ln.Config.HTTPIdleTimeout > 0 {
-			server.IdleTimeout = ln.Config.HTTPIdleTimeout
-		}
+		sl := c.newServerListener(core, config, ln)
 
 		// server config tests can exit now
 		if c.flagTestServerConfig {
 			continue
 		}
 
-		go server.Serve(ln.Listener)
+		c.listenersLock.Lock()
+		c.listeners = append(c.listeners, sl)
+		c.listenersLock.Unlock()
+
+		go sl.server.Serve(ln.Listener)
 	}
 	return nil
 }
@@ -56,6 +56,8 @@ type Config struct {
 
 	ServiceRegistration *ServiceRegistration `hcl:"-"`
 
+	KMSLibraries []*KMSLibrary `hcl:"-"`
+
 	Experiments []string `hcl:"experiments"`
 
 	CacheSize                int         `hcl:"cache_size"`
@@ -81,6 +83,8 @@ type Config struct {
 	PluginFilePermissions    int         `hcl:"-"`
 	PluginFilePermissionsRaw interface{} `hcl:"plugin_file_permissions,alias:PluginFilePermissions"`
 
+	PluginCgroupParent string `hcl:"plugin_cgroup_parent"`
+
 	EnableIntrospectionEndpoint    bool        `hcl:"-"`
 	EnableIntrospectionEndpointRaw interface{} `hcl:"introspection_endpoint,alias:EnableIntrospectionEndpoint"`
 
@@ -241,6 +245,11 @@ type Storage struct {
 	ClusterAddr       string
 	DisableClustering bool
 	Config            map[string]string
+
+	// Configuration of the physical cache of the backend
+	CacheSize            int
+	CacheTTL             time.Duration
+	DisableNegativeCache bool
 }
 
 func (b *Storage) GoString() string {
@@ -262,6 +271,18 @@ func (b *ServiceRegistration) GoString() string {
 	return fmt.Sprintf("*%#v", *b)
 }
 
+// KMSLibrary is a library managed keys reach their KMS with, referenced by
+// the managed keys by name.
+type KMSLibrary struct {
+	Type    string
+	Name    string
+	Library string
+}
+
+func (k *KMSLibrary) GoString() string {
+	return fmt.Sprintf("*%#v", *k)
+}
+
 func NewConfig() *Config {
 	return &Config{
 		SharedConfig: new(configutil.SharedConfig),
@@ -296,6 +317,11 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.ServiceRegistration = c2.ServiceRegistration
 	}
 
+	result.KMSLibraries = c.KMSLibraries
+	if len(c2.KMSLibraries) > 0 {
+		result.KMSLibraries = c2.KMSLibraries
+	}
+
 	result.CacheSize = c.CacheSize
 	if c2.CacheSize != 0 {
 		result.CacheSize = c2.CacheSize
@@ -382,6 +408,11 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.PluginFilePermissionsRaw = c2.PluginFilePermissionsRaw
 	}
 
+	result.PluginCgroupParent = c.PluginCgroupParent
+	if c2.PluginCgroupParent != "" {
+		result.PluginCgroupParent = c2.PluginCgroupParent
+	}
+
 	result.DisablePerformanceStandby = c.DisablePerformanceStandby
 	if c2.DisablePerformanceStandby {
 		result.DisablePerformanceStandby = c2.DisablePerformanceStandby
@@ -728,6 +759,13 @@ func ParseConfig(d, source string) (*Config, error) {
 		}
 	}
 
+	if o := list.Filter("kms_library"); len(o.Items) > 0 {
+		delete(result.UnusedKeys, "kms_library")
+		if err := parseKMSLibraries(result, o); err != nil {
+			return nil, fmt.Errorf("error parsing 'kms_library': %w", err)
+		}
+	}
+
 	if err := validateExperiments(result.Experiments); err != nil {
 		return nil, fmt.Errorf("error validating experiment(s) from config: %w", err)
 	}
@@ -945,6 +983,34 @@ func ParseStorage(result *Config, list *ast.ObjectList, name string) error {
 		delete(m, "disable_clustering")
 	}
 
+	// Pull out the cache configuration since it's common to all backends
+	var cacheSize int
+	if v, ok := m["cache_size"]; ok {
+		cacheSize, err = strconv.Atoi(v)
+		if err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s.%s:", name, key))
+		}
+		delete(m, "cache_size")
+	}
+
+	var cacheTTL time.Duration
+	if v, ok := m["cache_ttl"]; ok {
+		cacheTTL, err = parseutil.ParseDurationSecond(v)
+		if err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s.%s:", name, key))
+		}
+		delete(m, "cache_ttl")
+	}
+
+	var disableNegativeCache bool
+	if v, ok := m["disable_negative_cache"]; ok {
+		disableNegativeCache, err = strconv.ParseBool(v)
+		if err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s.%s:", name, key))
+		}
+		delete(m, "disable_negative_cache")
+	}
+
 	// Override with top-level values if they are set
 	if result.APIAddr != "" {
 		redirectAddr = result.APIAddr
@@ -959,11 +1025,14 @@ func ParseStorage(result *Config, list *ast.ObjectList, name string) error {
 	}
 
 	result.Storage = &Storage{
-		RedirectAddr:      redirectAddr,
-		ClusterAddr:       clusterAddr,
-		DisableClustering: disableClustering,
-		Type:              strings.ToLower(key),
-		Config:            m,
+		RedirectAddr:         redirectAddr,
+		ClusterAddr:          clusterAddr,
+		DisableClustering:    disableClustering,
+		Type:                 strings.ToLower(key),
+		Config:               m,
+		CacheSize:            cacheSize,
+		CacheTTL:             cacheTTL,
+		DisableNegativeCache: disableNegativeCache,
 	}
 	return nil
 }
@@ -1074,6 +1143,45 @@ func parseServiceRegistration(result *Config, list *ast.ObjectList, name string)
 	return nil
 }
 
+func parseKMSLibraries(result *Config, list *ast.ObjectList) error {
+	names := make(map[string]struct{})
+	for _, item := range list.Items {
+		if len(item.Keys) == 0 {
+			return errors.New("kms_library type must be specified")
+		}
+		libType := strings.ToLower(item.Keys[0].Token.Value().(string))
+		if libType != "pkcs11" {
+			return fmt.Errorf("unsupported kms_library type %q", libType)
+		}
+
+		var m map[string]string
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("kms_library.%s:", libType))
+		}
+		lib := &KMSLibrary{
+			Type:    libType,
+			Name:    m["name"],
+			Library: m["library"],
+		}
+		if lib.Name == "" {
+			return fmt.Errorf("kms_library.%s: name is required", libType)
+		}
+		if lib.Library == "" {
+			return fmt.Errorf("kms_library.%s: library is required", libType)
+		}
+
+		// Names are case-insensitive
+		name := strings.ToLower(lib.Name)
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("kms_library.%s: duplicate name %q", libType, lib.Name)
+		}
+		names[name] = struct{}{}
+
+		result.KMSLibraries = append(result.KMSLibraries, lib)
+	}
+	return nil
+}
+
 // Sanitized returns a copy of the config with all values that are considered
 // sensitive stripped. It also strips all `*Raw` values that are mainly
 // used for parsing.
@@ -1109,6 +1217,8 @@ func (c *Config) Sanitized() map[string]interface{} {
 
 		"plugin_file_permissions": c.PluginFilePermissions,
 
+		"plugin_cgroup_parent": c.PluginCgroupParent,
+
 		"raw_storage_endpoint": c.EnableRawEndpoint,
 
 		"introspection_endpoint": c.EnableIntrospectionEndpoint,
@@ -1146,6 +1256,16 @@ func (c *Config) Sanitized() map[string]interface{} {
 			"disable_clustering": c.Storage.DisableClustering,
 		}
 
+		if c.Storage.CacheSize != 0 {
+			sanitizedStorage["cache_size"] = c.Storage.CacheSize
+		}
+		if c.Storage.CacheTTL != 0 {
+			sanitizedStorage["cache_ttl"] = c.Storage.CacheTTL / time.Second
+		}
+		if c.Storage.DisableNegativeCache {
+			sanitizedStorage["disable_negative_cache"] = c.Storage.DisableNegativeCache
+		}
+
 		if storageType == "raft" {
 			sanitizedStorage["raft"] = map[string]interface{}{
 				"max_entry_size": c.Storage.Config["max_entry_size"],
@@ -1182,6 +1302,19 @@ func (c *Config) Sanitized() map[string]interface{} {
 		result["service_registration"] = sanitizedServiceRegistration
 	}
 
+	// Sanitize kms_library stanzas
+	if len(c.KMSLibraries) > 0 {
+		var sanitizedKMSLibraries []interface{}
+		for _, lib := range c.KMSLibraries {
+			sanitizedKMSLibraries = append(sanitizedKMSLibraries, map[string]interface{}{
+				"type":    lib.Type,
+				"name":    lib.Name,
+				"library": lib.Library,
+			})
+		}
+		result["kms_library"] = sanitizedKMSLibraries
+	}
+
 	entConfigResult := c.entConfig.Sanitized()
 	for k, v := range entConfigResult {
 		result[k] = v
@@ -189,6 +189,64 @@ func TestMerge(t *testing.T) {
 	}
 }
 
+func TestKMSLibraryConfigParsing(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config        string
+		expected      []*KMSLibrary
+		expectedError string
+	}{
+		"none": {"", nil, ""},
+		"multiple": {`
+kms_library "pkcs11" {
+  name    = "hsm1"
+  library = "/usr/lib/hsm1.so"
+}
+kms_library "pkcs11" {
+  name    = "hsm2"
+  library = "/usr/lib/hsm2.so"
+}`, []*KMSLibrary{
+			{Type: "pkcs11", Name: "hsm1", Library: "/usr/lib/hsm1.so"},
+			{Type: "pkcs11", Name: "hsm2", Library: "/usr/lib/hsm2.so"},
+		}, ""},
+
+		// Validation errors.
+		"unsupported type": {`kms_library "awskms" { name = "kms", library = "/usr/lib/kms.so" }`, nil, "unsupported kms_library type"},
+		"missing name":     {`kms_library "pkcs11" { library = "/usr/lib/hsm.so" }`, nil, "name is required"},
+		"missing library":  {`kms_library "pkcs11" { name = "hsm" }`, nil, "library is required"},
+		"duplicate name": {`
+kms_library "pkcs11" {
+  name    = "hsm"
+  library = "/usr/lib/hsm1.so"
+}
+kms_library "pkcs11" {
+  name    = "HSM"
+  library = "/usr/lib/hsm2.so"
+}`, nil, "duplicate name"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config, err := ParseConfig(tc.config, "")
+
+			switch tc.expectedError {
+			case "":
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(tc.expected, config.KMSLibraries) {
+					t.Fatalf("Expected %#v but got %#v", tc.expected, config.KMSLibraries)
+				}
+				if _, ok := config.UnusedKeys["kms_library"]; ok {
+					t.Fatal("Expected kms_library not to be reported as unused")
+				}
+
+			default:
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("Expected error to contain %q, but got: %s", tc.expectedError, err)
+				}
+			}
+		})
+	}
+}
+
 // Test_parseDevTLSConfig verifies that both Windows and Unix directories are correctly escaped when creating a dev TLS
 // configuration in HCL
 func Test_parseDevTLSConfig(t *testing.T) {
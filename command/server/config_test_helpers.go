@@ -90,6 +90,7 @@ func testLoadConfigFile_topLevel(t *testing.T, entropy *configutil.Entropy) {
 				MaximumGaugeCardinality:     125,
 				LeaseMetricsEpsilon:         time.Hour,
 				NumLeaseMetricsTimeBuckets:  168,
+				RouteMetricsMaxMounts:       100,
 				LeaseMetricsNameSpaceLabels: false,
 			},
 
@@ -217,6 +218,7 @@ func testLoadConfigFile_json2(t *testing.T, entropy *configutil.Entropy) {
 				PrometheusRetentionTime:            30 * time.Second,
 				LeaseMetricsEpsilon:                time.Hour,
 				NumLeaseMetricsTimeBuckets:         168,
+				RouteMetricsMaxMounts:              100,
 				LeaseMetricsNameSpaceLabels:        false,
 			},
 		},
@@ -409,6 +411,7 @@ func testLoadConfigFile(t *testing.T) {
 				MetricsPrefix:               "myprefix",
 				LeaseMetricsEpsilon:         time.Hour,
 				NumLeaseMetricsTimeBuckets:  168,
+				RouteMetricsMaxMounts:       100,
 				LeaseMetricsNameSpaceLabels: false,
 			},
 
@@ -610,6 +613,7 @@ func testLoadConfigFile_json(t *testing.T) {
 				PrometheusRetentionTime:            configutil.PrometheusDefaultRetentionTime,
 				LeaseMetricsEpsilon:                time.Hour,
 				NumLeaseMetricsTimeBuckets:         168,
+				RouteMetricsMaxMounts:              100,
 				LeaseMetricsNameSpaceLabels:        false,
 			},
 
@@ -683,6 +687,7 @@ func testLoadConfigDir(t *testing.T) {
 				PrometheusRetentionTime:     configutil.PrometheusDefaultRetentionTime,
 				LeaseMetricsEpsilon:         time.Hour,
 				NumLeaseMetricsTimeBuckets:  168,
+				RouteMetricsMaxMounts:       100,
 				LeaseMetricsNameSpaceLabels: false,
 			},
 			ClusterName: "testcluster",
@@ -744,6 +749,7 @@ func testConfig_Sanitized(t *testing.T) {
 		"experiments":                         []string(nil),
 		"plugin_file_uid":                     0,
 		"plugin_file_permissions":             0,
+		"plugin_cgroup_parent":                "",
 		"disable_printable_check":             false,
 		"disable_sealwrap":                    true,
 		"raw_storage_endpoint":                true,
@@ -1014,6 +1020,9 @@ storage "consul" {
 
 	disable_registration = false
 	path = "tmp/"
+	cache_size = 1024
+	cache_ttl = "5m"
+	disable_negative_cache = true
 
 }
 ha_storage "consul" {
@@ -1034,6 +1043,9 @@ ha_storage "consul" {
 				"disable_registration": "false",
 				"path":                 "tmp/",
 			},
+			CacheSize:            1024,
+			CacheTTL:             5 * time.Minute,
+			DisableNegativeCache: true,
 		},
 		HAStorage: &Storage{
 			Type: "consul",
@@ -1139,6 +1151,7 @@ func testLoadConfigFileLeaseMetrics(t *testing.T) {
 				MetricsPrefix:               "myprefix",
 				LeaseMetricsEpsilon:         time.Hour,
 				NumLeaseMetricsTimeBuckets:  2,
+				RouteMetricsMaxMounts:       100,
 				LeaseMetricsNameSpaceLabels: true,
 			},
 
@@ -4,6 +4,7 @@
 package server
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-sockaddr"
+	"github.com/hashicorp/vault/helper/proxyutil"
 	"github.com/hashicorp/vault/internalshared/configutil"
 	"github.com/mitchellh/cli"
 	"github.com/pires/go-proxyproto"
@@ -464,3 +466,87 @@ func TestTCPListener_proxyProtocol(t *testing.T) {
 		})
 	}
 }
+
+// TestTCPListener_proxyProtocolV2Info tests that the PROXY protocol v2
+// information, including TLVs, is made available to the HTTP server
+func TestTCPListener_proxyProtocolV2Info(t *testing.T) {
+	ln, _, _, err := tcpListenerFactory(&configutil.Listener{
+		Address:               "127.0.0.1:0",
+		TLSDisable:            true,
+		ProxyProtocolBehavior: "use_always",
+	}, nil, cli.NewMockUi())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer ln.Close()
+
+	header := &proxyproto.Header{
+		Version:           2,
+		Command:           proxyproto.PROXY,
+		TransportProtocol: proxyproto.TCPv4,
+		SourceAddr: &net.TCPAddr{
+			IP:   net.ParseIP("10.1.1.1"),
+			Port: 1000,
+		},
+		DestinationAddr: &net.TCPAddr{
+			IP:   net.ParseIP("20.2.2.2"),
+			Port: 2000,
+		},
+	}
+	if err := header.SetTLVs([]proxyproto.TLV{
+		{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("vault.example.com")},
+		{Type: proxyproto.PP2_TYPE_UNIQUE_ID, Value: []byte{0xde, 0xad}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if _, err := header.WriteTo(client); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	info := proxyutil.ProxyInfoFromContext(proxyutil.ConnContext(context.Background(), server))
+	if info == nil {
+		t.Fatal("expected proxy info")
+	}
+	if info.Version != 2 {
+		t.Fatalf("bad version: %d", info.Version)
+	}
+	if host, _, _ := net.SplitHostPort(info.ProxyAddr.String()); host != "127.0.0.1" {
+		t.Fatalf("bad proxy address: %s", info.ProxyAddr)
+	}
+	if host, _, _ := net.SplitHostPort(server.RemoteAddr().String()); host != "10.1.1.1" {
+		t.Fatalf("bad remote address: %s", server.RemoteAddr())
+	}
+	if info.Metadata["authority"] != "vault.example.com" || info.Metadata["unique_id"] != "dead" {
+		t.Fatalf("bad metadata: %#v", info.Metadata)
+	}
+
+	// Connections without a header don't carry any info
+	client2, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client2.Close()
+	if _, err := client2.Write([]byte("GET / HTTP/1.1\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	server2, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server2.Close()
+	if info := proxyutil.ProxyInfoFromContext(proxyutil.ConnContext(context.Background(), server2)); info != nil {
+		t.Fatalf("unexpected proxy info: %#v", info)
+	}
+}
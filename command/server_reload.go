@@ -0,0 +1,571 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	systemd "github.com/coreos/go-systemd/daemon"
+	"github.com/hashicorp/go-hclog"
+	wrapping "github.com/hashicorp/go-kms-wrapping/v2"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-secure-stdlib/reloadutil"
+	config2 "github.com/hashicorp/vault/command/config"
+	"github.com/hashicorp/vault/command/server"
+	loghelper "github.com/hashicorp/vault/helper/logging"
+	"github.com/hashicorp/vault/helper/proxyutil"
+	vaulthttp "github.com/hashicorp/vault/http"
+	"github.com/hashicorp/vault/internalshared/configutil"
+	"github.com/hashicorp/vault/internalshared/listenerutil"
+	"github.com/hashicorp/vault/vault"
+	"github.com/hashicorp/vault/vault/hcp_link"
+)
+
+// restartRequiredSettings are the settings that are only applied when the
+// server starts, by the name they are reported with on reload
+var restartRequiredSettings = map[string]func(*server.Config) interface{}{
+	"storage":    func(c *server.Config) interface{} { return c.Storage },
+	"ha_storage": func(c *server.Config) interface{} { return c.HAStorage },
+	"seal": func(c *server.Config) interface{} {
+		// The shamir seal is added to the configuration when no seal is
+		// configured, so it is ignored
+		var seals []*configutil.KMS
+		for _, seal := range c.Seals {
+			if seal.Type != wrapping.WrapperTypeShamir.String() {
+				seals = append(seals, seal)
+			}
+		}
+		return seals
+	},
+	"entropy":                      func(c *server.Config) interface{} { return c.Entropy },
+	"cluster_name":                 func(c *server.Config) interface{} { return c.ClusterName },
+	"api_addr":                     func(c *server.Config) interface{} { return c.APIAddr },
+	"cluster_addr":                 func(c *server.Config) interface{} { return c.ClusterAddr },
+	"cluster_cipher_suites":        func(c *server.Config) interface{} { return c.ClusterCipherSuites },
+	"disable_clustering":           func(c *server.Config) interface{} { return c.DisableClustering },
+	"disable_mlock":                func(c *server.Config) interface{} { return c.DisableMlock },
+	"disable_cache":                func(c *server.Config) interface{} { return c.DisableCache },
+	"cache_size":                   func(c *server.Config) interface{} { return c.CacheSize },
+	"ui":                           func(c *server.Config) interface{} { return c.EnableUI },
+	"plugin_directory":             func(c *server.Config) interface{} { return c.PluginDirectory },
+	"plugin_file_uid":              func(c *server.Config) interface{} { return c.PluginFileUid },
+	"plugin_file_permissions":      func(c *server.Config) interface{} { return c.PluginFilePermissions },
+	"default_lease_ttl":            func(c *server.Config) interface{} { return c.DefaultLeaseTTL },
+	"max_lease_ttl":                func(c *server.Config) interface{} { return c.MaxLeaseTTL },
+	"default_max_request_duration": func(c *server.Config) interface{} { return c.DefaultMaxRequestDuration },
+	"raw_storage_endpoint":         func(c *server.Config) interface{} { return c.EnableRawEndpoint },
+	"disable_performance_standby":  func(c *server.Config) interface{} { return c.DisablePerformanceStandby },
+	"disable_sealwrap":             func(c *server.Config) interface{} { return c.DisableSealWrap },
+	"experiments":                  func(c *server.Config) interface{} { return c.Experiments },
+	"log_format":                   func(c *server.Config) interface{} { return c.LogFormat },
+	"log_file":                     func(c *server.Config) interface{} { return c.LogFile },
+	"log_rotate_duration":          func(c *server.Config) interface{} { return c.LogRotateDuration },
+	"log_rotate_bytes":             func(c *server.Config) interface{} { return c.LogRotateBytes },
+	"log_rotate_max_files":         func(c *server.Config) interface{} { return c.LogRotateMaxFiles },
+	"pid_file":                     func(c *server.Config) interface{} { return c.PidFile },
+	"service_registration": func(c *server.Config) interface{} {
+		if c.ServiceRegistration == nil {
+			return nil
+		}
+		return []interface{}{c.ServiceRegistration.Type, c.ServiceRegistration.Config}
+	},
+}
+
+// reloadedSettings are the settings applied on reload, other than the
+// listeners and the telemetry prefix filter
+var reloadedSettings = map[string]func(*server.Config) interface{}{
+	"log_level":              func(c *server.Config) interface{} { return c.LogLevel },
+	"log_requests_level":     func(c *server.Config) interface{} { return c.LogRequestsLevel },
+	"introspection_endpoint": func(c *server.Config) interface{} { return c.EnableIntrospectionEndpoint },
+	"kms_library":            func(c *server.Config) interface{} { return c.KMSLibraries },
+}
+
+// configReloadReply is the reply to a configuration reload requested through
+// the API
+type configReloadReply struct {
+	result *vault.ConfigReloadResult
+	err    error
+}
+
+// serverListener is a listener serving the API. Its handler is replaced on
+// reload to apply configuration changes to the running listener.
+type serverListener struct {
+	listenerutil.Listener
+
+	server  *http.Server
+	handler *reloadableHandler
+}
+
+// reloadableHandler is an http.Handler whose underlying handler can be
+// replaced while serving requests
+type reloadableHandler struct {
+	handler atomic.Value
+}
+
+// storedHandler wraps handlers so that atomic.Value always stores the same type
+type storedHandler struct {
+	http.Handler
+}
+
+func newReloadableHandler(h http.Handler) *reloadableHandler {
+	r := new(reloadableHandler)
+	r.set(h)
+	return r
+}
+
+func (r *reloadableHandler) set(h http.Handler) {
+	r.handler.Store(storedHandler{h})
+}
+
+func (r *reloadableHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.handler.Load().(storedHandler).ServeHTTP(w, req)
+}
+
+// setListenerDefaults sets the default request limits of the listener
+func setListenerDefaults(lnConfig *configutil.Listener) {
+	if lnConfig.MaxRequestSize == 0 {
+		lnConfig.MaxRequestSize = vaulthttp.DefaultMaxRequestSize
+	}
+	if lnConfig.MaxRequestDuration == 0 {
+		lnConfig.MaxRequestDuration = vault.DefaultMaxRequestDuration
+	}
+}
+
+// listenerReloadKey is the key of the reload function of the listener
+func listenerReloadKey(lnConfig *configutil.Listener) string {
+	return "listener|" + lnConfig.Type + "|" + lnConfig.Address
+}
+
+// listenerSocketConfig returns the settings applied when the socket of the
+// listener is opened, which can only be changed by reopening it
+func listenerSocketConfig(lnConfig *configutil.Listener) configutil.Listener {
+	l := comparableListener(lnConfig)
+	return configutil.Listener{
+		Type:                          l.Type,
+		Address:                       l.Address,
+		TLSDisable:                    l.TLSDisable,
+		TLSCertFile:                   l.TLSCertFile,
+		TLSKeyFile:                    l.TLSKeyFile,
+		TLSMinVersion:                 l.TLSMinVersion,
+		TLSMaxVersion:                 l.TLSMaxVersion,
+		TLSCipherSuites:               l.TLSCipherSuites,
+		TLSRequireAndVerifyClientCert: l.TLSRequireAndVerifyClientCert,
+		TLSClientCAFile:               l.TLSClientCAFile,
+		TLSDisableClientCerts:         l.TLSDisableClientCerts,
+		HTTPReadTimeout:               l.HTTPReadTimeout,
+		HTTPReadHeaderTimeout:         l.HTTPReadHeaderTimeout,
+		HTTPWriteTimeout:              l.HTTPWriteTimeout,
+		HTTPIdleTimeout:               l.HTTPIdleTimeout,
+		ProxyProtocolBehavior:         l.ProxyProtocolBehavior,
+		ProxyProtocolAuthorizedAddrs:  l.ProxyProtocolAuthorizedAddrs,
+		SocketMode:                    l.SocketMode,
+		SocketUser:                    l.SocketUser,
+		SocketGroup:                   l.SocketGroup,
+	}
+}
+
+// comparableListener returns the listener configuration without the values
+// that don't change its behavior, such as the position of unused keys, and
+// with the defaults set when the listener is opened
+func comparableListener(l *configutil.Listener) configutil.Listener {
+	ret := *l
+	ret.UnusedKeys = nil
+	ret.RawConfig = nil
+	ret.Telemetry.UnusedKeys = nil
+	ret.Profiling.UnusedKeys = nil
+	if !ret.TLSDisable {
+		if ret.TLSMinVersion == "" {
+			ret.TLSMinVersion = "tls12"
+		}
+		if ret.TLSMaxVersion == "" {
+			ret.TLSMaxVersion = "tls13"
+		}
+	}
+	return ret
+}
+
+// telemetrySettings returns the telemetry settings by name
+func telemetrySettings(t *configutil.Telemetry) map[string]interface{} {
+	if t == nil {
+		t = new(configutil.Telemetry)
+	}
+
+	settings := make(map[string]interface{})
+	v := reflect.ValueOf(*t)
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("hcl"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		settings[name] = v.Field(i).Interface()
+	}
+
+	return settings
+}
+
+func (c *ServerCommand) listenerHandler(core *vault.Core, config *server.Config, lnConfig *configutil.Listener) http.Handler {
+	handler := vaulthttp.Handler.Handler(&vault.HandlerProperties{
+		Core:                  core,
+		ListenerConfig:        lnConfig,
+		DisablePrintableCheck: config.DisablePrintableCheck,
+		RecoveryMode:          c.flagRecovery,
+	})
+
+	if len(lnConfig.XForwardedForAuthorizedAddrs) > 0 {
+		handler = vaulthttp.WrapForwardedForHandler(handler, lnConfig)
+	}
+
+	return handler
+}
+
+func (c *ServerCommand) newServerListener(core *vault.Core, config *server.Config, ln listenerutil.Listener) *serverListener {
+	handler := newReloadableHandler(c.listenerHandler(core, config, ln.Config))
+
+	// server defaults
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		IdleTimeout:       5 * time.Minute,
+		ErrorLog:          c.logger.StandardLogger(nil),
+		ConnContext:       proxyutil.ConnContext,
+	}
+
+	// override server defaults with config values for read/write/idle timeouts if configured
+	if ln.Config.HTTPReadHeaderTimeout > 0 {
+		srv.ReadHeaderTimeout = ln.Config.HTTPReadHeaderTimeout
+	}
+	if ln.Config.HTTPReadTimeout > 0 {
+		srv.ReadTimeout = ln.Config.HTTPReadTimeout
+	}
+	if ln.Config.HTTPWriteTimeout > 0 {
+		srv.WriteTimeout = ln.Config.HTTPWriteTimeout
+	}
+	if ln.Config.HTTPIdleTimeout > 0 {
+		srv.IdleTimeout = ln.Config.HTTPIdleTimeout
+	}
+
+	return &serverListener{
+		Listener: ln,
+		server:   srv,
+		handler:  handler,
+	}
+}
+
+// openServerListener opens and serves a listener added on reload. The reload
+// functions lock must be held.
+func (c *ServerCommand) openServerListener(core *vault.Core, config *server.Config, lnConfig *configutil.Listener) (*serverListener, error) {
+	if err := config2.IsValidListener(lnConfig); err != nil {
+		return nil, err
+	}
+
+	ln, _, reloadFunc, err := server.NewListener(lnConfig, c.logGate, c.UI)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing listener of type %s: %w", lnConfig.Type, err)
+	}
+	if reloadFunc != nil {
+		(*c.reloadFuncs)[listenerReloadKey(lnConfig)] = []reloadutil.ReloadFunc{reloadFunc}
+	}
+
+	sl := c.newServerListener(core, config, listenerutil.Listener{
+		Listener: ln,
+		Config:   lnConfig,
+	})
+	go sl.server.Serve(ln)
+
+	return sl, nil
+}
+
+// closeServerListener stops accepting connections on the listener, and lets
+// the requests in flight complete in the background. The reload functions
+// lock must be held.
+func (c *ServerCommand) closeServerListener(sl *serverListener) {
+	sl.Listener.Listener.Close()
+	delete(*c.reloadFuncs, listenerReloadKey(sl.Config))
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sl.Config.MaxRequestDuration)
+		defer cancel()
+		if err := sl.server.Shutdown(ctx); err != nil {
+			c.logger.Debug("error shutting down listener", "address", sl.Config.Address, "error", err)
+		}
+	}()
+}
+
+// reloadListeners applies the listener configuration: listeners are opened
+// and closed as they are added and removed, listeners whose socket settings
+// changed are reopened, and the handlers of the others are replaced to apply
+// settings such as request limits.
+func (c *ServerCommand) reloadListeners(core *vault.Core, oldConfig, config *server.Config, result *vault.ConfigReloadResult) error {
+	c.listenersLock.Lock()
+	defer c.listenersLock.Unlock()
+	c.reloadFuncsLock.Lock()
+	defer c.reloadFuncsLock.Unlock()
+
+	current := make(map[string]*serverListener, len(c.listeners))
+	for _, sl := range c.listeners {
+		current[listenerReloadKey(sl.Config)] = sl
+	}
+	printableCheckChanged := oldConfig.DisablePrintableCheck != config.DisablePrintableCheck
+
+	var reloadErrors *multierror.Error
+	listeners := make([]*serverListener, 0, len(config.Listeners))
+	for _, lnConfig := range config.Listeners {
+		setListenerDefaults(lnConfig)
+		name := fmt.Sprintf("listener %q", lnConfig.Address)
+
+		key := listenerReloadKey(lnConfig)
+		sl, ok := current[key]
+		delete(current, key)
+
+		switch {
+		case !ok:
+			sl, err := c.openServerListener(core, config, lnConfig)
+			if err != nil {
+				reloadErrors = multierror.Append(reloadErrors, err)
+				continue
+			}
+			listeners = append(listeners, sl)
+			result.Applied = append(result.Applied, name+" (added)")
+
+			if lnConfig.ClusterAddress != "" {
+				result.RestartRequired = append(result.RestartRequired, name+" cluster_address")
+			}
+
+		case !reflect.DeepEqual(listenerSocketConfig(sl.Config), listenerSocketConfig(lnConfig)):
+			c.closeServerListener(sl)
+			sl, err := c.openServerListener(core, config, lnConfig)
+			if err != nil {
+				reloadErrors = multierror.Append(reloadErrors, err)
+				continue
+			}
+			listeners = append(listeners, sl)
+			result.Applied = append(result.Applied, name+" (reopened)")
+
+		default:
+			listeners = append(listeners, sl)
+
+			if sl.Config.ClusterAddress != lnConfig.ClusterAddress {
+				result.RestartRequired = append(result.RestartRequired, name+" cluster_address")
+			}
+			changed := !reflect.DeepEqual(comparableListener(sl.Config), comparableListener(lnConfig))
+			if !changed && !printableCheckChanged {
+				continue
+			}
+
+			sl.Config = lnConfig
+			sl.handler.set(c.listenerHandler(core, config, lnConfig))
+			if changed {
+				result.Applied = append(result.Applied, name+" (updated)")
+			}
+		}
+	}
+
+	// Close the listeners removed from the configuration
+	for _, sl := range c.listeners {
+		if current[listenerReloadKey(sl.Config)] != sl {
+			continue
+		}
+		c.closeServerListener(sl)
+		result.Applied = append(result.Applied, fmt.Sprintf("listener %q (removed)", sl.Config.Address))
+	}
+
+	if printableCheckChanged {
+		result.Applied = append(result.Applied, "disable_printable_check")
+	}
+
+	c.listeners = listeners
+	return reloadErrors.ErrorOrNil()
+}
+
+// applyConfig loads the configuration files and applies the settings that can
+// be changed while the server is running. It reports the changed settings
+// that require a restart.
+func (c *ServerCommand) applyConfig(core *vault.Core, hcpLink **hcp_link.HCPLinkVault, hcpLogger hclog.Logger, result *vault.ConfigReloadResult) error {
+	var config *server.Config
+	var configErrors []configutil.ConfigError
+	for _, path := range c.flagConfigs {
+		current, err := server.LoadConfig(path)
+		if err != nil {
+			c.logger.Error("could not reload config", "path", path, "error", err)
+			return fmt.Errorf("could not reload config %q: %w", path, err)
+		}
+
+		configErrors = append(configErrors, current.Validate(path)...)
+
+		if config == nil {
+			config = current
+		} else {
+			config = config.Merge(current)
+		}
+	}
+
+	// Ensure at least one config was found.
+	if config == nil {
+		c.logger.Error("no config found at reload time")
+		return errors.New("no config found at reload time")
+	}
+
+	// reporting Errors found in the config
+	for _, cErr := range configErrors {
+		c.logger.Warn(cErr.String())
+	}
+
+	oldConfig := core.GetCoreConfigInternal()
+	core.SetConfig(config)
+
+	// reloading custom response headers to make sure we have
+	// the most up to date headers after reloading the config file
+	if err := core.ReloadCustomResponseHeaders(); err != nil {
+		c.logger.Error(err.Error())
+	}
+
+	// Setting log request with the new value in the config after reload
+	core.ReloadLogRequestsLevel()
+
+	// reloading HCP link
+	var err error
+	*hcpLink, err = c.reloadHCPLink(*hcpLink, config, core, hcpLogger)
+	if err != nil {
+		c.logger.Error(err.Error())
+	}
+
+	var reloadErrors *multierror.Error
+
+	// Reload log level for loggers
+	if config.LogLevel != "" {
+		level, err := loghelper.ParseLogLevel(config.LogLevel)
+		if err != nil {
+			c.logger.Error("unknown log level found on reload", "level", config.LogLevel)
+			reloadErrors = multierror.Append(reloadErrors, fmt.Errorf("unknown log level %q", config.LogLevel))
+		} else {
+			core.SetLogLevel(level)
+		}
+	}
+
+	if oldConfig == nil {
+		return reloadErrors.ErrorOrNil()
+	}
+
+	for _, settings := range []struct {
+		values map[string]func(*server.Config) interface{}
+		report *[]string
+	}{
+		{reloadedSettings, &result.Applied},
+		{restartRequiredSettings, &result.RestartRequired},
+	} {
+		for name, value := range settings.values {
+			if !reflect.DeepEqual(value(oldConfig), value(config)) {
+				*settings.report = append(*settings.report, name)
+			}
+		}
+	}
+
+	oldTelemetry, telemetry := telemetrySettings(oldConfig.Telemetry), telemetrySettings(config.Telemetry)
+	for name, value := range telemetry {
+		if reflect.DeepEqual(oldTelemetry[name], value) {
+			continue
+		}
+		if name != "prefix_filter" {
+			result.RestartRequired = append(result.RestartRequired, "telemetry."+name)
+			continue
+		}
+
+		if err := configutil.UpdateTelemetryFilter(config.Telemetry); err != nil {
+			reloadErrors = multierror.Append(reloadErrors, fmt.Errorf("error updating telemetry prefix filter: %w", err))
+			continue
+		}
+		result.Applied = append(result.Applied, "telemetry."+name)
+	}
+
+	// The dev listener isn't part of the configuration files
+	if c.flagDev {
+		result.RestartRequired = append(result.RestartRequired, "listeners")
+	} else if err := c.reloadListeners(core, oldConfig, config, result); err != nil {
+		reloadErrors = multierror.Append(reloadErrors, err)
+	}
+
+	return reloadErrors.ErrorOrNil()
+}
+
+// reloadConfig reloads the configuration files, as on SIGHUP, and runs the
+// reload functions of the listeners and audit devices.
+func (c *ServerCommand) reloadConfig(core *vault.Core, hcpLink **hcp_link.HCPLinkVault, hcpLogger hclog.Logger) (*vault.ConfigReloadResult, error) {
+	// Notify systemd that the server is reloading config
+	c.notifySystemd(systemd.SdNotifyReloading)
+
+	result := &vault.ConfigReloadResult{
+		Applied:         []string{},
+		RestartRequired: []string{},
+	}
+
+	var reloadErrors *multierror.Error
+	if err := c.applyConfig(core, hcpLink, hcpLogger, result); err != nil {
+		reloadErrors = multierror.Append(reloadErrors, err)
+	}
+	sort.Strings(result.Applied)
+	sort.Strings(result.RestartRequired)
+
+	if err := c.Reload(c.reloadFuncsLock, c.reloadFuncs, c.flagConfigs, core); err != nil {
+		reloadErrors = multierror.Append(reloadErrors, err)
+	}
+
+	// Reload license file
+	err := vault.LicenseReload(core)
+	if err != nil {
+		c.UI.Error(err.Error())
+	}
+
+	if err := core.ReloadCensus(); err != nil {
+		c.UI.Error(err.Error())
+	}
+	select {
+	case c.licenseReloadedCh <- err:
+	default:
+	}
+
+	// Let the managedKeyRegistry react to configuration changes (i.e.
+	// changes in kms_libraries)
+	core.ReloadManagedKeyRegistryConfig()
+
+	if len(result.Applied) > 0 {
+		c.logger.Info("applied configuration changes", "settings", result.Applied)
+	}
+	if len(result.RestartRequired) > 0 {
+		c.logger.Warn("configuration changes require a restart to apply", "settings", result.RestartRequired)
+	}
+
+	// Notify systemd that the server has completed reloading config
+	c.notifySystemd(systemd.SdNotifyReady)
+
+	return result, reloadErrors.ErrorOrNil()
+}
+
+// configReloadFunc returns the function reloading the configuration through
+// the API. The reload runs on the main loop of the server, like on SIGHUP.
+func (c *ServerCommand) configReloadFunc() vault.ConfigReloadFunc {
+	return func(ctx context.Context) (*vault.ConfigReloadResult, error) {
+		replyCh := make(chan configReloadReply, 1)
+		select {
+		case c.configReloadCh <- replyCh:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		select {
+		case reply := <-replyCh:
+			return reply.result, reply.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
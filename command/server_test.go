@@ -11,10 +11,12 @@
 package command
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"strings"
 	"sync"
@@ -206,6 +208,104 @@ func TestServer_ReloadListener(t *testing.T) {
 	wg.Wait()
 }
 
+func TestServer_ReloadConfig(t *testing.T) {
+	t.Parallel()
+
+	td := t.TempDir()
+	writeConfig := func(config string) {
+		t.Helper()
+		if err := os.WriteFile(td+"/config.hcl", []byte(config), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(`
+backend "inmem" {}
+disable_mlock = true
+listener "tcp" {
+  address     = "127.0.0.1:8206"
+  tls_disable = true
+}
+`)
+
+	ui, cmd := testServerCommand(t)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		if code := cmd.Run([]string{"-config", td + "/config.hcl"}); code != 0 {
+			output := ui.ErrorWriter.String() + ui.OutputWriter.String()
+			t.Errorf("got a non-zero exit status: %s", output)
+		}
+		wg.Done()
+	}()
+
+	select {
+	case <-cmd.startedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout")
+	}
+
+	testListener := func(address string) error {
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	require.NoError(t, testListener("127.0.0.1:8206"))
+
+	writeConfig(`
+backend "inmem" {}
+disable_mlock = true
+log_level = "debug"
+log_format = "json"
+listener "tcp" {
+  address     = "127.0.0.1:8207"
+  tls_disable = true
+}
+`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := cmd.configReloadFunc()(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`listener "127.0.0.1:8206" (removed)`,
+		`listener "127.0.0.1:8207" (added)`,
+		"log_level",
+	}, result.Applied)
+	require.Equal(t, []string{"log_format"}, result.RestartRequired)
+
+	require.NoError(t, testListener("127.0.0.1:8207"))
+	require.Error(t, testListener("127.0.0.1:8206"))
+
+	// Handler settings are applied to the running listener
+	writeConfig(`
+backend "inmem" {}
+disable_mlock = true
+log_level = "debug"
+log_format = "json"
+listener "tcp" {
+  address          = "127.0.0.1:8207"
+  tls_disable      = true
+  max_request_size = 1024
+}
+`)
+	result, err = cmd.configReloadFunc()(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{`listener "127.0.0.1:8207" (updated)`}, result.Applied)
+	require.Empty(t, result.RestartRequired)
+
+	cmd.listenersLock.Lock()
+	require.Len(t, cmd.listeners, 1)
+	require.Equal(t, int64(1024), cmd.listeners[0].Config.MaxRequestSize)
+	cmd.listenersLock.Unlock()
+
+	cmd.ShutdownCh <- struct{}{}
+
+	wg.Wait()
+}
+
 func TestServer(t *testing.T) {
 	t.Parallel()
 
@@ -107,7 +107,7 @@ func PrintRawField(ui cli.Ui, data interface{}, field string) int {
 	}
 
 	format := Format(ui)
-	if format == "" || format == "table" || format == "raw" {
+	if format == "" || format == "table" || format == "raw" || format == "template" {
 		return PrintRaw(ui, fmt.Sprintf("%v", val))
 	}
 
@@ -39,6 +39,7 @@ import (
 	credOkta "github.com/hashicorp/vault/builtin/credential/okta"
 	credRadius "github.com/hashicorp/vault/builtin/credential/radius"
 	credUserpass "github.com/hashicorp/vault/builtin/credential/userpass"
+	credWebAuthn "github.com/hashicorp/vault/builtin/credential/webauthn"
 	logicalAws "github.com/hashicorp/vault/builtin/logical/aws"
 	logicalConsul "github.com/hashicorp/vault/builtin/logical/consul"
 	logicalNomad "github.com/hashicorp/vault/builtin/logical/nomad"
@@ -125,6 +126,7 @@ func newRegistry() *registry {
 			},
 			"radius":   {Factory: credRadius.Factory},
 			"userpass": {Factory: credUserpass.Factory},
+			"webauthn": {Factory: credWebAuthn.Factory},
 		},
 		databasePlugins: map[string]databasePlugin{
 			// These four plugins all use the same mysql implementation but with
@@ -125,6 +125,39 @@ func (j *JobManager) AddJob(job Job, queueID string) {
 	}
 }
 
+// AddJobs adds a batch of jobs to the given queue, creating the queue if it
+// doesn't exist. Unlike calling AddJob for each job, the lock is taken and
+// metrics are emitted once per batch.
+func (j *JobManager) AddJobs(jobs []Job, queueID string) {
+	if len(jobs) == 0 {
+		return
+	}
+
+	j.l.Lock()
+	if len(j.queues) == 0 {
+		defer func() {
+			// newWork must be buffered to avoid deadlocks if work is added
+			// before the job manager is started
+			j.newWork <- struct{}{}
+		}()
+	}
+	defer j.l.Unlock()
+
+	if _, ok := j.queues[queueID]; !ok {
+		j.addQueue(queueID)
+	}
+
+	for _, job := range jobs {
+		j.queues[queueID].PushBack(job)
+	}
+	j.totalJobs += len(jobs)
+
+	if j.metricSink != nil {
+		j.metricSink.AddSampleWithLabels([]string{j.name, "job_manager", "queue_length"}, float32(j.queues[queueID].Len()), []metrics.Label{{"queue_id", queueID}})
+		j.metricSink.AddSample([]string{j.name, "job_manager", "total_jobs"}, float32(j.totalJobs))
+	}
+}
+
 // GetCurrentJobCount returns the total number of pending jobs in the job manager
 func (j *JobManager) GetPendingJobCount() int {
 	j.l.RLock()
@@ -142,7 +175,13 @@ func (j *JobManager) GetPendingJobCount() int {
 func (j *JobManager) GetWorkerCounts() map[string]int {
 	j.l.RLock()
 	defer j.l.RUnlock()
-	return j.workerCount
+
+	out := make(map[string]int, len(j.workerCount))
+	for k, v := range j.workerCount {
+		out[k] = v
+	}
+
+	return out
 }
 
 // GetWorkQueueLengths() returns a map of queue ID to number of jobs in the queue
@@ -289,6 +289,61 @@ func TestJobManager_AddJob(t *testing.T) {
 	}
 }
 
+func TestJobManager_AddJobs(t *testing.T) {
+	j := NewJobManager("job-mgr-test", 3, newTestLogger("jobmanager-test"), nil)
+
+	expected := map[string]int{
+		"q1": 4,
+		"q2": 1,
+	}
+	for queueID, count := range expected {
+		jobs := make([]Job, 0, count)
+		for i := 0; i < count; i++ {
+			job := newDefaultTestJob(t, fmt.Sprintf("%s-job-%d", queueID, i))
+			jobs = append(jobs, &job)
+		}
+		j.AddJobs(jobs, queueID)
+	}
+
+	// an empty batch doesn't create a queue
+	j.AddJobs(nil, "q3")
+
+	if got := j.GetWorkQueueLengths(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v job count, got %v", expected, got)
+	}
+	if j.totalJobs != 5 {
+		t.Fatalf("expected 5 total jobs, got %d", j.totalJobs)
+	}
+
+	// jobs added in batches are all executed
+	var wg sync.WaitGroup
+	wg.Add(3)
+	jobs := make([]Job, 0, 3)
+	for i := 0; i < 3; i++ {
+		job := newTestJob(t, fmt.Sprintf("batch-job-%d", i), func(_ string) error {
+			wg.Done()
+			return nil
+		}, func(_ error) {})
+		jobs = append(jobs, &job)
+	}
+
+	started := NewJobManager("job-mgr-test", 3, newTestLogger("jobmanager-test"), nil)
+	started.Start()
+	defer started.Stop()
+	started.AddJobs(jobs, "q1")
+
+	doneCh := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(doneCh)
+	}()
+	select {
+	case <-doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for batched jobs to execute")
+	}
+}
+
 func TestJobManager_GetPendingJobCount(t *testing.T) {
 	numJobs := 15
 	j := NewJobManager("test-job-mgr", 3, newTestLogger("jobmanager-test"), nil)
@@ -0,0 +1,23 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package groupresolver defines the interface used by the identity store to
+// resolve the groups of an authenticated user from an external directory.
+package groupresolver
+
+import (
+	"context"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// Resolver resolves the names of the external groups that the user
+// represented by an alias belongs to. The returned names are matched against
+// the group aliases of external groups on the alias's auth mount.
+type Resolver interface {
+	ResolveGroups(ctx context.Context, alias *logical.Alias) ([]string, error)
+}
+
+// Factory creates a Resolver from its configuration.
+type Factory func(ctx context.Context, conf map[string]string, logger log.Logger) (Resolver, error)
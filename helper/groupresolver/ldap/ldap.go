@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package ldap implements a group resolver that searches an LDAP directory
+// for the groups of a user.
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/helper/groupresolver"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/ldaputil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+var _ groupresolver.Resolver = (*resolver)(nil)
+
+type resolver struct {
+	cfg    *ldaputil.ConfigEntry
+	logger log.Logger
+	ldap   ldaputil.LDAP
+}
+
+// Factory creates an LDAP group resolver. The configuration accepts the same
+// connection and group search parameters as the LDAP auth method. Since users
+// don't bind as part of group resolution, binddn and bindpass are required.
+func Factory(_ context.Context, conf map[string]string, logger log.Logger) (groupresolver.Resolver, error) {
+	raw := make(map[string]interface{}, len(conf))
+	for k, v := range conf {
+		raw[k] = v
+	}
+
+	cfg, err := ldaputil.NewConfigEntry(nil, &framework.FieldData{
+		Raw:    raw,
+		Schema: ldaputil.ConfigFields(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.BindDN == "" || cfg.BindPassword == "" {
+		return nil, errors.New("binddn and bindpass are required")
+	}
+
+	return &resolver{
+		cfg:    cfg,
+		logger: logger,
+		ldap:   ldaputil.NewLDAP(),
+	}, nil
+}
+
+// ResolveGroups searches for the user with the alias name as its username
+// and returns the names of the LDAP groups that the user belongs to.
+func (r *resolver) ResolveGroups(_ context.Context, alias *logical.Alias) ([]string, error) {
+	client := ldaputil.Client{
+		Logger: r.logger,
+		LDAP:   r.ldap,
+	}
+
+	conn, err := client.DialLDAP(r.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+	}
+	if conn == nil {
+		return nil, errors.New("invalid connection returned from LDAP dial")
+	}
+	defer conn.Close()
+
+	if err := conn.Bind(r.cfg.BindDN, r.cfg.BindPassword); err != nil {
+		return nil, fmt.Errorf("failed to bind with the BindDN user: %w", err)
+	}
+
+	userBindDN, err := client.GetUserBindDN(r.cfg, conn, alias.Name)
+	if err != nil {
+		return nil, err
+	}
+	userDN, err := client.GetUserDN(r.cfg, conn, userBindDN, alias.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetLdapGroups(r.cfg, conn, userDN, alias.Name)
+}
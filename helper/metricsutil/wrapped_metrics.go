@@ -40,6 +40,7 @@ type TelemetryConstConfig struct {
 	LeaseMetricsEpsilon         time.Duration
 	NumLeaseMetricsTimeBuckets  int
 	LeaseMetricsNameSpaceLabels bool
+	RouteMetricsMaxMounts       int
 }
 
 type Metrics interface {
@@ -4,6 +4,8 @@
 package proxyutil
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -13,8 +15,11 @@ import (
 	"github.com/hashicorp/go-secure-stdlib/parseutil"
 	sockaddr "github.com/hashicorp/go-sockaddr"
 	proxyproto "github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
 )
 
+type proxyInfoContextKey struct{}
+
 // ProxyProtoConfig contains configuration for the PROXY protocol
 type ProxyProtoConfig struct {
 	sync.RWMutex
@@ -81,3 +86,104 @@ func WrapInProxyProto(listener net.Listener, config *ProxyProtoConfig) (net.List
 
 	return newLn, nil
 }
+
+// ProxyInfo contains the PROXY protocol information received on a connection.
+type ProxyInfo struct {
+	// Version is the PROXY protocol version of the header, 1 or 2.
+	Version int
+
+	// ProxyAddr is the address of the upstream that sent the PROXY header,
+	// i.e. the load balancer or proxy in front of Vault.
+	ProxyAddr net.Addr
+
+	// Metadata holds the known TLVs of a version 2 header, keyed by name.
+	Metadata map[string]string
+}
+
+// ConnContext is meant to be used as the ConnContext of an http.Server. If
+// the connection was accepted by a PROXY protocol listener, it is stored in
+// the returned context so that the header's information can be retrieved
+// with ProxyInfoFromContext.
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	proxyConn, ok := conn.(*proxyproto.Conn)
+	if !ok {
+		return ctx
+	}
+
+	// The header is read lazily; ConnContext runs in the accept loop, so
+	// it must not block on it.
+	return context.WithValue(ctx, proxyInfoContextKey{}, proxyConn)
+}
+
+// ProxyInfoFromContext returns the PROXY protocol information of the
+// connection stored in the context by ConnContext, or nil if the connection
+// didn't carry a PROXY header.
+func ProxyInfoFromContext(ctx context.Context) *ProxyInfo {
+	proxyConn, ok := ctx.Value(proxyInfoContextKey{}).(*proxyproto.Conn)
+	if !ok {
+		return nil
+	}
+
+	header := proxyConn.ProxyHeader()
+	if header == nil || header.Command.IsLocal() {
+		return nil
+	}
+
+	info := &ProxyInfo{
+		Version:   int(header.Version),
+		ProxyAddr: proxyConn.Raw().RemoteAddr(),
+	}
+
+	tlvs, err := header.TLVs()
+	if err == nil && len(tlvs) > 0 {
+		info.Metadata = tlvMetadata(tlvs)
+	}
+
+	return info
+}
+
+// tlvMetadata extracts the TLVs Vault knows about into a map. Unknown and
+// binary TLVs are ignored.
+func tlvMetadata(tlvs []proxyproto.TLV) map[string]string {
+	metadata := make(map[string]string)
+	for _, tlv := range tlvs {
+		switch tlv.Type {
+		case proxyproto.PP2_TYPE_ALPN:
+			metadata["alpn"] = string(tlv.Value)
+		case proxyproto.PP2_TYPE_AUTHORITY:
+			metadata["authority"] = string(tlv.Value)
+		case proxyproto.PP2_TYPE_UNIQUE_ID:
+			metadata["unique_id"] = fmt.Sprintf("%x", tlv.Value)
+		case proxyproto.PP2_TYPE_NETNS:
+			metadata["netns"] = string(tlv.Value)
+		}
+	}
+
+	if ssl, ok := tlvparse.FindSSL(tlvs); ok && ssl.ClientSSL() {
+		if version, ok := ssl.SSLVersion(); ok {
+			metadata["ssl_version"] = version
+		}
+		if cn, ok := ssl.ClientCN(); ok {
+			metadata["ssl_client_cn"] = cn
+		}
+		metadata["ssl_client_verified"] = fmt.Sprintf("%t", ssl.Verified())
+	}
+	if vpce := tlvparse.FindAWSVPCEndpointID(tlvs); vpce != "" {
+		metadata["aws_vpce_id"] = vpce
+	}
+	if linkID, ok := tlvparse.FindAzurePrivateEndpointLinkID(tlvs); ok {
+		metadata["azure_private_endpoint_link_id"] = fmt.Sprintf("%d", linkID)
+	}
+	if pscID, ok := tlvparse.ExtractPSCConnectionID(tlvs); ok {
+		metadata["gcp_psc_connection_id"] = fmt.Sprintf("%d", pscID)
+	}
+
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
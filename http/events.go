@@ -7,13 +7,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/eventlogger"
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/hashicorp/vault/vault"
@@ -21,6 +24,8 @@ import (
 	"nhooyr.io/websocket"
 )
 
+const eventsSubscribePath = "sys/events/subscribe/"
+
 type eventSubscribeArgs struct {
 	ctx     context.Context
 	logger  hclog.Logger
@@ -29,6 +34,59 @@ type eventSubscribeArgs struct {
 	pattern string
 	conn    *websocket.Conn
 	json    bool
+	policy  *eventPolicyChecker
+}
+
+// eventPolicyChecker checks that the token of a subscription is allowed to
+// subscribe to the type of each event, so that a subscription to a pattern
+// only receives the events the token could have subscribed to individually.
+type eventPolicyChecker struct {
+	core  *vault.Core
+	token string
+
+	// results of the checks, keyed by event type
+	allowed map[string]bool
+}
+
+func newEventPolicyChecker(core *vault.Core, token string) *eventPolicyChecker {
+	return &eventPolicyChecker{
+		core:    core,
+		token:   token,
+		allowed: make(map[string]bool),
+	}
+}
+
+// allows returns whether the token can read the subscription path of the event
+// type. A nil checker allows every event.
+func (c *eventPolicyChecker) allows(ctx context.Context, eventType string) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+	if allowed, ok := c.allowed[eventType]; ok {
+		return allowed, nil
+	}
+
+	capabilities, err := c.core.Capabilities(ctx, c.token, eventsSubscribePath+eventType)
+	if err != nil {
+		return false, err
+	}
+	allowed := strutil.StrListContains(capabilities, vault.RootCapability) || strutil.StrListContains(capabilities, vault.ReadCapability)
+	c.allowed[eventType] = allowed
+	return allowed, nil
+}
+
+// allowedEvent returns whether the token of the subscription is allowed to
+// receive the event.
+func allowedEvent(args eventSubscribeArgs, message *eventlogger.Event) (bool, error) {
+	eventType := message.Payload.(*logical.EventReceived).EventType
+	allowed, err := args.policy.allows(args.ctx, eventType)
+	if err != nil {
+		return false, err
+	}
+	if !allowed {
+		args.logger.Trace("Dropping event the token is not allowed to receive", "event_type", eventType)
+	}
+	return allowed, nil
 }
 
 // handleEventsSubscribeWebsocket runs forever, returning a websocket error code and reason
@@ -49,6 +107,13 @@ func handleEventsSubscribeWebsocket(args eventSubscribeArgs) (websocket.StatusCo
 			logger.Info("Websocket context is done, closing the connection")
 			return websocket.StatusNormalClosure, "", nil
 		case message := <-ch:
+			allowed, err := allowedEvent(args, message)
+			if err != nil {
+				return 0, "", err
+			}
+			if !allowed {
+				continue
+			}
 			logger.Debug("Sending message to websocket", "message", message.Payload)
 			var messageBytes []byte
 			var messageType websocket.MessageType
@@ -76,6 +141,88 @@ func handleEventsSubscribeWebsocket(args eventSubscribeArgs) (websocket.StatusCo
 	}
 }
 
+// handleEventsSubscribeSSE streams the events of the subscription as
+// server-sent events, in the CloudEvents JSON format, until the client goes
+// away or there was an error.
+func handleEventsSubscribeSSE(w http.ResponseWriter, args eventSubscribeArgs) error {
+	logger := args.logger
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		// http.ResponseWriter is wrapped in wrapGenericHandler, so let's
+		// access the underlying functionality
+		nw, ok := w.(logical.WrappingResponseWriter)
+		if !ok {
+			respondError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported by the connection"))
+			return nil
+		}
+		flusher, ok = nw.Wrapped().(http.Flusher)
+		if !ok {
+			respondError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported by the connection"))
+			return nil
+		}
+	}
+
+	ch, cancel, err := args.events.Subscribe(args.ctx, args.ns, args.pattern)
+	if err != nil {
+		logger.Info("Error subscribing", "error", err)
+		respondError(w, http.StatusBadRequest, fmt.Errorf("error subscribing"))
+		return nil
+	}
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// not too aggressive, but keep the HTTP connection alive
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-args.ctx.Done():
+			logger.Info("SSE context is done, closing the connection")
+			return nil
+		case <-ticker.C:
+			// comment lines are ignored by clients
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return err
+			}
+			flusher.Flush()
+		case message := <-ch:
+			allowed, err := allowedEvent(args, message)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				continue
+			}
+			messageBytes, ok := message.Format("cloudevents-json")
+			if !ok {
+				logger.Warn("Could not get cloudevents JSON format")
+				return errors.New("could not get cloudevents JSON format")
+			}
+			eventReceived := message.Payload.(*logical.EventReceived)
+			logger.Debug("Sending server-sent event", "message", eventReceived)
+			_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", eventReceived.ID(), eventReceived.EventType, messageBytes)
+			if err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// wantsServerSentEvents returns whether the client asked for the events to be
+// streamed as server-sent events rather than over a websocket.
+func wantsServerSentEvents(r *http.Request) bool {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
 func handleEventsSubscribe(core *vault.Core, req *logical.Request) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		logger := core.Logger().Named("events-subscribe")
@@ -102,9 +249,9 @@ func handleEventsSubscribe(core *vault.Core, req *logical.Request) http.Handler
 			return
 		}
 
-		prefix := "/v1/sys/events/subscribe/"
+		prefix := "/v1/" + eventsSubscribePath
 		if ns.ID != namespace.RootNamespaceID {
-			prefix = fmt.Sprintf("/v1/%ssys/events/subscribe/", ns.Path)
+			prefix = fmt.Sprintf("/v1/%s%s", ns.Path, eventsSubscribePath)
 		}
 		pattern := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, prefix))
 		if pattern == "" {
@@ -123,6 +270,23 @@ func handleEventsSubscribe(core *vault.Core, req *logical.Request) http.Handler
 			}
 		}
 
+		args := eventSubscribeArgs{
+			ctx:     ctx,
+			logger:  logger,
+			events:  core.Events(),
+			ns:      ns,
+			pattern: pattern,
+			json:    json,
+			policy:  newEventPolicyChecker(core, req.ClientToken),
+		}
+
+		if wantsServerSentEvents(r) {
+			if err := handleEventsSubscribeSSE(w, args); err != nil {
+				logger.Debug("Error from SSE handler", "error", err)
+			}
+			return
+		}
+
 		conn, err := websocket.Accept(w, r, nil)
 		if err != nil {
 			logger.Info("Could not accept as websocket", "error", err)
@@ -143,7 +307,9 @@ func handleEventsSubscribe(core *vault.Core, req *logical.Request) http.Handler
 			}
 		}()
 
-		closeStatus, closeReason, err := handleEventsSubscribeWebsocket(eventSubscribeArgs{ctx, logger, core.Events(), ns, pattern, conn, json})
+		args.ctx = ctx
+		args.conn = conn
+		closeStatus, closeReason, err := handleEventsSubscribeWebsocket(args)
 		if err != nil {
 			closeStatus = websocket.CloseStatus(err)
 			if closeStatus == -1 {
@@ -4,6 +4,7 @@
 package http
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -197,3 +198,110 @@ func TestEventsSubscribeAuth(t *testing.T) {
 		t.Errorf("Expected 403 but got %+v", resp)
 	}
 }
+
+// TestEventsSubscribeSSE tests streaming events as server-sent events, and
+// that subscriptions only receive the events their token is allowed to
+// subscribe to.
+func TestEventsSubscribeSSE(t *testing.T) {
+	core := vault.TestCore(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+
+	// unseal the core
+	keys, root := vault.TestCoreInit(t, core)
+	for _, key := range keys {
+		_, err := core.Unseal(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := api.DefaultConfig()
+	config.Address = addr
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetToken(root)
+
+	err = client.Sys().PutPolicy("events", `
+path "sys/events/subscribe/*" {
+	capabilities = ["read"]
+}
+path "sys/events/subscribe/secret/*" {
+	capabilities = ["deny"]
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := client.Auth().Token().Create(&api.TokenCreateRequest{Policies: []string{"events"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := secret.Auth.ClientToken
+
+	stop := atomic.Bool{}
+	t.Cleanup(func() {
+		stop.Store(true)
+	})
+
+	// send some events, the token is not allowed to receive the first type
+	go func() {
+		for !stop.Load() {
+			for _, eventType := range []string{"secret/abc", "public/abc"} {
+				event, err := logical.NewEvent()
+				if err != nil {
+					core.Logger().Info("Error generating event, exiting sender", "error", err)
+					return
+				}
+				event.Note = eventType
+				err = core.Events().SendInternal(namespace.RootContext(context.Background()), namespace.RootNamespace, nil, logical.EventType(eventType), event)
+				if err != nil {
+					core.Logger().Info("Error sending event, exiting sender", "error", err)
+				}
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr+"/v1/sys/events/subscribe/*", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 but got %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("bad content type: %q", contentType)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for received := 0; received < 3; {
+		if !scanner.Scan() {
+			t.Fatalf("stream ended: %v", scanner.Err())
+		}
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			if eventType := strings.TrimPrefix(line, "event: "); eventType != "public/abc" {
+				t.Fatalf("received an event the token is not allowed to receive: %s", eventType)
+			}
+		case strings.HasPrefix(line, "data: "):
+			event := map[string]interface{}{}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				t.Fatal(err)
+			}
+			checkRequiredCloudEventsFields(t, event)
+			received++
+		}
+	}
+}
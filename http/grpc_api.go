@@ -0,0 +1,882 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/experiments"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/http/grpcapi"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/wrapping"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault"
+	"github.com/hashicorp/vault/vault/quotas"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// defaultGRPCListPageSize is the number of keys sent per message of a
+	// list stream when the client doesn't ask for a page size.
+	defaultGRPCListPageSize = 1000
+
+	// grpcSubscribeEventsMethod is the method of event subscriptions, which
+	// are not limited by the request duration
+	grpcSubscribeEventsMethod = "/grpcapi.Vault/SubscribeEvents"
+)
+
+// grpcAPIServer serves the gRPC API. Every method is mapped to the logical
+// request the equivalent HTTP API call would make, so that it goes through
+// the same token, ACL, quota and audit handling.
+type grpcAPIServer struct {
+	grpcapi.UnimplementedVaultServer
+
+	core *vault.Core
+}
+
+// wrapGRPCAPIHandler returns a handler serving gRPC requests with the gRPC
+// API, and every other request with the given handler. Listeners with TLS
+// disabled accept HTTP/2 without TLS so that gRPC clients can connect.
+func wrapGRPCAPIHandler(h http.Handler, core *vault.Core, props *vault.HandlerProperties) http.Handler {
+	// Like the HTTP API, messages are limited by the listener's
+	// max_request_size, unless it is negative
+	maxRequestSize := props.ListenerConfig.MaxRequestSize
+	if maxRequestSize == 0 {
+		maxRequestSize = DefaultMaxRequestSize
+	}
+	if maxRequestSize < 0 || maxRequestSize > math.MaxInt32 {
+		maxRequestSize = math.MaxInt32
+	}
+
+	interceptor := newGRPCAPIInterceptor(core, props)
+	server := grpc.NewServer(
+		grpc.MaxRecvMsgSize(int(maxRequestSize)),
+		grpc.UnaryInterceptor(interceptor.unary),
+		grpc.StreamInterceptor(interceptor.stream),
+	)
+	grpcapi.RegisterVaultServer(server, &grpcAPIServer{core: core})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			server.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+
+	if props.ListenerConfig.TLSDisable {
+		return h2c.NewHandler(handler, &http2.Server{})
+	}
+	return handler
+}
+
+// grpcAPIInterceptor applies the checks and the tracking that the generic
+// handlers of the HTTP API apply to every request, as gRPC requests are served
+// before them.
+type grpcAPIInterceptor struct {
+	core                  *vault.Core
+	listenerAddress       string
+	maxRequestDuration    time.Duration
+	disablePrintableCheck bool
+}
+
+func newGRPCAPIInterceptor(core *vault.Core, props *vault.HandlerProperties) *grpcAPIInterceptor {
+	i := &grpcAPIInterceptor{
+		core:                  core,
+		listenerAddress:       props.ListenerConfig.Address,
+		maxRequestDuration:    props.ListenerConfig.MaxRequestDuration,
+		disablePrintableCheck: props.DisablePrintableCheck,
+	}
+	if i.maxRequestDuration == 0 {
+		i.maxRequestDuration = vault.DefaultMaxRequestDuration
+	}
+	return i
+}
+
+func (i *grpcAPIInterceptor) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	if err := i.validate(req); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, i.maxRequestDuration)
+	defer cancel()
+
+	ctx, finalize, err := i.trackInFlight(ctx, req, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		code := grpcCodeToHTTPStatus(status.Code(err))
+		// Unary responses are sent once the call returns, so their headers
+		// are set according to the status of the call
+		if md := i.customHeaders(code); md.Len() > 0 {
+			if err := grpc.SetHeader(ctx, md); err != nil {
+				i.core.Logger().Warn("failed to set custom response headers", "method", info.FullMethod, "error", err)
+			}
+		}
+		finalize(code)
+	}()
+
+	return handler(ctx, req)
+}
+
+func (i *grpcAPIInterceptor) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	// Like the events endpoint of the HTTP API, event subscriptions are not
+	// limited by the request duration
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if info.FullMethod == grpcSubscribeEventsMethod {
+		ctx, cancel = context.WithCancel(ss.Context())
+	} else {
+		ctx, cancel = context.WithTimeout(ss.Context(), i.maxRequestDuration)
+	}
+	defer cancel()
+
+	ctx, finalize, err := i.trackInFlight(ctx, nil, info.FullMethod)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		finalize(grpcCodeToHTTPStatus(status.Code(err)))
+	}()
+
+	// Streams send their headers with their first message, before the status
+	// of the call is known, so they get the headers of successful responses
+	if md := i.customHeaders(http.StatusOK); md.Len() > 0 {
+		if err := ss.SetHeader(md); err != nil {
+			i.core.Logger().Warn("failed to set custom response headers", "method", info.FullMethod, "error", err)
+		}
+	}
+
+	return handler(srv, &grpcAPIServerStream{ServerStream: ss, ctx: ctx, interceptor: i})
+}
+
+// trackInFlight registers the call with the in-flight requests of core, like
+// the HTTP API registers every request. The returned function must be called
+// with the HTTP status equivalent to the status of the call once it is done.
+func (i *grpcAPIInterceptor) trackInFlight(ctx context.Context, req interface{}, method string) (context.Context, func(int), error) {
+	inFlightReqID, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, "failed to generate an identifier for the in-flight request")
+	}
+
+	// Calls are listed by the path of their request when it has one, like
+	// HTTP requests, and by their method otherwise
+	reqPath := method
+	if req, ok := req.(interface{ GetPath() string }); ok {
+		reqPath = req.GetPath()
+	}
+
+	i.core.StoreInFlightReqData(
+		inFlightReqID,
+		vault.InFlightReqData{
+			StartTime:        time.Now(),
+			ReqPath:          reqPath,
+			ClientRemoteAddr: grpcConnection(ctx).RemoteAddr,
+			Method:           method,
+		})
+
+	ctx = context.WithValue(ctx, logical.CtxKeyInFlightRequestID{}, inFlightReqID)
+	return ctx, func(code int) {
+		i.core.FinalizeInFlightReqData(inFlightReqID, code)
+	}, nil
+}
+
+// customHeaders returns the custom response headers of the listener for the
+// HTTP status, as gRPC metadata. They are looked up on every call so that
+// they are reloaded along with the configuration.
+func (i *grpcAPIInterceptor) customHeaders(code int) metadata.MD {
+	md := metadata.MD{}
+
+	listenerHeaders := i.core.GetListenerCustomResponseHeaders(i.listenerAddress)
+	if listenerHeaders == nil || listenerHeaders.StatusCodeHeaderMap == nil {
+		return md
+	}
+	for _, key := range []string{"default", fmt.Sprintf("%dxx", code/100), strconv.Itoa(code)} {
+		for _, header := range listenerHeaders.StatusCodeHeaderMap[key] {
+			md.Set(header.Name, header.Value)
+		}
+	}
+	return md
+}
+
+// validate checks the path of the request, like the HTTP API checks the path
+// of the URL.
+func (i *grpcAPIInterceptor) validate(req interface{}) error {
+	var path string
+	switch req := req.(type) {
+	case interface{ GetPath() string }:
+		path = req.GetPath()
+	case interface{ GetEventType() string }:
+		path = req.GetEventType()
+	default:
+		return nil
+	}
+
+	if !i.disablePrintableCheck && strings.IndexFunc(path, func(c rune) bool { return !unicode.IsPrint(c) }) != -1 {
+		return status.Error(codes.InvalidArgument, "path contains non-printable characters")
+	}
+
+	// The HTTP API only serves cleaned paths, so paths with relative or
+	// empty segments are rejected rather than resolved
+	segments := strings.Split(strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/"), "/")
+	for _, segment := range segments {
+		if segment == "." || segment == ".." || (segment == "" && len(segments) > 1) {
+			return status.Errorf(codes.InvalidArgument, "invalid path %q", path)
+		}
+	}
+
+	return nil
+}
+
+// grpcAPIServerStream is a server stream whose context is limited by the
+// request duration, and whose requests are validated.
+type grpcAPIServerStream struct {
+	grpc.ServerStream
+
+	ctx         context.Context
+	interceptor *grpcAPIInterceptor
+}
+
+func (s *grpcAPIServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *grpcAPIServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.interceptor.validate(m)
+}
+
+func (s *grpcAPIServer) Read(ctx context.Context, in *grpcapi.ReadRequest) (*grpcapi.Response, error) {
+	req, resp, err := s.request(ctx, logical.ReadOperation, in.Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, status.Error(codes.NotFound, "no value found")
+	}
+	return grpcResponse(req, resp)
+}
+
+func (s *grpcAPIServer) Write(ctx context.Context, in *grpcapi.WriteRequest) (*grpcapi.Response, error) {
+	req, resp, err := s.request(ctx, logical.UpdateOperation, in.Path, in.Data.AsMap())
+	if err != nil {
+		return nil, err
+	}
+	return grpcResponse(req, resp)
+}
+
+func (s *grpcAPIServer) Delete(ctx context.Context, in *grpcapi.DeleteRequest) (*grpcapi.Response, error) {
+	req, resp, err := s.request(ctx, logical.DeleteOperation, in.Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return grpcResponse(req, resp)
+}
+
+// List streams the keys of a list in pages. Backends return complete lists,
+// so the list is fetched at once like by the HTTP API and only then split into
+// pages, which bounds the size of the messages but not the memory used.
+func (s *grpcAPIServer) List(in *grpcapi.ListRequest, stream grpcapi.Vault_ListServer) error {
+	path := in.Path
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	_, resp, err := s.request(stream.Context(), logical.ListOperation, path, nil)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return status.Error(codes.NotFound, "no value found")
+	}
+
+	data, err := jsonData(resp.Data)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	keys, _ := data["keys"].([]interface{})
+	keyInfo, _ := data["key_info"].(map[string]interface{})
+
+	pageSize := int(in.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultGRPCListPageSize
+	}
+
+	for start := 0; start < len(keys); start += pageSize {
+		end := start + pageSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		page := &grpcapi.ListResponse{}
+		pageInfo := make(map[string]interface{})
+		for _, key := range keys[start:end] {
+			key, ok := key.(string)
+			if !ok {
+				continue
+			}
+			page.Keys = append(page.Keys, key)
+			if info, ok := keyInfo[key]; ok {
+				pageInfo[key] = info
+			}
+		}
+		if len(pageInfo) > 0 {
+			page.KeyInfo, err = structpb.NewStruct(pageInfo)
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+		}
+
+		if err := stream.Send(page); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *grpcAPIServer) LookupToken(ctx context.Context, in *grpcapi.LookupTokenRequest) (*grpcapi.Response, error) {
+	var path string
+	var data map[string]interface{}
+	switch {
+	case in.Accessor != "":
+		path, data = "auth/token/lookup-accessor", map[string]interface{}{"accessor": in.Accessor}
+	case in.Token != "":
+		path, data = "auth/token/lookup", map[string]interface{}{"token": in.Token}
+	default:
+		path = "auth/token/lookup-self"
+	}
+
+	req, resp, err := s.request(ctx, logical.UpdateOperation, path, data)
+	if err != nil {
+		return nil, err
+	}
+	return grpcResponse(req, resp)
+}
+
+func (s *grpcAPIServer) RenewToken(ctx context.Context, in *grpcapi.RenewTokenRequest) (*grpcapi.Response, error) {
+	data := map[string]interface{}{}
+	if in.Increment > 0 {
+		data["increment"] = in.Increment
+	}
+
+	var path string
+	switch {
+	case in.Accessor != "":
+		path, data["accessor"] = "auth/token/renew-accessor", in.Accessor
+	case in.Token != "":
+		path, data["token"] = "auth/token/renew", in.Token
+	default:
+		path = "auth/token/renew-self"
+	}
+
+	req, resp, err := s.request(ctx, logical.UpdateOperation, path, data)
+	if err != nil {
+		return nil, err
+	}
+	return grpcResponse(req, resp)
+}
+
+func (s *grpcAPIServer) Health(ctx context.Context, _ *grpcapi.HealthRequest) (*grpcapi.HealthResponse, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/v1/sys/health", nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	_, health, err := getSysHealth(s.core, r)
+	if err != nil {
+		s.core.Logger().Error("error checking health", "error", err)
+		return nil, status.Error(codes.Internal, "error checking health")
+	}
+
+	return &grpcapi.HealthResponse{
+		Initialized:                health.Initialized,
+		Sealed:                     health.Sealed,
+		Standby:                    health.Standby,
+		PerformanceStandby:         health.PerformanceStandby,
+		ReplicationPerformanceMode: health.ReplicationPerformanceMode,
+		ReplicationDrMode:          health.ReplicationDRMode,
+		ServerTimeUtc:              health.ServerTimeUTC,
+		Version:                    health.Version,
+		ClusterName:                health.ClusterName,
+		ClusterId:                  health.ClusterID,
+	}, nil
+}
+
+func (s *grpcAPIServer) SubscribeEvents(in *grpcapi.SubscribeEventsRequest, stream grpcapi.Vault_SubscribeEventsServer) error {
+	if !s.core.IsExperimentEnabled(experiments.VaultExperimentEventsAlpha1) {
+		return status.Error(codes.Unimplemented, "events are not enabled")
+	}
+
+	pattern := strings.TrimSpace(in.EventType)
+	if pattern == "" {
+		return status.Error(codes.InvalidArgument, "did not specify eventType to subscribe to")
+	}
+
+	ctx, req, err := s.logicalRequest(stream.Context(), logical.ReadOperation, eventsSubscribePath+pattern, nil)
+	if err != nil {
+		return err
+	}
+	if _, _, err := s.core.CheckToken(ctx, req, false); err != nil {
+		return grpcError(err)
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return status.Error(codes.Internal, "could not find namespace")
+	}
+
+	args := eventSubscribeArgs{
+		ctx:     ctx,
+		logger:  s.core.Logger().Named("events-subscribe"),
+		events:  s.core.Events(),
+		ns:      ns,
+		pattern: pattern,
+		policy:  newEventPolicyChecker(s.core, req.ClientToken),
+	}
+
+	ch, cancel, err := args.events.Subscribe(ctx, ns, pattern)
+	if err != nil {
+		args.logger.Info("Error subscribing", "error", err)
+		return status.Error(codes.InvalidArgument, "error subscribing")
+	}
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message := <-ch:
+			allowed, err := allowedEvent(args, message)
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			if !allowed {
+				continue
+			}
+			if err := stream.Send(message.Payload.(*logical.EventReceived)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// request handles a logical request built from the gRPC request, returning
+// the request and its response, or a gRPC status error.
+func (s *grpcAPIServer) request(ctx context.Context, op logical.Operation, path string, data map[string]interface{}) (*logical.Request, *logical.Response, error) {
+	ctx, req, err := s.logicalRequest(ctx, op, path, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.applyQuota(ctx, req); err != nil {
+		return nil, nil, err
+	}
+
+	// Like the HTTP API, performance standbys forward the requests they
+	// can't handle themselves
+	if s.core.PerfStandby() && perfStandbyAlwaysForwardPaths.HasPath(req.Path) {
+		resp, err := s.forwardRequest(ctx, req)
+		return req, resp, err
+	}
+
+	resp, err := s.core.HandleRequest(ctx, req)
+	if err != nil && (errwrap.Contains(err, consts.ErrStandby.Error()) || errwrap.Contains(err, logical.ErrPerfStandbyPleaseForward.Error())) {
+		resp, err := s.forwardRequest(ctx, req)
+		return req, resp, err
+	}
+	if err != nil || (resp != nil && resp.IsError()) {
+		return nil, nil, grpcError(s.responseError(req, resp, err))
+	}
+	return req, resp, nil
+}
+
+// forwardRequest forwards a request to the active node the same way the HTTP
+// API does, by sending the equivalent HTTP request over the cluster
+// connection. It returns the response of the active node, or a gRPC status
+// error.
+func (s *grpcAPIServer) forwardRequest(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+	if alwaysRedirectPaths.HasPath(req.Path) {
+		return nil, s.standbyError()
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	path := "/v1/" + ns.Path + req.Path
+
+	method := http.MethodGet
+	switch req.Operation {
+	case logical.UpdateOperation:
+		method = http.MethodPut
+	case logical.DeleteOperation:
+		method = http.MethodDelete
+	}
+
+	var body io.Reader = http.NoBody
+	if req.Data != nil {
+		raw, err := json.Marshal(req.Data)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	r, err := http.NewRequestWithContext(context.WithValue(ctx, "original_request_path", path), method, path, body)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if req.Operation == logical.ListOperation {
+		r.URL.RawQuery = "list=true"
+	}
+	if req.ClientToken != "" {
+		r.Header.Set(consts.AuthHeaderName, req.ClientToken)
+	}
+	if req.WrapInfo != nil {
+		r.Header.Set(WrapTTLHeaderName, strconv.FormatInt(int64(req.WrapInfo.TTL.Seconds()), 10))
+	}
+	if req.Connection != nil {
+		r.RemoteAddr = net.JoinHostPort(req.Connection.RemoteAddr, strconv.Itoa(req.Connection.RemotePort))
+		r.TLS = req.Connection.ConnState
+	}
+
+	code, _, respBody, err := s.core.ForwardRequest(r)
+	if err != nil {
+		if err == vault.ErrCannotForward {
+			s.core.Logger().Debug("cannot forward request (possibly disabled on active node), falling back")
+		} else {
+			s.core.Logger().Error("forward request error", "error", err)
+		}
+		return nil, s.standbyError()
+	}
+
+	if code >= http.StatusBadRequest {
+		var errResp struct {
+			Errors []string `json:"errors"`
+		}
+		if err := json.Unmarshal(respBody, &errResp); err != nil || len(errResp.Errors) == 0 {
+			return nil, status.Error(httpStatusToGRPCCode(code), http.StatusText(code))
+		}
+		return nil, status.Error(httpStatusToGRPCCode(code), strings.Join(errResp.Errors, "; "))
+	}
+	if code == http.StatusNoContent || len(respBody) == 0 {
+		return nil, nil
+	}
+
+	var httpResp logical.HTTPResponse
+	if err := jsonutil.DecodeJSON(respBody, &httpResp); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to decode forwarded response: %v", err)
+	}
+	resp := logical.HTTPResponseToLogicalResponse(&httpResp)
+	if info := httpResp.WrapInfo; info != nil {
+		creationTime, _ := time.Parse(time.RFC3339Nano, info.CreationTime)
+		resp.WrapInfo = &wrapping.ResponseWrapInfo{
+			Token:           info.Token,
+			Accessor:        info.Accessor,
+			TTL:             time.Duration(info.TTL) * time.Second,
+			CreationTime:    creationTime,
+			CreationPath:    info.CreationPath,
+			WrappedAccessor: info.WrappedAccessor,
+		}
+	}
+	return resp, nil
+}
+
+// logicalRequest builds the logical request of a gRPC request. The token,
+// namespace and wrap TTL of the request are taken from the same metadata
+// keys as the headers of the HTTP API.
+func (s *grpcAPIServer) logicalRequest(ctx context.Context, op logical.Operation, path string, data map[string]interface{}) (context.Context, *logical.Request, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	mdValue := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	path = strings.TrimPrefix(path, "/")
+	if nsHeader := namespace.Canonicalize(mdValue(consts.NamespaceHeaderName)); nsHeader != "" {
+		if s.core.NamespaceByPath(nsHeader).Path != nsHeader {
+			return nil, nil, status.Error(codes.NotFound, "namespace not found")
+		}
+		path = nsHeader + path
+	}
+	ns := s.core.NamespaceByPath(path)
+	ctx = namespace.ContextWithNamespace(ctx, ns)
+
+	requestID, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to generate identifier for the request: %v", err)
+	}
+
+	req := &logical.Request{
+		ID:         requestID,
+		Operation:  op,
+		Path:       ns.TrimmedPath(path),
+		Data:       data,
+		Connection: grpcConnection(ctx),
+	}
+
+	if token := mdValue(consts.AuthHeaderName); token != "" {
+		req.ClientToken = token
+		req.ClientTokenSource = logical.ClientTokenFromVaultHeader
+	}
+
+	if wrapTTL := mdValue(WrapTTLHeaderName); wrapTTL != "" {
+		dur, err := parseutil.ParseDurationSecond(wrapTTL)
+		if err != nil || int64(dur) < 0 {
+			return nil, nil, status.Error(codes.InvalidArgument, "invalid wrap ttl")
+		}
+		req.WrapInfo = &logical.RequestWrapInfo{
+			TTL: dur,
+		}
+	}
+
+	return ctx, req, nil
+}
+
+// applyQuota applies the rate limit quotas to the request, like the HTTP API
+// does for every request.
+func (s *grpcAPIServer) applyQuota(ctx context.Context, req *logical.Request) error {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	var clientAddress string
+	if req.Connection != nil {
+		clientAddress = req.Connection.RemoteAddr
+	}
+
+	quotaResp, err := s.core.ApplyRateLimitQuota(ctx, &quotas.Request{
+		Type:          quotas.TypeRateLimit,
+		Path:          req.Path,
+		MountPath:     strings.TrimPrefix(s.core.MatchingMount(ctx, req.Path), ns.Path),
+		NamespacePath: ns.Path,
+		ClientAddress: clientAddress,
+		Method:        quotas.MethodForOperation(req.Operation),
+		ClientToken:   req.ClientToken,
+	})
+	if err != nil {
+		s.core.Logger().Error("failed to apply quota", "path", req.Path, "error", err)
+		return status.Error(codes.Internal, err.Error())
+	}
+	if !quotaResp.Allowed {
+		return status.Errorf(codes.ResourceExhausted, "request path %q: %v", req.Path, quotas.ErrRateLimitQuotaExceeded)
+	}
+	return nil
+}
+
+// responseError returns the error of a failed request.
+func (s *grpcAPIServer) responseError(req *logical.Request, resp *logical.Response, err error) error {
+	code, err := logical.RespondErrorCommon(req, resp, err)
+	if err == nil {
+		return nil
+	}
+	return status.Error(httpStatusToGRPCCode(code), err.Error())
+}
+
+// standbyError returns the error of the requests a standby node can't forward
+// to the active node, which the client has to send to the active node.
+func (s *grpcAPIServer) standbyError() error {
+	_, leaderAddr, _, err := s.core.Leader()
+	if err != nil || leaderAddr == "" {
+		return status.Error(codes.Unavailable, "no active Vault instance found")
+	}
+	return status.Errorf(codes.Unavailable, "node is not active, send the request to the active node at %s", leaderAddr)
+}
+
+// grpcError returns the gRPC status error of an error returned by core.
+func grpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, logical.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, logical.ErrInvalidRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, consts.ErrSealed):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// grpcCodeToHTTPStatus returns the HTTP status equivalent to a gRPC code, the
+// inverse of httpStatusToGRPCCode.
+func grpcCodeToHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.Unimplemented:
+		return http.StatusMethodNotAllowed
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func httpStatusToGRPCCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusMethodNotAllowed:
+		return codes.Unimplemented
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// grpcConnection returns the connection information of a gRPC request.
+func grpcConnection(ctx context.Context) *logical.Connection {
+	connection := &logical.Connection{}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return connection
+	}
+
+	if host, port, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		connection.RemoteAddr = host
+		connection.RemotePort, _ = strconv.Atoi(port)
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		connection.ConnState = &tlsInfo.State
+	}
+	setProxyConnection(ctx, connection)
+
+	return connection
+}
+
+// grpcResponse converts a logical response to the response of the gRPC API.
+func grpcResponse(req *logical.Request, resp *logical.Response) (*grpcapi.Response, error) {
+	out := &grpcapi.Response{
+		RequestId: req.ID,
+	}
+	if resp == nil {
+		return out, nil
+	}
+
+	// Like the HTTP API, only return the wrapping information of wrapped
+	// responses
+	if resp.WrapInfo != nil && resp.WrapInfo.Token != "" {
+		out.WrapInfo = &grpcapi.WrapInfo{
+			Token:           resp.WrapInfo.Token,
+			Accessor:        resp.WrapInfo.Accessor,
+			Ttl:             int64(resp.WrapInfo.TTL.Seconds()),
+			CreationTime:    resp.WrapInfo.CreationTime.Unix(),
+			CreationPath:    resp.WrapInfo.CreationPath,
+			WrappedAccessor: resp.WrapInfo.WrappedAccessor,
+		}
+		return out, nil
+	}
+
+	httpResp := logical.LogicalResponseToHTTPResponse(resp)
+	out.LeaseId = httpResp.LeaseID
+	out.Renewable = httpResp.Renewable
+	out.LeaseDuration = int64(httpResp.LeaseDuration)
+	out.Warnings = httpResp.Warnings
+	out.MountType = req.MountType
+
+	if auth := httpResp.Auth; auth != nil {
+		out.Auth = &grpcapi.Auth{
+			ClientToken:      auth.ClientToken,
+			Accessor:         auth.Accessor,
+			Policies:         auth.Policies,
+			TokenPolicies:    auth.TokenPolicies,
+			IdentityPolicies: auth.IdentityPolicies,
+			Metadata:         auth.Metadata,
+			LeaseDuration:    int64(auth.LeaseDuration),
+			Renewable:        auth.Renewable,
+			EntityId:         auth.EntityID,
+			TokenType:        auth.TokenType,
+			Orphan:           auth.Orphan,
+		}
+	}
+
+	if len(httpResp.Data) > 0 {
+		data, err := jsonData(httpResp.Data)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		out.Data, err = structpb.NewStruct(data)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return out, nil
+}
+
+// jsonData returns the data as the HTTP API would encode it, so that it
+// only contains values that can be converted to protobuf values.
+func jsonData(data map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response data: %w", err)
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return out, nil
+}
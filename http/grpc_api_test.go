@@ -0,0 +1,333 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/http/grpcapi"
+	"github.com/hashicorp/vault/internalshared/configutil"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestGRPCAPI(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, addr := TestListener(t)
+	server := &http.Server{
+		Handler: Handler.Handler(&vault.HandlerProperties{
+			Core: core,
+			ListenerConfig: &configutil.Listener{
+				Address:    ln.Addr().String(),
+				TLSDisable: true,
+				GRPCAPI:    &configutil.GRPCAPI{Enable: true},
+			},
+		}),
+	}
+	go server.Serve(ln)
+	defer server.Close()
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpcapi.NewVaultClient(conn)
+
+	expectCode := func(t *testing.T, err error, code codes.Code) {
+		t.Helper()
+		if status.Code(err) != code {
+			t.Fatalf("expected code %s, got: %v", code, err)
+		}
+	}
+
+	// Health doesn't require a token
+	health, err := client.Health(context.Background(), &grpcapi.HealthRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !health.Initialized || health.Sealed || health.Standby {
+		t.Fatalf("bad: %#v", health)
+	}
+
+	_, err = client.Read(context.Background(), &grpcapi.ReadRequest{Path: "secret/foo"})
+	expectCode(t, err, codes.PermissionDenied)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-vault-token", token)
+
+	for _, path := range []string{"secret/foo", "secret/foo1", "secret/foo2", "secret/foo3", "secret/foo4"} {
+		data, err := structpb.NewStruct(map[string]interface{}{"bar": "baz"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Write(ctx, &grpcapi.WriteRequest{Path: path, Data: data}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := client.Read(ctx, &grpcapi.ReadRequest{Path: "secret/foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.RequestId == "" || resp.Data.AsMap()["bar"] != "baz" {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	stream, err := client.List(ctx, &grpcapi.ListRequest{Path: "secret", PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pages, keys int
+	for {
+		page, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		keys += len(page.Keys)
+	}
+	if pages != 3 || keys != 5 {
+		t.Fatalf("bad: %d pages, %d keys", pages, keys)
+	}
+
+	resp, err = client.LookupToken(ctx, &grpcapi.LookupTokenRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.AsMap()["id"] != token {
+		t.Fatalf("bad: %#v", resp.Data.AsMap())
+	}
+
+	if _, err := client.Delete(ctx, &grpcapi.DeleteRequest{Path: "secret/foo"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Read(ctx, &grpcapi.ReadRequest{Path: "secret/foo"})
+	expectCode(t, err, codes.NotFound)
+
+	// The HTTP API is still served on the listener
+	httpResp, err := http.Get(addr + "/v1/sys/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		t.Fatalf("bad: %d", httpResp.StatusCode)
+	}
+}
+
+func TestGRPCAPI_Validation(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, _ := TestListener(t)
+	server := &http.Server{
+		Handler: Handler.Handler(&vault.HandlerProperties{
+			Core: core,
+			ListenerConfig: &configutil.Listener{
+				Address:    ln.Addr().String(),
+				TLSDisable: true,
+				GRPCAPI:    &configutil.GRPCAPI{Enable: true},
+			},
+		}),
+	}
+	go server.Serve(ln)
+	defer server.Close()
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpcapi.NewVaultClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-vault-token", token)
+	for _, path := range []string{"secret/foo\x00", "secret/../sys/mounts", "secret/./foo", "secret//foo"} {
+		_, err := client.Read(ctx, &grpcapi.ReadRequest{Path: path})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected invalid argument for path %q, got: %v", path, err)
+		}
+
+		stream, err := client.List(ctx, &grpcapi.ListRequest{Path: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected invalid argument for path %q, got: %v", path, err)
+		}
+	}
+}
+
+func TestGRPCAPIInterceptor_MaxRequestDuration(t *testing.T) {
+	core, _, _ := vault.TestCoreUnsealed(t)
+	interceptor := newGRPCAPIInterceptor(core, &vault.HandlerProperties{
+		ListenerConfig: &configutil.Listener{MaxRequestDuration: time.Minute},
+	})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpcapi.Vault/Read"}
+	_, err := interceptor.unary(context.Background(), &grpcapi.ReadRequest{Path: "secret/foo"}, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > time.Minute {
+			t.Fatalf("expected a deadline within the max request duration, got %v", deadline)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGRPCAPIInterceptor_InFlightRequests(t *testing.T) {
+	core, _, _ := vault.TestCoreUnsealed(t)
+	interceptor := newGRPCAPIInterceptor(core, &vault.HandlerProperties{
+		ListenerConfig: &configutil.Listener{},
+	})
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpcapi.Vault/Read"}
+	_, err := interceptor.unary(context.Background(), &grpcapi.ReadRequest{Path: "secret/foo"}, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		reqID, _ := ctx.Value(logical.CtxKeyInFlightRequestID{}).(string)
+		data, ok := core.LoadInFlightReqData()[reqID]
+		if !ok || data.ReqPath != "secret/foo" || data.Method != info.FullMethod {
+			t.Fatalf("expected the call to be in flight, got: %#v", core.LoadInFlightReqData())
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inFlight := core.LoadInFlightReqData(); len(inFlight) != 0 {
+		t.Fatalf("expected no call in flight, got: %#v", inFlight)
+	}
+}
+
+func TestGRPCAPI_ListenerLimits(t *testing.T) {
+	core, _, token := vault.TestCoreWithCustomResponseHeaderAndUI(t, map[string]map[string]string{
+		"default": {"X-Custom-Default": "default"},
+		"2xx":     {"X-Custom-2xx": "2xx"},
+		"4xx":     {"X-Custom-4xx": "4xx"},
+	}, false)
+	ln, _ := TestListener(t)
+	server := &http.Server{
+		Handler: Handler.Handler(&vault.HandlerProperties{
+			Core: core,
+			ListenerConfig: &configutil.Listener{
+				Address:        "127.0.0.1",
+				TLSDisable:     true,
+				MaxRequestSize: 1024,
+				GRPCAPI:        &configutil.GRPCAPI{Enable: true},
+			},
+		}),
+	}
+	go server.Serve(ln)
+	defer server.Close()
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpcapi.NewVaultClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-vault-token", token)
+
+	// Messages are limited by the listener's max_request_size
+	data, err := structpb.NewStruct(map[string]interface{}{"bar": strings.Repeat("a", 2048)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Write(ctx, &grpcapi.WriteRequest{Path: "secret/foo", Data: data})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected resource exhausted, got: %v", err)
+	}
+
+	// Custom response headers are set according to the status of the call
+	var header metadata.MD
+	if _, err := client.Health(ctx, &grpcapi.HealthRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(header.Get("x-custom-default")) != "[default]" || fmt.Sprint(header.Get("x-custom-2xx")) != "[2xx]" || len(header.Get("x-custom-4xx")) != 0 {
+		t.Fatalf("bad headers: %v", header)
+	}
+
+	header = nil
+	_, err = client.Read(metadata.AppendToOutgoingContext(context.Background(), "x-vault-token", "invalid"), &grpcapi.ReadRequest{Path: "secret/foo"}, grpc.Header(&header))
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied, got: %v", err)
+	}
+	if fmt.Sprint(header.Get("x-custom-default")) != "[default]" || len(header.Get("x-custom-2xx")) != 0 || fmt.Sprint(header.Get("x-custom-4xx")) != "[4xx]" {
+		t.Fatalf("bad headers: %v", header)
+	}
+}
+
+func TestGRPCAPI_Forwarding(t *testing.T) {
+	cluster := vault.NewTestCluster(t, nil, &vault.TestClusterOptions{
+		HandlerFunc: Handler,
+		DefaultHandlerProperties: vault.HandlerProperties{
+			ListenerConfig: &configutil.Listener{
+				GRPCAPI: &configutil.GRPCAPI{Enable: true},
+			},
+		},
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+	vault.TestWaitActive(t, cluster.Cores[0].Core)
+
+	// Requests sent to a standby are forwarded to the active node
+	standby := cluster.Cores[1]
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", standby.Listeners[0].Address.Port),
+		grpc.WithTransportCredentials(credentials.NewTLS(standby.TLSConfig())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := grpcapi.NewVaultClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-vault-token", cluster.RootToken)
+	data, err := structpb.NewStruct(map[string]interface{}{"bar": "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Write(ctx, &grpcapi.WriteRequest{Path: "secret/foo", Data: data}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Read(ctx, &grpcapi.ReadRequest{Path: "secret/foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.AsMap()["bar"] != "baz" {
+		t.Fatalf("bad: %#v", resp.Data.AsMap())
+	}
+
+	stream, err := client.List(ctx, &grpcapi.ListRequest{Path: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Keys) != 1 || page.Keys[0] != "foo" {
+		t.Fatalf("bad: %v", page.Keys)
+	}
+
+	// Errors of the active node are returned
+	_, err = client.Read(metadata.AppendToOutgoingContext(context.Background(), "x-vault-token", "invalid"), &grpcapi.ReadRequest{Path: "secret/foo"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied, got: %v", err)
+	}
+}
@@ -0,0 +1,425 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package grpcapi
+
+import (
+	context "context"
+	logical "github.com/hashicorp/vault/sdk/logical"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// VaultClient is the client API for Vault service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type VaultClient interface {
+	// Read reads a path, like a GET request to the HTTP API.
+	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*Response, error)
+	// Write writes to a path, like a POST request to the HTTP API.
+	Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*Response, error)
+	// Delete deletes a path, like a DELETE request to the HTTP API.
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Response, error)
+	// List lists a path, streaming the keys of the complete list back in pages.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Vault_ListClient, error)
+	// LookupToken looks up a token using the token store.
+	LookupToken(ctx context.Context, in *LookupTokenRequest, opts ...grpc.CallOption) (*Response, error)
+	// RenewToken renews a token using the token store.
+	RenewToken(ctx context.Context, in *RenewTokenRequest, opts ...grpc.CallOption) (*Response, error)
+	// Health returns the health of the node. It doesn't require a token.
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+	// SubscribeEvents streams the events of a type.
+	SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Vault_SubscribeEventsClient, error)
+}
+
+type vaultClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVaultClient(cc grpc.ClientConnInterface) VaultClient {
+	return &vaultClient{cc}
+}
+
+func (c *vaultClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/grpcapi.Vault/Read", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/grpcapi.Vault/Write", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/grpcapi.Vault/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Vault_ListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Vault_ServiceDesc.Streams[0], "/grpcapi.Vault/List", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vaultListClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Vault_ListClient interface {
+	Recv() (*ListResponse, error)
+	grpc.ClientStream
+}
+
+type vaultListClient struct {
+	grpc.ClientStream
+}
+
+func (x *vaultListClient) Recv() (*ListResponse, error) {
+	m := new(ListResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *vaultClient) LookupToken(ctx context.Context, in *LookupTokenRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/grpcapi.Vault/LookupToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) RenewToken(ctx context.Context, in *RenewTokenRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/grpcapi.Vault/RenewToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, "/grpcapi.Vault/Health", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vaultClient) SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Vault_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Vault_ServiceDesc.Streams[1], "/grpcapi.Vault/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vaultSubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Vault_SubscribeEventsClient interface {
+	Recv() (*logical.EventReceived, error)
+	grpc.ClientStream
+}
+
+type vaultSubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *vaultSubscribeEventsClient) Recv() (*logical.EventReceived, error) {
+	m := new(logical.EventReceived)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// VaultServer is the server API for Vault service.
+// All implementations must embed UnimplementedVaultServer
+// for forward compatibility
+type VaultServer interface {
+	// Read reads a path, like a GET request to the HTTP API.
+	Read(context.Context, *ReadRequest) (*Response, error)
+	// Write writes to a path, like a POST request to the HTTP API.
+	Write(context.Context, *WriteRequest) (*Response, error)
+	// Delete deletes a path, like a DELETE request to the HTTP API.
+	Delete(context.Context, *DeleteRequest) (*Response, error)
+	// List lists a path, streaming the keys of the complete list back in pages.
+	List(*ListRequest, Vault_ListServer) error
+	// LookupToken looks up a token using the token store.
+	LookupToken(context.Context, *LookupTokenRequest) (*Response, error)
+	// RenewToken renews a token using the token store.
+	RenewToken(context.Context, *RenewTokenRequest) (*Response, error)
+	// Health returns the health of the node. It doesn't require a token.
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	// SubscribeEvents streams the events of a type.
+	SubscribeEvents(*SubscribeEventsRequest, Vault_SubscribeEventsServer) error
+	mustEmbedUnimplementedVaultServer()
+}
+
+// UnimplementedVaultServer must be embedded to have forward compatible implementations.
+type UnimplementedVaultServer struct {
+}
+
+func (UnimplementedVaultServer) Read(context.Context, *ReadRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
+}
+func (UnimplementedVaultServer) Write(context.Context, *WriteRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Write not implemented")
+}
+func (UnimplementedVaultServer) Delete(context.Context, *DeleteRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedVaultServer) List(*ListRequest, Vault_ListServer) error {
+	return status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedVaultServer) LookupToken(context.Context, *LookupTokenRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupToken not implemented")
+}
+func (UnimplementedVaultServer) RenewToken(context.Context, *RenewTokenRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewToken not implemented")
+}
+func (UnimplementedVaultServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedVaultServer) SubscribeEvents(*SubscribeEventsRequest, Vault_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+func (UnimplementedVaultServer) mustEmbedUnimplementedVaultServer() {}
+
+// UnsafeVaultServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VaultServer will
+// result in compilation errors.
+type UnsafeVaultServer interface {
+	mustEmbedUnimplementedVaultServer()
+}
+
+func RegisterVaultServer(s grpc.ServiceRegistrar, srv VaultServer) {
+	s.RegisterService(&Vault_ServiceDesc, srv)
+}
+
+func _Vault_Read_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).Read(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcapi.Vault/Read",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).Read(ctx, req.(*ReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).Write(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcapi.Vault/Write",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).Write(ctx, req.(*WriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcapi.Vault/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_List_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VaultServer).List(m, &vaultListServer{stream})
+}
+
+type Vault_ListServer interface {
+	Send(*ListResponse) error
+	grpc.ServerStream
+}
+
+type vaultListServer struct {
+	grpc.ServerStream
+}
+
+func (x *vaultListServer) Send(m *ListResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Vault_LookupToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).LookupToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcapi.Vault/LookupToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).LookupToken(ctx, req.(*LookupTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_RenewToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).RenewToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcapi.Vault/RenewToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).RenewToken(ctx, req.(*RenewTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VaultServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpcapi.Vault/Health",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VaultServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vault_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VaultServer).SubscribeEvents(m, &vaultSubscribeEventsServer{stream})
+}
+
+type Vault_SubscribeEventsServer interface {
+	Send(*logical.EventReceived) error
+	grpc.ServerStream
+}
+
+type vaultSubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *vaultSubscribeEventsServer) Send(m *logical.EventReceived) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Vault_ServiceDesc is the grpc.ServiceDesc for Vault service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Vault_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "grpcapi.Vault",
+	HandlerType: (*VaultServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Read",
+			Handler:    _Vault_Read_Handler,
+		},
+		{
+			MethodName: "Write",
+			Handler:    _Vault_Write_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Vault_Delete_Handler,
+		},
+		{
+			MethodName: "LookupToken",
+			Handler:    _Vault_LookupToken_Handler,
+		},
+		{
+			MethodName: "RenewToken",
+			Handler:    _Vault_RenewToken_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _Vault_Health_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "List",
+			Handler:       _Vault_List_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _Vault_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "http/grpcapi/grpc_api.proto",
+}
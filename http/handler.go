@@ -164,6 +164,7 @@ func handler(props *vault.HandlerProperties) http.Handler {
 		mux.Handle("/v1/sys/unseal", handleSysUnseal(core))
 		mux.Handle("/v1/sys/leader", handleSysLeader(core))
 		mux.Handle("/v1/sys/health", handleSysHealth(core))
+		mux.Handle("/v1/sys/health/detailed", handleSysHealthDetailed(core))
 		mux.Handle("/v1/sys/monitor", handleLogicalNoForward(core))
 		mux.Handle("/v1/sys/generate-root/attempt", handleRequestForwarding(core,
 			handleAuditNonLogical(core, handleSysGenerateRootAttempt(core, vault.GenerateStandardRootTokenStrategy))))
@@ -202,6 +203,7 @@ func handler(props *vault.HandlerProperties) http.Handler {
 		} else {
 			mux.Handle("/v1/sys/metrics", handleLogicalNoForward(core))
 		}
+		mux.Handle("/v1/sys/metrics/routes", handleLogicalNoForward(core))
 
 		if props.ListenerConfig != nil && props.ListenerConfig.Profiling.UnauthenticatedPProfAccess {
 			for _, name := range []string{"goroutine", "threadcreate", "heap", "allocs", "block", "mutex"} {
@@ -237,6 +239,10 @@ func handler(props *vault.HandlerProperties) http.Handler {
 		printablePathCheckHandler = cleanhttp.PrintablePathCheckHandler(genericWrappedHandler, nil)
 	}
 
+	if props.ListenerConfig != nil && props.ListenerConfig.GRPCAPI != nil && props.ListenerConfig.GRPCAPI.Enable {
+		return wrapGRPCAPIHandler(printablePathCheckHandler, core, props)
+	}
+
 	return printablePathCheckHandler
 }
 
@@ -275,7 +281,7 @@ func handleAuditNonLogical(core *vault.Core, h http.Handler) http.Handler {
 		origBody := new(bytes.Buffer)
 		reader := ioutil.NopCloser(io.TeeReader(r.Body, origBody))
 		r.Body = reader
-		req, _, status, err := buildLogicalRequestNoAuth(core.PerfStandby(), w, r)
+		req, _, status, err := buildLogicalRequestNoAuth(core.PerfStandby(), false, w, r)
 		if err != nil || status != 0 {
 			respondError(w, status, err)
 			return
@@ -379,6 +385,12 @@ func wrapGenericHandler(core *vault.Core, h http.Handler, props *vault.HandlerPr
 			nw.Header().Set("X-Vault-Hostname", hostname)
 		}
 
+		// Setting the namespace in the header to be included in the error message
+		ns := r.Header.Get(consts.NamespaceHeaderName)
+		if ns != "" {
+			nw.Header().Set(consts.NamespaceHeaderName, ns)
+		}
+
 		switch {
 		case strings.HasPrefix(r.URL.Path, "/v1/"):
 			newR, status := adjustRequest(core, r)
@@ -433,12 +445,6 @@ func wrapGenericHandler(core *vault.Core, h http.Handler, props *vault.HandlerPr
 			core.FinalizeInFlightReqData(inFlightReqID, nw.StatusCode)
 		}()
 
-		// Setting the namespace in the header to be included in the error message
-		ns := r.Header.Get(consts.NamespaceHeaderName)
-		if ns != "" {
-			nw.Header().Set(consts.NamespaceHeaderName, ns)
-		}
-
 		h.ServeHTTP(nw, r)
 
 		cancelFunc()
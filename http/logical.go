@@ -5,6 +5,7 @@ package http
 
 import (
 	"bufio"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -19,6 +20,7 @@ import (
 	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/helper/experiments"
 	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/helper/proxyutil"
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/hashicorp/vault/vault"
@@ -45,7 +47,14 @@ func (b *bufferedReader) Close() error {
 
 const MergePatchContentTypeHeader = "application/merge-patch+json"
 
-func buildLogicalRequestNoAuth(perfStandby bool, w http.ResponseWriter, r *http.Request) (*logical.Request, io.ReadCloser, int, error) {
+// SCIMContentTypeHeader is the content type used by SCIM clients, which send
+// PATCH requests using the SCIM patch format rather than a JSON merge patch.
+const SCIMContentTypeHeader = "application/scim+json"
+
+// buildLogicalRequestNoAuth builds the logical request of an HTTP request.
+// The body of requests to streaming paths isn't parsed; the request is given
+// the HTTP request and response writer instead.
+func buildLogicalRequestNoAuth(perfStandby, streaming bool, w http.ResponseWriter, r *http.Request) (*logical.Request, io.ReadCloser, int, error) {
 	ns, err := namespace.FromContext(r.Context())
 	if err != nil {
 		return nil, nil, http.StatusBadRequest, nil
@@ -110,7 +119,7 @@ func buildLogicalRequestNoAuth(perfStandby bool, w http.ResponseWriter, r *http.
 		// is der encoded) we don't want to parse it. Instead, we will simply
 		// add the HTTP request to the logical request object for later consumption.
 		contentType := r.Header.Get("Content-Type")
-		if path == "sys/storage/raft/snapshot" || path == "sys/storage/raft/snapshot-force" || isOcspRequest(contentType) {
+		if path == "sys/storage/raft/snapshot" || path == "sys/storage/raft/snapshot-force" || isOcspRequest(contentType) || streaming {
 			passHTTPReq = true
 			origBody = r.Body
 		} else {
@@ -159,7 +168,9 @@ func buildLogicalRequestNoAuth(perfStandby bool, w http.ResponseWriter, r *http.
 			return nil, nil, status, err
 		}
 
-		if contentType != MergePatchContentTypeHeader {
+		// SCIM patch requests are interpreted by the SCIM endpoints themselves
+		scimPatch := contentType == SCIMContentTypeHeader && isSCIMPath(path)
+		if contentType != MergePatchContentTypeHeader && !scimPatch {
 			return nil, nil, http.StatusUnsupportedMediaType, fmt.Errorf("PATCH requires Content-Type of %s, provided %s", MergePatchContentTypeHeader, contentType)
 		}
 
@@ -191,6 +202,10 @@ func buildLogicalRequestNoAuth(perfStandby bool, w http.ResponseWriter, r *http.
 		return nil, nil, http.StatusMethodNotAllowed, nil
 	}
 
+	if streaming {
+		responseWriter = w
+	}
+
 	requestId, err := uuid.GenerateUUID()
 	if err != nil {
 		return nil, nil, http.StatusInternalServerError, fmt.Errorf("failed to generate identifier for the request: %w", err)
@@ -215,6 +230,12 @@ func buildLogicalRequestNoAuth(perfStandby bool, w http.ResponseWriter, r *http.
 	return req, origBody, 0, nil
 }
 
+// isSCIMPath returns true if the path is served by the identity store's SCIM
+// endpoints.
+func isSCIMPath(path string) bool {
+	return strings.HasPrefix(path, "identity/scim/v2/")
+}
+
 func isOcspRequest(contentType string) bool {
 	contentType, _, err := mime.ParseMediaType(contentType)
 	if err != nil {
@@ -264,7 +285,12 @@ func buildLogicalPath(r *http.Request) (string, int, error) {
 }
 
 func buildLogicalRequest(core *vault.Core, w http.ResponseWriter, r *http.Request) (*logical.Request, io.ReadCloser, int, error) {
-	req, origBody, status, err := buildLogicalRequestNoAuth(core.PerfStandby(), w, r)
+	path, status, err := buildLogicalPath(r)
+	if err != nil || status != 0 {
+		return nil, nil, status, err
+	}
+
+	req, origBody, status, err := buildLogicalRequestNoAuth(core.PerfStandby(), core.StreamingPath(r.Context(), path), w, r)
 	if err != nil || status != 0 {
 		return nil, nil, status, err
 	}
@@ -316,7 +342,7 @@ func handleLogicalNoForward(core *vault.Core) http.Handler {
 
 func handleLogicalRecovery(raw *vault.RawBackend, token *atomic.String) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		req, _, statusCode, err := buildLogicalRequestNoAuth(false, w, r)
+		req, _, statusCode, err := buildLogicalRequestNoAuth(false, false, w, r)
 		if err != nil || statusCode != 0 {
 			respondError(w, statusCode, err)
 			return
@@ -584,5 +610,22 @@ func getConnection(r *http.Request) (connection *logical.Connection) {
 		RemotePort: remotePort,
 		ConnState:  r.TLS,
 	}
+
+	setProxyConnection(r.Context(), connection)
 	return
 }
+
+// setProxyConnection adds the upstream proxy of the connection, if it was
+// accepted with the PROXY protocol, to the connection information
+func setProxyConnection(ctx context.Context, connection *logical.Connection) {
+	info := proxyutil.ProxyInfoFromContext(ctx)
+	if info == nil || info.ProxyAddr == nil {
+		return
+	}
+
+	if host, port, err := net.SplitHostPort(info.ProxyAddr.String()); err == nil {
+		connection.ProxyAddr = host
+		connection.ProxyPort, _ = strconv.Atoi(port)
+	}
+	connection.ProxyMetadata = info.Metadata
+}
@@ -240,3 +240,65 @@ type HealthResponse struct {
 	LastWAL                    uint64                 `json:"last_wal,omitempty"`
 	License                    *HealthResponseLicense `json:"license,omitempty"`
 }
+
+func handleSysHealthDetailed(core *vault.Core) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET", "HEAD":
+		default:
+			respondError(w, http.StatusMethodNotAllowed, nil)
+			return
+		}
+
+		code, body, err := getSysHealthDetailed(core, r)
+		if err != nil {
+			respondError(w, code, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		if r.Method == "HEAD" {
+			return
+		}
+
+		// Generate the response
+		enc := json.NewEncoder(w)
+		enc.Encode(body)
+	})
+}
+
+func getSysHealthDetailed(core *vault.Core, r *http.Request) (int, *vault.DetailedHealth, error) {
+	okCode := http.StatusOK
+	if code, found, ok := fetchStatusCode(r, "okcode"); !ok {
+		return http.StatusBadRequest, nil, fmt.Errorf("bad value for okcode parameter")
+	} else if found {
+		okCode = code
+	}
+
+	degradedCode := http.StatusOK
+	if code, found, ok := fetchStatusCode(r, "degradedcode"); !ok {
+		return http.StatusBadRequest, nil, fmt.Errorf("bad value for degradedcode parameter")
+	} else if found {
+		degradedCode = code
+	}
+
+	failedCode := http.StatusServiceUnavailable
+	if code, found, ok := fetchStatusCode(r, "failedcode"); !ok {
+		return http.StatusBadRequest, nil, fmt.Errorf("bad value for failedcode parameter")
+	} else if found {
+		failedCode = code
+	}
+
+	body := core.DetailedHealth(r.Context())
+
+	code := okCode
+	switch body.Status {
+	case vault.HealthStatusDegraded:
+		code = degradedCode
+	case vault.HealthStatusFailed:
+		code = failedCode
+	}
+
+	return code, body, nil
+}
@@ -279,3 +279,69 @@ func TestSysHealth_head(t *testing.T) {
 		}
 	}
 }
+
+func TestSysHealthDetailed(t *testing.T) {
+	core := vault.TestCore(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+
+	resp, err := http.Get(addr + "/v1/sys/health/detailed")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var actual map[string]interface{}
+	testResponseStatus(t, resp, 503)
+	testResponseBody(t, resp, &actual)
+	if actual["status"] != vault.HealthStatusFailed {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	keys, token := vault.TestCoreInit(t, core)
+	for _, key := range keys {
+		if _, err := vault.TestCoreUnseal(core, vault.TestKeyCopy(key)); err != nil {
+			t.Fatalf("unseal err: %s", err)
+		}
+	}
+
+	resp, err = http.Get(addr + "/v1/sys/health/detailed")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual = map[string]interface{}{}
+	testResponseStatus(t, resp, 200)
+	testResponseBody(t, resp, &actual)
+	if actual["status"] != vault.HealthStatusOK {
+		t.Fatalf("bad: %#v", actual)
+	}
+	subsystems := actual["subsystems"].(map[string]interface{})
+	for _, name := range []string{"storage", "seal", "expiration", "replication", "audit"} {
+		subsystem, ok := subsystems[name].(map[string]interface{})
+		if !ok || subsystem["status"] != vault.HealthStatusOK {
+			t.Fatalf("bad %s status: %#v", name, subsystems[name])
+		}
+	}
+
+	if err := core.Seal(token); err != nil {
+		t.Fatalf("seal err: %s", err)
+	}
+
+	resp, err = http.Get(addr + "/v1/sys/health/detailed?failedcode=581")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	testResponseStatus(t, resp, 581)
+
+	resp, err = http.Head(addr + "/v1/sys/health/detailed")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	testResponseStatus(t, resp, 503)
+
+	resp, err = http.Get(addr + "/v1/sys/health/detailed?okcode=maybe")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	testResponseStatus(t, resp, 400)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/vault"
+)
+
+func TestSysNamespaces_Header(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+	TestServerAuth(t, addr, token)
+
+	resp := testHttpPut(t, token, addr+"/v1/sys/namespaces/ns1", nil)
+	testResponseStatus(t, resp, 200)
+
+	// The namespace can be given either within the header or the path
+	request := func(method, path, nsHeader string) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequest(method, addr+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(consts.AuthHeaderName, token)
+		if nsHeader != "" {
+			req.Header.Set(consts.NamespaceHeaderName, nsHeader)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp = request("POST", "/v1/sys/namespaces/ns2", "ns1")
+	testResponseStatus(t, resp, 200)
+	testResponseHeader(t, resp, map[string]string{consts.NamespaceHeaderName: "ns1"})
+
+	resp = request("GET", "/v1/ns1/sys/namespaces/ns2", "")
+	testResponseStatus(t, resp, 200)
+	var actual map[string]interface{}
+	testResponseBody(t, resp, &actual)
+	if path := actual["data"].(map[string]interface{})["path"]; path != "ns1/ns2/" {
+		t.Fatalf("bad path: %v", path)
+	}
+
+	resp = request("GET", "/v1/sys/namespaces/ns2", "ns1/")
+	testResponseStatus(t, resp, 200)
+
+	resp = request("GET", "/v1/sys/namespaces", "missing")
+	testResponseStatus(t, resp, 404)
+}
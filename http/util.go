@@ -10,18 +10,21 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/sdk/logical"
 
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/vault"
 	"github.com/hashicorp/vault/vault/quotas"
+	"github.com/sethvargo/go-limiter/httplimit"
 )
 
 var (
 	adjustRequest = func(c *vault.Core, r *http.Request) (*http.Request, int) {
-		return r, 0
+		return adjustRequestNamespace(c, r)
 	}
 
 	genericWrapping = func(core *vault.Core, in http.Handler, props *vault.HandlerProperties) http.Handler {
@@ -37,6 +40,31 @@ var (
 	adjustResponse = func(core *vault.Core, w http.ResponseWriter, req *logical.Request) {}
 )
 
+// adjustRequestNamespace sets the namespace of the request in its context. The
+// namespace given in the namespace header is moved to the path of the request,
+// so that the namespace is resolved the same way both when it is set within
+// the header and within the path.
+func adjustRequestNamespace(core *vault.Core, r *http.Request) (*http.Request, int) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+
+	if nsHeader := namespace.Canonicalize(r.Header.Get(consts.NamespaceHeaderName)); nsHeader != "" {
+		if core.NamespaceByPath(nsHeader).Path != nsHeader {
+			return r, http.StatusNotFound
+		}
+
+		path = nsHeader + path
+		r.URL.Path = "/v1/" + path
+		r.URL.RawPath = ""
+
+		// The namespace is now part of the path, and the request must not be
+		// resolved within it a second time when forwarded
+		r.Header.Del(consts.NamespaceHeaderName)
+	}
+
+	ns := core.NamespaceByPath(path)
+	return r.WithContext(namespace.ContextWithNamespace(r.Context(), ns)), 0
+}
+
 func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ns, err := namespace.FromContext(r.Context())
@@ -55,14 +83,20 @@ func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler
 		}
 		mountPath := strings.TrimPrefix(core.MatchingMount(r.Context(), path), ns.Path)
 
-		// Clone body, so we do not close the request body reader
-		bodyBytes, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			respondError(w, http.StatusInternalServerError, errors.New("failed to read request body"))
-			return
+		// Clone body, so we do not close the request body reader. The body of
+		// requests to streaming paths is left alone, as it isn't buffered.
+		var bodyBytes []byte
+		streaming := core.StreamingPath(r.Context(), path)
+		if !streaming {
+			bodyBytes, err = ioutil.ReadAll(r.Body)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, errors.New("failed to read request body"))
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
 		}
-		r.Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
 
+		token, _ := getTokenFromReq(r)
 		quotaResp, err := core.ApplyRateLimitQuota(r.Context(), &quotas.Request{
 			Type:          quotas.TypeRateLimit,
 			Path:          path,
@@ -70,6 +104,8 @@ func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler
 			Role:          core.DetermineRoleFromLoginRequestFromBytes(mountPath, bodyBytes, r.Context()),
 			NamespacePath: ns.Path,
 			ClientAddress: parseRemoteIPAddress(r),
+			Method:        quotaRequestMethod(r),
+			ClientToken:   token,
 		})
 		if err != nil {
 			core.Logger().Error("failed to apply quota", "path", path, "error", err)
@@ -84,6 +120,12 @@ func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler
 		}
 
 		if !quotaResp.Allowed {
+			// Clients are always told when to retry, even when the other rate
+			// limit headers are disabled.
+			if retryAfter := quotaResp.Headers[httplimit.HeaderRetryAfter]; retryAfter != "" {
+				w.Header().Set(httplimit.HeaderRetryAfter, retryAfter)
+			}
+
 			quotaErr := fmt.Errorf("request path %q: %w", path, quotas.ErrRateLimitQuotaExceeded)
 			respondError(w, http.StatusTooManyRequests, quotaErr)
 
@@ -92,7 +134,7 @@ func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler
 			}
 
 			if core.RateLimitAuditLoggingEnabled() {
-				req, _, status, err := buildLogicalRequestNoAuth(core.PerfStandby(), w, r)
+				req, _, status, err := buildLogicalRequestNoAuth(core.PerfStandby(), streaming, w, r)
 				if err != nil || status != 0 {
 					respondError(w, status, err)
 					return
@@ -115,6 +157,18 @@ func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler
 	})
 }
 
+// quotaRequestMethod returns the method of the request as seen by rate limit
+// quotas: list requests have the LIST method, whether they are sent with it or
+// as GET requests with the list parameter.
+func quotaRequestMethod(r *http.Request) string {
+	if r.Method == http.MethodGet {
+		if list, _ := strconv.ParseBool(r.URL.Query().Get("list")); list {
+			return "LIST"
+		}
+	}
+	return r.Method
+}
+
 func parseRemoteIPAddress(r *http.Request) string {
 	ip, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault/seal/pkcs11"
 )
 
 var (
@@ -55,6 +56,11 @@ type KMS struct {
 
 	Disabled bool
 	Config   map[string]string
+
+	// Name and Priority identify and order the seals when more than one
+	// seal is enabled at once
+	Name     string `hcl:"-"`
+	Priority int    `hcl:"-"`
 }
 
 func (k *KMS) GoString() string {
@@ -102,6 +108,24 @@ func parseKMS(result *[]*KMS, list *ast.ObjectList, blockName string, maxKMS int
 			delete(m, "disabled")
 		}
 
+		var name string
+		if v, ok := m["name"]; ok {
+			name, err = parseutil.ParseString(v)
+			if err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("%s.%s:", blockName, key))
+			}
+			delete(m, "name")
+		}
+
+		var priority int
+		if v, ok := m["priority"]; ok {
+			priority, err = parseutil.SafeParseInt(v)
+			if err != nil {
+				return multierror.Prefix(fmt.Errorf("unable to parse 'priority' in kms type %q: %w", key, err), fmt.Sprintf("%s.%s:", blockName, key))
+			}
+			delete(m, "priority")
+		}
+
 		strMap := make(map[string]string, len(m))
 		for k, v := range m {
 			s, err := parseutil.ParseString(v)
@@ -115,6 +139,8 @@ func parseKMS(result *[]*KMS, list *ast.ObjectList, blockName string, maxKMS int
 			Type:     strings.ToLower(key),
 			Purpose:  purpose,
 			Disabled: disabled,
+			Name:     name,
+			Priority: priority,
 		}
 		if len(strMap) > 0 {
 			seal.Config = strMap
@@ -196,7 +222,7 @@ func configureWrapper(configKMS *KMS, infoKeys *[]string, info *map[string]strin
 		wrapper, kmsInfo, err = GetTransitKMSFunc(configKMS, opts...)
 
 	case wrapping.WrapperTypePkcs11:
-		return nil, fmt.Errorf("KMS type 'pkcs11' requires the Vault Enterprise HSM binary")
+		wrapper, kmsInfo, err = GetPKCS11KMSFunc(configKMS, opts...)
 
 	default:
 		return nil, fmt.Errorf("Unknown KMS type %q", configKMS.Type)
@@ -347,6 +373,29 @@ var GetTransitKMSFunc = func(kms *KMS, opts ...wrapping.Option) (wrapping.Wrappe
 	return wrapper, info, nil
 }
 
+func GetPKCS11KMSFunc(kms *KMS, opts ...wrapping.Option) (wrapping.Wrapper, map[string]string, error) {
+	wrapper := pkcs11.NewWrapper()
+	wrapperInfo, err := wrapper.SetConfig(context.Background(), append(opts, wrapping.WithConfigMap(kms.Config))...)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := make(map[string]string)
+	if wrapperInfo != nil {
+		info["PKCS#11 Library"] = wrapperInfo.Metadata["lib"]
+		if slot, ok := wrapperInfo.Metadata["slot"]; ok {
+			info["PKCS#11 Slot"] = slot
+		}
+		if tokenLabel, ok := wrapperInfo.Metadata["token_label"]; ok {
+			info["PKCS#11 Token Label"] = tokenLabel
+		}
+		info["PKCS#11 Key Label"] = wrapperInfo.Metadata["key_label"]
+		if mechanism, ok := wrapperInfo.Metadata["mechanism"]; ok {
+			info["PKCS#11 Mechanism"] = mechanism
+		}
+	}
+	return wrapper, info, nil
+}
+
 func createSecureRandomReader(conf *SharedConfig, wrapper wrapping.Wrapper) (io.Reader, error) {
 	return rand.Reader, nil
 }
@@ -102,6 +102,8 @@ type Listener struct {
 
 	ProxyAPI *ProxyAPI `hcl:"proxy_api"`
 
+	GRPCAPI *GRPCAPI `hcl:"grpc_api"`
+
 	Telemetry              ListenerTelemetry              `hcl:"telemetry"`
 	Profiling              ListenerProfiling              `hcl:"profiling"`
 	InFlightRequestLogging ListenerInFlightRequestLogging `hcl:"inflight_requests_logging"`
@@ -130,6 +132,11 @@ type ProxyAPI struct {
 	EnableQuit bool `hcl:"enable_quit"`
 }
 
+// GRPCAPI configures the gRPC API served alongside the HTTP API.
+type GRPCAPI struct {
+	Enable bool `hcl:"enable"`
+}
+
 func (l *Listener) GoString() string {
 	return fmt.Sprintf("*%#v", *l)
 }
@@ -30,6 +30,7 @@ const (
 	PrometheusDefaultRetentionTime    = 24 * time.Hour
 	UsageGaugeDefaultPeriod           = 10 * time.Minute
 	MaximumGaugeCardinalityDefault    = 500
+	RouteMetricsMaxMountsDefault      = 100
 	LeaseMetricsEpsilonDefault        = time.Hour
 	NumLeaseMetricsTimeBucketsDefault = 168
 )
@@ -49,6 +50,11 @@ type Telemetry struct {
 
 	MaximumGaugeCardinality int `hcl:"maximum_gauge_cardinality"`
 
+	// RouteMetricsMaxMounts is the number of mounts the request metrics of
+	// the router are labeled with; requests to further mounts are labeled
+	// with the "other" mount.
+	RouteMetricsMaxMounts int `hcl:"route_metrics_max_mounts"`
+
 	// Circonus: see https://github.com/circonus-labs/circonus-gometrics
 	// for more details on the various configuration options.
 	// Valid configuration combinations:
@@ -216,6 +222,10 @@ func parseTelemetry(result *SharedConfig, list *ast.ObjectList) error {
 		result.Telemetry.MaximumGaugeCardinality = MaximumGaugeCardinalityDefault
 	}
 
+	if result.Telemetry.RouteMetricsMaxMounts == 0 {
+		result.Telemetry.RouteMetricsMaxMounts = RouteMetricsMaxMountsDefault
+	}
+
 	if result.Telemetry.LeaseMetricsEpsilonRaw != nil {
 		if result.Telemetry.LeaseMetricsEpsilonRaw == "none" {
 			result.Telemetry.LeaseMetricsEpsilonRaw = 0
@@ -402,6 +412,7 @@ func SetupTelemetry(opts *SetupTelemetryOpts) (*metrics.InmemSink, *metricsutil.
 	wrapper.TelemetryConsts.LeaseMetricsEpsilon = opts.Config.LeaseMetricsEpsilon
 	wrapper.TelemetryConsts.LeaseMetricsNameSpaceLabels = opts.Config.LeaseMetricsNameSpaceLabels
 	wrapper.TelemetryConsts.NumLeaseMetricsTimeBuckets = opts.Config.NumLeaseMetricsTimeBuckets
+	wrapper.TelemetryConsts.RouteMetricsMaxMounts = opts.Config.RouteMetricsMaxMounts
 
 	// Parse the metric filters
 	telemetryAllowedPrefixes, telemetryBlockedPrefixes, err := parsePrefixFilter(opts.Config.PrefixFilter)
@@ -413,6 +424,18 @@ func SetupTelemetry(opts *SetupTelemetryOpts) (*metrics.InmemSink, *metricsutil.
 	return inm, wrapper, prometheusEnabled, nil
 }
 
+// UpdateTelemetryFilter applies the prefix filter of the telemetry
+// configuration to the global metrics, so that it can be changed on reload.
+func UpdateTelemetryFilter(config *Telemetry) error {
+	telemetryAllowedPrefixes, telemetryBlockedPrefixes, err := parsePrefixFilter(config.PrefixFilter)
+	if err != nil {
+		return err
+	}
+
+	metrics.UpdateFilter(telemetryAllowedPrefixes, telemetryBlockedPrefixes)
+	return nil
+}
+
 func parsePrefixFilter(prefixFilters []string) ([]string, []string, error) {
 	var telemetryAllowedPrefixes, telemetryBlockedPrefixes []string
 
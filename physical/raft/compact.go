@@ -0,0 +1,221 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package raft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/rboyer/safeio"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// compactFilenameSuffix is appended to the filename of the database to
+	// name the file it is compacted into.
+	compactFilenameSuffix = ".compact"
+
+	// compactTxMaxSize is the amount of data copied to the compacted database
+	// in a single transaction.
+	compactTxMaxSize = 16 * 1024 * 1024
+)
+
+// CompactStats describes a compaction of the FSM database.
+type CompactStats struct {
+	SizeBefore int64
+	SizeAfter  int64
+	Duration   time.Duration
+}
+
+// Compact copies the content of the FSM database to a new file and installs
+// it in place of the current one, releasing the space held by free pages.
+// While a compaction is happening the FSM is locked and no writes or reads can
+// be performed.
+func (f *FSM) Compact() (*CompactStats, error) {
+	defer metrics.MeasureSince([]string{"raft_storage", "fsm", "compact"}, time.Now())
+
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	start := time.Now()
+	dbPath := filepath.Join(f.path, databaseFilename)
+	compactPath := dbPath + compactFilenameSuffix
+
+	st, err := os.Stat(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat database file: %w", err)
+	}
+	stats := &CompactStats{
+		SizeBefore: st.Size(),
+	}
+
+	// Remove the leftovers of an interrupted compaction
+	if err := os.Remove(compactPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove previous compacted database file: %w", err)
+	}
+
+	f.logger.Info("compacting database", "path", dbPath)
+
+	if err := f.compactTo(compactPath); err != nil {
+		os.Remove(compactPath)
+		return nil, err
+	}
+
+	// Close the db file
+	if err := f.db.Close(); err != nil {
+		f.logger.Error("failed to close database file", "error", err)
+		os.Remove(compactPath)
+		return nil, err
+	}
+
+	// Install the compacted boltdb file
+	var retErr *multierror.Error
+	if runtime.GOOS != "windows" {
+		err = safeio.Rename(compactPath, dbPath)
+	} else {
+		err = os.Rename(compactPath, dbPath)
+	}
+	if err != nil {
+		f.logger.Error("failed to install compacted database", "error", err)
+		retErr = multierror.Append(retErr, fmt.Errorf("failed to install compacted database: %w", err))
+		os.Remove(compactPath)
+	}
+
+	// Open the db file. We want to do this regardless of if the above install
+	// worked. If the install failed we should try to open the old DB file.
+	if err := f.openDBFile(dbPath); err != nil {
+		f.logger.Error("failed to open database file", "error", err)
+		retErr = multierror.Append(retErr, fmt.Errorf("failed to open bolt file: %w", err))
+	}
+
+	if err := retErr.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	if st, err := os.Stat(dbPath); err == nil {
+		stats.SizeAfter = st.Size()
+	}
+	stats.Duration = time.Since(start)
+
+	f.logger.Info("database compacted", "size_before", stats.SizeBefore, "size_after", stats.SizeAfter, "elapsed", stats.Duration)
+	return stats, nil
+}
+
+// compactTo copies the content of the database to a new database file at the
+// given path. The caller must hold the write lock of the FSM.
+func (f *FSM) compactTo(path string) error {
+	opts := boltOptions(path)
+	// The compacted database is reopened through openDBFile once installed
+	opts.InitialMmapSize = 0
+	dst, err := bolt.Open(path, 0o600, opts)
+	if err != nil {
+		return fmt.Errorf("failed to open compacted database file: %w", err)
+	}
+
+	// Syncing once the copy is done is enough, the file isn't installed if
+	// the copy fails
+	dst.NoSync = true
+
+	if err := bolt.Compact(dst, f.db, compactTxMaxSize); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to compact database: %w", err)
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to sync compacted database: %w", err)
+	}
+
+	return dst.Close()
+}
+
+// fragmentation returns the number of bytes held by the free and pending pages
+// of the FSM database and the size of the database.
+func (f *FSM) fragmentation() (int64, int64, error) {
+	f.l.RLock()
+	defer f.l.RUnlock()
+
+	stats := f.db.Stats()
+	free := int64(stats.FreePageN+stats.PendingPageN) * int64(f.db.Info().PageSize)
+
+	var size int64
+	err := f.db.View(func(tx *bolt.Tx) error {
+		size = tx.Size()
+		return nil
+	})
+
+	return free, size, err
+}
+
+// collectFragmentationMetrics emits the amount of free space in the FSM
+// database, and which fraction of the database it represents.
+func (b *RaftBackend) collectFragmentationMetrics(sink *metricsutil.ClusterMetricSink) {
+	free, size, err := b.fsm.fragmentation()
+	if err != nil || size == 0 {
+		return
+	}
+
+	labels := []metricsutil.Label{{Name: "database", Value: "fsm"}}
+	sink.SetGaugeWithLabels([]string{"raft_storage", "bolt", "freelist", "free_bytes"}, float32(free), labels)
+	sink.SetGaugeWithLabels([]string{"raft_storage", "bolt", "fragmentation_ratio"}, float32(free)/float32(size), labels)
+}
+
+// Compact compacts the FSM database of this node. Compacting the database of
+// the leader blocks writes to the cluster until the compaction is done, so
+// leadership should be transferred to another node first.
+func (b *RaftBackend) Compact() (*CompactStats, error) {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	if b.fsm == nil {
+		return nil, errors.New("raft storage is not initialized")
+	}
+
+	return b.fsm.Compact()
+}
+
+// RequestCompaction asks the given node to compact its FSM database. The node
+// does so when it applies the request, so its applied index does not move
+// past the returned index until the compaction is complete.
+func (b *RaftBackend) RequestCompaction(ctx context.Context, nodeID string) (uint64, error) {
+	command := &LogData{
+		Operations: []*LogOperation{
+			{
+				OpType: compactOp,
+				Key:    nodeID,
+			},
+		},
+	}
+
+	b.permitPool.Acquire()
+	b.l.RLock()
+	err := b.applyLog(ctx, command)
+	b.l.RUnlock()
+	b.permitPool.Release()
+	if err != nil {
+		return 0, err
+	}
+
+	return b.AppliedIndex(), nil
+}
+
+// TransferLeadership hands the leadership of the cluster over to another
+// voter.
+func (b *RaftBackend) TransferLeadership() error {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	if b.raft == nil {
+		return errors.New("raft storage is not initialized")
+	}
+
+	return b.raft.LeadershipTransfer().Error()
+}
@@ -36,6 +36,7 @@ const (
 	putOp
 	restoreCallbackOp
 	getOp
+	compactOp
 
 	chunkingPrefix   = "raftchunking/"
 	databaseFilename = "vault.db"
@@ -646,8 +647,11 @@ func (f *FSM) ApplyBatch(logs []*raft.Log) []interface{} {
 		}
 	}
 
+	// Compactions are requested for a single node and are performed once the
+	// batch is applied, unless the request is being replayed on startup
+	var compact bool
+
 	f.l.RLock()
-	defer f.l.RUnlock()
 
 	if f.applyCallback != nil {
 		f.applyCallback()
@@ -655,7 +659,7 @@ func (f *FSM) ApplyBatch(logs []*raft.Log) []interface{} {
 
 	err = f.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket(dataBucketName)
-		for _, commandRaw := range commands {
+		for i, commandRaw := range commands {
 			entrySlice := make([]*FSMEntry, 0)
 			switch command := commandRaw.(type) {
 			case *LogData:
@@ -682,6 +686,10 @@ func (f *FSM) ApplyBatch(logs []*raft.Log) []interface{} {
 							// Kick off the restore callback function in a go routine
 							go f.restoreCb(context.Background())
 						}
+					case compactOp:
+						if op.Key == f.localID && logs[i].Index > latestIndex.Index {
+							compact = true
+						}
 					default:
 						if _, ok := f.unknownOpTypes.Load(op.OpType); !ok {
 							f.logger.Error("unsupported transaction operation", "op", op.OpType)
@@ -717,11 +725,21 @@ func (f *FSM) ApplyBatch(logs []*raft.Log) []interface{} {
 
 		return nil
 	})
+	f.l.RUnlock()
 	if err != nil {
 		f.logger.Error("failed to store data", "error", err)
 		panic("failed to store data")
 	}
 
+	// Compact before advancing the latest index in memory, so that the
+	// applied index reported to the leader only moves past the compaction
+	// request once it is done.
+	if compact {
+		if _, err := f.Compact(); err != nil {
+			f.logger.Error("failed to compact database", "error", err)
+		}
+	}
+
 	// If we advanced the latest value, update the in-memory representation too.
 	if len(logIndex) > 0 {
 		atomic.StoreUint64(f.latestTerm, lastLog.Term)
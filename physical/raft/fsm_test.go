@@ -162,3 +162,102 @@ func TestFSM_List(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+func TestFSM_Compact(t *testing.T) {
+	fsm, dir := getFSM(t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	ctx := context.Background()
+	value := make([]byte, 4096)
+	for i := 0; i < 2000; i++ {
+		err := fsm.Put(ctx, &physical.Entry{Key: fmt.Sprintf("foo/%d", i), Value: value})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 10; i < 2000; i++ {
+		if err := fsm.Delete(ctx, fmt.Sprintf("foo/%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	free, _, err := fsm.fragmentation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if free == 0 {
+		t.Fatal("expected free pages")
+	}
+
+	getLog := func(index uint64) *raft.Log {
+		commandBytes, err := proto.Marshal(&LogData{
+			Operations: []*LogOperation{
+				{
+					OpType: compactOp,
+					Key:    fsm.localID,
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &raft.Log{
+			Index: index,
+			Term:  1,
+			Type:  raft.LogCommand,
+			Data:  commandBytes,
+		}
+	}
+
+	fsm.ApplyBatch([]*raft.Log{getLog(1)})
+
+	compacted, _, err := fsm.fragmentation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compacted >= free/10 {
+		t.Fatalf("free pages weren't released: %d bytes before, %d bytes after", free, compacted)
+	}
+
+	keys, err := fsm.List(ctx, "foo/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 10 {
+		t.Fatalf("incorrect number of keys: got %d expected 10", len(keys))
+	}
+	entry, err := fsm.Get(ctx, "foo/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry == nil || len(entry.Value) != len(value) {
+		t.Fatalf("bad entry: %v", entry)
+	}
+
+	latestIndex, _ := fsm.LatestState()
+	if latestIndex.Index != 1 {
+		t.Fatalf("bad latest index: got %d expected 1", latestIndex.Index)
+	}
+
+	// Requests replayed on startup are ignored
+	for i := 0; i < 10; i++ {
+		if err := fsm.Put(ctx, &physical.Entry{Key: fmt.Sprintf("bar/%d", i), Value: value}); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsm.Delete(ctx, fmt.Sprintf("bar/%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	free, _, err = fsm.fragmentation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsm.ApplyBatch([]*raft.Log{getLog(1)})
+	replayed, _, err := fsm.fragmentation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed < free {
+		t.Fatalf("replayed compaction request was applied: %d bytes before, %d bytes after", free, replayed)
+	}
+}
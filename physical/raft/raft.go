@@ -631,6 +631,7 @@ func (b *RaftBackend) CollectMetrics(sink *metricsutil.ClusterMetricSink) {
 	b.l.RUnlock()
 	b.collectMetricsWithStats(logstoreStats, sink, "logstore")
 	b.collectMetricsWithStats(fsmStats, sink, "fsm")
+	b.collectFragmentationMetrics(sink)
 	labels := []metrics.Label{
 		{
 			Name:  "peer_id",
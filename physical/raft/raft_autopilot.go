@@ -254,6 +254,20 @@ func (s *FollowerStates) Delete(nodeID string) {
 	s.l.Unlock()
 }
 
+// AppliedIndex returns the raft index last reported as applied by the given
+// follower, and whether the follower is known.
+func (s *FollowerStates) AppliedIndex(nodeID string) (uint64, bool) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+
+	state, ok := s.followers[nodeID]
+	if !ok {
+		return 0, false
+	}
+
+	return state.AppliedIndex, true
+}
+
 // MinIndex returns the minimum raft index applied in the raft cluster.
 func (s *FollowerStates) MinIndex() uint64 {
 	var min uint64 = math.MaxUint64
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package raft
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	snapshot "github.com/hashicorp/raft-snapshot"
+	"github.com/hashicorp/vault/sdk/plugin/pb"
+	bolt "go.etcd.io/bbolt"
+)
+
+// dryRunNodeID is the ID of the node the snapshots are restored to by
+// SnapshotFile.DryRunRestore.
+const dryRunNodeID = "snapshot-dry-run"
+
+// SnapshotFile is a snapshot whose integrity has been verified, and whose data
+// is buffered into a temporary file so that it can be inspected without
+// holding it in memory.
+type SnapshotFile struct {
+	Meta raft.SnapshotMeta
+
+	logger  log.Logger
+	file    *os.File
+	cleanup func()
+}
+
+// OpenSnapshotFile reads the snapshot archive from the given reader and
+// verifies the checksums of its content. The sealed checksums are not
+// verified, as this doesn't require access to the seal of the cluster the
+// snapshot was taken from.
+func OpenSnapshotFile(logger log.Logger, in io.Reader) (*SnapshotFile, error) {
+	var metadata raft.SnapshotMeta
+	file, cleanup, err := snapshot.WriteToTempFile(logger, in, &metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SnapshotFile{
+		Meta:    metadata,
+		logger:  logger,
+		file:    file,
+		cleanup: cleanup,
+	}, nil
+}
+
+// Close removes the temporary file of the snapshot data.
+func (s *SnapshotFile) Close() {
+	s.cleanup()
+}
+
+// Range calls fn for each of the storage entries of the snapshot, in key
+// order, until it returns an error.
+func (s *SnapshotFile) Range(fn func(key string, value []byte) error) error {
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind snapshot data: %w", err)
+	}
+
+	protoReader := NewDelimitedReader(s.file, math.MaxInt32)
+	entry := new(pb.StorageEntry)
+	for {
+		err := protoReader.ReadMsg(entry)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot entry: %w", err)
+		}
+
+		if err := fn(entry.Key, entry.Value); err != nil {
+			return err
+		}
+	}
+}
+
+// DryRunRestore restores the snapshot to an empty FSM in a temporary
+// directory, the same way it is installed on a node, and checks the restored
+// FSM matches the snapshot. It returns the number of restored keys.
+func (s *SnapshotFile) DryRunRestore() (int, error) {
+	dir, err := os.MkdirTemp("", "vault-raft-snapshot-dry-run")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create dry-run directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logger := s.logger.Named("dry-run")
+
+	fsm, err := NewFSM(dir, dryRunNodeID, logger)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create dry-run FSM: %w", err)
+	}
+	defer fsm.Close()
+
+	store, err := NewBoltSnapshotStore(dir, logger, fsm)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create dry-run snapshot store: %w", err)
+	}
+
+	sink, err := store.Create(s.Meta.Version, s.Meta.Index, s.Meta.Term, s.Meta.Configuration, s.Meta.ConfigurationIndex, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		sink.Cancel()
+		return 0, fmt.Errorf("failed to rewind snapshot data: %w", err)
+	}
+	if _, err := io.Copy(sink, s.file); err != nil {
+		sink.Cancel()
+		return 0, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := sink.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	_, snap, err := store.Open(sink.ID())
+	if err != nil {
+		return 0, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	if err := fsm.Restore(snap); err != nil {
+		return 0, fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	latestIndex, _ := fsm.LatestState()
+	if latestIndex.Index != s.Meta.Index || latestIndex.Term != s.Meta.Term {
+		return 0, fmt.Errorf("restored index %d and term %d don't match the snapshot index %d and term %d",
+			latestIndex.Index, latestIndex.Term, s.Meta.Index, s.Meta.Term)
+	}
+
+	var expected int
+	if err := s.Range(func(string, []byte) error {
+		expected++
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	var restored int
+	err = fsm.getDB().View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(dataBucketName)
+		if b == nil {
+			return errors.New("no data bucket")
+		}
+		restored = b.Stats().KeyN
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read restored FSM: %w", err)
+	}
+	if restored != expected {
+		return 0, fmt.Errorf("restored %d keys, the snapshot has %d keys", restored, expected)
+	}
+
+	return restored, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package raft
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/physical"
+)
+
+func TestSnapshotFile(t *testing.T) {
+	raft1, dir := GetRaft(t, true, false)
+	defer os.RemoveAll(dir)
+
+	// Write some data
+	for i := 0; i < 100; i++ {
+		err := raft1.Put(context.Background(), &physical.Entry{
+			Key:   fmt.Sprintf("key-%d", i),
+			Value: []byte(fmt.Sprintf("value-%d", i)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	snap := logical.NewHTTPResponseWriter(recorder)
+
+	err := raft1.Snapshot(snap, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := recorder.Body.Bytes()
+
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:  "raft",
+		Level: hclog.Trace,
+	})
+
+	snapFile, err := OpenSnapshotFile(logger, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snapFile.Close()
+
+	if snapFile.Meta.Index == 0 {
+		t.Fatal("expected a snapshot index")
+	}
+
+	entries := make(map[string]string)
+	err = snapFile.Range(func(key string, value []byte) error {
+		entries[key] = string(value)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 100 {
+		t.Fatalf("expected 100 entries, got %d", len(entries))
+	}
+	if entries["key-42"] != "value-42" {
+		t.Fatalf("bad entry: %q", entries["key-42"])
+	}
+
+	restored, err := snapFile.DryRunRestore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != 100 {
+		t.Fatalf("expected 100 restored keys, got %d", restored)
+	}
+
+	// Corrupted snapshots are rejected
+	data[len(data)/2] ^= 0xff
+	if _, err := OpenSnapshotFile(logger, bytes.NewReader(data)); err == nil {
+		t.Fatal("expected an error opening a corrupted snapshot")
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/hashicorp/vault/sdk/physical"
+
+	// Pure Go SQLite driver, so that Vault can keep being built without cgo
+	_ "modernc.org/sqlite"
+)
+
+// Verify SQLiteBackend satisfies the correct interfaces
+var (
+	_ physical.Backend       = (*SQLiteBackend)(nil)
+	_ physical.Transactional = (*SQLiteBackend)(nil)
+)
+
+const (
+	defaultTableName   = "vault_kv_store"
+	defaultJournalMode = "wal"
+	defaultSynchronous = "full"
+	defaultBusyTimeout = 5 * time.Second
+)
+
+var (
+	validJournalModes = []string{"delete", "truncate", "persist", "wal"}
+	validSynchronous  = []string{"off", "normal", "full", "extra"}
+)
+
+// SQLiteBackend is a physical backend that stores data within a SQLite
+// database file. It is meant for single-node deployments: the database can't
+// be shared by several Vault servers, so it doesn't support HA.
+type SQLiteBackend struct {
+	table         string
+	client        *sql.DB
+	rawStatements map[string]string
+	statements    map[string]*sql.Stmt
+	logger        log.Logger
+	permitPool    *physical.PermitPool
+}
+
+// NewSQLiteBackend constructs a SQLite backend storing its data in the
+// database file at the configured path.
+func NewSQLiteBackend(conf map[string]string, logger log.Logger) (physical.Backend, error) {
+	path := conf["path"]
+	if path == "" {
+		return nil, fmt.Errorf("'path' must be set")
+	}
+
+	dbTable := conf["table"]
+	if dbTable == "" {
+		dbTable = defaultTableName
+	}
+	if err := validateDBTable(dbTable); err != nil {
+		return nil, fmt.Errorf("invalid table: %w", err)
+	}
+
+	journalMode := strings.ToLower(conf["journal_mode"])
+	if journalMode == "" {
+		journalMode = defaultJournalMode
+	}
+	if !strutil.StrListContains(validJournalModes, journalMode) {
+		return nil, fmt.Errorf("invalid journal_mode %q, must be one of: %s", journalMode, strings.Join(validJournalModes, ", "))
+	}
+
+	synchronous := strings.ToLower(conf["synchronous"])
+	if synchronous == "" {
+		synchronous = defaultSynchronous
+	}
+	if !strutil.StrListContains(validSynchronous, synchronous) {
+		return nil, fmt.Errorf("invalid synchronous %q, must be one of: %s", synchronous, strings.Join(validSynchronous, ", "))
+	}
+
+	busyTimeout := defaultBusyTimeout
+	if busyTimeoutStr, ok := conf["busy_timeout"]; ok {
+		d, err := time.ParseDuration(busyTimeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing busy_timeout parameter: %w", err)
+		}
+		busyTimeout = d
+	}
+
+	maxParInt := physical.DefaultParallelOperations
+	if maxParStr, ok := conf["max_parallel"]; ok {
+		var err error
+		maxParInt, err = strconv.Atoi(maxParStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing max_parallel parameter: %w", err)
+		}
+		if logger.IsDebug() {
+			logger.Debug("max_parallel set", "max_parallel", maxParInt)
+		}
+	}
+
+	// Create the database file with restrictive permissions, SQLite uses the
+	// permissions of the database for its journal files.
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database file: %w", err)
+	}
+	f.Close()
+
+	// Transactions take the write lock as soon as they begin, so that
+	// concurrent transactions wait for each other instead of failing when
+	// upgrading their lock.
+	query := url.Values{}
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	query.Add("_pragma", fmt.Sprintf("journal_mode(%s)", journalMode))
+	query.Add("_pragma", fmt.Sprintf("synchronous(%s)", synchronous))
+	query.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", path+"?"+query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	db.SetMaxOpenConns(maxParInt)
+	db.SetMaxIdleConns(maxParInt)
+
+	// Create the required table if it doesn't exist.
+	createQuery := "CREATE TABLE IF NOT EXISTS " + dbTable +
+		" (path TEXT NOT NULL PRIMARY KEY, value BLOB) WITHOUT ROWID"
+	if _, err := db.Exec(createQuery); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create SQLite table: %w", err)
+	}
+
+	// Setup the backend
+	s := &SQLiteBackend{
+		table:  dbTable,
+		client: db,
+		rawStatements: map[string]string{
+			"put": "INSERT INTO " + dbTable + " (path, value) VALUES (?, ?)" +
+				" ON CONFLICT (path) DO UPDATE SET value = excluded.value",
+			"get":        "SELECT value FROM " + dbTable + " WHERE path = ?",
+			"delete":     "DELETE FROM " + dbTable + " WHERE path = ?",
+			"list":       "SELECT path FROM " + dbTable + " WHERE path >= ? AND path < ?",
+			"list_from":  "SELECT path FROM " + dbTable + " WHERE path >= ?",
+			"list_paths": "SELECT path FROM " + dbTable,
+		},
+		statements: make(map[string]*sql.Stmt),
+		logger:     logger,
+		permitPool: physical.NewPermitPool(maxParInt),
+	}
+
+	// Prepare all the statements required
+	for name, query := range s.rawStatements {
+		if err := s.prepare(name, query); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// prepare is a helper to prepare a query for future execution.
+func (s *SQLiteBackend) prepare(name, query string) error {
+	stmt, err := s.client.Prepare(query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare %q: %w", name, err)
+	}
+	s.statements[name] = stmt
+	return nil
+}
+
+// Put is used to insert or update an entry.
+func (s *SQLiteBackend) Put(ctx context.Context, entry *physical.Entry) error {
+	defer metrics.MeasureSince([]string{"sqlite", "put"}, time.Now())
+
+	s.permitPool.Acquire()
+	defer s.permitPool.Release()
+
+	_, err := s.statements["put"].ExecContext(ctx, entry.Key, entry.Value)
+	return err
+}
+
+// Get is used to fetch an entry.
+func (s *SQLiteBackend) Get(ctx context.Context, key string) (*physical.Entry, error) {
+	defer metrics.MeasureSince([]string{"sqlite", "get"}, time.Now())
+
+	s.permitPool.Acquire()
+	defer s.permitPool.Release()
+
+	var result []byte
+	err := s.statements["get"].QueryRowContext(ctx, key).Scan(&result)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ent := &physical.Entry{
+		Key:   key,
+		Value: result,
+	}
+	return ent, nil
+}
+
+// Delete is used to permanently delete an entry
+func (s *SQLiteBackend) Delete(ctx context.Context, key string) error {
+	defer metrics.MeasureSince([]string{"sqlite", "delete"}, time.Now())
+
+	s.permitPool.Acquire()
+	defer s.permitPool.Release()
+
+	_, err := s.statements["delete"].ExecContext(ctx, key)
+	return err
+}
+
+// List is used to list all the keys under a given
+// prefix, up to the next prefix.
+func (s *SQLiteBackend) List(ctx context.Context, prefix string) ([]string, error) {
+	defer metrics.MeasureSince([]string{"sqlite", "list"}, time.Now())
+
+	s.permitPool.Acquire()
+	defer s.permitPool.Release()
+
+	// Select the range of keys starting with the prefix rather than using
+	// LIKE, which is case insensitive and treats '_' as a wildcard.
+	var rows *sql.Rows
+	var err error
+	switch upper, ok := prefixUpperBound(prefix); {
+	case prefix == "":
+		rows, err = s.statements["list_paths"].QueryContext(ctx)
+	case ok:
+		rows, err = s.statements["list"].QueryContext(ctx, prefix, upper)
+	default:
+		rows, err = s.statements["list_from"].QueryContext(ctx, prefix)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		err = rows.Scan(&key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rows: %w", err)
+		}
+
+		key = strings.TrimPrefix(key, prefix)
+		if i := strings.Index(key, "/"); i == -1 {
+			// Add objects only from the current 'folder'
+			keys = append(keys, key)
+		} else {
+			// Add truncated 'folder' paths
+			keys = strutil.AppendIfMissing(keys, string(key[:i+1]))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Transaction is used to run multiple entries via a transaction
+func (s *SQLiteBackend) Transaction(ctx context.Context, txns []*physical.TxnEntry) error {
+	defer metrics.MeasureSince([]string{"sqlite", "transaction"}, time.Now())
+	if len(txns) == 0 {
+		return nil
+	}
+
+	s.permitPool.Acquire()
+	defer s.permitPool.Release()
+
+	tx, err := s.client.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.transaction(ctx, tx, txns); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteBackend) transaction(ctx context.Context, tx *sql.Tx, txns []*physical.TxnEntry) error {
+	deleteStmt := tx.StmtContext(ctx, s.statements["delete"])
+	putStmt := tx.StmtContext(ctx, s.statements["put"])
+
+	for _, op := range txns {
+		var err error
+		switch op.Operation {
+		case physical.DeleteOperation:
+			_, err = deleteStmt.ExecContext(ctx, op.Entry.Key)
+		case physical.PutOperation:
+			_, err = putStmt.ExecContext(ctx, op.Entry.Key, op.Entry.Value)
+		default:
+			return fmt.Errorf("%q is not a supported transaction operation", op.Operation)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the database.
+func (s *SQLiteBackend) Close() error {
+	return s.client.Close()
+}
+
+// prefixUpperBound returns the smallest string greater than every string
+// starting with the prefix, if there is one.
+func prefixUpperBound(prefix string) (string, bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < 0xff {
+			b[i]++
+			return string(b[:i+1]), true
+		}
+	}
+	return "", false
+}
+
+// validateDBTable checks that the table name is a plain SQL identifier, as it
+// is used unquoted in queries.
+func validateDBTable(dbTable string) error {
+	for i, r := range dbTable {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return fmt.Errorf("must only contain letters, digits and underscores, and not start with a digit")
+		}
+	}
+	if strings.HasPrefix(strings.ToLower(dbTable), "sqlite_") {
+		return fmt.Errorf("names starting with sqlite_ are reserved")
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/sdk/helper/logging"
+	"github.com/hashicorp/vault/sdk/physical"
+)
+
+func TestSQLiteBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "vault.db")
+	logger := logging.NewVaultLogger(log.Debug)
+
+	b, err := NewSQLiteBackend(map[string]string{
+		"path": path,
+	}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create new backend: %v", err)
+	}
+	defer b.(*SQLiteBackend).Close()
+
+	st, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perms := st.Mode().Perm(); perms != 0o600 {
+		t.Fatalf("bad database file permissions: %v", perms)
+	}
+
+	var journalMode string
+	if err := b.(*SQLiteBackend).client.QueryRow("PRAGMA journal_mode").Scan(&journalMode); err != nil {
+		t.Fatal(err)
+	}
+	if journalMode != "wal" {
+		t.Fatalf("bad journal mode: %q", journalMode)
+	}
+
+	physical.ExerciseBackend(t, b)
+	physical.ExerciseBackend_ListPrefix(t, b)
+	physical.ExerciseTransactionalBackend(t, b)
+}
+
+func TestSQLiteBackend_listWildcards(t *testing.T) {
+	b, err := NewSQLiteBackend(map[string]string{
+		"path": filepath.Join(t.TempDir(), "vault.db"),
+	}, logging.NewVaultLogger(log.Debug))
+	if err != nil {
+		t.Fatalf("Failed to create new backend: %v", err)
+	}
+	defer b.(*SQLiteBackend).Close()
+
+	ctx := context.Background()
+	for _, key := range []string{"foo_bar/a", "fooXbar/b", "FOO_BAR/c", "foo_bar"} {
+		if err := b.Put(ctx, &physical.Entry{Key: key, Value: []byte(key)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := b.List(ctx, "foo_bar/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("bad keys: %v", keys)
+	}
+}
+
+func TestSQLiteBackend_config(t *testing.T) {
+	cases := map[string]map[string]string{
+		"missing path":         {},
+		"invalid table":        {"table": "vault; DROP TABLE vault"},
+		"reserved table":       {"table": "sqlite_master"},
+		"invalid journal mode": {"journal_mode": "memory"},
+		"invalid synchronous":  {"synchronous": "sometimes"},
+		"invalid busy timeout": {"busy_timeout": "5"},
+		"invalid max parallel": {"max_parallel": "many"},
+	}
+
+	for name, conf := range cases {
+		t.Run(name, func(t *testing.T) {
+			if name != "missing path" {
+				conf["path"] = filepath.Join(t.TempDir(), "vault.db")
+			}
+			if _, err := NewSQLiteBackend(conf, logging.NewVaultLogger(log.Debug)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
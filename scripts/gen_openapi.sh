@@ -57,6 +57,7 @@ vault auth enable "oci"
 vault auth enable "okta"
 vault auth enable "radius"
 vault auth enable "userpass"
+vault auth enable "webauthn"
 
 # Enable secrets plugins
 vault secrets enable "alicloud"
@@ -62,6 +62,10 @@ type Backend struct {
 	// Initialize() just after a plugin has been mounted.
 	InitializeFunc InitializeFunc
 
+	// HealthCheckFunc is the callback, which if set, will be invoked via
+	// HealthCheck() when an operator requests the health of the mounts.
+	HealthCheckFunc HealthCheckFunc
+
 	// PeriodicFunc is the callback, which if set, will be invoked when the
 	// periodic timer of RollbackManager ticks. This can be used by
 	// backends to do anything it wishes to do periodically.
@@ -129,6 +133,10 @@ type InvalidateFunc func(context.Context, string)
 // Initialize() just after a plugin has been mounted.
 type InitializeFunc func(context.Context, *logical.InitializationRequest) error
 
+// HealthCheckFunc is the callback, which if set, will be invoked via
+// HealthCheck() when an operator requests the health of the mounts.
+type HealthCheckFunc func(context.Context, *logical.HealthCheckRequest) error
+
 // PatchPreprocessorFunc is used by HandlePatchOperation in order to shape
 // the input as defined by request handler prior to JSON marshaling
 type PatchPreprocessorFunc func(map[string]interface{}) (map[string]interface{}, error)
@@ -145,6 +153,15 @@ func (b *Backend) Initialize(ctx context.Context, req *logical.InitializationReq
 	return nil
 }
 
+// HealthCheck is the logical.HealthChecker implementation. It returns
+// logical.ErrUnsupportedOperation if no HealthCheckFunc is set.
+func (b *Backend) HealthCheck(ctx context.Context, req *logical.HealthCheckRequest) error {
+	if b.HealthCheckFunc == nil {
+		return logical.ErrUnsupportedOperation
+	}
+	return b.HealthCheckFunc(ctx, req)
+}
+
 // HandleExistenceCheck is the logical.Backend implementation.
 func (b *Backend) HandleExistenceCheck(ctx context.Context, req *logical.Request) (checkFound bool, exists bool, err error) {
 	b.once.Do(b.init)
@@ -241,6 +241,12 @@ type KeyEntry struct {
 	DeprecatedCreationTime int64 `json:"creation_time"`
 
 	ManagedKeyUUID string `json:"managed_key_id,omitempty"`
+
+	// Generated is set if the key was generated by Vault rather than
+	// imported. It is set on the legacy key upgraded to the keys map, since
+	// keys could not be imported then, but unset on the other keys generated
+	// before it was recorded.
+	Generated bool `json:"generated,omitempty"`
 }
 
 func (ke *KeyEntry) IsPrivateKeyMissing() bool {
@@ -1556,6 +1562,7 @@ func (p *Policy) RotateInMemory(randReader io.Reader) (retErr error) {
 	entry := KeyEntry{
 		CreationTime:           now,
 		DeprecatedCreationTime: now.Unix(),
+		Generated:              true,
 	}
 
 	hmacKey, err := uuid.GenerateRandomBytesWithReader(32, randReader)
@@ -1670,6 +1677,7 @@ func (p *Policy) MigrateKeyToKeysMap() {
 			Key:                    p.Key,
 			CreationTime:           now,
 			DeprecatedCreationTime: now.Unix(),
+			Generated:              true,
 		},
 	}
 	p.Key = nil
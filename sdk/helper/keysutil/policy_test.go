@@ -112,6 +112,9 @@ func testKeyUpgradeCommon(t *testing.T, lm *LockManager) {
 	if !reflect.DeepEqual(testBytes, p.Keys["1"].Key) {
 		t.Fatal("key mismatch")
 	}
+	if !p.Keys["1"].Generated {
+		t.Fatal("migrated key is not marked as generated")
+	}
 }
 
 func Test_ArchivingUpgrade(t *testing.T) {
@@ -520,6 +523,10 @@ func Test_BadUpgrade(t *testing.T) {
 
 	k := p.Keys["1"]
 	o := orig.(*Policy).Keys["1"]
+	// The legacy key was generated by Vault, like the key it replaces
+	if !k.Generated || !o.Generated {
+		t.Fatalf("expected generated keys, upgraded: %v, original: %v", k.Generated, o.Generated)
+	}
 	k.CreationTime = o.CreationTime
 	k.HMACKey = o.HMACKey
 	p.Keys["1"] = k
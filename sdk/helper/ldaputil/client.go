@@ -29,83 +29,84 @@ type Client struct {
 
 func (c *Client) DialLDAP(cfg *ConfigEntry) (Connection, error) {
 	var retErr *multierror.Error
-	var conn Connection
-	urls := strings.Split(cfg.Url, ",")
-
-	for _, uut := range urls {
-		u, err := url.Parse(uut)
+	for _, uut := range strings.Split(cfg.Url, ",") {
+		conn, err := c.DialURL(cfg, uut)
 		if err != nil {
-			retErr = multierror.Append(retErr, fmt.Errorf(fmt.Sprintf("error parsing url %q: {{err}}", uut), err))
+			retErr = multierror.Append(retErr, err)
 			continue
 		}
-		host, port, err := net.SplitHostPort(u.Host)
-		if err != nil {
-			host = u.Host
+		if retErr != nil {
+			if c.Logger.IsDebug() {
+				c.Logger.Debug("errors connecting to some hosts", "error", retErr.Error())
+			}
 		}
+		return conn, nil
+	}
+	return nil, retErr
+}
 
-		var tlsConfig *tls.Config
-		dialer := net.Dialer{
-			Timeout: time.Duration(cfg.ConnectionTimeout) * time.Second,
-		}
+// DialURL connects to a single one of the LDAP servers of the configuration.
+func (c *Client) DialURL(cfg *ConfigEntry, uut string) (Connection, error) {
+	u, err := url.Parse(uut)
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("error parsing url %q: {{err}}", uut), err)
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host = u.Host
+	}
 
-		switch u.Scheme {
-		case "ldap":
-			if port == "" {
-				port = "389"
-			}
+	var conn Connection
+	var tlsConfig *tls.Config
+	dialer := net.Dialer{
+		Timeout: time.Duration(cfg.ConnectionTimeout) * time.Second,
+	}
 
-			fullAddr := fmt.Sprintf("%s://%s", u.Scheme, net.JoinHostPort(host, port))
-			opt := ldap.DialWithDialer(&dialer)
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
 
-			conn, err = c.LDAP.DialURL(fullAddr, opt)
-			if err != nil {
-				break
-			}
-			if conn == nil {
-				err = fmt.Errorf("empty connection after dialing")
-				break
-			}
-			if cfg.StartTLS {
-				tlsConfig, err = getTLSConfig(cfg, host)
-				if err != nil {
-					break
-				}
-				err = conn.StartTLS(tlsConfig)
-			}
-		case "ldaps":
-			if port == "" {
-				port = "636"
-			}
+		fullAddr := fmt.Sprintf("%s://%s", u.Scheme, net.JoinHostPort(host, port))
+		opt := ldap.DialWithDialer(&dialer)
+
+		conn, err = c.LDAP.DialURL(fullAddr, opt)
+		if err != nil {
+			break
+		}
+		if conn == nil {
+			err = fmt.Errorf("empty connection after dialing")
+			break
+		}
+		if cfg.StartTLS {
 			tlsConfig, err = getTLSConfig(cfg, host)
-			if err != nil {
-				break
+			if err == nil {
+				err = conn.StartTLS(tlsConfig)
 			}
-
-			fullAddr := fmt.Sprintf("%s://%s", u.Scheme, net.JoinHostPort(host, port))
-			opt := ldap.DialWithDialer(&dialer)
-			tls := ldap.DialWithTLSConfig(tlsConfig)
-
-			conn, err = c.LDAP.DialURL(fullAddr, opt, tls)
 			if err != nil {
-				break
+				conn.Close()
 			}
-		default:
-			retErr = multierror.Append(retErr, fmt.Errorf("invalid LDAP scheme in url %q", net.JoinHostPort(host, port)))
-			continue
 		}
-		if err == nil {
-			if retErr != nil {
-				if c.Logger.IsDebug() {
-					c.Logger.Debug("errors connecting to some hosts", "error", retErr.Error())
-				}
-			}
-			retErr = nil
+	case "ldaps":
+		if port == "" {
+			port = "636"
+		}
+		tlsConfig, err = getTLSConfig(cfg, host)
+		if err != nil {
 			break
 		}
-		retErr = multierror.Append(retErr, fmt.Errorf(fmt.Sprintf("error connecting to host %q: {{err}}", uut), err))
+
+		fullAddr := fmt.Sprintf("%s://%s", u.Scheme, net.JoinHostPort(host, port))
+		opt := ldap.DialWithDialer(&dialer)
+		tls := ldap.DialWithTLSConfig(tlsConfig)
+
+		conn, err = c.LDAP.DialURL(fullAddr, opt, tls)
+	default:
+		return nil, fmt.Errorf("invalid LDAP scheme in url %q", net.JoinHostPort(host, port))
 	}
-	if retErr != nil {
-		return nil, retErr
+	if err != nil {
+		return nil, fmt.Errorf(fmt.Sprintf("error connecting to host %q: {{err}}", uut), err)
 	}
 	if timeout := cfg.RequestTimeout; timeout > 0 {
 		conn.SetTimeout(time.Duration(timeout) * time.Second)
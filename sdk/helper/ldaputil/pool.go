@@ -0,0 +1,398 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ldaputil
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// poolMaxIdleTime is how long a connection may stay idle in a pool before it
+// is closed, so that connections aren't reused after the server dropped them.
+const poolMaxIdleTime = 5 * time.Minute
+
+// Pool maintains idle connections to each of the LDAP servers of a
+// configuration and tracks the health of the servers. Servers that failed are
+// only tried after the healthy ones, so that requests fail over to the
+// remaining servers without waiting for unavailable ones to time out.
+//
+// Only connections bound as the configured BindDN are returned to the pool,
+// as connections bound as any other user can't be safely reused.
+type Pool struct {
+	client *Client
+	cfg    *ConfigEntry
+	size   int
+
+	lock    sync.Mutex
+	servers []*poolServer
+	closed  bool
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+type poolServer struct {
+	url       string
+	idle      []*idleConn
+	healthy   bool
+	lastCheck time.Time
+	lastError error
+}
+
+type idleConn struct {
+	conn  Connection
+	since time.Time
+}
+
+// ServerHealth is the health status of one of the LDAP servers of a pool.
+type ServerHealth struct {
+	URL             string
+	Healthy         bool
+	LastCheck       time.Time
+	LastError       string
+	IdleConnections int
+}
+
+// NewPool returns a pool of connections to the servers of the configuration,
+// keeping up to size idle connections per server. If healthCheckInterval is
+// positive, the servers are checked in the background at that interval until
+// the pool is closed.
+func NewPool(client *Client, cfg *ConfigEntry, size int, healthCheckInterval time.Duration) *Pool {
+	p := &Pool{
+		client: client,
+		cfg:    cfg,
+		size:   size,
+	}
+	for _, u := range strings.Split(cfg.Url, ",") {
+		p.servers = append(p.servers, &poolServer{
+			url:     strings.TrimSpace(u),
+			healthy: true,
+		})
+	}
+
+	if healthCheckInterval > 0 {
+		p.stopCh = make(chan struct{})
+		p.doneCh = make(chan struct{})
+		go p.run(healthCheckInterval)
+	}
+
+	return p
+}
+
+func (p *Pool) run(interval time.Duration) {
+	defer close(p.doneCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.HealthCheck()
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// pooling reports whether connections are returned to the pool.
+func (p *Pool) pooling() bool {
+	return p.size > 0 && p.cfg.BindDN != "" && p.cfg.BindPassword != ""
+}
+
+// Get returns a connection to one of the servers of the pool. Idle
+// connections are bound as the BindDN already. Closing the connection
+// returns it to the pool if it is bound as the BindDN at that time.
+func (p *Pool) Get() (Connection, error) {
+	return p.get(true)
+}
+
+// Dial returns a new connection to one of the servers of the pool, failing
+// over like Get. The connection is never returned to the pool.
+func (p *Pool) Dial() (Connection, error) {
+	return p.get(false)
+}
+
+func (p *Pool) get(reuse bool) (Connection, error) {
+	var retErr *multierror.Error
+	for _, server := range p.orderedServers() {
+		if reuse {
+			if conn := p.takeIdle(server); conn != nil {
+				return &pooledConn{Connection: conn, pool: p, server: server, reuse: true, bound: true}, nil
+			}
+		}
+
+		conn, err := p.client.DialURL(p.cfg, server.url)
+		if err != nil {
+			p.setHealth(server, err)
+			retErr = multierror.Append(retErr, err)
+			continue
+		}
+		p.setHealth(server, nil)
+
+		if retErr != nil {
+			if p.client.Logger.IsDebug() {
+				p.client.Logger.Debug("errors connecting to some hosts", "error", retErr.Error())
+			}
+		}
+		return &pooledConn{Connection: conn, pool: p, server: server, reuse: reuse}, nil
+	}
+	if retErr == nil {
+		return nil, errors.New("no LDAP servers configured")
+	}
+	return nil, retErr
+}
+
+// orderedServers returns the servers in the order they should be tried: the
+// healthy ones first, each group in the order they were configured in.
+func (p *Pool) orderedServers() []*poolServer {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	servers := make([]*poolServer, len(p.servers))
+	copy(servers, p.servers)
+	sort.SliceStable(servers, func(i, j int) bool {
+		return servers[i].healthy && !servers[j].healthy
+	})
+	return servers
+}
+
+// takeIdle returns an idle connection of the server that is still usable, or
+// nil if there is none.
+func (p *Pool) takeIdle(server *poolServer) Connection {
+	for {
+		p.lock.Lock()
+		if len(server.idle) == 0 {
+			p.lock.Unlock()
+			return nil
+		}
+		idle := server.idle[len(server.idle)-1]
+		server.idle = server.idle[:len(server.idle)-1]
+		p.lock.Unlock()
+
+		// Connections may have been dropped by the server while idle,
+		// re-binding makes sure they are still usable
+		if time.Since(idle.since) < poolMaxIdleTime {
+			if err := idle.conn.Bind(p.cfg.BindDN, p.cfg.BindPassword); err == nil {
+				return idle.conn
+			}
+		}
+		idle.conn.Close()
+	}
+}
+
+func (p *Pool) put(c *pooledConn) {
+	p.lock.Lock()
+	if !p.closed && c.reuse && c.bound && !c.broken && c.server.healthy && p.pooling() && len(c.server.idle) < p.size {
+		c.server.idle = append(c.server.idle, &idleConn{
+			conn:  c.Connection,
+			since: time.Now(),
+		})
+		p.lock.Unlock()
+		return
+	}
+	p.lock.Unlock()
+
+	c.Connection.Close()
+}
+
+func (p *Pool) setHealth(server *poolServer, err error) {
+	p.lock.Lock()
+	server.healthy = err == nil
+	server.lastCheck = time.Now()
+	server.lastError = err
+	var idle []*idleConn
+	if err != nil {
+		idle, server.idle = server.idle, nil
+	}
+	p.lock.Unlock()
+
+	if err != nil && p.client.Logger.IsDebug() {
+		p.client.Logger.Debug("ldap server is unhealthy", "url", server.url, "error", err)
+	}
+
+	for _, i := range idle {
+		i.conn.Close()
+	}
+}
+
+// HealthCheck checks that each server of the pool can be connected to, and
+// bound to as the BindDN if one is configured. Idle connections that have
+// been idle for too long are closed.
+func (p *Pool) HealthCheck() {
+	p.lock.Lock()
+	servers := make([]*poolServer, len(p.servers))
+	copy(servers, p.servers)
+
+	var expired []*idleConn
+	for _, server := range servers {
+		idle := server.idle[:0]
+		for _, i := range server.idle {
+			if time.Since(i.since) < poolMaxIdleTime {
+				idle = append(idle, i)
+			} else {
+				expired = append(expired, i)
+			}
+		}
+		server.idle = idle
+	}
+	p.lock.Unlock()
+
+	for _, i := range expired {
+		i.conn.Close()
+	}
+
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		wg.Add(1)
+		go func(server *poolServer) {
+			defer wg.Done()
+			p.setHealth(server, p.checkServer(server))
+		}(server)
+	}
+	wg.Wait()
+}
+
+func (p *Pool) checkServer(server *poolServer) error {
+	conn, err := p.client.DialURL(p.cfg, server.url)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if p.cfg.BindDN != "" && p.cfg.BindPassword != "" {
+		if err := conn.Bind(p.cfg.BindDN, p.cfg.BindPassword); err != nil {
+			return fmt.Errorf("error binding as BindDN on host %q: %w", server.url, err)
+		}
+	}
+	return nil
+}
+
+// Health returns the health status of each server of the pool.
+func (p *Pool) Health() []ServerHealth {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	health := make([]ServerHealth, 0, len(p.servers))
+	for _, server := range p.servers {
+		h := ServerHealth{
+			URL:             server.url,
+			Healthy:         server.healthy,
+			LastCheck:       server.lastCheck,
+			IdleConnections: len(server.idle),
+		}
+		if server.lastError != nil {
+			h.LastError = server.lastError.Error()
+		}
+		health = append(health, h)
+	}
+	return health
+}
+
+// Close stops the background health checks and closes the idle connections
+// of the pool. Connections in use are closed when they are released.
+func (p *Pool) Close() {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return
+	}
+	p.closed = true
+
+	var idle []*idleConn
+	for _, server := range p.servers {
+		idle = append(idle, server.idle...)
+		server.idle = nil
+	}
+	p.lock.Unlock()
+
+	for _, i := range idle {
+		i.conn.Close()
+	}
+
+	if p.stopCh != nil {
+		close(p.stopCh)
+		<-p.doneCh
+	}
+}
+
+// pooledConn is a connection of a pool, which tracks whether it can be
+// returned to the pool when closed.
+type pooledConn struct {
+	Connection
+
+	pool   *Pool
+	server *poolServer
+
+	// reuse is whether the connection may be returned to the pool
+	reuse bool
+	// bound is whether the connection is bound as the BindDN
+	bound bool
+	// broken is whether an operation on the connection failed because of a
+	// network error
+	broken bool
+	closed bool
+}
+
+var _ PagingConnection = (*pooledConn)(nil)
+
+func (c *pooledConn) checkErr(err error) error {
+	if err != nil && ldap.IsErrorWithCode(err, ldap.ErrorNetwork) {
+		c.broken = true
+		c.pool.setHealth(c.server, err)
+	}
+	return err
+}
+
+func (c *pooledConn) Bind(username, password string) error {
+	err := c.Connection.Bind(username, password)
+	c.bound = err == nil && username == c.pool.cfg.BindDN && password == c.pool.cfg.BindPassword
+	return c.checkErr(err)
+}
+
+func (c *pooledConn) UnauthenticatedBind(username string) error {
+	c.bound = false
+	return c.checkErr(c.Connection.UnauthenticatedBind(username))
+}
+
+func (c *pooledConn) Add(addRequest *ldap.AddRequest) error {
+	return c.checkErr(c.Connection.Add(addRequest))
+}
+
+func (c *pooledConn) Modify(modifyRequest *ldap.ModifyRequest) error {
+	return c.checkErr(c.Connection.Modify(modifyRequest))
+}
+
+func (c *pooledConn) Del(delRequest *ldap.DelRequest) error {
+	return c.checkErr(c.Connection.Del(delRequest))
+}
+
+func (c *pooledConn) Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	result, err := c.Connection.Search(searchRequest)
+	return result, c.checkErr(err)
+}
+
+func (c *pooledConn) SearchWithPaging(searchRequest *ldap.SearchRequest, pagingSize uint32) (*ldap.SearchResult, error) {
+	paging, ok := c.Connection.(PagingConnection)
+	if !ok {
+		return c.Search(searchRequest)
+	}
+	result, err := paging.SearchWithPaging(searchRequest, pagingSize)
+	return result, c.checkErr(err)
+}
+
+func (c *pooledConn) Close() {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.pool.put(c)
+}
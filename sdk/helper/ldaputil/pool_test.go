@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package ldaputil
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+// testLDAP dials fake connections to servers that can be taken down.
+type testLDAP struct {
+	lock  sync.Mutex
+	down  map[string]bool
+	dials map[string]int
+}
+
+func (l *testLDAP) DialURL(addr string, opts ...ldap.DialOpt) (Connection, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.down[addr] {
+		return nil, errors.New("connection refused")
+	}
+	l.dials[addr]++
+	return &testConn{}, nil
+}
+
+func (l *testLDAP) setDown(addr string, down bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.down[addr] = down
+}
+
+type testConn struct {
+	Connection
+	closed bool
+}
+
+func (c *testConn) Bind(username, password string) error {
+	if password != "password" {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	return nil
+}
+
+func (c *testConn) SetTimeout(time.Duration) {}
+
+func (c *testConn) Close() {
+	c.closed = true
+}
+
+func testPool(t *testing.T, url string) (*Pool, *testLDAP) {
+	t.Helper()
+
+	l := &testLDAP{
+		down:  map[string]bool{},
+		dials: map[string]int{},
+	}
+	client := &Client{
+		Logger: hclog.NewNullLogger(),
+		LDAP:   l,
+	}
+	p := NewPool(client, &ConfigEntry{
+		Url:          url,
+		BindDN:       "cn=vault",
+		BindPassword: "password",
+	}, 2, 0)
+	t.Cleanup(p.Close)
+	return p, l
+}
+
+func TestPool_Reuse(t *testing.T) {
+	p, l := testPool(t, "ldap://ldap1")
+
+	conn, err := p.Get()
+	require.NoError(t, err)
+	require.NoError(t, conn.Bind("cn=vault", "password"))
+	conn.Close()
+	require.Equal(t, 1, p.Health()[0].IdleConnections)
+
+	// Connections bound as the BindDN are reused
+	conn, err = p.Get()
+	require.NoError(t, err)
+	require.Equal(t, 1, l.dials["ldap://ldap1:389"])
+
+	// Connections bound as another user are not returned to the pool
+	require.NoError(t, conn.Bind("cn=alice", "password"))
+	conn.Close()
+	require.Equal(t, 0, p.Health()[0].IdleConnections)
+
+	// Dialed connections are never returned to the pool
+	conn, err = p.Dial()
+	require.NoError(t, err)
+	require.NoError(t, conn.Bind("cn=vault", "password"))
+	conn.Close()
+	require.Equal(t, 0, p.Health()[0].IdleConnections)
+	require.Equal(t, 2, l.dials["ldap://ldap1:389"])
+}
+
+func TestPool_Failover(t *testing.T) {
+	p, l := testPool(t, "ldap://ldap1,ldap://ldap2")
+	l.setDown("ldap://ldap1:389", true)
+
+	conn, err := p.Get()
+	require.NoError(t, err)
+	require.Equal(t, "ldap://ldap2", conn.(*pooledConn).server.url)
+	conn.Close()
+
+	health := p.Health()
+	require.False(t, health[0].Healthy)
+	require.Contains(t, health[0].LastError, "connection refused")
+	require.True(t, health[1].Healthy)
+
+	// Unhealthy servers are tried last
+	l.setDown("ldap://ldap1:389", false)
+	conn, err = p.Get()
+	require.NoError(t, err)
+	require.Equal(t, "ldap://ldap2", conn.(*pooledConn).server.url)
+	conn.Close()
+	require.Equal(t, 0, l.dials["ldap://ldap1:389"])
+
+	// Health checks pick up servers that recovered
+	p.HealthCheck()
+	health = p.Health()
+	require.True(t, health[0].Healthy)
+	require.Empty(t, health[0].LastError)
+	require.False(t, health[0].LastCheck.IsZero())
+
+	conn, err = p.Get()
+	require.NoError(t, err)
+	require.Equal(t, "ldap://ldap1", conn.(*pooledConn).server.url)
+	conn.Close()
+
+	l.setDown("ldap://ldap1:389", true)
+	l.setDown("ldap://ldap2:389", true)
+	_, err = p.Dial()
+	require.Error(t, err)
+}
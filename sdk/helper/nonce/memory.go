@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nonce
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// Ensure that memoryService implements the Service interface
+var _ Service = (*memoryService)(nil)
+
+// memoryService keeps the nonces it issues in memory. It is the cheapest
+// service, suited to endpoints handing out a nonce with every response, but
+// nonces can only be redeemed on the node which issued them and are lost when
+// the node restarts.
+type memoryService struct {
+	validity   time.Duration
+	nextExpiry *atomic.Int64
+	nonces     *sync.Map // map[string]time.Time
+}
+
+// NewMemoryService returns a service keeping the nonces it issues in memory,
+// which are valid for the given duration, or DefaultValidity if zero.
+func NewMemoryService(validity time.Duration) Service {
+	return &memoryService{
+		validity:   validityOrDefault(validity),
+		nextExpiry: new(atomic.Int64),
+		nonces:     new(sync.Map),
+	}
+}
+
+func (m *memoryService) Get(_ context.Context, _ logical.Storage) (string, time.Time, error) {
+	now := time.Now()
+	nonce, err := generate()
+	if err != nil {
+		return "", now, err
+	}
+
+	then := now.Add(m.validity)
+	m.nonces.Store(nonce, then)
+
+	nextExpiry := m.nextExpiry.Load()
+	next := time.Unix(nextExpiry, 0)
+	if now.After(next) || then.Before(next) {
+		m.nextExpiry.Store(then.Unix())
+	}
+
+	return nonce, then, nil
+}
+
+func (m *memoryService) Redeem(_ context.Context, _ logical.Storage, nonce string) (bool, error) {
+	rawTimeout, present := m.nonces.LoadAndDelete(nonce)
+	if !present {
+		return false, nil
+	}
+
+	timeout := rawTimeout.(time.Time)
+	if time.Now().After(timeout) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Tidy removes the expired nonces, if any may have expired since the last
+// time it ran.
+func (m *memoryService) Tidy(_ context.Context, _ logical.Storage) error {
+	now := time.Now()
+	expiry := m.nextExpiry.Load()
+	if expiry != 0 && now.Before(time.Unix(expiry, 0)) {
+		return nil
+	}
+
+	nextRun := now.Add(m.validity)
+	m.nonces.Range(func(key, value any) bool {
+		timeout := value.(time.Time)
+		if now.After(timeout) {
+			m.nonces.Delete(key)
+		} else if timeout.Before(nextRun) {
+			nextRun = timeout
+		}
+
+		return true
+	})
+
+	m.nextExpiry.Store(nextRun.Unix())
+	return nil
+}
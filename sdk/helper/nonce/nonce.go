@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package nonce implements services issuing single-use nonces, which
+// unauthenticated endpoints can require to protect against the replay of
+// captured requests: a client first fetches a nonce and then includes it in
+// its request, which is only accepted if the nonce was issued by the service,
+// hasn't expired and wasn't redeemed before.
+package nonce
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// DefaultValidity is how long nonces are valid for when no validity is
+	// given to a service.
+	DefaultValidity = 15 * time.Minute
+
+	// nonceBytes is the number of random bytes in a nonce.
+	nonceBytes = 21
+)
+
+// Service issues and redeems single-use nonces. Services which don't keep
+// nonces in storage ignore the storage passed to their methods, which may
+// then be nil.
+type Service interface {
+	// Get issues a new nonce, returning it along with its expiration time.
+	Get(ctx context.Context, s logical.Storage) (string, time.Time, error)
+
+	// Redeem returns whether the nonce was issued by the service and is
+	// still valid. A nonce can only be redeemed once.
+	Redeem(ctx context.Context, s logical.Storage, nonce string) (bool, error)
+
+	// Tidy removes the expired nonces.
+	Tidy(ctx context.Context, s logical.Storage) error
+}
+
+// generate returns a new random nonce, encoded with URL-safe base64 so that
+// it can be used in headers and URLs as is.
+func generate() (string, error) {
+	data := make([]byte, nonceBytes)
+	if _, err := io.ReadFull(rand.Reader, data); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func validityOrDefault(validity time.Duration) time.Duration {
+	if validity <= 0 {
+		return DefaultValidity
+	}
+	return validity
+}
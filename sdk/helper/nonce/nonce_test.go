@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nonce
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServices(t *testing.T) {
+	services := map[string]func(validity time.Duration) Service{
+		"memory": NewMemoryService,
+		"storage": func(validity time.Duration) Service {
+			return NewStorageService("nonces", validity)
+		},
+	}
+
+	for name, newService := range services {
+		newService := newService
+
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			storage := &logical.InmemStorage{}
+			s := newService(0)
+
+			nonce, expiry, err := s.Get(ctx, storage)
+			require.NoError(t, err)
+			require.NotEmpty(t, nonce)
+			require.WithinDuration(t, time.Now().Add(DefaultValidity), expiry, time.Minute)
+
+			// Nonces can only be redeemed once.
+			ok, err := s.Redeem(ctx, storage, nonce)
+			require.NoError(t, err)
+			require.True(t, ok)
+			ok, err = s.Redeem(ctx, storage, nonce)
+			require.NoError(t, err)
+			require.False(t, ok)
+
+			// Unknown nonces can't be redeemed.
+			for _, unknown := range []string{"", "unknown"} {
+				ok, err = s.Redeem(ctx, storage, unknown)
+				require.NoError(t, err)
+				require.False(t, ok)
+			}
+
+			// Nonces can be redeemed in any order.
+			var nonces []string
+			for i := 0; i < 10; i++ {
+				nonce, _, err := s.Get(ctx, storage)
+				require.NoError(t, err)
+				nonces = append(nonces, nonce)
+			}
+			for i := len(nonces) - 1; i >= 0; i-- {
+				ok, err := s.Redeem(ctx, storage, nonces[i])
+				require.NoError(t, err)
+				require.True(t, ok)
+			}
+
+			// Concurrent redemptions of a nonce succeed once.
+			nonce, _, err = s.Get(ctx, storage)
+			require.NoError(t, err)
+			var wg sync.WaitGroup
+			var lock sync.Mutex
+			redeemed := 0
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					ok, err := s.Redeem(ctx, storage, nonce)
+					require.NoError(t, err)
+					if ok {
+						lock.Lock()
+						redeemed++
+						lock.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			require.Equal(t, 1, redeemed)
+
+			// Expired nonces can't be redeemed, and are removed by tidying.
+			s = newService(time.Millisecond)
+			expired, _, err := s.Get(ctx, storage)
+			require.NoError(t, err)
+			time.Sleep(10 * time.Millisecond)
+			require.NoError(t, s.Tidy(ctx, storage))
+			ok, err = s.Redeem(ctx, storage, expired)
+			require.NoError(t, err)
+			require.False(t, ok)
+
+			keys, err := storage.List(ctx, "nonces/")
+			require.NoError(t, err)
+			require.Empty(t, keys)
+		})
+	}
+}
+
+func TestStorageService_HashesNonces(t *testing.T) {
+	ctx := context.Background()
+	storage := &logical.InmemStorage{}
+	s := NewStorageService("nonces/", 0)
+
+	nonce, _, err := s.Get(ctx, storage)
+	require.NoError(t, err)
+
+	keys, err := storage.List(ctx, "nonces/")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.NotContains(t, keys[0], nonce)
+}
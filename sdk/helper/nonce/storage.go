@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nonce
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/helper/locksutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// Ensure that storageService implements the Service interface
+var _ Service = (*storageService)(nil)
+
+// storageService keeps the nonces it issues in the storage passed to its
+// methods, usually the storage of the backend using it. Since the storage is
+// shared by the nodes of the cluster, a nonce issued by one node can be
+// redeemed on any other. On performance standbys, where the storage is read
+// only, requests issuing or redeeming nonces are forwarded to the active node.
+//
+// Nonces are stored by their hash, so that the nonces can't be recovered by
+// reading the storage.
+type storageService struct {
+	prefix   string
+	validity time.Duration
+	locks    []*locksutil.LockEntry
+}
+
+type storedNonce struct {
+	Expiration time.Time `json:"expiration"`
+}
+
+// NewStorageService returns a service keeping the nonces it issues under the
+// prefix of the storage, which are valid for the given duration, or
+// DefaultValidity if zero.
+func NewStorageService(prefix string, validity time.Duration) Service {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &storageService{
+		prefix:   prefix,
+		validity: validityOrDefault(validity),
+		locks:    locksutil.CreateLocks(),
+	}
+}
+
+func (s *storageService) key(nonce string) string {
+	hash := sha256.Sum256([]byte(nonce))
+	return s.prefix + hex.EncodeToString(hash[:])
+}
+
+func (s *storageService) Get(ctx context.Context, storage logical.Storage) (string, time.Time, error) {
+	now := time.Now()
+	nonce, err := generate()
+	if err != nil {
+		return "", now, err
+	}
+
+	then := now.Add(s.validity)
+	entry, err := logical.StorageEntryJSON(s.key(nonce), &storedNonce{Expiration: then})
+	if err != nil {
+		return "", now, err
+	}
+	if err := storage.Put(ctx, entry); err != nil {
+		return "", now, err
+	}
+
+	return nonce, then, nil
+}
+
+func (s *storageService) Redeem(ctx context.Context, storage logical.Storage, nonce string) (bool, error) {
+	if nonce == "" {
+		return false, nil
+	}
+
+	key := s.key(nonce)
+
+	// Concurrent redemptions of the same nonce must not both succeed.
+	lock := locksutil.LockForKey(s.locks, key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	stored, err := s.read(ctx, storage, key)
+	if err != nil || stored == nil {
+		return false, err
+	}
+
+	if err := storage.Delete(ctx, key); err != nil {
+		return false, err
+	}
+
+	return time.Now().Before(stored.Expiration), nil
+}
+
+func (s *storageService) Tidy(ctx context.Context, storage logical.Storage) error {
+	keys, err := storage.List(ctx, s.prefix)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, hash := range keys {
+		key := s.prefix + hash
+
+		lock := locksutil.LockForKey(s.locks, key)
+		lock.Lock()
+		stored, err := s.read(ctx, storage, key)
+		if err == nil && stored != nil && now.After(stored.Expiration) {
+			err = storage.Delete(ctx, key)
+		}
+		lock.Unlock()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *storageService) read(ctx context.Context, storage logical.Storage, key string) (*storedNonce, error) {
+	entry, err := storage.Get(ctx, key)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	var stored storedNonce
+	if err := entry.DecodeJSON(&stored); err != nil {
+		return nil, fmt.Errorf("failed to decode nonce: %w", err)
+	}
+	return &stored, nil
+}
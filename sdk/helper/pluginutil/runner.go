@@ -47,6 +47,10 @@ type PluginClient interface {
 
 const MultiplexingCtxKey string = "multiplex_id"
 
+// RuntimeWASM is the runtime of the plugins that are WebAssembly modules run
+// in-process by Vault, rather than executables run with go-plugin.
+const RuntimeWASM = "wasm"
+
 // PluginRunner defines the metadata needed to run a plugin securely with
 // go-plugin.
 type PluginRunner struct {
@@ -58,9 +62,30 @@ type PluginRunner struct {
 	Env            []string                    `json:"env" structs:"env"`
 	Sha256         []byte                      `json:"sha256" structs:"sha256"`
 	Builtin        bool                        `json:"builtin" structs:"builtin"`
+	Runtime        string                      `json:"runtime,omitempty" structs:"runtime"`
+	MemoryLimit    int64                       `json:"memory_limit,omitempty" structs:"memory_limit"`
+	CPULimit       float64                     `json:"cpu_limit,omitempty" structs:"cpu_limit"`
 	BuiltinFactory func() (interface{}, error) `json:"-" structs:"-"`
 }
 
+// SetPluginInput is the input to register an external plugin in the catalog.
+type SetPluginInput struct {
+	Name    string
+	Type    consts.PluginType
+	Version string
+	Command string
+	Args    []string
+	Env     []string
+	Sha256  []byte
+	Runtime string
+
+	// MemoryLimit is the maximum memory in bytes the plugin process may use.
+	MemoryLimit int64
+
+	// CPULimit is the maximum number of CPUs the plugin process may use.
+	CPULimit float64
+}
+
 // Run takes a wrapper RunnerUtil instance along with the go-plugin parameters and
 // returns a configured plugin.Client with TLS Configured and a wrapping token set
 // on PluginUnwrapTokenEnv for plugin process consumption.
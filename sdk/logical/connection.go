@@ -16,6 +16,18 @@ type Connection struct {
 	// RemotePort is the network port that sent the request.
 	RemotePort int `json:"remote_port"`
 
+	// ProxyAddr is the network address of the upstream proxy that forwarded
+	// the connection using the PROXY protocol, if any. In that case
+	// RemoteAddr is the original client address.
+	ProxyAddr string `json:"proxy_addr,omitempty"`
+
+	// ProxyPort is the network port of the upstream proxy, if any.
+	ProxyPort int `json:"proxy_port,omitempty"`
+
+	// ProxyMetadata holds the known TLVs sent by the upstream proxy in a
+	// PROXY protocol v2 header.
+	ProxyMetadata map[string]string `json:"proxy_metadata,omitempty"`
+
 	// ConnState is the TLS connection state if applicable.
 	ConnState *tls.ConnectionState `sentinel:""`
 }
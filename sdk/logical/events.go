@@ -32,3 +32,11 @@ type EventType string
 type EventSender interface {
 	Send(ctx context.Context, eventType EventType, event *EventData) error
 }
+
+// EventSubscriber subscribes to the events sent to the common event bus.
+type EventSubscriber interface {
+	// Subscribe returns a channel receiving the events whose type matches the
+	// glob pattern. The subscription ends, and the channel is closed, when the
+	// context is canceled.
+	Subscribe(ctx context.Context, pattern string) (<-chan *EventReceived, error)
+}
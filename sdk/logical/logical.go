@@ -111,6 +111,10 @@ type BackendConfig struct {
 
 	// EventsSender provides a mechanism to interact with Vault events.
 	EventsSender EventSender
+
+	// EventsSubscriber provides a mechanism to subscribe to the events sent
+	// by Vault and its plugins.
+	EventsSubscriber EventSubscriber
 }
 
 // Factory is the factory function to create a logical backend.
@@ -159,6 +163,14 @@ type Paths struct {
 	// On standby nodes, like all storage write operations, this will trigger
 	// an ErrReadOnly return.
 	WriteForwardedStorage []string
+
+	// Streaming are the API paths whose requests are streamed. The body of
+	// update requests is not parsed into the request data; the backend reads
+	// it from Request.HTTPRequest.Body instead. The backend may also write the
+	// response to Request.ResponseWriter rather than returning it, which
+	// avoids buffering large responses. For external plugins, both are
+	// streamed over the plugin connection.
+	Streaming []string
 }
 
 type Auditor interface {
@@ -177,3 +189,12 @@ type PluginVersioner interface {
 }
 
 var EmptyPluginVersion = PluginVersion{""}
+
+// HealthChecker is an optional interface for backends to report their own
+// health, such as the reachability of the systems they manage. A nil error
+// means the backend is healthy; ErrUnsupportedOperation means the backend
+// does not implement a health check.
+type HealthChecker interface {
+	// HealthCheck checks the health of the backend
+	HealthCheck(context.Context, *HealthCheckRequest) error
+}
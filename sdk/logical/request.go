@@ -440,6 +440,13 @@ type InitializationRequest struct {
 	Storage Storage
 }
 
+// HealthCheckRequest stores the parameters and context of a HealthCheck()
+// call being made to a logical.Backend.
+type HealthCheckRequest struct {
+	// Storage can be used to read the configuration of the backend.
+	Storage Storage
+}
+
 type CustomHeader struct {
 	Name  string
 	Value string
@@ -109,6 +109,15 @@ type ExtendedSystemView interface {
 	ForwardGenericRequest(context.Context, *Request) (*Response, error)
 }
 
+// AttestationSystemView is implemented by the system view of builtin plugins,
+// letting them have Vault sign statements about the keys they hold.
+type AttestationSystemView interface {
+	// SignAttestation signs the statement with the attestation key of the
+	// cluster, returning the signature along with the DER encoded certificate
+	// of the attestation key.
+	SignAttestation(ctx context.Context, statement []byte) (signature []byte, certificate []byte, err error)
+}
+
 type PasswordGenerator func() (password string, err error)
 
 type StaticSystemView struct {
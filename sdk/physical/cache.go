@@ -5,7 +5,9 @@ package physical
 
 import (
 	"context"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
@@ -50,6 +52,28 @@ func cacheRefreshFromContext(ctx context.Context) bool {
 	return r
 }
 
+// CacheConfig is the configuration of a physical cache.
+type CacheConfig struct {
+	// Size is the maximum number of entries of the cache. If it is not set,
+	// DefaultCacheSize is used.
+	Size int
+
+	// TTL is how long the entries are cached. If it is not set, the entries
+	// are cached until they are evicted.
+	TTL time.Duration
+
+	// DisableNegativeCache disables the caching of the keys missing from the
+	// underlying backend.
+	DisableNegativeCache bool
+}
+
+// cacheEntry is the value stored in the LRU cache. A nil entry denotes a
+// key missing from the underlying backend.
+type cacheEntry struct {
+	entry      *Entry
+	expiration time.Time
+}
+
 // Cache is used to wrap an underlying physical backend
 // and provide an LRU cache layer on top. Most of the reads done by
 // Vault are for policy objects so there is a large read reduction
@@ -57,6 +81,9 @@ func cacheRefreshFromContext(ctx context.Context) bool {
 type Cache struct {
 	backend         Backend
 	lru             *lru.TwoQueueCache
+	size            int
+	ttl             time.Duration
+	negativeCache   bool
 	locks           []*locksutil.LockEntry
 	logger          log.Logger
 	enabled         *uint32
@@ -74,6 +101,7 @@ type TransactionalCache struct {
 var (
 	_ ToggleablePurgemonster = (*Cache)(nil)
 	_ ToggleablePurgemonster = (*TransactionalCache)(nil)
+	_ PrefixPurgemonster     = (*Cache)(nil)
 	_ Backend                = (*Cache)(nil)
 	_ Transactional          = (*TransactionalCache)(nil)
 )
@@ -81,9 +109,15 @@ var (
 // NewCache returns a physical cache of the given size.
 // If no size is provided, the default size is used.
 func NewCache(b Backend, size int, logger log.Logger, metricSink metrics.MetricSink) *Cache {
+	return NewCacheWithConfig(b, CacheConfig{Size: size}, logger, metricSink)
+}
+
+// NewCacheWithConfig returns a physical cache with the given configuration.
+func NewCacheWithConfig(b Backend, config CacheConfig, logger log.Logger, metricSink metrics.MetricSink) *Cache {
 	if logger.IsDebug() {
-		logger.Debug("creating LRU cache", "size", size)
+		logger.Debug("creating LRU cache", "size", config.Size, "ttl", config.TTL, "negative_cache", !config.DisableNegativeCache)
 	}
+	size := config.Size
 	if size <= 0 {
 		size = DefaultCacheSize
 	}
@@ -93,10 +127,13 @@ func NewCache(b Backend, size int, logger log.Logger, metricSink metrics.MetricS
 
 	cache, _ := lru.New2Q(size)
 	c := &Cache{
-		backend: b,
-		lru:     cache,
-		locks:   locksutil.CreateLocks(),
-		logger:  logger,
+		backend:       b,
+		lru:           cache,
+		size:          size,
+		ttl:           config.TTL,
+		negativeCache: !config.DisableNegativeCache,
+		locks:         locksutil.CreateLocks(),
+		logger:        logger,
 		// This fails safe.
 		enabled:         new(uint32),
 		cacheExceptions: pm,
@@ -106,8 +143,12 @@ func NewCache(b Backend, size int, logger log.Logger, metricSink metrics.MetricS
 }
 
 func NewTransactionalCache(b Backend, size int, logger log.Logger, metricSink metrics.MetricSink) *TransactionalCache {
+	return NewTransactionalCacheWithConfig(b, CacheConfig{Size: size}, logger, metricSink)
+}
+
+func NewTransactionalCacheWithConfig(b Backend, config CacheConfig, logger log.Logger, metricSink metrics.MetricSink) *TransactionalCache {
 	c := &TransactionalCache{
-		Cache:         NewCache(b, size, logger, metricSink),
+		Cache:         NewCacheWithConfig(b, config, logger, metricSink),
 		Transactional: b.(Transactional),
 	}
 	return c
@@ -142,6 +183,48 @@ func (c *Cache) Purge(ctx context.Context) {
 	c.lru.Purge()
 }
 
+// PurgePrefix is used to clear the entries of the cache with the given key
+// prefix. It returns the number of cleared entries.
+func (c *Cache) PurgePrefix(ctx context.Context, prefix string) int {
+	var purged int
+	for _, raw := range c.lru.Keys() {
+		key, ok := raw.(string)
+		if !ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		lock := locksutil.LockForKey(c.locks, key)
+		lock.Lock()
+		if c.lru.Contains(key) {
+			c.lru.Remove(key)
+			purged++
+		}
+		lock.Unlock()
+	}
+
+	return purged
+}
+
+// add caches the given entry, which may be nil for missing keys. This
+// should be called with the lock of the key held.
+func (c *Cache) add(key string, entry *Entry) {
+	value := &cacheEntry{entry: entry}
+	if c.ttl > 0 {
+		value.expiration = time.Now().Add(c.ttl)
+	}
+
+	// The LRU cache doesn't report its evictions, a full cache evicts an
+	// entry when a new key is added. This is approximate, as other keys may
+	// be added or removed concurrently.
+	evicted := c.lru.Len() >= c.size && !c.lru.Contains(key)
+
+	c.lru.Add(key, value)
+
+	if evicted {
+		c.metricSink.IncrCounter([]string{"cache", "evict"}, 1)
+	}
+}
+
 func (c *Cache) Put(ctx context.Context, entry *Entry) error {
 	if entry != nil && !c.ShouldCache(entry.Key) {
 		return c.backend.Put(ctx, entry)
@@ -153,7 +236,7 @@ func (c *Cache) Put(ctx context.Context, entry *Entry) error {
 
 	err := c.backend.Put(ctx, entry)
 	if err == nil {
-		c.lru.Add(entry.Key, entry)
+		c.add(entry.Key, entry)
 		c.metricSink.IncrCounter([]string{"cache", "write"}, 1)
 	}
 	return err
@@ -171,11 +254,13 @@ func (c *Cache) Get(ctx context.Context, key string) (*Entry, error) {
 	// Check the LRU first
 	if !cacheRefreshFromContext(ctx) {
 		if raw, ok := c.lru.Get(key); ok {
-			if raw == nil {
-				return nil, nil
+			value := raw.(*cacheEntry)
+			if value.expiration.IsZero() || time.Now().Before(value.expiration) {
+				c.metricSink.IncrCounter([]string{"cache", "hit"}, 1)
+				return value.entry, nil
 			}
-			c.metricSink.IncrCounter([]string{"cache", "hit"}, 1)
-			return raw.(*Entry), nil
+			c.lru.Remove(key)
+			c.metricSink.IncrCounter([]string{"cache", "expire"}, 1)
 		}
 	}
 
@@ -186,8 +271,12 @@ func (c *Cache) Get(ctx context.Context, key string) (*Entry, error) {
 		return nil, err
 	}
 
-	// Cache the result, even if nil
-	c.lru.Add(key, ent)
+	// Cache the result, even if nil, unless negative caching is disabled
+	if ent != nil || c.negativeCache {
+		c.add(key, ent)
+	} else {
+		c.lru.Remove(key)
+	}
 
 	return ent, nil
 }
@@ -251,7 +340,7 @@ func (c *TransactionalCache) Transaction(ctx context.Context, txns []*TxnEntry)
 
 		switch txn.Operation {
 		case PutOperation:
-			c.lru.Add(txn.Entry.Key, txn.Entry)
+			c.add(txn.Entry.Key, txn.Entry)
 			c.metricSink.IncrCounter([]string{"cache", "write"}, 1)
 		case DeleteOperation:
 			c.lru.Remove(txn.Entry.Key)
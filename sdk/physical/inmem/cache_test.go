@@ -6,6 +6,7 @@ package inmem
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
@@ -331,3 +332,162 @@ func TestCache_Refresh(t *testing.T) {
 		t.Fatalf("expected value baz, got %s", string(r.Value))
 	}
 }
+
+func TestCache_TTL(t *testing.T) {
+	logger := logging.NewVaultLogger(log.Debug)
+
+	inm, err := NewInmem(nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := physical.NewCacheWithConfig(inm, physical.CacheConfig{TTL: 100 * time.Millisecond}, logger, &metrics.BlackholeSink{})
+	cache.SetEnabled(true)
+
+	ent := &physical.Entry{
+		Key:   "foo",
+		Value: []byte("bar"),
+	}
+	err = cache.Put(context.Background(), ent)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Delete from under
+	err = inm.Delete(context.Background(), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read should work until the entry expires
+	out, err := cache.Get(context.Background(), "foo")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out == nil {
+		t.Fatalf("should have key")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	out, err = cache.Get(context.Background(), "foo")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out != nil {
+		t.Fatalf("should not have key")
+	}
+}
+
+func TestCache_DisableNegativeCache(t *testing.T) {
+	logger := logging.NewVaultLogger(log.Debug)
+
+	inm, err := NewInmem(nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := physical.NewCacheWithConfig(inm, physical.CacheConfig{DisableNegativeCache: true}, logger, &metrics.BlackholeSink{})
+	cache.SetEnabled(true)
+
+	out, err := cache.Get(context.Background(), "foo")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out != nil {
+		t.Fatalf("should not have key")
+	}
+
+	// Write from under
+	err = inm.Put(context.Background(), &physical.Entry{
+		Key:   "foo",
+		Value: []byte("bar"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read should work as the missing key was not cached
+	out, err = cache.Get(context.Background(), "foo")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out == nil {
+		t.Fatalf("should have key")
+	}
+}
+
+func TestCache_PurgePrefix(t *testing.T) {
+	logger := logging.NewVaultLogger(log.Debug)
+
+	inm, err := NewInmem(nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := physical.NewCache(inm, 0, logger, &metrics.BlackholeSink{})
+	cache.SetEnabled(true)
+
+	for _, key := range []string{"foo/a", "foo/b", "bar/a"} {
+		err = cache.Put(context.Background(), &physical.Entry{
+			Key:   key,
+			Value: []byte("baz"),
+		})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Delete from under
+		err = inm.Delete(context.Background(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if purged := cache.PurgePrefix(context.Background(), "foo/"); purged != 2 {
+		t.Fatalf("expected 2 purged entries, got %d", purged)
+	}
+
+	for key, cached := range map[string]bool{"foo/a": false, "foo/b": false, "bar/a": true} {
+		out, err := cache.Get(context.Background(), key)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if (out != nil) != cached {
+			t.Fatalf("bad: %s: expected cached %t", key, cached)
+		}
+	}
+}
+
+func TestCache_Metrics(t *testing.T) {
+	logger := logging.NewVaultLogger(log.Debug)
+
+	inm, err := NewInmem(nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := metrics.NewInmemSink(time.Minute, time.Minute)
+	cache := physical.NewCacheWithConfig(inm, physical.CacheConfig{Size: 2}, logger, sink)
+	cache.SetEnabled(true)
+
+	for _, key := range []string{"foo", "bar", "baz"} {
+		err = cache.Put(context.Background(), &physical.Entry{
+			Key:   key,
+			Value: []byte(key),
+		})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	// The first entry has been evicted
+	for _, key := range []string{"foo", "baz"} {
+		if _, err := cache.Get(context.Background(), key); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	counters := sink.Data()[0].Counters
+	for name, expected := range map[string]int{"cache.write": 3, "cache.evict": 2, "cache.hit": 1, "cache.miss": 1} {
+		if counter := counters[name]; counter.Count != expected {
+			t.Fatalf("bad: %s: expected %d, got %d", name, expected, counter.Count)
+		}
+	}
+}
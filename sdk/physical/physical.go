@@ -68,6 +68,12 @@ type ToggleablePurgemonster interface {
 	SetEnabled(bool)
 }
 
+// PrefixPurgemonster is an optional interface for backends that can purge
+// their cached entries by key prefix.
+type PrefixPurgemonster interface {
+	PurgePrefix(ctx context.Context, prefix string) int
+}
+
 // RedirectDetect is an optional interface that an HABackend
 // can implement. If they do, a redirect address can be automatically
 // detected.
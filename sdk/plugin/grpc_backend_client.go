@@ -80,6 +80,38 @@ func (b *backendGRPCPluginClient) Initialize(ctx context.Context, _ *logical.Ini
 	return nil
 }
 
+func (b *backendGRPCPluginClient) HealthCheck(ctx context.Context, _ *logical.HealthCheckRequest) error {
+	if b.metadataMode {
+		return ErrClientInMetadataMode
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	quitCh := pluginutil.CtxCancelIfCanceled(cancel, b.doneCtx)
+	defer close(quitCh)
+	defer cancel()
+
+	reply, err := b.client.HealthCheck(ctx, &pb.HealthCheckArgs{}, largeMsgGRPCCallOpts...)
+	if err != nil {
+		if b.doneCtx.Err() != nil {
+			return ErrPluginShutdown
+		}
+
+		// Plugins built against an older SDK don't have HealthCheck
+		// implemented, which is the same as not providing a health check.
+		grpcStatus, ok := status.FromError(err)
+		if ok && grpcStatus.Code() == codes.Unimplemented {
+			return logical.ErrUnsupportedOperation
+		}
+
+		return err
+	}
+	if reply.Err != nil {
+		return pb.ProtoErrToErr(reply.Err)
+	}
+
+	return nil
+}
+
 func (b *backendGRPCPluginClient) HandleRequest(ctx context.Context, req *logical.Request) (*logical.Response, error) {
 	if b.metadataMode {
 		return nil, ErrClientInMetadataMode
@@ -90,6 +122,11 @@ func (b *backendGRPCPluginClient) HandleRequest(ctx context.Context, req *logica
 	defer close(quitCh)
 	defer cancel()
 
+	// Requests to the streaming paths are given a response writer
+	if req.ResponseWriter != nil {
+		return b.handleStreamingRequest(ctx, req)
+	}
+
 	protoReq, err := pb.LogicalRequestToProtoRequest(req)
 	if err != nil {
 		return nil, err
@@ -116,6 +153,76 @@ func (b *backendGRPCPluginClient) HandleRequest(ctx context.Context, req *logica
 	return resp, nil
 }
 
+// handleStreamingRequest handles a request to one of the streaming paths of
+// the plugin. The request body is sent to the plugin as it's read, and the
+// response the plugin writes is written to the response writer as it's
+// received.
+func (b *backendGRPCPluginClient) handleStreamingRequest(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+	protoReq, err := pb.LogicalRequestToProtoRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := b.client.HandleStreamingRequest(ctx, largeMsgGRPCCallOpts...)
+	if err != nil {
+		if b.doneCtx.Err() != nil {
+			return nil, ErrPluginShutdown
+		}
+
+		return nil, err
+	}
+
+	hasBody := req.HTTPRequest != nil && req.HTTPRequest.Body != nil
+	if err := stream.Send(&pb.HandleStreamingRequestArgs{
+		Request: protoReq,
+		HasBody: hasBody,
+	}); err != nil {
+		return nil, err
+	}
+
+	// The body is sent concurrently, as the plugin may start writing the
+	// response before having read it all. The stream is canceled along with
+	// the context once the request is handled, which stops the sending.
+	if hasBody {
+		go func() {
+			if err := sendStreamingRequestBody(stream, req.HTTPRequest.Body); err != nil {
+				b.Logger().Debug("failed to send request body to plugin", "error", err)
+			}
+		}()
+	} else if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	for {
+		reply, err := stream.Recv()
+		if err != nil {
+			if b.doneCtx.Err() != nil {
+				return nil, ErrPluginShutdown
+			}
+
+			return nil, err
+		}
+
+		if err := writeStreamingReply(req.ResponseWriter, reply); err != nil {
+			return nil, err
+		}
+
+		if !reply.Done {
+			continue
+		}
+
+		resp, err := pb.ProtoResponseToLogicalResponse(reply.Response)
+		if err != nil {
+			return nil, err
+		}
+		if reply.Err != nil {
+			return resp, pb.ProtoErrToErr(reply.Err)
+		}
+
+		return resp, nil
+	}
+}
+
 func (b *backendGRPCPluginClient) SpecialPaths() *logical.Paths {
 	reply, err := b.client.SpecialPaths(b.doneCtx, &pb.Empty{})
 	if err != nil {
@@ -132,6 +239,7 @@ func (b *backendGRPCPluginClient) SpecialPaths() *logical.Paths {
 		LocalStorage:          reply.Paths.LocalStorage,
 		SealWrapStorage:       reply.Paths.SealWrapStorage,
 		WriteForwardedStorage: reply.Paths.WriteForwardedStorage,
+		Streaming:             reply.Paths.Streaming,
 	}
 }
 
@@ -232,7 +340,8 @@ func (b *backendGRPCPluginClient) Setup(ctx context.Context, config *logical.Bac
 	}
 
 	events := &GRPCEventsServer{
-		impl: config.EventsSender,
+		impl:       config.EventsSender,
+		subscriber: config.EventsSubscriber,
 	}
 
 	// Register the server in this closure.
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"sync"
 
 	log "github.com/hashicorp/go-hclog"
@@ -98,12 +99,13 @@ func (b *backendGRPCPluginServer) Setup(ctx context.Context, args *pb.SetupArgs)
 	events := newGRPCEventsClient(brokeredClient)
 
 	config := &logical.BackendConfig{
-		StorageView:  storage,
-		Logger:       b.logger,
-		System:       sysView,
-		Config:       args.Config,
-		BackendUUID:  args.BackendUUID,
-		EventsSender: events,
+		StorageView:      storage,
+		Logger:           b.logger,
+		System:           sysView,
+		Config:           args.Config,
+		BackendUUID:      args.BackendUUID,
+		EventsSender:     events,
+		EventsSubscriber: events,
 	}
 
 	// Call the underlying backend factory after shims have been created
@@ -155,6 +157,54 @@ func (b *backendGRPCPluginServer) HandleRequest(ctx context.Context, args *pb.Ha
 	}, nil
 }
 
+// HandleStreamingRequest handles a request to one of the streaming paths of
+// the backend. The backend reads the request body from the stream through
+// the request's HTTPRequest, and writes the response to the stream through
+// the request's ResponseWriter.
+func (b *backendGRPCPluginServer) HandleStreamingRequest(stream pb.Backend_HandleStreamingRequestServer) error {
+	ctx := stream.Context()
+	backend, brokeredClient, err := b.getBackendAndBrokeredClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if pluginutil.InMetadataMode() {
+		return ErrServerInMetadataMode
+	}
+
+	args, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	logicalReq, err := pb.ProtoRequestToLogicalRequest(args.Request)
+	if err != nil {
+		return err
+	}
+
+	logicalReq.Storage = newGRPCStorageClient(brokeredClient)
+	logicalReq.ResponseWriter = logical.NewHTTPResponseWriter(newGRPCStreamingResponseWriter(stream))
+	if args.HasBody {
+		logicalReq.HTTPRequest = (&http.Request{
+			Header: http.Header(logicalReq.Headers),
+			Body:   &grpcStreamingRequestBody{stream: stream},
+		}).WithContext(ctx)
+	}
+
+	resp, respErr := backend.HandleRequest(ctx, logicalReq)
+
+	pbResp, err := pb.LogicalResponseToProtoResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&pb.HandleStreamingRequestReply{
+		Done:     true,
+		Response: pbResp,
+		Err:      pb.ErrToProtoErr(respErr),
+	})
+}
+
 func (b *backendGRPCPluginServer) Initialize(ctx context.Context, _ *pb.InitializeArgs) (*pb.InitializeReply, error) {
 	backend, brokeredClient, err := b.getBackendAndBrokeredClient(ctx)
 	if err != nil {
@@ -176,6 +226,34 @@ func (b *backendGRPCPluginServer) Initialize(ctx context.Context, _ *pb.Initiali
 	}, nil
 }
 
+func (b *backendGRPCPluginServer) HealthCheck(ctx context.Context, _ *pb.HealthCheckArgs) (*pb.HealthCheckReply, error) {
+	backend, brokeredClient, err := b.getBackendAndBrokeredClient(ctx)
+	if err != nil {
+		return &pb.HealthCheckReply{}, err
+	}
+
+	if pluginutil.InMetadataMode() {
+		return &pb.HealthCheckReply{}, ErrServerInMetadataMode
+	}
+
+	checker, ok := backend.(logical.HealthChecker)
+	if !ok {
+		return &pb.HealthCheckReply{
+			Err: pb.ErrToProtoErr(logical.ErrUnsupportedOperation),
+		}, nil
+	}
+
+	req := &logical.HealthCheckRequest{
+		Storage: newGRPCStorageClient(brokeredClient),
+	}
+
+	respErr := checker.HealthCheck(ctx, req)
+
+	return &pb.HealthCheckReply{
+		Err: pb.ErrToProtoErr(respErr),
+	}, nil
+}
+
 func (b *backendGRPCPluginServer) SpecialPaths(ctx context.Context, args *pb.Empty) (*pb.SpecialPathsReply, error) {
 	backend, _, err := b.getBackendAndBrokeredClient(ctx)
 	if err != nil {
@@ -196,6 +274,7 @@ func (b *backendGRPCPluginServer) SpecialPaths(ctx context.Context, args *pb.Emp
 			LocalStorage:          paths.LocalStorage,
 			SealWrapStorage:       paths.SealWrapStorage,
 			WriteForwardedStorage: paths.WriteForwardedStorage,
+			Streaming:             paths.Streaming,
 		},
 	}, nil
 }
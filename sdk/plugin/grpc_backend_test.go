@@ -4,8 +4,13 @@
 package plugin
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -150,6 +155,37 @@ func TestGRPCBackendPlugin_Initialize(t *testing.T) {
 	}
 }
 
+func TestGRPCBackendPlugin_HealthCheck(t *testing.T) {
+	b, cleanup := testGRPCBackend(t)
+	defer cleanup()
+
+	checker, ok := b.(logical.HealthChecker)
+	if !ok {
+		t.Fatal("expected the plugin client to implement logical.HealthChecker")
+	}
+
+	err := checker.HealthCheck(context.Background(), &logical.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.CreateOperation,
+		Path:      "kv/unhealthy",
+		Data: map[string]interface{}{
+			"value": "upstream unreachable",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = checker.HealthCheck(context.Background(), &logical.HealthCheckRequest{})
+	if err == nil || err.Error() != "upstream unreachable" {
+		t.Fatalf("expected the health check to fail, got: %v", err)
+	}
+}
+
 func TestGRPCBackendPlugin_Version(t *testing.T) {
 	b, cleanup := testGRPCBackend(t)
 	defer cleanup()
@@ -203,3 +239,62 @@ func testGRPCBackend(t *testing.T) (logical.Backend, func()) {
 
 	return b, cleanup
 }
+
+func TestGRPCBackendPlugin_HandleStreamingRequest(t *testing.T) {
+	b, cleanup := testGRPCBackend(t)
+	defer cleanup()
+
+	if paths := b.SpecialPaths(); !reflect.DeepEqual(paths.Streaming, []string{"stream"}) {
+		t.Fatalf("bad: %#v", paths)
+	}
+
+	// The response is larger than a chunk
+	w := httptest.NewRecorder()
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation:      logical.ReadOperation,
+		Path:           "stream",
+		Data:           map[string]interface{}{"size": 3*streamingChunkSize + 1},
+		ResponseWriter: logical.NewHTTPResponseWriter(w),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/octet-stream" {
+		t.Fatalf("bad: %d %v", w.Code, w.Header())
+	}
+	if !bytes.Equal(w.Body.Bytes(), bytes.Repeat([]byte("a"), 3*streamingChunkSize+1)) {
+		t.Fatalf("bad body of %d bytes", w.Body.Len())
+	}
+
+	// The request body is streamed to the plugin
+	body := make([]byte, 2*streamingChunkSize+1)
+	if _, err := rand.Read(body); err != nil {
+		t.Fatal(err)
+	}
+	w = httptest.NewRecorder()
+	_, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation:      logical.UpdateOperation,
+		Path:           "stream",
+		HTTPRequest:    httptest.NewRequest(http.MethodPost, "/v1/mock/stream", bytes.NewReader(body)),
+		ResponseWriter: logical.NewHTTPResponseWriter(w),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusCreated || !bytes.Equal(w.Body.Bytes(), body) {
+		t.Fatalf("bad: %d, body of %d bytes", w.Code, w.Body.Len())
+	}
+
+	// Errors are returned as usual
+	_, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation:      logical.UpdateOperation,
+		Path:           "stream",
+		ResponseWriter: logical.NewHTTPResponseWriter(httptest.NewRecorder()),
+	})
+	if err == nil || err.Error() != "request is not streamed" {
+		t.Fatalf("bad: %v", err)
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/hashicorp/vault/sdk/plugin/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func newGRPCEventsClient(conn *grpc.ClientConn) *GRPCEventsClient {
@@ -21,7 +24,10 @@ type GRPCEventsClient struct {
 	client pb.EventsClient
 }
 
-var _ logical.EventSender = (*GRPCEventsClient)(nil)
+var (
+	_ logical.EventSender     = (*GRPCEventsClient)(nil)
+	_ logical.EventSubscriber = (*GRPCEventsClient)(nil)
+)
 
 func (s *GRPCEventsClient) Send(ctx context.Context, eventType logical.EventType, event *logical.EventData) error {
 	_, err := s.client.SendEvent(ctx, &pb.SendEventRequest{
@@ -31,9 +37,51 @@ func (s *GRPCEventsClient) Send(ctx context.Context, eventType logical.EventType
 	return err
 }
 
+// Subscribe subscribes to the events sent in the namespace of the plugin
+// whose type matches the pattern. The events are pushed by Vault over the
+// stream until the context is canceled.
+func (s *GRPCEventsClient) Subscribe(ctx context.Context, pattern string) (<-chan *logical.EventReceived, error) {
+	stream, err := s.client.SubscribeEvents(ctx, &pb.SubscribeEventsRequest{
+		Pattern: pattern,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for the subscription to be acknowledged, so that it's active
+	// once this returns
+	md, err := stream.Header()
+	if err != nil {
+		return nil, err
+	}
+	if md == nil {
+		// The stream ended without the acknowledgement, Recv returns why
+		_, err := stream.Recv()
+		return nil, err
+	}
+
+	ch := make(chan *logical.EventReceived)
+	go func() {
+		defer close(ch)
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
 type GRPCEventsServer struct {
 	pb.UnimplementedEventsServer
-	impl logical.EventSender
+	impl       logical.EventSender
+	subscriber logical.EventSubscriber
 }
 
 func (s *GRPCEventsServer) SendEvent(ctx context.Context, req *pb.SendEventRequest) (*pb.Empty, error) {
@@ -47,3 +95,34 @@ func (s *GRPCEventsServer) SendEvent(ctx context.Context, req *pb.SendEventReque
 	}
 	return &pb.Empty{}, nil
 }
+
+func (s *GRPCEventsServer) SubscribeEvents(req *pb.SubscribeEventsRequest, stream pb.Events_SubscribeEventsServer) error {
+	if s.subscriber == nil {
+		return status.Error(codes.Unimplemented, "event subscriptions are not enabled")
+	}
+
+	ctx := stream.Context()
+	ch, err := s.subscriber.Subscribe(ctx, req.Pattern)
+	if err != nil {
+		return err
+	}
+
+	// Acknowledge the subscription
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
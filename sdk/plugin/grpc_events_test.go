@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/plugin/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testEventSubscriber struct {
+	pattern string
+	events  chan *logical.EventReceived
+}
+
+func (s *testEventSubscriber) Subscribe(ctx context.Context, pattern string) (<-chan *logical.EventReceived, error) {
+	s.pattern = pattern
+	return s.events, nil
+}
+
+func TestEvents_GRPC_subscribe(t *testing.T) {
+	subscriber := &testEventSubscriber{events: make(chan *logical.EventReceived)}
+	client, _ := plugin.TestGRPCConn(t, func(s *grpc.Server) {
+		pb.RegisterEventsServer(s, &GRPCEventsServer{
+			subscriber: subscriber,
+		})
+	})
+	defer client.Close()
+	events := newGRPCEventsClient(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := events.Subscribe(ctx, "lease/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subscriber.pattern != "lease/*" {
+		t.Fatalf("bad pattern: %q", subscriber.pattern)
+	}
+
+	go func() {
+		subscriber.events <- &logical.EventReceived{EventType: "lease/revoked"}
+	}()
+	select {
+	case event := <-ch:
+		if event.EventType != "lease/revoked" {
+			t.Fatalf("bad event: %#v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	// The channel is closed once the subscription ends
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("expected channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the channel to be closed")
+	}
+}
+
+func TestEvents_GRPC_subscribeUnimplemented(t *testing.T) {
+	client, _ := plugin.TestGRPCConn(t, func(s *grpc.Server) {
+		pb.RegisterEventsServer(s, &GRPCEventsServer{})
+	})
+	defer client.Close()
+	events := newGRPCEventsClient(client)
+
+	_, err := events.Subscribe(context.Background(), "*")
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected unimplemented error, got: %v", err)
+	}
+}
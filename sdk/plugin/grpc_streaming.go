@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plugin
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/plugin/pb"
+)
+
+// streamingChunkSize is the maximum size of the chunks of the request and
+// response bodies sent over a streaming request.
+const streamingChunkSize = 1024 * 1024
+
+// sendStreamingRequestBody sends the request body to the plugin in chunks,
+// and closes the sending side of the stream once it's fully read.
+func sendStreamingRequestBody(stream pb.Backend_HandleStreamingRequestClient, body io.Reader) error {
+	buf := make([]byte, streamingChunkSize)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.HandleStreamingRequestArgs{Body: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return stream.CloseSend()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeStreamingReply writes the status code, headers and body chunk carried
+// by a reply to the response writer.
+func writeStreamingReply(w *logical.HTTPResponseWriter, reply *pb.HandleStreamingRequestReply) error {
+	if reply.StatusCode != 0 {
+		for k, v := range reply.Headers {
+			w.Header()[k] = v.Header
+		}
+		w.WriteHeader(int(reply.StatusCode))
+	}
+
+	if len(reply.Body) == 0 {
+		return nil
+	}
+	if _, err := w.Write(reply.Body); err != nil {
+		return err
+	}
+
+	// Flush each chunk so that the response is streamed to the client
+	// rather than buffered
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+	if !ok {
+		nw, ok := w.ResponseWriter.(logical.WrappingResponseWriter)
+		if !ok {
+			return nil
+		}
+		if flusher, ok = nw.Wrapped().(http.Flusher); !ok {
+			return nil
+		}
+	}
+	flusher.Flush()
+	return nil
+}
+
+// grpcStreamingRequestBody is the body of a streaming request on the plugin
+// side, reading the chunks sent by Vault from the stream.
+type grpcStreamingRequestBody struct {
+	stream pb.Backend_HandleStreamingRequestServer
+	buf    []byte
+	err    error
+}
+
+var _ io.ReadCloser = (*grpcStreamingRequestBody)(nil)
+
+func (b *grpcStreamingRequestBody) Read(p []byte) (int, error) {
+	for len(b.buf) == 0 {
+		if b.err != nil {
+			return 0, b.err
+		}
+
+		args, err := b.stream.Recv()
+		if err != nil {
+			b.err = err
+			continue
+		}
+		b.buf = args.Body
+	}
+
+	n := copy(p, b.buf)
+	b.buf = b.buf[n:]
+	return n, nil
+}
+
+func (b *grpcStreamingRequestBody) Close() error {
+	return nil
+}
+
+// grpcStreamingResponseWriter is the response writer of a streaming request
+// on the plugin side, sending what the backend writes to Vault.
+type grpcStreamingResponseWriter struct {
+	stream      pb.Backend_HandleStreamingRequestServer
+	header      http.Header
+	wroteHeader bool
+}
+
+var (
+	_ http.ResponseWriter = (*grpcStreamingResponseWriter)(nil)
+	_ http.Flusher        = (*grpcStreamingResponseWriter)(nil)
+)
+
+func newGRPCStreamingResponseWriter(stream pb.Backend_HandleStreamingRequestServer) *grpcStreamingResponseWriter {
+	return &grpcStreamingResponseWriter{
+		stream: stream,
+		header: make(http.Header),
+	}
+}
+
+func (w *grpcStreamingResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *grpcStreamingResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	headers := make(map[string]*pb.Header, len(w.header))
+	for k, v := range w.header {
+		headers[k] = &pb.Header{Header: v}
+	}
+	// The error is returned by the following writes, or by sending the final
+	// reply of the request.
+	w.stream.Send(&pb.HandleStreamingRequestReply{
+		StatusCode: int32(statusCode),
+		Headers:    headers,
+	})
+}
+
+func (w *grpcStreamingResponseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > streamingChunkSize {
+			chunk = chunk[:streamingChunkSize]
+		}
+		if err := w.stream.Send(&pb.HandleStreamingRequestReply{Body: chunk}); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// Flush is a no-op; every write is sent to Vault, which flushes it to the
+// client.
+func (w *grpcStreamingResponseWriter) Flush() {}
@@ -5,6 +5,7 @@ package mock
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"github.com/hashicorp/vault/sdk/framework"
@@ -53,16 +54,21 @@ func Backend() *backend {
 				pathInternal(&b),
 				pathSpecial(&b),
 				pathRaw(&b),
+				pathStream(&b),
 			},
 		),
 		PathsSpecial: &logical.Paths{
 			Unauthenticated: []string{
 				"special",
 			},
+			Streaming: []string{
+				"stream",
+			},
 		},
-		Secrets:     []*framework.Secret{},
-		Invalidate:  b.invalidate,
-		BackendType: logical.TypeLogical,
+		Secrets:         []*framework.Secret{},
+		Invalidate:      b.invalidate,
+		HealthCheckFunc: b.healthCheck,
+		BackendType:     logical.TypeLogical,
 	}
 	b.internal = "bar"
 	b.RunningVersion = "v0.0.0+mock"
@@ -85,3 +91,16 @@ func (b *backend) invalidate(ctx context.Context, key string) {
 		b.internal = ""
 	}
 }
+
+// healthCheck is used to test HealthCheck. The backend reports itself as
+// unhealthy while the "kv/unhealthy" key is set, using its value as reason.
+func (b *backend) healthCheck(ctx context.Context, req *logical.HealthCheckRequest) error {
+	entry, err := req.Storage.Get(ctx, "kv/unhealthy")
+	if err != nil {
+		return err
+	}
+	if entry != nil {
+		return errors.New(string(entry.Value))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package mock
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// pathStream is used to test streaming requests. Reads stream a response of
+// the requested size, and writes stream the request body back.
+func pathStream(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "stream",
+		Fields: map[string]*framework.FieldSchema{
+			"size": {
+				Type:        framework.TypeInt,
+				Description: "Size of the response",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   b.pathStreamRead,
+			logical.UpdateOperation: b.pathStreamWrite,
+		},
+	}
+}
+
+func (b *backend) pathStreamRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	if req.ResponseWriter == nil {
+		return nil, errors.New("response writer is not set")
+	}
+
+	req.ResponseWriter.Header().Set("Content-Type", "application/octet-stream")
+	req.ResponseWriter.WriteHeader(http.StatusOK)
+	_, err := req.ResponseWriter.Write(bytes.Repeat([]byte("a"), data.Get("size").(int)))
+	return nil, err
+}
+
+func (b *backend) pathStreamWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	if req.ResponseWriter == nil || req.HTTPRequest == nil {
+		return nil, errors.New("request is not streamed")
+	}
+
+	req.ResponseWriter.Header().Set("Content-Type", "application/octet-stream")
+	req.ResponseWriter.WriteHeader(http.StatusCreated)
+	_, err := io.Copy(req.ResponseWriter, req.HTTPRequest.Body)
+	return nil, err
+}
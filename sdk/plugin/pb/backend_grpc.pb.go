@@ -4,6 +4,7 @@ package pb
 
 import (
 	context "context"
+	logical "github.com/hashicorp/vault/sdk/logical"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -53,6 +54,14 @@ type BackendClient interface {
 	Initialize(ctx context.Context, in *InitializeArgs, opts ...grpc.CallOption) (*InitializeReply, error)
 	// Type returns the BackendType for the particular backend
 	Type(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypeReply, error)
+	// HandleStreamingRequest is used to handle a request to one of the
+	// streaming paths. The request body is streamed to the plugin, and the
+	// response the plugin writes to the response writer is streamed back.
+	HandleStreamingRequest(ctx context.Context, opts ...grpc.CallOption) (Backend_HandleStreamingRequestClient, error)
+	// HealthCheck is invoked when an operator requests the health of the
+	// mounts to allow the backend to report on the systems it manages.
+	// Backends without a health check reply with ErrUnsupportedOperation.
+	HealthCheck(ctx context.Context, in *HealthCheckArgs, opts ...grpc.CallOption) (*HealthCheckReply, error)
 }
 
 type backendClient struct {
@@ -135,6 +144,46 @@ func (c *backendClient) Type(ctx context.Context, in *Empty, opts ...grpc.CallOp
 	return out, nil
 }
 
+func (c *backendClient) HandleStreamingRequest(ctx context.Context, opts ...grpc.CallOption) (Backend_HandleStreamingRequestClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Backend_ServiceDesc.Streams[0], "/pb.Backend/HandleStreamingRequest", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &backendHandleStreamingRequestClient{stream}
+	return x, nil
+}
+
+type Backend_HandleStreamingRequestClient interface {
+	Send(*HandleStreamingRequestArgs) error
+	Recv() (*HandleStreamingRequestReply, error)
+	grpc.ClientStream
+}
+
+type backendHandleStreamingRequestClient struct {
+	grpc.ClientStream
+}
+
+func (x *backendHandleStreamingRequestClient) Send(m *HandleStreamingRequestArgs) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *backendHandleStreamingRequestClient) Recv() (*HandleStreamingRequestReply, error) {
+	m := new(HandleStreamingRequestReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *backendClient) HealthCheck(ctx context.Context, in *HealthCheckArgs, opts ...grpc.CallOption) (*HealthCheckReply, error) {
+	out := new(HealthCheckReply)
+	err := c.cc.Invoke(ctx, "/pb.Backend/HealthCheck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BackendServer is the server API for Backend service.
 // All implementations must embed UnimplementedBackendServer
 // for forward compatibility
@@ -174,6 +223,14 @@ type BackendServer interface {
 	Initialize(context.Context, *InitializeArgs) (*InitializeReply, error)
 	// Type returns the BackendType for the particular backend
 	Type(context.Context, *Empty) (*TypeReply, error)
+	// HandleStreamingRequest is used to handle a request to one of the
+	// streaming paths. The request body is streamed to the plugin, and the
+	// response the plugin writes to the response writer is streamed back.
+	HandleStreamingRequest(Backend_HandleStreamingRequestServer) error
+	// HealthCheck is invoked when an operator requests the health of the
+	// mounts to allow the backend to report on the systems it manages.
+	// Backends without a health check reply with ErrUnsupportedOperation.
+	HealthCheck(context.Context, *HealthCheckArgs) (*HealthCheckReply, error)
 	mustEmbedUnimplementedBackendServer()
 }
 
@@ -205,6 +262,12 @@ func (UnimplementedBackendServer) Initialize(context.Context, *InitializeArgs) (
 func (UnimplementedBackendServer) Type(context.Context, *Empty) (*TypeReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Type not implemented")
 }
+func (UnimplementedBackendServer) HandleStreamingRequest(Backend_HandleStreamingRequestServer) error {
+	return status.Errorf(codes.Unimplemented, "method HandleStreamingRequest not implemented")
+}
+func (UnimplementedBackendServer) HealthCheck(context.Context, *HealthCheckArgs) (*HealthCheckReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
+}
 func (UnimplementedBackendServer) mustEmbedUnimplementedBackendServer() {}
 
 // UnsafeBackendServer may be embedded to opt out of forward compatibility for this service.
@@ -362,6 +425,50 @@ func _Backend_Type_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Backend_HandleStreamingRequest_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BackendServer).HandleStreamingRequest(&backendHandleStreamingRequestServer{stream})
+}
+
+type Backend_HandleStreamingRequestServer interface {
+	Send(*HandleStreamingRequestReply) error
+	Recv() (*HandleStreamingRequestArgs, error)
+	grpc.ServerStream
+}
+
+type backendHandleStreamingRequestServer struct {
+	grpc.ServerStream
+}
+
+func (x *backendHandleStreamingRequestServer) Send(m *HandleStreamingRequestReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *backendHandleStreamingRequestServer) Recv() (*HandleStreamingRequestArgs, error) {
+	m := new(HandleStreamingRequestArgs)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Backend_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthCheckArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackendServer).HealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Backend/HealthCheck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackendServer).HealthCheck(ctx, req.(*HealthCheckArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Backend_ServiceDesc is the grpc.ServiceDesc for Backend service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -401,8 +508,19 @@ var Backend_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Type",
 			Handler:    _Backend_Type_Handler,
 		},
+		{
+			MethodName: "HealthCheck",
+			Handler:    _Backend_HealthCheck_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "HandleStreamingRequest",
+			Handler:       _Backend_HandleStreamingRequest_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "sdk/plugin/pb/backend.proto",
 }
 
@@ -1177,6 +1295,9 @@ var SystemView_ServiceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type EventsClient interface {
 	SendEvent(ctx context.Context, in *SendEventRequest, opts ...grpc.CallOption) (*Empty, error)
+	// SubscribeEvents streams the events sent in the namespace of the plugin
+	// whose type matches the pattern.
+	SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Events_SubscribeEventsClient, error)
 }
 
 type eventsClient struct {
@@ -1196,11 +1317,46 @@ func (c *eventsClient) SendEvent(ctx context.Context, in *SendEventRequest, opts
 	return out, nil
 }
 
+func (c *eventsClient) SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (Events_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Events_ServiceDesc.Streams[0], "/pb.Events/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventsSubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Events_SubscribeEventsClient interface {
+	Recv() (*logical.EventReceived, error)
+	grpc.ClientStream
+}
+
+type eventsSubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventsSubscribeEventsClient) Recv() (*logical.EventReceived, error) {
+	m := new(logical.EventReceived)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // EventsServer is the server API for Events service.
 // All implementations must embed UnimplementedEventsServer
 // for forward compatibility
 type EventsServer interface {
 	SendEvent(context.Context, *SendEventRequest) (*Empty, error)
+	// SubscribeEvents streams the events sent in the namespace of the plugin
+	// whose type matches the pattern.
+	SubscribeEvents(*SubscribeEventsRequest, Events_SubscribeEventsServer) error
 	mustEmbedUnimplementedEventsServer()
 }
 
@@ -1211,6 +1367,9 @@ type UnimplementedEventsServer struct {
 func (UnimplementedEventsServer) SendEvent(context.Context, *SendEventRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendEvent not implemented")
 }
+func (UnimplementedEventsServer) SubscribeEvents(*SubscribeEventsRequest, Events_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
 func (UnimplementedEventsServer) mustEmbedUnimplementedEventsServer() {}
 
 // UnsafeEventsServer may be embedded to opt out of forward compatibility for this service.
@@ -1242,6 +1401,27 @@ func _Events_SendEvent_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Events_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventsServer).SubscribeEvents(m, &eventsSubscribeEventsServer{stream})
+}
+
+type Events_SubscribeEventsServer interface {
+	Send(*logical.EventReceived) error
+	grpc.ServerStream
+}
+
+type eventsSubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventsSubscribeEventsServer) Send(m *logical.EventReceived) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Events_ServiceDesc is the grpc.ServiceDesc for Events service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1254,6 +1434,12 @@ var Events_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Events_SendEvent_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _Events_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "sdk/plugin/pb/backend.proto",
 }
@@ -57,6 +57,15 @@ func (b *BackendPluginClientV5) PluginVersion() logical.PluginVersion {
 
 var _ logical.PluginVersioner = (*BackendPluginClientV5)(nil)
 
+func (b *BackendPluginClientV5) HealthCheck(ctx context.Context, req *logical.HealthCheckRequest) error {
+	if checker, ok := b.Backend.(logical.HealthChecker); ok {
+		return checker.HealthCheck(ctx, req)
+	}
+	return logical.ErrUnsupportedOperation
+}
+
+var _ logical.HealthChecker = (*BackendPluginClientV5)(nil)
+
 // NewBackendV5 will return an instance of an RPC-based client implementation of
 // the backend for external plugins, or a concrete implementation of the
 // backend if it is a builtin backend. The backend is returned as a
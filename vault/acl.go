@@ -35,6 +35,9 @@ type ACL struct {
 
 	// Stores policies that are actually RGPs for later fetching
 	rgpPolicies []*Policy
+
+	// Stores Rego policies, which further restrict what the ACL allows
+	regoPolicies []*Policy
 }
 
 type PolicyCheckOpts struct {
@@ -95,6 +98,9 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 		case PolicyTypeRGP:
 			a.rgpPolicies = append(a.rgpPolicies, policy)
 			continue
+		case PolicyTypeRego:
+			a.regoPolicies = append(a.regoPolicies, policy)
+			continue
 		default:
 			return nil, fmt.Errorf("unable to parse policy (wrong type)")
 		}
@@ -723,6 +729,14 @@ func (c *Core) performPolicyChecks(ctx context.Context, acl *ACL, te *logical.To
 		if !ret.ACLResults.Allowed {
 			return ret
 		}
+		// Rego policies can only further restrict what the ACL policies
+		// allow, so they are not evaluated for denied requests
+		if len(acl.regoPolicies) > 0 && req.Operation != logical.HelpOperation {
+			c.performRegoPolicyChecks(ctx, acl, te, req, inEntity, ret)
+			if !ret.ACLResults.Allowed {
+				return ret
+			}
+		}
 		// Since HelpOperation was fast-pathed inside AllowOperation, RootPrivs will not have been populated in this
 		// case, so we need to special-case that here as well, or we'll block HelpOperation on all sudo-protected paths.
 		if !ret.RootPrivs && opts.RootPrivsRequired && req.Operation != logical.HelpOperation {
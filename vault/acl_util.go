@@ -8,10 +8,16 @@ package vault
 import (
 	"context"
 
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/vault/helper/identity"
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
 func (c *Core) performEntPolicyChecks(ctx context.Context, acl *ACL, te *logical.TokenEntry, req *logical.Request, inEntity *identity.Entity, opts *PolicyCheckOpts, ret *AuthResults) {
+	if err := checkControlGroup(req, ret.ACLResults); err != nil {
+		ret.Error = multierror.Append(ret.Error, err)
+		return
+	}
+
 	ret.Allowed = true
 }
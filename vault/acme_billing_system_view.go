@@ -99,9 +99,10 @@ func (c *Core) NewAcmeBillingSystemView(sysView interface{}) extendedSystemView
 	es := sysView.(extendedSystemViewImpl)
 	des := es.dynamicSystemView
 
-	// Scenario 3.
-	return &acmeBillingSystemViewImplNoManagedKeys{
-		extendedSystemView: es,
+	// Scenario 2, the managed keys being served by the dynamic system view.
+	return &acmeBillingSystemViewImplNoSourcer{
+		extendedSystemView:   es,
+		ManagedKeySystemView: des,
 		acmeBillingImpl: acmeBillingImpl{
 			core:  c,
 			entry: des.mountEntry,
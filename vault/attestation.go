@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// coreAttestationKeyPath is the location of the key signing the attestation
+// statements of the plugins
+const coreAttestationKeyPath = "core/attestation/key"
+
+var errNoAttestationKey = errors.New("attestation key is not loaded")
+
+type attestationKeyEntry struct {
+	// Key is the PKCS #8 DER encoded private key
+	Key []byte `json:"key"`
+
+	// Certificate is the DER encoded self-signed certificate of the key
+	Certificate []byte `json:"certificate"`
+}
+
+// ensureAttestationKey loads the attestation key of the cluster, generating
+// it along with its certificate on first use. The key never leaves the
+// barrier, so the certificate identifies the statements signed by the
+// cluster for as long as the storage lives.
+func (c *Core) ensureAttestationKey(ctx context.Context) error {
+	entry, err := c.barrier.Get(ctx, coreAttestationKeyPath)
+	if err != nil {
+		return err
+	}
+
+	var keyEntry attestationKeyEntry
+
+	if entry == nil {
+		keyEntry, err = c.generateAttestationKey()
+		if err != nil {
+			return err
+		}
+		val, err := jsonutil.EncodeJSON(keyEntry)
+		if err != nil {
+			return fmt.Errorf("failed to encode attestation key: %w", err)
+		}
+		entry = &logical.StorageEntry{
+			Key:   coreAttestationKeyPath,
+			Value: val,
+		}
+		if err = c.barrier.Put(ctx, entry); err != nil {
+			return fmt.Errorf("failed to store attestation key: %w", err)
+		}
+	}
+
+	if err = jsonutil.DecodeJSON(entry.Value, &keyEntry); err != nil {
+		return fmt.Errorf("failed to decode attestation key: %w", err)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(keyEntry.Key)
+	if err != nil {
+		return fmt.Errorf("failed to parse attestation key: %w", err)
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return fmt.Errorf("unexpected attestation key type %T", key)
+	}
+	cert, err := x509.ParseCertificate(keyEntry.Certificate)
+	if err != nil {
+		return fmt.Errorf("failed to parse attestation certificate: %w", err)
+	}
+
+	c.attestationKey = ecKey
+	c.attestationCert = cert
+
+	c.logger.Info("loaded attestation key")
+
+	return nil
+}
+
+func (c *Core) generateAttestationKey() (attestationKeyEntry, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), c.secureRandomReader)
+	if err != nil {
+		return attestationKeyEntry{}, fmt.Errorf("failed to generate attestation key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return attestationKeyEntry{}, fmt.Errorf("failed to encode attestation key: %w", err)
+	}
+
+	serial, err := rand.Int(c.secureRandomReader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return attestationKeyEntry{}, err
+	}
+	template := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName: "Vault attestation",
+		},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		SerialNumber: serial,
+		NotBefore:    time.Now().Add(-30 * time.Second),
+		// Statements must remain verifiable for as long as the keys they are
+		// about, like the local cluster certificate
+		NotAfter:              time.Now().Add(262980 * time.Hour),
+		BasicConstraintsValid: true,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return attestationKeyEntry{}, fmt.Errorf("failed to generate attestation certificate: %w", err)
+	}
+
+	return attestationKeyEntry{
+		Key:         der,
+		Certificate: cert,
+	}, nil
+}
+
+// signAttestation signs the SHA-384 digest of the statement with the
+// attestation key, returning the ASN.1 encoded signature along with the DER
+// encoded certificate of the key.
+func (c *Core) signAttestation(statement []byte) ([]byte, []byte, error) {
+	if c.attestationKey == nil || c.attestationCert == nil {
+		return nil, nil, errNoAttestationKey
+	}
+
+	digest := sha512.Sum384(statement)
+	sig, err := ecdsa.SignASN1(rand.Reader, c.attestationKey, digest[:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign attestation statement: %w", err)
+	}
+
+	return sig, c.attestationCert.Raw, nil
+}
@@ -8,8 +8,10 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
+	log "github.com/hashicorp/go-hclog"
 	uuid "github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/audit"
 	"github.com/hashicorp/vault/helper/namespace"
@@ -75,6 +77,11 @@ func (c *Core) enableAudit(ctx context.Context, entry *MountEntry, updateStorage
 	c.auditLock.Lock()
 	defer c.auditLock.Unlock()
 
+	filter, err := newAuditFilter(entry.Options)
+	if err != nil {
+		return err
+	}
+
 	// Look for matching name
 	for _, ent := range c.audit.Entries {
 		switch {
@@ -131,6 +138,7 @@ func (c *Core) enableAudit(ctx context.Context, entry *MountEntry, updateStorage
 		err = backend.LogTestMessage(ctx, testProbe, entry.Options)
 		if err != nil {
 			c.logger.Error("new audit backend failed test", "path", entry.Path, "type", entry.Type, "error", err)
+			closeAuditBackend(c.logger, entry.Path, backend)
 			return fmt.Errorf("audit backend failed test message: %w", err)
 
 		}
@@ -148,6 +156,7 @@ func (c *Core) enableAudit(ctx context.Context, entry *MountEntry, updateStorage
 
 	if updateStorage {
 		if err := c.persistAudit(ctx, newTable, entry.Local); err != nil {
+			closeAuditBackend(c.logger, entry.Path, backend)
 			return errors.New("failed to update audit table")
 		}
 	}
@@ -155,7 +164,7 @@ func (c *Core) enableAudit(ctx context.Context, entry *MountEntry, updateStorage
 	c.audit = newTable
 
 	// Register the backend
-	c.auditBroker.Register(entry.Path, backend, view, entry.Local)
+	c.auditBroker.Register(entry.Path, backend, view, entry.Local, filter)
 	if c.logger.IsInfo() {
 		c.logger.Info("enabled audit backend", "path", entry.Path, "type", entry.Type)
 	}
@@ -404,6 +413,12 @@ func (c *Core) setupAudits(ctx context.Context) error {
 			view.setReadOnlyErr(origViewReadOnlyErr)
 		})
 
+		filter, err := newAuditFilter(entry.Options)
+		if err != nil {
+			c.logger.Error("failed to parse audit filter", "path", entry.Path, "error", err)
+			continue
+		}
+
 		// Initialize the backend
 		backend, err := c.newAuditBackend(ctx, entry, view, entry.Options)
 		if err != nil {
@@ -416,7 +431,7 @@ func (c *Core) setupAudits(ctx context.Context) error {
 		}
 
 		// Mount the backend
-		broker.Register(entry.Path, backend, view, entry.Local)
+		broker.Register(entry.Path, backend, view, entry.Local, filter)
 
 		successCount++
 	}
@@ -439,6 +454,9 @@ func (c *Core) teardownAudits() error {
 		for _, entry := range c.audit.Entries {
 			c.removeAuditReloadFunc(entry)
 			removeAuditPathChecker(c, entry)
+			if c.auditBroker != nil {
+				c.auditBroker.Deregister(entry.Path)
+			}
 		}
 	}
 
@@ -447,12 +465,24 @@ func (c *Core) teardownAudits() error {
 	return nil
 }
 
+// closeAuditBackend releases the resources held by audit backends that need
+// to be closed, such as connections or background workers.
+func closeAuditBackend(logger log.Logger, path string, backend audit.Backend) {
+	closer, ok := backend.(io.Closer)
+	if !ok {
+		return
+	}
+	if err := closer.Close(); err != nil {
+		logger.Error("failed to close audit backend", "path", path, "error", err)
+	}
+}
+
 // removeAuditReloadFunc removes the reload func from the working set. The
 // audit lock needs to be held before calling this.
 func (c *Core) removeAuditReloadFunc(entry *MountEntry) {
 	switch entry.Type {
-	case "file":
-		key := "audit_file|" + entry.Path
+	case "file", "kafka", "otel":
+		key := "audit_" + entry.Type + "|" + entry.Path
 		c.reloadFuncsLock.Lock()
 
 		if c.logger.IsDebug() {
@@ -465,22 +495,28 @@ func (c *Core) removeAuditReloadFunc(entry *MountEntry) {
 	}
 }
 
+// newAuditSaltConfig returns the config of the salts audit backends use to
+// compute hashes.
+func newAuditSaltConfig() *salt.Config {
+	return &salt.Config{
+		HMAC:     sha256.New,
+		HMACType: "hmac-sha256",
+		Location: salt.DefaultLocation,
+	}
+}
+
 // newAuditBackend is used to create and configure a new audit backend by name
 func (c *Core) newAuditBackend(ctx context.Context, entry *MountEntry, view logical.Storage, conf map[string]string) (audit.Backend, error) {
 	f, ok := c.auditBackends[entry.Type]
 	if !ok {
 		return nil, fmt.Errorf("unknown backend type: %q", entry.Type)
 	}
-	saltConfig := &salt.Config{
-		HMAC:     sha256.New,
-		HMACType: "hmac-sha256",
-		Location: salt.DefaultLocation,
-	}
-
 	be, err := f(ctx, &audit.BackendConfig{
-		SaltView:   view,
-		SaltConfig: saltConfig,
-		Config:     conf,
+		SaltView:    view,
+		SaltConfig:  newAuditSaltConfig(),
+		Config:      conf,
+		MountPath:   entry.Path,
+		ClusterName: c.clusterName,
 	})
 	if err != nil {
 		return nil, err
@@ -512,6 +548,26 @@ func (c *Core) newAuditBackend(ctx context.Context, entry *MountEntry, view logi
 			return be.Reload(ctx)
 		})
 
+		c.reloadFuncsLock.Unlock()
+	case "otel":
+		key := "audit_otel|" + entry.Path
+
+		c.reloadFuncsLock.Lock()
+
+		if auditLogger.IsDebug() {
+			auditLogger.Debug("adding reload function", "path", entry.Path)
+			if entry.Options != nil {
+				auditLogger.Debug("otel backend options", "path", entry.Path, "endpoint", entry.Options["endpoint"])
+			}
+		}
+
+		c.reloadFuncs[key] = append(c.reloadFuncs[key], func() error {
+			if auditLogger.IsInfo() {
+				auditLogger.Info("reloading otel audit backend", "path", entry.Path)
+			}
+			return be.Reload(ctx)
+		})
+
 		c.reloadFuncsLock.Unlock()
 	case "socket":
 		if auditLogger.IsDebug() {
@@ -519,6 +575,26 @@ func (c *Core) newAuditBackend(ctx context.Context, entry *MountEntry, view logi
 				auditLogger.Debug("socket backend options", "path", entry.Path, "address", entry.Options["address"], "socket type", entry.Options["socket_type"])
 			}
 		}
+	case "kafka":
+		key := "audit_kafka|" + entry.Path
+
+		c.reloadFuncsLock.Lock()
+
+		if auditLogger.IsDebug() {
+			auditLogger.Debug("adding reload function", "path", entry.Path)
+			if entry.Options != nil {
+				auditLogger.Debug("kafka backend options", "path", entry.Path, "brokers", entry.Options["brokers"], "topic", entry.Options["topic"], "unavailable_behavior", entry.Options["unavailable_behavior"])
+			}
+		}
+
+		c.reloadFuncs[key] = append(c.reloadFuncs[key], func() error {
+			if auditLogger.IsInfo() {
+				auditLogger.Info("reloading kafka audit backend", "path", entry.Path)
+			}
+			return be.Reload(ctx)
+		})
+
+		c.reloadFuncsLock.Unlock()
 	case "syslog":
 		if auditLogger.IsDebug() {
 			if entry.Options != nil {
@@ -21,6 +21,7 @@ type backendEntry struct {
 	backend audit.Backend
 	view    *BarrierView
 	local   bool
+	filter  *auditFilter
 }
 
 // AuditBroker is used to provide a single ingest interface to auditable
@@ -40,21 +41,27 @@ func NewAuditBroker(log log.Logger) *AuditBroker {
 	return b
 }
 
-// Register is used to add new audit backend to the broker
-func (a *AuditBroker) Register(name string, b audit.Backend, v *BarrierView, local bool) {
+// Register is used to add new audit backend to the broker. If a filter is
+// given, the backend only logs the requests matching it.
+func (a *AuditBroker) Register(name string, b audit.Backend, v *BarrierView, local bool, filter *auditFilter) {
 	a.Lock()
 	defer a.Unlock()
 	a.backends[name] = backendEntry{
 		backend: b,
 		view:    v,
 		local:   local,
+		filter:  filter,
 	}
 }
 
-// Deregister is used to remove an audit backend from the broker
+// Deregister is used to remove an audit backend from the broker. Backends
+// holding resources that need to be released are closed.
 func (a *AuditBroker) Deregister(name string) {
 	a.Lock()
 	defer a.Unlock()
+	if be, ok := a.backends[name]; ok {
+		closeAuditBackend(a.logger, name, be.backend)
+	}
 	delete(a.backends, name)
 }
 
@@ -90,7 +97,9 @@ func (a *AuditBroker) GetHash(ctx context.Context, name string, input string) (s
 }
 
 // LogRequest is used to ensure all the audit backends have an opportunity to
-// log the given request and that *at least one* succeeds.
+// log the given request and that *at least one* succeeds. Backends whose
+// filter doesn't match the request don't log it, so at least one of the
+// backends matching it must succeed.
 func (a *AuditBroker) LogRequest(ctx context.Context, in *logical.LogInput, headersConfig *AuditedHeadersConfig) (ret error) {
 	defer metrics.MeasureSince([]string{"audit", "log_request"}, time.Now())
 	a.RLock()
@@ -135,7 +144,18 @@ func (a *AuditBroker) LogRequest(ctx context.Context, in *logical.LogInput, head
 
 	// Ensure at least one backend logs
 	anyLogged := false
+	anyMatched := false
 	for name, be := range a.backends {
+		match, fErr := be.filter.matches(ctx, in)
+		if fErr != nil {
+			a.logger.Error("backend failed to evaluate filter", "backend", name, "error", fErr)
+			continue
+		}
+		if !match {
+			continue
+		}
+		anyMatched = true
+
 		in.Request.Headers = nil
 		transHeaders, thErr := headersConfig.ApplyConfig(ctx, headers, be.backend.GetHash)
 		if thErr != nil {
@@ -153,7 +173,9 @@ func (a *AuditBroker) LogRequest(ctx context.Context, in *logical.LogInput, head
 			anyLogged = true
 		}
 	}
-	if !anyLogged && len(a.backends) > 0 {
+	if !anyMatched && len(a.backends) > 0 {
+		retErr = multierror.Append(retErr, fmt.Errorf("no audit backend filter matched the request"))
+	} else if !anyLogged && len(a.backends) > 0 {
 		retErr = multierror.Append(retErr, fmt.Errorf("no audit backend succeeded in logging the request"))
 	}
 
@@ -161,7 +183,8 @@ func (a *AuditBroker) LogRequest(ctx context.Context, in *logical.LogInput, head
 }
 
 // LogResponse is used to ensure all the audit backends have an opportunity to
-// log the given response and that *at least one* succeeds.
+// log the given response and that *at least one* succeeds. As for requests,
+// only the backends whose filter matches the request log the response.
 func (a *AuditBroker) LogResponse(ctx context.Context, in *logical.LogInput, headersConfig *AuditedHeadersConfig) (ret error) {
 	defer metrics.MeasureSince([]string{"audit", "log_response"}, time.Now())
 	a.RLock()
@@ -200,7 +223,18 @@ func (a *AuditBroker) LogResponse(ctx context.Context, in *logical.LogInput, hea
 
 	// Ensure at least one backend logs
 	anyLogged := false
+	anyMatched := false
 	for name, be := range a.backends {
+		match, fErr := be.filter.matches(ctx, in)
+		if fErr != nil {
+			a.logger.Error("backend failed to evaluate filter", "backend", name, "error", fErr)
+			continue
+		}
+		if !match {
+			continue
+		}
+		anyMatched = true
+
 		in.Request.Headers = nil
 		transHeaders, thErr := headersConfig.ApplyConfig(ctx, headers, be.backend.GetHash)
 		if thErr != nil {
@@ -218,7 +252,9 @@ func (a *AuditBroker) LogResponse(ctx context.Context, in *logical.LogInput, hea
 			anyLogged = true
 		}
 	}
-	if !anyLogged && len(a.backends) > 0 {
+	if !anyMatched && len(a.backends) > 0 {
+		retErr = multierror.Append(retErr, fmt.Errorf("no audit backend filter matched the response"))
+	} else if !anyLogged && len(a.backends) > 0 {
 		retErr = multierror.Append(retErr, fmt.Errorf("no audit backend succeeded in logging the response"))
 	}
 
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// auditFilterOption is the audit device option holding the filter expression
+// selecting the requests logged by the device.
+const auditFilterOption = "filter"
+
+// auditFilter selects the requests, and their responses, an audit device
+// logs.
+type auditFilter struct {
+	expression string
+	evaluator  *bexpr.Evaluator
+}
+
+// auditFilterDatum holds the fields of a request filter expressions are
+// evaluated against.
+type auditFilterDatum struct {
+	MountPoint    string `bexpr:"mount_point"`
+	MountType     string `bexpr:"mount_type"`
+	Namespace     string `bexpr:"namespace"`
+	Operation     string `bexpr:"operation"`
+	Path          string `bexpr:"path"`
+	DisplayName   string `bexpr:"display_name"`
+	RemoteAddress string `bexpr:"remote_address"`
+}
+
+// newAuditFilter parses the filter expression of an audit device. A nil
+// filter is returned if the device doesn't have one.
+func newAuditFilter(options map[string]string) (*auditFilter, error) {
+	expression := options[auditFilterOption]
+	if expression == "" {
+		return nil, nil
+	}
+
+	evaluator, err := bexpr.CreateEvaluator(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expression, err)
+	}
+
+	// Catch references to unknown fields now rather than when logging
+	if _, err := evaluator.Evaluate(auditFilterDatum{}); err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expression, err)
+	}
+
+	return &auditFilter{
+		expression: expression,
+		evaluator:  evaluator,
+	}, nil
+}
+
+// matches returns whether the request of the log input should be logged. A
+// nil filter matches every request.
+func (f *auditFilter) matches(ctx context.Context, in *logical.LogInput) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+
+	var datum auditFilterDatum
+	if req := in.Request; req != nil {
+		datum.MountPoint = req.MountPoint
+		datum.MountType = req.MountType
+		datum.Operation = string(req.Operation)
+		datum.Path = req.Path
+		if req.Connection != nil {
+			datum.RemoteAddress = req.Connection.RemoteAddr
+		}
+	}
+	if in.Auth != nil {
+		datum.DisplayName = in.Auth.DisplayName
+	}
+	if ns, err := namespace.FromContext(ctx); err == nil {
+		datum.Namespace = ns.Path
+	}
+
+	return f.evaluator.Evaluate(datum)
+}
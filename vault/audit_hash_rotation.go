@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/salt"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// auditSaltRotationLocation is the path in the view of an audit device
+	// holding the salt it used before its last rotation.
+	auditSaltRotationLocation = "salt-rotation"
+
+	// defaultAuditSaltOverlap is how long the previous salt of an audit device
+	// keeps being used to compute hashes after a rotation if no overlap is
+	// given.
+	defaultAuditSaltOverlap = 24 * time.Hour
+)
+
+// auditSaltRotation records the salt an audit device used before its last
+// rotation, until the end of the overlap window.
+type auditSaltRotation struct {
+	PreviousSalt string    `json:"previous_salt"`
+	RotatedAt    time.Time `json:"rotated_at"`
+	OverlapEnd   time.Time `json:"overlap_end"`
+}
+
+// RotateSalt replaces the salt of the named audit backend with a new one. The
+// previous salt keeps being available to compute hashes for the overlap
+// window, so that audit pipelines can correlate the hashes computed with
+// both.
+func (a *AuditBroker) RotateSalt(ctx context.Context, name string, overlap time.Duration) (*auditSaltRotation, error) {
+	a.Lock()
+	defer a.Unlock()
+	be, ok := a.backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown audit backend %q", name)
+	}
+
+	if be.view == nil {
+		return nil, fmt.Errorf("audit backend %q has no storage", name)
+	}
+
+	raw, err := be.view.Get(ctx, salt.DefaultLocation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read salt: %w", err)
+	}
+
+	newSalt, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	now := time.Now().UTC()
+	rotation := &auditSaltRotation{
+		RotatedAt:  now,
+		OverlapEnd: now.Add(overlap),
+	}
+	if raw != nil {
+		rotation.PreviousSalt = string(raw.Value)
+	}
+
+	// Without a previous salt, there is nothing to overlap with
+	if rotation.PreviousSalt != "" && overlap > 0 {
+		entry, err := logical.StorageEntryJSON(auditSaltRotationLocation, rotation)
+		if err != nil {
+			return nil, err
+		}
+		if err := be.view.Put(ctx, entry); err != nil {
+			return nil, fmt.Errorf("failed to persist previous salt: %w", err)
+		}
+	} else {
+		if err := be.view.Delete(ctx, auditSaltRotationLocation); err != nil {
+			return nil, fmt.Errorf("failed to remove previous salt: %w", err)
+		}
+		rotation.OverlapEnd = now
+	}
+
+	if err := be.view.Put(ctx, &logical.StorageEntry{
+		Key:   salt.DefaultLocation,
+		Value: []byte(newSalt),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to persist salt: %w", err)
+	}
+
+	// Make the backend load the new salt
+	be.backend.Invalidate(ctx)
+
+	return rotation, nil
+}
+
+// GetPreviousHash returns the hash of the input computed with the salt the
+// named audit backend used before its last rotation. An empty hash is
+// returned if the overlap window of the rotation is over.
+func (a *AuditBroker) GetPreviousHash(ctx context.Context, name string, input string) (string, error) {
+	a.RLock()
+	be, ok := a.backends[name]
+	a.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown audit backend %q", name)
+	}
+
+	if be.view == nil {
+		return "", nil
+	}
+
+	raw, err := be.view.Get(ctx, auditSaltRotationLocation)
+	if err != nil {
+		return "", fmt.Errorf("failed to read previous salt: %w", err)
+	}
+	if raw == nil {
+		return "", nil
+	}
+
+	var rotation auditSaltRotation
+	if err := jsonutil.DecodeJSON(raw.Value, &rotation); err != nil {
+		return "", fmt.Errorf("failed to decode previous salt: %w", err)
+	}
+	if rotation.PreviousSalt == "" || !time.Now().Before(rotation.OverlapEnd) {
+		return "", nil
+	}
+
+	saltConfig := newAuditSaltConfig()
+	return salt.HMACIdentifiedValue(rotation.PreviousSalt, input, saltConfig.HMACType, saltConfig.HMAC), nil
+}
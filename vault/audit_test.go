@@ -343,8 +343,8 @@ func TestAuditBroker_LogRequest(t *testing.T) {
 	b := NewAuditBroker(l)
 	a1 := corehelpers.TestNoopAudit(t, nil)
 	a2 := corehelpers.TestNoopAudit(t, nil)
-	b.Register("foo", a1, nil, false)
-	b.Register("bar", a2, nil, false)
+	b.Register("foo", a1, nil, false, nil)
+	b.Register("bar", a2, nil, false, nil)
 
 	auth := &logical.Auth{
 		ClientToken: "foo",
@@ -430,8 +430,8 @@ func TestAuditBroker_LogResponse(t *testing.T) {
 	b := NewAuditBroker(l)
 	a1 := corehelpers.TestNoopAudit(t, nil)
 	a2 := corehelpers.TestNoopAudit(t, nil)
-	b.Register("foo", a1, nil, false)
-	b.Register("bar", a2, nil, false)
+	b.Register("foo", a1, nil, false, nil)
+	b.Register("bar", a2, nil, false, nil)
 
 	auth := &logical.Auth{
 		NumUses:     10,
@@ -530,6 +530,77 @@ func TestAuditBroker_LogResponse(t *testing.T) {
 	}
 }
 
+func TestAuditBroker_Filter(t *testing.T) {
+	l := logging.NewVaultLogger(log.Trace)
+	b := NewAuditBroker(l)
+	noise := corehelpers.TestNoopAudit(t, nil)
+	compliance := corehelpers.TestNoopAudit(t, nil)
+
+	noiseFilter, err := newAuditFilter(map[string]string{
+		"filter": `path == "sys/health" or (operation == "update" and path == "auth/token/renew-self")`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	complianceFilter, err := newAuditFilter(map[string]string{
+		"filter": `path != "sys/health" and path != "auth/token/renew-self"`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Register("noise", noise, nil, false, noiseFilter)
+	b.Register("compliance", compliance, nil, false, complianceFilter)
+
+	headersConf := &AuditedHeadersConfig{
+		Headers: make(map[string]*auditedHeaderSettings),
+	}
+	ctx := namespace.RootContext(context.Background())
+	for _, req := range []*logical.Request{
+		{Operation: logical.ReadOperation, Path: "sys/health"},
+		{Operation: logical.UpdateOperation, Path: "auth/token/renew-self"},
+		{Operation: logical.UpdateOperation, Path: "secret/foo", MountPoint: "secret/", MountType: "kv"},
+	} {
+		in := &logical.LogInput{Request: req}
+		if err := b.LogRequest(ctx, in, headersConf); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.LogResponse(ctx, in, headersConf); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(noise.Req) != 2 || len(noise.Resp) != 2 {
+		t.Fatalf("expected the noise backend to log 2 requests, got %d", len(noise.Req))
+	}
+	if len(compliance.Req) != 1 || len(compliance.Resp) != 1 || compliance.Req[0].Path != "secret/foo" {
+		t.Fatalf("expected the compliance backend to log secret/foo, got %#v", compliance.Req)
+	}
+
+	// Requests matching no filter fail to be logged
+	b.Deregister("compliance")
+	in := &logical.LogInput{Request: &logical.Request{Operation: logical.ReadOperation, Path: "secret/foo"}}
+	if err := b.LogRequest(ctx, in, headersConf); !errwrap.Contains(err, "no audit backend filter matched the request") {
+		t.Fatalf("err: %v", err)
+	}
+}
+
+func TestCore_EnableAudit_InvalidFilter(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	c.auditBackends["noop"] = corehelpers.NoopAuditFactory(nil)
+
+	for _, filter := range []string{`path ==`, `unknown_field == "foo"`} {
+		me := &MountEntry{
+			Table:   auditTableType,
+			Path:    "foo",
+			Type:    "noop",
+			Options: map[string]string{"filter": filter},
+		}
+		if err := c.enableAudit(namespace.RootContext(nil), me, true); err == nil {
+			t.Fatalf("expected filter %q to be rejected", filter)
+		}
+	}
+}
+
 func TestAuditBroker_AuditHeaders(t *testing.T) {
 	logger := logging.NewVaultLogger(log.Trace)
 	b := NewAuditBroker(logger)
@@ -537,8 +608,8 @@ func TestAuditBroker_AuditHeaders(t *testing.T) {
 	view := NewBarrierView(barrier, "headers/")
 	a1 := corehelpers.TestNoopAudit(t, nil)
 	a2 := corehelpers.TestNoopAudit(t, nil)
-	b.Register("foo", a1, nil, false)
-	b.Register("bar", a2, nil, false)
+	b.Register("foo", a1, nil, false, nil)
+	b.Register("bar", a2, nil, false, nil)
 
 	auth := &logical.Auth{
 		ClientToken: "foo",
@@ -74,6 +74,8 @@ func (c *Core) enableCredential(ctx context.Context, entry *MountEntry) error {
 		}
 		return err
 	}
+
+	c.sendCoreEvent(ctx, eventTypeAuthEnabled, "path", entry.Path, "type", entry.Type, "accessor", entry.Accessor)
 	return nil
 }
 
@@ -253,8 +255,20 @@ func (c *Core) disableCredential(ctx context.Context, path string) error {
 		return fmt.Errorf("token credential backend cannot be disabled")
 	}
 
+	// Check the mount can be deleted, and retain its storage if it is
+	// soft-deleted
+	softDeletedUUID, err := c.prepareMountDeletion(ctx, credentialRoutePrefix+path)
+	if err != nil {
+		return err
+	}
+
 	// Disable credential internally
 	if err := c.disableCredentialInternal(ctx, path, MountTableUpdateStorage); err != nil {
+		if softDeletedUUID != "" {
+			if forgetErr := c.forgetDeletedMount(ctx, softDeletedUUID); forgetErr != nil {
+				c.logger.Error("failed to forget deleted mount", "path", path, "error", forgetErr)
+			}
+		}
 		return err
 	}
 
@@ -263,6 +277,8 @@ func (c *Core) disableCredential(ctx context.Context, path string) error {
 		// Even we failed to evaluate filtered paths, the unmount operation was still successful
 		c.logger.Error("failed to evaluate filtered paths", "error", err)
 	}
+
+	c.sendCoreEvent(ctx, eventTypeAuthDisabled, "path", path)
 	return nil
 }
 
@@ -318,10 +334,18 @@ func (c *Core) disableCredentialInternal(ctx context.Context, path string, updat
 		backend.Cleanup(ctx)
 	}
 
+	retained, err := c.isMountStorageRetained(ctx, entry.UUID)
+	if err != nil {
+		return err
+	}
+
 	viewPath := entry.ViewPath()
 	switch {
 	case !updateStorage:
 		// Don't attempt to clear data, replication will handle this
+	case retained:
+		// Don't clear the data of soft-deleted mounts, it is purged at the
+		// end of their retention window
 	case c.IsDRSecondary():
 		// If we are a dr secondary we want to clear the view, but the provided
 		// view is marked as read only. We use the barrier here to get around
@@ -1013,6 +1037,7 @@ func (c *Core) newCredentialBackend(ctx context.Context, entry *MountEntry, sysV
 	}
 	if c.IsExperimentEnabled(experiments.VaultExperimentEventsAlpha1) {
 		config.EventsSender = pluginEventSender
+		config.EventsSubscriber = pluginEventSender
 	}
 
 	b, err := f(ctx, config)
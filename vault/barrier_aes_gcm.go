@@ -58,6 +58,12 @@ var (
 	barrierRotationsMetric                 = []string{"barrier", "auto_rotation"}
 )
 
+var (
+	barrierRotationErrorsMetric      = []string{"barrier", "auto_rotation", "errors"}
+	barrierRemainingOperationsMetric = []string{"barrier", "auto_rotation", "remaining_operations"}
+	barrierTimeUntilRotationMetric   = []string{"barrier", "auto_rotation", "seconds_until_rotation"}
+)
+
 // AESGCMBarrier is a SecurityBarrier implementation that uses the AES
 // cipher core and the Galois Counter Mode block mode. It defaults to
 // the golang NONCE default value of 12 and a key size of 256
@@ -0,0 +1,574 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/helper/wrapping"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// controlGroupSubPath is the sub-path of the system view holding the
+	// control group configuration and the state of pending requests
+	controlGroupSubPath = "control-group/"
+
+	controlGroupConfigPath    = "config"
+	controlGroupRequestPrefix = "request/"
+
+	// controlGroupCubbyholePath is where the original request is stored in
+	// the cubbyhole of the control group token
+	controlGroupCubbyholePath = "cubbyhole/control-group"
+
+	// defaultControlGroupTTL is the TTL of control group requests when neither
+	// the policy nor the configuration set one
+	defaultControlGroupTTL = 24 * time.Hour
+)
+
+var errControlGroupRequired = errors.New("request requires control group authorization")
+
+// controlGroupRequiredError is returned by the policy checks of a request that
+// must be authorized by a control group before running.
+type controlGroupRequiredError struct {
+	controlGroup *ControlGroup
+}
+
+func (e *controlGroupRequiredError) Error() string {
+	return errControlGroupRequired.Error()
+}
+
+func (e *controlGroupRequiredError) Is(target error) bool {
+	return target == errControlGroupRequired
+}
+
+// controlGroupConfig is the configuration of control groups
+type controlGroupConfig struct {
+	MaxTTL time.Duration `json:"max_ttl"`
+}
+
+// controlGroupRequest is the state of a control group request. It is stored
+// by the accessor of the control group token of the request.
+type controlGroupRequest struct {
+	Accessor       string                       `json:"accessor"`
+	NamespaceID    string                       `json:"namespace_id"`
+	Path           string                       `json:"path"`
+	Operation      logical.Operation            `json:"operation"`
+	EntityID       string                       `json:"entity_id"`
+	Factors        []*ControlGroupFactor        `json:"factors"`
+	RequestTime    time.Time                    `json:"request_time"`
+	ExpireTime     time.Time                    `json:"expire_time"`
+	Authorizations []*controlGroupAuthorization `json:"authorizations"`
+	Approved       bool                         `json:"approved"`
+	Denied         bool                         `json:"denied"`
+}
+
+type controlGroupAuthorization struct {
+	EntityID string    `json:"entity_id"`
+	Time     time.Time `json:"time"`
+}
+
+// controlGroupStoredRequest is the original request, run once the control
+// group request is approved
+type controlGroupStoredRequest struct {
+	Operation   logical.Operation        `json:"operation"`
+	Path        string                   `json:"path"`
+	Data        map[string]interface{}   `json:"data"`
+	ClientToken string                   `json:"client_token"`
+	WrapInfo    *logical.RequestWrapInfo `json:"wrap_info"`
+}
+
+func (r *controlGroupRequest) expired() bool {
+	return time.Now().After(r.ExpireTime)
+}
+
+func (r *controlGroupRequest) authorized(entityID string) bool {
+	for _, authz := range r.Authorizations {
+		if authz.EntityID == entityID {
+			return true
+		}
+	}
+	return false
+}
+
+// controlGroupForRequest returns the control group whose factors control the
+// operation of the request, if any
+func controlGroupForRequest(req *logical.Request, results *ACLResults) *ControlGroup {
+	if results == nil || results.ControlGroup == nil || len(results.ControlGroup.Factors) == 0 {
+		return nil
+	}
+
+	var capability string
+	switch req.Operation {
+	case logical.ReadOperation:
+		capability = ReadCapability
+	case logical.ListOperation:
+		capability = ListCapability
+	case logical.CreateOperation:
+		capability = CreateCapability
+	case logical.DeleteOperation:
+		capability = DeleteCapability
+	case logical.PatchOperation:
+		capability = PatchCapability
+	case logical.UpdateOperation, logical.RevokeOperation, logical.RenewOperation, logical.RollbackOperation:
+		capability = UpdateCapability
+	default:
+		return nil
+	}
+
+	var factors []*ControlGroupFactor
+	for _, factor := range results.ControlGroup.Factors {
+		if len(factor.ControlledCapabilities) == 0 || strutil.StrListContains(factor.ControlledCapabilities, capability) {
+			factors = append(factors, factor)
+		}
+	}
+	if len(factors) == 0 {
+		return nil
+	}
+
+	return &ControlGroup{
+		TTL:     results.ControlGroup.TTL,
+		Factors: factors,
+	}
+}
+
+// checkControlGroup returns a controlGroupRequiredError if the request must
+// be authorized by a control group and isn't the run of an approved request.
+func checkControlGroup(req *logical.Request, results *ACLResults) error {
+	if req.ControlGroup != nil && req.ControlGroup.Approved {
+		return nil
+	}
+
+	cg := controlGroupForRequest(req, results)
+	if cg == nil {
+		return nil
+	}
+
+	return &controlGroupRequiredError{controlGroup: cg}
+}
+
+func (c *Core) controlGroupView() *BarrierView {
+	return c.systemBarrierView.SubView(controlGroupSubPath)
+}
+
+func (c *Core) controlGroupConfig(ctx context.Context) (*controlGroupConfig, error) {
+	entry, err := c.controlGroupView().Get(ctx, controlGroupConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config := new(controlGroupConfig)
+	if entry == nil {
+		return config, nil
+	}
+	if err := entry.DecodeJSON(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (c *Core) loadControlGroupRequest(ctx context.Context, accessor string) (*controlGroupRequest, error) {
+	entry, err := c.controlGroupView().Get(ctx, controlGroupRequestPrefix+accessor)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	cgReq := new(controlGroupRequest)
+	if err := entry.DecodeJSON(cgReq); err != nil {
+		return nil, err
+	}
+
+	// Expired requests are cleaned up lazily
+	if cgReq.expired() {
+		if err := c.controlGroupView().Delete(ctx, controlGroupRequestPrefix+accessor); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	return cgReq, nil
+}
+
+func (c *Core) storeControlGroupRequest(ctx context.Context, cgReq *controlGroupRequest) error {
+	entry, err := logical.StorageEntryJSON(controlGroupRequestPrefix+cgReq.Accessor, cgReq)
+	if err != nil {
+		return err
+	}
+	return c.controlGroupView().Put(ctx, entry)
+}
+
+// createControlGroupRequest stores the request in the cubbyhole of a new
+// control group token and returns a response wrapping that token. The token
+// is unwrapped once the request is authorized to run the request.
+func (c *Core) createControlGroupRequest(ctx context.Context, req *logical.Request, auth *logical.Auth, cg *ControlGroup) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := c.controlGroupConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ttl := cg.TTL
+	if ttl == 0 {
+		ttl = config.MaxTTL
+	}
+	if ttl == 0 {
+		ttl = defaultControlGroupTTL
+	}
+	if config.MaxTTL > 0 && ttl > config.MaxTTL {
+		ttl = config.MaxTTL
+	}
+
+	creationTime := time.Now()
+	te := logical.TokenEntry{
+		Path:           req.Path,
+		Policies:       []string{controlGroupPolicyName},
+		CreationTime:   creationTime.Unix(),
+		TTL:            ttl,
+		ExplicitMaxTTL: ttl,
+		NamespaceID:    ns.ID,
+	}
+	if err := c.CreateToken(ctx, &te); err != nil {
+		c.logger.Error("failed to create control group token", "error", err)
+		return nil, ErrInternalError
+	}
+
+	storedReq, err := json.Marshal(&controlGroupStoredRequest{
+		Operation:   req.Operation,
+		Path:        req.Path,
+		Data:        req.Data,
+		ClientToken: req.ClientToken,
+		WrapInfo:    req.WrapInfo,
+	})
+	if err != nil {
+		c.tokenStore.revokeOrphan(ctx, te.ID)
+		return nil, err
+	}
+
+	for path, data := range map[string]map[string]interface{}{
+		controlGroupCubbyholePath: {
+			"request": string(storedReq),
+		},
+		"cubbyhole/wrapinfo": {
+			"creation_ttl":  ttl,
+			"creation_time": creationTime,
+			"creation_path": req.Path,
+		},
+	} {
+		cubbyReq := &logical.Request{
+			Operation:   logical.CreateOperation,
+			Path:        path,
+			ClientToken: te.ID,
+			Data:        data,
+		}
+		cubbyReq.SetTokenEntry(&te)
+		cubbyResp, err := c.router.Route(ctx, cubbyReq)
+		if err == nil && cubbyResp != nil && cubbyResp.IsError() {
+			err = cubbyResp.Error()
+		}
+		if err != nil {
+			c.tokenStore.revokeOrphan(ctx, te.ID)
+			c.logger.Error("failed to store control group request", "error", err)
+			return nil, ErrInternalError
+		}
+	}
+
+	cgAuth := &logical.Auth{
+		ClientToken: te.ID,
+		Policies:    []string{controlGroupPolicyName},
+		LeaseOptions: logical.LeaseOptions{
+			TTL:       te.TTL,
+			Renewable: false,
+		},
+	}
+	if err := c.expiration.RegisterAuth(ctx, &te, cgAuth, ""); err != nil {
+		c.tokenStore.revokeOrphan(ctx, te.ID)
+		c.logger.Error("failed to register control group token lease", "request_path", req.Path, "error", err)
+		return nil, ErrInternalError
+	}
+
+	var entityID string
+	if auth != nil {
+		entityID = auth.EntityID
+	}
+
+	c.controlGroupLock.Lock()
+	defer c.controlGroupLock.Unlock()
+
+	err = c.storeControlGroupRequest(ctx, &controlGroupRequest{
+		Accessor:    te.Accessor,
+		NamespaceID: ns.ID,
+		Path:        req.Path,
+		Operation:   req.Operation,
+		EntityID:    entityID,
+		Factors:     cg.Factors,
+		RequestTime: creationTime,
+		ExpireTime:  creationTime.Add(ttl),
+	})
+	if err != nil {
+		c.logger.Error("failed to store control group request", "error", err)
+		return nil, ErrInternalError
+	}
+
+	return &logical.Response{
+		WrapInfo: &wrapping.ResponseWrapInfo{
+			TTL:             ttl,
+			Token:           te.ExternalID,
+			Accessor:        te.Accessor,
+			CreationTime:    creationTime,
+			CreationPath:    req.Path,
+			WrappedEntityID: entityID,
+		},
+	}, nil
+}
+
+// controlGroupFactorsOf returns the names of the factors of the request whose
+// identity groups the entity is a member of
+func (c *Core) controlGroupFactorsOf(cgReq *controlGroupRequest, entityID string) ([]string, error) {
+	directGroups, inheritedGroups, err := c.identityStore.groupsByEntityID(entityID)
+	if err != nil {
+		return nil, err
+	}
+	groups := append(directGroups, inheritedGroups...)
+
+	var factors []string
+	for _, factor := range cgReq.Factors {
+		if factor.Identity == nil {
+			continue
+		}
+		for _, group := range groups {
+			if strutil.StrListContains(factor.Identity.GroupIDs, group.ID) ||
+				(group.NamespaceID == cgReq.NamespaceID && strutil.StrListContains(factor.Identity.GroupNames, group.Name)) {
+				factors = append(factors, factor.Name)
+				break
+			}
+		}
+	}
+
+	return factors, nil
+}
+
+// controlGroupApproved returns whether every factor of the request got the
+// approvals it requires
+func (c *Core) controlGroupApproved(cgReq *controlGroupRequest) (bool, error) {
+	approvals := make(map[string]int, len(cgReq.Factors))
+	for _, authz := range cgReq.Authorizations {
+		factors, err := c.controlGroupFactorsOf(cgReq, authz.EntityID)
+		if err != nil {
+			return false, err
+		}
+		for _, factor := range factors {
+			approvals[factor]++
+		}
+	}
+
+	for _, factor := range cgReq.Factors {
+		if factor.Identity == nil || approvals[factor.Name] < factor.Identity.ApprovalsRequired {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// checkControlGroupApprover ensures that the entity may authorize or deny the
+// control group request
+func (c *Core) checkControlGroupApprover(cgReq *controlGroupRequest, entityID string) error {
+	if entityID == "" {
+		return fmt.Errorf("%w: control group requests can only be authorized by tokens with an entity", logical.ErrPermissionDenied)
+	}
+	if entityID == cgReq.EntityID {
+		return fmt.Errorf("%w: control group requests can't be authorized by their requester", logical.ErrPermissionDenied)
+	}
+
+	factors, err := c.controlGroupFactorsOf(cgReq, entityID)
+	if err != nil {
+		return err
+	}
+	if len(factors) == 0 {
+		return fmt.Errorf("%w: entity is not a member of the groups authorizing the request", logical.ErrPermissionDenied)
+	}
+
+	return nil
+}
+
+// revokeControlGroupToken revokes the control group token of the accessor
+func (c *Core) revokeControlGroupToken(ctx context.Context, accessor string) error {
+	aEntry, err := c.tokenStore.lookupByAccessor(ctx, accessor, false, true)
+	if err != nil {
+		return err
+	}
+	if aEntry == nil {
+		return nil
+	}
+
+	te, err := c.tokenStore.Lookup(ctx, aEntry.TokenID)
+	if err != nil {
+		return err
+	}
+	if te == nil {
+		return nil
+	}
+
+	leaseID, err := c.expiration.CreateOrFetchRevocationLeaseByToken(ctx, te)
+	if err != nil {
+		return err
+	}
+	return c.expiration.Revoke(ctx, leaseID)
+}
+
+// controlGroupRun runs the request of an approved control group request when
+// its token is unwrapped, and returns the marshalled HTTP response
+func (c *Core) controlGroupRun(ctx context.Context, token string) (string, error) {
+	te, err := c.tokenStore.lookupTainted(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	if te == nil {
+		return "", logical.ErrPermissionDenied
+	}
+
+	c.controlGroupLock.Lock()
+	cgReq, err := c.loadControlGroupRequest(ctx, te.Accessor)
+	if err != nil {
+		c.controlGroupLock.Unlock()
+		return "", err
+	}
+	switch {
+	case cgReq == nil:
+		c.controlGroupLock.Unlock()
+		return "control group request not found", logical.ErrInvalidRequest
+	case cgReq.Denied:
+		c.controlGroupLock.Unlock()
+		return "control group request was denied", logical.ErrPermissionDenied
+	case !cgReq.Approved:
+		c.controlGroupLock.Unlock()
+		return "control group request needs further authorizations", logical.ErrInvalidRequest
+	}
+
+	// The request runs once
+	err = c.controlGroupView().Delete(ctx, controlGroupRequestPrefix+cgReq.Accessor)
+	c.controlGroupLock.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	cubbyReq := &logical.Request{
+		Operation:   logical.ReadOperation,
+		Path:        controlGroupCubbyholePath,
+		ClientToken: te.ID,
+	}
+	cubbyReq.SetTokenEntry(te)
+	cubbyResp, err := c.router.Route(ctx, cubbyReq)
+	if err != nil {
+		return "", fmt.Errorf("error looking up control group request: %w", err)
+	}
+
+	leaseID, err := c.expiration.CreateOrFetchRevocationLeaseByToken(ctx, te)
+	if err == nil {
+		err = c.expiration.Revoke(ctx, leaseID)
+	}
+	if err != nil {
+		c.logger.Error("failed to revoke control group token", "error", err)
+	}
+
+	if cubbyResp == nil || cubbyResp.Data == nil {
+		return "no request found inside the cubbyhole", ErrInternalError
+	}
+	raw, ok := cubbyResp.Data["request"].(string)
+	if !ok {
+		return "", errors.New("could not decode request inside the cubbyhole")
+	}
+	storedReq := new(controlGroupStoredRequest)
+	if err := jsonutil.DecodeJSON([]byte(raw), storedReq); err != nil {
+		return "", fmt.Errorf("could not decode request inside the cubbyhole: %w", err)
+	}
+
+	authorizations := make([]*logical.Authz, 0, len(cgReq.Authorizations))
+	for _, authz := range cgReq.Authorizations {
+		authorizations = append(authorizations, &logical.Authz{
+			AuthorizationTime: authz.Time,
+		})
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return "", err
+	}
+	req := &logical.Request{
+		ID:          id,
+		Operation:   storedReq.Operation,
+		Path:        storedReq.Path,
+		Data:        storedReq.Data,
+		ClientToken: storedReq.ClientToken,
+		WrapInfo:    storedReq.WrapInfo,
+		ControlGroup: &logical.ControlGroup{
+			Authorizations: authorizations,
+			RequestTime:    cgReq.RequestTime,
+			Approved:       true,
+			NamespaceID:    cgReq.NamespaceID,
+		},
+	}
+
+	reqNS, err := NamespaceByID(ctx, cgReq.NamespaceID, c)
+	if err != nil {
+		return "", err
+	}
+	if reqNS == nil {
+		return "", namespace.ErrNoNamespace
+	}
+
+	resp, err := c.handleCancelableRequest(namespace.ContextWithNamespace(ctx, reqNS), req)
+	if err != nil {
+		if resp != nil && resp.IsError() {
+			return resp.Error().Error(), err
+		}
+		return "", err
+	}
+	if resp == nil {
+		return "", nil
+	}
+
+	httpResp := logical.LogicalResponseToHTTPResponse(resp)
+	httpResp.RequestID = req.ID
+	marshaled, err := json.Marshal(httpResp)
+	if err != nil {
+		return "", err
+	}
+
+	return string(marshaled), nil
+}
+
+// controlGroupEntityInfo returns the ID and name of the entity
+func (c *Core) controlGroupEntityInfo(entityID string) map[string]interface{} {
+	info := map[string]interface{}{
+		"id":   entityID,
+		"name": "",
+	}
+
+	var entity *identity.Entity
+	if entityID != "" {
+		entity, _ = c.identityStore.MemDBEntityByID(entityID, false)
+	}
+	if entity != nil {
+		info["name"] = entity.Name
+	}
+
+	return info
+}
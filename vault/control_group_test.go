@@ -0,0 +1,209 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const testControlGroupPolicy = `
+path "secret/ca" {
+	capabilities = ["create", "update", "read"]
+	control_group = {
+		factor "approvers" {
+			controlled_capabilities = ["create", "update"]
+			identity {
+				group_names = ["approvers"]
+				approvals = 2
+			}
+		}
+	}
+}
+
+path "sys/control-group/*" {
+	capabilities = ["update"]
+}
+
+path "sys/control-group/request" {
+	capabilities = ["update", "list"]
+}
+`
+
+func TestCore_ControlGroup(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	handle := func(t *testing.T, token string, op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		return c.HandleRequest(ctx, &logical.Request{
+			Operation:   op,
+			Path:        path,
+			Data:        data,
+			ClientToken: token,
+		})
+	}
+	mustHandle := func(t *testing.T, token string, op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := handle(t, token, op, path, data)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v, resp: %#v", err, resp)
+		}
+		return resp
+	}
+
+	mustHandle(t, root, logical.UpdateOperation, "sys/policies/acl/ca", map[string]interface{}{
+		"policy": testControlGroupPolicy,
+	})
+
+	var entityIDs []string
+	for _, name := range []string{"requester", "approver1", "approver2", "outsider"} {
+		resp := mustHandle(t, root, logical.UpdateOperation, "identity/entity", map[string]interface{}{
+			"name": name,
+		})
+		entityID := resp.Data["id"].(string)
+		entityIDs = append(entityIDs, entityID)
+
+		testMakeTokenDirectly(t, c.tokenStore, &logical.TokenEntry{
+			ID:       name,
+			Path:     "auth/token/create",
+			Policies: []string{"ca", "default"},
+			EntityID: entityID,
+			TTL:      time.Hour,
+		})
+	}
+	mustHandle(t, root, logical.UpdateOperation, "identity/group", map[string]interface{}{
+		"name":              "approvers",
+		"member_entity_ids": entityIDs[:3],
+	})
+
+	request := func(t *testing.T) string {
+		t.Helper()
+		resp := mustHandle(t, "requester", logical.UpdateOperation, "secret/ca", map[string]interface{}{
+			"foo": "bar",
+		})
+		if resp == nil || resp.WrapInfo == nil || resp.WrapInfo.Token == "" || resp.WrapInfo.Accessor == "" {
+			t.Fatalf("expected wrap info, resp: %#v", resp)
+		}
+		return resp.WrapInfo.Token
+	}
+
+	// Reads aren't controlled
+	resp := mustHandle(t, "requester", logical.ReadOperation, "secret/ca", nil)
+	if resp != nil && resp.WrapInfo != nil {
+		t.Fatalf("unexpected wrap info, resp: %#v", resp)
+	}
+
+	token := request(t)
+	te, err := c.tokenStore.Lookup(ctx, token)
+	if err != nil || te == nil {
+		t.Fatalf("err: %v, te: %#v", err, te)
+	}
+	accessor := map[string]interface{}{"accessor": te.Accessor}
+
+	resp = mustHandle(t, "approver1", logical.ListOperation, "sys/control-group/request", nil)
+	if keys := resp.Data["keys"].([]string); len(keys) != 1 || keys[0] != te.Accessor {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// The request doesn't run until it is approved
+	if _, err := handle(t, root, logical.UpdateOperation, "sys/wrapping/unwrap", map[string]interface{}{"token": token}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, approver := range []string{"requester", "outsider", root} {
+		_, err := handle(t, approver, logical.UpdateOperation, "sys/control-group/authorize", accessor)
+		if !errors.Is(err, logical.ErrPermissionDenied) {
+			t.Fatalf("expected permission denied for %q, err: %v", approver, err)
+		}
+	}
+
+	for i, approver := range []string{"approver1", "approver1", "approver2"} {
+		resp = mustHandle(t, approver, logical.UpdateOperation, "sys/control-group/authorize", accessor)
+		if approved := resp.Data["approved"].(bool); approved != (i == 2) {
+			t.Fatalf("bad: approval %d: %#v", i, resp.Data)
+		}
+	}
+
+	resp = mustHandle(t, "requester", logical.UpdateOperation, "sys/control-group/request", accessor)
+	if !resp.Data["approved"].(bool) || resp.Data["request_path"] != "secret/ca" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+	if authz := resp.Data["authorizations"].([]map[string]interface{}); len(authz) != 2 {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	resp = mustHandle(t, root, logical.ListOperation, "sys/control-group/request", nil)
+	if len(resp.Data) != 0 {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	mustHandle(t, "requester", logical.UpdateOperation, "sys/wrapping/unwrap", map[string]interface{}{"token": token})
+	resp = mustHandle(t, root, logical.ReadOperation, "secret/ca", nil)
+	if resp == nil || resp.Data["foo"] != "bar" {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	// The request runs once
+	if _, err := handle(t, "requester", logical.UpdateOperation, "sys/wrapping/unwrap", map[string]interface{}{"token": token}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Denied requests can't be run
+	token = request(t)
+	te, err = c.tokenStore.Lookup(ctx, token)
+	if err != nil || te == nil {
+		t.Fatalf("err: %v, te: %#v", err, te)
+	}
+	accessor = map[string]interface{}{"accessor": te.Accessor}
+	mustHandle(t, "approver1", logical.UpdateOperation, "sys/control-group/deny", accessor)
+	if _, err := handle(t, "approver2", logical.UpdateOperation, "sys/control-group/authorize", accessor); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := handle(t, "requester", logical.UpdateOperation, "sys/wrapping/unwrap", map[string]interface{}{"token": token}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestCore_ControlGroupTTL(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	resp, err := c.HandleRequest(ctx, &logical.Request{
+		Operation:   logical.UpdateOperation,
+		Path:        "sys/config/control-group",
+		Data:        map[string]interface{}{"max_ttl": "1h"},
+		ClientToken: root,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v, resp: %#v", err, resp)
+	}
+
+	resp, err = c.HandleRequest(ctx, &logical.Request{
+		Operation:   logical.ReadOperation,
+		Path:        "sys/config/control-group",
+		ClientToken: root,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v, resp: %#v", err, resp)
+	}
+	if resp.Data["max_ttl"].(int64) != 3600 {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	resp, err = c.createControlGroupRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "secret/ca",
+	}, nil, &ControlGroup{TTL: 48 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.WrapInfo.TTL != time.Hour {
+		t.Fatalf("bad: %v", resp.WrapInfo.TTL)
+	}
+}
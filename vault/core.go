@@ -42,6 +42,7 @@ import (
 	"github.com/hashicorp/vault/audit"
 	"github.com/hashicorp/vault/command/server"
 	"github.com/hashicorp/vault/helper/experiments"
+	"github.com/hashicorp/vault/helper/groupresolver"
 	"github.com/hashicorp/vault/helper/identity/mfa"
 	"github.com/hashicorp/vault/helper/locking"
 	"github.com/hashicorp/vault/helper/metricsutil"
@@ -321,6 +322,10 @@ type Core struct {
 	// auditBackends is the mapping of backends to use for this core
 	auditBackends map[string]audit.Factory
 
+	// groupResolvers is the mapping of group resolvers that can be
+	// configured in the identity store
+	groupResolvers map[string]groupresolver.Factory
+
 	// stateLock protects mutable state
 	stateLock locking.RWMutex
 	sealed    *uint32
@@ -411,6 +416,9 @@ type Core struct {
 	// policy store is used to manage named ACL policies
 	policyStore *PolicyStore
 
+	// namespaceStore is used to manage namespaces
+	namespaceStore *NamespaceStore
+
 	// token store is used to manage authentication tokens
 	tokenStore *TokenStore
 
@@ -436,6 +444,18 @@ type Core struct {
 	mfaResponseAuthQueue     *LoginMFAPriorityQueue
 	mfaResponseAuthQueueLock sync.Mutex
 
+	// controlGroupLock serializes changes to control group requests
+	controlGroupLock sync.Mutex
+
+	// disableSealWrap disables seal wrapping regardless of its configuration
+	disableSealWrap bool
+
+	// sealWrapRules selects the storage entries that are seal wrapped
+	sealWrapRules atomic.Value // *sealWrapRules
+
+	// sealRewrap is the state of the job rewrapping seal wrapped entries
+	sealRewrap sealWrapRewrap
+
 	// metricSink is the destination for all metrics that have
 	// a cluster label.
 	metricSink *metricsutil.ClusterMetricSink
@@ -473,6 +493,18 @@ type Core struct {
 	// wrapping information
 	wrappingJWTKey *ecdsa.PrivateKey
 
+	// attestationKey is the key signing the attestation statements of the
+	// plugins, and attestationCert its self-signed certificate
+	attestationKey  *ecdsa.PrivateKey
+	attestationCert *x509.Certificate
+
+	// managedKeyRegistry holds the managed keys, keys held by a KMS that
+	// mounts sign with
+	managedKeyRegistry *managedKeyRegistry
+
+	// deletedMountsLock serializes the updates of the soft-deleted mounts
+	deletedMountsLock sync.Mutex
+
 	//
 	// Cluster information
 	//
@@ -543,6 +575,10 @@ type Core struct {
 	// pluginFilePermissions is the permissions of the plugin files and directory
 	pluginFilePermissions int
 
+	// pluginCgroupParent is the cgroup the cgroups limiting the resources of
+	// the plugin processes are created in
+	pluginCgroupParent string
+
 	// pluginCatalog is used to manage plugin configurations
 	pluginCatalog *PluginCatalog
 
@@ -610,12 +646,21 @@ type Core struct {
 	raftFollowerStates *raft.FollowerStates
 	// Stop channel for raft TLS rotations
 	raftTLSRotationStopCh chan struct{}
+
+	// raftAutoSnapshots takes the automatic snapshots of the raft storage on
+	// the active node
+	raftAutoSnapshots     *raftAutoSnapshots
+	raftAutoSnapshotsLock sync.RWMutex
 	// Stores the pending peers we are waiting to give answers
 	pendingRaftPeers *sync.Map
 
 	// rawConfig stores the config as-is from the provided server configuration.
 	rawConfig *atomic.Value
 
+	// configReloadFunc is the ConfigReloadFunc set by the server, used by
+	// sys/config/reload/config
+	configReloadFunc *atomic.Value
+
 	coreNumber int
 
 	// secureRandomReader is the reader used for CSP operations
@@ -651,6 +696,11 @@ type Core struct {
 
 	autoRotateCancel context.CancelFunc
 
+	// lastAutoRotation is the outcome of the last automatic barrier key
+	// rotation attempted by this node
+	lastAutoRotation     barrierAutoRotation
+	lastAutoRotationLock sync.RWMutex
+
 	// number of workers to use for lease revocation in the expiration manager
 	numExpirationWorkers int
 
@@ -733,6 +783,8 @@ type CoreConfig struct {
 
 	AuditBackends map[string]audit.Factory
 
+	GroupResolvers map[string]groupresolver.Factory
+
 	Physical physical.Backend
 
 	StorageType string
@@ -771,6 +823,14 @@ type CoreConfig struct {
 	// Custom cache size for the LRU cache on the physical backend, or zero for default
 	CacheSize int
 
+	// How long the entries of the LRU cache on the physical backend are
+	// cached, or zero until they are evicted
+	CacheTTL time.Duration
+
+	// Disables the caching of missing keys in the LRU cache on the physical
+	// backend
+	DisableNegativeCache bool
+
 	// Set as the leader address for HA
 	RedirectAddr string
 
@@ -799,6 +859,8 @@ type CoreConfig struct {
 
 	PluginFilePermissions int
 
+	PluginCgroupParent string
+
 	DisableSealWrap bool
 
 	RawConfig *server.Config
@@ -1000,6 +1062,7 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 		metricSink:                     conf.MetricSink,
 		secureRandomReader:             conf.SecureRandomReader,
 		rawConfig:                      new(atomic.Value),
+		configReloadFunc:               new(atomic.Value),
 		recoveryMode:                   conf.RecoveryMode,
 		postUnsealStarted:              new(uint32),
 		raftInfo:                       new(atomic.Value),
@@ -1010,6 +1073,7 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 		numExpirationWorkers:           conf.NumExpirationWorkers,
 		raftFollowerStates:             raft.NewFollowerStates(),
 		disableAutopilot:               conf.DisableAutopilot,
+		disableSealWrap:                conf.DisableSealWrap,
 		enableResponseHeaderHostname:   conf.EnableResponseHeaderHostname,
 		enableResponseHeaderRaftNodeID: conf.EnableResponseHeaderRaftNodeID,
 		mountMigrationTracker:          &sync.Map{},
@@ -1031,6 +1095,7 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 
 	c.router.logger = c.logger.Named("router")
 	c.allLoggers = append(c.allLoggers, c.router.logger)
+	c.router.routeMetrics = newRouteMetrics(c.metricSink)
 
 	c.inFlightReqData = &InFlightRequests{
 		InFlightReqMap:   &sync.Map{},
@@ -1170,6 +1235,7 @@ func NewCore(conf *CoreConfig) (*Core, error) {
 	if conf.PluginFilePermissions != 0 {
 		c.pluginFilePermissions = conf.PluginFilePermissions
 	}
+	c.pluginCgroupParent = conf.PluginCgroupParent
 
 	createSecondaries(c, conf)
 
@@ -1228,6 +1294,12 @@ func NewCore(conf *CoreConfig) (*Core, error) {
 	}
 	c.auditBackends = auditBackends
 
+	groupResolvers := make(map[string]groupresolver.Factory)
+	for k, f := range conf.GroupResolvers {
+		groupResolvers[k] = f
+	}
+	c.groupResolvers = groupResolvers
+
 	uiStoragePrefix := systemBarrierPrefix + "ui"
 	c.uiConfig = NewUIConfig(conf.EnableUI, physical.NewView(c.physical, uiStoragePrefix), NewBarrierView(c.barrier, uiStoragePrefix))
 
@@ -1259,6 +1331,7 @@ func NewCore(conf *CoreConfig) (*Core, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.quotaManager.SetEntityResolver(c.quotaEntityResolver)
 
 	err = c.adjustForSealMigration(conf.UnwrapSeal)
 	if err != nil {
@@ -2139,6 +2212,7 @@ func (c *Core) sealInternalWithOptions(grabStateLock, keepHALock, performCleanup
 	c.metricSink.SetGaugeWithLabels([]string{"core", "unsealed"}, 0, nil)
 
 	c.logger.Info("marked as sealed")
+	c.sendCoreEvent(namespace.RootContext(nil), eventTypeCoreSealed)
 
 	// Clear forwarding clients
 	c.requestForwardingConnectionLock.Lock()
@@ -2295,10 +2369,19 @@ func (s standardUnsealStrategy) unseal(ctx context.Context, logger log.Logger, c
 		if err := c.ensureWrappingKey(ctx); err != nil {
 			return err
 		}
+		if err := c.ensureAttestationKey(ctx); err != nil {
+			return err
+		}
 	}
 	if err := c.setupPluginCatalog(ctx); err != nil {
 		return err
 	}
+	if err := c.setupNamespaceStore(ctx); err != nil {
+		return err
+	}
+	if err := c.loadSealWrapConfig(ctx); err != nil {
+		return err
+	}
 	if err := c.loadMounts(ctx); err != nil {
 		return err
 	}
@@ -2347,6 +2430,7 @@ func (s standardUnsealStrategy) unseal(ctx context.Context, logger log.Logger, c
 		if err := c.setupExpiration(expireLeaseStrategyFairsharing); err != nil {
 			return err
 		}
+		c.startDeletedMountsPurge(ctx)
 		if err := c.loadAudits(ctx); err != nil {
 			return err
 		}
@@ -2583,12 +2667,19 @@ func (c *Core) preSeal() error {
 	if err := c.teardownPolicyStore(); err != nil {
 		result = multierror.Append(result, fmt.Errorf("error tearing down policy store: %w", err))
 	}
+	if err := c.teardownManagedKeyRegistry(); err != nil {
+		result = multierror.Append(result, fmt.Errorf("error tearing down managed key registry: %w", err))
+	}
 	if err := c.stopRollback(); err != nil {
 		result = multierror.Append(result, fmt.Errorf("error stopping rollback: %w", err))
 	}
 	if err := c.unloadMounts(context.Background()); err != nil {
 		result = multierror.Append(result, fmt.Errorf("error unloading mounts: %w", err))
 	}
+	if err := c.teardownNamespaceStore(); err != nil {
+		result = multierror.Append(result, fmt.Errorf("error tearing down namespace store: %w", err))
+	}
+	c.sealWrapRules.Store((*sealWrapRules)(nil))
 
 	if err := enterprisePreSeal(c); err != nil {
 		result = multierror.Append(result, err)
@@ -2659,6 +2750,26 @@ func (c *Core) StorageType() string {
 	return c.storageType
 }
 
+// FlushStorageCache removes the entries of the physical cache with the given
+// key prefixes, and returns the number of removed entries.
+func (c *Core) FlushStorageCache(ctx context.Context, prefixes []string) (int, error) {
+	purger, ok := c.physicalCache.(physical.PrefixPurgemonster)
+	if !ok {
+		return 0, fmt.Errorf("the storage cache can't be flushed by prefix")
+	}
+
+	var flushed int
+	for _, prefix := range prefixes {
+		flushed += purger.PurgePrefix(ctx, prefix)
+	}
+
+	if c.logger.IsInfo() {
+		c.logger.Info("flushed storage cache", "prefixes", prefixes, "entries", flushed)
+	}
+
+	return flushed, nil
+}
+
 func (c *Core) Logger() log.Logger {
 	return c.logger
 }
@@ -3089,6 +3200,31 @@ func (c *Core) SetConfig(conf *server.Config) {
 	c.logger.Debug("set config", "sanitized config", string(bz))
 }
 
+// ConfigReloadResult reports the settings applied when reloading the server
+// configuration, and the changed settings that require a restart to apply.
+type ConfigReloadResult struct {
+	Applied         []string
+	RestartRequired []string
+}
+
+// ConfigReloadFunc reloads the server configuration
+type ConfigReloadFunc func(ctx context.Context) (*ConfigReloadResult, error)
+
+// SetConfigReloadFunc sets the function used to reload the server
+// configuration through the sys/config/reload/config endpoint.
+func (c *Core) SetConfigReloadFunc(f ConfigReloadFunc) {
+	c.configReloadFunc.Store(f)
+}
+
+// ReloadConfig reloads the server configuration, as on SIGHUP.
+func (c *Core) ReloadConfig(ctx context.Context) (*ConfigReloadResult, error) {
+	f, _ := c.configReloadFunc.Load().(ConfigReloadFunc)
+	if f == nil {
+		return nil, errors.New("the server configuration can't be reloaded")
+	}
+	return f(ctx)
+}
+
 func (c *Core) GetListenerCustomResponseHeaders(listenerAdd string) *ListenerCustomHeaders {
 	customHeaders := c.customListenerHeader.Load()
 	if customHeaders == nil {
@@ -3273,6 +3409,12 @@ func (c *Core) MatchingMount(ctx context.Context, reqPath string) string {
 	return c.router.MatchingMount(ctx, reqPath)
 }
 
+// StreamingPath returns whether the request body and response of the given
+// request path are streamed rather than buffered.
+func (c *Core) StreamingPath(ctx context.Context, reqPath string) bool {
+	return c.router.StreamingPath(ctx, reqPath)
+}
+
 func (c *Core) setupQuotas(ctx context.Context, isPerfStandby bool) error {
 	if c.quotaManager == nil {
 		return nil
@@ -3303,6 +3445,20 @@ func (c *Core) ApplyRateLimitQuota(ctx context.Context, req *quotas.Request) (qu
 	return resp, nil
 }
 
+// quotaEntityResolver returns the entity of the client token, for rate limit
+// quotas grouping clients by entity. Tokens which can't be looked up, e.g. on
+// standbys, are treated as having no entity.
+func (c *Core) quotaEntityResolver(ctx context.Context, clientToken string) string {
+	c.stateLock.RLock()
+	defer c.stateLock.RUnlock()
+
+	te, err := c.LookupToken(ctx, clientToken)
+	if err != nil || te == nil {
+		return ""
+	}
+	return te.EntityID
+}
+
 // RateLimitAuditLoggingEnabled returns if the quota configuration allows audit
 // logging of request rejections due to rate limiting quota rule violations.
 func (c *Core) RateLimitAuditLoggingEnabled() bool {
@@ -3363,16 +3519,88 @@ func (c *Core) checkBarrierAutoRotate(ctx context.Context) {
 			// the replication canary
 			c.logger.Info("automatic barrier key rotation triggered", "reason", reason)
 
-			_, err := c.systemBackend.handleRotate(ctx, nil, nil)
+			resp, err := c.systemBackend.handleRotate(ctx, nil, nil)
+			if err == nil && resp.IsError() {
+				err = resp.Error()
+			}
 			if err != nil {
 				c.logger.Error("error automatically rotating barrier key", "error", err)
+				metrics.IncrCounterWithLabels(barrierRotationErrorsMetric, 1, []metrics.Label{{"reason", reason}})
 			} else {
 				metrics.IncrCounter(barrierRotationsMetric, 1)
 			}
+
+			c.lastAutoRotationLock.Lock()
+			c.lastAutoRotation = barrierAutoRotation{
+				Time:   time.Now(),
+				Reason: reason,
+				Err:    err,
+			}
+			c.lastAutoRotationLock.Unlock()
+		}
+
+		if status, err := c.barrierRotationStatus(); err == nil && status.Enabled {
+			metrics.SetGauge(barrierRemainingOperationsMetric, float32(status.RemainingOperations))
+			if !status.NextRotationTime.IsZero() {
+				metrics.SetGauge(barrierTimeUntilRotationMetric, float32(time.Until(status.NextRotationTime).Seconds()))
+			}
 		}
 	}
 }
 
+// barrierAutoRotation is the outcome of an automatic barrier key rotation.
+type barrierAutoRotation struct {
+	Time   time.Time
+	Reason string
+	Err    error
+}
+
+// barrierRotationStatus is how far the active barrier key is from being
+// rotated automatically.
+type barrierRotationStatus struct {
+	Enabled bool
+
+	// RemainingOperations is the number of encryptions left before the key
+	// reaches the maximum operations of the rotation config.
+	RemainingOperations int64
+
+	// NextRotationTime is when the key reaches the rotation interval, if
+	// there is one.
+	NextRotationTime time.Time
+
+	LastAutoRotation barrierAutoRotation
+}
+
+func (c *Core) barrierRotationStatus() (*barrierRotationStatus, error) {
+	info, err := c.barrier.ActiveKeyInfo()
+	if err != nil {
+		return nil, err
+	}
+	rotConfig, err := c.barrier.RotationConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &barrierRotationStatus{
+		Enabled: !rotConfig.Disabled,
+	}
+	if status.Enabled {
+		status.RemainingOperations = rotConfig.MaxOperations - info.Encryptions
+		if status.RemainingOperations < 0 {
+			status.RemainingOperations = 0
+		}
+		if rotConfig.Interval > 0 {
+			status.NextRotationTime = info.InstallTime.Add(rotConfig.Interval)
+		}
+	}
+
+	c.lastAutoRotationLock.RLock()
+	status.LastAutoRotation = c.lastAutoRotation
+	c.lastAutoRotationLock.RUnlock()
+
+	return status, nil
+}
+
 func (c *Core) isPrimary() bool {
 	return !c.ReplicationState().HasState(consts.ReplicationPerformanceSecondary | consts.ReplicationDRSecondary)
 }
@@ -8,6 +8,8 @@ package vault
 import (
 	"context"
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/helper/namespace"
@@ -42,13 +44,19 @@ func coreInit(c *Core, conf *CoreConfig) error {
 	sealUnwrapperLogger := conf.Logger.Named("storage.sealunwrapper")
 	c.allLoggers = append(c.allLoggers, sealUnwrapperLogger)
 	c.sealUnwrapper = NewSealUnwrapper(phys, sealUnwrapperLogger)
+	sealUnwrapperFor(c.sealUnwrapper).policy = c
 	// Wrap the physical backend in a cache layer if enabled
 	cacheLogger := c.baseLogger.Named("storage.cache")
 	c.allLoggers = append(c.allLoggers, cacheLogger)
+	cacheConfig := physical.CacheConfig{
+		Size:                 conf.CacheSize,
+		TTL:                  conf.CacheTTL,
+		DisableNegativeCache: conf.DisableNegativeCache,
+	}
 	if txnOK {
-		c.physical = physical.NewTransactionalCache(c.sealUnwrapper, conf.CacheSize, cacheLogger, c.MetricSink().Sink)
+		c.physical = physical.NewTransactionalCacheWithConfig(c.sealUnwrapper, cacheConfig, cacheLogger, c.MetricSink().Sink)
 	} else {
-		c.physical = physical.NewCache(c.sealUnwrapper, conf.CacheSize, cacheLogger, c.MetricSink().Sink)
+		c.physical = physical.NewCacheWithConfig(c.sealUnwrapper, cacheConfig, cacheLogger, c.MetricSink().Sink)
 	}
 	c.physicalCache = c.physical.(physical.ToggleablePurgemonster)
 
@@ -67,11 +75,19 @@ func (c *Core) setupReplicationResolverHandler() error {
 func NewPolicyMFABackend(core *Core, logger hclog.Logger) *PolicyMFABackend { return nil }
 
 func (c *Core) barrierViewForNamespace(namespaceId string) (*BarrierView, error) {
-	if namespaceId != namespace.RootNamespaceID {
-		return nil, fmt.Errorf("failed to find barrier view for non-root namespace")
+	if namespaceId == namespace.RootNamespaceID {
+		return c.systemBarrierView, nil
+	}
+
+	ns, err := NamespaceByID(namespace.RootContext(nil), namespaceId, c)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil {
+		return nil, fmt.Errorf("failed to find barrier view for namespace %q", namespaceId)
 	}
 
-	return c.systemBarrierView, nil
+	return c.namespacedView(ns, c.systemBarrierView), nil
 }
 
 func (c *Core) UndoLogsEnabled() bool            { return false }
@@ -90,11 +106,8 @@ func preUnsealInternal(context.Context, *Core) error { return nil }
 func postSealInternal(*Core) {}
 
 func preSealPhysical(c *Core) {
-	switch c.sealUnwrapper.(type) {
-	case *sealUnwrapper:
-		c.sealUnwrapper.(*sealUnwrapper).stopUnwraps()
-	case *transactionalSealUnwrapper:
-		c.sealUnwrapper.(*transactionalSealUnwrapper).stopUnwraps()
+	if d := sealUnwrapperFor(c.sealUnwrapper); d != nil {
+		d.stopUnwraps()
 	}
 
 	// Purge the cache
@@ -103,11 +116,8 @@ func preSealPhysical(c *Core) {
 }
 
 func postUnsealPhysical(c *Core) error {
-	switch c.sealUnwrapper.(type) {
-	case *sealUnwrapper:
-		c.sealUnwrapper.(*sealUnwrapper).runUnwraps()
-	case *transactionalSealUnwrapper:
-		c.sealUnwrapper.(*transactionalSealUnwrapper).runUnwraps()
+	if d := sealUnwrapperFor(c.sealUnwrapper); d != nil {
+		d.runUnwraps()
 	}
 	return nil
 }
@@ -116,7 +126,7 @@ func loadPolicyMFAConfigs(context.Context, *Core) error { return nil }
 
 func shouldStartClusterListener(*Core) bool { return true }
 
-func hasNamespaces(*Core) bool { return false }
+func hasNamespaces(*Core) bool { return true }
 
 func (c *Core) Features() license.Features {
 	return license.FeatureNone
@@ -127,9 +137,12 @@ func (c *Core) HasFeature(license.Features) bool {
 }
 
 func (c *Core) collectNamespaces() []*namespace.Namespace {
-	return []*namespace.Namespace{
-		namespace.RootNamespace,
+	if c.namespaceStore == nil {
+		return []*namespace.Namespace{
+			namespace.RootNamespace,
+		}
 	}
+	return c.namespaceStore.listNamespaces()
 }
 
 func (c *Core) HasWALState(required *logical.WALState, perfStandby bool) bool {
@@ -162,24 +175,92 @@ func (c *Core) initSealsForMigration() {}
 
 func (c *Core) postSealMigration(ctx context.Context) error { return nil }
 
-func (c *Core) applyLeaseCountQuota(_ context.Context, in *quotas.Request) (*quotas.Response, error) {
-	return &quotas.Response{Allowed: true}, nil
+func (c *Core) applyLeaseCountQuota(ctx context.Context, in *quotas.Request) (*quotas.Response, error) {
+	if c.quotaManager == nil {
+		return &quotas.Response{Allowed: true}, nil
+	}
+
+	in.Type = quotas.TypeLeaseCount
+	resp, err := c.quotaManager.ApplyQuota(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
 
 func (c *Core) ackLeaseQuota(access quotas.Access, leaseGenerated bool) error {
-	return nil
+	if c.quotaManager == nil {
+		return nil
+	}
+	return c.quotaManager.AckLease(access)
 }
 
+// quotaLeaseWalker calls the callback with the quota request matching each
+// lease known to the expiration manager.
 func (c *Core) quotaLeaseWalker(ctx context.Context, callback func(request *quotas.Request) bool) error {
+	expiration := c.expiration
+	if expiration == nil {
+		return nil
+	}
+
+	walkFn := func(key, value interface{}) bool {
+		pending := value.(pendingInfo)
+		req := c.leaseQuotaRequest(ctx, key.(string), pending.loginRole)
+		if req == nil {
+			return true
+		}
+		return callback(req)
+	}
+	expiration.pending.Range(walkFn)
+	expiration.nonexpiring.Range(walkFn)
+
 	return nil
 }
 
 func (c *Core) quotasHandleLeases(ctx context.Context, action quotas.LeaseAction, leases []*quotas.QuotaLeaseInformation) error {
-	return nil
+	if c.quotaManager == nil {
+		return nil
+	}
+
+	reqs := make([]*quotas.Request, 0, len(leases))
+	for _, lease := range leases {
+		if req := c.leaseQuotaRequest(ctx, lease.LeaseId, lease.Role); req != nil {
+			reqs = append(reqs, req)
+		}
+	}
+	return c.quotaManager.HandleLeases(action, reqs)
+}
+
+// leaseQuotaRequest returns the quota request matching the request which
+// created the given lease, or nil if the lease doesn't belong to a mount.
+func (c *Core) leaseQuotaRequest(ctx context.Context, leaseID, role string) *quotas.Request {
+	_, nsID := namespace.SplitIDFromString(leaseID)
+	if nsID == "" {
+		nsID = namespace.RootNamespaceID
+	}
+	ns, err := c.NamespaceByID(ctx, nsID)
+	if err != nil || ns == nil {
+		return nil
+	}
+
+	mountPath := c.router.MatchingMount(namespace.ContextWithNamespace(ctx, ns), leaseID)
+	if mountPath == "" {
+		return nil
+	}
+
+	return &quotas.Request{
+		Path:          path.Dir(leaseID),
+		MountPath:     strings.TrimPrefix(mountPath, ns.Path),
+		Role:          role,
+		NamespacePath: ns.Path,
+	}
 }
 
 func (c *Core) namespaceByPath(path string) *namespace.Namespace {
-	return namespace.RootNamespace
+	if c.namespaceStore == nil {
+		return namespace.RootNamespace
+	}
+	return c.namespaceStore.namespaceByPath(path)
 }
 
 func (c *Core) AllowForwardingViaHeader() bool {
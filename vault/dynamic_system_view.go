@@ -427,3 +427,9 @@ func (d dynamicSystemView) ClusterID(ctx context.Context) (string, error) {
 
 	return clusterInfo.ID, nil
 }
+
+// SignAttestation signs the statement with the attestation key of the
+// cluster, implementing logical.AttestationSystemView.
+func (d dynamicSystemView) SignAttestation(_ context.Context, statement []byte) ([]byte, []byte, error) {
+	return d.core.signAttestation(statement)
+}
@@ -66,8 +66,9 @@ type asyncChanNode struct {
 }
 
 var (
-	_ eventlogger.Node    = (*asyncChanNode)(nil)
-	_ logical.EventSender = (*pluginEventBus)(nil)
+	_ eventlogger.Node        = (*asyncChanNode)(nil)
+	_ logical.EventSender     = (*pluginEventBus)(nil)
+	_ logical.EventSubscriber = (*pluginEventBus)(nil)
 )
 
 // Start starts the event bus, allowing events to be written.
@@ -131,6 +132,34 @@ func (bus *pluginEventBus) Send(ctx context.Context, eventType logical.EventType
 	return bus.bus.SendInternal(ctx, bus.namespace, bus.pluginInfo, eventType, data)
 }
 
+// Subscribe subscribes to the events sent in the namespace of the plugin whose
+// type matches the pattern, including the events sent by Vault itself.
+func (bus *pluginEventBus) Subscribe(ctx context.Context, pattern string) (<-chan *logical.EventReceived, error) {
+	ch, cancel, err := bus.bus.Subscribe(ctx, bus.namespace, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *logical.EventReceived)
+	go func() {
+		defer close(events)
+		defer cancel()
+		for {
+			select {
+			case event := <-ch:
+				select {
+				case events <- event.Payload.(*logical.EventReceived):
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
 func init() {
 	// TODO: maybe this should relate to the Vault core somehow?
 	sourceUrl, err := url.Parse("https://vaultproject.io/")
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+
+	"github.com/hashicorp/vault/helper/experiments"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Event types sent by Vault itself, rather than by its plugins.
+const (
+	eventTypeMountEnabled  logical.EventType = "mount/enabled"
+	eventTypeMountDisabled logical.EventType = "mount/disabled"
+	eventTypeAuthEnabled   logical.EventType = "auth/enabled"
+	eventTypeAuthDisabled  logical.EventType = "auth/disabled"
+	eventTypeLeaseExpired  logical.EventType = "lease/expired"
+	eventTypeLeaseRevoked  logical.EventType = "lease/revoked"
+	eventTypeCoreSealed    logical.EventType = "core/sealed"
+
+	eventTypeWrappingDelivered logical.EventType = "wrapping/delivered"
+)
+
+// sendCoreEvent sends an event in the namespace of the context to the event
+// bus. Events are best effort, so failures are logged rather than returned.
+func (c *Core) sendCoreEvent(ctx context.Context, eventType logical.EventType, metadataPairs ...string) {
+	if c.events == nil || !c.IsExperimentEnabled(experiments.VaultExperimentEventsAlpha1) {
+		return
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		c.logger.Warn("error sending event", "event_type", eventType, "error", err)
+		return
+	}
+
+	ev, err := logical.NewEvent()
+	if err != nil {
+		c.logger.Warn("error creating event", "event_type", eventType, "error", err)
+		return
+	}
+
+	metadata := make(map[string]interface{}, len(metadataPairs)/2)
+	for i := 0; i+1 < len(metadataPairs); i += 2 {
+		metadata[metadataPairs[i]] = metadataPairs[i+1]
+	}
+	ev.Metadata, err = structpb.NewStruct(metadata)
+	if err != nil {
+		c.logger.Warn("error encoding event metadata", "event_type", eventType, "error", err)
+		return
+	}
+
+	if err := c.events.SendInternal(ctx, ns, nil, eventType, ev); err != nil {
+		c.logger.Warn("error sending event", "event_type", eventType, "error", err)
+	}
+}
@@ -57,3 +57,47 @@ func TestCanSendEventsFromBuiltinPlugin(t *testing.T) {
 		t.Error("timeout waiting for event")
 	}
 }
+
+func TestCoreEvents_mounts(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	ctx := namespace.RootContext(nil)
+	ch, cancel, err := c.events.Subscribe(ctx, namespace.RootNamespace, "mount/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+
+	me := &MountEntry{
+		Table: mountTableType,
+		Path:  "foo/",
+		Type:  "kv",
+	}
+	if err := c.mount(ctx, me); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.unmount(ctx, "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// events are delivered asynchronously, so they may arrive in any order
+	expected := map[string]bool{
+		string(eventTypeMountEnabled):  true,
+		string(eventTypeMountDisabled): true,
+	}
+	for len(expected) > 0 {
+		select {
+		case receivedEvent := <-ch:
+			received := receivedEvent.Payload.(*logical.EventReceived)
+			if !expected[received.EventType] {
+				t.Fatalf("unexpected event type %s", received.EventType)
+			}
+			delete(expected, received.EventType)
+			if path := received.Event.Metadata.AsMap()["path"]; path != "foo/" {
+				t.Fatalf("expected path foo/, got %v", path)
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatalf("timeout waiting for events %v", expected)
+		}
+	}
+}
@@ -154,8 +154,14 @@ type ExpirationManager struct {
 	// request. This value should only be set by tests.
 	testRegisterAuthFailure uberAtomic.Bool
 
-	jobManager      *fairshare.JobManager
-	revokeRetryBase time.Duration
+	jobManager       *fairshare.JobManager
+	expirationShards *expirationShards
+	revokeRetryBase  time.Duration
+
+	// revocationQueues tracks the mounts queue metrics were last emitted
+	// for, so that the gauges of drained queues are reset. It is only
+	// accessed by emitMetrics.
+	revocationQueues map[string]struct{}
 }
 
 type ExpireLeaseStrategy func(context.Context, *ExpirationManager, string, *namespace.Namespace)
@@ -233,6 +239,10 @@ func (r *revocationJob) Execute() error {
 	err := r.m.Revoke(revokeCtx, r.leaseID)
 	r.m.coreStateLock.RUnlock()
 
+	if err == nil {
+		r.m.core.sendCoreEvent(r.nsCtx, eventTypeLeaseExpired, "lease_id", r.leaseID)
+	}
+
 	return err
 }
 
@@ -281,17 +291,7 @@ func (r *revocationJob) OnFailure(err error) {
 }
 
 func expireLeaseStrategyFairsharing(ctx context.Context, m *ExpirationManager, leaseID string, ns *namespace.Namespace) {
-	nsCtx := namespace.ContextWithNamespace(ctx, ns)
-
-	mountAccessor := m.getLeaseMountAccessorLocked(ctx, leaseID)
-
-	job, err := newRevocationJob(nsCtx, leaseID, ns, m)
-	if err != nil {
-		m.logger.Warn("error creating revocation job", "error", err)
-		return
-	}
-
-	m.jobManager.AddJob(job, mountAccessor)
+	m.expirationShards.enqueue(ctx, leaseID, ns)
 }
 
 func (r *revocationJob) revokeExponentialBackoff(attempt uint8) time.Duration {
@@ -360,8 +360,10 @@ func NewExpirationManager(c *Core, view *BarrierView, e ExpireLeaseStrategy, log
 		logLeaseExpirations: os.Getenv("VAULT_SKIP_LOGGING_LEASE_EXPIRATIONS") == "",
 		expireFunc:          e,
 
-		jobManager:      jobManager,
-		revokeRetryBase: c.expirationRevokeRetryBase,
+		jobManager:       jobManager,
+		expirationShards: newExpirationShards(numExpirationShards),
+		revokeRetryBase:  c.expirationRevokeRetryBase,
+		revocationQueues: make(map[string]struct{}),
 	}
 	if exp.revokeRetryBase == 0 {
 		exp.revokeRetryBase = revokeRetryBase
@@ -374,6 +376,7 @@ func NewExpirationManager(c *Core, view *BarrierView, e ExpireLeaseStrategy, log
 	}
 
 	go exp.uniquePoliciesGc()
+	exp.expirationShards.start(exp, exp.quitCh)
 
 	return exp
 }
@@ -1053,6 +1056,7 @@ func (m *ExpirationManager) revokeCommon(ctx context.Context, leaseID string, fo
 	if m.logger.IsInfo() && !skipToken && m.logLeaseExpirations {
 		m.logger.Info("revoked lease", "lease_id", leaseID)
 	}
+	m.core.sendCoreEvent(namespace.ContextWithNamespace(ctx, le.namespace), eventTypeLeaseRevoked, "lease_id", leaseID)
 	if m.logger.IsWarn() && !skipToken && le.isIncorrectlyNonExpiring() {
 		var accessor string
 		if le.Auth != nil {
@@ -1403,6 +1407,16 @@ func (m *ExpirationManager) RenewToken(ctx context.Context, req *logical.Request
 		if resp.Auth.Alias != nil {
 			mountAccessor = resp.Auth.Alias.MountAccessor
 		}
+
+		// Failing to resolve groups externally shouldn't fail the renewal,
+		// the previously resolved groups are kept instead.
+		groupAliases, err := m.core.identityStore.resolveGroupAliases(ctx, resp.Auth.Alias, resp.Auth.GroupAliases)
+		if err != nil {
+			m.logger.Warn("failed to resolve groups on renewal", "error", err)
+		} else {
+			resp.Auth.GroupAliases = groupAliases
+		}
+
 		validAliases, err := m.core.identityStore.refreshExternalGroupMembershipsByEntityID(ctx, resp.Auth.EntityID, resp.Auth.GroupAliases, mountAccessor)
 		if err != nil {
 			return nil, err
@@ -2375,6 +2389,9 @@ func (m *ExpirationManager) emitMetrics() {
 	metrics.SetGauge([]string{"expire", "num_leases"}, float32(allLeases))
 
 	metrics.SetGauge([]string{"expire", "num_irrevocable_leases"}, float32(irrevocableLeases))
+
+	m.emitRevocationQueueMetrics()
+
 	// Check if lease count is greater than the threshold
 	if allLeases > maxLeaseThreshold {
 		if atomic.LoadUint32(m.leaseCheckCounter) > 59 {
@@ -2386,6 +2403,38 @@ func (m *ExpirationManager) emitMetrics() {
 	}
 }
 
+// emitRevocationQueueMetrics reports the depth of the revocation queues of
+// each mount, and the number of workers revoking its leases.
+func (m *ExpirationManager) emitRevocationQueueMetrics() {
+	metrics.SetGauge([]string{"expire", "revocation", "pending_expirations"}, float32(m.expirationShards.pendingCount()))
+
+	queueLengths := m.jobManager.GetWorkQueueLengths()
+	workerCounts := m.jobManager.GetWorkerCounts()
+
+	queues := make(map[string]struct{}, len(queueLengths)+len(workerCounts))
+	for mountAccessor := range queueLengths {
+		queues[mountAccessor] = struct{}{}
+	}
+	for mountAccessor := range workerCounts {
+		queues[mountAccessor] = struct{}{}
+	}
+	// Report drained queues one last time, so their gauges don't linger.
+	for mountAccessor := range m.revocationQueues {
+		queues[mountAccessor] = struct{}{}
+	}
+
+	m.revocationQueues = make(map[string]struct{}, len(queueLengths)+len(workerCounts))
+	for mountAccessor := range queues {
+		labels := []metrics.Label{{Name: "mount_accessor", Value: mountAccessor}}
+		metrics.SetGaugeWithLabels([]string{"expire", "revocation", "queue_depth"}, float32(queueLengths[mountAccessor]), labels)
+		metrics.SetGaugeWithLabels([]string{"expire", "revocation", "active_workers"}, float32(workerCounts[mountAccessor]), labels)
+
+		if queueLengths[mountAccessor] > 0 || workerCounts[mountAccessor] > 0 {
+			m.revocationQueues[mountAccessor] = struct{}{}
+		}
+	}
+}
+
 func (m *ExpirationManager) leaseAggregationMetrics(ctx context.Context, consts metricsutil.TelemetryConstConfig) ([]metricsutil.GaugeLabelValues, error) {
 	expiryTimes := make(map[metricsutil.LeaseExpiryLabel]int)
 	leaseEpsilon := consts.LeaseMetricsEpsilon
@@ -2578,12 +2627,6 @@ func (m *ExpirationManager) getNamespaceFromLeaseID(ctx context.Context, leaseID
 	return leaseNS, nil
 }
 
-func (m *ExpirationManager) getLeaseMountAccessorLocked(ctx context.Context, leaseID string) string {
-	m.coreStateLock.RLock()
-	defer m.coreStateLock.RUnlock()
-	return m.getLeaseMountAccessor(ctx, leaseID)
-}
-
 // note: this function must be called with m.coreStateLock held for read
 func (m *ExpirationManager) getLeaseMountAccessor(ctx context.Context, leaseID string) string {
 	mount := m.core.router.MatchingMountEntry(ctx, leaseID)
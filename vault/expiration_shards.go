@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/helper/fairshare"
+	"github.com/hashicorp/vault/helper/namespace"
+)
+
+// numExpirationShards is the number of shards expired leases are handed to
+// before being queued for revocation.
+const numExpirationShards = 16
+
+// expirationBatchSize is the maximum number of expired leases whose mounts
+// are resolved while holding the state lock once.
+const expirationBatchSize = 1000
+
+// expiredLease is a lease whose timer fired, waiting to be queued for
+// revocation.
+type expiredLease struct {
+	ctx       context.Context
+	leaseID   string
+	ns        *namespace.Namespace
+	expiredAt time.Time
+}
+
+// expirationShard collects expired leases of a subset of the lease IDs. Its
+// queue is drained by a single goroutine.
+type expirationShard struct {
+	l       sync.Mutex
+	queue   []expiredLease
+	newWork chan struct{} // must be buffered
+}
+
+// expirationShards decouples pending lease timers from the revocation job
+// manager. Expiring a lease only appends it to the queue of its shard; the
+// shards then resolve the mounts of the leases under a single state lock and
+// hand them to the job manager in a batch per mount. Without it, a storm of
+// expiring leases has each timer goroutine contend for the state lock and
+// the job manager lock, starving the job manager of the time to dispatch
+// revocations to its workers.
+type expirationShards struct {
+	shards []*expirationShard
+}
+
+func newExpirationShards(n int) *expirationShards {
+	s := &expirationShards{
+		shards: make([]*expirationShard, n),
+	}
+	for i := range s.shards {
+		s.shards[i] = &expirationShard{
+			newWork: make(chan struct{}, 1),
+		}
+	}
+	return s
+}
+
+// start starts draining the shards until quitCh is closed. Leases still
+// queued at that point are dropped, just like pending revocation jobs.
+func (s *expirationShards) start(m *ExpirationManager, quitCh chan struct{}) {
+	for _, shard := range s.shards {
+		go shard.run(m, quitCh)
+	}
+}
+
+// enqueue hands an expired lease to its shard.
+func (s *expirationShards) enqueue(ctx context.Context, leaseID string, ns *namespace.Namespace) {
+	h := fnv.New32a()
+	h.Write([]byte(leaseID))
+	shard := s.shards[h.Sum32()%uint32(len(s.shards))]
+
+	shard.l.Lock()
+	shard.queue = append(shard.queue, expiredLease{
+		ctx:       ctx,
+		leaseID:   leaseID,
+		ns:        ns,
+		expiredAt: time.Now(),
+	})
+	shard.l.Unlock()
+
+	select {
+	case shard.newWork <- struct{}{}:
+	default:
+	}
+}
+
+// pendingCount returns the number of expired leases not yet queued for
+// revocation.
+func (s *expirationShards) pendingCount() int {
+	count := 0
+	for _, shard := range s.shards {
+		shard.l.Lock()
+		count += len(shard.queue)
+		shard.l.Unlock()
+	}
+	return count
+}
+
+func (s *expirationShard) run(m *ExpirationManager, quitCh chan struct{}) {
+	for {
+		select {
+		case <-quitCh:
+			return
+		case <-s.newWork:
+		}
+
+		s.l.Lock()
+		leases := s.queue
+		s.queue = nil
+		s.l.Unlock()
+
+		m.queueRevocations(leases)
+	}
+}
+
+// queueRevocations creates revocation jobs for the expired leases and adds
+// them to the job manager in a batch per mount, keeping revocations fair
+// across mounts.
+func (m *ExpirationManager) queueRevocations(leases []expiredLease) {
+	for len(leases) > 0 {
+		batch := leases
+		if len(batch) > expirationBatchSize {
+			batch = batch[:expirationBatchSize]
+		}
+		leases = leases[len(batch):]
+
+		m.queueRevocationsBatch(batch)
+	}
+}
+
+func (m *ExpirationManager) queueRevocationsBatch(leases []expiredLease) {
+	jobsByMount := make(map[string][]fairshare.Job)
+
+	m.coreStateLock.RLock()
+	for _, lease := range leases {
+		nsCtx := namespace.ContextWithNamespace(lease.ctx, lease.ns)
+		job, err := newRevocationJob(nsCtx, lease.leaseID, lease.ns, m)
+		if err != nil {
+			m.logger.Warn("error creating revocation job", "error", err)
+			continue
+		}
+		// account for the time spent waiting in the shard
+		job.startTime = lease.expiredAt
+
+		mountAccessor := m.getLeaseMountAccessor(nsCtx, lease.leaseID)
+		jobsByMount[mountAccessor] = append(jobsByMount[mountAccessor], job)
+	}
+	m.coreStateLock.RUnlock()
+
+	for mountAccessor, jobs := range jobsByMount {
+		m.jobManager.AddJobs(jobs, mountAccessor)
+	}
+}
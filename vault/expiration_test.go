@@ -2983,6 +2983,56 @@ func TestExpiration_FairsharingEnvVar(t *testing.T) {
 	}
 }
 
+func TestExpiration_ShardedExpiration(t *testing.T) {
+	exp := mockExpiration(t)
+
+	// Swap in a job manager that isn't started, so revocation jobs stay queued
+	exp.jobManager.Stop()
+	exp.jobManager = fairshare.NewJobManager("expire-test", numExpirationWorkersTest, nil, nil)
+
+	ctx := namespace.RootContext(nil)
+	mounts := []string{"cubbyhole/", "sys/"}
+	numLeases := 3 * expirationBatchSize / 2
+
+	var wg sync.WaitGroup
+	for _, mount := range mounts {
+		for i := 0; i < numLeases; i++ {
+			wg.Add(1)
+			go func(leaseID string) {
+				defer wg.Done()
+				exp.expireFunc(exp.quitContext, exp, leaseID, namespace.RootNamespace)
+			}(fmt.Sprintf("%slease/%d", mount, i))
+		}
+	}
+	wg.Wait()
+
+	timeout := time.Now().Add(10 * time.Second)
+	for exp.jobManager.GetPendingJobCount() < len(mounts)*numLeases {
+		if time.Now().After(timeout) {
+			t.Fatalf("expected %d queued revocations, got %d", len(mounts)*numLeases, exp.jobManager.GetPendingJobCount())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if pending := exp.expirationShards.pendingCount(); pending != 0 {
+		t.Fatalf("expected no expirations left in the shards, got %d", pending)
+	}
+
+	queueLengths := exp.jobManager.GetWorkQueueLengths()
+	if len(queueLengths) != len(mounts) {
+		t.Fatalf("expected a revocation queue per mount, got %v", queueLengths)
+	}
+	for _, mount := range mounts {
+		entry := exp.router.MatchingMountEntry(ctx, mount)
+		if entry == nil {
+			t.Fatalf("no mount entry for %s", mount)
+		}
+		if queueLengths[entry.Accessor] != numLeases {
+			t.Fatalf("expected %d revocations queued for %s, got %d", numLeases, mount, queueLengths[entry.Accessor])
+		}
+	}
+}
+
 // register one lease ID and return the leaseID
 func registerOneLease(t *testing.T, ctx context.Context, exp *ExpirationManager) string {
 	t.Helper()
@@ -12,22 +12,24 @@ import (
 	"github.com/hashicorp/vault/sdk/logical"
 )
 
-func (m *ExpirationManager) leaseView(*namespace.Namespace) *BarrierView {
-	return m.idView
+func (m *ExpirationManager) leaseView(ns *namespace.Namespace) *BarrierView {
+	return m.core.namespacedView(ns, m.idView)
 }
 
-func (m *ExpirationManager) tokenIndexView(*namespace.Namespace) *BarrierView {
-	return m.tokenView
+func (m *ExpirationManager) tokenIndexView(ns *namespace.Namespace) *BarrierView {
+	return m.core.namespacedView(ns, m.tokenView)
 }
 
 func (m *ExpirationManager) collectLeases() (map[*namespace.Namespace][]string, int, error) {
 	leaseCount := 0
 	existing := make(map[*namespace.Namespace][]string)
-	keys, err := logical.CollectKeys(m.quitContext, m.leaseView(namespace.RootNamespace))
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to scan for leases: %w", err)
+	for _, ns := range m.core.collectNamespaces() {
+		keys, err := logical.CollectKeys(namespace.ContextWithNamespace(m.quitContext, ns), m.leaseView(ns))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan for leases: %w", err)
+		}
+		existing[ns] = keys
+		leaseCount += len(keys)
 	}
-	existing[namespace.RootNamespace] = keys
-	leaseCount += len(keys)
 	return existing, leaseCount, nil
 }
@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package identity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/hashicorp/vault/builtin/credential/userpass"
+	"github.com/hashicorp/vault/helper/testhelpers"
+	vaulthttp "github.com/hashicorp/vault/http"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault"
+)
+
+const webhookSigningKey = "webhook-signing-key"
+
+func webhookSignature(message string) string {
+	mac := hmac.New(sha256.New, []byte(webhookSigningKey))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// testApprover approves or denies approval requests by calling back Vault.
+func testApprover(t *testing.T, client *api.Client, approve *atomic.Bool) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("X-Vault-Signature") != webhookSignature(string(body)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var approvalRequest map[string]interface{}
+		if err := json.Unmarshal(body, &approvalRequest); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		approvalID := approvalRequest["approval_id"].(string)
+
+		go func() {
+			decision := "denied"
+			if approve.Load() {
+				decision = "approved"
+			}
+			_, err := client.Logical().Write("sys/mfa/webhook/callback", map[string]interface{}{
+				"approval_id": approvalID,
+				"approved":    approve.Load(),
+				"approver":    "alice",
+				"signature":   webhookSignature(approvalID + ":" + decision + ":alice"),
+			})
+			if err != nil {
+				t.Errorf("failed to call back: %v", err)
+			}
+		}()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+}
+
+func TestLoginMFA_Webhook(t *testing.T) {
+	cluster := vault.NewTestCluster(t, &vault.CoreConfig{
+		CredentialBackends: map[string]logical.Factory{
+			"userpass": userpass.Factory,
+		},
+	}, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	client := cluster.Cores[0].Client
+	mountAccessor := testhelpers.SetupUserpassMountAccessor(t, client)
+	userClient, entityID, _ := testhelpers.CreateEntityAndAlias(t, client, mountAccessor, "entity1", "testuser1")
+
+	callbackClient, err := client.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	callbackClient.ClearToken()
+
+	var approve atomic.Bool
+	approver := testApprover(t, callbackClient, &approve)
+	defer approver.Close()
+
+	resp, err := client.Logical().Write("identity/mfa/method/webhook", map[string]interface{}{
+		"url":         approver.URL,
+		"ca_cert":     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: approver.Certificate().Raw})),
+		"signing_key": webhookSigningKey,
+		"timeout":     "10s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	methodID := resp.Data["method_id"].(string)
+
+	resp, err = client.Logical().Read("identity/mfa/method/webhook/" + methodID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["url"] != approver.URL || resp.Data["type"] != "webhook" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+	if _, ok := resp.Data["signing_key"]; ok {
+		t.Fatal("signing_key was returned")
+	}
+
+	testhelpers.SetupMFALoginEnforcement(t, client, map[string]interface{}{
+		"name":              "webhook",
+		"auth_method_types": []string{"userpass"},
+		"mfa_method_ids":    []string{methodID},
+	})
+
+	login := func() (*api.Secret, error) {
+		secret, err := userClient.Logical().Write("auth/userpass/login/testuser1", map[string]interface{}{
+			"password": "testpassword",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if secret.Auth == nil || secret.Auth.MFARequirement == nil {
+			t.Fatalf("two phase login returned nil MFARequirement")
+		}
+		for _, method := range secret.Auth.MFARequirement.MFAConstraints["webhook"].Any {
+			if method.Type != "webhook" || method.UsesPasscode {
+				t.Fatalf("bad MFA constraint: %#v", method)
+			}
+		}
+
+		return userClient.Logical().Write("sys/mfa/validate", map[string]interface{}{
+			"mfa_request_id": secret.Auth.MFARequirement.MFARequestID,
+			"mfa_payload": map[string][]string{
+				methodID: {},
+			},
+		})
+	}
+
+	// Denied logins fail
+	_, err = login()
+	if err == nil || !strings.Contains(err.Error(), `denied by "alice"`) {
+		t.Fatalf("expected the login to be denied, got: %v", err)
+	}
+
+	// Approved logins record the approver in the token metadata
+	approve.Store(true)
+	secret, err := login()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Auth == nil || secret.Auth.ClientToken == "" {
+		t.Fatal("successful mfa validation did not return a client token")
+	}
+	if secret.Auth.Metadata["mfa_approver"] != "alice" {
+		t.Fatalf("approver not recorded in the metadata: %#v", secret.Auth.Metadata)
+	}
+
+	lookup, err := client.Auth().Token().Lookup(secret.Auth.ClientToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lookup.Data["entity_id"] != entityID {
+		t.Fatalf("bad entity ID: %v", lookup.Data["entity_id"])
+	}
+
+	// Callbacks for unknown approvals or with an invalid signature are rejected
+	_, err = callbackClient.Logical().Write("sys/mfa/webhook/callback", map[string]interface{}{
+		"approval_id": "00000000-0000-0000-0000-000000000000",
+		"approved":    true,
+		"approver":    "mallory",
+		"signature":   "invalid",
+	})
+	if err == nil {
+		t.Fatal("expected the callback to be rejected")
+	}
+}
@@ -5,6 +5,8 @@ package quotas
 
 import (
 	"fmt"
+	"net/http"
+	"strconv"
 	"testing"
 	"time"
 
@@ -542,3 +544,95 @@ func TestQuotas_RateLimitQuota(t *testing.T) {
 		t.Fatalf("unexpected number of failed requests: %d", numFail)
 	}
 }
+
+func TestQuotas_RateLimitQuota_GroupByEntity(t *testing.T) {
+	conf, opts := teststorage.ClusterSetup(coreConfig, nil, nil)
+	opts.NoDefaultQuotas = true
+	cluster := vault.NewTestCluster(t, conf, opts)
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	core := cluster.Cores[0].Core
+	client := cluster.Cores[0].Client
+	vault.TestWaitActive(t, core)
+
+	err := client.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{
+		Type: "userpass",
+	})
+	require.NoError(t, err)
+
+	// Each entity may look itself up once per hour; other methods on the same
+	// path aren't limited.
+	_, err = client.Logical().Write("sys/quotas/rate-limit/lookup-self", map[string]interface{}{
+		"path":     "auth/token/lookup-self",
+		"rate":     1,
+		"interval": "1h",
+		"group_by": "entity_then_ip",
+		"methods":  "GET",
+	})
+	require.NoError(t, err)
+
+	resp, err := client.Logical().Read("sys/quotas/rate-limit/lookup-self")
+	require.NoError(t, err)
+	require.Equal(t, "entity_then_ip", resp.Data["group_by"])
+	require.Equal(t, []interface{}{"GET"}, resp.Data["methods"])
+
+	// A quota on the same path is rejected if its methods overlap.
+	_, err = client.Logical().Write("sys/quotas/rate-limit/lookup-self-all", map[string]interface{}{
+		"path": "auth/token/lookup-self",
+		"rate": 1,
+	})
+	require.Error(t, err)
+	_, err = client.Logical().Write("sys/quotas/rate-limit/lookup-self-post", map[string]interface{}{
+		"path":    "auth/token/lookup-self",
+		"rate":    1,
+		"methods": "POST",
+	})
+	require.NoError(t, err)
+	_, err = client.Logical().Delete("sys/quotas/rate-limit/lookup-self-post")
+	require.NoError(t, err)
+
+	login := func(user string) *api.Client {
+		t.Helper()
+
+		_, err := client.Logical().Write("auth/userpass/users/"+user, map[string]interface{}{
+			"password": "bar",
+		})
+		require.NoError(t, err)
+
+		userClient, err := client.Clone()
+		require.NoError(t, err)
+		userClient.SetMaxRetries(0)
+
+		secret, err := userClient.Logical().Write("auth/userpass/login/"+user, map[string]interface{}{
+			"password": "bar",
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, secret.Auth.EntityID)
+		userClient.SetToken(secret.Auth.ClientToken)
+		return userClient
+	}
+	foo, baz := login("foo"), login("baz")
+
+	_, err = foo.Logical().Read("auth/token/lookup-self")
+	require.NoError(t, err)
+
+	// The entity is rate limited, whatever token or address it uses, and told
+	// when to retry even though rate limit headers are disabled.
+	rawResp, err := foo.Logical().ReadRaw("auth/token/lookup-self")
+	require.Error(t, err)
+	require.Equal(t, http.StatusTooManyRequests, rawResp.StatusCode)
+	retryAfter, err := strconv.Atoi(rawResp.Header.Get("Retry-After"))
+	require.NoError(t, err)
+	require.Greater(t, retryAfter, 3500)
+
+	// Writes aren't rate limited: the default policy denies them instead.
+	_, err = foo.Logical().Write("auth/token/lookup-self", nil)
+	var respErr *api.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusForbidden, respErr.StatusCode)
+
+	// Other entities aren't affected.
+	_, err = baz.Logical().Read("auth/token/lookup-self")
+	require.NoError(t, err)
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -510,6 +511,148 @@ func TestRaft_SnapshotAPI(t *testing.T) {
 	}
 }
 
+func TestRaft_SnapshotAuto(t *testing.T) {
+	t.Parallel()
+	cluster, _ := raftCluster(t, nil)
+	defer cluster.Cleanup()
+
+	leaderClient := cluster.Cores[0].Client
+	dir := t.TempDir()
+
+	// Invalid configurations are rejected
+	_, err := leaderClient.Logical().Write("sys/storage/raft/snapshot-auto/config/local", map[string]interface{}{
+		"interval":     "1s",
+		"path_prefix":  dir,
+		"storage_type": "local",
+	})
+	if err == nil || !strings.Contains(err.Error(), "local_max_space") {
+		t.Fatalf("expected local_max_space to be required, got %v", err)
+	}
+	_, err = leaderClient.Logical().Write("sys/storage/raft/snapshot-auto/config/local", map[string]interface{}{
+		"interval":        "1s",
+		"path_prefix":     dir,
+		"storage_type":    "local",
+		"local_max_space": 1 << 30,
+		"aws_s3_bucket":   "bucket",
+	})
+	if err == nil || !strings.Contains(err.Error(), "aws_s3_bucket") {
+		t.Fatalf("expected aws_s3_bucket to be rejected, got %v", err)
+	}
+
+	_, err = leaderClient.Logical().Write("sys/storage/raft/snapshot-auto/config/local", map[string]interface{}{
+		"interval":        "1s",
+		"retain":          2,
+		"path_prefix":     dir,
+		"storage_type":    "local",
+		"local_max_space": 1 << 30,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := leaderClient.Logical().Read("sys/storage/raft/snapshot-auto/config/local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, "vault-snapshot", secret.Data["file_prefix"])
+	require.Equal(t, "1", fmt.Sprint(secret.Data["interval"]))
+
+	secret, err = leaderClient.Logical().List("sys/storage/raft/snapshot-auto/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	require.Equal(t, []interface{}{"local"}, secret.Data["keys"])
+
+	// Wait for more snapshots than are retained
+	var urls []string
+	corehelpers.RetryUntil(t, 15*time.Second, func() error {
+		secret, err := leaderClient.Logical().Read("sys/storage/raft/snapshot-auto/status/local")
+		if err != nil {
+			return err
+		}
+		if msg := secret.Data["last_snapshot_error"]; msg != "" {
+			t.Fatalf("snapshot failed: %v", msg)
+		}
+		url, _ := secret.Data["snapshot_url"].(string)
+		if url != "" && (len(urls) == 0 || urls[len(urls)-1] != url) {
+			urls = append(urls, url)
+		}
+		if len(urls) < 3 {
+			return fmt.Errorf("took %d snapshots", len(urls))
+		}
+		return nil
+	})
+
+	_, err = leaderClient.Logical().Delete("sys/storage/raft/snapshot-auto/config/local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err = leaderClient.Logical().Read("sys/storage/raft/snapshot-auto/status/local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret != nil {
+		t.Fatalf("expected no status after deleting the configuration, got %v", secret.Data)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 retained snapshots, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "vault-snapshot-") {
+			t.Fatalf("unexpected file %q", entry.Name())
+		}
+	}
+}
+
+func TestRaft_Compact(t *testing.T) {
+	t.Parallel()
+	cluster, _ := raftCluster(t, nil)
+	defer cluster.Cleanup()
+
+	leaderClient := cluster.Cores[0].Client
+	for i := 0; i < 100; i++ {
+		_, err := leaderClient.Logical().Write(fmt.Sprintf("secret/test/%d", i), map[string]interface{}{
+			"test": strings.Repeat("a", 4096),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i < 100; i++ {
+		if _, err := leaderClient.Logical().Delete(fmt.Sprintf("secret/test/%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Wait for the standbys to report their state
+	time.Sleep(5 * time.Second)
+
+	secret, err := leaderClient.Logical().Write("sys/storage/raft/compact", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Data["leadership_transferred"] != true {
+		t.Fatalf("expected leadership to be transferred: %v", secret.Data)
+	}
+	expected := []interface{}{cluster.Cores[1].NodeID, cluster.Cores[2].NodeID, cluster.Cores[0].NodeID}
+	require.ElementsMatch(t, expected, secret.Data["nodes"])
+	require.Equal(t, cluster.Cores[0].NodeID, secret.Data["nodes"].([]interface{})[2])
+
+	active := testhelpers.WaitForActiveNode(t, cluster)
+	secret, err = active.Client.Logical().Read("secret/test/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret == nil || secret.Data["test"] != strings.Repeat("a", 4096) {
+		t.Fatalf("bad secret: %v", secret)
+	}
+}
+
 func TestRaft_SnapshotAPI_MidstreamFailure(t *testing.T) {
 	// defer goleak.VerifyNone(t)
 	t.Parallel()
@@ -0,0 +1,428 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault/seal"
+)
+
+const (
+	// HealthStatusOK is reported by the subsystems working as expected.
+	HealthStatusOK = "ok"
+
+	// HealthStatusDegraded is reported by the subsystems which are working,
+	// but need attention, e.g. because of a growing backlog.
+	HealthStatusDegraded = "degraded"
+
+	// HealthStatusFailed is reported by the subsystems which are not
+	// working.
+	HealthStatusFailed = "failed"
+
+	// HealthStatusSkipped is reported by the subsystems which can't be
+	// checked in the current state of the node, e.g. on a sealed node.
+	HealthStatusSkipped = "skipped"
+)
+
+const (
+	// storageHealthCheckKey is read from the storage to check it is
+	// reachable. It doesn't need to exist.
+	storageHealthCheckKey = "core/health-check"
+
+	// storageHealthLatencyThreshold is the latency of the storage health
+	// check above which the storage is reported as degraded.
+	storageHealthLatencyThreshold = 1 * time.Second
+
+	// mountsHealthCheckTimeout is how long the health checks of the mounts
+	// can take altogether. The mounts whose check is still running by then
+	// are reported as failed.
+	mountsHealthCheckTimeout = 10 * time.Second
+)
+
+// SubsystemHealth is the health status of a subsystem of the server. It is
+// served to unauthenticated clients, so errors are summarized rather than
+// passed on as is, the underlying errors being logged instead.
+type SubsystemHealth struct {
+	Status  string                 `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// DetailedHealth is the health status of the server and its subsystems. The
+// status of the server is the worst status of its subsystems.
+type DetailedHealth struct {
+	Status     string                      `json:"status"`
+	Subsystems map[string]*SubsystemHealth `json:"subsystems"`
+}
+
+// MountHealth is the result of the health check of the backend of a mount.
+// It is only served to authenticated clients, so errors are passed on as is.
+type MountHealth struct {
+	Path     string `json:"path"`
+	Accessor string `json:"accessor"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// DetailedHealth checks the health of the storage, the seal, the expiration
+// manager, the replication and HA state and the audit devices of the node.
+func (c *Core) DetailedHealth(ctx context.Context) *DetailedHealth {
+	health := &DetailedHealth{
+		Subsystems: map[string]*SubsystemHealth{
+			"storage":     c.storageHealth(ctx),
+			"seal":        c.sealHealth(ctx),
+			"expiration":  c.expirationHealth(),
+			"replication": c.replicationHealth(),
+			"audit":       c.auditHealth(),
+		},
+	}
+
+	statuses := make([]string, 0, len(health.Subsystems))
+	for _, subsystem := range health.Subsystems {
+		statuses = append(statuses, subsystem.Status)
+	}
+
+	health.Status = worstHealthStatus(statuses)
+
+	return health
+}
+
+// worstHealthStatus returns the worst of the given statuses, skipped
+// statuses being ignored.
+func worstHealthStatus(statuses []string) string {
+	worst := HealthStatusOK
+	for _, status := range statuses {
+		switch status {
+		case HealthStatusFailed:
+			return HealthStatusFailed
+		case HealthStatusDegraded:
+			worst = HealthStatusDegraded
+		}
+	}
+
+	return worst
+}
+
+func (c *Core) storageHealth(ctx context.Context) *SubsystemHealth {
+	health := &SubsystemHealth{
+		Status: HealthStatusOK,
+		Details: map[string]interface{}{
+			"type": c.StorageType(),
+		},
+	}
+
+	start := time.Now()
+	_, err := c.underlyingPhysical.Get(ctx, storageHealthCheckKey)
+	latency := time.Since(start)
+
+	health.Details["latency_ms"] = latency.Milliseconds()
+	switch {
+	case err != nil:
+		c.logger.Error("storage health check failed", "error", err)
+		health.Status = HealthStatusFailed
+		health.Error = "storage is unreachable"
+	case latency > storageHealthLatencyThreshold:
+		health.Status = HealthStatusDegraded
+		health.Error = fmt.Sprintf("storage latency exceeds %s", storageHealthLatencyThreshold)
+	}
+
+	return health
+}
+
+func (c *Core) sealHealth(ctx context.Context) *SubsystemHealth {
+	sealed := c.Sealed()
+	health := &SubsystemHealth{
+		Status: HealthStatusOK,
+		Details: map[string]interface{}{
+			"sealed": sealed,
+			"type":   c.seal.BarrierType().String(),
+		},
+	}
+
+	init, err := c.Initialized(ctx)
+	if err != nil {
+		c.logger.Error("seal health check failed to check the initialization status", "error", err)
+		health.Status = HealthStatusFailed
+		health.Error = "failed to check the initialization status"
+		return health
+	}
+	health.Details["initialized"] = init
+
+	switch {
+	case !init:
+		health.Status = HealthStatusFailed
+		health.Error = "vault is not initialized"
+	case sealed:
+		health.Status = HealthStatusFailed
+		health.Error = "vault is sealed"
+	}
+
+	if as, ok := c.seal.(*autoSeal); ok {
+		healthy := as.Healthy()
+		health.Details["healthy"] = healthy
+		if !healthy && health.Status == HealthStatusOK {
+			health.Status = HealthStatusDegraded
+			health.Error = "the last health test of the seal failed"
+		}
+
+		// Each seal of a multi-seal is reported on its own, the multi-seal
+		// is degraded as long as one of them is unavailable
+		if multi, ok := as.Access.(*seal.MultiAccess); ok {
+			statuses := multi.Status(ctx)
+			for i := range statuses {
+				statuses[i].LastError = ""
+			}
+			health.Details["seals"] = statuses
+			for _, status := range statuses {
+				if !status.Healthy && health.Status == HealthStatusOK {
+					health.Status = HealthStatusDegraded
+					health.Error = fmt.Sprintf("seal %q is unavailable", status.Name)
+				}
+			}
+		}
+	}
+
+	return health
+}
+
+func (c *Core) expirationHealth() *SubsystemHealth {
+	if c.Sealed() {
+		return &SubsystemHealth{Status: HealthStatusSkipped}
+	}
+
+	c.stateLock.RLock()
+	m := c.expiration
+	c.stateLock.RUnlock()
+
+	// The expiration manager only runs on the active node
+	if m == nil {
+		return &SubsystemHealth{Status: HealthStatusSkipped}
+	}
+
+	m.pendingLock.RLock()
+	leases := m.leaseCount
+	irrevocableLeases := m.irrevocableLeaseCount
+	m.pendingLock.RUnlock()
+
+	var revocationQueueDepth int
+	for _, length := range m.jobManager.GetWorkQueueLengths() {
+		revocationQueueDepth += length
+	}
+
+	health := &SubsystemHealth{
+		Status: HealthStatusOK,
+		Details: map[string]interface{}{
+			"num_leases":             leases,
+			"num_irrevocable_leases": irrevocableLeases,
+			"pending_expirations":    m.expirationShards.pendingCount(),
+			"revocation_queue_depth": revocationQueueDepth,
+			"restoring":              m.inRestoreMode(),
+		},
+	}
+
+	switch {
+	case leases > maxLeaseThreshold:
+		health.Status = HealthStatusDegraded
+		health.Error = fmt.Sprintf("lease count exceeds the warning threshold of %d", maxLeaseThreshold)
+	case irrevocableLeases > 0:
+		health.Status = HealthStatusDegraded
+		health.Error = "some leases could not be revoked"
+	}
+
+	return health
+}
+
+func (c *Core) replicationHealth() *SubsystemHealth {
+	standby, perfStandby := c.StandbyStates()
+
+	replicationState := c.ReplicationState()
+	if standby {
+		replicationState = c.ActiveNodeReplicationState()
+	}
+
+	health := &SubsystemHealth{
+		Status: HealthStatusOK,
+		Details: map[string]interface{}{
+			"ha_enabled":                   c.HAEnabled(),
+			"standby":                      standby,
+			"performance_standby":          perfStandby,
+			"replication_performance_mode": replicationState.GetPerformanceString(),
+			"replication_dr_mode":          replicationState.GetDRString(),
+		},
+	}
+
+	if !c.HAEnabled() {
+		return health
+	}
+	if c.Sealed() {
+		health.Status = HealthStatusSkipped
+		return health
+	}
+
+	isLeader, leaderAddr, _, err := c.Leader()
+	switch {
+	case err != nil:
+		c.logger.Error("replication health check failed to look up the active node", "error", err)
+		health.Status = HealthStatusFailed
+		health.Error = "failed to look up the active node"
+	case !isLeader && leaderAddr == "":
+		health.Status = HealthStatusDegraded
+		health.Error = "no active node"
+	default:
+		health.Details["leader_address"] = leaderAddr
+	}
+
+	return health
+}
+
+func (c *Core) auditHealth() *SubsystemHealth {
+	if c.Sealed() {
+		return &SubsystemHealth{Status: HealthStatusSkipped}
+	}
+
+	c.auditLock.RLock()
+	var entries []*MountEntry
+	if c.audit != nil {
+		entries = c.audit.Entries
+	}
+	c.auditLock.RUnlock()
+
+	var unregistered []string
+	for _, entry := range entries {
+		if c.auditBroker == nil || !c.auditBroker.IsRegistered(entry.Path) {
+			unregistered = append(unregistered, entry.Path)
+		}
+	}
+
+	health := &SubsystemHealth{
+		Status: HealthStatusOK,
+		Details: map[string]interface{}{
+			"enabled_devices": len(entries),
+		},
+	}
+
+	// The devices themselves are not disclosed
+	if len(unregistered) > 0 {
+		health.Status = HealthStatusFailed
+		health.Error = fmt.Sprintf("%d audit devices are not active", len(unregistered))
+	}
+
+	return health
+}
+
+// MountsHealth runs the health checks of the backends implementing
+// logical.HealthChecker concurrently, and returns their results sorted by
+// mount accessor. The backends replying with logical.ErrUnsupportedOperation
+// have no health check and are left out.
+func (c *Core) MountsHealth(ctx context.Context) []*MountHealth {
+	var entries []*MountEntry
+
+	c.mountsLock.RLock()
+	if c.mounts != nil {
+		entries = append(entries, c.mounts.Entries...)
+	}
+	c.mountsLock.RUnlock()
+
+	c.authLock.RLock()
+	if c.auth != nil {
+		entries = append(entries, c.auth.Entries...)
+	}
+	c.authLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, mountsHealthCheckTimeout)
+	defer cancel()
+
+	type checkResult struct {
+		index int
+		err   error
+	}
+
+	var mounts []*MountHealth
+	// The channel is buffered so that the checks still running after the
+	// deadline don't block once they finish.
+	resultCh := make(chan checkResult, len(entries))
+	for _, entry := range entries {
+		nsCtx := namespace.ContextWithNamespace(ctx, entry.Namespace())
+		backend := c.router.MatchingBackend(nsCtx, entry.APIPathNoNamespace())
+		checker, ok := backend.(logical.HealthChecker)
+		if !ok {
+			continue
+		}
+
+		req := &logical.HealthCheckRequest{
+			Storage: c.router.MatchingStorageByAPIPath(nsCtx, entry.APIPathNoNamespace()),
+		}
+
+		index := len(mounts)
+		mounts = append(mounts, &MountHealth{
+			Path:     entry.APIPath(),
+			Accessor: entry.Accessor,
+			Type:     entry.Type,
+			Status:   HealthStatusFailed,
+			Error:    fmt.Sprintf("health check timed out after %s", mountsHealthCheckTimeout),
+		})
+
+		go func() {
+			resultCh <- checkResult{
+				index: index,
+				err:   checkMountHealth(nsCtx, checker, req),
+			}
+		}()
+	}
+
+	unsupported := make(map[int]bool)
+LOOP:
+	for pending := len(mounts); pending > 0; pending-- {
+		var result checkResult
+		select {
+		case result = <-resultCh:
+		case <-ctx.Done():
+			break LOOP
+		}
+
+		mount := mounts[result.index]
+		switch {
+		case errors.Is(result.err, logical.ErrUnsupportedOperation):
+			unsupported[result.index] = true
+		case errors.Is(result.err, context.DeadlineExceeded):
+			// Keep the timeout error
+		case result.err != nil:
+			mount.Error = result.err.Error()
+		default:
+			mount.Status = HealthStatusOK
+			mount.Error = ""
+		}
+	}
+
+	checked := make([]*MountHealth, 0, len(mounts))
+	for i, mount := range mounts {
+		if !unsupported[i] {
+			checked = append(checked, mount)
+		}
+	}
+
+	sort.Slice(checked, func(i, j int) bool {
+		return checked[i].Accessor < checked[j].Accessor
+	})
+
+	return checked
+}
+
+func checkMountHealth(ctx context.Context, checker logical.HealthChecker, req *logical.HealthCheckRequest) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic during health check: %v", r)
+		}
+	}()
+
+	return checker.HealthCheck(ctx, req)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/helper/testhelpers/corehelpers"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_DetailedHealth(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	health := c.DetailedHealth(ctx)
+	require.Equal(t, HealthStatusOK, health.Status)
+	for _, name := range []string{"storage", "seal", "expiration", "replication", "audit"} {
+		require.Contains(t, health.Subsystems, name)
+		require.Equal(t, HealthStatusOK, health.Subsystems[name].Status, name)
+	}
+	require.Equal(t, false, health.Subsystems["seal"].Details["sealed"])
+
+	// Sealed nodes are failed
+	require.NoError(t, c.Seal(root))
+	health = c.DetailedHealth(ctx)
+	require.Equal(t, HealthStatusFailed, health.Status)
+	require.Equal(t, HealthStatusFailed, health.Subsystems["seal"].Status)
+	require.Equal(t, HealthStatusSkipped, health.Subsystems["expiration"].Status)
+	require.Equal(t, HealthStatusOK, health.Subsystems["storage"].Status)
+}
+
+func TestCore_DetailedHealth_Audit(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	c.auditBackends["noop"] = corehelpers.NoopAuditFactory(nil)
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/audit/noop")
+	req.Data["type"] = "noop"
+	req.ClientToken = root
+	_, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	health := c.DetailedHealth(ctx)
+	require.Equal(t, HealthStatusOK, health.Subsystems["audit"].Status)
+	require.Equal(t, 1, health.Subsystems["audit"].Details["enabled_devices"])
+
+	// Devices which failed to be set up are reported
+	c.auditBroker.Deregister("noop/")
+	health = c.DetailedHealth(ctx)
+	require.Equal(t, HealthStatusFailed, health.Status)
+	require.Equal(t, HealthStatusFailed, health.Subsystems["audit"].Status)
+}
+
+type healthCheckingBackend struct {
+	*NoopBackend
+	err error
+}
+
+func (b *healthCheckingBackend) HealthCheck(_ context.Context, req *logical.HealthCheckRequest) error {
+	if req.Storage == nil {
+		return errors.New("missing storage")
+	}
+	return b.err
+}
+
+func TestSystemBackend_HealthMounts(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	healthy := &healthCheckingBackend{NoopBackend: &NoopBackend{}}
+	unhealthy := &healthCheckingBackend{NoopBackend: &NoopBackend{}, err: errors.New("upstream unreachable")}
+	c.logicalBackends["healthy"] = func(context.Context, *logical.BackendConfig) (logical.Backend, error) {
+		return healthy, nil
+	}
+	c.logicalBackends["unhealthy"] = func(context.Context, *logical.BackendConfig) (logical.Backend, error) {
+		return unhealthy, nil
+	}
+
+	readHealth := func() *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, logical.ReadOperation, "sys/health/mounts")
+		req.ClientToken = root
+		resp, err := c.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		return resp
+	}
+
+	// The builtin backends have no health check
+	resp := readHealth()
+	require.Equal(t, HealthStatusOK, resp.Data["status"])
+	require.Empty(t, resp.Data["mounts"])
+
+	for _, typ := range []string{"healthy", "unhealthy"} {
+		req := logical.TestRequest(t, logical.UpdateOperation, "sys/mounts/"+typ)
+		req.Data["type"] = typ
+		req.ClientToken = root
+		_, err := c.HandleRequest(ctx, req)
+		require.NoError(t, err)
+	}
+
+	resp = readHealth()
+	require.Equal(t, HealthStatusDegraded, resp.Data["status"])
+
+	mounts := resp.Data["mounts"].([]*MountHealth)
+	require.Len(t, mounts, 2)
+	byPath := make(map[string]*MountHealth)
+	for _, mount := range mounts {
+		byPath[mount.Path] = mount
+	}
+	require.Equal(t, &MountHealth{
+		Path:     "healthy/",
+		Accessor: c.router.MatchingMountEntry(ctx, "healthy/").Accessor,
+		Type:     "healthy",
+		Status:   HealthStatusOK,
+	}, byPath["healthy/"])
+	require.Equal(t, &MountHealth{
+		Path:     "unhealthy/",
+		Accessor: c.router.MatchingMountEntry(ctx, "unhealthy/").Accessor,
+		Type:     "unhealthy",
+		Status:   HealthStatusFailed,
+		Error:    "upstream unreachable",
+	}, byPath["unhealthy/"])
+	require.Less(t, mounts[0].Accessor, mounts[1].Accessor)
+}
@@ -65,6 +65,8 @@ func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendCo
 		tokenStorer:   core,
 		entityCreator: core,
 		mfaBackend:    core.loginMFABackend,
+
+		groupResolverFactories: core.groupResolvers,
 	}
 
 	// Create a memdb instance, which by default, operates on lower cased
@@ -106,6 +108,7 @@ func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendCo
 				"oidc/.well-known/*",
 				"oidc/provider/+/.well-known/*",
 				"oidc/provider/+/token",
+				"oidc/provider/+/device",
 			},
 			LocalStorage: []string{
 				localAliasesBucketsPrefix,
@@ -121,6 +124,7 @@ func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendCo
 
 	iStore.oidcCache = newOIDCCache(cache.NoExpiration, cache.NoExpiration)
 	iStore.oidcAuthCodeCache = newOIDCCache(5*time.Minute, 5*time.Minute)
+	iStore.oidcDeviceCodeCache = newOIDCCache(deviceCodeTTL+5*time.Minute, 5*time.Minute)
 
 	err = iStore.Setup(ctx, config)
 	if err != nil {
@@ -141,6 +145,8 @@ func (i *IdentityStore) paths() []*framework.Path {
 		oidcPaths(i),
 		oidcProviderPaths(i),
 		mfaPaths(i),
+		scimPaths(i),
+		groupResolverPaths(i),
 	)
 }
 
@@ -571,6 +577,82 @@ func mfaPaths(i *IdentityStore) []*framework.Path {
 				},
 			},
 		},
+		{
+			Pattern: "mfa/method/webhook" + genericOptionalUUIDRegex("method_id"),
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "mfa",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"method_name": {
+					Type:        framework.TypeString,
+					Description: `The unique name identifier for this MFA method.`,
+				},
+				"method_id": {
+					Type:        framework.TypeString,
+					Description: `The unique identifier for this MFA method.`,
+				},
+				"username_format": {
+					Type:        framework.TypeString,
+					Description: `A template string for mapping Identity names to MFA method names. Values to subtitute should be placed in {{}}. For example, "{{alias.name}}@example.com". Currently-supported mappings: alias.name: The name returned by the mount configured via the mount_accessor parameter If blank, the Alias's name field will be used as-is. `,
+				},
+				"url": {
+					Type:        framework.TypeString,
+					Description: "URL of the approver service that approval requests are sent to.",
+				},
+				"ca_cert": {
+					Type:        framework.TypeString,
+					Description: "PEM encoded CA certificate used to verify the TLS certificate of the approver service. Defaults to the system roots.",
+				},
+				"signing_key": {
+					Type:        framework.TypeString,
+					Description: "Key shared with the approver service, used to sign approval requests and to verify the signature of its callbacks.",
+				},
+				"timeout": {
+					Type:        framework.TypeDurationSecond,
+					Description: "How long a login waits for the approver service to call back. Defaults to 60 seconds.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.handleMFAMethodWebhookRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "read",
+						OperationSuffix: "webhook-method-configuration|webhook-method-configuration",
+					},
+					Summary: "Read the current configuration for the given MFA method",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.handleMFAMethodWebhookUpdate,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "configure",
+						OperationSuffix: "webhook-method|webhook-method",
+					},
+					Summary: "Update or create a configuration for the given MFA method",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: i.handleMFAMethodWebhookDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "delete",
+						OperationSuffix: "webhook-method|webhook-method",
+					},
+					Summary: "Delete a configuration for the given MFA method",
+				},
+			},
+		},
+		{
+			Pattern: "mfa/method/webhook/?$",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: i.handleMFAMethodListWebhook,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationPrefix: "mfa",
+						OperationVerb:   "list",
+						OperationSuffix: "webhook-methods",
+					},
+					Summary: "List MFA method configurations for the given MFA method",
+				},
+			},
+		},
 		{
 			Pattern: "mfa/login-enforcement/" + framework.GenericNameRegex("name"),
 			DisplayAttrs: &framework.DisplayAttributes{
@@ -903,6 +985,11 @@ func (i *IdentityStore) Invalidate(ctx context.Context, key string) {
 		if err := i.oidcCache.Flush(ns); err != nil {
 			i.logger.Error("error flushing oidc cache", "error", err)
 		}
+	case strings.HasPrefix(key, groupResolverPrefix):
+		// Group resolvers are reloaded on next use
+		i.groupResolversLock.Lock()
+		i.groupResolvers = nil
+		i.groupResolversLock.Unlock()
 	case strings.HasPrefix(key, clientPath):
 		name := strings.TrimPrefix(key, clientPath)
 
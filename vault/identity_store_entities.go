@@ -143,6 +143,39 @@ func entityPaths(i *IdentityStore) []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(entityHelp["entity-id"][0]),
 			HelpDescription: strings.TrimSpace(entityHelp["entity-id"][1]),
 		},
+		{
+			Pattern: "entity/id/" + framework.GenericNameRegex("id") + "/revoke-sessions$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "entity",
+				OperationVerb:   "revoke-sessions",
+				OperationSuffix: "by-id",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"id": {
+					Type:        framework.TypeString,
+					Description: "ID of the entity.",
+				},
+				"oidc_sessions": {
+					Type:        framework.TypeBool,
+					Description: "Whether to also drop the authorization and device codes the OIDC providers issued to the entity and that weren't exchanged yet.",
+				},
+				"dry_run": {
+					Type:        framework.TypeBool,
+					Description: "Whether to only report what would be revoked.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathEntityIDRevokeSessions(),
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(entityHelp["entity-revoke-sessions"][0]),
+			HelpDescription: strings.TrimSpace(entityHelp["entity-revoke-sessions"][1]),
+		},
 		{
 			Pattern: "entity/batch-delete",
 
@@ -556,6 +589,50 @@ func (i *IdentityStore) pathEntityIDDelete() framework.OperationFunc {
 	}
 }
 
+// pathEntityIDRevokeSessions revokes the tokens of the entity along with
+// their leases
+func (i *IdentityStore) pathEntityIDRevokeSessions() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		entityID := d.Get("id").(string)
+		if entityID == "" {
+			return logical.ErrorResponse("missing entity id"), nil
+		}
+
+		ns, err := namespace.FromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		entity, err := i.MemDBEntityByID(entityID, false)
+		if err != nil {
+			return nil, err
+		}
+		if entity == nil || entity.NamespaceID != ns.ID {
+			return logical.ErrorResponse("entity not found"), logical.ErrInvalidRequest
+		}
+
+		dryRun := d.Get("dry_run").(bool)
+		tokens, err := i.tokenStorer.RevokeEntityTokens(ctx, entity.ID, dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		respData := map[string]interface{}{
+			"dry_run":         dryRun,
+			"token_accessors": tokens.Accessors,
+			"leases":          tokens.Leases,
+			"batch_tokens":    tokens.BatchTokens,
+		}
+		if d.Get("oidc_sessions").(bool) {
+			respData["oidc_sessions"] = i.revokeOIDCSessions(entity.ID, dryRun)
+		}
+
+		return &logical.Response{
+			Data: respData,
+		}, nil
+	}
+}
+
 // pathEntityNameDelete deletes the entity for a given entity ID
 func (i *IdentityStore) pathEntityNameDelete() framework.OperationFunc {
 	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
@@ -1139,6 +1216,15 @@ var entityHelp = map[string][2]string{
 		"Update, read or delete an entity using entity ID",
 		"",
 	},
+	"entity-revoke-sessions": {
+		"Revoke the tokens and leases of an entity",
+		`Revokes every token issued to the entity, along with the leases created
+with them. Batch tokens are revoked by issue time, so batch tokens issued to
+the entity after the revocation are valid. If oidc_sessions is set, the
+authorization and device codes the OIDC providers issued to the entity are
+dropped too. With dry_run, nothing is revoked and the response reports what
+would be.`,
+	},
 	"entity-name": {
 		"Update, read or delete an entity using entity name",
 		"",
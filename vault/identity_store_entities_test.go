@@ -1311,3 +1311,126 @@ func TestIdentityStore_MergeEntitiesByID_DuplicateFromEntityIDs(t *testing.T) {
 		t.Fatalf("invalid number of entity policies; expected: 2, actualL: %d", len(entity1Lookup.Policies))
 	}
 }
+
+func TestIdentityStore_EntityRevokeSessions(t *testing.T) {
+	core, _, root := TestCoreUnsealed(t)
+	is := core.identityStore
+	ctx := namespace.RootContext(nil)
+
+	mustHandle := func(t *testing.T, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := core.HandleRequest(ctx, &logical.Request{
+			Operation:   logical.UpdateOperation,
+			Path:        path,
+			Data:        data,
+			ClientToken: root,
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v, resp: %#v", err, resp)
+		}
+		return resp
+	}
+	mustLookup := func(t *testing.T, token string, valid bool) {
+		t.Helper()
+		te, err := core.tokenStore.Lookup(ctx, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (te != nil) != valid {
+			t.Fatalf("expected token to be valid: %t, got: %#v", valid, te)
+		}
+	}
+
+	mustHandle(t, "auth/token/roles/test", map[string]interface{}{
+		"orphan":                 true,
+		"allowed_entity_aliases": "alice,bob",
+	})
+	mustHandle(t, "auth/token/roles/batch", map[string]interface{}{
+		"orphan":                 true,
+		"token_type":             "batch",
+		"renewable":              false,
+		"allowed_policies":       "policy1",
+		"allowed_entity_aliases": "alice",
+	})
+
+	var aliceTokens []string
+	var aliceID string
+	for i := 0; i < 2; i++ {
+		resp := mustHandle(t, "auth/token/create/test", map[string]interface{}{
+			"entity_alias": "alice",
+		})
+		aliceTokens = append(aliceTokens, resp.Auth.ClientToken)
+		aliceID = resp.Auth.EntityID
+	}
+	resp := mustHandle(t, "auth/token/create/batch", map[string]interface{}{
+		"entity_alias": "alice",
+		"policies":     "policy1",
+	})
+	aliceBatch := resp.Auth.ClientToken
+	resp = mustHandle(t, "auth/token/create/test", map[string]interface{}{
+		"entity_alias": "bob",
+	})
+	bobToken := resp.Auth.ClientToken
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := is.oidcAuthCodeCache.SetDefault(ns, "code", &authCodeCacheEntry{entityID: aliceID}); err != nil {
+		t.Fatal(err)
+	}
+
+	mustRevocations := func(t *testing.T, expected int) {
+		t.Helper()
+		revocations, err := core.tokenStore.batchRevocations(ctx, ns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(revocations) != expected {
+			t.Fatalf("expected %d batch token revocations, got: %d", expected, len(revocations))
+		}
+	}
+
+	expect := func(t *testing.T, resp *logical.Response) {
+		t.Helper()
+		if len(resp.Data["token_accessors"].([]string)) != 2 {
+			t.Fatalf("expected 2 token accessors, got: %#v", resp.Data)
+		}
+		if resp.Data["batch_tokens"].(int) != 1 {
+			t.Fatalf("expected 1 batch token, got: %#v", resp.Data)
+		}
+		if resp.Data["oidc_sessions"].(int) != 1 {
+			t.Fatalf("expected 1 OIDC session, got: %#v", resp.Data)
+		}
+	}
+
+	// A dry run only reports the tokens
+	resp = mustHandle(t, "identity/entity/id/"+aliceID+"/revoke-sessions", map[string]interface{}{
+		"oidc_sessions": true,
+		"dry_run":       true,
+	})
+	expect(t, resp)
+	for _, token := range append(aliceTokens, aliceBatch, bobToken) {
+		mustLookup(t, token, true)
+	}
+	mustRevocations(t, 0)
+
+	resp = mustHandle(t, "identity/entity/id/"+aliceID+"/revoke-sessions", map[string]interface{}{
+		"oidc_sessions": true,
+	})
+	expect(t, resp)
+	for _, token := range append(aliceTokens, aliceBatch) {
+		mustLookup(t, token, false)
+	}
+	mustLookup(t, bobToken, true)
+	mustRevocations(t, 1)
+	if _, ok, _ := is.oidcAuthCodeCache.Get(ns, "code"); ok {
+		t.Fatal("expected the authorization code to be dropped")
+	}
+
+	// Tokens issued afterwards are valid
+	resp = mustHandle(t, "auth/token/create/test", map[string]interface{}{
+		"entity_alias": "alice",
+	})
+	mustLookup(t, resp.Auth.ClientToken, true)
+}
@@ -0,0 +1,400 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/helper/groupresolver"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/patrickmn/go-cache"
+)
+
+const (
+	groupResolverPrefix          = "group-resolver/"
+	groupResolverDefaultCacheTTL = 5 * time.Minute
+
+	// groupResolverAliasMetadataKey marks group aliases that were added by a
+	// group resolver, so that they can be replaced when groups are resolved
+	// again on token renewal.
+	groupResolverAliasMetadataKey = "group_resolver"
+)
+
+type groupResolverConfig struct {
+	Type          string            `json:"type"`
+	MountAccessor string            `json:"mount_accessor"`
+	Config        map[string]string `json:"config"`
+	CacheTTL      time.Duration     `json:"cache_ttl"`
+}
+
+// activeGroupResolver is an instantiated group resolver along with the
+// groups it resolved for each alias name.
+type activeGroupResolver struct {
+	name     string
+	resolver groupresolver.Resolver
+	cache    *cache.Cache
+}
+
+func groupResolverPaths(i *IdentityStore) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "group-resolver/" + framework.GenericNameRegex("name"),
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "group-resolver",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the group resolver.",
+				},
+				"type": {
+					Type:        framework.TypeString,
+					Description: "Type of the group resolver.",
+				},
+				"mount_accessor": {
+					Type:        framework.TypeString,
+					Description: "Accessor of the auth mount whose logins have their groups resolved.",
+				},
+				"config": {
+					Type:        framework.TypeKVPairs,
+					Description: "Configuration of the group resolver, which depends on its type.",
+				},
+				"cache_ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "How long the resolved groups of a user are cached for.",
+					Default:     int(groupResolverDefaultCacheTTL.Seconds()),
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathGroupResolverWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "write",
+					},
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathGroupResolverRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: i.pathGroupResolverDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "delete",
+					},
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(groupResolverHelp["group-resolver"][0]),
+			HelpDescription: strings.TrimSpace(groupResolverHelp["group-resolver"][1]),
+		},
+		{
+			Pattern: "group-resolver/?$",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "group-resolver",
+				OperationVerb:   "list",
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: i.pathGroupResolverList,
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(groupResolverHelp["group-resolver-list"][0]),
+			HelpDescription: strings.TrimSpace(groupResolverHelp["group-resolver-list"][1]),
+		},
+	}
+}
+
+func (i *IdentityStore) pathGroupResolverWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	i.groupResolversLock.Lock()
+	defer i.groupResolversLock.Unlock()
+
+	config, err := i.getGroupResolverConfig(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &groupResolverConfig{
+			CacheTTL: groupResolverDefaultCacheTTL,
+		}
+	}
+
+	if typeRaw, ok := d.GetOk("type"); ok {
+		config.Type = typeRaw.(string)
+	}
+	if config.Type == "" {
+		return logical.ErrorResponse("type is required"), nil
+	}
+	factory, ok := i.groupResolverFactories[config.Type]
+	if !ok {
+		return logical.ErrorResponse("unknown group resolver type %q", config.Type), nil
+	}
+
+	if mountAccessorRaw, ok := d.GetOk("mount_accessor"); ok {
+		config.MountAccessor = mountAccessorRaw.(string)
+	}
+	if config.MountAccessor == "" {
+		return logical.ErrorResponse("mount_accessor is required"), nil
+	}
+	mountEntry := i.router.MatchingMountByAccessor(config.MountAccessor)
+	if mountEntry == nil || mountEntry.Table != credentialTableType {
+		return logical.ErrorResponse("mount_accessor %q is not the accessor of an auth mount", config.MountAccessor), nil
+	}
+
+	if configRaw, ok := d.GetOk("config"); ok {
+		config.Config = configRaw.(map[string]string)
+	}
+	if cacheTTLRaw, ok := d.GetOk("cache_ttl"); ok {
+		config.CacheTTL = time.Duration(cacheTTLRaw.(int)) * time.Second
+	}
+
+	// Only a single group resolver can be configured for an auth mount
+	names, err := i.view.List(ctx, groupResolverPrefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, otherName := range names {
+		if otherName == name {
+			continue
+		}
+		other, err := i.getGroupResolverConfig(ctx, otherName)
+		if err != nil {
+			return nil, err
+		}
+		if other != nil && other.MountAccessor == config.MountAccessor {
+			return logical.ErrorResponse("group resolver %q is already configured for mount_accessor %q", otherName, config.MountAccessor), nil
+		}
+	}
+
+	// Validate the configuration by instantiating the resolver
+	if _, err := factory(ctx, config.Config, i.logger.Named("group-resolver").Named(name)); err != nil {
+		return logical.ErrorResponse("invalid group resolver configuration: %s", err), nil
+	}
+
+	entry, err := logical.StorageEntryJSON(groupResolverPrefix+name, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.view.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	// Group resolvers are reloaded on next use
+	i.groupResolvers = nil
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathGroupResolverRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := i.getGroupResolverConfig(ctx, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, nil
+	}
+
+	// Values of sensitive configuration parameters aren't returned
+	redacted := make(map[string]string, len(config.Config))
+	for k, v := range config.Config {
+		if isSensitiveGroupResolverConfigKey(k) {
+			continue
+		}
+		redacted[k] = v
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"type":           config.Type,
+			"mount_accessor": config.MountAccessor,
+			"config":         redacted,
+			"cache_ttl":      int64(config.CacheTTL.Seconds()),
+		},
+	}, nil
+}
+
+func (i *IdentityStore) pathGroupResolverDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	i.groupResolversLock.Lock()
+	defer i.groupResolversLock.Unlock()
+
+	if err := i.view.Delete(ctx, groupResolverPrefix+d.Get("name").(string)); err != nil {
+		return nil, err
+	}
+	i.groupResolvers = nil
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathGroupResolverList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	names, err := i.view.List(ctx, groupResolverPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(names), nil
+}
+
+func (i *IdentityStore) getGroupResolverConfig(ctx context.Context, name string) (*groupResolverConfig, error) {
+	entry, err := i.view.Get(ctx, groupResolverPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var config groupResolverConfig
+	if err := entry.DecodeJSON(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// groupResolverForAccessor returns the group resolver configured for the auth
+// mount with the given accessor, or nil if there is none. Group resolvers are
+// loaded from storage on first use after they were modified.
+func (i *IdentityStore) groupResolverForAccessor(ctx context.Context, mountAccessor string) (*activeGroupResolver, error) {
+	i.groupResolversLock.RLock()
+	if i.groupResolvers != nil {
+		defer i.groupResolversLock.RUnlock()
+		return i.groupResolvers[mountAccessor], nil
+	}
+	i.groupResolversLock.RUnlock()
+
+	i.groupResolversLock.Lock()
+	defer i.groupResolversLock.Unlock()
+
+	// Check again in case another caller loaded the resolvers
+	if i.groupResolvers != nil {
+		return i.groupResolvers[mountAccessor], nil
+	}
+
+	names, err := i.view.List(ctx, groupResolverPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make(map[string]*activeGroupResolver, len(names))
+	for _, name := range names {
+		config, err := i.getGroupResolverConfig(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			continue
+		}
+
+		factory, ok := i.groupResolverFactories[config.Type]
+		if !ok {
+			i.logger.Error("unknown group resolver type", "name", name, "type", config.Type)
+			continue
+		}
+		resolver, err := factory(ctx, config.Config, i.logger.Named("group-resolver").Named(name))
+		if err != nil {
+			i.logger.Error("failed to create group resolver", "name", name, "error", err)
+			continue
+		}
+
+		resolvers[config.MountAccessor] = &activeGroupResolver{
+			name:     name,
+			resolver: resolver,
+			cache:    cache.New(config.CacheTTL, config.CacheTTL),
+		}
+	}
+	i.groupResolvers = resolvers
+
+	return resolvers[mountAccessor], nil
+}
+
+// resolveGroupAliases returns the given group aliases along with those of the
+// groups resolved for the alias by the group resolver configured for its auth
+// mount. Group aliases added by a previous resolution are replaced.
+func (i *IdentityStore) resolveGroupAliases(ctx context.Context, alias *logical.Alias, groupAliases []*logical.Alias) ([]*logical.Alias, error) {
+	if alias == nil || alias.MountAccessor == "" {
+		return groupAliases, nil
+	}
+
+	r, err := i.groupResolverForAccessor(ctx, alias.MountAccessor)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return groupAliases, nil
+	}
+
+	var groupNames []string
+	if cached, ok := r.cache.Get(alias.Name); ok {
+		groupNames = cached.([]string)
+	} else {
+		groupNames, err = r.resolver.ResolveGroups(ctx, alias)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve groups using group resolver %q: %w", r.name, err)
+		}
+		r.cache.SetDefault(alias.Name, groupNames)
+	}
+
+	existing := make(map[string]struct{}, len(groupAliases))
+	resolved := make([]*logical.Alias, 0, len(groupAliases)+len(groupNames))
+	for _, groupAlias := range groupAliases {
+		if _, ok := groupAlias.Metadata[groupResolverAliasMetadataKey]; ok {
+			continue
+		}
+		existing[groupAlias.Name] = struct{}{}
+		resolved = append(resolved, groupAlias)
+	}
+
+	sort.Strings(groupNames)
+	for _, groupName := range groupNames {
+		if _, ok := existing[groupName]; ok {
+			continue
+		}
+		existing[groupName] = struct{}{}
+		resolved = append(resolved, &logical.Alias{
+			Name:          groupName,
+			MountAccessor: alias.MountAccessor,
+			MountType:     alias.MountType,
+			Metadata: map[string]string{
+				groupResolverAliasMetadataKey: r.name,
+			},
+		})
+	}
+
+	return resolved, nil
+}
+
+func isSensitiveGroupResolverConfigKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range []string{"pass", "secret", "key", "token"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var groupResolverHelp = map[string][2]string{
+	"group-resolver": {
+		"Configure a group resolver for an auth mount.",
+		`
+A group resolver queries an external directory for the groups of users that
+log in using an auth mount. The resolved groups are matched against the group
+aliases of external groups on the mount when users log in and when their
+tokens are renewed, in addition to the groups provided by the auth method.
+Resolved groups are cached for cache_ttl.
+`,
+	},
+	"group-resolver-list": {
+		"List the configured group resolvers.",
+		"",
+	},
+}
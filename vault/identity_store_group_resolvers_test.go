@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/helper/groupresolver"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+type testGroupResolver struct {
+	groups []string
+	err    error
+	calls  int
+}
+
+func (r *testGroupResolver) ResolveGroups(_ context.Context, _ *logical.Alias) ([]string, error) {
+	r.calls++
+	return r.groups, r.err
+}
+
+func testGroupResolverFactory(r *testGroupResolver) groupresolver.Factory {
+	return func(_ context.Context, conf map[string]string, _ log.Logger) (groupresolver.Resolver, error) {
+		if conf["invalid"] != "" {
+			return nil, errors.New("invalid configuration")
+		}
+		return r, nil
+	}
+}
+
+func TestIdentityStore_GroupResolverConfig(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	c.identityStore.groupResolverFactories = map[string]groupresolver.Factory{
+		"test": testGroupResolverFactory(&testGroupResolver{}),
+	}
+
+	tokenMount := c.router.MatchingMountEntry(ctx, "auth/token/")
+	sysMount := c.router.MatchingMountEntry(ctx, "sys/")
+
+	write := func(name string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "group-resolver/" + name,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	// Invalid configurations are rejected
+	resp := write("test", map[string]interface{}{
+		"type":           "unknown",
+		"mount_accessor": tokenMount.Accessor,
+	})
+	require.True(t, resp.IsError())
+
+	resp = write("test", map[string]interface{}{
+		"type":           "test",
+		"mount_accessor": sysMount.Accessor,
+	})
+	require.True(t, resp.IsError())
+
+	resp = write("test", map[string]interface{}{
+		"type":           "test",
+		"mount_accessor": tokenMount.Accessor,
+		"config":         map[string]interface{}{"invalid": "true"},
+	})
+	require.True(t, resp.IsError())
+
+	// Create a group resolver
+	resp = write("test", map[string]interface{}{
+		"type":           "test",
+		"mount_accessor": tokenMount.Accessor,
+		"config":         map[string]interface{}{"url": "ldap://127.0.0.1", "bindpass": "secret"},
+		"cache_ttl":      60,
+	})
+	require.Nil(t, resp)
+
+	// Sensitive configuration isn't returned
+	resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "group-resolver/test",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"type":           "test",
+		"mount_accessor": tokenMount.Accessor,
+		"config":         map[string]string{"url": "ldap://127.0.0.1"},
+		"cache_ttl":      int64(60),
+	}, resp.Data)
+
+	// Only a single group resolver can be configured per auth mount
+	resp = write("other", map[string]interface{}{
+		"type":           "test",
+		"mount_accessor": tokenMount.Accessor,
+	})
+	require.True(t, resp.IsError())
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ListOperation,
+		Path:      "group-resolver/",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"test"}, resp.Data["keys"])
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Operation: logical.DeleteOperation,
+		Path:      "group-resolver/test",
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "group-resolver/test",
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
+
+func TestIdentityStore_ResolveGroupAliases(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	resolver := &testGroupResolver{groups: []string{"engineering", "admins"}}
+	c.identityStore.groupResolverFactories = map[string]groupresolver.Factory{
+		"test": testGroupResolverFactory(resolver),
+	}
+
+	tokenMount := c.router.MatchingMountEntry(ctx, "auth/token/")
+	alias := &logical.Alias{
+		Name:          "alice",
+		MountAccessor: tokenMount.Accessor,
+		MountType:     tokenMount.Type,
+	}
+	providedAliases := []*logical.Alias{
+		{Name: "admins", MountAccessor: tokenMount.Accessor},
+	}
+
+	// Without a group resolver the group aliases are unchanged
+	groupAliases, err := c.identityStore.resolveGroupAliases(ctx, alias, providedAliases)
+	require.NoError(t, err)
+	require.Equal(t, providedAliases, groupAliases)
+
+	resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "group-resolver/test",
+		Data: map[string]interface{}{
+			"type":           "test",
+			"mount_accessor": tokenMount.Accessor,
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	// Resolved groups are added to the provided ones
+	groupAliases, err = c.identityStore.resolveGroupAliases(ctx, alias, providedAliases)
+	require.NoError(t, err)
+	require.Len(t, groupAliases, 2)
+	require.Equal(t, "admins", groupAliases[0].Name)
+	require.Empty(t, groupAliases[0].Metadata)
+	require.Equal(t, "engineering", groupAliases[1].Name)
+	require.Equal(t, "test", groupAliases[1].Metadata[groupResolverAliasMetadataKey])
+	require.Equal(t, 1, resolver.calls)
+
+	// Resolved groups are cached and replace previously resolved ones
+	resolver.groups = []string{"operations"}
+	groupAliases, err = c.identityStore.resolveGroupAliases(ctx, alias, groupAliases)
+	require.NoError(t, err)
+	require.Len(t, groupAliases, 2)
+	require.Equal(t, "engineering", groupAliases[1].Name)
+	require.Equal(t, 1, resolver.calls)
+
+	// Changing the configuration resets the cache
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "group-resolver/test",
+		Data: map[string]interface{}{
+			"cache_ttl": 30,
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	groupAliases, err = c.identityStore.resolveGroupAliases(ctx, alias, groupAliases)
+	require.NoError(t, err)
+	require.Len(t, groupAliases, 2)
+	require.Equal(t, "admins", groupAliases[0].Name)
+	require.Equal(t, "operations", groupAliases[1].Name)
+	require.Equal(t, 2, resolver.calls)
+
+	// Resolution errors are returned
+	resolver.err = errors.New("directory unavailable")
+	_, err = c.identityStore.resolveGroupAliases(ctx, &logical.Alias{Name: "bob", MountAccessor: tokenMount.Accessor}, nil)
+	require.Error(t, err)
+}
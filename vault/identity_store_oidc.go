@@ -1470,8 +1470,6 @@ func (i *IdentityStore) pathOIDCReadPublicKeys(ctx context.Context, req *logical
 }
 
 func (i *IdentityStore) pathOIDCIntrospect(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
-	var claims jwt.Claims
-
 	// helper for preparing the non-standard introspection response
 	introspectionResp := func(errorMsg string) (*logical.Response, error) {
 		response := map[string]interface{}{
@@ -1502,16 +1500,33 @@ func (i *IdentityStore) pathOIDCIntrospect(ctx context.Context, req *logical.Req
 	rawIDToken := d.Get("token").(string)
 	clientID := d.Get("client_id").(string)
 
+	_, invalidReason, err := i.validateIdentityToken(ctx, req.Storage, rawIDToken, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if invalidReason != "" {
+		return introspectionResp(invalidReason)
+	}
+
+	return introspectionResp("")
+}
+
+// validateIdentityToken validates the signature and claims of an identity
+// token and returns the entity it was issued to. If the token is not valid, the
+// reason is returned instead of an error.
+func (i *IdentityStore) validateIdentityToken(ctx context.Context, s logical.Storage, rawIDToken, clientID string) (*identity.Entity, string, error) {
+	var claims jwt.Claims
+
 	// validate basic JWT structure
 	parsedJWT, err := jwt.ParseSigned(rawIDToken)
 	if err != nil {
-		return introspectionResp(fmt.Sprintf("error parsing token: %s", err.Error()))
+		return nil, fmt.Sprintf("error parsing token: %s", err.Error()), nil
 	}
 
 	// validate signature
-	jwks, err := i.generatePublicJWKS(ctx, req.Storage)
+	jwks, err := i.generatePublicJWKS(ctx, s)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var valid bool
@@ -1523,13 +1538,13 @@ func (i *IdentityStore) pathOIDCIntrospect(ctx context.Context, req *logical.Req
 	}
 
 	if !valid {
-		return introspectionResp("unable to validate the token signature")
+		return nil, "unable to validate the token signature", nil
 	}
 
 	// validate claims
-	c, err := i.getOIDCConfig(ctx, req.Storage)
+	c, err := i.getOIDCConfig(ctx, s)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	expected := jwt.Expected{
@@ -1542,21 +1557,21 @@ func (i *IdentityStore) pathOIDCIntrospect(ctx context.Context, req *logical.Req
 	}
 
 	if claimsErr := claims.Validate(expected); claimsErr != nil {
-		return introspectionResp(fmt.Sprintf("error validating claims: %s", claimsErr.Error()))
+		return nil, fmt.Sprintf("error validating claims: %s", claimsErr.Error()), nil
 	}
 
 	// validate entity exists and is active
 	entity, err := i.MemDBEntityByID(claims.Subject, true)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	if entity == nil {
-		return introspectionResp("entity was not found")
+		return nil, "entity was not found", nil
 	} else if entity.Disabled {
-		return introspectionResp("entity is disabled")
+		return nil, "entity is disabled", nil
 	}
 
-	return introspectionResp("")
+	return entity, "", nil
 }
 
 // namedKey.rotate(overrides) performs a key rotation on a namedKey.
@@ -2086,6 +2101,23 @@ func (c *oidcCache) Flush(ns *namespace.Namespace) error {
 	return nil
 }
 
+// DeleteFunc removes the items of every namespace that match, returning the
+// number of items matched. Nothing is removed if dryRun is set.
+func (c *oidcCache) DeleteFunc(match func(interface{}) bool, dryRun bool) int {
+	count := 0
+	for itemKey, item := range c.c.Items() {
+		if !match(item.Object) {
+			continue
+		}
+		count++
+		if !dryRun {
+			c.c.Delete(itemKey)
+		}
+	}
+
+	return count
+}
+
 // isTargetNamespacedKey returns true for a properly constructed namespaced key (<version>:<nsID>:<key>)
 // where <nsID> matches any targeted nsID
 func isTargetNamespacedKey(nskey string, nsTargets []string) bool {
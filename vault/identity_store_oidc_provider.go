@@ -5,16 +5,20 @@ package vault
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-secure-stdlib/base62"
@@ -42,6 +46,18 @@ const (
 	defaultKeyName           = "default"
 	allowAllAssignmentName   = "allow_all"
 
+	// Grant types supported by the Token Endpoint
+	grantTypeAuthorizationCode = "authorization_code"
+	grantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// Device authorization grant constants. See details at
+	// https://datatracker.ietf.org/doc/html/rfc8628.
+	deviceCodeTTL          = 10 * time.Minute
+	deviceCodePollInterval = 5 * time.Second
+	userCodeCharset        = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength         = 8
+	userCodeCachePrefix    = "user_code/"
+
 	// Storage path constants
 	oidcProviderPrefix = "oidc_provider/"
 	assignmentPath     = oidcProviderPrefix + "assignment/"
@@ -67,6 +83,13 @@ const (
 	ErrTokenUnsupportedGrantType = "unsupported_grant_type"
 	ErrTokenServerError          = "server_error"
 
+	// Error constants used in the Token Endpoint for the device authorization
+	// grant. See details at https://datatracker.ietf.org/doc/html/rfc8628#section-3.5
+	ErrTokenAuthorizationPending = "authorization_pending"
+	ErrTokenSlowDown             = "slow_down"
+	ErrTokenAccessDenied         = "access_denied"
+	ErrTokenExpiredToken         = "expired_token"
+
 	// Error constants used in the UserInfo Endpoint. See details at
 	// https://openid.net/specs/openid-connect-core-1_0.html#UserInfoError
 	ErrUserInfoServerError    = "server_error"
@@ -155,6 +178,7 @@ type providerDiscovery struct {
 	Issuer                string   `json:"issuer"`
 	Keys                  string   `json:"jwks_uri"`
 	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	DeviceEndpoint        string   `json:"device_authorization_endpoint"`
 	TokenEndpoint         string   `json:"token_endpoint"`
 	UserinfoEndpoint      string   `json:"userinfo_endpoint"`
 	RequestParameter      bool     `json:"request_parameter_supported"`
@@ -180,6 +204,33 @@ type authCodeCacheEntry struct {
 	codeChallengeMethod string
 }
 
+type deviceCodeStatus int
+
+const (
+	deviceCodePending deviceCodeStatus = iota
+	deviceCodeApproved
+	deviceCodeDenied
+)
+
+// deviceCodeCacheEntry tracks the state of a device authorization request
+// from its issuance until the device code is exchanged for tokens. The entry
+// is shared between the verification and token endpoints, so its mutable
+// fields must only be accessed while holding the lock.
+type deviceCodeCacheEntry struct {
+	sync.Mutex
+
+	provider  string
+	clientID  string
+	userCode  string
+	scopes    []string
+	expiresAt time.Time
+
+	interval   time.Duration
+	lastPolled time.Time
+	status     deviceCodeStatus
+	entityID   string
+}
+
 func oidcProviderPaths(i *IdentityStore) []*framework.Path {
 	return []*framework.Path{
 		{
@@ -537,6 +588,75 @@ func oidcProviderPaths(i *IdentityStore) []*framework.Path {
 			HelpSynopsis:    "Provides the OIDC Authorization Endpoint.",
 			HelpDescription: "The OIDC Authorization Endpoint performs authentication and authorization by using request parameters defined by OpenID Connect (OIDC).",
 		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/device",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "oidc-provider",
+				OperationVerb:   "device-authorize",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+				"scope": {
+					Type:        framework.TypeString,
+					Description: "A space-delimited, case-sensitive list of scopes to be requested. The 'openid' scope is required.",
+					Required:    true,
+				},
+				// Clients authenticate to the device authorization endpoint
+				// the same way they authenticate to the token endpoint.
+				"client_id": {
+					Type:        framework.TypeString,
+					Description: "The ID of the requesting client.",
+				},
+				"client_secret": {
+					Type:        framework.TypeString,
+					Description: "The secret of the requesting client.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback:                    i.pathOIDCDeviceAuthorize,
+					ForwardPerformanceStandby:   true,
+					ForwardPerformanceSecondary: false,
+				},
+			},
+			HelpSynopsis:    "Provides the OAuth 2.0 Device Authorization Endpoint.",
+			HelpDescription: "The Device Authorization Endpoint issues a device code and an end-user code to clients that lack a browser or have limited input capabilities.",
+		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/device/verify",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "oidc-provider",
+				OperationVerb:   "device-verify",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+				"user_code": {
+					Type:        framework.TypeString,
+					Description: "The end-user code displayed on the device.",
+					Required:    true,
+				},
+				"approve": {
+					Type:        framework.TypeBool,
+					Description: "Whether to approve or deny the device authorization request. Defaults to true.",
+					Default:     true,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback:                    i.pathOIDCDeviceVerify,
+					ForwardPerformanceStandby:   true,
+					ForwardPerformanceSecondary: false,
+				},
+			},
+			HelpSynopsis:    "Approves or denies a device authorization request.",
+			HelpDescription: "Approves or denies the device authorization request identified by the given user code on behalf of the identity entity associated with the request.",
+		},
 		{
 			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/token",
 			DisplayAttrs: &framework.DisplayAttributes{
@@ -550,18 +670,20 @@ func oidcProviderPaths(i *IdentityStore) []*framework.Path {
 				},
 				"code": {
 					Type:        framework.TypeString,
-					Description: "The authorization code received from the provider's authorization endpoint.",
-					Required:    true,
+					Description: "The authorization code received from the provider's authorization endpoint. Required for the 'authorization_code' grant type.",
 				},
 				"grant_type": {
 					Type:        framework.TypeString,
-					Description: "The authorization grant type. The following grant types are supported: 'authorization_code'.",
+					Description: "The authorization grant type. The following grant types are supported: 'authorization_code', 'urn:ietf:params:oauth:grant-type:device_code'.",
 					Required:    true,
 				},
 				"redirect_uri": {
 					Type:        framework.TypeString,
-					Description: "The callback location where the authentication response was sent.",
-					Required:    true,
+					Description: "The callback location where the authentication response was sent. Required for the 'authorization_code' grant type.",
+				},
+				"device_code": {
+					Type:        framework.TypeString,
+					Description: "The device code received from the provider's device authorization endpoint. Required for the 'urn:ietf:params:oauth:grant-type:device_code' grant type.",
 				},
 				"code_verifier": {
 					Type:        framework.TypeString,
@@ -1541,6 +1663,7 @@ func (i *IdentityStore) pathOIDCProviderDiscovery(ctx context.Context, req *logi
 		Issuer:                p.effectiveIssuer,
 		Keys:                  p.effectiveIssuer + "/.well-known/keys",
 		AuthorizationEndpoint: strings.Replace(p.effectiveIssuer, "/v1/", "/ui/vault/", 1) + "/authorize",
+		DeviceEndpoint:        p.effectiveIssuer + "/device",
 		TokenEndpoint:         p.effectiveIssuer + "/token",
 		UserinfoEndpoint:      p.effectiveIssuer + "/userinfo",
 		IDTokenAlgs:           supportedAlgs,
@@ -1550,7 +1673,7 @@ func (i *IdentityStore) pathOIDCProviderDiscovery(ctx context.Context, req *logi
 		RequestURIParameter:   false,
 		ResponseTypes:         []string{"code"},
 		Subjects:              []string{"public"},
-		GrantTypes:            []string{"authorization_code"},
+		GrantTypes:            []string{grantTypeAuthorizationCode, grantTypeDeviceCode},
 		AuthMethods: []string{
 			// PKCE is required for auth method "none"
 			"none",
@@ -1928,32 +2051,12 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 		return tokenResponse(nil, ErrTokenInvalidRequest, "provider not found")
 	}
 
-	// client_secret_basic - Check for client credentials in the Authorization header
-	clientID, clientSecret, okBasicAuth := basicAuth(req)
-	if !okBasicAuth {
-		// client_secret_post - Check for client credentials in the request body
-		clientID = d.Get("client_id").(string)
-		if clientID == "" {
-			return tokenResponse(nil, ErrTokenInvalidRequest, "client_id parameter is required")
-		}
-		clientSecret = d.Get("client_secret").(string)
-	}
-	client, err := i.clientByID(ctx, req.Storage, clientID)
-	if err != nil {
-		return tokenResponse(nil, ErrTokenServerError, err.Error())
-	}
-	if client == nil {
-		i.Logger().Debug("client failed to authenticate with client not found", "client_id", clientID)
-		return tokenResponse(nil, ErrTokenInvalidClient, "client failed to authenticate")
-	}
-
-	// Authenticate the client if it's a confidential client type.
-	// Details at https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication
-	if client.Type == confidential &&
-		subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(clientSecret)) == 0 {
-		i.Logger().Debug("client failed to authenticate with invalid client secret", "client_id", clientID)
-		return tokenResponse(nil, ErrTokenInvalidClient, "client failed to authenticate")
+	// Authenticate the client
+	client, errCode, errDesc := i.authenticateOIDCClient(ctx, req, d)
+	if errCode != "" {
+		return tokenResponse(nil, errCode, errDesc)
 	}
+	clientID := client.ClientID
 
 	// Validate that the client is authorized to use the provider
 	if !provider.allowedClientID(clientID) {
@@ -1977,10 +2080,13 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 
 	// Validate the grant type
 	grantType := d.Get("grant_type").(string)
-	if grantType == "" {
+	switch grantType {
+	case "":
 		return tokenResponse(nil, ErrTokenInvalidRequest, "grant_type parameter is required")
-	}
-	if grantType != "authorization_code" {
+	case grantTypeAuthorizationCode:
+	case grantTypeDeviceCode:
+		return i.deviceCodeTokenExchange(ctx, req, d, ns, provider, client, key)
+	default:
 		return tokenResponse(nil, ErrTokenUnsupportedGrantType, "unsupported grant_type value")
 	}
 
@@ -2064,6 +2170,47 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 		}
 	}
 
+	return i.issueOIDCTokens(ctx, req, ns, provider, client, key, entity, authCodeEntry, code)
+}
+
+// authenticateOIDCClient authenticates the client making a request to the token
+// or device authorization endpoint. A non-empty error code and description are
+// returned if the client could not be authenticated.
+func (i *IdentityStore) authenticateOIDCClient(ctx context.Context, req *logical.Request, d *framework.FieldData) (*client, string, string) {
+	// client_secret_basic - Check for client credentials in the Authorization header
+	clientID, clientSecret, okBasicAuth := basicAuth(req)
+	if !okBasicAuth {
+		// client_secret_post - Check for client credentials in the request body
+		clientID = d.Get("client_id").(string)
+		if clientID == "" {
+			return nil, ErrTokenInvalidRequest, "client_id parameter is required"
+		}
+		clientSecret = d.Get("client_secret").(string)
+	}
+	client, err := i.clientByID(ctx, req.Storage, clientID)
+	if err != nil {
+		return nil, ErrTokenServerError, err.Error()
+	}
+	if client == nil {
+		i.Logger().Debug("client failed to authenticate with client not found", "client_id", clientID)
+		return nil, ErrTokenInvalidClient, "client failed to authenticate"
+	}
+
+	// Authenticate the client if it's a confidential client type.
+	// Details at https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication
+	if client.Type == confidential &&
+		subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(clientSecret)) == 0 {
+		i.Logger().Debug("client failed to authenticate with invalid client secret", "client_id", clientID)
+		return nil, ErrTokenInvalidClient, "client failed to authenticate"
+	}
+
+	return client, "", ""
+}
+
+// issueOIDCTokens creates an access token and signed ID token for the given
+// entity and returns them in a token response. The code is the authorization
+// code used in the exchange, if any, and is used to compute the c_hash claim.
+func (i *IdentityStore) issueOIDCTokens(ctx context.Context, req *logical.Request, ns *namespace.Namespace, provider *provider, client *client, key *namedKey, entity *identity.Entity, grant *authCodeCacheEntry, code string) (*logical.Response, error) {
 	// The access token is a Vault batch token with a policy that only
 	// provides access to the issuing provider's userinfo endpoint.
 	accessTokenIssuedAt := time.Now()
@@ -2081,15 +2228,15 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 		},
 		InternalMeta: map[string]string{
 			accessTokenClientIDMeta: client.ClientID,
-			accessTokenScopesMeta:   strings.Join(authCodeEntry.scopes, scopesDelimiter),
+			accessTokenScopesMeta:   strings.Join(grant.scopes, scopesDelimiter),
 		},
 		InlinePolicy: fmt.Sprintf(`
 			path "identity/oidc/provider/%s/userinfo" {
 				capabilities = ["read", "update"]
 			}
-		`, name),
+		`, grant.provider),
 	}
-	err = i.tokenStorer.CreateToken(ctx, accessToken)
+	err := i.tokenStorer.CreateToken(ctx, accessToken)
 	if err != nil {
 		return tokenResponse(nil, ErrTokenServerError, err.Error())
 	}
@@ -2100,34 +2247,35 @@ func (i *IdentityStore) pathOIDCToken(ctx context.Context, req *logical.Request,
 		return tokenResponse(nil, ErrTokenServerError, err.Error())
 	}
 
-	// Compute the authorization code hash claim (c_hash)
-	cHash, err := computeHashClaim(key.Algorithm, code)
-	if err != nil {
-		return tokenResponse(nil, ErrTokenServerError, err.Error())
-	}
-
 	// Set the ID token claims
 	idTokenIssuedAt := time.Now()
 	idTokenExpiry := idTokenIssuedAt.Add(client.IDTokenTTL)
 	idToken := idToken{
 		Namespace:       ns.ID,
 		Issuer:          provider.effectiveIssuer,
-		Subject:         authCodeEntry.entityID,
-		Audience:        authCodeEntry.clientID,
-		Nonce:           authCodeEntry.nonce,
+		Subject:         grant.entityID,
+		Audience:        grant.clientID,
+		Nonce:           grant.nonce,
 		Expiry:          idTokenExpiry.Unix(),
 		IssuedAt:        idTokenIssuedAt.Unix(),
 		AccessTokenHash: atHash,
-		CodeHash:        cHash,
+	}
+
+	// Compute the authorization code hash claim (c_hash)
+	if code != "" {
+		idToken.CodeHash, err = computeHashClaim(key.Algorithm, code)
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
 	}
 
 	// Add the auth_time claim if it's not the zero time instant
-	if !authCodeEntry.authTime.IsZero() {
-		idToken.AuthTime = authCodeEntry.authTime.Unix()
+	if !grant.authTime.IsZero() {
+		idToken.AuthTime = grant.authTime.Unix()
 	}
 
 	// Populate each of the requested scope templates
-	templates, conflict, err := i.populateScopeTemplates(ctx, req.Storage, ns, entity, authCodeEntry.scopes...)
+	templates, conflict, err := i.populateScopeTemplates(ctx, req.Storage, ns, entity, grant.scopes...)
 	if !conflict && err != nil {
 		return tokenResponse(nil, ErrTokenServerError, err.Error())
 	}
@@ -2207,6 +2355,375 @@ func tokenResponse(response map[string]interface{}, errorCode, errorDescription
 	}, nil
 }
 
+// pathOIDCDeviceAuthorize issues a device code and end-user code to a client
+// using the device authorization grant. For details, see spec at
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.1
+func (i *IdentityStore) pathOIDCDeviceAuthorize(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	// Get the namespace
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	// Get the OIDC provider
+	name := d.Get("name").(string)
+	provider, err := i.getOIDCProvider(ctx, req.Storage, name)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if provider == nil {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "provider not found")
+	}
+
+	// Authenticate the client
+	client, errCode, errDesc := i.authenticateOIDCClient(ctx, req, d)
+	if errCode != "" {
+		return tokenResponse(nil, errCode, errDesc)
+	}
+
+	// Validate that the client is authorized to use the provider
+	if !provider.allowedClientID(client.ClientID) {
+		return tokenResponse(nil, ErrTokenInvalidClient, "client is not authorized to use the provider")
+	}
+
+	// Validate that a scope parameter is present and contains the openid scope value
+	requestedScopes := strutil.ParseDedupAndSortStrings(d.Get("scope").(string), scopesDelimiter)
+	if len(requestedScopes) == 0 || !strutil.StrListContains(requestedScopes, openIDScope) {
+		return tokenResponse(nil, ErrTokenInvalidRequest,
+			fmt.Sprintf("scope parameter must contain the %q value", openIDScope))
+	}
+
+	// Scope values that are not supported by the provider should be ignored
+	scopes := make([]string, 0)
+	for _, scope := range requestedScopes {
+		if strutil.StrListContains(provider.ScopesSupported, scope) && scope != openIDScope {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	// Generate the device code
+	deviceCode, err := base62.Random(32)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	// Generate a user code that isn't in use by another pending request
+	var userCode string
+	for attempt := 0; ; attempt++ {
+		if attempt == 5 {
+			return tokenResponse(nil, ErrTokenServerError, "failed to generate a unique user code")
+		}
+
+		userCode, err = generateUserCode()
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+
+		_, exists, err := i.oidcDeviceCodeCache.Get(ns, userCodeCachePrefix+userCode)
+		if err != nil {
+			return tokenResponse(nil, ErrTokenServerError, err.Error())
+		}
+		if !exists {
+			break
+		}
+	}
+
+	// Cache the device code for subsequent verification and polling. The user
+	// code maps back to the device code so that it can be verified by the user.
+	entry := &deviceCodeCacheEntry{
+		provider:  name,
+		clientID:  client.ClientID,
+		userCode:  userCode,
+		scopes:    scopes,
+		expiresAt: time.Now().Add(deviceCodeTTL),
+		interval:  deviceCodePollInterval,
+		status:    deviceCodePending,
+	}
+	if err := i.oidcDeviceCodeCache.SetDefault(ns, deviceCode, entry); err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if err := i.oidcDeviceCodeCache.SetDefault(ns, userCodeCachePrefix+userCode, deviceCode); err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	// The verification URI is served by the UI, which calls the device
+	// verification endpoint on behalf of the authenticated user.
+	displayUserCode := formatUserCode(userCode)
+	verificationURI := strings.Replace(provider.effectiveIssuer, "/v1/", "/ui/vault/", 1) + "/device"
+
+	return tokenResponse(map[string]interface{}{
+		"device_code":               deviceCode,
+		"user_code":                 displayUserCode,
+		"verification_uri":          verificationURI,
+		"verification_uri_complete": verificationURI + "?user_code=" + url.QueryEscape(displayUserCode),
+		"expires_in":                int64(deviceCodeTTL.Seconds()),
+		"interval":                  int64(deviceCodePollInterval.Seconds()),
+	}, "", "")
+}
+
+// pathOIDCDeviceVerify approves or denies a pending device authorization
+// request on behalf of the identity entity associated with the request.
+func (i *IdentityStore) pathOIDCDeviceVerify(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := d.Get("name").(string)
+	provider, err := i.getOIDCProvider(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return logical.ErrorResponse("provider not found"), nil
+	}
+
+	userCode := normalizeUserCode(d.Get("user_code").(string))
+	if userCode == "" {
+		return logical.ErrorResponse("user_code parameter is required"), nil
+	}
+
+	// Validate that there is an identity entity associated with the request
+	if req.EntityID == "" {
+		return logical.ErrorResponse("identity entity must be associated with the request"), nil
+	}
+	entity, err := i.MemDBEntityByID(req.EntityID, false)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return logical.ErrorResponse("identity entity associated with the request not found"), nil
+	}
+
+	deviceCode, entry, err := i.deviceCodeEntryByUserCode(ns, userCode)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return logical.ErrorResponse("user code is invalid or expired"), nil
+	}
+
+	entry.Lock()
+	defer entry.Unlock()
+
+	if entry.provider != name {
+		return logical.ErrorResponse("user code was not issued by the provider"), nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		i.deleteDeviceCode(ns, deviceCode, entry)
+		return logical.ErrorResponse("user code is invalid or expired"), nil
+	}
+	if entry.status != deviceCodePending {
+		return logical.ErrorResponse("device authorization request has already been completed"), nil
+	}
+
+	client, err := i.clientByID(ctx, req.Storage, entry.clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return logical.ErrorResponse("client with client_id not found"), nil
+	}
+
+	approve := d.Get("approve").(bool)
+	if approve {
+		// Validate that the entity is a member of the client's assignments
+		isMember, err := i.entityHasAssignment(ctx, req.Storage, entity, client.Assignments)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return logical.ErrorResponse("identity entity not authorized by client assignment"), nil
+		}
+
+		entry.status = deviceCodeApproved
+		entry.entityID = entity.GetID()
+	} else {
+		entry.status = deviceCodeDenied
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"client":   client.Name,
+			"scopes":   entry.scopes,
+			"approved": approve,
+		},
+	}, nil
+}
+
+// deviceCodeTokenExchange handles a token request using the device code grant
+// type. For details, see spec at
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.4
+func (i *IdentityStore) deviceCodeTokenExchange(ctx context.Context, req *logical.Request, d *framework.FieldData, ns *namespace.Namespace, provider *provider, client *client, key *namedKey) (*logical.Response, error) {
+	deviceCode := d.Get("device_code").(string)
+	if deviceCode == "" {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "device_code parameter is required")
+	}
+
+	entryRaw, ok, err := i.oidcDeviceCodeCache.Get(ns, deviceCode)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if !ok {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "device code is invalid or expired")
+	}
+	entry, ok := entryRaw.(*deviceCodeCacheEntry)
+	if !ok {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "device code is invalid or expired")
+	}
+
+	entry.Lock()
+	defer entry.Unlock()
+
+	// Ensure the device code was issued to the authenticated client
+	if entry.clientID != client.ClientID {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "device code was not issued to the client")
+	}
+
+	// Ensure the device code was issued by the provider
+	if entry.provider != d.Get("name").(string) {
+		return tokenResponse(nil, ErrTokenInvalidGrant, "device code was not issued by the provider")
+	}
+
+	now := time.Now()
+	if now.After(entry.expiresAt) {
+		i.deleteDeviceCode(ns, deviceCode, entry)
+		return tokenResponse(nil, ErrTokenExpiredToken, "device code has expired")
+	}
+
+	switch entry.status {
+	case deviceCodePending:
+		// Clients polling faster than the interval must increase their
+		// polling interval by 5 seconds for this and all subsequent requests.
+		polledTooSoon := !entry.lastPolled.IsZero() && now.Sub(entry.lastPolled) < entry.interval
+		entry.lastPolled = now
+		if polledTooSoon {
+			entry.interval += 5 * time.Second
+			return tokenResponse(nil, ErrTokenSlowDown, "polling too frequently")
+		}
+		return tokenResponse(nil, ErrTokenAuthorizationPending, "device authorization request is pending")
+	case deviceCodeDenied:
+		i.deleteDeviceCode(ns, deviceCode, entry)
+		return tokenResponse(nil, ErrTokenAccessDenied, "device authorization request was denied")
+	}
+
+	// The device code is approved and can only be exchanged once
+	i.deleteDeviceCode(ns, deviceCode, entry)
+
+	// Get the entity that approved the device authorization request
+	entity, err := i.MemDBEntityByID(entry.entityID, true)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if entity == nil {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "identity entity associated with the request not found")
+	}
+
+	// Validate that the entity is a member of the client's assignments
+	isMember, err := i.entityHasAssignment(ctx, req.Storage, entity, client.Assignments)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if !isMember {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "identity entity not authorized by client assignment")
+	}
+
+	grant := &authCodeCacheEntry{
+		provider: entry.provider,
+		clientID: entry.clientID,
+		entityID: entry.entityID,
+		scopes:   entry.scopes,
+	}
+	return i.issueOIDCTokens(ctx, req, ns, provider, client, key, entity, grant, "")
+}
+
+// deviceCodeEntryByUserCode returns the device code and cache entry that the
+// given normalized user code maps to. A nil entry is returned if not found.
+func (i *IdentityStore) deviceCodeEntryByUserCode(ns *namespace.Namespace, userCode string) (string, *deviceCodeCacheEntry, error) {
+	deviceCodeRaw, ok, err := i.oidcDeviceCodeCache.Get(ns, userCodeCachePrefix+userCode)
+	if err != nil || !ok {
+		return "", nil, err
+	}
+	deviceCode, ok := deviceCodeRaw.(string)
+	if !ok {
+		return "", nil, nil
+	}
+
+	entryRaw, ok, err := i.oidcDeviceCodeCache.Get(ns, deviceCode)
+	if err != nil || !ok {
+		return "", nil, err
+	}
+	entry, ok := entryRaw.(*deviceCodeCacheEntry)
+	if !ok {
+		return "", nil, nil
+	}
+
+	return deviceCode, entry, nil
+}
+
+// deleteDeviceCode removes the device code and its user code mapping from the cache.
+func (i *IdentityStore) deleteDeviceCode(ns *namespace.Namespace, deviceCode string, entry *deviceCodeCacheEntry) {
+	i.oidcDeviceCodeCache.Delete(ns, deviceCode)
+	i.oidcDeviceCodeCache.Delete(ns, userCodeCachePrefix+entry.userCode)
+}
+
+// revokeOIDCSessions drops the authorization codes and approved device codes
+// the providers issued to the entity, returning how many there were. The
+// access tokens of the providers are batch tokens revoked with the other
+// tokens of the entity.
+func (i *IdentityStore) revokeOIDCSessions(entityID string, dryRun bool) int {
+	count := i.oidcAuthCodeCache.DeleteFunc(func(obj interface{}) bool {
+		entry, ok := obj.(*authCodeCacheEntry)
+		return ok && entry.entityID == entityID
+	}, dryRun)
+
+	// The user codes left behind no longer map to a device code
+	count += i.oidcDeviceCodeCache.DeleteFunc(func(obj interface{}) bool {
+		entry, ok := obj.(*deviceCodeCacheEntry)
+		if !ok {
+			return false
+		}
+		entry.Lock()
+		defer entry.Unlock()
+		return entry.entityID == entityID
+	}, dryRun)
+
+	return count
+}
+
+// generateUserCode generates a random user code using a charset that
+// excludes vowels and easily confused characters. See details at
+// https://datatracker.ietf.org/doc/html/rfc8628#section-6.1
+func generateUserCode() (string, error) {
+	max := big.NewInt(int64(len(userCodeCharset)))
+	code := make([]byte, userCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = userCodeCharset[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// formatUserCode formats a user code for display by splitting it in half.
+func formatUserCode(userCode string) string {
+	return userCode[:len(userCode)/2] + "-" + userCode[len(userCode)/2:]
+}
+
+// normalizeUserCode strips the separators and whitespace that users commonly
+// type when entering a user code and converts it to uppercase.
+func normalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, userCode)
+}
+
 func (i *IdentityStore) pathOIDCUserInfo(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	// Get the namespace
 	ns, err := namespace.FromContext(ctx)
@@ -1168,6 +1168,132 @@ func TestOIDC_Path_OIDC_Authorize(t *testing.T) {
 	}
 }
 
+// TestOIDC_Path_OIDC_DeviceAuthorization tests the device authorization grant
+// from device code issuance through verification and token polling.
+func TestOIDC_Path_OIDC_DeviceAuthorization(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	s := new(logical.InmemStorage)
+
+	entityID, _, _, clientID, clientSecret := setupOIDCCommon(t, c, s)
+
+	type deviceResponse struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int64  `json:"expires_in"`
+		Interval                int64  `json:"interval"`
+	}
+	type tokenResponse struct {
+		Error       string `json:"error"`
+		AccessToken string `json:"access_token"`
+		IDToken     string `json:"id_token"`
+	}
+
+	deviceAuthorize := func(t *testing.T) deviceResponse {
+		t.Helper()
+		resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+			Storage:   s,
+			Path:      "oidc/provider/test-provider/device",
+			Operation: logical.UpdateOperation,
+			Headers: map[string][]string{
+				"Authorization": {basicAuthHeader(clientID, clientSecret)},
+			},
+			Data: map[string]interface{}{
+				"scope": "openid test-scope",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.Data[logical.HTTPStatusCode])
+
+		var res deviceResponse
+		require.NoError(t, json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &res))
+		return res
+	}
+	poll := func(t *testing.T, deviceCode string) tokenResponse {
+		t.Helper()
+		req := testTokenReq(s, "", clientID, clientSecret)
+		req.Data = map[string]interface{}{
+			"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
+			"device_code": deviceCode,
+		}
+		resp, err := c.identityStore.HandleRequest(ctx, req)
+		require.NoError(t, err)
+
+		var res tokenResponse
+		require.NoError(t, json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &res))
+		return res
+	}
+	verify := func(t *testing.T, userCode string, approve bool) (*logical.Response, error) {
+		t.Helper()
+		return c.identityStore.HandleRequest(ctx, &logical.Request{
+			Storage:   s,
+			Path:      "oidc/provider/test-provider/device/verify",
+			Operation: logical.UpdateOperation,
+			EntityID:  entityID,
+			Data: map[string]interface{}{
+				"user_code": userCode,
+				"approve":   approve,
+			},
+		})
+	}
+
+	t.Run("approved", func(t *testing.T) {
+		device := deviceAuthorize(t)
+		require.Regexp(t, "^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$", device.UserCode)
+		require.Equal(t, "/ui/vault/identity/oidc/provider/test-provider/device", device.VerificationURI)
+		require.Equal(t, device.VerificationURI+"?user_code="+device.UserCode, device.VerificationURIComplete)
+		require.Equal(t, int64(600), device.ExpiresIn)
+		require.Equal(t, int64(5), device.Interval)
+
+		// The user hasn't approved the request yet
+		require.Equal(t, ErrTokenAuthorizationPending, poll(t, device.DeviceCode).Error)
+
+		// Polling again within the interval must slow down
+		require.Equal(t, ErrTokenSlowDown, poll(t, device.DeviceCode).Error)
+
+		// User codes are case-insensitive and the separator is optional
+		resp, err := verify(t, strings.ToLower(strings.ReplaceAll(device.UserCode, "-", "")), true)
+		expectSuccess(t, resp, err)
+		require.Equal(t, true, resp.Data["approved"])
+
+		// The request can only be verified once
+		resp, err = verify(t, device.UserCode, true)
+		require.NoError(t, err)
+		require.True(t, resp.IsError())
+
+		res := poll(t, device.DeviceCode)
+		require.Empty(t, res.Error)
+		require.NotEmpty(t, res.AccessToken)
+		require.NotEmpty(t, res.IDToken)
+
+		// The device code can only be exchanged once
+		require.Equal(t, ErrTokenInvalidGrant, poll(t, device.DeviceCode).Error)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		device := deviceAuthorize(t)
+
+		resp, err := verify(t, device.UserCode, false)
+		expectSuccess(t, resp, err)
+		require.Equal(t, false, resp.Data["approved"])
+
+		require.Equal(t, ErrTokenAccessDenied, poll(t, device.DeviceCode).Error)
+		require.Equal(t, ErrTokenInvalidGrant, poll(t, device.DeviceCode).Error)
+	})
+
+	t.Run("invalid user code", func(t *testing.T) {
+		resp, err := verify(t, "BCDF-GHJK", true)
+		require.NoError(t, err)
+		require.True(t, resp.IsError())
+	})
+
+	t.Run("invalid device code", func(t *testing.T) {
+		require.Equal(t, ErrTokenInvalidGrant, poll(t, "not-a-device-code").Error)
+	})
+}
+
 // setupOIDCCommon creates all of the resources needed to test a Vault OIDC provider.
 // Returns the entity ID, group ID, client ID, client secret to be used in tests.
 func setupOIDCCommon(t *testing.T, c *Core, s logical.Storage) (string, string, string, string, string) {
@@ -3630,9 +3756,10 @@ func TestOIDC_Path_OpenIDProviderConfig(t *testing.T) {
 		Subjects:              []string{"public"},
 		IDTokenAlgs:           supportedAlgs,
 		AuthorizationEndpoint: "/ui/vault/identity/oidc/provider/test-provider/authorize",
+		DeviceEndpoint:        basePath + "/device",
 		TokenEndpoint:         basePath + "/token",
 		UserinfoEndpoint:      basePath + "/userinfo",
-		GrantTypes:            []string{"authorization_code"},
+		GrantTypes:            []string{"authorization_code", "urn:ietf:params:oauth:grant-type:device_code"},
 		AuthMethods:           []string{"none", "client_secret_basic", "client_secret_post"},
 		RequestParameter:      false,
 		RequestURIParameter:   false,
@@ -3686,9 +3813,10 @@ func TestOIDC_Path_OpenIDProviderConfig(t *testing.T) {
 		Subjects:              []string{"public"},
 		IDTokenAlgs:           supportedAlgs,
 		AuthorizationEndpoint: testIssuer + "/ui/vault/identity/oidc/provider/test-provider/authorize",
+		DeviceEndpoint:        basePath + "/device",
 		TokenEndpoint:         basePath + "/token",
 		UserinfoEndpoint:      basePath + "/userinfo",
-		GrantTypes:            []string{"authorization_code"},
+		GrantTypes:            []string{"authorization_code", "urn:ietf:params:oauth:grant-type:device_code"},
 		AuthMethods:           []string{"none", "client_secret_basic", "client_secret_post"},
 		RequestParameter:      false,
 		RequestURIParameter:   false,
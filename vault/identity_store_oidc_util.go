@@ -10,5 +10,5 @@ import (
 )
 
 func (i *IdentityStore) listNamespaces() []*namespace.Namespace {
-	return []*namespace.Namespace{namespace.RootNamespace}
+	return i.namespacer.ListNamespaces(true)
 }
@@ -0,0 +1,1233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// SCIM schema URIs. See details at
+	// https://datatracker.ietf.org/doc/html/rfc7643#section-8.7
+	scimSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	scimSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimSchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	scimContentType  = "application/scim+json"
+	scimDefaultCount = 100
+
+	// Entity and group metadata keys used to store SCIM attributes that don't
+	// have an equivalent in the identity store
+	scimMetadataExternalID  = "scim_external_id"
+	scimMetadataDisplayName = "scim_display_name"
+	scimMetadataEmail       = "email"
+
+	// Error types used in SCIM error responses. See details at
+	// https://datatracker.ietf.org/doc/html/rfc7644#section-3.12
+	scimErrInvalidFilter = "invalidFilter"
+	scimErrInvalidSyntax = "invalidSyntax"
+	scimErrInvalidPath   = "invalidPath"
+	scimErrInvalidValue  = "invalidValue"
+	scimErrUniqueness    = "uniqueness"
+	scimErrMutability    = "mutability"
+)
+
+// scimFilterRegex matches the subset of the SCIM filter syntax that is
+// supported, which is a single attribute compared for equality with a string.
+// This is the form used by identity providers when matching existing resources.
+var scimFilterRegex = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z0-9.]*)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// scimMemberFilterRegex matches the value filter used to remove a single
+// member from a group, e.g. `members[value eq "<id>"]`.
+var scimMemberFilterRegex = regexp.MustCompile(`^(?i:members)\[\s*(?i:value)\s+(?i:eq)\s+"([^"]*)"\s*\]$`)
+
+type scimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+type scimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type scimMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type scimUser struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Emails      []scimEmail  `json:"emails,omitempty"`
+	Groups      []scimMember `json:"groups,omitempty"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+type scimGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []scimMember `json:"members"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+type scimListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type scimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []scimPatchOperation `json:"Operations"`
+}
+
+type scimPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// scimError is both an error and the body of a SCIM error response.
+type scimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+
+	statusCode int
+}
+
+func (e *scimError) Error() string {
+	return e.Detail
+}
+
+func newSCIMError(statusCode int, scimType, detail string) *scimError {
+	return &scimError{
+		Schemas:    []string{scimSchemaError},
+		Status:     strconv.Itoa(statusCode),
+		ScimType:   scimType,
+		Detail:     detail,
+		statusCode: statusCode,
+	}
+}
+
+func scimPaths(i *IdentityStore) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "scim/v2/ServiceProviderConfig",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "scim",
+				OperationVerb:   "read",
+				OperationSuffix: "service-provider-config",
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathSCIMServiceProviderConfig,
+				},
+			},
+			HelpSynopsis:    "Returns the SCIM service provider configuration.",
+			HelpDescription: "Returns the SCIM features that are supported by the identity store's SCIM server.",
+		},
+		{
+			Pattern: "scim/v2/Users",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "scim",
+			},
+			Fields: scimListFields(),
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathSCIMUserList,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "list",
+						OperationSuffix: "users",
+					},
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathSCIMUserCreate,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "create",
+						OperationSuffix: "user",
+					},
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(scimHelp["users"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["users"][1]),
+		},
+		{
+			Pattern: "scim/v2/Users/" + framework.GenericNameRegex("id"),
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "scim",
+				OperationSuffix: "user",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"id": {
+					Type:        framework.TypeString,
+					Description: "ID of the user, which is the ID of its identity entity.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathSCIMUserRead,
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathSCIMUserReplace,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "replace",
+					},
+				},
+				logical.PatchOperation: &framework.PathOperation{
+					Callback: i.pathSCIMUserPatch,
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: i.pathSCIMUserDelete,
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(scimHelp["users"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["users"][1]),
+		},
+		{
+			Pattern: "scim/v2/Groups",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "scim",
+			},
+			Fields: scimListFields(),
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathSCIMGroupList,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "list",
+						OperationSuffix: "groups",
+					},
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathSCIMGroupCreate,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "create",
+						OperationSuffix: "group",
+					},
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(scimHelp["groups"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["groups"][1]),
+		},
+		{
+			Pattern: "scim/v2/Groups/" + framework.GenericNameRegex("id"),
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "scim",
+				OperationSuffix: "group",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"id": {
+					Type:        framework.TypeString,
+					Description: "ID of the group.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathSCIMGroupRead,
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathSCIMGroupReplace,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "replace",
+					},
+				},
+				logical.PatchOperation: &framework.PathOperation{
+					Callback: i.pathSCIMGroupPatch,
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: i.pathSCIMGroupDelete,
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(scimHelp["groups"][0]),
+			HelpDescription: strings.TrimSpace(scimHelp["groups"][1]),
+		},
+	}
+}
+
+func scimListFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"filter": {
+			Type:        framework.TypeString,
+			Description: `Filter to apply to the results. Only a single equality comparison, e.g. 'userName eq "alice"', is supported.`,
+		},
+		"startIndex": {
+			Type:        framework.TypeInt,
+			Description: "The 1-based index of the first result to return.",
+			Default:     1,
+		},
+		"count": {
+			Type:        framework.TypeInt,
+			Description: "The maximum number of results to return.",
+			Default:     scimDefaultCount,
+		},
+	}
+}
+
+func (i *IdentityStore) pathSCIMServiceProviderConfig(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return scimResponse(http.StatusOK, map[string]interface{}{
+		"schemas":          []string{scimSchemaServiceProviderConfig},
+		"patch":            map[string]interface{}{"supported": true},
+		"bulk":             map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":           map[string]interface{}{"supported": true, "maxResults": scimDefaultCount},
+		"changePassword":   map[string]interface{}{"supported": false},
+		"sort":             map[string]interface{}{"supported": false},
+		"etag":             map[string]interface{}{"supported": false},
+		"documentationUri": "https://developer.hashicorp.com/vault/api-docs/secret/identity/scim",
+		"authenticationSchemes": []map[string]interface{}{
+			{
+				"type":        "oauthbearertoken",
+				"name":        "OAuth Bearer Token",
+				"description": "Authentication using a Vault token in the Authorization header",
+				"primary":     true,
+			},
+		},
+	})
+}
+
+func (i *IdentityStore) pathSCIMUserList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attr, value, err := parseSCIMFilter(d.Get("filter").(string), "id", "userName", "externalId")
+	if err != nil {
+		return scimErrorResponse(err)
+	}
+
+	txn := i.db.Txn(false)
+	iter, err := txn.Get(entitiesTable, "namespace_id", ns.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch iterator for entities in memdb: %w", err)
+	}
+
+	var entities []*identity.Entity
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		entity := raw.(*identity.Entity)
+
+		switch attr {
+		case "id":
+			if entity.ID != value {
+				continue
+			}
+		case "username":
+			if !strings.EqualFold(entity.Name, value) {
+				continue
+			}
+		case "externalid":
+			if entity.Metadata[scimMetadataExternalID] != value {
+				continue
+			}
+		}
+
+		entities = append(entities, entity)
+	}
+	sort.Slice(entities, func(a, b int) bool { return entities[a].Name < entities[b].Name })
+
+	startIndex, count := scimPage(d, len(entities))
+	resources := make([]interface{}, 0, count)
+	for _, entity := range entities[startIndex-1 : startIndex-1+count] {
+		user, err := i.scimUserFromEntity(req, ns, entity)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, user)
+	}
+
+	return scimListResult(len(entities), startIndex, resources)
+}
+
+func (i *IdentityStore) pathSCIMUserCreate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var user scimUser
+	if err := decodeSCIMRequest(req, &user); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	entity := new(identity.Entity)
+	if err := applySCIMUser(entity, &user); err != nil {
+		return scimErrorResponse(err)
+	}
+	if err := i.scimUpsertEntity(ctx, entity); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	created, err := i.scimUserFromEntity(req, ns, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimResponse(http.StatusCreated, created)
+}
+
+func (i *IdentityStore) pathSCIMUserRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := i.scimEntityByID(ns, d.Get("id").(string), false)
+	if err != nil {
+		return scimErrorResponse(err)
+	}
+
+	user, err := i.scimUserFromEntity(req, ns, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimResponse(http.StatusOK, user)
+}
+
+func (i *IdentityStore) pathSCIMUserReplace(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var user scimUser
+	if err := decodeSCIMRequest(req, &user); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	entity, err := i.scimEntityByID(ns, d.Get("id").(string), true)
+	if err != nil {
+		return scimErrorResponse(err)
+	}
+
+	// Attributes that aren't provided are cleared when replacing a user
+	for _, key := range []string{scimMetadataExternalID, scimMetadataDisplayName, scimMetadataEmail} {
+		delete(entity.Metadata, key)
+	}
+	if err := applySCIMUser(entity, &user); err != nil {
+		return scimErrorResponse(err)
+	}
+	if err := i.scimUpsertEntity(ctx, entity); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	replaced, err := i.scimUserFromEntity(req, ns, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimResponse(http.StatusOK, replaced)
+}
+
+func (i *IdentityStore) pathSCIMUserPatch(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch scimPatchRequest
+	if err := decodeSCIMRequest(req, &patch); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	entity, err := i.scimEntityByID(ns, d.Get("id").(string), true)
+	if err != nil {
+		return scimErrorResponse(err)
+	}
+
+	for _, op := range patch.Operations {
+		if err := applySCIMPatchOperation(op, func(attr string, value json.RawMessage, remove bool) error {
+			return setSCIMUserAttribute(entity, attr, value, remove)
+		}); err != nil {
+			return scimErrorResponse(err)
+		}
+	}
+	if err := i.scimUpsertEntity(ctx, entity); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	patched, err := i.scimUserFromEntity(req, ns, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimResponse(http.StatusOK, patched)
+}
+
+func (i *IdentityStore) pathSCIMUserDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	txn := i.db.Txn(true)
+	defer txn.Abort()
+
+	entity, err := i.MemDBEntityByIDInTxn(txn, d.Get("id").(string), true)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil || entity.NamespaceID != ns.ID {
+		return scimErrorResponse(newSCIMError(http.StatusNotFound, "", "user not found"))
+	}
+
+	if err := i.handleEntityDeleteCommon(ctx, txn, entity, true); err != nil {
+		return nil, err
+	}
+
+	txn.Commit()
+
+	return scimResponse(http.StatusNoContent, nil)
+}
+
+func (i *IdentityStore) pathSCIMGroupList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attr, value, err := parseSCIMFilter(d.Get("filter").(string), "id", "displayName", "externalId")
+	if err != nil {
+		return scimErrorResponse(err)
+	}
+
+	txn := i.db.Txn(false)
+	iter, err := txn.Get(groupsTable, "namespace_id", ns.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch iterator for groups in memdb: %w", err)
+	}
+
+	var groups []*identity.Group
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		group := raw.(*identity.Group)
+
+		// Membership of external groups is managed by auth methods
+		if group.Type != groupTypeInternal {
+			continue
+		}
+
+		switch attr {
+		case "id":
+			if group.ID != value {
+				continue
+			}
+		case "displayname":
+			if !strings.EqualFold(group.Name, value) {
+				continue
+			}
+		case "externalid":
+			if group.Metadata[scimMetadataExternalID] != value {
+				continue
+			}
+		}
+
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(a, b int) bool { return groups[a].Name < groups[b].Name })
+
+	startIndex, count := scimPage(d, len(groups))
+	resources := make([]interface{}, 0, count)
+	for _, group := range groups[startIndex-1 : startIndex-1+count] {
+		resources = append(resources, i.scimGroupFromGroup(req, ns, group))
+	}
+
+	return scimListResult(len(groups), startIndex, resources)
+}
+
+func (i *IdentityStore) pathSCIMGroupCreate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var scimGroup scimGroup
+	if err := decodeSCIMRequest(req, &scimGroup); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	i.groupLock.Lock()
+	defer i.groupLock.Unlock()
+
+	group := &identity.Group{
+		Type: groupTypeInternal,
+	}
+	if err := applySCIMGroup(group, &scimGroup); err != nil {
+		return scimErrorResponse(err)
+	}
+	if err := i.scimUpsertGroup(ctx, ns, group); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	return scimResponse(http.StatusCreated, i.scimGroupFromGroup(req, ns, group))
+}
+
+func (i *IdentityStore) pathSCIMGroupRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := i.scimGroupByID(ns, d.Get("id").(string), false)
+	if err != nil {
+		return scimErrorResponse(err)
+	}
+
+	return scimResponse(http.StatusOK, i.scimGroupFromGroup(req, ns, group))
+}
+
+func (i *IdentityStore) pathSCIMGroupReplace(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var scimGroup scimGroup
+	if err := decodeSCIMRequest(req, &scimGroup); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	i.groupLock.Lock()
+	defer i.groupLock.Unlock()
+
+	group, err := i.scimGroupByID(ns, d.Get("id").(string), true)
+	if err != nil {
+		return scimErrorResponse(err)
+	}
+
+	// Attributes that aren't provided are cleared when replacing a group
+	delete(group.Metadata, scimMetadataExternalID)
+	group.MemberEntityIDs = nil
+	if err := applySCIMGroup(group, &scimGroup); err != nil {
+		return scimErrorResponse(err)
+	}
+	if err := i.scimUpsertGroup(ctx, ns, group); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	return scimResponse(http.StatusOK, i.scimGroupFromGroup(req, ns, group))
+}
+
+func (i *IdentityStore) pathSCIMGroupPatch(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch scimPatchRequest
+	if err := decodeSCIMRequest(req, &patch); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	i.groupLock.Lock()
+	defer i.groupLock.Unlock()
+
+	group, err := i.scimGroupByID(ns, d.Get("id").(string), true)
+	if err != nil {
+		return scimErrorResponse(err)
+	}
+
+	for _, op := range patch.Operations {
+		// Removing a single member is expressed using a value filter in the path
+		if matches := scimMemberFilterRegex.FindStringSubmatch(op.Path); matches != nil {
+			if !strings.EqualFold(op.Op, "remove") {
+				return scimErrorResponse(newSCIMError(http.StatusBadRequest, scimErrInvalidPath,
+					fmt.Sprintf("unsupported path %q for %q operation", op.Path, op.Op)))
+			}
+			group.MemberEntityIDs = strutil.StrListDelete(group.MemberEntityIDs, matches[1])
+			continue
+		}
+
+		if err := applySCIMPatchOperation(op, func(attr string, value json.RawMessage, remove bool) error {
+			return setSCIMGroupAttribute(group, strings.ToLower(op.Op), attr, value, remove)
+		}); err != nil {
+			return scimErrorResponse(err)
+		}
+	}
+	if err := i.scimUpsertGroup(ctx, ns, group); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	return scimResponse(http.StatusOK, i.scimGroupFromGroup(req, ns, group))
+}
+
+func (i *IdentityStore) pathSCIMGroupDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := d.Get("id").(string)
+	if _, err := i.scimGroupByID(ns, id, false); err != nil {
+		return scimErrorResponse(err)
+	}
+
+	resp, err := i.handleGroupDeleteCommon(ctx, id, true)
+	if err != nil || resp != nil {
+		return resp, err
+	}
+
+	return scimResponse(http.StatusNoContent, nil)
+}
+
+// scimEntityByID returns the entity with the given ID if it belongs to the
+// namespace, or a SCIM not found error.
+func (i *IdentityStore) scimEntityByID(ns *namespace.Namespace, id string, clone bool) (*identity.Entity, error) {
+	entity, err := i.MemDBEntityByID(id, clone)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil || entity.NamespaceID != ns.ID {
+		return nil, newSCIMError(http.StatusNotFound, "", "user not found")
+	}
+
+	return entity, nil
+}
+
+// scimGroupByID returns the internal group with the given ID if it belongs to
+// the namespace, or a SCIM not found error.
+func (i *IdentityStore) scimGroupByID(ns *namespace.Namespace, id string, clone bool) (*identity.Group, error) {
+	group, err := i.MemDBGroupByID(id, clone)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil || group.NamespaceID != ns.ID || group.Type != groupTypeInternal {
+		return nil, newSCIMError(http.StatusNotFound, "", "group not found")
+	}
+
+	return group, nil
+}
+
+// scimUpsertEntity validates and persists an entity that was created or
+// modified by a SCIM request. The caller must hold the identity store lock.
+func (i *IdentityStore) scimUpsertEntity(ctx context.Context, entity *identity.Entity) error {
+	if entity.Name == "" {
+		return newSCIMError(http.StatusBadRequest, scimErrInvalidValue, "userName is required")
+	}
+
+	entityByName, err := i.MemDBEntityByName(ctx, entity.Name, false)
+	if err != nil {
+		return err
+	}
+	if entityByName != nil && entityByName.ID != entity.ID {
+		return newSCIMError(http.StatusConflict, scimErrUniqueness, "userName is already in use")
+	}
+
+	if err := i.sanitizeEntity(ctx, entity); err != nil {
+		return err
+	}
+
+	return i.upsertEntity(ctx, entity, nil, true)
+}
+
+// scimUpsertGroup validates and persists a group that was created or
+// modified by a SCIM request. The caller must hold the group lock.
+func (i *IdentityStore) scimUpsertGroup(ctx context.Context, ns *namespace.Namespace, group *identity.Group) error {
+	if group.Name == "" {
+		return newSCIMError(http.StatusBadRequest, scimErrInvalidValue, "displayName is required")
+	}
+
+	groupByName, err := i.MemDBGroupByName(ctx, group.Name, false)
+	if err != nil {
+		return err
+	}
+	if groupByName != nil && groupByName.ID != group.ID {
+		return newSCIMError(http.StatusConflict, scimErrUniqueness, "displayName is already in use")
+	}
+
+	// Members must be users in the same namespace as the group
+	for _, entityID := range group.MemberEntityIDs {
+		entity, err := i.MemDBEntityByID(entityID, false)
+		if err != nil {
+			return err
+		}
+		if entity == nil || entity.NamespaceID != ns.ID {
+			return newSCIMError(http.StatusBadRequest, scimErrInvalidValue, fmt.Sprintf("member %q is not a valid user", entityID))
+		}
+	}
+
+	return i.sanitizeAndUpsertGroup(ctx, group, nil, nil)
+}
+
+func (i *IdentityStore) scimUserFromEntity(req *logical.Request, ns *namespace.Namespace, entity *identity.Entity) (*scimUser, error) {
+	active := !entity.Disabled
+	user := &scimUser{
+		Schemas:     []string{scimSchemaUser},
+		ID:          entity.ID,
+		ExternalID:  entity.Metadata[scimMetadataExternalID],
+		UserName:    entity.Name,
+		DisplayName: entity.Metadata[scimMetadataDisplayName],
+		Active:      &active,
+		Meta:        scimResourceMeta(req, ns, "User", entity.ID, entity.CreationTime, entity.LastUpdateTime),
+	}
+
+	if email := entity.Metadata[scimMetadataEmail]; email != "" {
+		user.Emails = []scimEmail{{Value: email, Primary: true}}
+	}
+
+	groups, err := i.MemDBGroupsByMemberEntityID(entity.ID, false, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		if group.Type != groupTypeInternal {
+			continue
+		}
+		user.Groups = append(user.Groups, scimMember{
+			Value:   group.ID,
+			Display: group.Name,
+			Ref:     scimResourceLocation(req, ns, "Groups", group.ID),
+		})
+	}
+
+	return user, nil
+}
+
+func (i *IdentityStore) scimGroupFromGroup(req *logical.Request, ns *namespace.Namespace, group *identity.Group) *scimGroup {
+	scimGroup := &scimGroup{
+		Schemas:     []string{scimSchemaGroup},
+		ID:          group.ID,
+		ExternalID:  group.Metadata[scimMetadataExternalID],
+		DisplayName: group.Name,
+		Members:     make([]scimMember, 0, len(group.MemberEntityIDs)),
+		Meta:        scimResourceMeta(req, ns, "Group", group.ID, group.CreationTime, group.LastUpdateTime),
+	}
+
+	for _, entityID := range group.MemberEntityIDs {
+		member := scimMember{
+			Value: entityID,
+			Ref:   scimResourceLocation(req, ns, "Users", entityID),
+		}
+		if entity, err := i.MemDBEntityByID(entityID, false); err == nil && entity != nil {
+			member.Display = entity.Name
+		}
+		scimGroup.Members = append(scimGroup.Members, member)
+	}
+
+	return scimGroup
+}
+
+// applySCIMUser sets the attributes of the entity from a SCIM user resource.
+func applySCIMUser(entity *identity.Entity, user *scimUser) error {
+	entity.Name = user.UserName
+	if user.Active != nil {
+		entity.Disabled = !*user.Active
+	}
+
+	setSCIMMetadata(&entity.Metadata, scimMetadataExternalID, user.ExternalID)
+	setSCIMMetadata(&entity.Metadata, scimMetadataDisplayName, user.DisplayName)
+	setSCIMMetadata(&entity.Metadata, scimMetadataEmail, primarySCIMEmail(user.Emails))
+
+	return nil
+}
+
+// applySCIMGroup sets the attributes of the group from a SCIM group resource.
+func applySCIMGroup(group *identity.Group, scimGroup *scimGroup) error {
+	group.Name = scimGroup.DisplayName
+	setSCIMMetadata(&group.Metadata, scimMetadataExternalID, scimGroup.ExternalID)
+
+	for _, member := range scimGroup.Members {
+		group.MemberEntityIDs = append(group.MemberEntityIDs, member.Value)
+	}
+	group.MemberEntityIDs = strutil.RemoveDuplicates(group.MemberEntityIDs, false)
+
+	return nil
+}
+
+// applySCIMPatchOperation applies a single PATCH operation using the given
+// function to set or remove individual attributes. Operations without a path
+// set every attribute present in the value object. See details at
+// https://datatracker.ietf.org/doc/html/rfc7644#section-3.5.2
+func applySCIMPatchOperation(op scimPatchOperation, set func(attr string, value json.RawMessage, remove bool) error) error {
+	var remove bool
+	switch strings.ToLower(op.Op) {
+	case "add", "replace":
+	case "remove":
+		remove = true
+	default:
+		return newSCIMError(http.StatusBadRequest, scimErrInvalidSyntax, fmt.Sprintf("unsupported operation %q", op.Op))
+	}
+
+	if op.Path != "" {
+		return set(op.Path, op.Value, remove)
+	}
+	if remove {
+		return newSCIMError(http.StatusBadRequest, scimErrInvalidPath, "path is required for remove operations")
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &values); err != nil {
+		return newSCIMError(http.StatusBadRequest, scimErrInvalidValue, "value must be an object when path is not provided")
+	}
+
+	// Apply the attributes in a stable order
+	attrs := make([]string, 0, len(values))
+	for attr := range values {
+		attrs = append(attrs, attr)
+	}
+	sort.Strings(attrs)
+
+	for _, attr := range attrs {
+		if err := set(attr, values[attr], false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setSCIMUserAttribute sets or removes a single user attribute on the entity.
+// Attributes that aren't stored in the identity store are ignored, since
+// identity providers commonly send attributes beyond the core set.
+func setSCIMUserAttribute(entity *identity.Entity, attr string, value json.RawMessage, remove bool) error {
+	attr = strings.ToLower(strings.TrimPrefix(attr, scimSchemaUser+":"))
+
+	switch {
+	case attr == "active":
+		if remove {
+			return newSCIMError(http.StatusBadRequest, scimErrMutability, "active cannot be removed")
+		}
+		active, err := scimBoolValue(value)
+		if err != nil {
+			return err
+		}
+		entity.Disabled = !active
+
+	case attr == "username":
+		if remove {
+			return newSCIMError(http.StatusBadRequest, scimErrMutability, "userName cannot be removed")
+		}
+		userName, err := scimStringValue(value)
+		if err != nil {
+			return err
+		}
+		entity.Name = userName
+
+	case attr == "displayname":
+		return setSCIMMetadataValue(&entity.Metadata, scimMetadataDisplayName, value, remove)
+
+	case attr == "externalid":
+		return setSCIMMetadataValue(&entity.Metadata, scimMetadataExternalID, value, remove)
+
+	case attr == "emails":
+		if remove {
+			delete(entity.Metadata, scimMetadataEmail)
+			return nil
+		}
+		var emails []scimEmail
+		if err := json.Unmarshal(value, &emails); err != nil {
+			return newSCIMError(http.StatusBadRequest, scimErrInvalidValue, "emails must be a list of email objects")
+		}
+		setSCIMMetadata(&entity.Metadata, scimMetadataEmail, primarySCIMEmail(emails))
+
+	case strings.HasPrefix(attr, "emails[") && strings.HasSuffix(attr, "].value"):
+		// A value filter selecting a single email, e.g. `emails[type eq "work"].value`
+		return setSCIMMetadataValue(&entity.Metadata, scimMetadataEmail, value, remove)
+	}
+
+	return nil
+}
+
+// setSCIMGroupAttribute sets or removes a single group attribute. Members are
+// added to or removed from the existing members for the add and remove
+// operations, and replaced for the replace operation.
+func setSCIMGroupAttribute(group *identity.Group, op, attr string, value json.RawMessage, remove bool) error {
+	attr = strings.ToLower(strings.TrimPrefix(attr, scimSchemaGroup+":"))
+
+	switch attr {
+	case "displayname":
+		if remove {
+			return newSCIMError(http.StatusBadRequest, scimErrMutability, "displayName cannot be removed")
+		}
+		displayName, err := scimStringValue(value)
+		if err != nil {
+			return err
+		}
+		group.Name = displayName
+
+	case "externalid":
+		return setSCIMMetadataValue(&group.Metadata, scimMetadataExternalID, value, remove)
+
+	case "members":
+		var members []scimMember
+		if len(value) > 0 && string(value) != "null" {
+			if err := json.Unmarshal(value, &members); err != nil {
+				return newSCIMError(http.StatusBadRequest, scimErrInvalidValue, "members must be a list of member objects")
+			}
+		}
+
+		switch {
+		case remove && len(members) == 0:
+			group.MemberEntityIDs = nil
+		case remove:
+			for _, member := range members {
+				group.MemberEntityIDs = strutil.StrListDelete(group.MemberEntityIDs, member.Value)
+			}
+		case op == "replace":
+			group.MemberEntityIDs = nil
+			fallthrough
+		default:
+			for _, member := range members {
+				group.MemberEntityIDs = append(group.MemberEntityIDs, member.Value)
+			}
+			group.MemberEntityIDs = strutil.RemoveDuplicates(group.MemberEntityIDs, false)
+		}
+	}
+
+	return nil
+}
+
+// parseSCIMFilter parses a filter into the lowercased attribute name and the
+// value it must equal. An empty attribute is returned if no filter is given.
+func parseSCIMFilter(filter string, supportedAttrs ...string) (string, string, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", "", nil
+	}
+
+	matches := scimFilterRegex.FindStringSubmatch(filter)
+	if matches == nil {
+		return "", "", newSCIMError(http.StatusBadRequest, scimErrInvalidFilter,
+			`only filters of the form 'attribute eq "value"' are supported`)
+	}
+
+	attr := matches[1]
+	if !strutil.StrListContainsCaseInsensitive(supportedAttrs, attr) {
+		return "", "", newSCIMError(http.StatusBadRequest, scimErrInvalidFilter,
+			fmt.Sprintf("filtering on %q is not supported", attr))
+	}
+
+	value, err := strconv.Unquote(`"` + matches[2] + `"`)
+	if err != nil {
+		return "", "", newSCIMError(http.StatusBadRequest, scimErrInvalidFilter, "invalid filter value")
+	}
+
+	return strings.ToLower(attr), value, nil
+}
+
+// scimPage returns the 1-based start index and the number of resources to
+// return from a result set of the given size.
+func scimPage(d *framework.FieldData, total int) (int, int) {
+	startIndex := d.Get("startIndex").(int)
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if startIndex > total+1 {
+		startIndex = total + 1
+	}
+
+	count := d.Get("count").(int)
+	if count < 0 {
+		count = 0
+	}
+	if count > scimDefaultCount {
+		count = scimDefaultCount
+	}
+	if remaining := total - (startIndex - 1); count > remaining {
+		count = remaining
+	}
+
+	return startIndex, count
+}
+
+func scimListResult(total, startIndex int, resources []interface{}) (*logical.Response, error) {
+	return scimResponse(http.StatusOK, &scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func scimResourceLocation(req *logical.Request, ns *namespace.Namespace, endpoint, id string) string {
+	return fmt.Sprintf("/v1/%s%sscim/v2/%s/%s", ns.Path, req.MountPoint, endpoint, id)
+}
+
+func scimResourceMeta(req *logical.Request, ns *namespace.Namespace, resourceType, id string, created, modified *timestamppb.Timestamp) *scimMeta {
+	meta := &scimMeta{
+		ResourceType: resourceType,
+		Location:     scimResourceLocation(req, ns, resourceType+"s", id),
+	}
+	if created != nil {
+		meta.Created = created.AsTime().Format(time.RFC3339)
+	}
+	if modified != nil {
+		meta.LastModified = modified.AsTime().Format(time.RFC3339)
+	}
+
+	return meta
+}
+
+// decodeSCIMRequest decodes the JSON request body into the given SCIM resource.
+func decodeSCIMRequest(req *logical.Request, out interface{}) error {
+	body, err := json.Marshal(req.Data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return newSCIMError(http.StatusBadRequest, scimErrInvalidSyntax, fmt.Sprintf("failed to decode request: %v", err))
+	}
+
+	return nil
+}
+
+// scimResponse returns a raw response with the given SCIM resource as the body.
+func scimResponse(statusCode int, body interface{}) (*logical.Response, error) {
+	data := map[string]interface{}{
+		logical.HTTPStatusCode: statusCode,
+	}
+
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		data[logical.HTTPRawBody] = raw
+		data[logical.HTTPContentType] = scimContentType
+	}
+
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+// scimErrorResponse returns a SCIM error response for SCIM errors. All other
+// errors are returned as-is and result in a regular Vault error response.
+func scimErrorResponse(err error) (*logical.Response, error) {
+	var scimErr *scimError
+	if errors.As(err, &scimErr) {
+		return scimResponse(scimErr.statusCode, scimErr)
+	}
+
+	return nil, err
+}
+
+func scimStringValue(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", newSCIMError(http.StatusBadRequest, scimErrInvalidValue, "value must be a string")
+	}
+
+	return s, nil
+}
+
+// scimBoolValue parses a boolean value. Some identity providers send booleans
+// as strings, e.g. "False", so those are accepted as well.
+func scimBoolValue(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(strings.ToLower(s)); err == nil {
+			return b, nil
+		}
+	}
+
+	return false, newSCIMError(http.StatusBadRequest, scimErrInvalidValue, "value must be a boolean")
+}
+
+func setSCIMMetadataValue(metadata *map[string]string, key string, value json.RawMessage, remove bool) error {
+	if remove {
+		delete(*metadata, key)
+		return nil
+	}
+
+	s, err := scimStringValue(value)
+	if err != nil {
+		return err
+	}
+	setSCIMMetadata(metadata, key, s)
+
+	return nil
+}
+
+func setSCIMMetadata(metadata *map[string]string, key, value string) {
+	if value == "" {
+		delete(*metadata, key)
+		return
+	}
+	if *metadata == nil {
+		*metadata = make(map[string]string)
+	}
+	(*metadata)[key] = value
+}
+
+// primarySCIMEmail returns the primary email, or the first email if none of
+// them are marked as primary.
+func primarySCIMEmail(emails []scimEmail) string {
+	for _, email := range emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+
+	return ""
+}
+
+var scimHelp = map[string][2]string{
+	"users": {
+		"Provision users using SCIM 2.0.",
+		`
+SCIM users map to identity entities. The userName is the entity name, and a
+user that is not active is a disabled entity. The externalId, displayName and
+primary email are stored in the entity metadata.
+`,
+	},
+	"groups": {
+		"Provision groups using SCIM 2.0.",
+		`
+SCIM groups map to internal identity groups. The displayName is the group
+name, and the members are the IDs of the entities that belong to the group.
+The externalId is stored in the group metadata.
+`,
+	},
+}
@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func testSCIMRequest(t *testing.T, c *Core, op logical.Operation, path string, data map[string]interface{}, out interface{}) int {
+	t.Helper()
+
+	resp, err := c.identityStore.HandleRequest(namespace.RootContext(nil), &logical.Request{
+		Operation:  op,
+		Path:       path,
+		MountPoint: "identity/",
+		Data:       data,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	if out != nil {
+		require.Equal(t, scimContentType, resp.Data[logical.HTTPContentType])
+		require.NoError(t, json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), out))
+	}
+
+	return resp.Data[logical.HTTPStatusCode].(int)
+}
+
+func TestIdentityStore_SCIMUsers(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	// Create a user
+	var user scimUser
+	status := testSCIMRequest(t, c, logical.UpdateOperation, "scim/v2/Users", map[string]interface{}{
+		"schemas":     []interface{}{scimSchemaUser},
+		"userName":    "alice@example.com",
+		"externalId":  "00u1",
+		"displayName": "Alice",
+		"active":      true,
+		"emails": []interface{}{
+			map[string]interface{}{"value": "alice@example.com", "type": "work", "primary": true},
+		},
+	}, &user)
+	require.Equal(t, http.StatusCreated, status)
+	require.NotEmpty(t, user.ID)
+	require.Equal(t, "alice@example.com", user.UserName)
+	require.Equal(t, "/v1/identity/scim/v2/Users/"+user.ID, user.Meta.Location)
+
+	entity, err := c.identityStore.MemDBEntityByID(user.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, "alice@example.com", entity.Name)
+	require.False(t, entity.Disabled)
+	require.Equal(t, map[string]string{
+		scimMetadataExternalID:  "00u1",
+		scimMetadataDisplayName: "Alice",
+		scimMetadataEmail:       "alice@example.com",
+	}, entity.Metadata)
+
+	// A second user with the same userName is rejected
+	var scimErr scimError
+	status = testSCIMRequest(t, c, logical.UpdateOperation, "scim/v2/Users", map[string]interface{}{
+		"userName": "alice@example.com",
+	}, &scimErr)
+	require.Equal(t, http.StatusConflict, status)
+	require.Equal(t, scimErrUniqueness, scimErr.ScimType)
+
+	// Filter users by userName, which is case-insensitive
+	var list scimListResponse
+	status = testSCIMRequest(t, c, logical.ReadOperation, "scim/v2/Users", map[string]interface{}{
+		"filter": `userName eq "ALICE@example.com"`,
+	}, &list)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 1, list.TotalResults)
+	require.Len(t, list.Resources, 1)
+
+	status = testSCIMRequest(t, c, logical.ReadOperation, "scim/v2/Users", map[string]interface{}{
+		"filter": `externalId eq "unknown"`,
+	}, &list)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 0, list.TotalResults)
+
+	status = testSCIMRequest(t, c, logical.ReadOperation, "scim/v2/Users", map[string]interface{}{
+		"filter": `userName sw "alice"`,
+	}, &scimErr)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, scimErrInvalidFilter, scimErr.ScimType)
+
+	// Deactivate the user the way identity providers do, with the value as a string
+	status = testSCIMRequest(t, c, logical.PatchOperation, "scim/v2/Users/"+user.ID, map[string]interface{}{
+		"schemas": []interface{}{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		"Operations": []interface{}{
+			map[string]interface{}{"op": "Replace", "path": "active", "value": "False"},
+			map[string]interface{}{"op": "replace", "value": map[string]interface{}{"displayName": "Alice A."}},
+		},
+	}, &user)
+	require.Equal(t, http.StatusOK, status)
+	require.False(t, *user.Active)
+	require.Equal(t, "Alice A.", user.DisplayName)
+
+	entity, err = c.identityStore.MemDBEntityByID(user.ID, false)
+	require.NoError(t, err)
+	require.True(t, entity.Disabled)
+
+	// Replacing the user clears attributes that aren't provided
+	var replaced scimUser
+	status = testSCIMRequest(t, c, logical.UpdateOperation, "scim/v2/Users/"+user.ID, map[string]interface{}{
+		"userName": "alice",
+		"active":   true,
+	}, &replaced)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "alice", replaced.UserName)
+	require.True(t, *replaced.Active)
+	require.Empty(t, replaced.ExternalID)
+	require.Empty(t, replaced.Emails)
+
+	// Delete the user
+	status = testSCIMRequest(t, c, logical.DeleteOperation, "scim/v2/Users/"+user.ID, nil, nil)
+	require.Equal(t, http.StatusNoContent, status)
+
+	status = testSCIMRequest(t, c, logical.ReadOperation, "scim/v2/Users/"+user.ID, nil, &scimErr)
+	require.Equal(t, http.StatusNotFound, status)
+}
+
+func TestIdentityStore_SCIMGroups(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	var alice, bob scimUser
+	testSCIMRequest(t, c, logical.UpdateOperation, "scim/v2/Users", map[string]interface{}{"userName": "alice"}, &alice)
+	testSCIMRequest(t, c, logical.UpdateOperation, "scim/v2/Users", map[string]interface{}{"userName": "bob"}, &bob)
+
+	// Create a group with a member
+	var group scimGroup
+	status := testSCIMRequest(t, c, logical.UpdateOperation, "scim/v2/Groups", map[string]interface{}{
+		"displayName": "engineering",
+		"externalId":  "00g1",
+		"members": []interface{}{
+			map[string]interface{}{"value": alice.ID},
+		},
+	}, &group)
+	require.Equal(t, http.StatusCreated, status)
+	require.Len(t, group.Members, 1)
+	require.Equal(t, "alice", group.Members[0].Display)
+
+	// Members must be existing users
+	var scimErr scimError
+	status = testSCIMRequest(t, c, logical.UpdateOperation, "scim/v2/Groups", map[string]interface{}{
+		"displayName": "nonexistent-members",
+		"members": []interface{}{
+			map[string]interface{}{"value": "unknown"},
+		},
+	}, &scimErr)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, scimErrInvalidValue, scimErr.ScimType)
+
+	// Add and remove members
+	status = testSCIMRequest(t, c, logical.PatchOperation, "scim/v2/Groups/"+group.ID, map[string]interface{}{
+		"Operations": []interface{}{
+			map[string]interface{}{
+				"op":    "add",
+				"path":  "members",
+				"value": []interface{}{map[string]interface{}{"value": bob.ID}},
+			},
+			map[string]interface{}{
+				"op":   "remove",
+				"path": `members[value eq "` + alice.ID + `"]`,
+			},
+		},
+	}, &group)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, group.Members, 1)
+	require.Equal(t, bob.ID, group.Members[0].Value)
+
+	identityGroup, err := c.identityStore.MemDBGroupByID(group.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{bob.ID}, identityGroup.MemberEntityIDs)
+
+	// Users list the groups they are a member of
+	var user scimUser
+	testSCIMRequest(t, c, logical.ReadOperation, "scim/v2/Users/"+bob.ID, nil, &user)
+	require.Len(t, user.Groups, 1)
+	require.Equal(t, group.ID, user.Groups[0].Value)
+
+	// Filter groups by displayName
+	var list scimListResponse
+	status = testSCIMRequest(t, c, logical.ReadOperation, "scim/v2/Groups", map[string]interface{}{
+		"filter": `displayName eq "engineering"`,
+	}, &list)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 1, list.TotalResults)
+
+	// Delete the group
+	status = testSCIMRequest(t, c, logical.DeleteOperation, "scim/v2/Groups/"+group.ID, nil, nil)
+	require.Equal(t, http.StatusNoContent, status)
+
+	status = testSCIMRequest(t, c, logical.ReadOperation, "scim/v2/Groups/"+group.ID, nil, &scimErr)
+	require.Equal(t, http.StatusNotFound, status)
+}
+
+func TestIdentityStore_SCIMPagination(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	for _, name := range []string{"a", "b", "c"} {
+		status := testSCIMRequest(t, c, logical.UpdateOperation, "scim/v2/Users", map[string]interface{}{"userName": name}, nil)
+		require.Equal(t, http.StatusCreated, status)
+	}
+
+	var list scimListResponse
+	testSCIMRequest(t, c, logical.ReadOperation, "scim/v2/Users", map[string]interface{}{
+		"startIndex": 2,
+		"count":      5,
+	}, &list)
+	require.Equal(t, 3, list.TotalResults)
+	require.Equal(t, 2, list.StartIndex)
+	require.Equal(t, 2, list.ItemsPerPage)
+
+	testSCIMRequest(t, c, logical.ReadOperation, "scim/v2/Users", map[string]interface{}{
+		"startIndex": 10,
+	}, &list)
+	require.Equal(t, 3, list.TotalResults)
+	require.Empty(t, list.Resources)
+}
@@ -10,6 +10,7 @@ import (
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/vault/helper/groupresolver"
 	"github.com/hashicorp/vault/helper/identity"
 	"github.com/hashicorp/vault/helper/metricsutil"
 	"github.com/hashicorp/vault/helper/namespace"
@@ -72,6 +73,20 @@ type IdentityStore struct {
 	// for an ID token during an authorization code flow.
 	oidcAuthCodeCache *oidcCache
 
+	// oidcDeviceCodeCache stores OIDC device codes and their user codes to be
+	// verified by a user and exchanged for an ID token during a device
+	// authorization flow.
+	oidcDeviceCodeCache *oidcCache
+
+	// groupResolverFactories are the available types of group resolvers
+	groupResolverFactories map[string]groupresolver.Factory
+
+	// groupResolvers are the configured group resolvers keyed by the accessor
+	// of their auth mount. They are loaded lazily and reset to nil whenever
+	// their configuration changes.
+	groupResolvers     map[string]*activeGroupResolver
+	groupResolversLock sync.RWMutex
+
 	// logger is the server logger copied over from core
 	logger log.Logger
 
@@ -144,6 +159,7 @@ var _ GroupUpdater = &Core{}
 type TokenStorer interface {
 	LookupToken(context.Context, string) (*logical.TokenEntry, error)
 	CreateToken(context.Context, *logical.TokenEntry) error
+	RevokeEntityTokens(context.Context, string, bool) (*EntityTokens, error)
 }
 
 var _ TokenStorer = &Core{}
@@ -47,6 +47,7 @@ import (
 	"github.com/hashicorp/vault/sdk/helper/roottoken"
 	"github.com/hashicorp/vault/sdk/helper/wrapping"
 	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault/seal"
 	"github.com/hashicorp/vault/version"
 	"github.com/mitchellh/mapstructure"
 	"golang.org/x/crypto/sha3"
@@ -55,6 +56,10 @@ import (
 const (
 	maxBytes    = 128 * 1024
 	globalScope = "global"
+
+	// maxRenewBatchSize is the maximum number of leases which can be renewed
+	// with a single request to leases/renew-batch
+	maxRenewBatchSize = 100
 )
 
 func systemBackendMemDBSchema() *memdb.DBSchema {
@@ -108,16 +113,24 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 				"replication/dr/reindex",
 				"replication/performance/reindex",
 				"rotate",
+				"sealwrap/config",
+				"sealwrap/rewrap",
+				"wrapping/deliver",
 				"config/cors",
 				"config/auditing/*",
 				"config/ui/headers/*",
+				"config/reload/config",
 				"plugins/catalog/*",
+				"plugins/runtime/status",
+				"health/mounts",
 				"revoke-prefix/*",
 				"revoke-force/*",
 				"leases/revoke-prefix/*",
 				"leases/revoke-force/*",
 				"leases/lookup/*",
+				"storage/raft/compact",
 				"storage/raft/snapshot-auto/config/*",
+				"storage/cache/flush",
 				"leases",
 				"internal/inspect/*",
 			},
@@ -125,6 +138,7 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 			Unauthenticated: []string{
 				"wrapping/lookup",
 				"wrapping/pubkey",
+				"attestation/certificate",
 				"replication/status",
 				"internal/specs/openapi",
 				"internal/ui/mounts",
@@ -159,6 +173,7 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 				"rekey-recovery-key/update",
 				"rekey-recovery-key/verify",
 				"mfa/validate",
+				"mfa/webhook/callback",
 			},
 
 			LocalStorage: []string{
@@ -180,6 +195,8 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 	b.Backend.Paths = append(b.Backend.Paths, b.pluginsCatalogListPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.pluginsCatalogCRUDPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.pluginsReloadPath())
+	b.Backend.Paths = append(b.Backend.Paths, b.pluginsRuntimeStatusPath())
+	b.Backend.Paths = append(b.Backend.Paths, b.healthMountsPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.auditPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.mountPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.authPaths()...)
@@ -187,16 +204,25 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 	b.Backend.Paths = append(b.Backend.Paths, b.leasePaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.policyPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.wrappingPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.wrappingDeliveryPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.toolsPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.capabilitiesPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.internalPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.pprofPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.remountPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.deletedMountsPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.storageCachePaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.metricsPath())
+	b.Backend.Paths = append(b.Backend.Paths, b.metricsRoutesPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.monitorPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.inFlightRequestPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.hostInfoPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.quotasPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.namespacesPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.controlGroupPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.sealWrapPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.attestationPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.managedKeysPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.rootActivityPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.loginMFAPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.experimentPaths()...)
@@ -262,6 +288,22 @@ func (b *SystemBackend) handleConfigReload(ctx context.Context, req *logical.Req
 	switch subsystem {
 	case "license":
 		return handleLicenseReload(b)(ctx, req, data)
+	case "config":
+		result, err := b.Core.ReloadConfig(ctx)
+		if result == nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+
+		resp := &logical.Response{
+			Data: map[string]interface{}{
+				"applied":          result.Applied,
+				"restart_required": result.RestartRequired,
+			},
+		}
+		if err != nil {
+			resp.AddWarning(fmt.Sprintf("Error(s) were encountered during reload: %s", err))
+		}
+		return resp, nil
 	}
 
 	return nil, logical.ErrUnsupportedPath
@@ -542,15 +584,40 @@ func (b *SystemBackend) handlePluginCatalogUpdate(ctx context.Context, _ *logica
 	}
 
 	env := d.Get("env").([]string)
+	runtime := d.Get("runtime").(string)
+
+	var memoryLimit int64
+	if raw := d.Get("memory_limit").(string); raw != "" {
+		limit, err := parseutil.ParseCapacityString(raw)
+		if err != nil {
+			return logical.ErrorResponse("invalid memory_limit: %s", err), nil
+		}
+		memoryLimit = int64(limit)
+	}
+	cpuLimit := d.Get("cpu_limit").(float64)
+	if cpuLimit < 0 {
+		return logical.ErrorResponse("invalid cpu_limit: cannot be negative"), nil
+	}
 
 	sha256Bytes, err := hex.DecodeString(sha256)
 	if err != nil {
 		return logical.ErrorResponse("Could not decode SHA-256 value from Hex"), err
 	}
 
-	err = b.Core.pluginCatalog.Set(ctx, pluginName, pluginType, pluginVersion, parts[0], args, env, sha256Bytes)
+	err = b.Core.pluginCatalog.Set(ctx, pluginutil.SetPluginInput{
+		Name:        pluginName,
+		Type:        pluginType,
+		Version:     pluginVersion,
+		Command:     parts[0],
+		Args:        args,
+		Env:         env,
+		Sha256:      sha256Bytes,
+		Runtime:     runtime,
+		MemoryLimit: memoryLimit,
+		CPULimit:    cpuLimit,
+	})
 	if err != nil {
-		if errors.Is(err, ErrPluginNotFound) || strings.HasPrefix(err.Error(), "plugin version mismatch") {
+		if errors.Is(err, ErrPluginNotFound) || errors.Is(err, ErrPluginBadRuntime) || errors.Is(err, ErrPluginInvalidWASM) || errors.Is(err, ErrPluginBadLimits) || strings.HasPrefix(err.Error(), "plugin version mismatch") {
 			return logical.ErrorResponse(err.Error()), nil
 		}
 		return nil, err
@@ -610,6 +677,16 @@ func (b *SystemBackend) handlePluginCatalogRead(ctx context.Context, _ *logical.
 		"version": plugin.Version,
 	}
 
+	if plugin.Runtime != "" {
+		data["runtime"] = plugin.Runtime
+	}
+	if plugin.MemoryLimit != 0 {
+		data["memory_limit"] = plugin.MemoryLimit
+	}
+	if plugin.CPULimit != 0 {
+		data["cpu_limit"] = plugin.CPULimit
+	}
+
 	if plugin.Builtin {
 		status, _ := b.Core.builtinRegistry.DeprecationStatus(plugin.Name, plugin.Type)
 		data["deprecation_status"] = status.String()
@@ -620,6 +697,32 @@ func (b *SystemBackend) handlePluginCatalogRead(ctx context.Context, _ *logical.
 	}, nil
 }
 
+func (b *SystemBackend) handlePluginRuntimeStatus(ctx context.Context, _ *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"plugins": b.Core.pluginCatalog.PluginRuntimeStatus(),
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleHealthMounts(ctx context.Context, _ *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	mounts := b.Core.MountsHealth(ctx)
+
+	status := HealthStatusOK
+	for _, mount := range mounts {
+		if mount.Status == HealthStatusFailed {
+			status = HealthStatusDegraded
+		}
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"status": status,
+			"mounts": mounts,
+		},
+	}, nil
+}
+
 func (b *SystemBackend) handlePluginCatalogDelete(ctx context.Context, _ *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	pluginName := d.Get("name").(string)
 	if pluginName == "" {
@@ -985,6 +1088,12 @@ func (b *SystemBackend) mountInfo(ctx context.Context, entry *MountEntry) map[st
 	if entry.Table == credentialTableType {
 		entryConfig["token_type"] = entry.Config.TokenType.String()
 	}
+	if entry.Config.DeletionProtection {
+		entryConfig["deletion_protection"] = true
+	}
+	if entry.Config.SoftDeleteRetention > 0 {
+		entryConfig["soft_delete_retention"] = int64(entry.Config.SoftDeleteRetention.Seconds())
+	}
 	if entry.Config.UserLockoutConfig != nil {
 		userLockoutConfig := map[string]interface{}{
 			"user_lockout_counter_reset_duration": int64(entry.Config.UserLockoutConfig.LockoutCounterReset.Seconds()),
@@ -1200,6 +1309,17 @@ func (b *SystemBackend) handleMount(ctx context.Context, req *logical.Request, d
 		config.AllowedManagedKeys = apiConfig.AllowedManagedKeys
 	}
 
+	config.DeletionProtection = apiConfig.DeletionProtection
+	if apiConfig.SoftDeleteRetention != "" {
+		retention, err := parseutil.ParseDurationSecond(apiConfig.SoftDeleteRetention)
+		if err != nil || retention < 0 {
+			return logical.ErrorResponse(fmt.Sprintf(
+					"invalid soft_delete_retention %s", apiConfig.SoftDeleteRetention)),
+				logical.ErrInvalidRequest
+		}
+		config.SoftDeleteRetention = retention
+	}
+
 	// Create the mount entry
 	me := &MountEntry{
 		Table:                 mountTableType,
@@ -1555,6 +1675,97 @@ func (b *SystemBackend) handleAuthTuneRead(ctx context.Context, req *logical.Req
 	return b.handleTuneReadCommon(ctx, "auth/"+path)
 }
 
+// deletedMountInfo returns the information about a soft-deleted mount.
+func deletedMountInfo(deleted *DeletedMountEntry) map[string]interface{} {
+	return map[string]interface{}{
+		"accessor":      deleted.Entry.Accessor,
+		"path":          deleted.Entry.APIPathNoNamespace(),
+		"type":          deleted.Entry.Type,
+		"table":         deleted.Entry.Table,
+		"description":   deleted.Entry.Description,
+		"deletion_time": deleted.DeletionTime.Format(time.RFC3339),
+		"purge_time":    deleted.PurgeTime.Format(time.RFC3339),
+	}
+}
+
+// handleDeletedMountsList lists the soft-deleted mounts by accessor
+func (b *SystemBackend) handleDeletedMountsList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	entries, err := b.Core.listDeletedMounts(ctx)
+	if err != nil {
+		return handleError(err)
+	}
+
+	keys := make([]string, 0, len(entries))
+	keyInfo := make(map[string]interface{}, len(entries))
+	for _, deleted := range entries {
+		keys = append(keys, deleted.Entry.Accessor)
+		keyInfo[deleted.Entry.Accessor] = deletedMountInfo(deleted)
+	}
+
+	return logical.ListResponseWithInfo(keys, keyInfo), nil
+}
+
+// handleDeletedMountRead reads a soft-deleted mount
+func (b *SystemBackend) handleDeletedMountRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	deleted, err := b.Core.getDeletedMount(ctx, data.Get("accessor").(string))
+	if err != nil {
+		return handleError(err)
+	}
+	if deleted == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: deletedMountInfo(deleted),
+	}, nil
+}
+
+// handleDeletedMountPurge purges the retained storage of a soft-deleted
+// mount
+func (b *SystemBackend) handleDeletedMountPurge(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.purgeDeletedMount(ctx, data.Get("accessor").(string)); err != nil {
+		return handleError(err)
+	}
+
+	return nil, nil
+}
+
+// handleDeletedMountRestore mounts again a soft-deleted mount
+func (b *SystemBackend) handleDeletedMountRestore(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	entry, err := b.Core.restoreDeletedMount(ctx, data.Get("accessor").(string), sanitizePath(data.Get("path").(string)))
+	if err != nil {
+		b.Backend.Logger().Error("restore of deleted mount failed", "accessor", data.Get("accessor").(string), "error", err)
+		return handleError(err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"accessor": entry.Accessor,
+			"path":     entry.APIPathNoNamespace(),
+		},
+	}, nil
+}
+
+// handleStorageCacheFlush flushes the entries of the storage cache with the
+// given key prefixes
+func (b *SystemBackend) handleStorageCacheFlush(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	prefixes := data.Get("prefixes").([]string)
+	if len(prefixes) == 0 {
+		return logical.ErrorResponse("at least one prefix must be provided"), logical.ErrInvalidRequest
+	}
+
+	flushed, err := b.Core.FlushStorageCache(ctx, prefixes)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"flushed_entries": flushed,
+		},
+	}, nil
+}
+
 func (b *SystemBackend) handleRemountStatusCheck(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	repState := b.Core.ReplicationState()
 
@@ -1671,6 +1882,14 @@ func (b *SystemBackend) handleTuneReadCommon(ctx context.Context, path string) (
 		resp.Data["plugin_version"] = mountEntry.Version
 	}
 
+	if mountEntry.Config.DeletionProtection {
+		resp.Data["deletion_protection"] = true
+	}
+
+	if mountEntry.Config.SoftDeleteRetention > 0 {
+		resp.Data["soft_delete_retention"] = int64(mountEntry.Config.SoftDeleteRetention.Seconds())
+	}
+
 	return resp, nil
 }
 
@@ -2143,6 +2362,56 @@ func (b *SystemBackend) handleTuneWriteCommon(ctx context.Context, path string,
 		}
 	}
 
+	if rawVal, ok := data.GetOk("deletion_protection"); ok {
+		deletionProtection := rawVal.(bool)
+
+		oldVal := mountEntry.Config.DeletionProtection
+		mountEntry.Config.DeletionProtection = deletionProtection
+
+		// Update the mount table
+		var err error
+		switch {
+		case strings.HasPrefix(path, "auth/"):
+			err = b.Core.persistAuth(ctx, b.Core.auth, &mountEntry.Local)
+		default:
+			err = b.Core.persistMounts(ctx, b.Core.mounts, &mountEntry.Local)
+		}
+		if err != nil {
+			mountEntry.Config.DeletionProtection = oldVal
+			return handleError(err)
+		}
+
+		if b.Core.logger.IsInfo() {
+			b.Core.logger.Info("mount tuning of deletion_protection successful", "path", path, "deletion_protection", deletionProtection)
+		}
+	}
+
+	if rawVal, ok := data.GetOk("soft_delete_retention"); ok {
+		retention, err := parseutil.ParseDurationSecond(rawVal.(string))
+		if err != nil || retention < 0 {
+			return logical.ErrorResponse(fmt.Sprintf("invalid soft_delete_retention %s", rawVal.(string))), logical.ErrInvalidRequest
+		}
+
+		oldVal := mountEntry.Config.SoftDeleteRetention
+		mountEntry.Config.SoftDeleteRetention = retention
+
+		// Update the mount table
+		switch {
+		case strings.HasPrefix(path, "auth/"):
+			err = b.Core.persistAuth(ctx, b.Core.auth, &mountEntry.Local)
+		default:
+			err = b.Core.persistMounts(ctx, b.Core.mounts, &mountEntry.Local)
+		}
+		if err != nil {
+			mountEntry.Config.SoftDeleteRetention = oldVal
+			return handleError(err)
+		}
+
+		if b.Core.logger.IsInfo() {
+			b.Core.logger.Info("mount tuning of soft_delete_retention successful", "path", path, "soft_delete_retention", retention)
+		}
+	}
+
 	var err error
 	var resp *logical.Response
 	var options map[string]string
@@ -2359,6 +2628,61 @@ func (b *SystemBackend) handleRenew(ctx context.Context, req *logical.Request, d
 	return resp, err
 }
 
+// handleRenewBatch is used to renew several leases with the given LeaseIDs
+func (b *SystemBackend) handleRenewBatch(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	leaseIDs := data.Get("lease_ids").([]string)
+	if len(leaseIDs) == 0 {
+		return logical.ErrorResponse("lease_ids must be specified"),
+			logical.ErrInvalidRequest
+	}
+	if len(leaseIDs) > maxRenewBatchSize {
+		return logical.ErrorResponse("at most %d leases can be renewed at once", maxRenewBatchSize),
+			logical.ErrInvalidRequest
+	}
+	increment := time.Duration(data.Get("increment").(int)) * time.Second
+
+	results := make([]map[string]interface{}, 0, len(leaseIDs))
+	for _, leaseID := range leaseIDs {
+		result := map[string]interface{}{
+			"lease_id": leaseID,
+		}
+		results = append(results, result)
+
+		resp, err := b.Core.expiration.Renew(ctx, leaseID, increment)
+		switch {
+		case errors.Is(err, logical.ErrReadOnly):
+			// None of the leases can be renewed on a read-only node, so the
+			// whole batch fails the same way a single renewal does
+			b.Backend.Logger().Error("lease renewal failed", "lease_id", leaseID, "error", err)
+			return handleErrorNoReadOnlyForward(err)
+		case err != nil:
+			b.Backend.Logger().Error("lease renewal failed", "lease_id", leaseID, "error", err)
+			result["error"] = err.Error()
+			continue
+		case resp == nil || resp.Secret == nil:
+			// The backend did not return a secret, which means the lease was
+			// not extended
+			result["error"] = "lease was not renewed"
+			continue
+		case resp.IsError():
+			result["error"] = resp.Error().Error()
+			continue
+		}
+
+		result["lease_duration"] = int64(resp.Secret.TTL.Seconds())
+		result["renewable"] = resp.Secret.Renewable
+		if len(resp.Warnings) > 0 {
+			result["warnings"] = resp.Warnings
+		}
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"leases": results,
+		},
+	}, nil
+}
+
 // handleRevoke is used to revoke a given LeaseID
 func (b *SystemBackend) handleRevoke(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	// Get all the options
@@ -2676,6 +3000,17 @@ func (b *SystemBackend) handleEnableAuth(ctx context.Context, req *logical.Reque
 		config.AllowedManagedKeys = apiConfig.AllowedManagedKeys
 	}
 
+	config.DeletionProtection = apiConfig.DeletionProtection
+	if apiConfig.SoftDeleteRetention != "" {
+		retention, err := parseutil.ParseDurationSecond(apiConfig.SoftDeleteRetention)
+		if err != nil || retention < 0 {
+			return logical.ErrorResponse(fmt.Sprintf(
+					"invalid soft_delete_retention %s", apiConfig.SoftDeleteRetention)),
+				logical.ErrInvalidRequest
+		}
+		config.SoftDeleteRetention = retention
+	}
+
 	// Create the mount entry
 	me := &MountEntry{
 		Table:                 credentialTableType,
@@ -2852,7 +3187,7 @@ func (b *SystemBackend) handlePoliciesList(policyType PolicyType) framework.Oper
 			}
 			return resp, nil
 
-		case PolicyTypeRGP:
+		case PolicyTypeRGP, PolicyTypeRego:
 			return logical.ListResponse(policies), nil
 
 		case PolicyTypeEGP:
@@ -2958,6 +3293,13 @@ func (b *SystemBackend) handlePoliciesSet(policyType PolicyType) framework.Opera
 
 		case PolicyTypeRGP, PolicyTypeEGP:
 
+		case PolicyTypeRego:
+			module, err := parseRegoPolicy(policy.Raw)
+			if err != nil {
+				return logical.ErrorResponse("failed to parse policy: %s", err), nil
+			}
+			policy.regoModule = module
+
 		default:
 			return logical.ErrorResponse("unknown policy type"), nil
 		}
@@ -3228,10 +3570,93 @@ func (b *SystemBackend) handleAuditHash(ctx context.Context, req *logical.Reques
 		return logical.ErrorResponse(err.Error()), nil
 	}
 
-	return &logical.Response{
+	resp := &logical.Response{
 		Data: map[string]interface{}{
 			"hash": hash,
 		},
+	}
+
+	// While the previous salt of the device overlaps with its current one,
+	// both hashes are returned
+	previousHash, err := b.Core.auditBroker.GetPreviousHash(ctx, path, input)
+	if err != nil {
+		return nil, err
+	}
+	if previousHash != "" {
+		resp.Data["previous_hash"] = previousHash
+	}
+
+	return resp, nil
+}
+
+// handleAuditHashRotate rotates the salt an audit device uses to compute
+// hashes.
+func (b *SystemBackend) handleAuditHashRotate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	path := data.Get("path").(string)
+	if path == "" {
+		return logical.ErrorResponse("the \"path\" parameter is empty"), nil
+	}
+	path = sanitizePath(path)
+
+	overlap := time.Duration(data.Get("overlap").(int)) * time.Second
+	if overlap < 0 {
+		return logical.ErrorResponse("overlap cannot be negative"), nil
+	}
+
+	rotation, err := b.Core.auditBroker.RotateSalt(ctx, path, overlap)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	b.Core.logger.Info("rotated audit device salt", "path", path, "overlap_end", rotation.OverlapEnd)
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"rotated_at":  rotation.RotatedAt,
+			"overlap_end": rotation.OverlapEnd,
+		},
+	}, nil
+}
+
+// handleAuditHashRewrap computes the hashes of the inputs with both the
+// previous and the current salt of an audit device, so that audit pipelines
+// can translate the hashes they recorded before a rotation.
+func (b *SystemBackend) handleAuditHashRewrap(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	path := data.Get("path").(string)
+	if path == "" {
+		return logical.ErrorResponse("the \"path\" parameter is empty"), nil
+	}
+	path = sanitizePath(path)
+
+	inputs := data.Get("input").([]string)
+	if len(inputs) == 0 {
+		return logical.ErrorResponse("the \"input\" parameter is empty"), nil
+	}
+
+	hashes := make([]map[string]interface{}, 0, len(inputs))
+	for _, input := range inputs {
+		hash, err := b.Core.auditBroker.GetHash(ctx, path, input)
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+		previousHash, err := b.Core.auditBroker.GetPreviousHash(ctx, path, input)
+		if err != nil {
+			return nil, err
+		}
+		if previousHash == "" {
+			return logical.ErrorResponse("the audit device has no previous salt overlapping with its current one"), nil
+		}
+
+		hashes = append(hashes, map[string]interface{}{
+			"previous_hash": previousHash,
+			"hash":          hash,
+		})
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"hashes": hashes,
+		},
 	}, nil
 }
 
@@ -3410,6 +3835,24 @@ func (b *SystemBackend) handleKeyStatus(ctx context.Context, req *logical.Reques
 			"encryptions":  info.Encryptions,
 		},
 	}
+
+	status, err := b.Core.barrierRotationStatus()
+	if err != nil {
+		return nil, err
+	}
+	if status.Enabled {
+		resp.Data["remaining_operations"] = status.RemainingOperations
+		if !status.NextRotationTime.IsZero() {
+			resp.Data["next_rotation_time"] = status.NextRotationTime.Format(time.RFC3339Nano)
+		}
+	}
+	if last := status.LastAutoRotation; !last.Time.IsZero() {
+		resp.Data["last_auto_rotation_time"] = last.Time.Format(time.RFC3339Nano)
+		resp.Data["last_auto_rotation_reason"] = last.Reason
+		if last.Err != nil {
+			resp.Data["last_auto_rotation_error"] = last.Err.Error()
+		}
+	}
 	return resp, nil
 }
 
@@ -3474,7 +3917,7 @@ func (b *SystemBackend) handleKeyRotationConfigUpdate(ctx context.Context, req *
 	}
 
 	// Store the rotation config
-	b.Core.barrier.SetRotationConfig(ctx, rotConfig)
+	err = b.Core.barrier.SetRotationConfig(ctx, rotConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -3584,6 +4027,12 @@ func (b *SystemBackend) handleWrappingUnwrap(ctx context.Context, req *logical.R
 		return respErr, err
 	}
 
+	return b.unwrappedResponse(response)
+}
+
+// unwrappedResponse turns the raw HTTP response stored in the cubbyhole of a
+// wrapping token back into the response it was.
+func (b *SystemBackend) unwrappedResponse(response string) (*logical.Response, error) {
 	resp := &logical.Response{
 		Data: map[string]interface{}{},
 	}
@@ -3597,7 +4046,7 @@ func (b *SystemBackend) handleWrappingUnwrap(ctx context.Context, req *logical.R
 	// However there is a sad separate case: if the original response was using
 	// bare values we need to use those or else what comes back is garbled.
 	httpResp := &logical.HTTPResponse{}
-	err = jsonutil.DecodeJSON([]byte(response), httpResp)
+	err := jsonutil.DecodeJSON([]byte(response), httpResp)
 	if err != nil {
 		return nil, fmt.Errorf("error decoding wrapped response: %w", err)
 	}
@@ -3699,6 +4148,15 @@ func (b *SystemBackend) handleMetrics(ctx context.Context, req *logical.Request,
 	return b.Core.metricsHelper.ResponseForFormat(format), nil
 }
 
+func (b *SystemBackend) handleMetricsRoutes(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"routes":     b.Core.router.routeMetrics.summary(),
+			"max_mounts": b.Core.router.routeMetrics.maxMounts,
+		},
+	}, nil
+}
+
 func (b *SystemBackend) handleInFlightRequestData(_ context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	resp := &logical.Response{
 		Data: map[string]interface{}{
@@ -4706,6 +5164,8 @@ type SealStatusResponse struct {
 	HCPLinkStatus     string   `json:"hcp_link_status,omitempty"`
 	HCPLinkResourceID string   `json:"hcp_link_resource_ID,omitempty"`
 	Warnings          []string `json:"warnings,omitempty"`
+
+	Seals []seal.SealBackendStatus `json:"seals,omitempty"`
 }
 
 // getStatusWarnings exposes potentially dangerous overrides in the status response
@@ -4752,6 +5212,10 @@ func (core *Core) GetSealStatus(ctx context.Context) (*SealStatusResponse, error
 			BuildDate:    version.BuildDate,
 		}
 
+		if multi, ok := core.SealAccess().GetAccess().(*seal.MultiAccess); ok {
+			s.Seals = multi.Status(ctx)
+		}
+
 		if resourceIDonHCP != "" {
 			s.HCPLinkStatus = hcpLinkStatus
 			s.HCPLinkResourceID = resourceIDonHCP
@@ -4794,6 +5258,10 @@ func (core *Core) GetSealStatus(ctx context.Context) (*SealStatusResponse, error
 		Warnings:     core.getStatusWarnings(),
 	}
 
+	if multi, ok := core.SealAccess().GetAccess().(*seal.MultiAccess); ok {
+		s.Seals = multi.Status(ctx)
+	}
+
 	if resourceIDonHCP != "" {
 		s.HCPLinkStatus = hcpLinkStatus
 		s.HCPLinkResourceID = resourceIDonHCP
@@ -5219,6 +5687,10 @@ The path responds to the following HTTP methods.
         Sets the license for the server
 	`,
 	},
+	"config/reload": {
+		"The subsystem to reload, either license or config.",
+		"",
+	},
 	"config/cors": {
 		"Configures or returns the current configuration of CORS settings.",
 		`
@@ -5428,6 +5900,74 @@ in the plugin catalog.`,
 		`The user lockout configuration to pass into the backend. Should be a json object with string keys and values.`,
 	},
 
+	"tune_deletion_protection": {
+		`Whether disabling the mount is blocked. It must be cleared before the mount can be disabled.`,
+	},
+
+	"tune_soft_delete_retention": {
+		`How long the storage of the mount is retained once it is disabled, so that it can be restored. Zero, the default, deletes the storage immediately.`,
+	},
+
+	"deleted-mounts": {
+		"List the disabled mounts whose storage is retained.",
+		`
+This path responds to the following HTTP methods.
+
+    LIST /sys/deleted-mounts
+        Lists the disabled mounts whose storage is retained until the end of
+        their soft-delete retention window, by accessor.
+		`,
+	},
+
+	"deleted-mounts-accessor": {
+		"Read or purge a disabled mount whose storage is retained.",
+		`
+This path responds to the following HTTP methods.
+
+    GET /sys/deleted-mounts/<accessor>
+        Reads the disabled mount with the given accessor.
+
+    DELETE /sys/deleted-mounts/<accessor>
+        Purges the retained storage of the disabled mount with the given
+        accessor, before the end of its retention window.
+		`,
+	},
+
+	"deleted-mounts-restore": {
+		"Restore a disabled mount with its retained storage.",
+		`
+This path responds to the following HTTP methods.
+
+    POST /sys/deleted-mounts/<accessor>/restore
+        Mounts again the disabled mount with the given accessor, with its
+        retained storage. The leases and tokens issued by the mount were
+        revoked when it was disabled.
+		`,
+	},
+
+	"deleted_mount_accessor": {
+		`The accessor of the disabled mount.`,
+	},
+
+	"deleted_mount_restore_path": {
+		`The path to restore the mount at. Defaults to the path the mount was disabled at.`,
+	},
+
+	"storage-cache-flush": {
+		"Flush the entries of the storage cache with the given key prefixes.",
+		`
+This path responds to the following HTTP methods.
+
+    POST /sys/storage/cache/flush
+        Flushes the entries of the storage cache of the node with the given
+        key prefixes, so that they are read again from the storage backend.
+		`,
+	},
+
+	"storage_cache_flush_prefixes": {
+		`The storage key prefixes of the entries to flush, e.g. "logical/<mount UUID>/".`,
+	},
+
 	"remount": {
 		"Move the mount point of an already-mounted backend, within or across namespaces",
 		`
@@ -5503,6 +6043,23 @@ lease and to prevent an automatic revocation.
 		"",
 	},
 
+	"renew-batch": {
+		"Renew several leases on secrets at once",
+		`
+This endpoint renews up to 100 leases in a single request, which is useful for
+clients that hold many leases and renew them together. Every lease is renewed
+and persisted independently, the same way as by the renew endpoint, so the
+batch is not atomic: the leases renewed before a failure stay renewed. The
+response contains the result of the renewal of each lease, including any
+error, in the order of the request.
+		`,
+	},
+
+	"lease_ids": {
+		"The lease identifiers to renew.",
+		"",
+	},
+
 	"increment": {
 		"The desired increment in seconds to the lease",
 		"",
@@ -5645,6 +6202,40 @@ or delete a policy.
 		`,
 	},
 
+	"rego-policy-list": {
+		`List the configured Rego policies.`,
+		`
+This path responds to the following HTTP methods.
+
+    LIST /
+        List the names of the configured Rego policies.
+
+    GET /<name>
+        Retrieve the module of the named Rego policy.
+
+    PUT /<name>
+        Add or update a Rego policy.
+
+    DELETE /<name>
+        Delete the Rego policy with the given name.
+		`,
+	},
+
+	"rego-policy": {
+		`Read, Modify, or Delete a Rego policy.`,
+		`
+Rego policies are attached to tokens like access control policies. They can
+only further restrict the requests that the access control policies of the
+token allow: their "deny" rules deny requests, and their "allow" rules, when
+defined, must be true for requests to be allowed.
+		`,
+	},
+
+	"rego-policy-module": {
+		`The module of the Rego policy.`,
+		"",
+	},
+
 	"policy-name": {
 		`The name of the policy. Example: "ops"`,
 		"",
@@ -5675,6 +6266,26 @@ or delete a policy.
 		"",
 	},
 
+	"audit-hash-rotate": {
+		"Rotate the salt the given audit backend computes hashes with.",
+		`
+The previous salt of the audit backend keeps being used to compute hashes for
+the overlap window: hashes requested through audit-hash are returned computed
+with both the previous and the new salt, and audit-hash/rewrap translates the
+hashes of values from the previous salt to the new one. Audit entries are
+hashed with the new salt as soon as the rotation completes.
+`,
+	},
+
+	"audit-hash-rewrap": {
+		"Compute the hashes of the given strings with both the previous and current salt of the given audit backend.",
+		`
+This is only possible during the overlap window following a rotation of the
+salt of the audit backend. It lets audit pipelines translate the hashes they
+recorded with the previous salt into hashes computed with the current one.
+`,
+	},
+
 	"audit-table": {
 		"List the currently enabled audit backends.",
 		`
@@ -5797,6 +6408,41 @@ it.`,
 		`Round trips the given input data into a response-wrapped token.`,
 	},
 
+	"wrapping-deliver": {
+		"Response-wraps an arbitrary JSON object for an entity.",
+		`Response-wraps the given data and delivers it to the entity, rather than
+returning the wrapping token. Tokens of the entity list the responses delivered
+to it with sys/wrapping/inbox, and unwrap them once by their accessor.`,
+	},
+
+	"wrapping-inbox": {
+		"Lists and unwraps the responses delivered to the entity of the token.",
+		`Lists the responses delivered to the entity of the token that have not
+expired, or unwraps one of them by the accessor of its wrapping token. A
+delivered response can only be unwrapped once.`,
+	},
+
+	"attestation-certificate": {
+		"Returns the certificate of the attestation key of the cluster.",
+		`Returns the PEM encoded certificate of the key the cluster signs the
+attestation statements of plugins with, such as those of transit keys. The key
+is generated on first unseal and kept for the life of the cluster.`,
+	},
+
+	"managed-keys": {
+		"Configures the managed keys of the namespace.",
+		`Managed keys are keys held by an external KMS or HSM, such as AWS KMS,
+Azure Key Vault, GCP Cloud KMS or a PKCS#11 token. Vault only stores how to
+reach them, and mounts allowed to use a key sign through the API of the KMS so
+the private key never enters Vault.`,
+	},
+
+	"managed-keys-test-sign": {
+		"Tests a managed key by signing random data with it.",
+		`Signs random data with the managed key through its KMS and verifies the
+signature with its public key, validating the configuration of the key.`,
+	},
+
 	"wrappubkey": {
 		"Returns pubkeys used in some wrapping formats.",
 		"Returns pubkeys used in some wrapping formats.",
@@ -5895,6 +6541,44 @@ Each entry is of the form "key=value".`,
 		"The semantic version of the plugin to use.",
 		"",
 	},
+	"plugin-catalog_runtime": {
+		`The runtime the plugin is run with. Leave empty for plugin
+executables, or set to "wasm" for WebAssembly modules run in-process.`,
+		"",
+	},
+	"plugin-catalog_memory-limit": {
+		`The maximum memory the plugin processes may use, in bytes or with a unit
+suffix such as "512MiB". Requires plugin_cgroup_parent to be configured.`,
+		"",
+	},
+	"plugin-catalog_cpu-limit": {
+		`The maximum number of CPUs the plugin processes may use, which may be
+fractional. Requires plugin_cgroup_parent to be configured.`,
+		"",
+	},
+	"plugin-runtime-status": {
+		"Report the restarts and resource usage of the running plugins.",
+		`
+This path responds to the following HTTP methods.
+
+    GET /
+        Returns the starts, restarts, crashes and crash-loop backoff of the
+        external plugins run since the node was unsealed, along with the
+        health and resource usage of their processes.
+		`,
+	},
+	"health-mounts": {
+		"Run the health checks of the mounted backends.",
+		`
+This path responds to the following HTTP methods.
+
+    GET /
+        Runs the health checks of the secrets engines and auth methods that
+        implement one, concurrently and under an overall deadline, and returns
+        their results along with any error. The status is "degraded" if any
+        of the checks failed.
+		`,
+	},
 	"leases": {
 		`View or list lease metadata.`,
 		`
@@ -5977,6 +6661,16 @@ This path responds to the following HTTP methods.
 		"Export the metrics aggregated for telemetry purpose.",
 		"",
 	},
+	"metrics-routes": {
+		"Report the request latency of each mount.",
+		`
+The request count, error count and latency percentiles of the requests
+handled by this node since it started, by mount and operation. The
+percentiles are the upper bounds of the latency histogram buckets they fall
+in. Requests to the mounts over the telemetry route_metrics_max_mounts limit
+are reported under the "other" mount.
+		`,
+	},
 	"in-flight-req": {
 		"reports in-flight requests",
 		`
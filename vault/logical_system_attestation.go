@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// attestationPaths returns paths that publish the certificate verifying the
+// attestation statements signed by the cluster
+func (b *SystemBackend) attestationPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "attestation/certificate$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "attestation",
+				OperationVerb:   "read",
+				OperationSuffix: "certificate",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleAttestationCertificateRead,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"certificate": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["attestation-certificate"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["attestation-certificate"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleAttestationCertificateRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cert := b.Core.attestationCert
+	if cert == nil {
+		return nil, errNoAttestationKey
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"certificate": string(pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: cert.Raw,
+			})),
+		},
+	}, nil
+}
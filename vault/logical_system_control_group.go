@@ -0,0 +1,455 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// controlGroupPaths returns paths that manage control group requests
+func (b *SystemBackend) controlGroupPaths() []*framework.Path {
+	accessorFields := map[string]*framework.FieldSchema{
+		"accessor": {
+			Type:        framework.TypeString,
+			Description: "The accessor of the control group wrapping token.",
+		},
+	}
+
+	return []*framework.Path{
+		{
+			Pattern: "control-group/authorize$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "control-group",
+				OperationVerb:   "authorize",
+			},
+
+			Fields: accessorFields,
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleControlGroupAuthorize,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"approved": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+							},
+						}},
+					},
+					Summary: "Authorize a control group request.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(controlGroupHelp["authorize"][0]),
+			HelpDescription: strings.TrimSpace(controlGroupHelp["authorize"][1]),
+		},
+		{
+			Pattern: "control-group/deny$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "control-group",
+				OperationVerb:   "deny",
+			},
+
+			Fields: accessorFields,
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleControlGroupDeny,
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: http.StatusText(http.StatusNoContent),
+						}},
+					},
+					Summary: "Deny a control group request.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(controlGroupHelp["deny"][0]),
+			HelpDescription: strings.TrimSpace(controlGroupHelp["deny"][1]),
+		},
+		{
+			Pattern: "control-group/request/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "control-group",
+			},
+
+			Fields: accessorFields,
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleControlGroupRequestRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "read",
+						OperationSuffix: "request-status",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"approved": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+								"denied": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+								"request_path": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"request_operation": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"request_entity": {
+									Type:     framework.TypeMap,
+									Required: true,
+								},
+								"authorizations": {
+									Type:     framework.TypeSlice,
+									Required: true,
+								},
+								"request_time": {
+									Type:     framework.TypeTime,
+									Required: true,
+								},
+								"expire_time": {
+									Type:     framework.TypeTime,
+									Required: true,
+								},
+							},
+						}},
+					},
+					Summary: "Check the status of a control group request.",
+				},
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleControlGroupRequestList,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "list",
+						OperationSuffix: "requests",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+								"key_info": {
+									Type:     framework.TypeMap,
+									Required: true,
+								},
+							},
+						}},
+					},
+					Summary: "List the pending control group requests.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(controlGroupHelp["request"][0]),
+			HelpDescription: strings.TrimSpace(controlGroupHelp["request"][1]),
+		},
+		{
+			Pattern: "config/control-group$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "control-group",
+				OperationSuffix: "configuration",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"max_ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "The maximum TTL of control group requests.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleControlGroupConfigRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"max_ttl": {
+									Type:     framework.TypeDurationSecond,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleControlGroupConfigUpdate,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "configure",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: http.StatusText(http.StatusNoContent),
+						}},
+					},
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleControlGroupConfigDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "delete",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: http.StatusText(http.StatusNoContent),
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(controlGroupHelp["config"][0]),
+			HelpDescription: strings.TrimSpace(controlGroupHelp["config"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleControlGroupAuthorize(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	accessor := d.Get("accessor").(string)
+	if accessor == "" {
+		return logical.ErrorResponse("missing accessor"), logical.ErrInvalidRequest
+	}
+
+	b.Core.controlGroupLock.Lock()
+	defer b.Core.controlGroupLock.Unlock()
+
+	cgReq, err := b.Core.loadControlGroupRequest(ctx, accessor)
+	if err != nil {
+		return nil, err
+	}
+	if cgReq == nil {
+		return logical.ErrorResponse("control group request not found"), logical.ErrInvalidRequest
+	}
+	if cgReq.Denied {
+		return logical.ErrorResponse("control group request was denied"), logical.ErrInvalidRequest
+	}
+
+	if err := b.Core.checkControlGroupApprover(cgReq, req.EntityID); err != nil {
+		return nil, err
+	}
+
+	if !cgReq.authorized(req.EntityID) {
+		cgReq.Authorizations = append(cgReq.Authorizations, &controlGroupAuthorization{
+			EntityID: req.EntityID,
+			Time:     time.Now(),
+		})
+		cgReq.Approved, err = b.Core.controlGroupApproved(cgReq)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.Core.storeControlGroupRequest(ctx, cgReq); err != nil {
+			return nil, err
+		}
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"approved": cgReq.Approved,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleControlGroupDeny(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	accessor := d.Get("accessor").(string)
+	if accessor == "" {
+		return logical.ErrorResponse("missing accessor"), logical.ErrInvalidRequest
+	}
+
+	b.Core.controlGroupLock.Lock()
+	defer b.Core.controlGroupLock.Unlock()
+
+	cgReq, err := b.Core.loadControlGroupRequest(ctx, accessor)
+	if err != nil {
+		return nil, err
+	}
+	if cgReq == nil {
+		return logical.ErrorResponse("control group request not found"), logical.ErrInvalidRequest
+	}
+	if cgReq.Denied {
+		return nil, nil
+	}
+
+	if err := b.Core.checkControlGroupApprover(cgReq, req.EntityID); err != nil {
+		return nil, err
+	}
+
+	// The denied request is kept until it expires so that the requester can
+	// check its status, but it can't be run anymore.
+	cgReq.Approved = false
+	cgReq.Denied = true
+	if err := b.Core.storeControlGroupRequest(ctx, cgReq); err != nil {
+		return nil, err
+	}
+	if err := b.Core.revokeControlGroupToken(ctx, accessor); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *SystemBackend) handleControlGroupRequestRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	accessor := d.Get("accessor").(string)
+	if accessor == "" {
+		return logical.ErrorResponse("missing accessor"), logical.ErrInvalidRequest
+	}
+
+	b.Core.controlGroupLock.Lock()
+	defer b.Core.controlGroupLock.Unlock()
+
+	cgReq, err := b.Core.loadControlGroupRequest(ctx, accessor)
+	if err != nil {
+		return nil, err
+	}
+	if cgReq == nil {
+		return logical.ErrorResponse("control group request not found"), logical.ErrInvalidRequest
+	}
+
+	authorizations := make([]map[string]interface{}, 0, len(cgReq.Authorizations))
+	for _, authz := range cgReq.Authorizations {
+		entity := b.Core.controlGroupEntityInfo(authz.EntityID)
+		authorizations = append(authorizations, map[string]interface{}{
+			"entity_id":   entity["id"],
+			"entity_name": entity["name"],
+		})
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"approved":          cgReq.Approved,
+			"denied":            cgReq.Denied,
+			"request_path":      cgReq.Path,
+			"request_operation": string(cgReq.Operation),
+			"request_entity":    b.Core.controlGroupEntityInfo(cgReq.EntityID),
+			"authorizations":    authorizations,
+			"request_time":      cgReq.RequestTime,
+			"expire_time":       cgReq.ExpireTime,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleControlGroupRequestList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.Core.controlGroupLock.Lock()
+	defer b.Core.controlGroupLock.Unlock()
+
+	accessors, err := b.Core.controlGroupView().List(ctx, controlGroupRequestPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	keyInfo := make(map[string]interface{})
+	for _, accessor := range accessors {
+		cgReq, err := b.Core.loadControlGroupRequest(ctx, accessor)
+		if err != nil {
+			return nil, err
+		}
+		// Only pending requests are listed
+		if cgReq == nil || cgReq.Approved || cgReq.Denied {
+			continue
+		}
+
+		keys = append(keys, accessor)
+		keyInfo[accessor] = map[string]interface{}{
+			"request_path":      cgReq.Path,
+			"request_operation": string(cgReq.Operation),
+			"request_entity_id": cgReq.EntityID,
+			"authorizations":    len(cgReq.Authorizations),
+			"request_time":      cgReq.RequestTime,
+			"expire_time":       cgReq.ExpireTime,
+		}
+	}
+
+	return logical.ListResponseWithInfo(keys, keyInfo), nil
+}
+
+func (b *SystemBackend) handleControlGroupConfigRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.Core.controlGroupConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"max_ttl": int64(config.MaxTTL.Seconds()),
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleControlGroupConfigUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.Core.controlGroupConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if maxTTL, ok := d.GetOk("max_ttl"); ok {
+		config.MaxTTL = time.Duration(maxTTL.(int)) * time.Second
+	}
+	if config.MaxTTL < 0 {
+		return logical.ErrorResponse("max_ttl must not be negative"), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(controlGroupConfigPath, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Core.controlGroupView().Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *SystemBackend) handleControlGroupConfigDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.controlGroupView().Delete(ctx, controlGroupConfigPath); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+var controlGroupHelp = map[string][2]string{
+	"authorize": {
+		"Authorize a control group request.",
+		`The entity of the token must be a member of an identity group of a factor
+of the control group, and must not be the requester. The request is approved
+once every factor got the number of authorizations it requires.`,
+	},
+	"deny": {
+		"Deny a control group request.",
+		`The control group token of a denied request is revoked, so the request can't
+be run anymore. Denying requires the same group membership as authorizing.`,
+	},
+	"request": {
+		"Check the status of a control group request, or list the pending ones.",
+		`Requests on paths under a control group aren't run right away. Their
+response is a wrapping token instead, which runs the request when it is
+unwrapped after the control group approved it. Requests that weren't approved
+before the TTL of the token expire.`,
+	},
+	"config": {
+		"Configure control groups.",
+		`The max_ttl caps the TTL of control group requests. It is also the TTL of
+requests on paths whose policy doesn't set one.`,
+	},
+}
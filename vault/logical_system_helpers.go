@@ -5,7 +5,6 @@ package vault
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -36,8 +35,8 @@ var (
 	addSentinelPolicyData     = func(map[string]interface{}, *Policy) {}
 	inputSentinelPolicyData   = func(*framework.FieldData, *Policy) *logical.Response { return nil }
 
-	controlGroupUnwrap = func(context.Context, *SystemBackend, string, bool) (string, error) {
-		return "", errors.New("control groups unavailable")
+	controlGroupUnwrap = func(ctx context.Context, b *SystemBackend, token string, _ bool) (string, error) {
+		return b.Core.controlGroupRun(ctx, token)
 	}
 
 	pathInternalUINamespacesRead = func(b *SystemBackend) framework.OperationFunc {
@@ -61,7 +60,21 @@ var (
 				return nil, logical.ErrPermissionDenied
 			}
 
-			return logical.ListResponse([]string{""}), nil
+			ns, err := namespace.FromContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			// List the namespaces within the namespace of the request,
+			// relatively to it
+			var keys []string
+			for _, child := range b.Core.collectNamespaces() {
+				if child.ID != ns.ID && child.HasParent(ns) {
+					keys = append(keys, strings.TrimPrefix(child.Path, ns.Path))
+				}
+			}
+
+			return logical.ListResponse(keys), nil
 		}
 	}
 
@@ -111,10 +124,8 @@ var (
 
 		// namespaces paths
 		paths = append(paths, buildEnterpriseOnlyPaths(map[string]enterprisePathStub{
-			"namespaces/?$": {operations: []logical.Operation{logical.ListOperation}},
 			"namespaces/api-lock/lock" + framework.OptionalParamRegex("path"):   {parameters: []string{"path"}, operations: []logical.Operation{logical.UpdateOperation}},
 			"namespaces/api-lock/unlock" + framework.OptionalParamRegex("path"): {parameters: []string{"path"}, operations: []logical.Operation{logical.UpdateOperation}},
-			"namespaces/(?P<path>.+?)": {parameters: []string{"path"}, operations: []logical.Operation{logical.DeleteOperation, logical.PatchOperation, logical.ReadOperation, logical.UpdateOperation}},
 		})...)
 
 		// replication paths
@@ -176,11 +187,6 @@ var (
 			},
 		})
 
-		// seal paths
-		paths = append(paths, buildEnterpriseOnlyPaths(map[string]enterprisePathStub{
-			"sealwrap/rewrap": {operations: []logical.Operation{logical.ReadOperation, logical.UpdateOperation}},
-		})...)
-
 		// mfa paths
 		paths = append(paths, buildEnterpriseOnlyPaths(map[string]enterprisePathStub{
 			"mfa/method/?": {operations: []logical.Operation{logical.ListOperation}},
@@ -193,13 +199,6 @@ var (
 			"mfa/method/pingid/" + framework.GenericNameRegex("name"):                    {parameters: []string{"name"}, operations: []logical.Operation{logical.DeleteOperation, logical.ReadOperation, logical.UpdateOperation}},
 		})...)
 
-		// control-group paths
-		paths = append(paths, buildEnterpriseOnlyPaths(map[string]enterprisePathStub{
-			"control-group/authorize": {operations: []logical.Operation{logical.UpdateOperation}},
-			"control-group/request":   {operations: []logical.Operation{logical.UpdateOperation}},
-			"config/control-group":    {operations: []logical.Operation{logical.DeleteOperation, logical.ReadOperation, logical.UpdateOperation}},
-		})...)
-
 		// sentinel paths
 		paths = append(paths, buildEnterpriseOnlyPaths(map[string]enterprisePathStub{
 			"policies/rgp/?$":           {operations: []logical.Operation{logical.ListOperation}},
@@ -219,19 +218,6 @@ var (
 			"quotas/lease-count/" + framework.GenericNameRegex("name"): {parameters: []string{"name"}, operations: []logical.Operation{logical.DeleteOperation, logical.ReadOperation, logical.UpdateOperation}},
 		})...)
 
-		// raft auto-snapshot paths
-		paths = append(paths, buildEnterpriseOnlyPaths(map[string]enterprisePathStub{
-			"storage/raft/snapshot-auto/config/":                                      {operations: []logical.Operation{logical.ListOperation}},
-			"storage/raft/snapshot-auto/config/" + framework.GenericNameRegex("name"): {parameters: []string{"name"}, operations: []logical.Operation{logical.DeleteOperation, logical.ReadOperation, logical.UpdateOperation}},
-			"storage/raft/snapshot-auto/status/" + framework.GenericNameRegex("name"): {parameters: []string{"name"}, operations: []logical.Operation{logical.ReadOperation}},
-		})...)
-
-		paths = append(paths, buildEnterpriseOnlyPaths(map[string]enterprisePathStub{
-			"managed-keys/" + framework.GenericNameRegex("type") + "/?":                                                    {parameters: []string{"type"}, operations: []logical.Operation{logical.ListOperation}},
-			"managed-keys/" + framework.GenericNameRegex("type") + "/" + framework.GenericNameRegex("name"):                {parameters: []string{"type", "name"}, operations: []logical.Operation{logical.CreateOperation, logical.DeleteOperation, logical.ReadOperation, logical.UpdateOperation}},
-			"managed-keys/" + framework.GenericNameRegex("type") + "/" + framework.GenericNameRegex("name") + "/test/sign": {parameters: []string{"type", "name"}, operations: []logical.Operation{logical.CreateOperation, logical.UpdateOperation}},
-		})...)
-
 		return paths
 	}
 	handleGlobalPluginReload = func(context.Context, *Core, string, string, []string) error {
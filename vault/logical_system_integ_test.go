@@ -111,6 +111,11 @@ func TestSystemBackend_InternalUIResultantACL(t *testing.T) {
 					"update",
 				},
 			},
+			"sys/wrapping/inbox": map[string]interface{}{
+				"capabilities": []interface{}{
+					"list",
+				},
+			},
 			"sys/wrapping/lookup": map[string]interface{}{
 				"capabilities": []interface{}{
 					"update",
@@ -142,6 +147,11 @@ func TestSystemBackend_InternalUIResultantACL(t *testing.T) {
 					"update",
 				},
 			},
+			"sys/wrapping/inbox/": map[string]interface{}{
+				"capabilities": []interface{}{
+					"update",
+				},
+			},
 		},
 		"root": false,
 	}
@@ -0,0 +1,392 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !enterprise
+
+package vault
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault/managedkeys"
+)
+
+// managedKeyHashAlgorithms are the hash algorithms keys can be tested with.
+var managedKeyHashAlgorithms = map[string]crypto.Hash{
+	"sha2-256": crypto.SHA256,
+	"sha2-384": crypto.SHA384,
+	"sha2-512": crypto.SHA512,
+}
+
+// managedKeysPaths returns paths that configure the managed keys of the
+// namespace, the keys held by a KMS that mounts sign with
+func (b *SystemBackend) managedKeysPaths() []*framework.Path {
+	keyFields := map[string]*framework.FieldSchema{
+		"type": {
+			Type:        framework.TypeString,
+			Description: "The type of KMS holding the key: " + strings.Join(managedkeys.Types(), ", ") + ".",
+		},
+		"name": {
+			Type:        framework.TypeString,
+			Description: "The name of the key, unique across all the types in the namespace.",
+		},
+		"usages": {
+			Type:        framework.TypeCommaStringSlice,
+			Default:     []string{managedKeyUsageSign, managedKeyUsageVerify},
+			Description: "The allowed usages of the key, sign and verify.",
+		},
+		"any_mount": {
+			Type:        framework.TypeBool,
+			Description: "Allow every mount of the namespace to use the key, rather than only those listing it in allowed_managed_keys.",
+		},
+		"allow_generate_key": {
+			Type:        framework.TypeBool,
+			Description: "Not supported, the key must exist in the KMS.",
+		},
+		"allow_replace_key": {
+			Type:        framework.TypeBool,
+			Description: "Not supported, the key must exist in the KMS.",
+		},
+		"allow_store_key": {
+			Type:        framework.TypeBool,
+			Description: "Not supported, the key must exist in the KMS.",
+		},
+	}
+	for _, keyType := range managedkeys.Types() {
+		fields, _, _ := managedkeys.Fields(keyType)
+		for _, field := range fields {
+			keyFields[field] = &framework.FieldSchema{
+				Type:        framework.TypeString,
+				Description: "Configuration of the key. See the documentation of the key type for its meaning.",
+			}
+		}
+	}
+
+	return []*framework.Path{
+		{
+			Pattern: "managed-keys/" + framework.GenericNameRegex("type") + "/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "managed-keys",
+				OperationVerb:   "list",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"type": keyFields["type"],
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleManagedKeysList,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type: framework.TypeStringSlice,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["managed-keys"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["managed-keys"][1]),
+		},
+		{
+			Pattern: "managed-keys/" + framework.GenericNameRegex("type") + "/" + framework.GenericNameRegex("name") + "$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "managed-keys",
+			},
+
+			Fields: keyFields,
+
+			ExistenceCheck: b.handleManagedKeyExistenceCheck,
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleManagedKeyRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+				},
+				logical.CreateOperation: &framework.PathOperation{
+					Callback: b.handleManagedKeyWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "create",
+					},
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleManagedKeyWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "update",
+					},
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleManagedKeyDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "delete",
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["managed-keys"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["managed-keys"][1]),
+		},
+		{
+			Pattern: "managed-keys/" + framework.GenericNameRegex("type") + "/" + framework.GenericNameRegex("name") + "/test/sign$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "managed-keys",
+				OperationVerb:   "test-sign",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"type": keyFields["type"],
+				"name": keyFields["name"],
+				"use_pss": {
+					Type:        framework.TypeBool,
+					Description: "Sign with RSA PSS rather than PKCS#1 v1.5.",
+				},
+				"hash_algorithm": {
+					Type:        framework.TypeString,
+					Default:     "sha2-256",
+					Description: "The hash algorithm to sign with: sha2-256, sha2-384 or sha2-512.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleManagedKeyTestSign,
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["managed-keys-test-sign"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["managed-keys-test-sign"][1]),
+		},
+	}
+}
+
+// managedKeyFromRequest returns the namespace of the request and the key at
+// the path, or nil if there is none. A key of another type is an error.
+func (b *SystemBackend) managedKeyFromRequest(ctx context.Context, d *framework.FieldData) (*namespace.Namespace, *managedKeyEntry, error) {
+	r := b.Core.managedKeyRegistry
+	if r == nil {
+		return nil, nil, errors.New("managed key registry is not set up")
+	}
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyType := strings.ToLower(d.Get("type").(string))
+	if _, _, err := managedkeys.Fields(keyType); err != nil {
+		return nil, nil, logical.CodedError(http.StatusBadRequest, err.Error())
+	}
+	name := d.Get("name").(string)
+	if name != strings.ToLower(name) {
+		return nil, nil, logical.CodedError(http.StatusBadRequest, "managed key names must be lowercase")
+	}
+
+	entry, err := r.get(ctx, ns, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if entry != nil && entry.Type != keyType {
+		return nil, nil, logical.CodedError(http.StatusBadRequest, fmt.Sprintf("managed key %q is of type %q", name, entry.Type))
+	}
+	return ns, entry, nil
+}
+
+func (b *SystemBackend) handleManagedKeysList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	r := b.Core.managedKeyRegistry
+	if r == nil {
+		return nil, errors.New("managed key registry is not set up")
+	}
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyType := strings.ToLower(d.Get("type").(string))
+	if _, _, err := managedkeys.Fields(keyType); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	keys, err := r.list(ctx, ns, keyType)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(keys), nil
+}
+
+func (b *SystemBackend) handleManagedKeyExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	_, entry, err := b.managedKeyFromRequest(ctx, d)
+	if err != nil {
+		return false, err
+	}
+	return entry != nil, nil
+}
+
+func (b *SystemBackend) handleManagedKeyRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	_, entry, err := b.managedKeyFromRequest(ctx, d)
+	if err != nil {
+		return handleError(err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	data := map[string]interface{}{
+		"UUID":               entry.UUID,
+		"name":               entry.Name,
+		"type":               entry.Type,
+		"usages":             entry.Usages,
+		"any_mount":          entry.AnyMount,
+		"allow_generate_key": false,
+		"allow_replace_key":  false,
+		"allow_store_key":    false,
+	}
+	fields, sensitive, _ := managedkeys.Fields(entry.Type)
+	for _, field := range fields {
+		value := entry.Config[field]
+		if value != "" && strutil.StrListContains(sensitive, field) {
+			value = "redacted"
+		}
+		data[field] = value
+	}
+
+	return &logical.Response{Data: data}, nil
+}
+
+func (b *SystemBackend) handleManagedKeyWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, entry, err := b.managedKeyFromRequest(ctx, d)
+	if err != nil {
+		return handleError(err)
+	}
+
+	for _, field := range []string{"allow_generate_key", "allow_replace_key", "allow_store_key"} {
+		if d.Get(field).(bool) {
+			return logical.ErrorResponse("%s is not supported, the key must exist in the KMS", field), logical.ErrInvalidRequest
+		}
+	}
+
+	if entry == nil {
+		entry = &managedKeyEntry{
+			Name:   d.Get("name").(string),
+			Type:   strings.ToLower(d.Get("type").(string)),
+			Config: make(map[string]string),
+		}
+	}
+
+	if _, ok := d.GetOk("usages"); ok || entry.Usages == nil {
+		usages := strutil.RemoveDuplicates(d.Get("usages").([]string), true)
+		for _, usage := range usages {
+			if usage != managedKeyUsageSign && usage != managedKeyUsageVerify {
+				return logical.ErrorResponse("unsupported usage %q, managed keys can only sign and verify", usage), logical.ErrInvalidRequest
+			}
+		}
+		entry.Usages = usages
+	}
+	if raw, ok := d.GetOk("any_mount"); ok {
+		entry.AnyMount = raw.(bool)
+	}
+
+	fields, _, _ := managedkeys.Fields(entry.Type)
+	for _, field := range fields {
+		if raw, ok := d.GetOk(field); ok {
+			entry.Config[field] = raw.(string)
+		}
+	}
+	if err := managedkeys.Validate(entry.Type, entry.Config, b.Core.kmsLibraries()); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if err := b.Core.managedKeyRegistry.put(ctx, ns, entry); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleManagedKeyDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, entry, err := b.managedKeyFromRequest(ctx, d)
+	if err != nil {
+		return handleError(err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	if paths := b.Core.managedKeyRegistry.mountsUsing(ns, entry); len(paths) > 0 {
+		return logical.ErrorResponse("managed key %q is in the allowed_managed_keys of mounts %s", entry.Name, strings.Join(paths, ", ")), logical.ErrInvalidRequest
+	}
+
+	if err := b.Core.managedKeyRegistry.delete(ctx, ns, entry); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleManagedKeyTestSign(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	_, entry, err := b.managedKeyFromRequest(ctx, d)
+	if err != nil {
+		return handleError(err)
+	}
+	if entry == nil {
+		return logical.ErrorResponse("managed key %q not found", d.Get("name").(string)), logical.ErrInvalidRequest
+	}
+
+	hash, ok := managedKeyHashAlgorithms[d.Get("hash_algorithm").(string)]
+	if !ok {
+		return logical.ErrorResponse("unsupported hash_algorithm %q", d.Get("hash_algorithm").(string)), logical.ErrInvalidRequest
+	}
+	var opts crypto.SignerOpts = hash
+	if d.Get("use_pss").(bool) {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash}
+	}
+
+	signer, err := b.Core.managedKeyRegistry.signer(ctx, entry)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+	key := &managedKey{entry: entry, signer: signer}
+
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return nil, err
+	}
+	h := hash.New()
+	h.Write(data)
+	digest := h.Sum(nil)
+
+	sig, err := key.Sign(ctx, digest, nil, opts)
+	if err != nil {
+		return logical.ErrorResponse("failed to sign with managed key %q: %s", entry.Name, err), logical.ErrInvalidRequest
+	}
+	verified, err := key.verify(sig, digest, opts)
+	if err != nil {
+		return logical.ErrorResponse("failed to verify with managed key %q: %s", entry.Name, err), logical.ErrInvalidRequest
+	}
+	if !verified {
+		return logical.ErrorResponse("the signature of managed key %q does not verify with its public key", entry.Name), logical.ErrInvalidRequest
+	}
+	return nil, nil
+}
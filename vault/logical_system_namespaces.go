@@ -0,0 +1,291 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// namespacesPaths returns paths that enable namespace management
+func (b *SystemBackend) namespacesPaths() []*framework.Path {
+	namespaceResponseFields := map[string]*framework.FieldSchema{
+		"id": {
+			Type:     framework.TypeString,
+			Required: true,
+		},
+		"path": {
+			Type:     framework.TypeString,
+			Required: true,
+		},
+		"custom_metadata": {
+			Type:     framework.TypeMap,
+			Required: true,
+		},
+	}
+
+	return []*framework.Path{
+		{
+			Pattern: "namespaces/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "namespaces",
+				OperationVerb:   "list",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleNamespacesList(),
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+								"key_info": {
+									Type:     framework.TypeMap,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(namespacesHelp["namespaces-list"][0]),
+			HelpDescription: strings.TrimSpace(namespacesHelp["namespaces-list"][1]),
+		},
+		{
+			Pattern: "namespaces/(?P<path>.+)",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "namespaces",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": {
+					Type:        framework.TypeString,
+					Description: "Path of the namespace, relative to the namespace of the request.",
+				},
+				"custom_metadata": {
+					Type:        framework.TypeKVPairs,
+					Description: "User-provided key-value pairs describing the namespace.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleNamespacesCreate(),
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "create",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields:      namespaceResponseFields,
+						}},
+					},
+				},
+				logical.PatchOperation: &framework.PathOperation{
+					Callback: b.handleNamespacesPatch(),
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "patch",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields:      namespaceResponseFields,
+						}},
+					},
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleNamespacesRead(),
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields:      namespaceResponseFields,
+						}},
+					},
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleNamespacesDelete(),
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "delete",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: http.StatusText(http.StatusNoContent),
+						}},
+					},
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(namespacesHelp["namespaces"][0]),
+			HelpDescription: strings.TrimSpace(namespacesHelp["namespaces"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleNamespacesList() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		ns, err := namespace.FromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var keys []string
+		keyInfo := make(map[string]interface{})
+		for _, child := range b.Core.namespaceStore.childNamespaces(ns) {
+			key := strings.TrimPrefix(child.Path, ns.Path)
+			keys = append(keys, key)
+			keyInfo[key] = namespaceResponseData(child)
+		}
+
+		return logical.ListResponseWithInfo(keys, keyInfo), nil
+	}
+}
+
+func (b *SystemBackend) handleNamespacesCreate() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		ns, err := namespace.FromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		customMetadata := d.Get("custom_metadata").(map[string]string)
+		created, err := b.Core.createNamespace(ctx, ns, d.Get("path").(string), customMetadata)
+		if err != nil {
+			return handleError(err)
+		}
+
+		return &logical.Response{
+			Data: namespaceResponseData(created),
+		}, nil
+	}
+}
+
+func (b *SystemBackend) handleNamespacesPatch() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		existing, err := b.namespaceFromPath(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return logical.RespondWithStatusCode(nil, req, http.StatusNotFound)
+		}
+
+		// Keys set to null are removed from the custom metadata
+		customMetadata := make(map[string]string, len(existing.CustomMetadata))
+		for k, v := range existing.CustomMetadata {
+			customMetadata[k] = v
+		}
+		if raw, ok := req.Data["custom_metadata"]; ok && raw != nil {
+			patch, ok := raw.(map[string]interface{})
+			if !ok {
+				return logical.ErrorResponse("custom_metadata must be a map"), logical.ErrInvalidRequest
+			}
+			for k, v := range patch {
+				switch v := v.(type) {
+				case nil:
+					delete(customMetadata, k)
+				case string:
+					customMetadata[k] = v
+				default:
+					return logical.ErrorResponse(fmt.Sprintf("the value of custom_metadata key %q must be a string", k)), logical.ErrInvalidRequest
+				}
+			}
+		}
+
+		patched, err := b.Core.patchNamespace(ctx, existing, customMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		return &logical.Response{
+			Data: namespaceResponseData(patched),
+		}, nil
+	}
+}
+
+func (b *SystemBackend) handleNamespacesRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		ns, err := b.namespaceFromPath(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		if ns == nil {
+			return nil, nil
+		}
+
+		return &logical.Response{
+			Data: namespaceResponseData(ns),
+		}, nil
+	}
+}
+
+func (b *SystemBackend) handleNamespacesDelete() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		ns, err := b.namespaceFromPath(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		if ns == nil {
+			return nil, nil
+		}
+
+		if err := b.Core.deleteNamespace(ctx, ns); err != nil {
+			return handleError(err)
+		}
+		return nil, nil
+	}
+}
+
+// namespaceFromPath returns the namespace at the path of the request, relative
+// to the namespace of the request, or nil if there is none.
+func (b *SystemBackend) namespaceFromPath(ctx context.Context, d *framework.FieldData) (*namespace.Namespace, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := namespace.Canonicalize(d.Get("path").(string))
+	if path == "" {
+		return nil, nil
+	}
+
+	found := b.Core.namespaceByPath(ns.Path + path)
+	if found.Path != ns.Path+path {
+		return nil, nil
+	}
+	return found, nil
+}
+
+func namespaceResponseData(ns *namespace.Namespace) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              ns.ID,
+		"path":            ns.Path,
+		"custom_metadata": ns.CustomMetadata,
+	}
+}
+
+var namespacesHelp = map[string][2]string{
+	"namespaces-list": {
+		"List the namespaces within the current namespace.",
+		"",
+	},
+	"namespaces": {
+		"Create, read, update or delete a namespace.",
+		`Namespaces isolate secrets engines, auth methods, policies, tokens and
+identities from each other. A namespace can only be deleted when it has no
+secrets engines, auth methods, child namespaces or identities left.`,
+	},
+}
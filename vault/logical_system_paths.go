@@ -135,6 +135,19 @@ func (b *SystemBackend) configPaths() []*framework.Path {
 					Summary:     "Reload the given subsystem",
 					Description: "",
 					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"applied": {
+									Type:        framework.TypeStringSlice,
+									Description: "The settings applied by reloading the server configuration.",
+								},
+								"restart_required": {
+									Type:        framework.TypeStringSlice,
+									Description: "The changed settings that require a restart to apply.",
+								},
+							},
+						}},
 						http.StatusNoContent: {{
 							Description: "OK",
 						}},
@@ -1488,6 +1501,98 @@ func (b *SystemBackend) statusPaths() []*framework.Path {
 
 func (b *SystemBackend) auditPaths() []*framework.Path {
 	return []*framework.Path{
+		{
+			Pattern: "audit-hash/rotate$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "auditing",
+				OperationVerb:   "rotate",
+				OperationSuffix: "hash-salt",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": {
+					Type:        framework.TypeString,
+					Required:    true,
+					Description: strings.TrimSpace(sysHelp["audit_path"][0]),
+				},
+
+				"overlap": {
+					Type:        framework.TypeDurationSecond,
+					Default:     int(defaultAuditSaltOverlap.Seconds()),
+					Description: "How long the previous salt keeps being used to compute hashes after the rotation.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleAuditHashRotate,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"rotated_at": {
+									Type:     framework.TypeTime,
+									Required: true,
+								},
+								"overlap_end": {
+									Type:     framework.TypeTime,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["audit-hash-rotate"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["audit-hash-rotate"][1]),
+		},
+
+		{
+			Pattern: "audit-hash/rewrap$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "auditing",
+				OperationVerb:   "rewrap",
+				OperationSuffix: "hashes",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": {
+					Type:        framework.TypeString,
+					Required:    true,
+					Description: strings.TrimSpace(sysHelp["audit_path"][0]),
+				},
+
+				"input": {
+					Type:        framework.TypeCommaStringSlice,
+					Required:    true,
+					Description: "The values to compute the previous and current hashes of.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleAuditHashRewrap,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"hashes": {
+									Type:     framework.TypeSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["audit-hash-rewrap"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["audit-hash-rewrap"][1]),
+		},
+
 		{
 			Pattern: "audit-hash/(?P<path>.+)",
 
@@ -1519,6 +1624,10 @@ func (b *SystemBackend) auditPaths() []*framework.Path {
 									Type:     framework.TypeString,
 									Required: true,
 								},
+								"previous_hash": {
+									Type:     framework.TypeString,
+									Required: false,
+								},
 							},
 						}},
 					},
@@ -1874,6 +1983,18 @@ func (b *SystemBackend) pluginsCatalogCRUDPath() *framework.Path {
 				Type:        framework.TypeString,
 				Description: strings.TrimSpace(sysHelp["plugin-catalog_version"][0]),
 			},
+			"runtime": {
+				Type:        framework.TypeString,
+				Description: strings.TrimSpace(sysHelp["plugin-catalog_runtime"][0]),
+			},
+			"memory_limit": {
+				Type:        framework.TypeString,
+				Description: strings.TrimSpace(sysHelp["plugin-catalog_memory-limit"][0]),
+			},
+			"cpu_limit": {
+				Type:        framework.TypeFloat,
+				Description: strings.TrimSpace(sysHelp["plugin-catalog_cpu-limit"][0]),
+			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -1943,6 +2064,21 @@ func (b *SystemBackend) pluginsCatalogCRUDPath() *framework.Path {
 								Type:     framework.TypeBool,
 								Required: true,
 							},
+							"runtime": {
+								Type:        framework.TypeString,
+								Description: strings.TrimSpace(sysHelp["plugin-catalog_runtime"][0]),
+								Required:    false,
+							},
+							"memory_limit": {
+								Type:        framework.TypeInt64,
+								Description: strings.TrimSpace(sysHelp["plugin-catalog_memory-limit"][0]),
+								Required:    false,
+							},
+							"cpu_limit": {
+								Type:        framework.TypeFloat,
+								Description: strings.TrimSpace(sysHelp["plugin-catalog_cpu-limit"][0]),
+								Required:    false,
+							},
 							"deprecation_status": {
 								Type:     framework.TypeString,
 								Required: false,
@@ -2089,6 +2225,76 @@ func (b *SystemBackend) pluginsReloadPath() *framework.Path {
 	}
 }
 
+func (b *SystemBackend) pluginsRuntimeStatusPath() *framework.Path {
+	return &framework.Path{
+		Pattern: "plugins/runtime/status$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: "plugins",
+			OperationVerb:   "read",
+			OperationSuffix: "runtime-status",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.handlePluginRuntimeStatus,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"plugins": {
+								Type:     framework.TypeSlice,
+								Required: true,
+							},
+						},
+					}},
+				},
+				Summary: "Report the restarts and resource usage of the running plugins.",
+			},
+		},
+
+		HelpSynopsis:    strings.TrimSpace(sysHelp["plugin-runtime-status"][0]),
+		HelpDescription: strings.TrimSpace(sysHelp["plugin-runtime-status"][1]),
+	}
+}
+
+func (b *SystemBackend) healthMountsPath() *framework.Path {
+	return &framework.Path{
+		Pattern: "health/mounts$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: "health",
+			OperationVerb:   "read",
+			OperationSuffix: "mounts",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.handleHealthMounts,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"status": {
+								Type:     framework.TypeString,
+								Required: true,
+							},
+							"mounts": {
+								Type:     framework.TypeSlice,
+								Required: true,
+							},
+						},
+					}},
+				},
+				Summary: "Run the health checks of the mounted backends.",
+			},
+		},
+
+		HelpSynopsis:    strings.TrimSpace(sysHelp["health-mounts"][0]),
+		HelpDescription: strings.TrimSpace(sysHelp["health-mounts"][1]),
+	}
+}
+
 func (b *SystemBackend) toolsPaths() []*framework.Path {
 	return []*framework.Path{
 		{
@@ -2832,6 +3038,49 @@ func (b *SystemBackend) leasePaths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(sysHelp["renew"][1]),
 		},
 
+		{
+			Pattern: "leases/renew-batch$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "leases",
+				OperationVerb:   "renew",
+				OperationSuffix: "batch",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"lease_ids": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: strings.TrimSpace(sysHelp["lease_ids"][0]),
+				},
+				"increment": {
+					Type:        framework.TypeDurationSecond,
+					Description: strings.TrimSpace(sysHelp["increment"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleRenewBatch,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"leases": {
+									Type:        framework.TypeSlice,
+									Description: "The result of the renewal of each lease, in the order of the request",
+									Required:    true,
+								},
+							},
+						}},
+					},
+					Summary: "Renews several leases at once, requesting to extend them.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["renew-batch"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["renew-batch"][1]),
+		},
+
 		{
 			Pattern: "(leases/)?revoke" + framework.OptionalParamRegex("url_lease_id"),
 
@@ -3154,6 +3403,206 @@ func (b *SystemBackend) remountPaths() []*framework.Path {
 	}
 }
 
+func (b *SystemBackend) deletedMountsPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "deleted-mounts/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "deleted-mounts",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleDeletedMountsList,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "list",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+								"key_info": {
+									Type:     framework.TypeMap,
+									Required: true,
+								},
+							},
+						}},
+					},
+					Summary: "List the disabled mounts whose storage is retained.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["deleted-mounts"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["deleted-mounts"][1]),
+		},
+		{
+			Pattern: "deleted-mounts/(?P<accessor>[^/]+)$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "deleted-mounts",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"accessor": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["deleted_mount_accessor"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleDeletedMountRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"accessor": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"path": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"type": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"table": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"description": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"deletion_time": {
+									Type:     framework.TypeTime,
+									Required: true,
+								},
+								"purge_time": {
+									Type:     framework.TypeTime,
+									Required: true,
+								},
+							},
+						}},
+					},
+					Summary: "Read a disabled mount whose storage is retained.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleDeletedMountPurge,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "purge",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+						}},
+					},
+					Summary: "Purge the retained storage of a disabled mount.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["deleted-mounts-accessor"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["deleted-mounts-accessor"][1]),
+		},
+		{
+			Pattern: "deleted-mounts/(?P<accessor>[^/]+)/restore$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "deleted-mounts",
+				OperationVerb:   "restore",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"accessor": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["deleted_mount_accessor"][0]),
+				},
+				"path": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["deleted_mount_restore_path"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleDeletedMountRestore,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"accessor": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"path": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+							},
+						}},
+					},
+					Summary: "Restore a disabled mount with its retained storage.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["deleted-mounts-restore"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["deleted-mounts-restore"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) storageCachePaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "storage/cache/flush$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "storage-cache",
+				OperationVerb:   "flush",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"prefixes": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: strings.TrimSpace(sysHelp["storage_cache_flush_prefixes"][0]),
+					Required:    true,
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageCacheFlush,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"flushed_entries": {
+									Type:     framework.TypeInt,
+									Required: true,
+								},
+							},
+						}},
+					},
+					Summary: "Flush the entries of the storage cache with the given key prefixes.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["storage-cache-flush"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["storage-cache-flush"][1]),
+		},
+	}
+}
+
 func (b *SystemBackend) metricsPath() *framework.Path {
 	return &framework.Path{
 		Pattern: "metrics",
@@ -3184,6 +3633,40 @@ func (b *SystemBackend) metricsPath() *framework.Path {
 	}
 }
 
+func (b *SystemBackend) metricsRoutesPath() *framework.Path {
+	return &framework.Path{
+		Pattern: "metrics/routes$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationVerb:   "metrics",
+			OperationSuffix: "routes",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.handleMetricsRoutes,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"routes": {
+								Type:     framework.TypeSlice,
+								Required: true,
+							},
+							"max_mounts": {
+								Type:     framework.TypeInt,
+								Required: true,
+							},
+						},
+					}},
+				},
+			},
+		},
+		HelpSynopsis:    strings.TrimSpace(sysHelp["metrics-routes"][0]),
+		HelpDescription: strings.TrimSpace(sysHelp["metrics-routes"][1]),
+	}
+}
+
 func (b *SystemBackend) monitorPath() *framework.Path {
 	return &framework.Path{
 		Pattern: "monitor",
@@ -3383,6 +3866,14 @@ func (b *SystemBackend) authPaths() []*framework.Path {
 					Type:        framework.TypeString,
 					Description: strings.TrimSpace(sysHelp["plugin-catalog_version"][0]),
 				},
+				"deletion_protection": {
+					Type:        framework.TypeBool,
+					Description: strings.TrimSpace(sysHelp["tune_deletion_protection"][0]),
+				},
+				"soft_delete_retention": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["tune_soft_delete_retention"][0]),
+				},
 			},
 			Operations: map[logical.Operation]framework.OperationHandler{
 				logical.ReadOperation: &framework.PathOperation{
@@ -3445,6 +3936,14 @@ func (b *SystemBackend) authPaths() []*framework.Path {
 									Type:     framework.TypeCommaStringSlice,
 									Required: false,
 								},
+								"deletion_protection": {
+									Type:     framework.TypeBool,
+									Required: false,
+								},
+								"soft_delete_retention": {
+									Type:     framework.TypeInt64,
+									Required: false,
+								},
 								"user_lockout_counter_reset_duration": {
 									Type:     framework.TypeInt64,
 									Required: false,
@@ -3873,6 +4372,100 @@ func (b *SystemBackend) policyPaths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(sysHelp["policy"][1]),
 		},
 
+		{
+			Pattern: "policies/rego/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "policies",
+				OperationSuffix: "rego-policies",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handlePoliciesList(PolicyTypeRego),
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["rego-policy-list"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["rego-policy-list"][1]),
+		},
+
+		{
+			Pattern: "policies/rego/(?P<name>.+)",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "policies",
+				OperationSuffix: "rego-policy",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["policy-name"][0]),
+				},
+				"policy": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["rego-policy-module"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handlePoliciesRead(PolicyTypeRego),
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"name": {
+									Type:     framework.TypeString,
+									Required: false,
+								},
+								"policy": {
+									Type:     framework.TypeString,
+									Required: false,
+								},
+							},
+						}},
+					},
+					Summary: "Retrieve information about the named Rego policy.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handlePoliciesSet(PolicyTypeRego),
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+							Fields:      map[string]*framework.FieldSchema{},
+						}},
+					},
+					Summary: "Add a new or update an existing Rego policy.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handlePoliciesDelete(PolicyTypeRego),
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+							Fields:      map[string]*framework.FieldSchema{},
+						}},
+					},
+					Summary: "Delete the Rego policy with the given name.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["rego-policy"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["rego-policy"][1]),
+		},
+
 		{
 			Pattern: "policies/password/?$",
 
@@ -4227,6 +4820,14 @@ func (b *SystemBackend) mountPaths() []*framework.Path {
 					Type:        framework.TypeCommaStringSlice,
 					Description: strings.TrimSpace(sysHelp["tune_allowed_managed_keys"][0]),
 				},
+				"deletion_protection": {
+					Type:        framework.TypeBool,
+					Description: strings.TrimSpace(sysHelp["tune_deletion_protection"][0]),
+				},
+				"soft_delete_retention": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["tune_soft_delete_retention"][0]),
+				},
 				"plugin_version": {
 					Type:        framework.TypeString,
 					Description: strings.TrimSpace(sysHelp["plugin-catalog_version"][0]),
@@ -4277,6 +4878,16 @@ func (b *SystemBackend) mountPaths() []*framework.Path {
 									Description: strings.TrimSpace(sysHelp["tune_allowed_managed_keys"][0]),
 									Required:    false,
 								},
+								"deletion_protection": {
+									Type:        framework.TypeBool,
+									Description: strings.TrimSpace(sysHelp["tune_deletion_protection"][0]),
+									Required:    false,
+								},
+								"soft_delete_retention": {
+									Type:        framework.TypeInt64,
+									Description: strings.TrimSpace(sysHelp["tune_soft_delete_retention"][0]),
+									Required:    false,
+								},
 								"allowed_response_headers": {
 									Type:        framework.TypeCommaStringSlice,
 									Description: strings.TrimSpace(sysHelp["allowed_response_headers"][0]),
@@ -9,11 +9,13 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	wrapping "github.com/hashicorp/go-kms-wrapping/v2"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/helper/constants"
 	"github.com/hashicorp/vault/helper/namespace"
@@ -21,6 +23,7 @@ import (
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/hashicorp/vault/sdk/physical"
+	"github.com/hashicorp/vault/vault/snapshotstore"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -152,6 +155,70 @@ func (b *SystemBackend) raftStoragePaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-force"][0]),
 			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-force"][1]),
 		},
+		{
+			Pattern: "storage/raft/compact",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftCompactUpdate(),
+					Summary:  "Compacts the database of every node of the raft cluster, one node at a time.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-compact"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-compact"][1]),
+		},
+		{
+			Pattern: "storage/raft/snapshot-auto/config/?$",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotAutoConfigList(),
+					Summary:  "Lists the automatic snapshot configurations.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-config-list"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-config-list"][1]),
+		},
+		{
+			Pattern: "storage/raft/snapshot-auto/config/" + framework.GenericNameRegex("name"),
+			Fields:  raftSnapshotAutoConfigFields(),
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotAutoConfigRead(),
+					Summary:  "Returns the automatic snapshot configuration.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotAutoConfigUpdate(),
+					Summary:  "Creates or updates the automatic snapshot configuration.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotAutoConfigDelete(),
+					Summary:  "Deletes the automatic snapshot configuration, stopping its snapshots.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-config"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-config"][1]),
+		},
+		{
+			Pattern: "storage/raft/snapshot-auto/status/" + framework.GenericNameRegex("name"),
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the automatic snapshot configuration.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback:                  b.handleStorageRaftSnapshotAutoStatus(),
+					Summary:                   "Returns the status of the snapshots of the automatic snapshot configuration.",
+					ForwardPerformanceStandby: true,
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-status"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-auto-status"][1]),
+		},
 		{
 			Pattern: "storage/raft/autopilot/state",
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -551,6 +618,315 @@ func (b *SystemBackend) handleStorageRaftAutopilotConfigUpdate() framework.Opera
 	}
 }
 
+// raftSnapshotAutoConfigFields returns the fields of an automatic snapshot
+// configuration.
+func raftSnapshotAutoConfigFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"name": {
+			Type:        framework.TypeString,
+			Description: "Name of the automatic snapshot configuration.",
+		},
+		"interval": {
+			Type:        framework.TypeDurationSecond,
+			Description: "Time between snapshots.",
+		},
+		"retain": {
+			Type:        framework.TypeInt,
+			Default:     defaultRaftAutoSnapshotRetain,
+			Description: "Number of snapshots to keep; older snapshots are deleted. 0 keeps every snapshot.",
+		},
+		"path_prefix": {
+			Type:        framework.TypeString,
+			Description: "Directory, or object key prefix, snapshots are saved under.",
+		},
+		"file_prefix": {
+			Type:        framework.TypeString,
+			Default:     defaultRaftAutoSnapshotFilePrefix,
+			Description: "Prefix of the file name of the snapshots.",
+		},
+		"storage_type": {
+			Type:          framework.TypeString,
+			Description:   "Destination of the snapshots.",
+			AllowedValues: []interface{}{snapshotstore.TypeLocal, snapshotstore.TypeAWSS3, snapshotstore.TypeGoogleGCS, snapshotstore.TypeAzureBlob},
+		},
+		"local_max_space": {
+			Type:        framework.TypeInt,
+			Description: "For local storage, the maximum space in bytes all the snapshots of the configuration may use.",
+		},
+		"aws_s3_bucket": {
+			Type:        framework.TypeString,
+			Description: "For aws-s3 storage, the bucket snapshots are saved to.",
+		},
+		"aws_s3_region": {
+			Type:        framework.TypeString,
+			Description: "For aws-s3 storage, the region of the bucket.",
+		},
+		"aws_s3_endpoint": {
+			Type:        framework.TypeString,
+			Description: "For aws-s3 storage, a custom S3 endpoint.",
+		},
+		"aws_s3_disable_tls": {
+			Type:        framework.TypeBool,
+			Description: "For aws-s3 storage, whether to connect to the endpoint without TLS.",
+		},
+		"aws_s3_force_path_style": {
+			Type:        framework.TypeBool,
+			Description: "For aws-s3 storage, whether to use path-style bucket addressing.",
+		},
+		"aws_s3_server_side_encryption": {
+			Type:        framework.TypeBool,
+			Description: "For aws-s3 storage, whether to encrypt the snapshots with S3 managed keys.",
+		},
+		"aws_s3_enable_kms": {
+			Type:        framework.TypeBool,
+			Description: "For aws-s3 storage, whether to encrypt the snapshots with KMS.",
+		},
+		"aws_s3_kms_key": {
+			Type:        framework.TypeString,
+			Description: "For aws-s3 storage, the KMS key to encrypt the snapshots with when KMS is enabled. Defaults to the AWS managed key.",
+		},
+		"aws_access_key_id": {
+			Type:        framework.TypeString,
+			Description: "For aws-s3 storage, the access key ID. Defaults to the AWS credential chain.",
+		},
+		"aws_secret_access_key": {
+			Type:        framework.TypeString,
+			Description: "For aws-s3 storage, the secret access key.",
+		},
+		"aws_session_token": {
+			Type:        framework.TypeString,
+			Description: "For aws-s3 storage, the session token.",
+		},
+		"google_gcs_bucket": {
+			Type:        framework.TypeString,
+			Description: "For google-gcs storage, the bucket snapshots are saved to.",
+		},
+		"google_endpoint": {
+			Type:        framework.TypeString,
+			Description: "For google-gcs storage, a custom storage endpoint.",
+		},
+		"google_disable_tls": {
+			Type:        framework.TypeBool,
+			Description: "For google-gcs storage, whether to connect to the endpoint without TLS.",
+		},
+		"google_service_account_key": {
+			Type:        framework.TypeString,
+			Description: "For google-gcs storage, the JSON service account key. Defaults to application default credentials.",
+		},
+		"azure_container_name": {
+			Type:        framework.TypeString,
+			Description: "For azure-blob storage, the container snapshots are saved to.",
+		},
+		"azure_account_name": {
+			Type:        framework.TypeString,
+			Description: "For azure-blob storage, the storage account name.",
+		},
+		"azure_account_key": {
+			Type:        framework.TypeString,
+			Description: "For azure-blob storage, the storage account key.",
+		},
+		"azure_blob_environment": {
+			Type:        framework.TypeString,
+			Description: "For azure-blob storage, the Azure environment. Defaults to AzurePublicCloud.",
+		},
+		"azure_endpoint": {
+			Type:        framework.TypeString,
+			Description: "For azure-blob storage, a custom blob service endpoint.",
+		},
+	}
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoConfigList() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		names, err := b.Core.barrier.List(ctx, raftAutoSnapshotConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		return logical.ListResponse(names), nil
+	}
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoConfigRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		config, err := b.Core.loadRaftAutoSnapshotConfig(ctx, d.Get("name").(string))
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			return nil, nil
+		}
+
+		data := map[string]interface{}{
+			"interval":        int64(config.Interval.Seconds()),
+			"retain":          config.Retain,
+			"path_prefix":     config.PathPrefix,
+			"file_prefix":     config.FilePrefix,
+			"storage_type":    config.StorageType,
+			"local_max_space": config.LocalMaxSpace,
+		}
+		for key, value := range config.StorageConfig {
+			if strutil.StrListContains(snapshotstore.SensitiveConfigKeys, key) {
+				continue
+			}
+			data[key] = value
+		}
+
+		return &logical.Response{
+			Data: data,
+		}, nil
+	}
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoConfigUpdate() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		name := d.Get("name").(string)
+		config, err := b.Core.loadRaftAutoSnapshotConfig(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			config = &raftAutoSnapshotConfig{
+				Name:       name,
+				Retain:     defaultRaftAutoSnapshotRetain,
+				FilePrefix: defaultRaftAutoSnapshotFilePrefix,
+			}
+		}
+
+		if interval, ok := d.GetOk("interval"); ok {
+			config.Interval = time.Duration(interval.(int)) * time.Second
+		}
+		if retain, ok := d.GetOk("retain"); ok {
+			config.Retain = retain.(int)
+		}
+		if pathPrefix, ok := d.GetOk("path_prefix"); ok {
+			config.PathPrefix = pathPrefix.(string)
+		}
+		if filePrefix, ok := d.GetOk("file_prefix"); ok {
+			config.FilePrefix = filePrefix.(string)
+		}
+		if localMaxSpace, ok := d.GetOk("local_max_space"); ok {
+			config.LocalMaxSpace = int64(localMaxSpace.(int))
+		}
+		if storageType, ok := d.GetOk("storage_type"); ok && storageType.(string) != config.StorageType {
+			// The settings of the previous storage type don't apply anymore
+			config.StorageType = storageType.(string)
+			config.StorageConfig = nil
+		}
+
+		switch {
+		case config.Interval <= 0:
+			return logical.ErrorResponse("interval must be set to a positive duration"), nil
+		case config.Retain < 0:
+			return logical.ErrorResponse("retain must not be negative"), nil
+		case config.LocalMaxSpace < 0:
+			return logical.ErrorResponse("local_max_space must not be negative"), nil
+		case config.FilePrefix == "":
+			return logical.ErrorResponse("file_prefix must not be empty"), nil
+		case config.StorageType == "":
+			return logical.ErrorResponse("storage_type must be set"), nil
+		case config.PathPrefix == "":
+			return logical.ErrorResponse("path_prefix must be set"), nil
+		case config.StorageType == snapshotstore.TypeLocal && config.LocalMaxSpace <= 0:
+			return logical.ErrorResponse("local_max_space must be set for local storage"), nil
+		}
+
+		storageKeys, ok := snapshotstore.ConfigKeys[config.StorageType]
+		if !ok {
+			return logical.ErrorResponse("unknown storage_type %q", config.StorageType), nil
+		}
+		if config.StorageConfig == nil {
+			config.StorageConfig = make(map[string]string)
+		}
+		for key, schema := range d.Schema {
+			value, ok := d.GetOk(key)
+			if !ok || !isRaftSnapshotAutoStorageKey(key) {
+				continue
+			}
+			if !strutil.StrListContains(storageKeys, key) {
+				return logical.ErrorResponse("%s is not supported with storage_type %q", key, config.StorageType), nil
+			}
+			if schema.Type == framework.TypeBool {
+				config.StorageConfig[key] = strconv.FormatBool(value.(bool))
+			} else {
+				config.StorageConfig[key] = value.(string)
+			}
+		}
+
+		// Catch configuration errors now rather than when taking a snapshot
+		if _, err := config.newStore(b.logger); err != nil {
+			return logical.ErrorResponse("invalid storage configuration: %s", err), nil
+		}
+
+		if err := b.Core.putRaftAutoSnapshotConfig(ctx, config); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
+// isRaftSnapshotAutoStorageKey returns whether the field is specific to a
+// storage type.
+func isRaftSnapshotAutoStorageKey(key string) bool {
+	for _, keys := range snapshotstore.ConfigKeys {
+		if strutil.StrListContains(keys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoConfigDelete() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		if err := b.Core.deleteRaftAutoSnapshotConfig(ctx, d.Get("name").(string)); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
+func (b *SystemBackend) handleStorageRaftSnapshotAutoStatus() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		name := d.Get("name").(string)
+		config, err := b.Core.loadRaftAutoSnapshotConfig(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			return nil, nil
+		}
+
+		autoSnapshots := b.Core.getRaftAutoSnapshots()
+		if autoSnapshots == nil {
+			return logical.ErrorResponse("automatic snapshots are not running on this node"), logical.ErrInvalidRequest
+		}
+		status, ok := autoSnapshots.status(name)
+		if !ok {
+			return nil, nil
+		}
+
+		data := map[string]interface{}{
+			"consecutive_errors":  status.ConsecutiveErrors,
+			"last_snapshot_error": status.LastSnapshotError,
+			"last_snapshot_url":   status.LastSnapshotURL,
+			"snapshot_url":        status.SnapshotURL,
+		}
+		for key, t := range map[string]time.Time{
+			"last_snapshot_start": status.LastSnapshotStart,
+			"last_snapshot_end":   status.LastSnapshotEnd,
+			"next_snapshot_start": status.NextSnapshotStart,
+			"snapshot_start":      status.SnapshotStart,
+		} {
+			if !t.IsZero() {
+				data[key] = t.Format(time.RFC3339)
+			}
+		}
+
+		return &logical.Response{
+			Data: data,
+		}, nil
+	}
+}
+
 func (b *SystemBackend) handleStorageRaftSnapshotWrite(force bool) framework.OperationFunc {
 	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 		raftStorage, ok := b.Core.underlyingPhysical.(*raft.RaftBackend)
@@ -666,6 +1042,35 @@ func (b *SystemBackend) handleStorageRaftSnapshotWrite(force bool) framework.Ope
 	}
 }
 
+func (b *SystemBackend) handleStorageRaftCompactUpdate() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		if b.Core.getRaftBackend() == nil {
+			return logical.ErrorResponse("raft storage is not in use"), logical.ErrInvalidRequest
+		}
+		if b.Core.isRaftHAOnly() {
+			return logical.ErrorResponse("raft is only used for ha_storage"), logical.ErrInvalidRequest
+		}
+
+		result, err := b.Core.compactRaftStorage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &logical.Response{
+			Data: map[string]interface{}{
+				"nodes":                  result.Nodes,
+				"leadership_transferred": result.LeadershipTransferred,
+			},
+		}
+		if result.Stats != nil {
+			resp.Data["size_before"] = result.Stats.SizeBefore
+			resp.Data["size_after"] = result.Stats.SizeAfter
+		}
+
+		return resp, nil
+	}
+}
+
 var sysRaftHelp = map[string][2]string{
 	"raft-bootstrap-challenge": {
 		"Creates a challenge for the new peer to be joined to the raft cluster.",
@@ -688,6 +1093,32 @@ var sysRaftHelp = map[string][2]string{
 		"Restores and saves snapshots from the raft cluster.",
 		"",
 	},
+	"raft-compact": {
+		"Compacts the database of the nodes of the raft cluster.",
+		`
+The database of each follower is compacted first, one node at a time. The
+active node then transfers leadership to another voter and compacts its own
+database once it is a standby, so that writes are never blocked. If there is
+no other voter, the active node compacts its database in place, blocking
+writes for the duration of the compaction.
+`,
+	},
+	"raft-snapshot-auto-config-list": {
+		"Lists the automatic snapshot configurations.",
+		"",
+	},
+	"raft-snapshot-auto-config": {
+		"Configures snapshots of the raft storage taken periodically by the active node.",
+		`
+Snapshots are saved to a local directory, an AWS S3 bucket, a Google Cloud
+Storage bucket or an Azure Blob Storage container. The oldest snapshots beyond
+the retention of the configuration are deleted.
+		`,
+	},
+	"raft-snapshot-auto-status": {
+		"Returns the status of the snapshots of an automatic snapshot configuration.",
+		"",
+	},
 	"raft-snapshot-force": {
 		"Force restore a raft cluster snapshot",
 		"",
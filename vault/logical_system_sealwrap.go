@@ -0,0 +1,242 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// sealWrapPaths returns paths that configure seal wrapping and rewrap the
+// stored entries
+func (b *SystemBackend) sealWrapPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "sealwrap/config$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "seal-wrap",
+				OperationSuffix: "configuration",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"enabled": {
+					Type:        framework.TypeBool,
+					Description: "Whether the selected entries are seal wrapped. Mounts enabled with seal_wrap are selected as a whole.",
+				},
+				"paths": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Prefixes of mount paths followed by the storage keys of the mount, selecting the entries to seal wrap.",
+				},
+				"wrap_plugin_storage": {
+					Type:        framework.TypeBool,
+					Description: "Whether the storage paths the plugins of the mounts ask to be seal wrapped are selected.",
+				},
+				"rewrap": {
+					Type:        framework.TypeBool,
+					Default:     true,
+					Description: "Whether to rewrap the stored entries under the new configuration in the background.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleSealWrapConfigRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"enabled": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+								"paths": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+								"wrap_plugin_storage": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+								"supported": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleSealWrapConfigUpdate,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "configure",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: http.StatusText(http.StatusNoContent),
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sealWrapHelp["config"][0]),
+			HelpDescription: strings.TrimSpace(sealWrapHelp["config"][1]),
+		},
+		{
+			Pattern: "sealwrap/rewrap$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "seal-wrap",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleSealWrapRewrapRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "read",
+						OperationSuffix: "rewrap-status",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"is_running": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+								"entries": {
+									Type:     framework.TypeMap,
+									Required: true,
+								},
+							},
+						}},
+					},
+					Summary: "Check the status of the seal rewrap process.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleSealWrapRewrapUpdate,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "rewrap",
+					},
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"is_running": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+								"entries": {
+									Type:     framework.TypeMap,
+									Required: true,
+								},
+							},
+						}},
+						http.StatusNoContent: {{
+							Description: http.StatusText(http.StatusNoContent),
+						}},
+					},
+					Summary: "Start a seal rewrap process.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sealWrapHelp["rewrap"][0]),
+			HelpDescription: strings.TrimSpace(sealWrapHelp["rewrap"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleSealWrapConfigRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.Core.sealWrapConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := config.Paths
+	if paths == nil {
+		paths = []string{}
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"enabled":             config.Enabled,
+			"paths":               paths,
+			"wrap_plugin_storage": config.WrapPluginStorage,
+			"supported":           b.Core.sealWrapAccess() != nil,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleSealWrapConfigUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.Core.sealWrapConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if enabled, ok := d.GetOk("enabled"); ok {
+		config.Enabled = enabled.(bool)
+	}
+	if paths, ok := d.GetOk("paths"); ok {
+		config.Paths = paths.([]string)
+	}
+	if wrapPluginStorage, ok := d.GetOk("wrap_plugin_storage"); ok {
+		config.WrapPluginStorage = wrapPluginStorage.(bool)
+	}
+
+	if err := b.Core.setSealWrapConfig(ctx, config); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if d.Get("rewrap").(bool) {
+		b.Core.startSealRewrap()
+	}
+
+	return nil, nil
+}
+
+func (b *SystemBackend) handleSealWrapRewrapRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	running, processed, succeeded, failed := b.Core.sealRewrapStatus()
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"is_running": running,
+			"entries": map[string]interface{}{
+				"processed": processed,
+				"succeeded": succeeded,
+				"failed":    failed,
+			},
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleSealWrapRewrapUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	// A process already running is reported like reading the status
+	if !b.Core.startSealRewrap() {
+		return b.handleSealWrapRewrapRead(ctx, req, d)
+	}
+	return nil, nil
+}
+
+var sealWrapHelp = map[string][2]string{
+	"config": {
+		"Configure which stored entries are seal wrapped.",
+		`Seal wrapped entries are encrypted by the seal on top of the barrier, so they
+never reach storage encrypted with only the barrier key. Seal wrapping requires
+an auto seal. Updating the configuration rewraps the stored entries in the
+background unless rewrap is false.`,
+	},
+	"rewrap": {
+		"Rewrap the stored entries under the seal wrap configuration.",
+		`Entries selected by the configuration are seal wrapped, entries wrapped with
+an older key of the seal are wrapped again with the current one, and entries
+no longer selected are unwrapped. Rewrapping runs in the background; reading
+the endpoint reports its progress.`,
+	},
+}
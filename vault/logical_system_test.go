@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -21,6 +22,7 @@ import (
 	"github.com/go-test/deep"
 	"github.com/hashicorp/go-hclog"
 	semver "github.com/hashicorp/go-version"
+	"github.com/hashicorp/vault/audit"
 	credUserpass "github.com/hashicorp/vault/builtin/credential/userpass"
 	"github.com/hashicorp/vault/helper/builtinplugins"
 	"github.com/hashicorp/vault/helper/experiments"
@@ -55,16 +57,24 @@ func TestSystemBackend_RootPaths(t *testing.T) {
 		"replication/dr/reindex",
 		"replication/performance/reindex",
 		"rotate",
+		"sealwrap/config",
+		"sealwrap/rewrap",
+		"wrapping/deliver",
 		"config/cors",
 		"config/auditing/*",
 		"config/ui/headers/*",
+		"config/reload/config",
 		"plugins/catalog/*",
+		"plugins/runtime/status",
+		"health/mounts",
 		"revoke-prefix/*",
 		"revoke-force/*",
 		"leases/revoke-prefix/*",
 		"leases/revoke-force/*",
 		"leases/lookup/*",
+		"storage/raft/compact",
 		"storage/raft/snapshot-auto/config/*",
+		"storage/cache/flush",
 		"leases",
 		"internal/inspect/*",
 	}
@@ -1551,6 +1561,86 @@ func TestSystemBackend_renew_invalidID(t *testing.T) {
 	}
 }
 
+func TestSystemBackend_renewBatch(t *testing.T) {
+	core, b, root := testCoreSystemBackend(t)
+
+	// Create a key with a lease
+	req := logical.TestRequest(t, logical.UpdateOperation, "secret/foo")
+	req.Data["foo"] = "bar"
+	req.Data["ttl"] = "180s"
+	req.ClientToken = root
+	if _, err := core.HandleRequest(namespace.RootContext(nil), req); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Read the key twice to get two leases
+	var leaseIDs []string
+	for i := 0; i < 2; i++ {
+		req = logical.TestRequest(t, logical.ReadOperation, "secret/foo")
+		req.ClientToken = root
+		if err := core.PopulateTokenEntry(namespace.RootContext(nil), req); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		resp, err := core.HandleRequest(namespace.RootContext(nil), req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if resp == nil || resp.Secret == nil || resp.Secret.LeaseID == "" {
+			t.Fatalf("bad: %#v", resp)
+		}
+		leaseIDs = append(leaseIDs, resp.Secret.LeaseID)
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "leases/renew-batch")
+	req.Data["lease_ids"] = append(leaseIDs, "foobarbaz")
+	resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	schema.ValidateResponse(
+		t,
+		schema.GetResponseSchema(t, b.(*SystemBackend).Route(req.Path), req.Operation),
+		resp,
+		true,
+	)
+
+	results := resp.Data["leases"].([]map[string]interface{})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, leaseID := range leaseIDs {
+		if results[i]["lease_id"] != leaseID {
+			t.Fatalf("bad lease ID: %v", results[i]["lease_id"])
+		}
+		if results[i]["error"] != nil {
+			t.Fatalf("unexpected error: %v", results[i]["error"])
+		}
+		if results[i]["lease_duration"] != int64(180) {
+			t.Fatalf("bad lease duration: %v", results[i]["lease_duration"])
+		}
+	}
+	if results[2]["error"] != "lease not found" {
+		t.Fatalf("bad: %v", results[2])
+	}
+
+	// Requests without leases or with too many leases are rejected
+	req = logical.TestRequest(t, logical.UpdateOperation, "leases/renew-batch")
+	if _, err := b.HandleRequest(namespace.RootContext(nil), req); err != logical.ErrInvalidRequest {
+		t.Fatalf("err: %v", err)
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "leases/renew-batch")
+	tooMany := make([]string, maxRenewBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("lease-%d", i)
+	}
+	req.Data["lease_ids"] = tooMany
+	if _, err := b.HandleRequest(namespace.RootContext(nil), req); err != logical.ErrInvalidRequest {
+		t.Fatalf("err: %v", err)
+	}
+}
+
 func TestSystemBackend_renew_invalidID_origUrl(t *testing.T) {
 	b := testSystemBackend(t)
 
@@ -2225,7 +2315,15 @@ func TestSystemBackend_tuneAuth(t *testing.T) {
 		if err := file.Close(); err != nil {
 			t.Fatal(err)
 		}
-		err = c.pluginCatalog.Set(context.Background(), "token", consts.PluginTypeCredential, "v1.0.0", "foo", []string{}, []string{}, []byte{})
+		err = c.pluginCatalog.Set(context.Background(), pluginutil.SetPluginInput{
+			Name:    "token",
+			Type:    consts.PluginTypeCredential,
+			Version: "v1.0.0",
+			Command: "foo",
+			Args:    []string{},
+			Env:     []string{},
+			Sha256:  []byte{},
+		})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -2523,6 +2621,89 @@ func TestSystemBackend_auditHash(t *testing.T) {
 	}
 }
 
+func TestSystemBackend_auditHashRotate(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	// Use the storage of the audit device for the salt
+	c.auditBackends["noop"] = func(_ context.Context, config *audit.BackendConfig) (audit.Backend, error) {
+		n, err := corehelpers.NewNoopAudit(config.Config)
+		if err != nil {
+			return nil, err
+		}
+		n.Config.SaltView = config.SaltView
+		n.Config.SaltConfig = config.SaltConfig
+		return n, nil
+	}
+	ctx := namespace.RootContext(nil)
+
+	handle := func(path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, path)
+		req.Data = data
+		resp, err := b.HandleRequest(ctx, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if resp != nil && !resp.IsError() {
+			schema.ValidateResponse(
+				t,
+				schema.GetResponseSchema(t, b.(*SystemBackend).Route(req.Path), req.Operation),
+				resp,
+				true,
+			)
+		}
+		return resp
+	}
+
+	handle("audit/foo", map[string]interface{}{"type": "noop"})
+
+	resp := handle("audit-hash/foo", map[string]interface{}{"input": "bar"})
+	oldHash := resp.Data["hash"].(string)
+	if _, ok := resp.Data["previous_hash"]; ok {
+		t.Fatalf("unexpected previous hash before rotating: %#v", resp.Data)
+	}
+
+	// Rewrapping requires a previous salt
+	resp = handle("audit-hash/rewrap", map[string]interface{}{"path": "foo", "input": "bar"})
+	if !resp.IsError() {
+		t.Fatalf("expected an error: %#v", resp)
+	}
+
+	resp = handle("audit-hash/rotate", map[string]interface{}{"path": "unknown"})
+	if !resp.IsError() {
+		t.Fatalf("expected an error: %#v", resp)
+	}
+
+	resp = handle("audit-hash/rotate", map[string]interface{}{"path": "foo", "overlap": "1h"})
+	if resp.Data["overlap_end"].(time.Time).Sub(resp.Data["rotated_at"].(time.Time)) != time.Hour {
+		t.Fatalf("bad overlap: %#v", resp.Data)
+	}
+
+	resp = handle("audit-hash/foo", map[string]interface{}{"input": "bar"})
+	newHash := resp.Data["hash"].(string)
+	if newHash == oldHash {
+		t.Fatal("hash did not change after rotating")
+	}
+	if resp.Data["previous_hash"] != oldHash {
+		t.Fatalf("bad previous hash: %#v", resp.Data)
+	}
+
+	resp = handle("audit-hash/rewrap", map[string]interface{}{"path": "foo", "input": "bar,baz"})
+	hashes := resp.Data["hashes"].([]map[string]interface{})
+	if len(hashes) != 2 || hashes[0]["previous_hash"] != oldHash || hashes[0]["hash"] != newHash {
+		t.Fatalf("bad hashes: %#v", hashes)
+	}
+
+	// Rotating without an overlap discards the previous salt
+	handle("audit-hash/rotate", map[string]interface{}{"path": "foo", "overlap": "0"})
+	resp = handle("audit-hash/foo", map[string]interface{}{"input": "bar"})
+	if resp.Data["hash"] == newHash {
+		t.Fatal("hash did not change after rotating")
+	}
+	if _, ok := resp.Data["previous_hash"]; ok {
+		t.Fatalf("unexpected previous hash: %#v", resp.Data)
+	}
+}
+
 func TestSystemBackend_enableAudit_invalid(t *testing.T) {
 	b := testSystemBackend(t)
 	req := logical.TestRequest(t, logical.UpdateOperation, "audit/foo")
@@ -3183,7 +3364,8 @@ func TestSystemBackend_keyStatus(t *testing.T) {
 	}
 
 	exp := map[string]interface{}{
-		"term": 1,
+		"term":                 1,
+		"remaining_operations": absoluteOperationMaximum - resp.Data["encryptions"].(int64),
 	}
 	delete(resp.Data, "install_time")
 	delete(resp.Data, "encryptions")
@@ -3192,6 +3374,42 @@ func TestSystemBackend_keyStatus(t *testing.T) {
 	}
 }
 
+func TestSystemBackend_keyStatusAutoRotation(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	if err := c.barrier.SetRotationConfig(ctx, KeyRotationConfig{
+		MaxOperations: absoluteOperationMinimum,
+		Interval:      minimumRotationInterval,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	c.barrier.(*AESGCMBarrier).UnaccountedEncryptions.Add(absoluteOperationMinimum)
+	c.checkBarrierAutoRotate(ctx)
+
+	req := logical.TestRequest(t, logical.ReadOperation, "key-status")
+	resp, err := c.systemBackend.HandleRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp.Data["term"] != 2 {
+		t.Fatalf("expected the key to be rotated, got: %#v", resp.Data)
+	}
+	if resp.Data["last_auto_rotation_reason"] != "reached max operations" {
+		t.Fatalf("unexpected last auto rotation: %#v", resp.Data)
+	}
+	if _, ok := resp.Data["last_auto_rotation_error"]; ok {
+		t.Fatalf("unexpected last auto rotation error: %#v", resp.Data)
+	}
+	installTime, err := time.Parse(time.RFC3339Nano, resp.Data["install_time"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["next_rotation_time"] != installTime.Add(minimumRotationInterval).Format(time.RFC3339Nano) {
+		t.Fatalf("unexpected next rotation time: %#v", resp.Data)
+	}
+}
+
 func TestSystemBackend_rotateConfig(t *testing.T) {
 	b := testSystemBackend(t)
 	req := logical.TestRequest(t, logical.ReadOperation, "rotate/config")
@@ -3272,7 +3490,8 @@ func TestSystemBackend_rotate(t *testing.T) {
 	}
 
 	exp := map[string]interface{}{
-		"term": 2,
+		"term":                 2,
+		"remaining_operations": absoluteOperationMaximum - resp.Data["encryptions"].(int64),
 	}
 	delete(resp.Data, "install_time")
 	delete(resp.Data, "encryptions")
@@ -3293,6 +3512,55 @@ func testSystemBackendRaw(t *testing.T) logical.Backend {
 	return c.systemBackend
 }
 
+func TestSystemBackend_storageCacheFlush(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	for _, key := range []string{"test/foo", "test/bar", "other/foo"} {
+		err := c.barrier.Put(ctx, &logical.StorageEntry{Key: key, Value: []byte("baz")})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Delete from under the cache
+		if err := c.sealUnwrapper.Delete(ctx, key); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "storage/cache/flush")
+	_, err := b.HandleRequest(ctx, req)
+	if err != logical.ErrInvalidRequest {
+		t.Fatalf("expected invalid request, got: %v", err)
+	}
+
+	req.Data["prefixes"] = []string{"test/"}
+	resp, err := b.HandleRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp.Data["flushed_entries"] != 2 {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	schema.ValidateResponse(
+		t,
+		schema.GetResponseSchema(t, b.(*SystemBackend).Route(req.Path), req.Operation),
+		resp,
+		true,
+	)
+
+	for key, cached := range map[string]bool{"test/foo": false, "test/bar": false, "other/foo": true} {
+		entry, err := c.barrier.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if (entry != nil) != cached {
+			t.Fatalf("bad: %s: expected cached %t", key, cached)
+		}
+	}
+}
+
 func testCoreSystemBackend(t *testing.T) (*Core, logical.Backend, string) {
 	t.Helper()
 	c, _, root := TestCoreUnsealed(t)
@@ -3535,6 +3803,98 @@ func TestSystemBackend_PluginCatalog_CannotRegisterBuiltinPlugins(t *testing.T)
 	}
 }
 
+func TestSystemBackend_PluginCatalog_InvalidLimits(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	sym, err := filepath.EvalSymlinks(os.TempDir())
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	c.pluginCatalog.directory = sym
+
+	for name, data := range map[string]map[string]interface{}{
+		"bad memory limit":      {"memory_limit": "lots"},
+		"negative memory limit": {"memory_limit": "-1"},
+		"negative cpu limit":    {"cpu_limit": -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := logical.TestRequest(t, logical.UpdateOperation, "plugins/catalog/secret/test-plugin")
+			req.Data = data
+			req.Data["sha256"] = hex.EncodeToString([]byte{'1'})
+			req.Data["command"] = "foo"
+			resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if !resp.IsError() {
+				t.Fatalf("expected an error response, got: %#v", resp)
+			}
+		})
+	}
+}
+
+func TestSystemBackend_PluginRuntimeStatus(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+
+	key := externalPluginsKey{
+		name:        "foo",
+		typ:         consts.PluginTypeSecrets,
+		version:     "v1.0.0",
+		memoryLimit: 1 << 20,
+	}
+	c.pluginCatalog.lock.Lock()
+	for i := 0; i < 2; i++ {
+		if err := c.pluginCatalog.recordPluginStart(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.pluginCatalog.recordPluginCrash(key, 100)
+	c.pluginCatalog.lock.Unlock()
+
+	req := logical.TestRequest(t, logical.ReadOperation, "plugins/runtime/status")
+	resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	schema.ValidateResponse(
+		t,
+		schema.GetResponseSchema(t, b.(*SystemBackend).Route(req.Path), req.Operation),
+		resp,
+		true,
+	)
+
+	plugins := resp.Data["plugins"].([]map[string]interface{})
+	if len(plugins) != 1 {
+		t.Fatalf("bad: %#v", plugins)
+	}
+	status := plugins[0]
+	if _, ok := status["last_crash"]; !ok {
+		t.Fatalf("expected the last crash, got: %#v", status)
+	}
+	if _, ok := status["backoff_until"]; !ok {
+		t.Fatalf("expected the backoff, got: %#v", status)
+	}
+	delete(status, "last_crash")
+	delete(status, "backoff_until")
+
+	expected := map[string]interface{}{
+		"name":         "foo",
+		"type":         "secret",
+		"version":      "v1.0.0",
+		"multiplexed":  false,
+		"connections":  0,
+		"starts":       2,
+		"restarts":     1,
+		"crashes":      1,
+		"memory_limit": int64(1 << 20),
+		"cpu_limit":    float64(0),
+		"processes":    []map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Fatalf("expected %#v, got: %#v", expected, status)
+	}
+}
+
 func TestSystemBackend_ToolsHash(t *testing.T) {
 	b := testSystemBackend(t)
 	req := logical.TestRequest(t, logical.UpdateOperation, "tools/hash")
@@ -5769,7 +6129,11 @@ func TestValidateVersion_HelpfulErrorWhenBuiltinOverridden(t *testing.T) {
 	defer file.Close()
 
 	command := filepath.Base(file.Name())
-	err = core.pluginCatalog.Set(context.Background(), "kubernetes", consts.PluginTypeCredential, "", command, nil, nil, nil)
+	err = core.pluginCatalog.Set(context.Background(), pluginutil.SetPluginInput{
+		Name:    "kubernetes",
+		Type:    consts.PluginTypeCredential,
+		Command: command,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -5911,3 +6275,87 @@ func TestSystemBackend_ReadExperiments(t *testing.T) {
 		})
 	}
 }
+
+func TestSystemBackend_wrappingDelivery(t *testing.T) {
+	core, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	handle := func(t *testing.T, token string, op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		return core.HandleRequest(ctx, &logical.Request{
+			Operation:   op,
+			Path:        path,
+			Data:        data,
+			ClientToken: token,
+		})
+	}
+	mustHandle := func(t *testing.T, token string, op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := handle(t, token, op, path, data)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v, resp: %#v", err, resp)
+		}
+		return resp
+	}
+
+	mustHandle(t, root, logical.UpdateOperation, "auth/token/roles/test", map[string]interface{}{
+		"allowed_policies":       "default",
+		"allowed_entity_aliases": "alice,bob",
+	})
+	resp := mustHandle(t, root, logical.UpdateOperation, "auth/token/create/test", map[string]interface{}{
+		"entity_alias": "alice",
+	})
+	alice, aliceID := resp.Auth.ClientToken, resp.Auth.EntityID
+	resp = mustHandle(t, root, logical.UpdateOperation, "auth/token/create/test", map[string]interface{}{
+		"entity_alias": "bob",
+	})
+	bob := resp.Auth.ClientToken
+
+	// Delivering requires sudo
+	_, err := handle(t, alice, logical.UpdateOperation, "sys/wrapping/deliver", map[string]interface{}{
+		"entity_id": aliceID,
+		"data":      map[string]interface{}{"foo": "bar"},
+	})
+	if !errors.Is(err, logical.ErrPermissionDenied) {
+		t.Fatalf("expected permission denied, got: %v", err)
+	}
+
+	resp = mustHandle(t, root, logical.UpdateOperation, "sys/wrapping/deliver", map[string]interface{}{
+		"entity_id": aliceID,
+		"data":      map[string]interface{}{"foo": "bar"},
+	})
+	accessor := resp.Data["accessor"].(string)
+	if _, ok := resp.Data["token"]; ok {
+		t.Fatal("expected the wrapping token not to be returned")
+	}
+
+	// Only tokens of the entity see the delivery
+	resp = mustHandle(t, bob, logical.ListOperation, "sys/wrapping/inbox", nil)
+	if resp != nil && resp.Data["keys"] != nil {
+		t.Fatalf("expected no deliveries, got: %#v", resp.Data)
+	}
+	resp = mustHandle(t, bob, logical.UpdateOperation, "sys/wrapping/inbox/"+accessor, nil)
+	if resp != nil {
+		t.Fatalf("expected no delivery, got: %#v", resp)
+	}
+
+	resp = mustHandle(t, alice, logical.ListOperation, "sys/wrapping/inbox", nil)
+	if keys := resp.Data["keys"].([]string); len(keys) != 1 || keys[0] != accessor {
+		t.Fatalf("unexpected deliveries: %#v", resp.Data)
+	}
+
+	resp = mustHandle(t, alice, logical.UpdateOperation, "sys/wrapping/inbox/"+accessor, nil)
+	httpResp := &logical.HTTPResponse{}
+	if err := jsonutil.DecodeJSON(resp.Data[logical.HTTPRawBody].([]byte), httpResp); err != nil {
+		t.Fatal(err)
+	}
+	if httpResp.Data["foo"] != "bar" {
+		t.Fatalf("unexpected unwrapped response: %#v", httpResp)
+	}
+
+	// Deliveries are unwrapped once
+	resp = mustHandle(t, alice, logical.UpdateOperation, "sys/wrapping/inbox/"+accessor, nil)
+	if resp != nil {
+		t.Fatalf("expected no delivery, got: %#v", resp)
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/wrapping"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// wrappingDeliveryPrefix is the prefix of the system view of a namespace
+// under which responses delivered to its entities are stored, keyed by
+// entity ID and the accessor of their wrapping token.
+const wrappingDeliveryPrefix = "wrapping/deliveries/"
+
+// defaultWrappingDeliveryTTL is the TTL of delivered responses unless set.
+// The recipient picks them up whenever it logs in, so it is longer than what
+// wrapping tokens are usually created with.
+const defaultWrappingDeliveryTTL = 24 * time.Hour
+
+// wrappingDelivery is a response wrapped for an entity. The wrapping token
+// never leaves Vault: the response is unwrapped on behalf of a token of the
+// entity.
+type wrappingDelivery struct {
+	Accessor       string    `json:"accessor"`
+	Token          string    `json:"token"`
+	EntityID       string    `json:"entity_id"`
+	SenderEntityID string    `json:"sender_entity_id"`
+	CreationTime   time.Time `json:"creation_time"`
+	ExpireTime     time.Time `json:"expire_time"`
+}
+
+// wrappingDeliveryPaths returns paths that deliver wrapped responses to
+// entities and let the entities unwrap them
+func (b *SystemBackend) wrappingDeliveryPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "wrapping/deliver$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "wrapping",
+				OperationVerb:   "deliver",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"entity_id": {
+					Type:        framework.TypeString,
+					Required:    true,
+					Description: "ID of the entity to deliver the response to.",
+				},
+				"data": {
+					Type:        framework.TypeMap,
+					Required:    true,
+					Description: "Data of the wrapped response.",
+				},
+				"ttl": {
+					Type:        framework.TypeDurationSecond,
+					Default:     int(defaultWrappingDeliveryTTL.Seconds()),
+					Description: "How long the entity has to unwrap the response.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleWrappingDeliver,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"accessor": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"entity_id": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"creation_time": {
+									Type:     framework.TypeTime,
+									Required: true,
+								},
+								"expire_time": {
+									Type:     framework.TypeTime,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["wrapping-deliver"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["wrapping-deliver"][1]),
+		},
+		{
+			Pattern: "wrapping/inbox/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "wrapping",
+				OperationSuffix: "inbox",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleWrappingInboxList,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type: framework.TypeStringSlice,
+								},
+								"key_info": {
+									Type: framework.TypeMap,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["wrapping-inbox"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["wrapping-inbox"][1]),
+		},
+		{
+			Pattern: "wrapping/inbox/" + framework.GenericNameRegex("accessor"),
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "wrapping",
+				OperationVerb:   "unwrap",
+				OperationSuffix: "delivery",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"accessor": {
+					Type:        framework.TypeString,
+					Description: "Accessor of the wrapping token of the delivered response.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleWrappingInboxUnwrap,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							// dynamic fields
+							Fields: nil,
+						}},
+						http.StatusNoContent: {{
+							Description: "No content",
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["wrapping-inbox"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["wrapping-inbox"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) wrappingDeliveryView(ns *namespace.Namespace, entityID string) (*BarrierView, error) {
+	view, err := b.Core.barrierViewForNamespace(ns.ID)
+	if err != nil {
+		return nil, err
+	}
+	return view.SubView(wrappingDeliveryPrefix + entityID + "/"), nil
+}
+
+func (b *SystemBackend) handleWrappingDeliver(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	// Fail early on performance standbys, so that the request is forwarded
+	// before a wrapping token is created
+	if b.Core.perfStandby {
+		return nil, logical.ErrReadOnly
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entityID := d.Get("entity_id").(string)
+	if entityID == "" {
+		return logical.ErrorResponse("missing entity_id"), logical.ErrInvalidRequest
+	}
+	entity, err := b.Core.identityStore.MemDBEntityByID(entityID, false)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil || entity.NamespaceID != ns.ID {
+		return logical.ErrorResponse("entity not found"), logical.ErrInvalidRequest
+	}
+
+	data := d.Get("data").(map[string]interface{})
+	if len(data) == 0 {
+		return logical.ErrorResponse("missing data"), logical.ErrInvalidRequest
+	}
+
+	ttl := time.Duration(d.Get("ttl").(int)) * time.Second
+	if ttl <= 0 {
+		return logical.ErrorResponse("ttl must be positive"), logical.ErrInvalidRequest
+	}
+	if maxTTL := b.System().MaxLeaseTTL(); ttl > maxTTL {
+		return logical.ErrorResponse(fmt.Sprintf("ttl must not be greater than the max lease TTL of %s", maxTTL)), logical.ErrInvalidRequest
+	}
+
+	// Wrap the data the way wrapping tokens are created for any response
+	wrapped := &logical.Response{
+		Data: data,
+		WrapInfo: &wrapping.ResponseWrapInfo{
+			TTL:    ttl,
+			Format: "uuid",
+		},
+	}
+	cubbyResp, err := b.Core.wrapInCubbyhole(ctx, req, wrapped, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cubbyResp != nil {
+		return cubbyResp, nil
+	}
+
+	delivery := &wrappingDelivery{
+		Accessor:       wrapped.WrapInfo.Accessor,
+		Token:          wrapped.WrapInfo.Token,
+		EntityID:       entity.ID,
+		SenderEntityID: req.EntityID,
+		CreationTime:   wrapped.WrapInfo.CreationTime,
+		ExpireTime:     wrapped.WrapInfo.CreationTime.Add(ttl),
+	}
+
+	view, err := b.wrappingDeliveryView(ns, entity.ID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := logical.StorageEntryJSON(delivery.Accessor, delivery)
+	if err != nil {
+		return nil, err
+	}
+	if err := view.Put(ctx, entry); err != nil {
+		b.Core.tokenStore.revokeOrphan(ctx, delivery.Token)
+		return nil, err
+	}
+
+	b.Core.sendCoreEvent(ctx, eventTypeWrappingDelivered, "entity_id", entity.ID, "accessor", delivery.Accessor)
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"accessor":      delivery.Accessor,
+			"entity_id":     delivery.EntityID,
+			"creation_time": delivery.CreationTime.Format(time.RFC3339Nano),
+			"expire_time":   delivery.ExpireTime.Format(time.RFC3339Nano),
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleWrappingInboxList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if req.EntityID == "" {
+		return logical.ErrorResponse("token is not associated with an entity"), logical.ErrInvalidRequest
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	view, err := b.wrappingDeliveryView(ns, req.EntityID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessors, err := view.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var keys []string
+	keyInfo := make(map[string]interface{})
+	for _, accessor := range accessors {
+		delivery, err := b.wrappingDelivery(ctx, view, accessor)
+		if err != nil {
+			return nil, err
+		}
+		if delivery == nil || now.After(delivery.ExpireTime) {
+			continue
+		}
+
+		keys = append(keys, delivery.Accessor)
+		keyInfo[delivery.Accessor] = map[string]interface{}{
+			"sender_entity_id": delivery.SenderEntityID,
+			"creation_time":    delivery.CreationTime.Format(time.RFC3339Nano),
+			"expire_time":      delivery.ExpireTime.Format(time.RFC3339Nano),
+		}
+	}
+	sort.Strings(keys)
+
+	return logical.ListResponseWithInfo(keys, keyInfo), nil
+}
+
+func (b *SystemBackend) handleWrappingInboxUnwrap(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if req.EntityID == "" {
+		return logical.ErrorResponse("token is not associated with an entity"), logical.ErrInvalidRequest
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	view, err := b.wrappingDeliveryView(ns, req.EntityID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessor := d.Get("accessor").(string)
+	delivery, err := b.wrappingDelivery(ctx, view, accessor)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, nil
+	}
+
+	// Like the wrapping token, the delivery can only be unwrapped once
+	if err := view.Delete(ctx, accessor); err != nil {
+		return nil, err
+	}
+
+	te, err := b.Core.tokenStore.Lookup(ctx, delivery.Token)
+	if err != nil {
+		return nil, err
+	}
+	if te == nil {
+		return logical.ErrorResponse("delivered response has expired"), logical.ErrInvalidRequest
+	}
+
+	response, err := b.responseWrappingUnwrap(ctx, te, true)
+	if err != nil {
+		var respErr *logical.Response
+		if len(response) > 0 {
+			respErr = logical.ErrorResponse(response)
+		}
+
+		return respErr, err
+	}
+
+	return b.unwrappedResponse(response)
+}
+
+func (b *SystemBackend) wrappingDelivery(ctx context.Context, view *BarrierView, accessor string) (*wrappingDelivery, error) {
+	entry, err := view.Get(ctx, accessor)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	delivery := new(wrappingDelivery)
+	if err := entry.DecodeJSON(delivery); err != nil {
+		return nil, fmt.Errorf("failed to decode wrapping delivery %q: %w", accessor, err)
+	}
+	return delivery, nil
+}
@@ -50,6 +50,7 @@ const (
 	mfaMethodTypeDuo               = "duo"
 	mfaMethodTypeOkta              = "okta"
 	mfaMethodTypePingID            = "pingid"
+	mfaMethodTypeWebhook           = "webhook"
 	memDBLoginMFAConfigsTable      = "login_mfa_configs"
 	memDBMFALoginEnforcementsTable = "login_enforcements"
 	mfaTOTPKeysPrefix              = systemBarrierPrefix + "mfa/totpkeys/"
@@ -67,6 +68,7 @@ type totpKey struct {
 // loginMfaPaths returns the API endpoints to configure the new style
 // login MFA. The following paths are supported:
 // mfa/method/:mfa_method - management of MFA method IDs, which can be used for configuration
+// mfa/webhook/callback - receives the decisions of the approver services of webhook methods
 // mfa/login_enforcement/:config_name - configures single or two phase MFA auth
 func (b *SystemBackend) loginMFAPaths() []*framework.Path {
 	return []*framework.Path{
@@ -103,6 +105,49 @@ func (b *SystemBackend) loginMFAPaths() []*framework.Path {
 				},
 			},
 		},
+		{
+			Pattern: "mfa/webhook/callback",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "mfa",
+				OperationVerb:   "approve",
+				OperationSuffix: "webhook-login",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"approval_id": {
+					Type:        framework.TypeString,
+					Description: "ID of the approval request sent to the approver service",
+					Required:    true,
+				},
+				"approved": {
+					Type:        framework.TypeBool,
+					Description: "Whether the login is approved",
+				},
+				"approver": {
+					Type:        framework.TypeString,
+					Description: "Identity of the approver, recorded in the metadata of the token of approved logins",
+					Required:    true,
+				},
+				"signature": {
+					Type:        framework.TypeString,
+					Description: `Hex encoded HMAC-SHA256 of "<approval_id>:<approved|denied>:<approver>", keyed with the signing key of the MFA method`,
+					Required:    true,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.Core.loginMFABackend.handleMFAWebhookCallback,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+						}},
+					},
+					Summary:                   "Approves or denies a login waiting for the approval of a webhook MFA method",
+					ForwardPerformanceStandby: true,
+				},
+			},
+		},
 	}
 }
 
@@ -122,6 +167,9 @@ type MFABackend struct {
 
 type LoginMFABackend struct {
 	*MFABackend
+
+	webhookApprovalsLock sync.Mutex
+	webhookApprovals     map[string]*webhookApproval
 }
 
 func loginMFASchemaFuncs() []func() *memdb.TableSchema {
@@ -133,7 +181,10 @@ func loginMFASchemaFuncs() []func() *memdb.TableSchema {
 
 func NewLoginMFABackend(core *Core, logger hclog.Logger) *LoginMFABackend {
 	b := NewMFABackend(core, logger, memDBLoginMFAConfigsTable, loginMFASchemaFuncs())
-	return &LoginMFABackend{b}
+	return &LoginMFABackend{
+		MFABackend:       b,
+		webhookApprovals: make(map[string]*webhookApproval),
+	}
 }
 
 func NewMFABackend(core *Core, logger hclog.Logger, prefix string, schemaFuncs []func() *memdb.TableSchema) *MFABackend {
@@ -197,6 +248,10 @@ func (i *IdentityStore) handleMFAMethodListPingID(ctx context.Context, req *logi
 	return i.handleMFAMethodList(ctx, req, d, mfaMethodTypePingID)
 }
 
+func (i *IdentityStore) handleMFAMethodListWebhook(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleMFAMethodList(ctx, req, d, mfaMethodTypeWebhook)
+}
+
 func (i *IdentityStore) handleMFAMethodListGlobal(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	keys, configInfo, err := i.mfaBackend.mfaMethodList(ctx, "")
 	if err != nil {
@@ -231,6 +286,10 @@ func (i *IdentityStore) handleMFAMethodPingIDRead(ctx context.Context, req *logi
 	return i.handleMFAMethodReadCommon(ctx, req, d, mfaMethodTypePingID)
 }
 
+func (i *IdentityStore) handleMFAMethodWebhookRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleMFAMethodReadCommon(ctx, req, d, mfaMethodTypeWebhook)
+}
+
 func (i *IdentityStore) handleMFAMethodReadGlobal(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	return i.handleMFAMethodReadCommon(ctx, req, d, "")
 }
@@ -378,6 +437,12 @@ func (i *IdentityStore) handleMFAMethodUpdateCommon(ctx context.Context, req *lo
 			return logical.ErrorResponse(err.Error()), nil
 		}
 
+	case mfaMethodTypeWebhook:
+		err = parseWebhookConfig(mConfig, d)
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
+
 	default:
 		return logical.ErrorResponse(fmt.Sprintf("unrecognized type %q", methodType)), nil
 	}
@@ -421,6 +486,10 @@ func (i *IdentityStore) handleMFAMethodPingIDUpdate(ctx context.Context, req *lo
 	return i.handleMFAMethodUpdateCommon(ctx, req, d, mfaMethodTypePingID)
 }
 
+func (i *IdentityStore) handleMFAMethodWebhookUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleMFAMethodUpdateCommon(ctx, req, d, mfaMethodTypeWebhook)
+}
+
 func (i *IdentityStore) handleMFAMethodTOTPDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	return i.handleMFAMethodDeleteCommon(ctx, req, d, mfaMethodTypeTOTP)
 }
@@ -437,6 +506,10 @@ func (i *IdentityStore) handleMFAMethodPingIDDelete(ctx context.Context, req *lo
 	return i.handleMFAMethodDeleteCommon(ctx, req, d, mfaMethodTypePingID)
 }
 
+func (i *IdentityStore) handleMFAMethodWebhookDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return i.handleMFAMethodDeleteCommon(ctx, req, d, mfaMethodTypeWebhook)
+}
+
 func (i *IdentityStore) handleMFAMethodDeleteCommon(ctx context.Context, req *logical.Request, d *framework.FieldData, methodType string) (*logical.Response, error) {
 	methodID := d.Get("method_id").(string)
 	if methodID == "" {
@@ -795,7 +868,7 @@ func (b *LoginMFABackend) handleMFALoginValidate(ctx context.Context, req *logic
 	}
 
 	for _, eConfig := range matchedMfaEnforcementList {
-		err = b.Core.validateLoginMFA(ctx, eConfig, entity, req.Connection.RemoteAddr, mfaCreds)
+		err = b.Core.validateLoginMFA(ctx, eConfig, entity, req.Connection.RemoteAddr, mfaCreds, cachedResponseAuth.CachedAuth)
 		if err != nil {
 			return logical.ErrorResponse(fmt.Sprintf("failed to satisfy enforcement %s. error: %s", eConfig.Name, err.Error())), logical.ErrPermissionDenied
 		}
@@ -1502,6 +1575,12 @@ func (b *MFABackend) mfaConfigToMap(mConfig *mfa.Config) (map[string]interface{}
 		respData["org_alias"] = pingConfig.OrgAlias
 		respData["admin_url"] = pingConfig.AdminURL
 		respData["authenticator_url"] = pingConfig.AuthenticatorURL
+	case *mfa.Config_WebhookConfig:
+		webhookConfig := mConfig.GetWebhookConfig()
+		respData["url"] = webhookConfig.URL
+		respData["ca_cert"] = webhookConfig.CACert
+		respData["timeout"] = webhookConfig.Timeout
+		respData["username_format"] = mConfig.UsernameFormat
 	default:
 		return nil, fmt.Errorf("invalid method type %q was persisted, underlying type: %T", mConfig.Type, mConfig.Config)
 	}
@@ -1659,7 +1738,7 @@ func parseOktaConfig(mConfig *mfa.Config, d *framework.FieldData) error {
 	return nil
 }
 
-func (c *Core) validateLoginMFA(ctx context.Context, eConfig *mfa.MFAEnforcementConfig, entity *identity.Entity, requestConnRemoteAddr string, mfaCredsMap logical.MFACreds) error {
+func (c *Core) validateLoginMFA(ctx context.Context, eConfig *mfa.MFAEnforcementConfig, entity *identity.Entity, requestConnRemoteAddr string, mfaCredsMap logical.MFACreds, auth *logical.Auth) error {
 	sanitizedMfaCreds, err := c.loginMFABackend.sanitizeMFACredsWithLoginEnforcementMethodIDs(ctx, mfaCredsMap, eConfig.MFAMethodIDs)
 	if err != nil {
 		return fmt.Errorf("failed to sanitize MFA creds, %w", err)
@@ -1677,7 +1756,7 @@ func (c *Core) validateLoginMFA(ctx context.Context, eConfig *mfa.MFAEnforcement
 			continue
 		}
 
-		err := c.validateLoginMFAInternal(ctx, methodID, entity, requestConnRemoteAddr, mfaCreds)
+		err := c.validateLoginMFAInternal(ctx, methodID, entity, requestConnRemoteAddr, mfaCreds, auth)
 		if err != nil {
 			retErr = multierror.Append(retErr, err)
 			continue
@@ -1688,7 +1767,7 @@ func (c *Core) validateLoginMFA(ctx context.Context, eConfig *mfa.MFAEnforcement
 	return multierror.Append(retErr, fmt.Errorf("login MFA validation failed for methodID: %v", eConfig.MFAMethodIDs))
 }
 
-func (c *Core) validateLoginMFAInternal(ctx context.Context, methodID string, entity *identity.Entity, reqConnectionRemoteAddress string, mfaCreds []string) (retErr error) {
+func (c *Core) validateLoginMFAInternal(ctx context.Context, methodID string, entity *identity.Entity, reqConnectionRemoteAddress string, mfaCreds []string, auth *logical.Auth) (retErr error) {
 	if entity == nil {
 		return fmt.Errorf("entity is nil")
 	}
@@ -1705,7 +1784,7 @@ func (c *Core) validateLoginMFAInternal(ctx context.Context, methodID string, en
 
 	var finalUsername string
 	switch mConfig.Type {
-	case mfaMethodTypeDuo, mfaMethodTypeOkta, mfaMethodTypePingID:
+	case mfaMethodTypeDuo, mfaMethodTypeOkta, mfaMethodTypePingID, mfaMethodTypeWebhook:
 		if mConfig.UsernameFormat == "" {
 			finalUsername = entity.Name
 		} else {
@@ -1755,6 +1834,9 @@ func (c *Core) validateLoginMFAInternal(ctx context.Context, methodID string, en
 	case mfaMethodTypePingID:
 		return c.validatePingID(ctx, mConfig, finalUsername)
 
+	case mfaMethodTypeWebhook:
+		return c.validateWebhook(ctx, mConfig, entity, finalUsername, reqConnectionRemoteAddress, auth)
+
 	default:
 		return fmt.Errorf("unrecognized MFA type %q", mConfig.Type)
 	}
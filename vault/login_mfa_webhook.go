@@ -0,0 +1,262 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/identity/mfa"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// defaultWebhookMFATimeout is how long a login waits for the approver
+	// service to approve or deny it if the method doesn't configure a timeout.
+	defaultWebhookMFATimeout = 60 * time.Second
+
+	// webhookMFASignatureHeader is the header of the approval requests holding
+	// the HMAC-SHA256 of their body, keyed with the signing key of the method.
+	webhookMFASignatureHeader = "X-Vault-Signature"
+
+	// webhookMFAApproverMetadataKey is the auth metadata key the identity of
+	// the approver of a login is recorded under.
+	webhookMFAApproverMetadataKey = "mfa_approver"
+)
+
+// webhookApproval is a login waiting for the approver service to call back.
+type webhookApproval struct {
+	methodID   string
+	signingKey string
+	resultCh   chan webhookApprovalResult
+}
+
+type webhookApprovalResult struct {
+	approved bool
+	approver string
+}
+
+func parseWebhookConfig(mConfig *mfa.Config, d *framework.FieldData) error {
+	webhookURL := d.Get("url").(string)
+	if webhookURL == "" {
+		return errors.New("url must be set")
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid url %q", webhookURL)
+	}
+
+	signingKey := d.Get("signing_key").(string)
+	if signingKey == "" {
+		return errors.New("signing_key must be set")
+	}
+
+	caCert := d.Get("ca_cert").(string)
+	if caCert != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(caCert)) {
+			return errors.New("failed to parse ca_cert")
+		}
+	}
+
+	timeout := d.Get("timeout").(int)
+	if timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+
+	mConfig.Config = &mfa.Config_WebhookConfig{
+		WebhookConfig: &mfa.WebhookConfig{
+			URL:        webhookURL,
+			CACert:     caCert,
+			SigningKey: signingKey,
+			Timeout:    int64(timeout),
+		},
+	}
+
+	return nil
+}
+
+// webhookMFASignature returns the hex encoded HMAC-SHA256 of the message keyed
+// with the signing key.
+func webhookMFASignature(signingKey string, message []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write(message)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookMFACallbackMessage returns the message signed by the approver service
+// when calling back.
+func webhookMFACallbackMessage(approvalID string, approved bool, approver string) []byte {
+	decision := "denied"
+	if approved {
+		decision = "approved"
+	}
+	return []byte(approvalID + ":" + decision + ":" + approver)
+}
+
+// validateWebhook sends an approval request for the login to the approver
+// service of the method and waits for the service to call back with its
+// decision. The identity of the approver is recorded in the metadata of the
+// auth of the login.
+func (c *Core) validateWebhook(ctx context.Context, mConfig *mfa.Config, entity *identity.Entity, username, reqConnectionRemoteAddr string, auth *logical.Auth) error {
+	webhookConfig := mConfig.GetWebhookConfig()
+	if webhookConfig == nil {
+		return fmt.Errorf("failed to get webhook configuration for method %q", mConfig.Name)
+	}
+
+	timeout := defaultWebhookMFATimeout
+	if webhookConfig.Timeout > 0 {
+		timeout = time.Duration(webhookConfig.Timeout) * time.Second
+	}
+
+	approvalID, err := uuid.GenerateUUID()
+	if err != nil {
+		return fmt.Errorf("failed to generate approval ID: %w", err)
+	}
+
+	b := c.loginMFABackend
+	approval := &webhookApproval{
+		methodID:   mConfig.ID,
+		signingKey: webhookConfig.SigningKey,
+		resultCh:   make(chan webhookApprovalResult, 1),
+	}
+	b.webhookApprovalsLock.Lock()
+	b.webhookApprovals[approvalID] = approval
+	b.webhookApprovalsLock.Unlock()
+	defer func() {
+		b.webhookApprovalsLock.Lock()
+		delete(b.webhookApprovals, approvalID)
+		b.webhookApprovalsLock.Unlock()
+	}()
+
+	approvalRequest := map[string]interface{}{
+		"approval_id":   approvalID,
+		"method_id":     mConfig.ID,
+		"method_name":   mConfig.Name,
+		"username":      username,
+		"entity_id":     entity.ID,
+		"entity_name":   entity.Name,
+		"remote_addr":   reqConnectionRemoteAddr,
+		"expires_at":    time.Now().Add(timeout).UTC().Format(time.RFC3339),
+		"callback_path": "sys/mfa/webhook/callback",
+	}
+	if c.redirectAddr != "" {
+		approvalRequest["callback_url"] = c.redirectAddr + "/v1/sys/mfa/webhook/callback"
+	}
+	body, err := json.Marshal(approvalRequest)
+	if err != nil {
+		return err
+	}
+
+	client := cleanhttp.DefaultClient()
+	client.Timeout = 30 * time.Second
+	if webhookConfig.CACert != "" {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM([]byte(webhookConfig.CACert))
+		client.Transport.(*http.Transport).TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookConfig.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookMFASignatureHeader, webhookMFASignature(webhookConfig.SigningKey, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send approval request: %w", err)
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("approver service responded with status code %d", resp.StatusCode)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-approval.resultCh:
+		if !result.approved {
+			return fmt.Errorf("login request was denied by %q", result.approver)
+		}
+
+		c.logger.Info("login request approved", "method_id", mConfig.ID, "entity_id", entity.ID, "approver", result.approver)
+		if auth != nil {
+			if auth.Metadata == nil {
+				auth.Metadata = make(map[string]string)
+			}
+			auth.Metadata[webhookMFAApproverMetadataKey] = result.approver
+		}
+		return nil
+
+	case <-timer.C:
+		return errors.New("timed out waiting for the login request to be approved")
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handleMFAWebhookCallback receives the decision of the approver service on a
+// login waiting for approval.
+func (b *LoginMFABackend) handleMFAWebhookCallback(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	approvalID := d.Get("approval_id").(string)
+	if approvalID == "" {
+		return logical.ErrorResponse("missing approval_id"), nil
+	}
+	approver := d.Get("approver").(string)
+	if approver == "" {
+		return logical.ErrorResponse("missing approver"), nil
+	}
+	approved := d.Get("approved").(bool)
+	signature := d.Get("signature").(string)
+
+	b.webhookApprovalsLock.Lock()
+	approval, ok := b.webhookApprovals[approvalID]
+	if ok {
+		expected := webhookMFASignature(approval.signingKey, webhookMFACallbackMessage(approvalID, approved, approver))
+		if !hmac.Equal([]byte(expected), []byte(signature)) {
+			ok = false
+		} else {
+			// Each approval request can only be decided once
+			delete(b.webhookApprovals, approvalID)
+		}
+	}
+	b.webhookApprovalsLock.Unlock()
+
+	if !ok {
+		return logical.ErrorResponse("unknown approval_id or invalid signature"), logical.ErrPermissionDenied
+	}
+
+	approval.resultCh <- webhookApprovalResult{
+		approved: approved,
+		approver: approver,
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"method_id": approval.methodID,
+		},
+	}, nil
+}
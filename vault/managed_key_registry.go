@@ -5,16 +5,492 @@
 
 package vault
 
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/command/server"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/strutil"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault/managedkeys"
+)
+
 // managedKeyRegistrySubPath is the storage prefix used by the registry.
-// We need to define the constant even though managed keys is a Vault Enterprise
-// feature in order to set up seal wrapping in the SystemBackend.
 const managedKeyRegistrySubPath = "managed-key-registry/"
 
+const (
+	managedKeyNamePrefix = "name/"
+	managedKeyUUIDPrefix = "uuid/"
+)
+
+// Usages of managed keys. Managed keys can only sign and verify.
+const (
+	managedKeyUsageSign   = "sign"
+	managedKeyUsageVerify = "verify"
+)
+
+var (
+	errManagedKeyNotFound   = errors.New("managed key not found")
+	errManagedKeyNotAllowed = errors.New("managed key is not allowed for this mount")
+	errManagedKeySignOnly   = errors.New("managed keys only support signing")
+)
+
+// managedKeyEntry is a managed key as stored in the registry. The key itself
+// stays in the KMS, the entry only holds how to reach it.
+type managedKeyEntry struct {
+	UUID     string            `json:"uuid"`
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Usages   []string          `json:"usages"`
+	AnyMount bool              `json:"any_mount"`
+	Config   map[string]string `json:"config"`
+}
+
+// managedKeyRegistry holds the managed keys of every namespace, and caches
+// the signers of the keys in use.
+type managedKeyRegistry struct {
+	core   *Core
+	logger hclog.Logger
+
+	// newSigner creates the signers of the keys, and is replaced by tests
+	// that can't reach a KMS.
+	newSigner func(ctx context.Context, keyType string, config map[string]string, libraries map[string]string) (managedkeys.Signer, error)
+
+	l       sync.Mutex
+	signers map[string]*managedKeySigner
+}
+
 func (c *Core) setupManagedKeyRegistry() error {
-	// Nothing to do, the registry is only used by enterprise features
+	logger := c.baseLogger.Named("managed-keys")
+	c.AddLogger(logger)
+
+	c.managedKeyRegistry = &managedKeyRegistry{
+		core:      c,
+		logger:    logger,
+		newSigner: managedkeys.New,
+		signers:   make(map[string]*managedKeySigner),
+	}
 	return nil
 }
 
+func (c *Core) teardownManagedKeyRegistry() error {
+	if c.managedKeyRegistry == nil {
+		return nil
+	}
+	c.managedKeyRegistry.closeSigners(func(*managedKeyEntry) bool { return true })
+	c.managedKeyRegistry = nil
+	return nil
+}
+
+// ReloadManagedKeyRegistryConfig closes the signers of the keys using a
+// kms_library, so that they are created again with the reloaded libraries.
 func (c *Core) ReloadManagedKeyRegistryConfig() {
-	// Nothing to do, the registry is only used by enterprise features
+	c.stateLock.RLock()
+	defer c.stateLock.RUnlock()
+
+	if c.managedKeyRegistry == nil {
+		return
+	}
+	c.managedKeyRegistry.closeSigners(func(entry *managedKeyEntry) bool {
+		return entry.Type == managedkeys.TypePKCS11
+	})
+}
+
+// kmsLibraries returns the paths of the kms_library stanzas of the server
+// configuration by name.
+func (c *Core) kmsLibraries() map[string]string {
+	libraries := make(map[string]string)
+	conf := c.rawConfig.Load()
+	if conf == nil {
+		return libraries
+	}
+	for _, lib := range conf.(*server.Config).KMSLibraries {
+		libraries[lib.Name] = lib.Library
+	}
+	return libraries
+}
+
+func (r *managedKeyRegistry) view(ns *namespace.Namespace) (*BarrierView, error) {
+	view, err := r.core.barrierViewForNamespace(ns.ID)
+	if err != nil {
+		return nil, err
+	}
+	return view.SubView(managedKeyRegistrySubPath), nil
+}
+
+// list returns the names of the keys of the type in the namespace.
+func (r *managedKeyRegistry) list(ctx context.Context, ns *namespace.Namespace, keyType string) ([]string, error) {
+	view, err := r.view(ns)
+	if err != nil {
+		return nil, err
+	}
+	names, err := view.List(ctx, managedKeyNamePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, name := range names {
+		entry, err := r.get(ctx, ns, name)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil && entry.Type == keyType {
+			keys = append(keys, name)
+		}
+	}
+	return keys, nil
+}
+
+// get returns the key with the name in the namespace, or nil if there is
+// none.
+func (r *managedKeyRegistry) get(ctx context.Context, ns *namespace.Namespace, name string) (*managedKeyEntry, error) {
+	view, err := r.view(ns)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := view.Get(ctx, managedKeyNamePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	entry := &managedKeyEntry{}
+	if err := jsonutil.DecodeJSON(raw.Value, entry); err != nil {
+		return nil, fmt.Errorf("failed to decode managed key %q: %w", name, err)
+	}
+	return entry, nil
+}
+
+// getByUUID returns the key with the UUID in the namespace, or nil if there
+// is none.
+func (r *managedKeyRegistry) getByUUID(ctx context.Context, ns *namespace.Namespace, keyUUID string) (*managedKeyEntry, error) {
+	view, err := r.view(ns)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := view.Get(ctx, managedKeyUUIDPrefix+keyUUID)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	entry, err := r.get(ctx, ns, string(raw.Value))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || entry.UUID != keyUUID {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+// put stores the key, generating its UUID if it is new, and closes the
+// signer of its previous configuration.
+func (r *managedKeyRegistry) put(ctx context.Context, ns *namespace.Namespace, entry *managedKeyEntry) error {
+	view, err := r.view(ns)
+	if err != nil {
+		return err
+	}
+
+	if entry.UUID == "" {
+		if entry.UUID, err = uuid.GenerateUUID(); err != nil {
+			return err
+		}
+		if err := view.Put(ctx, &logical.StorageEntry{
+			Key:   managedKeyUUIDPrefix + entry.UUID,
+			Value: []byte(entry.Name),
+		}); err != nil {
+			return err
+		}
+	}
+
+	raw, err := logical.StorageEntryJSON(managedKeyNamePrefix+entry.Name, entry)
+	if err != nil {
+		return err
+	}
+	if err := view.Put(ctx, raw); err != nil {
+		return err
+	}
+
+	r.closeSigners(func(e *managedKeyEntry) bool { return e.UUID == entry.UUID })
+	return nil
+}
+
+// delete removes the key and closes its signer.
+func (r *managedKeyRegistry) delete(ctx context.Context, ns *namespace.Namespace, entry *managedKeyEntry) error {
+	view, err := r.view(ns)
+	if err != nil {
+		return err
+	}
+	if err := view.Delete(ctx, managedKeyNamePrefix+entry.Name); err != nil {
+		return err
+	}
+	if err := view.Delete(ctx, managedKeyUUIDPrefix+entry.UUID); err != nil {
+		return err
+	}
+
+	r.closeSigners(func(e *managedKeyEntry) bool { return e.UUID == entry.UUID })
+	return nil
+}
+
+// signer returns the signer of the key, creating it on first use.
+func (r *managedKeyRegistry) signer(ctx context.Context, entry *managedKeyEntry) (managedkeys.Signer, error) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if s, ok := r.signers[entry.UUID]; ok {
+		return s, nil
+	}
+	s, err := r.newSigner(ctx, entry.Type, entry.Config, r.core.kmsLibraries())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach managed key %q: %w", entry.Name, err)
+	}
+	r.signers[entry.UUID] = &managedKeySigner{Signer: s, entry: entry}
+	return r.signers[entry.UUID], nil
+}
+
+// closeSigners closes the cached signers of the keys matching the filter.
+func (r *managedKeyRegistry) closeSigners(filter func(*managedKeyEntry) bool) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	for id, s := range r.signers {
+		if !filter(s.entry) {
+			continue
+		}
+		if err := s.Close(); err != nil {
+			r.logger.Warn("failed to close managed key", "name", s.entry.Name, "error", err)
+		}
+		delete(r.signers, id)
+	}
+}
+
+// mountsUsing returns the paths of the mounts of the namespace allowed to use
+// the key by name or UUID.
+func (r *managedKeyRegistry) mountsUsing(ns *namespace.Namespace, entry *managedKeyEntry) []string {
+	r.core.mountsLock.RLock()
+	defer r.core.mountsLock.RUnlock()
+	r.core.authLock.RLock()
+	defer r.core.authLock.RUnlock()
+
+	var paths []string
+	for _, table := range []*MountTable{r.core.mounts, r.core.auth} {
+		if table == nil {
+			continue
+		}
+		for _, me := range table.Entries {
+			if me.NamespaceID != ns.ID {
+				continue
+			}
+			allowed := me.Config.AllowedManagedKeys
+			if strutil.StrListContains(allowed, entry.Name) || strutil.StrListContains(allowed, entry.UUID) {
+				paths = append(paths, me.Path)
+			}
+		}
+	}
+	return paths
+}
+
+// managedKeySigner remembers the entry a cached signer was created for.
+type managedKeySigner struct {
+	managedkeys.Signer
+	entry *managedKeyEntry
+}
+
+// managedKey is a managed key handed to a mount through its system view.
+type managedKey struct {
+	entry  *managedKeyEntry
+	signer managedkeys.Signer
+}
+
+var _ logical.ManagedSigningKey = (*managedKey)(nil)
+
+func (k *managedKey) Name() string {
+	return k.entry.Name
+}
+
+func (k *managedKey) UUID() string {
+	return k.entry.UUID
+}
+
+func (k *managedKey) Present(ctx context.Context) (bool, error) {
+	return k.signer != nil, nil
+}
+
+func (k *managedKey) AllowsAll(usages []logical.KeyUsage) bool {
+	for _, usage := range usages {
+		switch usage {
+		case logical.KeyUsageSign:
+			if !strutil.StrListContains(k.entry.Usages, managedKeyUsageSign) {
+				return false
+			}
+		case logical.KeyUsageVerify:
+			if !strutil.StrListContains(k.entry.Usages, managedKeyUsageVerify) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (k *managedKey) GetPublicKey(ctx context.Context) (crypto.PublicKey, error) {
+	return k.signer.Public(), nil
+}
+
+func (k *managedKey) Sign(ctx context.Context, value []byte, _ io.Reader, opts crypto.SignerOpts) ([]byte, error) {
+	if !k.AllowsAll([]logical.KeyUsage{logical.KeyUsageSign}) {
+		return nil, fmt.Errorf("managed key %q does not allow signing", k.entry.Name)
+	}
+	return k.signer.Sign(ctx, value, opts)
+}
+
+func (k *managedKey) Verify(ctx context.Context, signature, value []byte, opts crypto.SignerOpts) (bool, error) {
+	if !k.AllowsAll([]logical.KeyUsage{logical.KeyUsageVerify}) {
+		return false, fmt.Errorf("managed key %q does not allow verifying", k.entry.Name)
+	}
+	return k.verify(signature, value, opts)
+}
+
+// verify checks the signature regardless of the usages of the key.
+func (k *managedKey) verify(signature, value []byte, opts crypto.SignerOpts) (bool, error) {
+	// Signatures are verified with the public key rather than by the KMS
+	switch pub := k.signer.Public().(type) {
+	case *rsa.PublicKey:
+		if pssOpts, ok := opts.(*rsa.PSSOptions); ok {
+			return rsa.VerifyPSS(pub, opts.HashFunc(), value, signature, pssOpts) == nil, nil
+		}
+		return rsa.VerifyPKCS1v15(pub, opts.HashFunc(), value, signature) == nil, nil
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(pub, value, signature), nil
+	default:
+		return false, fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+func (k *managedKey) GetSigner(ctx context.Context) (crypto.Signer, error) {
+	return &managedKeyCryptoSigner{ctx: ctx, key: k}, nil
+}
+
+// managedKeyCryptoSigner is a crypto.Signer signing with a managed key within
+// the context it was created for.
+type managedKeyCryptoSigner struct {
+	ctx context.Context
+	key *managedKey
+}
+
+func (s *managedKeyCryptoSigner) Public() crypto.PublicKey {
+	return s.key.signer.Public()
+}
+
+func (s *managedKeyCryptoSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.key.Sign(s.ctx, digest, rand, opts)
+}
+
+// managedKey returns the key with the name or UUID for the mount of the
+// system view, checking that the mount is allowed to use it.
+func (d dynamicSystemView) managedKey(ctx context.Context, name, keyUUID, backendUUID string) (*managedKey, error) {
+	r := d.core.managedKeyRegistry
+	if r == nil || d.mountEntry == nil {
+		return nil, errManagedKeyNotFound
+	}
+	if backendUUID != "" && backendUUID != d.mountEntry.BackendAwareUUID {
+		return nil, fmt.Errorf("backend UUID %q does not match the mount", backendUUID)
+	}
+
+	ns := d.mountEntry.Namespace()
+	var entry *managedKeyEntry
+	var err error
+	if keyUUID != "" {
+		entry, err = r.getByUUID(ctx, ns, keyUUID)
+	} else {
+		entry, err = r.get(ctx, ns, strings.ToLower(name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, errManagedKeyNotFound
+	}
+
+	if !entry.AnyMount {
+		var allowed []string
+		if raw, ok := d.mountEntry.synthesizedConfigCache.Load("allowed_managed_keys"); ok {
+			allowed = raw.([]string)
+		}
+		if !strutil.StrListContains(allowed, entry.Name) && !strutil.StrListContains(allowed, entry.UUID) {
+			return nil, errManagedKeyNotAllowed
+		}
+	}
+
+	signer, err := r.signer(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	return &managedKey{entry: entry, signer: signer}, nil
+}
+
+func (d dynamicSystemView) WithManagedKeyByName(ctx context.Context, keyName, backendUUID string, f logical.ManagedKeyConsumer) error {
+	key, err := d.managedKey(ctx, keyName, "", backendUUID)
+	if err != nil {
+		return err
+	}
+	return f(ctx, key)
+}
+
+func (d dynamicSystemView) WithManagedKeyByUUID(ctx context.Context, keyUUID, backendUUID string, f logical.ManagedKeyConsumer) error {
+	key, err := d.managedKey(ctx, "", keyUUID, backendUUID)
+	if err != nil {
+		return err
+	}
+	return f(ctx, key)
+}
+
+func (d dynamicSystemView) WithManagedSigningKeyByName(ctx context.Context, keyName, backendUUID string, f logical.ManagedSigningKeyConsumer) error {
+	key, err := d.managedKey(ctx, keyName, "", backendUUID)
+	if err != nil {
+		return err
+	}
+	return f(ctx, key)
+}
+
+func (d dynamicSystemView) WithManagedSigningKeyByUUID(ctx context.Context, keyUUID, backendUUID string, f logical.ManagedSigningKeyConsumer) error {
+	key, err := d.managedKey(ctx, "", keyUUID, backendUUID)
+	if err != nil {
+		return err
+	}
+	return f(ctx, key)
+}
+
+func (d dynamicSystemView) WithManagedEncryptingKeyByName(context.Context, string, string, logical.ManagedEncryptingKeyConsumer) error {
+	return errManagedKeySignOnly
+}
+
+func (d dynamicSystemView) WithManagedEncryptingKeyByUUID(context.Context, string, string, logical.ManagedEncryptingKeyConsumer) error {
+	return errManagedKeySignOnly
+}
+
+func (d dynamicSystemView) WithManagedMACKeyByName(context.Context, string, string, logical.ManagedMACKeyConsumer) error {
+	return errManagedKeySignOnly
+}
+
+func (d dynamicSystemView) WithManagedMACKeyByUUID(context.Context, string, string, logical.ManagedMACKeyConsumer) error {
+	return errManagedKeySignOnly
 }
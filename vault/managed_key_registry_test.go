@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !enterprise
+
+package vault
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault/managedkeys"
+)
+
+// testManagedKeySigner is a managed key held in memory rather than by a KMS.
+type testManagedKeySigner struct {
+	key    *ecdsa.PrivateKey
+	closed bool
+}
+
+func (s *testManagedKeySigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *testManagedKeySigner) Sign(_ context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.key.Sign(rand.Reader, digest, opts)
+}
+
+func (s *testManagedKeySigner) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestManagedKeyRegistry(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	var signers []*testManagedKeySigner
+	c.managedKeyRegistry.newSigner = func(_ context.Context, keyType string, config map[string]string, _ map[string]string) (managedkeys.Signer, error) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		s := &testManagedKeySigner{key: key}
+		signers = append(signers, s)
+		return s, nil
+	}
+
+	handle := func(t *testing.T, op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		return c.HandleRequest(ctx, &logical.Request{
+			Operation:   op,
+			Path:        path,
+			Data:        data,
+			ClientToken: root,
+		})
+	}
+	mustHandle := func(t *testing.T, op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := handle(t, op, path, data)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v, resp: %#v", err, resp)
+		}
+		return resp
+	}
+	mustFail := func(t *testing.T, op logical.Operation, path string, data map[string]interface{}) {
+		t.Helper()
+		resp, err := handle(t, op, path, data)
+		if err == nil && (resp == nil || !resp.IsError()) {
+			t.Fatalf("expected %s on %s to fail, got: %#v", op, path, resp)
+		}
+	}
+
+	mustHandle(t, logical.UpdateOperation, "sys/managed-keys/awskms/test-key", map[string]interface{}{
+		"kms_key":    "alias/test-key",
+		"key_type":   "ECDSA",
+		"access_key": "access",
+		"secret_key": "secret",
+	})
+
+	resp := mustHandle(t, logical.ReadOperation, "sys/managed-keys/awskms/test-key", nil)
+	if resp.Data["UUID"] == "" || resp.Data["kms_key"] != "alias/test-key" || resp.Data["access_key"] != "access" {
+		t.Fatalf("unexpected key: %#v", resp.Data)
+	}
+	if resp.Data["secret_key"] != "redacted" {
+		t.Fatalf("expected secret_key to be redacted, got: %v", resp.Data["secret_key"])
+	}
+	keyUUID := resp.Data["UUID"].(string)
+
+	resp = mustHandle(t, logical.ListOperation, "sys/managed-keys/awskms", nil)
+	if keys := resp.Data["keys"].([]string); len(keys) != 1 || keys[0] != "test-key" {
+		t.Fatalf("unexpected keys: %#v", resp.Data)
+	}
+	resp = mustHandle(t, logical.ListOperation, "sys/managed-keys/gcpckms", nil)
+	if _, ok := resp.Data["keys"]; ok {
+		t.Fatalf("unexpected keys: %#v", resp.Data)
+	}
+
+	// Names are unique across types, and keys can't be generated
+	mustFail(t, logical.UpdateOperation, "sys/managed-keys/gcpckms/test-key", map[string]interface{}{
+		"project":    "project",
+		"region":     "global",
+		"key_ring":   "ring",
+		"crypto_key": "key",
+		"algorithm":  "EC_SIGN_P256_SHA256",
+	})
+	mustFail(t, logical.UpdateOperation, "sys/managed-keys/awskms/other-key", map[string]interface{}{
+		"kms_key":            "alias/other-key",
+		"key_type":           "ECDSA",
+		"allow_generate_key": true,
+	})
+	mustFail(t, logical.UpdateOperation, "sys/managed-keys/awskms/other-key", map[string]interface{}{
+		"key_type": "ECDSA",
+	})
+
+	mustHandle(t, logical.UpdateOperation, "sys/managed-keys/awskms/test-key/test/sign", map[string]interface{}{
+		"hash_algorithm": "sha2-256",
+	})
+	if len(signers) != 1 {
+		t.Fatalf("expected a single signer, got %d", len(signers))
+	}
+
+	// Mounts can only use the keys they are allowed to
+	mustHandle(t, logical.UpdateOperation, "sys/mounts/kv", map[string]interface{}{
+		"type": "kv",
+	})
+	me := c.router.MatchingMountEntry(ctx, "kv/")
+	view := c.mountEntrySysView(me).(logical.ManagedKeySystemView)
+
+	digest := sha256.Sum256([]byte("test"))
+	sign := func(ctx context.Context, key logical.ManagedSigningKey) error {
+		sig, err := key.Sign(ctx, digest[:], rand.Reader, crypto.SHA256)
+		if err != nil {
+			return err
+		}
+		if ok, err := key.Verify(ctx, sig, digest[:], crypto.SHA256); err != nil || !ok {
+			t.Fatalf("failed to verify the signature: %v", err)
+		}
+		return nil
+	}
+
+	if err := view.WithManagedSigningKeyByName(ctx, "test-key", me.BackendAwareUUID, sign); !errors.Is(err, errManagedKeyNotAllowed) {
+		t.Fatalf("expected the key not to be allowed, got: %v", err)
+	}
+
+	mustHandle(t, logical.UpdateOperation, "sys/mounts/kv/tune", map[string]interface{}{
+		"allowed_managed_keys": "test-key",
+	})
+	if err := view.WithManagedSigningKeyByName(ctx, "test-key", me.BackendAwareUUID, sign); err != nil {
+		t.Fatal(err)
+	}
+	if err := view.WithManagedSigningKeyByUUID(ctx, keyUUID, me.BackendAwareUUID, sign); err != nil {
+		t.Fatal(err)
+	}
+	if err := view.WithManagedSigningKeyByName(ctx, "test-key", "other-backend", sign); err == nil {
+		t.Fatal("expected a mismatched backend UUID to fail")
+	}
+	if err := view.WithManagedSigningKeyByName(ctx, "missing-key", me.BackendAwareUUID, sign); !errors.Is(err, errManagedKeyNotFound) {
+		t.Fatalf("expected the key not to be found, got: %v", err)
+	}
+
+	// Keys allowed to a mount can't be deleted, and updating a key closes
+	// its signer
+	mustFail(t, logical.DeleteOperation, "sys/managed-keys/awskms/test-key", nil)
+
+	mustHandle(t, logical.UpdateOperation, "sys/managed-keys/awskms/test-key", map[string]interface{}{
+		"usages": "verify",
+	})
+	if !signers[0].closed {
+		t.Fatal("expected the signer of the key to be closed")
+	}
+	err := view.WithManagedSigningKeyByName(ctx, "test-key", me.BackendAwareUUID, sign)
+	if err == nil {
+		t.Fatal("expected signing with a verify only key to fail")
+	}
+
+	mustHandle(t, logical.UpdateOperation, "sys/mounts/kv/tune", map[string]interface{}{
+		"allowed_managed_keys": "",
+	})
+	mustHandle(t, logical.DeleteOperation, "sys/managed-keys/awskms/test-key", nil)
+
+	resp = mustHandle(t, logical.ReadOperation, "sys/managed-keys/awskms/test-key", nil)
+	if resp != nil {
+		t.Fatalf("expected the key to be deleted, got: %#v", resp)
+	}
+	entry, err := c.managedKeyRegistry.getByUUID(ctx, namespace.RootNamespace, keyUUID)
+	if err != nil || entry != nil {
+		t.Fatalf("expected the UUID of the key to be deleted, got: %#v, %v", entry, err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package managedkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-secure-stdlib/awsutil"
+)
+
+type awsKMSSigner struct {
+	client *kms.KMS
+	keyID  string
+	public crypto.PublicKey
+	kind   string
+}
+
+func validateAWSKMS(config map[string]string, _ map[string]string) error {
+	if err := required(config, "kms_key", "key_type"); err != nil {
+		return err
+	}
+	switch strings.ToUpper(config["key_type"]) {
+	case "RSA", "ECDSA":
+	default:
+		return fmt.Errorf("unsupported key_type %q, must be RSA or ECDSA", config["key_type"])
+	}
+	return nil
+}
+
+func newAWSKMSSigner(ctx context.Context, config map[string]string, _ map[string]string) (Signer, error) {
+	credsConfig := &awsutil.CredentialsConfig{
+		AccessKey:    config["access_key"],
+		SecretKey:    config["secret_key"],
+		SessionToken: config["session_token"],
+		Region:       config["region"],
+		HTTPClient:   cleanhttp.DefaultClient(),
+	}
+	if credsConfig.Region == "" {
+		credsConfig.Region = "us-east-1"
+	}
+	creds, err := credsConfig.GenerateCredentialChain()
+	if err != nil {
+		return nil, err
+	}
+
+	awsConfig := &aws.Config{
+		Credentials: creds,
+		Region:      aws.String(credsConfig.Region),
+		HTTPClient:  cleanhttp.DefaultClient(),
+	}
+	if endpoint := config["endpoint"]; endpoint != "" {
+		awsConfig.Endpoint = aws.String(endpoint)
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &awsKMSSigner{
+		client: kms.New(sess),
+		keyID:  config["kms_key"],
+	}
+	out, err := s.client.GetPublicKeyWithContext(ctx, &kms.GetPublicKeyInput{
+		KeyId: aws.String(s.keyID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching the public key of %q: %w", s.keyID, err)
+	}
+	if s.public, err = x509.ParsePKIXPublicKey(out.PublicKey); err != nil {
+		return nil, fmt.Errorf("error parsing the public key of %q: %w", s.keyID, err)
+	}
+	if s.kind, err = keyKind(s.public); err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(s.kind, config["key_type"]) {
+		return nil, fmt.Errorf("key %q is not an %s key", s.keyID, strings.ToUpper(config["key_type"]))
+	}
+
+	return s, nil
+}
+
+func (s *awsKMSSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *awsKMSSigner) Sign(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if err := checkHash(opts); err != nil {
+		return nil, err
+	}
+	bits := fmt.Sprintf("%d", opts.HashFunc().Size()*8)
+
+	var algorithm string
+	switch {
+	case s.kind == "ecdsa":
+		algorithm = "ECDSA_SHA_" + bits
+	case isPSS(opts):
+		algorithm = "RSASSA_PSS_SHA_" + bits
+	default:
+		algorithm = "RSASSA_PKCS1_V1_5_SHA_" + bits
+	}
+
+	out, err := s.client.SignWithContext(ctx, &kms.SignInput{
+		KeyId:            aws.String(s.keyID),
+		Message:          digest,
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		SigningAlgorithm: aws.String(algorithm),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
+
+func (s *awsKMSSigner) Close() error {
+	return nil
+}
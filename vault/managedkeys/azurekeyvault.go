@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package managedkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.1/keyvault"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+)
+
+type azureKeyVaultSigner struct {
+	client     *keyvault.BaseClient
+	baseURL    string
+	keyName    string
+	keyVersion string
+	public     crypto.PublicKey
+	kind       string
+}
+
+func validateAzureKeyVault(config map[string]string, _ map[string]string) error {
+	if err := required(config, "tenant_id", "vault_name", "key_name", "key_type"); err != nil {
+		return err
+	}
+	switch strings.ToUpper(config["key_type"]) {
+	case "RSA", "RSA-HSM", "EC", "EC-HSM":
+	default:
+		return fmt.Errorf("unsupported key_type %q", config["key_type"])
+	}
+	if config["client_secret"] != "" && config["client_id"] == "" {
+		return fmt.Errorf("%q is required with %q", "client_id", "client_secret")
+	}
+	return nil
+}
+
+func newAzureKeyVaultSigner(ctx context.Context, config map[string]string, _ map[string]string) (Signer, error) {
+	environment := azure.PublicCloud
+	if name := config["environment"]; name != "" {
+		var err error
+		if environment, err = azure.EnvironmentFromName(name); err != nil {
+			return nil, err
+		}
+	}
+	dnsSuffix := config["resource"]
+	if dnsSuffix == "" {
+		dnsSuffix = environment.KeyVaultDNSSuffix
+	}
+	resource := "https://" + dnsSuffix
+
+	var authorizer autorest.Authorizer
+	var err error
+	switch {
+	case config["client_id"] != "" && config["client_secret"] != "":
+		authConfig := auth.NewClientCredentialsConfig(config["client_id"], config["client_secret"], config["tenant_id"])
+		authConfig.AADEndpoint = environment.ActiveDirectoryEndpoint
+		authConfig.Resource = resource
+		authorizer, err = authConfig.Authorizer()
+	// By default use MSI
+	default:
+		authConfig := auth.NewMSIConfig()
+		authConfig.Resource = resource
+		if config["client_id"] != "" {
+			authConfig.ClientID = config["client_id"]
+		}
+		authorizer, err = authConfig.Authorizer()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client := keyvault.New()
+	client.Authorizer = authorizer
+	s := &azureKeyVaultSigner{
+		client:     &client,
+		baseURL:    fmt.Sprintf("https://%s.%s/", config["vault_name"], dnsSuffix),
+		keyName:    config["key_name"],
+		keyVersion: config["key_version"],
+	}
+
+	bundle, err := client.GetKey(ctx, s.baseURL, s.keyName, s.keyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching the key %q: %w", s.keyName, err)
+	}
+	if bundle.Key == nil {
+		return nil, fmt.Errorf("key %q not found", s.keyName)
+	}
+	if s.public, err = azurePublicKey(bundle.Key); err != nil {
+		return nil, fmt.Errorf("error parsing the public key of %q: %w", s.keyName, err)
+	}
+	if s.kind, err = keyKind(s.public); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// azurePublicKey returns the public key of a JSON web key.
+func azurePublicKey(key *keyvault.JSONWebKey) (crypto.PublicKey, error) {
+	decode := func(v *string) (*big.Int, error) {
+		if v == nil {
+			return nil, fmt.Errorf("missing key parameter")
+		}
+		b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(*v, "="))
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+
+	switch key.Kty {
+	case keyvault.RSA, keyvault.RSAHSM:
+		n, err := decode(key.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(key.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case keyvault.EC, keyvault.ECHSM:
+		var curve elliptic.Curve
+		switch key.Crv {
+		case keyvault.P256:
+			curve = elliptic.P256()
+		case keyvault.P384:
+			curve = elliptic.P384()
+		case keyvault.P521:
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", key.Crv)
+		}
+		x, err := decode(key.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(key.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", key.Kty)
+	}
+}
+
+func (s *azureKeyVaultSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *azureKeyVaultSigner) Sign(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if err := checkHash(opts); err != nil {
+		return nil, err
+	}
+	bits := fmt.Sprintf("%d", opts.HashFunc().Size()*8)
+
+	var algorithm keyvault.JSONWebKeySignatureAlgorithm
+	switch {
+	case s.kind == "ecdsa":
+		algorithm = keyvault.JSONWebKeySignatureAlgorithm("ES" + bits)
+	case isPSS(opts):
+		algorithm = keyvault.JSONWebKeySignatureAlgorithm("PS" + bits)
+	default:
+		algorithm = keyvault.JSONWebKeySignatureAlgorithm("RS" + bits)
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(digest)
+	out, err := s.client.Sign(ctx, s.baseURL, s.keyName, s.keyVersion, keyvault.KeySignParameters{
+		Algorithm: algorithm,
+		Value:     &value,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Result == nil {
+		return nil, fmt.Errorf("no signature returned by the key vault")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(*out.Result, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.kind == "ecdsa" {
+		return rawToASN1(sig)
+	}
+	return sig, nil
+}
+
+func (s *azureKeyVaultSigner) Close() error {
+	return nil
+}
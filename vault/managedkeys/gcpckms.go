@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package managedkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	cloudkms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"google.golang.org/api/option"
+)
+
+// gcpckmsAlgorithms are the signature algorithms supported for GCP Cloud KMS
+// keys, with the hash and padding they sign with.
+var gcpckmsAlgorithms = map[string]struct {
+	hash crypto.Hash
+	pss  bool
+}{
+	"EC_SIGN_P256_SHA256":        {hash: crypto.SHA256},
+	"EC_SIGN_P384_SHA384":        {hash: crypto.SHA384},
+	"RSA_SIGN_PSS_2048_SHA256":   {hash: crypto.SHA256, pss: true},
+	"RSA_SIGN_PSS_3072_SHA256":   {hash: crypto.SHA256, pss: true},
+	"RSA_SIGN_PSS_4096_SHA256":   {hash: crypto.SHA256, pss: true},
+	"RSA_SIGN_PSS_4096_SHA512":   {hash: crypto.SHA512, pss: true},
+	"RSA_SIGN_PKCS1_2048_SHA256": {hash: crypto.SHA256},
+	"RSA_SIGN_PKCS1_3072_SHA256": {hash: crypto.SHA256},
+	"RSA_SIGN_PKCS1_4096_SHA256": {hash: crypto.SHA256},
+	"RSA_SIGN_PKCS1_4096_SHA512": {hash: crypto.SHA512},
+}
+
+type gcpckmsSigner struct {
+	client    *cloudkms.KeyManagementClient
+	name      string
+	algorithm string
+	public    crypto.PublicKey
+}
+
+func validateGCPCKMS(config map[string]string, _ map[string]string) error {
+	if err := required(config, "project", "region", "key_ring", "crypto_key", "algorithm"); err != nil {
+		return err
+	}
+	if _, ok := gcpckmsAlgorithms[strings.ToUpper(config["algorithm"])]; !ok {
+		return fmt.Errorf("unsupported algorithm %q", config["algorithm"])
+	}
+	return nil
+}
+
+func newGCPCKMSSigner(ctx context.Context, config map[string]string, _ map[string]string) (Signer, error) {
+	var opts []option.ClientOption
+	if creds := config["credentials"]; creds != "" {
+		opts = append(opts, option.WithCredentialsFile(creds))
+	}
+	client, err := cloudkms.NewKeyManagementClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create KMS client: %w", err)
+	}
+
+	version := config["crypto_key_version"]
+	if version == "" {
+		version = "1"
+	}
+	s := &gcpckmsSigner{
+		client: client,
+		name: fmt.Sprintf("projects/%s/locations/%s/keyRings/%s/cryptoKeys/%s/cryptoKeyVersions/%s",
+			config["project"], config["region"], config["key_ring"], config["crypto_key"], version),
+		algorithm: strings.ToUpper(config["algorithm"]),
+	}
+
+	out, err := client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: s.name})
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error fetching the public key of %q: %w", s.name, err)
+	}
+	if out.Algorithm.String() != s.algorithm {
+		client.Close()
+		return nil, fmt.Errorf("key %q uses algorithm %s, not %s", s.name, out.Algorithm, s.algorithm)
+	}
+	block, _ := pem.Decode([]byte(out.Pem))
+	if block == nil {
+		client.Close()
+		return nil, fmt.Errorf("error decoding the public key of %q", s.name)
+	}
+	if s.public, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error parsing the public key of %q: %w", s.name, err)
+	}
+
+	return s, nil
+}
+
+func (s *gcpckmsSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *gcpckmsSigner) Sign(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	// The algorithm of GCP keys is fixed on creation, so the signature must
+	// be requested with the hash and padding of the key
+	algorithm := gcpckmsAlgorithms[s.algorithm]
+	if opts.HashFunc() != algorithm.hash {
+		return nil, fmt.Errorf("key with algorithm %s cannot sign %v digests", s.algorithm, opts.HashFunc())
+	}
+	if strings.HasPrefix(s.algorithm, "RSA_") && isPSS(opts) != algorithm.pss {
+		return nil, fmt.Errorf("key with algorithm %s cannot sign with the requested padding", s.algorithm)
+	}
+
+	req := &kmspb.AsymmetricSignRequest{
+		Name:   s.name,
+		Digest: &kmspb.Digest{},
+	}
+	switch algorithm.hash {
+	case crypto.SHA256:
+		req.Digest.Digest = &kmspb.Digest_Sha256{Sha256: digest}
+	case crypto.SHA384:
+		req.Digest.Digest = &kmspb.Digest_Sha384{Sha384: digest}
+	case crypto.SHA512:
+		req.Digest.Digest = &kmspb.Digest_Sha512{Sha512: digest}
+	}
+
+	out, err := s.client.AsymmetricSign(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
+
+func (s *gcpckmsSigner) Close() error {
+	return s.client.Close()
+}
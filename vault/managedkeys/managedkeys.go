@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package managedkeys implements the signers of managed keys, keys held by a
+// KMS or an HSM that Vault signs with through their API without ever holding
+// the private key.
+package managedkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Types of managed keys.
+const (
+	TypeAWSKMS        = "awskms"
+	TypeAzureKeyVault = "azurekeyvault"
+	TypeGCPCKMS       = "gcpckms"
+	TypePKCS11        = "pkcs11"
+)
+
+// ErrUnsupportedType is returned for managed keys of an unknown type.
+var ErrUnsupportedType = errors.New("unsupported managed key type")
+
+// Signer signs digests with a managed key. Implementations are safe for
+// concurrent use.
+type Signer interface {
+	// Public returns the public key of the managed key.
+	Public() crypto.PublicKey
+
+	// Sign signs the digest, hashed with the hash function of the options.
+	// RSA keys sign with PSS if the options are *rsa.PSSOptions. ECDSA
+	// signatures are ASN.1 encoded like those of crypto/ecdsa.
+	Sign(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error)
+
+	// Close releases the connections to the KMS.
+	Close() error
+}
+
+type factory struct {
+	fields    []string
+	sensitive []string
+	validate  func(config map[string]string, libraries map[string]string) error
+	new       func(ctx context.Context, config map[string]string, libraries map[string]string) (Signer, error)
+}
+
+var factories = map[string]factory{
+	TypeAWSKMS: {
+		fields:    []string{"access_key", "secret_key", "session_token", "endpoint", "region", "kms_key", "key_type", "key_bits", "curve"},
+		sensitive: []string{"secret_key", "session_token"},
+		validate:  validateAWSKMS,
+		new:       newAWSKMSSigner,
+	},
+	TypeAzureKeyVault: {
+		fields:    []string{"tenant_id", "client_id", "client_secret", "environment", "vault_name", "key_name", "key_version", "resource", "key_type", "key_bits"},
+		sensitive: []string{"client_secret"},
+		validate:  validateAzureKeyVault,
+		new:       newAzureKeyVaultSigner,
+	},
+	TypeGCPCKMS: {
+		fields:   []string{"credentials", "project", "region", "key_ring", "crypto_key", "crypto_key_version", "algorithm"},
+		validate: validateGCPCKMS,
+		new:      newGCPCKMSSigner,
+	},
+	TypePKCS11: {
+		fields:    []string{"library", "slot", "token_label", "pin", "key_label", "key_id", "mechanism", "curve", "key_bits", "force_rw_session", "max_parallel"},
+		sensitive: []string{"pin"},
+		validate:  validatePKCS11,
+		new:       newPKCS11Signer,
+	},
+}
+
+// Types returns the supported types of managed keys.
+func Types() []string {
+	types := make([]string, 0, len(factories))
+	for t := range factories {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Fields returns the configuration fields of the managed keys of the type,
+// and which of them are sensitive.
+func Fields(keyType string) (fields []string, sensitive []string, err error) {
+	f, ok := factories[keyType]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w %q", ErrUnsupportedType, keyType)
+	}
+	return f.fields, f.sensitive, nil
+}
+
+// Validate checks the configuration of a managed key without reaching the
+// KMS. The libraries map the names of the kms_library stanzas of the server
+// configuration to the path of their library.
+func Validate(keyType string, config map[string]string, libraries map[string]string) error {
+	f, ok := factories[keyType]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnsupportedType, keyType)
+	}
+	return f.validate(config, libraries)
+}
+
+// New returns the signer of a managed key, reaching the KMS to fetch its
+// public key.
+func New(ctx context.Context, keyType string, config map[string]string, libraries map[string]string) (Signer, error) {
+	f, ok := factories[keyType]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedType, keyType)
+	}
+	if err := f.validate(config, libraries); err != nil {
+		return nil, err
+	}
+	return f.new(ctx, config, libraries)
+}
+
+// required returns an error naming the first of the fields that is not set.
+func required(config map[string]string, fields ...string) error {
+	for _, field := range fields {
+		if config[field] == "" {
+			return fmt.Errorf("%q is required", field)
+		}
+	}
+	return nil
+}
+
+// checkHash returns an error unless the hash of the options is one the KMS
+// APIs sign with.
+func checkHash(opts crypto.SignerOpts) error {
+	switch opts.HashFunc() {
+	case crypto.SHA256, crypto.SHA384, crypto.SHA512:
+		return nil
+	default:
+		return fmt.Errorf("unsupported hash function %v", opts.HashFunc())
+	}
+}
+
+// isPSS returns whether the options request an RSA PSS signature.
+func isPSS(opts crypto.SignerOpts) bool {
+	_, ok := opts.(*rsa.PSSOptions)
+	return ok
+}
+
+// keyKind returns "rsa" or "ecdsa" for the public key.
+func keyKind(pub crypto.PublicKey) (string, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		return "rsa", nil
+	case *ecdsa.PublicKey:
+		return "ecdsa", nil
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// rawToASN1 converts an ECDSA signature encoded as the concatenation of r and
+// s, as returned by PKCS#11 and Azure, to ASN.1.
+func rawToASN1(sig []byte) ([]byte, error) {
+	if len(sig) == 0 || len(sig)%2 != 0 {
+		return nil, fmt.Errorf("invalid ECDSA signature length %d", len(sig))
+	}
+	half := len(sig) / 2
+	return asn1.Marshal(struct {
+		R, S *big.Int
+	}{
+		R: new(big.Int).SetBytes(sig[:half]),
+		S: new(big.Int).SetBytes(sig[half:]),
+	})
+}
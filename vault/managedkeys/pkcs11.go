@@ -0,0 +1,355 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build cgo
+
+package managedkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	p11 "github.com/miekg/pkcs11"
+)
+
+// pkcs11Libraries are the loaded PKCS#11 libraries by path. Libraries are
+// shared by all the keys using them and never finalized, as finalizing a
+// library invalidates the sessions of every key of the process.
+var (
+	pkcs11LibrariesLock sync.Mutex
+	pkcs11Libraries     = make(map[string]*p11.Ctx)
+)
+
+var (
+	oidNamedCurveP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveP384 = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+	oidNamedCurveP521 = asn1.ObjectIdentifier{1, 3, 132, 0, 35}
+)
+
+// digestInfoPrefixes are the DER prefixes of the DigestInfo signed with
+// CKM_RSA_PKCS, which leaves encoding the digest to the caller.
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// pkcs11Signer signs with a private key of a token. PKCS#11 sessions may not
+// be used concurrently, so each signature takes a session from the pool.
+type pkcs11Signer struct {
+	config   *pkcs11Config
+	ctx      *p11.Ctx
+	sessions chan p11.SessionHandle
+	key      p11.ObjectHandle
+	public   crypto.PublicKey
+}
+
+func newPKCS11Signer(_ context.Context, config map[string]string, libraries map[string]string) (Signer, error) {
+	c, err := parsePKCS11Config(config, libraries)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := loadPKCS11Library(c.lib)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &pkcs11Signer{
+		config:   c,
+		ctx:      ctx,
+		sessions: make(chan p11.SessionHandle, c.maxParallel),
+	}
+	if err := s.open(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func loadPKCS11Library(lib string) (*p11.Ctx, error) {
+	pkcs11LibrariesLock.Lock()
+	defer pkcs11LibrariesLock.Unlock()
+
+	if ctx, ok := pkcs11Libraries[lib]; ok {
+		return ctx, nil
+	}
+	ctx := p11.New(lib)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 library %q", lib)
+	}
+	if err := ctx.Initialize(); err != nil && !isPKCS11Error(err, p11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("failed to initialize PKCS#11 library: %w", err)
+	}
+	pkcs11Libraries[lib] = ctx
+	return ctx, nil
+}
+
+// open logs in, fills the session pool and finds the key pair.
+func (s *pkcs11Signer) open() error {
+	slot, err := s.findSlot()
+	if err != nil {
+		return err
+	}
+
+	flags := uint(p11.CKF_SERIAL_SESSION)
+	if s.config.forceRWSession {
+		flags |= p11.CKF_RW_SESSION
+	}
+	for i := 0; i < s.config.maxParallel; i++ {
+		session, err := s.ctx.OpenSession(slot, flags)
+		if err != nil {
+			return fmt.Errorf("failed to open session on slot %d: %w", slot, err)
+		}
+		s.sessions <- session
+
+		// The login state is shared by all the sessions of the token
+		if i == 0 {
+			if err := s.ctx.Login(session, p11.CKU_USER, s.config.pin); err != nil && !isPKCS11Error(err, p11.CKR_USER_ALREADY_LOGGED_IN) {
+				return fmt.Errorf("failed to log in to slot %d: %w", slot, err)
+			}
+		}
+	}
+
+	session := <-s.sessions
+	defer func() { s.sessions <- session }()
+
+	private, err := s.findObject(session, p11.CKO_PRIVATE_KEY)
+	if err != nil {
+		return err
+	}
+	public, err := s.findObject(session, p11.CKO_PUBLIC_KEY)
+	if err != nil {
+		return err
+	}
+	s.key = private
+	if s.public, err = s.publicKey(session, public); err != nil {
+		return err
+	}
+
+	switch s.public.(type) {
+	case *ecdsa.PublicKey:
+		if s.config.mechanism != mechanismECDSA {
+			return fmt.Errorf("mechanism 0x%04X cannot sign with an EC key", s.config.mechanism)
+		}
+	case *rsa.PublicKey:
+		if s.config.mechanism == mechanismECDSA {
+			return errors.New("mechanism CKM_ECDSA cannot sign with an RSA key")
+		}
+	}
+	return nil
+}
+
+func (s *pkcs11Signer) findSlot() (uint, error) {
+	if s.config.slot != nil {
+		return *s.config.slot, nil
+	}
+
+	slots, err := s.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list slots: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := s.ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get token info of slot %d: %w", slot, err)
+		}
+		if info.Label == s.config.tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("no token labeled %q found", s.config.tokenLabel)
+}
+
+func (s *pkcs11Signer) findObject(session p11.SessionHandle, class uint) (p11.ObjectHandle, error) {
+	template := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, class),
+	}
+	if s.config.keyLabel != "" {
+		template = append(template, p11.NewAttribute(p11.CKA_LABEL, s.config.keyLabel))
+	}
+	if len(s.config.keyID) > 0 {
+		template = append(template, p11.NewAttribute(p11.CKA_ID, s.config.keyID))
+	}
+
+	if err := s.ctx.FindObjectsInit(session, template); err != nil {
+		return 0, fmt.Errorf("failed to search for the key: %w", err)
+	}
+	objects, _, err := s.ctx.FindObjects(session, 2)
+	if finalErr := s.ctx.FindObjectsFinal(session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to search for the key: %w", err)
+	}
+
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("no key labeled %q with id %x found", s.config.keyLabel, s.config.keyID)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("more than one key labeled %q found, set 'key_id' to select one", s.config.keyLabel)
+	}
+}
+
+func (s *pkcs11Signer) publicKey(session p11.SessionHandle, object p11.ObjectHandle) (crypto.PublicKey, error) {
+	attrs, err := s.ctx.GetAttributeValue(session, object, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_KEY_TYPE, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the key type: %w", err)
+	}
+	keyType := smallUlong(attrs[0].Value)
+
+	switch keyType {
+	case p11.CKK_RSA:
+		attrs, err := s.ctx.GetAttributeValue(session, object, []*p11.Attribute{
+			p11.NewAttribute(p11.CKA_MODULUS, nil),
+			p11.NewAttribute(p11.CKA_PUBLIC_EXPONENT, nil),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the public key: %w", err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(attrs[0].Value),
+			E: int(new(big.Int).SetBytes(attrs[1].Value).Int64()),
+		}, nil
+	case p11.CKK_EC:
+		attrs, err := s.ctx.GetAttributeValue(session, object, []*p11.Attribute{
+			p11.NewAttribute(p11.CKA_EC_PARAMS, nil),
+			p11.NewAttribute(p11.CKA_EC_POINT, nil),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the public key: %w", err)
+		}
+		var oid asn1.ObjectIdentifier
+		if _, err := asn1.Unmarshal(attrs[0].Value, &oid); err != nil {
+			return nil, fmt.Errorf("failed to parse the curve of the key: %w", err)
+		}
+		var curve elliptic.Curve
+		switch {
+		case oid.Equal(oidNamedCurveP256):
+			curve = elliptic.P256()
+		case oid.Equal(oidNamedCurveP384):
+			curve = elliptic.P384()
+		case oid.Equal(oidNamedCurveP521):
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %v", oid)
+		}
+		// The point is an uncompressed point wrapped in an octet string
+		var point []byte
+		if _, err := asn1.Unmarshal(attrs[1].Value, &point); err != nil {
+			return nil, fmt.Errorf("failed to parse the public key: %w", err)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(point) != 1+2*size || point[0] != 4 {
+			return nil, errors.New("failed to parse the public key: invalid point")
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(point[1 : 1+size]),
+			Y:     new(big.Int).SetBytes(point[1+size:]),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type 0x%x", keyType)
+	}
+}
+
+func (s *pkcs11Signer) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *pkcs11Signer) Sign(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if err := checkHash(opts); err != nil {
+		return nil, err
+	}
+
+	var mechanism *p11.Mechanism
+	message := digest
+	switch s.config.mechanism {
+	case mechanismECDSA:
+		mechanism = p11.NewMechanism(p11.CKM_ECDSA, nil)
+	case mechanismRSAPKCS:
+		if isPSS(opts) {
+			return nil, errors.New("mechanism CKM_RSA_PKCS cannot sign with PSS")
+		}
+		mechanism = p11.NewMechanism(p11.CKM_RSA_PKCS, nil)
+		message = append(append([]byte{}, digestInfoPrefixes[opts.HashFunc()]...), digest...)
+	case mechanismRSAPKCSPSS:
+		if !isPSS(opts) {
+			return nil, errors.New("mechanism CKM_RSA_PKCS_PSS can only sign with PSS")
+		}
+		var hash, mgf uint
+		switch opts.HashFunc() {
+		case crypto.SHA256:
+			hash, mgf = p11.CKM_SHA256, p11.CKG_MGF1_SHA256
+		case crypto.SHA384:
+			hash, mgf = p11.CKM_SHA384, p11.CKG_MGF1_SHA384
+		case crypto.SHA512:
+			hash, mgf = p11.CKM_SHA512, p11.CKG_MGF1_SHA512
+		}
+		mechanism = p11.NewMechanism(p11.CKM_RSA_PKCS_PSS, p11.NewPSSParams(hash, mgf, uint(opts.HashFunc().Size())))
+	}
+
+	var session p11.SessionHandle
+	select {
+	case session = <-s.sessions:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { s.sessions <- session }()
+
+	if err := s.ctx.SignInit(session, []*p11.Mechanism{mechanism}, s.key); err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	sig, err := s.ctx.Sign(session, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	if s.config.mechanism == mechanismECDSA {
+		return rawToASN1(sig)
+	}
+	return sig, nil
+}
+
+// Close closes the sessions of the key. The library stays logged in, as the
+// login state is shared with the other keys of the token.
+func (s *pkcs11Signer) Close() error {
+	var retErr error
+	for {
+		select {
+		case session := <-s.sessions:
+			if err := s.ctx.CloseSession(session); err != nil && retErr == nil {
+				retErr = fmt.Errorf("failed to close session: %w", err)
+			}
+		default:
+			return retErr
+		}
+	}
+}
+
+// smallUlong decodes a CK_ULONG attribute holding a value below 256, such as
+// a key type, whatever the size and byte order of CK_ULONG on the platform.
+func smallUlong(b []byte) uint {
+	var v uint
+	for _, c := range b {
+		v |= uint(c)
+	}
+	return v
+}
+
+func isPKCS11Error(err error, code uint) bool {
+	var p11Err p11.Error
+	return errors.As(err, &p11Err) && uint(p11Err) == code
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package managedkeys
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PKCS#11 mechanisms managed keys sign with.
+const (
+	mechanismRSAPKCS    = 0x0001
+	mechanismRSAPKCSPSS = 0x000D
+	mechanismECDSA      = 0x1041
+)
+
+type pkcs11Config struct {
+	lib            string
+	slot           *uint
+	tokenLabel     string
+	pin            string
+	keyLabel       string
+	keyID          []byte
+	mechanism      uint
+	forceRWSession bool
+	maxParallel    int
+}
+
+func validatePKCS11(config map[string]string, libraries map[string]string) error {
+	_, err := parsePKCS11Config(config, libraries)
+	return err
+}
+
+func parsePKCS11Config(config map[string]string, libraries map[string]string) (*pkcs11Config, error) {
+	if err := required(config, "library", "pin", "mechanism"); err != nil {
+		return nil, err
+	}
+
+	c := &pkcs11Config{
+		tokenLabel:  config["token_label"],
+		pin:         config["pin"],
+		keyLabel:    config["key_label"],
+		maxParallel: 1,
+	}
+
+	// Library names are case-insensitive
+	for name, lib := range libraries {
+		if strings.EqualFold(name, config["library"]) {
+			c.lib = lib
+			break
+		}
+	}
+	if c.lib == "" {
+		return nil, fmt.Errorf("no kms_library stanza named %q found in the server configuration", config["library"])
+	}
+
+	switch {
+	case config["slot"] != "":
+		slot, err := strconv.ParseUint(config["slot"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slot %q: %w", config["slot"], err)
+		}
+		s := uint(slot)
+		c.slot = &s
+	case c.tokenLabel == "":
+		return nil, fmt.Errorf("either %q or %q is required", "slot", "token_label")
+	}
+
+	if id := config["key_id"]; id != "" {
+		keyID, err := hex.DecodeString(strings.TrimPrefix(id, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid key_id %q: %w", id, err)
+		}
+		c.keyID = keyID
+	}
+	if c.keyLabel == "" && len(c.keyID) == 0 {
+		return nil, fmt.Errorf("either %q or %q is required", "key_label", "key_id")
+	}
+
+	mechanism, err := strconv.ParseUint(config["mechanism"], 0, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mechanism %q: %w", config["mechanism"], err)
+	}
+	switch mechanism {
+	case mechanismRSAPKCS, mechanismRSAPKCSPSS, mechanismECDSA:
+		c.mechanism = uint(mechanism)
+	default:
+		return nil, fmt.Errorf("unsupported mechanism %q, managed keys only support signing mechanisms", config["mechanism"])
+	}
+
+	if v := config["force_rw_session"]; v != "" {
+		if c.forceRWSession, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid force_rw_session %q: %w", v, err)
+		}
+	}
+	if v := config["max_parallel"]; v != "" {
+		if c.maxParallel, err = strconv.Atoi(v); err != nil || c.maxParallel < 1 {
+			return nil, fmt.Errorf("invalid max_parallel %q", v)
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,17 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !cgo
+
+package managedkeys
+
+import (
+	"context"
+	"errors"
+)
+
+// PKCS#11 libraries are loaded through cgo, so pkcs11 managed keys are
+// unavailable without it.
+func newPKCS11Signer(_ context.Context, _ map[string]string, _ map[string]string) (Signer, error) {
+	return nil, errors.New("pkcs11 managed keys require Vault to be built with cgo enabled")
+}
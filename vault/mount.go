@@ -361,6 +361,8 @@ type MountConfig struct {
 	TokenType                 logical.TokenType     `json:"token_type,omitempty" structs:"token_type" mapstructure:"token_type"`
 	AllowedManagedKeys        []string              `json:"allowed_managed_keys,omitempty" mapstructure:"allowed_managed_keys"`
 	UserLockoutConfig         *UserLockoutConfig    `json:"user_lockout_config,omitempty" mapstructure:"user_lockout_config"`
+	DeletionProtection        bool                  `json:"deletion_protection,omitempty" mapstructure:"deletion_protection"`     // Whether disabling the mount is blocked
+	SoftDeleteRetention       time.Duration         `json:"soft_delete_retention,omitempty" mapstructure:"soft_delete_retention"` // How long the storage of the mount is retained once disabled
 
 	// PluginName is the name of the plugin registered in the catalog.
 	//
@@ -396,6 +398,8 @@ type APIMountConfig struct {
 	AllowedManagedKeys        []string              `json:"allowed_managed_keys,omitempty" mapstructure:"allowed_managed_keys"`
 	UserLockoutConfig         *UserLockoutConfig    `json:"user_lockout_config,omitempty" mapstructure:"user_lockout_config"`
 	PluginVersion             string                `json:"plugin_version,omitempty" mapstructure:"plugin_version"`
+	DeletionProtection        bool                  `json:"deletion_protection,omitempty" mapstructure:"deletion_protection"`
+	SoftDeleteRetention       string                `json:"soft_delete_retention,omitempty" mapstructure:"soft_delete_retention"`
 
 	// PluginName is the name of the plugin registered in the catalog.
 	//
@@ -578,6 +582,8 @@ func (c *Core) mount(ctx context.Context, entry *MountEntry) error {
 		return err
 	}
 
+	c.sendCoreEvent(ctx, eventTypeMountEnabled, "path", entry.Path, "type", entry.Type, "accessor", entry.Accessor)
+
 	return nil
 }
 
@@ -806,8 +812,20 @@ func (c *Core) unmount(ctx context.Context, path string) error {
 		}
 	}
 
+	// Check the mount can be deleted, and retain its storage if it is
+	// soft-deleted
+	softDeletedUUID, err := c.prepareMountDeletion(ctx, path)
+	if err != nil {
+		return err
+	}
+
 	// Unmount mount internally
 	if err := c.unmountInternal(ctx, path, MountTableUpdateStorage); err != nil {
+		if softDeletedUUID != "" {
+			if forgetErr := c.forgetDeletedMount(ctx, softDeletedUUID); forgetErr != nil {
+				c.logger.Error("failed to forget deleted mount", "path", path, "error", forgetErr)
+			}
+		}
 		return err
 	}
 
@@ -816,6 +834,8 @@ func (c *Core) unmount(ctx context.Context, path string) error {
 		// Even we failed to evaluate filtered paths, the unmount operation was still successful
 		c.logger.Error("failed to evaluate filtered paths", "error", err)
 	}
+
+	c.sendCoreEvent(ctx, eventTypeMountDisabled, "path", path)
 	return nil
 }
 
@@ -870,10 +890,18 @@ func (c *Core) unmountInternal(ctx context.Context, path string, updateStorage b
 		backend.Cleanup(ctx)
 	}
 
+	retained, err := c.isMountStorageRetained(ctx, entry.UUID)
+	if err != nil {
+		return err
+	}
+
 	viewPath := entry.ViewPath()
 	switch {
 	case !updateStorage:
 		// Don't attempt to clear data, replication will handle this
+	case retained:
+		// Don't clear the data of soft-deleted mounts, it is purged at the
+		// end of their retention window
 	case c.IsDRSecondary():
 		// If we are a dr secondary we want to clear the view, but the provided
 		// view is marked as read only. We use the barrier here to get around
@@ -1691,6 +1719,7 @@ func (c *Core) newLogicalBackend(ctx context.Context, entry *MountEntry, sysView
 	}
 	if c.IsExperimentEnabled(experiments.VaultExperimentEventsAlpha1) {
 		config.EventsSender = pluginEventSender
+		config.EventsSubscriber = pluginEventSender
 	}
 
 	ctx = context.WithValue(ctx, "core_number", c.coreNumber)
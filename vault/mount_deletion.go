@@ -0,0 +1,360 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// coreDeletedMountsPath is the path of the table of the soft-deleted mounts,
+// both secrets engines and auth methods.
+const coreDeletedMountsPath = "core/deleted-mounts"
+
+// deletedMountsPurgeInterval is how often the storage of the soft-deleted
+// mounts past their retention window is purged.
+var deletedMountsPurgeInterval = 1 * time.Hour
+
+// DeletedMountEntry is a mount which was disabled with a soft-delete
+// retention window. Its storage is retained until PurgeTime, so that it can
+// be restored.
+type DeletedMountEntry struct {
+	Entry        *MountEntry `json:"entry"`
+	DeletionTime time.Time   `json:"deletion_time"`
+	PurgeTime    time.Time   `json:"purge_time"`
+}
+
+// prepareMountDeletion checks the mount at the given API path can be
+// deleted, and records it as soft-deleted if it has a soft-delete retention
+// window, in which case its UUID is returned. Missing mounts are left to the
+// callers to report.
+func (c *Core) prepareMountDeletion(ctx context.Context, apiPath string) (softDeletedUUID string, err error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	entry := c.router.MatchingMountEntry(ctx, apiPath)
+	if entry == nil || entry.NamespaceID != ns.ID || entry.APIPathNoNamespace() != apiPath {
+		return "", nil
+	}
+
+	// The configuration of the entry is tuned with the lock of its table held
+	lock := &c.mountsLock
+	if entry.Table == credentialTableType {
+		lock = &c.authLock
+	}
+	lock.RLock()
+	deletionProtection := entry.Config.DeletionProtection
+	retention := entry.Config.SoftDeleteRetention
+	deleted, err := entry.Clone()
+	lock.RUnlock()
+	if err != nil {
+		return "", err
+	}
+
+	if deletionProtection {
+		return "", logical.CodedError(400, fmt.Sprintf("cannot disable %q: deletion protection is enabled, it must be cleared by tuning the mount first", apiPath))
+	}
+
+	if retention <= 0 {
+		return "", nil
+	}
+
+	deleted.Tainted = false
+	deleted.MountState = ""
+
+	now := time.Now()
+
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+
+	entries, err := c.loadDeletedMounts(ctx)
+	if err != nil {
+		return "", err
+	}
+	entries = append(entries, &DeletedMountEntry{
+		Entry:        deleted,
+		DeletionTime: now,
+		PurgeTime:    now.Add(retention),
+	})
+	if err := c.persistDeletedMounts(ctx, entries); err != nil {
+		return "", err
+	}
+
+	return entry.UUID, nil
+}
+
+// forgetDeletedMount removes the mount with the given UUID from the
+// soft-deleted mounts, without purging its storage.
+func (c *Core) forgetDeletedMount(ctx context.Context, uuid string) error {
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+
+	entries, err := c.loadDeletedMounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := entries[:0]
+	for _, deleted := range entries {
+		if deleted.Entry.UUID != uuid {
+			remaining = append(remaining, deleted)
+		}
+	}
+
+	return c.persistDeletedMounts(ctx, remaining)
+}
+
+// isMountStorageRetained returns true if the storage of the mount with the
+// given UUID must be retained when it is unmounted, as it is soft-deleted.
+func (c *Core) isMountStorageRetained(ctx context.Context, uuid string) (bool, error) {
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+
+	entries, err := c.loadDeletedMounts(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, deleted := range entries {
+		if deleted.Entry.UUID == uuid {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// listDeletedMounts returns the soft-deleted mounts of the namespace of the
+// context, sorted by accessor.
+func (c *Core) listDeletedMounts(ctx context.Context) ([]*DeletedMountEntry, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+
+	entries, err := c.loadDeletedMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*DeletedMountEntry
+	for _, deleted := range entries {
+		if deleted.Entry.NamespaceID == ns.ID {
+			ret = append(ret, deleted)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Entry.Accessor < ret[j].Entry.Accessor
+	})
+
+	return ret, nil
+}
+
+// getDeletedMount returns the soft-deleted mount of the namespace of the
+// context with the given accessor, or nil if there is none.
+func (c *Core) getDeletedMount(ctx context.Context, accessor string) (*DeletedMountEntry, error) {
+	entries, err := c.listDeletedMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, deleted := range entries {
+		if deleted.Entry.Accessor == accessor {
+			return deleted, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// restoreDeletedMount mounts again the soft-deleted mount with the given
+// accessor, with its retained storage. It is mounted at its original path,
+// unless another path is given.
+func (c *Core) restoreDeletedMount(ctx context.Context, accessor, path string) (*MountEntry, error) {
+	deleted, err := c.getDeletedMount(ctx, accessor)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == nil {
+		return nil, logical.CodedError(404, fmt.Sprintf("no deleted mount with accessor %q", accessor))
+	}
+	if !deleted.PurgeTime.After(time.Now()) {
+		return nil, logical.CodedError(400, fmt.Sprintf("the retention window of the deleted mount with accessor %q has ended", accessor))
+	}
+
+	entry, err := deleted.Entry.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		entry.Path = path
+	}
+
+	switch entry.Table {
+	case credentialTableType:
+		entry.Path = strings.TrimPrefix(entry.Path, credentialRoutePrefix)
+		err = c.enableCredential(ctx, entry)
+	default:
+		err = c.mount(ctx, entry)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.forgetDeletedMount(ctx, entry.UUID); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// purgeDeletedMount deletes the retained storage of the soft-deleted mount
+// with the given accessor.
+func (c *Core) purgeDeletedMount(ctx context.Context, accessor string) error {
+	deleted, err := c.getDeletedMount(ctx, accessor)
+	if err != nil {
+		return err
+	}
+	if deleted == nil {
+		return nil
+	}
+
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+
+	return c.purgeDeletedMountsLocked(ctx, func(candidate *DeletedMountEntry) bool {
+		return candidate.Entry.UUID == deleted.Entry.UUID
+	})
+}
+
+// purgeExpiredDeletedMounts deletes the retained storage of the soft-deleted
+// mounts past their retention window.
+func (c *Core) purgeExpiredDeletedMounts(ctx context.Context) error {
+	now := time.Now()
+
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+
+	return c.purgeDeletedMountsLocked(ctx, func(deleted *DeletedMountEntry) bool {
+		return !deleted.PurgeTime.After(now)
+	})
+}
+
+// purgeDeletedMountsLocked deletes the retained storage of the soft-deleted
+// mounts selected by the given function. This should be called with the
+// deletedMountsLock held.
+func (c *Core) purgeDeletedMountsLocked(ctx context.Context, purge func(*DeletedMountEntry) bool) error {
+	entries, err := c.loadDeletedMounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]*DeletedMountEntry, 0, len(entries))
+	for _, deleted := range entries {
+		if !purge(deleted) {
+			remaining = append(remaining, deleted)
+			continue
+		}
+
+		// Restored mounts may have not been forgotten if the deleted mounts
+		// failed to be updated, their storage is in use again
+		if c.router.MatchingMountByUUID(deleted.Entry.UUID) == nil {
+			logger := c.logger.Named("mounts.purge").With("namespace", deleted.Entry.NamespaceID, "path", deleted.Entry.APIPathNoNamespace())
+			if err := logical.ClearViewWithLogging(ctx, NewBarrierView(c.barrier, deleted.Entry.ViewPath()), logger); err != nil {
+				c.logger.Error("failed to purge deleted mount", "accessor", deleted.Entry.Accessor, "error", err)
+				remaining = append(remaining, deleted)
+				continue
+			}
+		}
+
+		if c.logger.IsInfo() {
+			c.logger.Info("purged deleted mount", "accessor", deleted.Entry.Accessor, "type", deleted.Entry.Type)
+		}
+	}
+
+	return c.persistDeletedMounts(ctx, remaining)
+}
+
+// startDeletedMountsPurge periodically purges the soft-deleted mounts past
+// their retention window, until the context is done.
+func (c *Core) startDeletedMountsPurge(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(deletedMountsPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.purgeExpiredDeletedMounts(ctx); err != nil && ctx.Err() == nil {
+				c.logger.Error("failed to purge deleted mounts", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// loadDeletedMounts reads the soft-deleted mounts. This should be called with
+// the deletedMountsLock held.
+func (c *Core) loadDeletedMounts(ctx context.Context) ([]*DeletedMountEntry, error) {
+	raw, err := c.barrier.Get(ctx, coreDeletedMountsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deleted mounts: %w", err)
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	var entries []*DeletedMountEntry
+	if err := raw.DecodeJSON(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode deleted mounts: %w", err)
+	}
+
+	for _, deleted := range entries {
+		ns, err := NamespaceByID(ctx, deleted.Entry.NamespaceID, c)
+		if err != nil {
+			return nil, err
+		}
+		if ns == nil {
+			c.logger.Error("namespace on deleted mount entry not found", "namespace_id", deleted.Entry.NamespaceID, "accessor", deleted.Entry.Accessor)
+			continue
+		}
+		deleted.Entry.namespace = ns
+	}
+
+	return entries, nil
+}
+
+// persistDeletedMounts writes the soft-deleted mounts. This should be called
+// with the deletedMountsLock held.
+func (c *Core) persistDeletedMounts(ctx context.Context, entries []*DeletedMountEntry) error {
+	if len(entries) == 0 {
+		return c.barrier.Delete(ctx, coreDeletedMountsPath)
+	}
+
+	entry, err := logical.StorageEntryJSON(coreDeletedMountsPath, entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode deleted mounts: %w", err)
+	}
+
+	if err := c.barrier.Put(ctx, entry); err != nil {
+		return fmt.Errorf("failed to persist deleted mounts: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_MountDeletionProtection(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/mounts/protected")
+	req.Data["type"] = "kv"
+	req.Data["config"] = map[string]interface{}{
+		"deletion_protection": true,
+	}
+	req.ClientToken = root
+	_, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/mounts/protected/tune")
+	req.ClientToken = root
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, true, resp.Data["deletion_protection"])
+
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/mounts/protected")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	require.Error(t, err)
+	require.True(t, resp.IsError())
+	require.Contains(t, resp.Error().Error(), "deletion protection is enabled")
+	require.NotNil(t, c.router.MatchingMountEntry(ctx, "protected/"))
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/mounts/protected/tune")
+	req.Data["deletion_protection"] = false
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/mounts/protected")
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Nil(t, c.router.MatchingMountEntry(ctx, "protected/"))
+}
+
+func TestCore_MountSoftDelete(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/mounts/soft")
+	req.Data["type"] = "kv"
+	req.ClientToken = root
+	_, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/mounts/soft/tune")
+	req.Data["soft_delete_retention"] = "1h"
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "soft/foo")
+	req.Data["bar"] = "baz"
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	entry := c.router.MatchingMountEntry(ctx, "soft/")
+	require.NotNil(t, entry)
+	accessor, viewPath := entry.Accessor, entry.ViewPath()
+
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/mounts/soft")
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Nil(t, c.router.MatchingMountEntry(ctx, "soft/"))
+
+	// The storage of the mount is retained
+	keys, err := logical.CollectKeys(ctx, NewBarrierView(c.barrier, viewPath))
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, keys)
+
+	req = logical.TestRequest(t, logical.ListOperation, "sys/deleted-mounts")
+	req.ClientToken = root
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, []string{accessor}, resp.Data["keys"])
+	info := resp.Data["key_info"].(map[string]interface{})[accessor].(map[string]interface{})
+	require.Equal(t, "soft/", info["path"])
+	require.Equal(t, "kv", info["type"])
+
+	// The mount is restored with its data
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/deleted-mounts/"+accessor+"/restore")
+	req.Data["path"] = "restored"
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "restored/", resp.Data["path"])
+
+	req = logical.TestRequest(t, logical.ReadOperation, "restored/foo")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "baz", resp.Data["bar"])
+	require.Equal(t, accessor, c.router.MatchingMountEntry(ctx, "restored/").Accessor)
+
+	deleted, err := c.listDeletedMounts(ctx)
+	require.NoError(t, err)
+	require.Empty(t, deleted)
+
+	// Purged mounts can't be restored
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/mounts/restored")
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/deleted-mounts/"+accessor)
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	keys, err = logical.CollectKeys(ctx, NewBarrierView(c.barrier, viewPath))
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "sys/deleted-mounts/"+accessor+"/restore")
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.Error(t, err)
+}
+
+func TestCore_MountSoftDelete_Expired(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	c.credentialBackends["noop"] = func(context.Context, *logical.BackendConfig) (logical.Backend, error) {
+		return &NoopBackend{BackendType: logical.TypeCredential}, nil
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/auth/soft")
+	req.Data["type"] = "noop"
+	req.Data["config"] = map[string]interface{}{
+		"soft_delete_retention": "1h",
+	}
+	req.ClientToken = root
+	_, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	entry := c.router.MatchingMountEntry(ctx, "auth/soft/")
+	require.NotNil(t, entry)
+	require.NoError(t, NewBarrierView(c.barrier, entry.ViewPath()).Put(ctx, &logical.StorageEntry{Key: "foo", Value: []byte("bar")}))
+
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/auth/soft")
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	deleted, err := c.getDeletedMount(ctx, entry.Accessor)
+	require.NoError(t, err)
+	require.NotNil(t, deleted)
+	require.Equal(t, "auth/soft/", deleted.Entry.APIPathNoNamespace())
+
+	// Mounts within their retention window are not purged
+	require.NoError(t, c.purgeExpiredDeletedMounts(ctx))
+	keys, err := logical.CollectKeys(ctx, NewBarrierView(c.barrier, entry.ViewPath()))
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo"}, keys)
+
+	c.deletedMountsLock.Lock()
+	entries, err := c.loadDeletedMounts(ctx)
+	require.NoError(t, err)
+	entries[0].PurgeTime = time.Now().Add(-time.Minute)
+	require.NoError(t, c.persistDeletedMounts(ctx, entries))
+	c.deletedMountsLock.Unlock()
+
+	require.NoError(t, c.purgeExpiredDeletedMounts(ctx))
+	keys, err = logical.CollectKeys(ctx, NewBarrierView(c.barrier, entry.ViewPath()))
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	deleted, err = c.getDeletedMount(ctx, entry.Accessor)
+	require.NoError(t, err)
+	require.Nil(t, deleted)
+}
@@ -7,7 +7,9 @@ package vault
 
 import (
 	"context"
+	"fmt"
 	"path"
+	"strings"
 
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/sdk/logical"
@@ -50,7 +52,21 @@ func (e *MountEntry) ViewPath() string {
 	panic("invalid mount entry")
 }
 
-func verifyNamespace(*Core, *namespace.Namespace, *MountEntry) error { return nil }
+// verifyNamespace ensures that the mount doesn't shadow a namespace within the
+// namespace it is mounted in.
+func verifyNamespace(c *Core, ns *namespace.Namespace, entry *MountEntry) error {
+	if c.namespaceStore == nil || entry.Table != mountTableType {
+		return nil
+	}
+
+	for _, child := range c.namespaceStore.childNamespaces(ns) {
+		childPath := strings.TrimPrefix(child.Path, ns.Path)
+		if strings.HasPrefix(childPath, entry.Path) || strings.HasPrefix(entry.Path, childPath) {
+			return logical.CodedError(409, fmt.Sprintf("path is already in use by namespace %s", child.Path))
+		}
+	}
+	return nil
+}
 
 // mountEntrySysView creates a logical.SystemView from global and
 // mount-specific entries; because this should be called when setting
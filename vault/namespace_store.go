@@ -0,0 +1,432 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/armon/go-radix"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-secure-stdlib/base62"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/helper/pathmanager"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// coreNamespacesPath is the path of the storage of the namespace
+	// definitions.
+	coreNamespacesPath = "core/namespaces/"
+
+	// namespaceBarrierPrefix is the prefix of the storage of the namespaces.
+	// Each namespace stores its tokens, policies and leases under the ID of
+	// the namespace, mirroring the layout of the root namespace.
+	namespaceBarrierPrefix = "namespaces/"
+
+	// namespaceIDLength is the length of the generated namespace IDs
+	namespaceIDLength = 5
+)
+
+var (
+	// reservedNamespaceNames can't be used as the name of a namespace, as
+	// they would be ambiguous with the paths handled by every namespace.
+	reservedNamespaceNames = []string{
+		namespace.RootNamespaceID,
+		"sys",
+		"audit",
+		"auth",
+		"cubbyhole",
+		"identity",
+	}
+
+	validNamespaceName = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]*[a-zA-Z0-9])?$`)
+
+	// namespaceSysPaths are the paths of the system backend that can be used
+	// in namespaces other than the root namespace. The other paths manage the
+	// whole cluster.
+	namespaceSysPaths = pathmanager.New()
+)
+
+func init() {
+	namespaceSysPaths.AddPaths([]string{
+		"sys/auth",
+		"sys/capabilities",
+		"sys/control-group/",
+		"sys/deleted-mounts",
+		"sys/internal/specs/openapi",
+		"sys/internal/ui/",
+		"sys/leases/lookup",
+		"sys/leases/renew",
+		"sys/leases/renew-batch",
+		"sys/leases/revoke",
+		"sys/locked-users",
+		"sys/managed-keys/",
+		"sys/mounts",
+		"sys/namespaces",
+		"sys/policies/acl",
+		"sys/policies/rego",
+		"sys/policy",
+		"sys/remount",
+		"sys/renew",
+		"sys/revoke",
+		"sys/tools/",
+		"sys/wrapping/",
+	})
+}
+
+// NamespaceStore keeps track of the namespaces. The namespaces are kept in
+// memory, indexed by ID and by path, since they are looked up on every
+// request.
+type NamespaceStore struct {
+	view   *BarrierView
+	logger log.Logger
+
+	// modifyLock serializes the creation, update and deletion of namespaces
+	modifyLock sync.Mutex
+
+	l      sync.RWMutex
+	byID   map[string]*namespace.Namespace
+	byPath *radix.Tree
+}
+
+// NewNamespaceStore creates a namespace store, loading the namespaces stored
+// in the given view.
+func NewNamespaceStore(ctx context.Context, view *BarrierView, logger log.Logger) (*NamespaceStore, error) {
+	s := &NamespaceStore{
+		view:   view,
+		logger: logger,
+		byID:   make(map[string]*namespace.Namespace),
+		byPath: radix.New(),
+	}
+
+	ids, err := view.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	for _, id := range ids {
+		entry, err := view.Get(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read namespace %q: %w", id, err)
+		}
+		if entry == nil {
+			continue
+		}
+
+		var ns namespace.Namespace
+		if err := jsonutil.DecodeJSON(entry.Value, &ns); err != nil {
+			return nil, fmt.Errorf("failed to decode namespace %q: %w", id, err)
+		}
+		if ns.CustomMetadata == nil {
+			ns.CustomMetadata = make(map[string]string)
+		}
+		s.byID[ns.ID] = &ns
+		s.byPath.Insert(ns.Path, &ns)
+	}
+
+	return s, nil
+}
+
+// namespaceByID returns the namespace with the given ID, or nil.
+func (s *NamespaceStore) namespaceByID(id string) *namespace.Namespace {
+	if id == namespace.RootNamespaceID {
+		return namespace.RootNamespace
+	}
+
+	s.l.RLock()
+	defer s.l.RUnlock()
+	return s.byID[id]
+}
+
+// namespaceByPath returns the deepest namespace the path is within. Every path
+// is within the root namespace.
+func (s *NamespaceStore) namespaceByPath(path string) *namespace.Namespace {
+	s.l.RLock()
+	defer s.l.RUnlock()
+
+	_, raw, ok := s.byPath.LongestPrefix(path)
+	if !ok {
+		return namespace.RootNamespace
+	}
+	return raw.(*namespace.Namespace)
+}
+
+// listNamespaces returns the root namespace, followed by every other
+// namespace sorted by path.
+func (s *NamespaceStore) listNamespaces() []*namespace.Namespace {
+	s.l.RLock()
+	defer s.l.RUnlock()
+
+	namespaces := make([]*namespace.Namespace, 0, len(s.byID)+1)
+	namespaces = append(namespaces, namespace.RootNamespace)
+	s.byPath.Walk(func(_ string, raw interface{}) bool {
+		namespaces = append(namespaces, raw.(*namespace.Namespace))
+		return false
+	})
+	return namespaces
+}
+
+// childNamespaces returns the namespaces directly within the given namespace,
+// sorted by path.
+func (s *NamespaceStore) childNamespaces(parent *namespace.Namespace) []*namespace.Namespace {
+	s.l.RLock()
+	defer s.l.RUnlock()
+
+	var children []*namespace.Namespace
+	s.byPath.WalkPrefix(parent.Path, func(path string, raw interface{}) bool {
+		if path == parent.Path {
+			return false
+		}
+		if !strings.Contains(strings.TrimSuffix(strings.TrimPrefix(path, parent.Path), "/"), "/") {
+			children = append(children, raw.(*namespace.Namespace))
+		}
+		return false
+	})
+	return children
+}
+
+// putNamespace persists the namespace and adds it to the store, replacing the
+// namespace with the same ID if there is one.
+func (s *NamespaceStore) putNamespace(ctx context.Context, ns *namespace.Namespace) error {
+	entry, err := logical.StorageEntryJSON(ns.ID, ns)
+	if err != nil {
+		return fmt.Errorf("failed to encode namespace: %w", err)
+	}
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if err := s.view.Put(ctx, entry); err != nil {
+		return fmt.Errorf("failed to persist namespace: %w", err)
+	}
+	s.byID[ns.ID] = ns
+	s.byPath.Insert(ns.Path, ns)
+	return nil
+}
+
+// deleteNamespace removes the namespace from the store.
+func (s *NamespaceStore) deleteNamespace(ctx context.Context, ns *namespace.Namespace) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if err := s.view.Delete(ctx, ns.ID); err != nil {
+		return fmt.Errorf("failed to delete namespace: %w", err)
+	}
+	delete(s.byID, ns.ID)
+	s.byPath.Delete(ns.Path)
+	return nil
+}
+
+// setupNamespaceStore loads the namespaces. It must happen before the mounts
+// are loaded, since mounts reference the namespace they belong to.
+func (c *Core) setupNamespaceStore(ctx context.Context) error {
+	nsLogger := c.baseLogger.Named("namespaces")
+	c.AddLogger(nsLogger)
+
+	store, err := NewNamespaceStore(ctx, NewBarrierView(c.barrier, coreNamespacesPath), nsLogger)
+	if err != nil {
+		return err
+	}
+	c.namespaceStore = store
+	return nil
+}
+
+func (c *Core) teardownNamespaceStore() error {
+	c.namespaceStore = nil
+	return nil
+}
+
+// validateNamespaceName checks that the name can be used for a namespace.
+func validateNamespaceName(name string) error {
+	if strutil.StrListContains(reservedNamespaceNames, strings.ToLower(name)) {
+		return fmt.Errorf("%q is a reserved name", name)
+	}
+	if !validNamespaceName.MatchString(name) {
+		return fmt.Errorf("%q is not a valid name, names must only contain letters, digits, '_', '-' and '.'", name)
+	}
+	return nil
+}
+
+// createNamespace creates a namespace named after the last segment of the
+// path, within the namespace the rest of the path designates relatively to
+// the parent namespace.
+func (c *Core) createNamespace(ctx context.Context, parent *namespace.Namespace, path string, customMetadata map[string]string) (*namespace.Namespace, error) {
+	path = namespace.Canonicalize(path)
+	if path == "" {
+		return nil, errors.New("missing namespace path")
+	}
+
+	c.namespaceStore.modifyLock.Lock()
+	defer c.namespaceStore.modifyLock.Unlock()
+
+	fullPath := parent.Path + path
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	name := segments[len(segments)-1]
+	if err := validateNamespaceName(name); err != nil {
+		return nil, err
+	}
+
+	parent = c.namespaceStore.namespaceByPath(strings.TrimSuffix(fullPath, name+"/"))
+	if parent.Path+name+"/" != fullPath {
+		return nil, fmt.Errorf("parent namespace %q does not exist", strings.TrimSuffix(fullPath, name+"/"))
+	}
+	parentCtx := namespace.ContextWithNamespace(ctx, parent)
+
+	if existing := c.namespaceStore.namespaceByPath(fullPath); existing.Path == fullPath {
+		return nil, logical.CodedError(409, fmt.Sprintf("namespace %q already exists", fullPath))
+	}
+
+	// The namespace would shadow the mounts of its parent
+	if match := c.router.MountConflict(parentCtx, name+"/"); match != "" {
+		return nil, logical.CodedError(409, fmt.Sprintf("existing mount at %s", match))
+	}
+
+	var id string
+	for {
+		var err error
+		id, err = base62.Random(namespaceIDLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate namespace ID: %w", err)
+		}
+		if c.namespaceStore.namespaceByID(id) == nil {
+			break
+		}
+	}
+
+	ns := &namespace.Namespace{
+		ID:             id,
+		Path:           fullPath,
+		CustomMetadata: make(map[string]string, len(customMetadata)),
+	}
+	for k, v := range customMetadata {
+		ns.CustomMetadata[k] = v
+	}
+
+	if err := c.namespaceStore.putNamespace(ctx, ns); err != nil {
+		return nil, err
+	}
+
+	// Load the default policies in the new namespace
+	nsCtx := namespace.ContextWithNamespace(ctx, ns)
+	for name, policy := range map[string]string{
+		defaultPolicyName:          defaultPolicy,
+		responseWrappingPolicyName: responseWrappingPolicy,
+		controlGroupPolicyName:     controlGroupPolicy,
+	} {
+		if err := c.policyStore.loadACLPolicyInternal(nsCtx, name, policy); err != nil {
+			return nil, fmt.Errorf("failed to load %s policy: %w", name, err)
+		}
+	}
+
+	c.logger.Info("created namespace", "path", ns.Path, "id", ns.ID)
+	return ns, nil
+}
+
+// patchNamespace replaces the custom metadata of the namespace.
+func (c *Core) patchNamespace(ctx context.Context, ns *namespace.Namespace, customMetadata map[string]string) (*namespace.Namespace, error) {
+	c.namespaceStore.modifyLock.Lock()
+	defer c.namespaceStore.modifyLock.Unlock()
+
+	patched := &namespace.Namespace{
+		ID:             ns.ID,
+		Path:           ns.Path,
+		CustomMetadata: customMetadata,
+	}
+	if patched.CustomMetadata == nil {
+		patched.CustomMetadata = make(map[string]string)
+	}
+
+	if err := c.namespaceStore.putNamespace(ctx, patched); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// deleteNamespace deletes an empty namespace: its secrets engines, auth
+// methods, child namespaces and identities must be removed beforehand. The
+// tokens and policies of the namespace are deleted along with it.
+func (c *Core) deleteNamespace(ctx context.Context, ns *namespace.Namespace) error {
+	if ns.ID == namespace.RootNamespaceID {
+		return errors.New("the root namespace can't be deleted")
+	}
+
+	c.namespaceStore.modifyLock.Lock()
+	defer c.namespaceStore.modifyLock.Unlock()
+
+	if children := c.namespaceStore.childNamespaces(ns); len(children) > 0 {
+		return logical.CodedError(409, fmt.Sprintf("namespace %q has child namespaces", ns.Path))
+	}
+
+	if mounts := c.namespaceMounts(ns); len(mounts) > 0 {
+		sort.Strings(mounts)
+		return logical.CodedError(409, fmt.Sprintf("namespace %q has secrets engines or auth methods enabled: %s", ns.Path, strings.Join(mounts, ", ")))
+	}
+
+	if c.identityStore != nil {
+		txn := c.identityStore.db.Txn(false)
+		for _, table := range []string{entitiesTable, groupsTable} {
+			raw, err := txn.First(table, "namespace_id", ns.ID)
+			if err != nil {
+				return err
+			}
+			if raw != nil {
+				return logical.CodedError(409, fmt.Sprintf("namespace %q has identity %s", ns.Path, table))
+			}
+		}
+	}
+
+	// Revoke the tokens of the namespace, along with their leases
+	nsCtx := namespace.ContextWithNamespace(ctx, ns)
+	if c.expiration != nil {
+		if err := c.expiration.RevokePrefix(nsCtx, "auth/token/", true); err != nil {
+			return fmt.Errorf("failed to revoke tokens: %w", err)
+		}
+	}
+
+	if err := logical.ClearViewWithLogging(nsCtx, NamespaceView(c.barrier, ns), c.logger.Named("namespaces.deletion").With("namespace", ns.ID)); err != nil {
+		return fmt.Errorf("failed to delete the storage of the namespace: %w", err)
+	}
+
+	if err := c.namespaceStore.deleteNamespace(ctx, ns); err != nil {
+		return err
+	}
+
+	c.logger.Info("deleted namespace", "path", ns.Path, "id", ns.ID)
+	return nil
+}
+
+// namespaceMounts returns the paths of the secrets engines and auth methods
+// enabled in the namespace.
+func (c *Core) namespaceMounts(ns *namespace.Namespace) []string {
+	var paths []string
+
+	c.mountsLock.RLock()
+	if c.mounts != nil {
+		for _, entry := range c.mounts.Entries {
+			if entry.NamespaceID == ns.ID {
+				paths = append(paths, entry.Path)
+			}
+		}
+	}
+	c.mountsLock.RUnlock()
+
+	c.authLock.RLock()
+	if c.auth != nil {
+		for _, entry := range c.auth.Entries {
+			if entry.NamespaceID == ns.ID {
+				paths = append(paths, credentialRoutePrefix+entry.Path)
+			}
+		}
+	}
+	c.authLock.RUnlock()
+
+	return paths
+}
@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/pathmanager"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+func testNamespaceRequest(t *testing.T, c *Core, ctx context.Context, token string, op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+	t.Helper()
+
+	req := logical.TestRequest(t, op, path)
+	req.ClientToken = token
+	req.Data = data
+	return c.HandleRequest(ctx, req)
+}
+
+func TestNamespaceStore_CreateAndDelete(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	rootCtx := namespace.RootContext(nil)
+
+	resp, err := testNamespaceRequest(t, c, rootCtx, root, logical.UpdateOperation, "sys/namespaces/ns1", map[string]interface{}{
+		"custom_metadata": map[string]interface{}{"team": "a"},
+	})
+	if err != nil || resp.IsError() {
+		t.Fatalf("err: %v, resp: %#v", err, resp)
+	}
+	if resp.Data["path"] != "ns1/" || resp.Data["id"] == "" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	ns1 := c.NamespaceByPath("ns1/")
+	if ns1.Path != "ns1/" {
+		t.Fatalf("namespace not found: %#v", ns1)
+	}
+	ns1Ctx := namespace.ContextWithNamespace(rootCtx, ns1)
+
+	// Nested namespaces are created relatively to the namespace of the request
+	resp, err = testNamespaceRequest(t, c, ns1Ctx, root, logical.UpdateOperation, "sys/namespaces/ns2", nil)
+	if err != nil || resp.IsError() {
+		t.Fatalf("err: %v, resp: %#v", err, resp)
+	}
+	if resp.Data["path"] != "ns1/ns2/" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Existing namespaces and reserved names are rejected
+	if _, err := testNamespaceRequest(t, c, rootCtx, root, logical.UpdateOperation, "sys/namespaces/ns1", nil); err == nil {
+		t.Fatal("expected an error creating an existing namespace")
+	}
+	if _, err := testNamespaceRequest(t, c, rootCtx, root, logical.UpdateOperation, "sys/namespaces/sys", nil); err == nil {
+		t.Fatal("expected an error creating a namespace with a reserved name")
+	}
+	if _, err := testNamespaceRequest(t, c, rootCtx, root, logical.UpdateOperation, "sys/namespaces/secret", nil); err == nil {
+		t.Fatal("expected an error creating a namespace conflicting with a mount")
+	}
+
+	resp, err = testNamespaceRequest(t, c, rootCtx, root, logical.ListOperation, "sys/namespaces", nil)
+	if err != nil || resp.IsError() {
+		t.Fatalf("err: %v, resp: %#v", err, resp)
+	}
+	if keys := resp.Data["keys"].([]string); len(keys) != 1 || keys[0] != "ns1/" {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// Namespaces with children can't be deleted
+	if _, err := testNamespaceRequest(t, c, rootCtx, root, logical.DeleteOperation, "sys/namespaces/ns1", nil); err == nil {
+		t.Fatal("expected an error deleting a namespace with children")
+	}
+	if _, err := testNamespaceRequest(t, c, ns1Ctx, root, logical.DeleteOperation, "sys/namespaces/ns2", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testNamespaceRequest(t, c, rootCtx, root, logical.DeleteOperation, "sys/namespaces/ns1", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if ns := c.NamespaceByPath("ns1/"); ns.ID != namespace.RootNamespaceID {
+		t.Fatalf("namespace still exists: %#v", ns)
+	}
+}
+
+func TestNamespaceStore_Patch(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	rootCtx := namespace.RootContext(nil)
+
+	_, err := testNamespaceRequest(t, c, rootCtx, root, logical.UpdateOperation, "sys/namespaces/ns1", map[string]interface{}{
+		"custom_metadata": map[string]interface{}{"team": "a", "env": "dev"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := testNamespaceRequest(t, c, rootCtx, root, logical.PatchOperation, "sys/namespaces/ns1", map[string]interface{}{
+		"custom_metadata": map[string]interface{}{"team": "b", "env": nil},
+	})
+	if err != nil || resp.IsError() {
+		t.Fatalf("err: %v, resp: %#v", err, resp)
+	}
+
+	metadata := c.NamespaceByPath("ns1/").CustomMetadata
+	if len(metadata) != 1 || metadata["team"] != "b" {
+		t.Fatalf("bad: %#v", metadata)
+	}
+}
+
+func TestNamespaceStore_Isolation(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	rootCtx := namespace.RootContext(nil)
+
+	if _, err := testNamespaceRequest(t, c, rootCtx, root, logical.UpdateOperation, "sys/namespaces/ns1", nil); err != nil {
+		t.Fatal(err)
+	}
+	ns1Ctx := namespace.ContextWithNamespace(rootCtx, c.NamespaceByPath("ns1/"))
+
+	// Mounts are created within the namespace
+	if _, err := testNamespaceRequest(t, c, ns1Ctx, root, logical.UpdateOperation, "sys/mounts/kv", map[string]interface{}{"type": "kv"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testNamespaceRequest(t, c, ns1Ctx, root, logical.UpdateOperation, "kv/foo", map[string]interface{}{"bar": "baz"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := testNamespaceRequest(t, c, ns1Ctx, root, logical.ReadOperation, "kv/foo", nil)
+	if err != nil || resp == nil || resp.Data["bar"] != "baz" {
+		t.Fatalf("err: %v, resp: %#v", err, resp)
+	}
+	if match := c.router.MatchingMount(rootCtx, "kv/foo"); match != "" {
+		t.Fatalf("mount of the namespace is visible from the root namespace: %q", match)
+	}
+
+	// Policies are scoped to the namespace
+	if _, err := testNamespaceRequest(t, c, ns1Ctx, root, logical.UpdateOperation, "sys/policy/reader", map[string]interface{}{
+		"policy": `path "kv/*" { capabilities = ["read"] }`,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if policy, err := c.policyStore.GetPolicy(rootCtx, "reader", PolicyTypeACL); err != nil || policy != nil {
+		t.Fatalf("err: %v, policy of the namespace is visible from the root namespace: %#v", err, policy)
+	}
+
+	// Paths reserved to the root namespace aren't available
+	if _, err := testNamespaceRequest(t, c, ns1Ctx, root, logical.ReadOperation, "sys/audit", nil); err == nil {
+		t.Fatal("expected an error accessing sys/audit within a namespace")
+	}
+
+	// Namespaces with mounts can't be deleted
+	if _, err := testNamespaceRequest(t, c, rootCtx, root, logical.DeleteOperation, "sys/namespaces/ns1", nil); err == nil {
+		t.Fatal("expected an error deleting a namespace with mounts")
+	}
+	if _, err := testNamespaceRequest(t, c, ns1Ctx, root, logical.DeleteOperation, "sys/mounts/kv", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testNamespaceRequest(t, c, rootCtx, root, logical.DeleteOperation, "sys/namespaces/ns1", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNamespaceStore_Persistence(t *testing.T) {
+	c, keys, root := TestCoreUnsealed(t)
+	rootCtx := namespace.RootContext(nil)
+
+	resp, err := testNamespaceRequest(t, c, rootCtx, root, logical.UpdateOperation, "sys/namespaces/ns1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := resp.Data["id"].(string)
+
+	if err := c.Seal(root); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if _, err := TestCoreUnseal(c, TestKeyCopy(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ns, err := c.NamespaceByID(rootCtx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns == nil || ns.Path != "ns1/" {
+		t.Fatalf("bad: %#v", ns)
+	}
+}
+
+// TestNamespaceStore_SysPaths checks that every path of the system backend is
+// either usable in namespaces or known to manage the whole cluster, so that
+// new endpoints are not silently restricted to the root namespace.
+func TestNamespaceStore_SysPaths(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	// rootSysPaths are the paths of the system backend that can only be used
+	// in the root namespace
+	rootSysPaths := pathmanager.New()
+	rootSysPaths.AddPaths([]string{
+		"sys/attestation/",
+		"sys/audit",
+		"sys/config/",
+		"sys/decode-token",
+		"sys/experiments",
+		"sys/generate-root",
+		"sys/ha-status",
+		"sys/health",
+		"sys/host-info",
+		"sys/in-flight-req",
+		"sys/init",
+		"sys/internal/counters/",
+		"sys/internal/inspect/",
+		"sys/key-status",
+		"sys/leader",
+		"sys/leases",
+		"!sys/leases/",
+		"sys/leases/count",
+		"sys/leases/tidy",
+		"sys/license/",
+		"sys/loggers",
+		"sys/metrics",
+		"sys/mfa/",
+		"sys/monitor",
+		"sys/plugins/",
+		"sys/policies/egp/",
+		"sys/policies/password/",
+		"sys/policies/rgp/",
+		"sys/pprof/",
+		"sys/quotas/",
+		"sys/rekey/",
+		"sys/replication/",
+		"sys/rotate",
+		"sys/seal",
+		"sys/sealwrap/",
+		"sys/step-down",
+		"sys/storage/",
+		"sys/unseal",
+		"sys/version-history/",
+	})
+
+	for _, p := range c.systemBackend.Backend.Paths {
+		for _, path := range testSysPathPrefixes(p.Pattern) {
+			inNamespaces := namespaceSysPaths.HasPath(path)
+			inRoot := rootSysPaths.HasPath(path)
+			switch {
+			case inNamespaces && inRoot:
+				t.Errorf("path %q (pattern %q) is both namespaced and root only", path, p.Pattern)
+			case !inNamespaces && !inRoot:
+				t.Errorf("path %q (pattern %q) is neither namespaced nor root only", path, p.Pattern)
+			}
+		}
+	}
+}
+
+// testSysPathPrefixes returns the literal prefixes of the requests a system
+// backend path pattern matches, expanding a leading optional group.
+func testSysPathPrefixes(pattern string) []string {
+	pattern = strings.TrimPrefix(pattern, "^")
+
+	patterns := []string{pattern}
+	if strings.HasPrefix(pattern, "(") {
+		if i := strings.Index(pattern, ")?"); i != -1 {
+			patterns = []string{pattern[i+2:], pattern[1:i] + pattern[i+2:]}
+		}
+	}
+
+	var prefixes []string
+	for _, pattern := range patterns {
+		if i := strings.IndexAny(pattern, `([?$.*+\`); i != -1 {
+			pattern = pattern[:i]
+		}
+		prefixes = append(prefixes, "sys/"+pattern)
+	}
+	return prefixes
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
 )
 
 var NamespaceByID func(context.Context, string, *Core) (*namespace.Namespace, error) = namespaceByID
@@ -15,5 +16,26 @@ func namespaceByID(ctx context.Context, nsID string, c *Core) (*namespace.Namesp
 	if nsID == namespace.RootNamespaceID {
 		return namespace.RootNamespace, nil
 	}
-	return nil, namespace.ErrNoNamespace
+	if c.namespaceStore == nil {
+		return nil, namespace.ErrNoNamespace
+	}
+	return c.namespaceStore.namespaceByID(nsID), nil
+}
+
+// NamespaceView returns the view of the storage of a namespace. The root
+// namespace uses the whole barrier.
+func NamespaceView(barrier logical.Storage, ns *namespace.Namespace) *BarrierView {
+	if ns.ID == namespace.RootNamespaceID {
+		return NewBarrierView(barrier, "")
+	}
+	return NewBarrierView(barrier, namespaceBarrierPrefix+ns.ID+"/")
+}
+
+// namespacedView returns the view of the namespace that matches the given
+// view of the root namespace.
+func (c *Core) namespacedView(ns *namespace.Namespace, view *BarrierView) *BarrierView {
+	if ns == nil || ns.ID == namespace.RootNamespaceID {
+		return view
+	}
+	return NamespaceView(c.barrier, ns).SubView(view.Prefix())
 }
@@ -16,5 +16,10 @@ func (c *Core) NamespaceByID(ctx context.Context, nsID string) (*namespace.Names
 }
 
 func (c *Core) ListNamespaces(includePath bool) []*namespace.Namespace {
-	return []*namespace.Namespace{namespace.RootNamespace}
+	return c.collectNamespaces()
+}
+
+// NamespaceByPath returns the deepest namespace the given path is within.
+func (c *Core) NamespaceByPath(path string) *namespace.Namespace {
+	return c.namespaceByPath(path)
 }
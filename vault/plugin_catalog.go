@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/go-secure-stdlib/base62"
 	semver "github.com/hashicorp/go-version"
+	"github.com/hashicorp/vault/builtin/plugin/wasm"
 	"github.com/hashicorp/vault/helper/versions"
 	v4 "github.com/hashicorp/vault/sdk/database/dbplugin"
 	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
@@ -38,6 +39,9 @@ var (
 	ErrPluginNotFound           = errors.New("plugin not found in the catalog")
 	ErrPluginConnectionNotFound = errors.New("plugin connection not found for client")
 	ErrPluginBadType            = errors.New("unable to determine plugin type")
+	ErrPluginBadRuntime         = errors.New("unsupported plugin runtime")
+	ErrPluginInvalidWASM        = errors.New("invalid WASM plugin")
+	ErrPluginBadLimits          = errors.New("invalid plugin resource limits")
 )
 
 // PluginCatalog keeps a record of plugins known to vault. External plugins need
@@ -59,6 +63,14 @@ type PluginCatalog struct {
 	externalPlugins map[externalPluginsKey]*externalPlugin
 	mlockPlugins    bool
 
+	// cgroupParent is the cgroup v2 directory the cgroups enforcing the
+	// resource limits of the plugin processes are created in.
+	cgroupParent string
+
+	// runtimeStatus holds the restarts and crashes of the plugins run by
+	// the catalog, by the same key as externalPlugins.
+	runtimeStatus map[externalPluginsKey]*pluginRuntimeStatus
+
 	lock    sync.RWMutex
 	wrapper pluginutil.RunnerUtil
 }
@@ -79,6 +91,9 @@ type externalPluginsKey struct {
 	env     string
 	sha256  string
 	builtin bool
+
+	memoryLimit int64
+	cpuLimit    float64
 }
 
 func makeExternalPluginsKey(p *pluginutil.PluginRunner) (externalPluginsKey, error) {
@@ -101,6 +116,9 @@ func makeExternalPluginsKey(p *pluginutil.PluginRunner) (externalPluginsKey, err
 		env:     string(env),
 		sha256:  hex.EncodeToString(p.Sha256),
 		builtin: p.Builtin,
+
+		memoryLimit: p.MemoryLimit,
+		cpuLimit:    p.CPULimit,
 	}, nil
 }
 
@@ -124,6 +142,7 @@ type pluginClient struct {
 	// client handles the lifecycle of a plugin process
 	// multiplexed plugins share the same client
 	client      *plugin.Client
+	process     *pluginProcess
 	clientConn  grpc.ClientConnInterface
 	cleanupFunc func() error
 	reloadFunc  func() error
@@ -173,6 +192,7 @@ func (c *Core) setupPluginCatalog(ctx context.Context) error {
 		directory:       c.pluginDirectory,
 		logger:          c.logger,
 		mlockPlugins:    c.enableMlock,
+		cgroupParent:    c.pluginCgroupParent,
 		wrapper:         logical.StaticSystemView{VersionString: version.GetVersion().Version},
 	}
 
@@ -183,6 +203,8 @@ func (c *Core) setupPluginCatalog(ctx context.Context) error {
 		return err
 	}
 
+	go c.pluginCatalog.supervise(ctx)
+
 	if c.logger.IsInfo() {
 		c.logger.Info("successfully setup plugin catalog", "plugin-directory", c.pluginDirectory)
 	}
@@ -243,7 +265,7 @@ func (c *PluginCatalog) reloadExternalPlugin(key externalPluginsKey, id, path st
 	}
 
 	delete(c.externalPlugins, key)
-	pc.client.Kill()
+	c.killPluginProcess(key, pc)
 	c.logger.Debug("killed external plugin process for reload", "path", path, "pid", pc.pid)
 
 	return nil
@@ -278,14 +300,14 @@ func (c *PluginCatalog) cleanupExternalPlugin(key externalPluginsKey, id, path s
 	c.logger.Debug("removed plugin client connection", "id", id)
 
 	if !extPlugin.multiplexingSupport {
-		pc.client.Kill()
+		c.killPluginProcess(key, pc)
 
 		if len(extPlugin.connections) == 0 {
 			delete(c.externalPlugins, key)
 		}
 		c.logger.Debug("killed external plugin process", "path", path, "pid", pc.pid)
 	} else if len(extPlugin.connections) == 0 || pc.client.Exited() {
-		pc.client.Kill()
+		c.killPluginProcess(key, pc)
 		delete(c.externalPlugins, key)
 		c.logger.Debug("killed external multiplexed plugin process", "path", path, "pid", pc.pid)
 	}
@@ -373,7 +395,12 @@ func (c *PluginCatalog) newPluginClient(ctx context.Context, pluginRunner *plugi
 
 	// Multiplexing support will always be false initially, but will be
 	// adjusted once we query from the plugin whether it can multiplex or not
-	if !extPlugin.multiplexingSupport || len(extPlugin.connections) == 0 {
+	spawned := !extPlugin.multiplexingSupport || len(extPlugin.connections) == 0
+	if spawned {
+		if err := c.recordPluginStart(key); err != nil {
+			return nil, err
+		}
+
 		c.logger.Debug("spawning a new plugin process", "plugin_name", pluginRunner.Name, "id", id)
 		client, err := pluginRunner.RunConfig(ctx,
 			pluginutil.PluginSets(config.PluginSets),
@@ -389,12 +416,14 @@ func (c *PluginCatalog) newPluginClient(ctx context.Context, pluginRunner *plugi
 		}
 
 		pc.client = client
+		pc.process = &pluginProcess{}
 	} else {
 		c.logger.Debug("returning existing plugin client for multiplexed plugin", "id", id)
 
 		// get the first client, since they are all the same
 		for k := range extPlugin.connections {
 			pc.client = extPlugin.connections[k].client
+			pc.process = extPlugin.connections[k].process
 			break
 		}
 
@@ -407,6 +436,9 @@ func (c *PluginCatalog) newPluginClient(ctx context.Context, pluginRunner *plugi
 	// Subsequent calls to this will return the same client.
 	rpcClient, err := pc.client.Client()
 	if err != nil {
+		if spawned {
+			c.recordPluginCrash(key, 0)
+		}
 		return nil, err
 	}
 
@@ -416,6 +448,14 @@ func (c *PluginCatalog) newPluginClient(ctx context.Context, pluginRunner *plugi
 		pc.pid = conf.Pid
 	}
 
+	if spawned {
+		pc.process.cgroup, err = c.limitPluginProcess(pluginRunner, id, pc.pid)
+		if err != nil {
+			pc.client.Kill()
+			return nil, fmt.Errorf("error limiting the resources of the plugin process: %w", err)
+		}
+	}
+
 	clientConn := rpcClient.(*plugin.GRPCClient).Conn
 
 	muxed, err := pluginutil.MultiplexingSupported(ctx, clientConn, config.Name)
@@ -773,7 +813,18 @@ func (c *PluginCatalog) UpgradePlugins(ctx context.Context, logger log.Logger) e
 		plugin.Command = filepath.Join(c.directory, plugin.Command)
 
 		// Upgrade the storage. At this point we don't know what type of plugin this is so pass in the unknown type.
-		runner, err := c.setInternal(ctx, pluginName, consts.PluginTypeUnknown, plugin.Version, cmdOld, plugin.Args, plugin.Env, plugin.Sha256)
+		runner, err := c.setInternal(ctx, pluginutil.SetPluginInput{
+			Name:        pluginName,
+			Type:        consts.PluginTypeUnknown,
+			Version:     plugin.Version,
+			Command:     cmdOld,
+			Args:        plugin.Args,
+			Env:         plugin.Env,
+			Sha256:      plugin.Sha256,
+			Runtime:     plugin.Runtime,
+			MemoryLimit: plugin.MemoryLimit,
+			CPULimit:    plugin.CPULimit,
+		})
 		if err != nil {
 			if errors.Is(err, ErrPluginBadType) {
 				retErr = multierror.Append(retErr, fmt.Errorf("could not upgrade plugin %s: plugin of unknown type", pluginName))
@@ -867,30 +918,31 @@ func (c *PluginCatalog) get(ctx context.Context, name string, pluginType consts.
 }
 
 // Set registers a new external plugin with the catalog, or updates an existing
-// external plugin. It takes the name, command and SHA256 of the plugin.
-func (c *PluginCatalog) Set(ctx context.Context, name string, pluginType consts.PluginType, version string, command string, args []string, env []string, sha256 []byte) error {
+// external plugin. It takes the name, command and SHA256 of the plugin, and
+// the runtime it is run with, which is empty for plugin executables.
+func (c *PluginCatalog) Set(ctx context.Context, plugin pluginutil.SetPluginInput) error {
 	if c.directory == "" {
 		return ErrDirectoryNotConfigured
 	}
 
 	switch {
-	case strings.Contains(name, ".."):
+	case strings.Contains(plugin.Name, ".."):
 		fallthrough
-	case strings.Contains(command, ".."):
+	case strings.Contains(plugin.Command, ".."):
 		return consts.ErrPathContainsParentReferences
 	}
 
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	_, err := c.setInternal(ctx, name, pluginType, version, command, args, env, sha256)
+	_, err := c.setInternal(ctx, plugin)
 	return err
 }
 
-func (c *PluginCatalog) setInternal(ctx context.Context, name string, pluginType consts.PluginType, version string, command string, args []string, env []string, sha256 []byte) (*pluginutil.PluginRunner, error) {
+func (c *PluginCatalog) setInternal(ctx context.Context, plugin pluginutil.SetPluginInput) (*pluginutil.PluginRunner, error) {
 	// Best effort check to make sure the command isn't breaking out of the
 	// configured plugin directory.
-	commandFull := filepath.Join(c.directory, command)
+	commandFull := filepath.Join(c.directory, plugin.Command)
 	sym, err := filepath.EvalSymlinks(commandFull)
 	if err != nil {
 		return nil, fmt.Errorf("error while validating the command path: %w", err)
@@ -904,6 +956,71 @@ func (c *PluginCatalog) setInternal(ctx context.Context, name string, pluginType
 		return nil, errors.New("cannot execute files outside of configured plugin directory")
 	}
 
+	if plugin.MemoryLimit < 0 || plugin.CPULimit < 0 {
+		return nil, fmt.Errorf("%w: limits cannot be negative", ErrPluginBadLimits)
+	}
+
+	pluginType, version := plugin.Type, plugin.Version
+	switch plugin.Runtime {
+	case "":
+		version, pluginType, err = c.checkPluginExecutable(ctx, plugin.Name, pluginType, version, commandFull, plugin.Args, plugin.Env, plugin.Sha256)
+		if err != nil {
+			return nil, err
+		}
+	case pluginutil.RuntimeWASM:
+		// WASM plugins are not run to determine their type and version, as
+		// they only implement the secrets and auth backend interface.
+		switch pluginType {
+		case consts.PluginTypeSecrets, consts.PluginTypeCredential:
+		default:
+			return nil, fmt.Errorf("%w: %s plugins cannot be run with the %q runtime", ErrPluginBadRuntime, pluginType, plugin.Runtime)
+		}
+		if plugin.MemoryLimit != 0 || plugin.CPULimit != 0 {
+			return nil, fmt.Errorf("%w: limits are not supported by the %q runtime", ErrPluginBadLimits, plugin.Runtime)
+		}
+		if err := wasm.Validate(ctx, commandFull, plugin.Sha256); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrPluginInvalidWASM, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrPluginBadRuntime, plugin.Runtime)
+	}
+
+	entry := &pluginutil.PluginRunner{
+		Name:        plugin.Name,
+		Type:        pluginType,
+		Version:     version,
+		Command:     plugin.Command,
+		Args:        plugin.Args,
+		Env:         plugin.Env,
+		Sha256:      plugin.Sha256,
+		Builtin:     false,
+		Runtime:     plugin.Runtime,
+		MemoryLimit: plugin.MemoryLimit,
+		CPULimit:    plugin.CPULimit,
+	}
+
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin entry: %w", err)
+	}
+
+	storageKey := path.Join(pluginType.String(), plugin.Name)
+	if version != "" {
+		storageKey = path.Join(storageKey, version)
+	}
+	logicalEntry := logical.StorageEntry{
+		Key:   storageKey,
+		Value: buf,
+	}
+	if err := c.catalogView.Put(ctx, &logicalEntry); err != nil {
+		return nil, fmt.Errorf("failed to persist plugin entry: %w", err)
+	}
+	return entry, nil
+}
+
+// checkPluginExecutable runs a plugin executable to determine its type if it
+// is unknown, and its version if it isn't set.
+func (c *PluginCatalog) checkPluginExecutable(ctx context.Context, name string, pluginType consts.PluginType, version string, commandFull string, args []string, env []string, sha256 []byte) (string, consts.PluginType, error) {
 	// entryTmp should only be used for the below type and version checks, it uses the
 	// full command instead of the relative command.
 	entryTmp := &pluginutil.PluginRunner{
@@ -916,12 +1033,13 @@ func (c *PluginCatalog) setInternal(ctx context.Context, name string, pluginType
 	}
 	// If the plugin type is unknown, we want to attempt to determine the type
 	if pluginType == consts.PluginTypeUnknown {
+		var err error
 		pluginType, err = c.getPluginTypeFromUnknown(ctx, entryTmp)
 		if err != nil {
-			return nil, err
+			return "", consts.PluginTypeUnknown, err
 		}
 		if pluginType == consts.PluginTypeUnknown {
-			return nil, ErrPluginBadType
+			return "", consts.PluginTypeUnknown, ErrPluginBadType
 		}
 	}
 
@@ -934,50 +1052,23 @@ func (c *PluginCatalog) setInternal(ctx context.Context, name string, pluginType
 	case consts.PluginTypeDatabase:
 		runningVersion, versionErr = c.getDatabaseRunningVersion(ctx, entryTmp)
 	default:
-		return nil, fmt.Errorf("unknown plugin type: %v", pluginType)
+		return "", consts.PluginTypeUnknown, fmt.Errorf("unknown plugin type: %v", pluginType)
 	}
 	if versionErr != nil {
 		c.logger.Warn("Error determining plugin version", "error", versionErr)
 	} else if version != "" && runningVersion.Version != "" && version != runningVersion.Version {
 		c.logger.Warn("Plugin self-reported version did not match requested version", "plugin", name, "requestedVersion", version, "reportedVersion", runningVersion.Version)
-		return nil, fmt.Errorf("plugin version mismatch: %s reported version (%s) did not match requested version (%s)", name, runningVersion.Version, version)
+		return "", consts.PluginTypeUnknown, fmt.Errorf("plugin version mismatch: %s reported version (%s) did not match requested version (%s)", name, runningVersion.Version, version)
 	} else if version == "" && runningVersion.Version != "" {
 		version = runningVersion.Version
 		_, err := semver.NewVersion(version)
 		if err != nil {
-			return nil, fmt.Errorf("plugin self-reported version %q is not a valid semantic version: %w", version, err)
+			return "", consts.PluginTypeUnknown, fmt.Errorf("plugin self-reported version %q is not a valid semantic version: %w", version, err)
 		}
 
 	}
 
-	entry := &pluginutil.PluginRunner{
-		Name:    name,
-		Type:    pluginType,
-		Version: version,
-		Command: command,
-		Args:    args,
-		Env:     env,
-		Sha256:  sha256,
-		Builtin: false,
-	}
-
-	buf, err := json.Marshal(entry)
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode plugin entry: %w", err)
-	}
-
-	storageKey := path.Join(pluginType.String(), name)
-	if version != "" {
-		storageKey = path.Join(storageKey, version)
-	}
-	logicalEntry := logical.StorageEntry{
-		Key:   storageKey,
-		Value: buf,
-	}
-	if err := c.catalogView.Put(ctx, &logicalEntry); err != nil {
-		return nil, fmt.Errorf("failed to persist plugin entry: %w", err)
-	}
-	return entry, nil
+	return version, pluginType, nil
 }
 
 // Delete is used to remove an external plugin from the catalog. Builtin plugins
@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -14,14 +15,17 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/vault/api"
 	"github.com/hashicorp/vault/builtin/credential/userpass"
+	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/helper/versions"
 	"github.com/hashicorp/vault/plugins/database/postgresql"
 	v5 "github.com/hashicorp/vault/sdk/database/dbplugin/v5"
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/hashicorp/vault/sdk/helper/pluginutil"
+	"github.com/hashicorp/vault/sdk/logical"
 	backendplugin "github.com/hashicorp/vault/sdk/plugin"
 
 	"github.com/hashicorp/vault/helper/builtinplugins"
@@ -79,7 +83,14 @@ func TestPluginCatalog_CRUD(t *testing.T) {
 	defer file.Close()
 
 	command := filepath.Base(file.Name())
-	err = core.pluginCatalog.Set(context.Background(), pluginName, consts.PluginTypeDatabase, "", command, []string{"--test"}, []string{"FOO=BAR"}, []byte{'1'})
+	err = core.pluginCatalog.Set(context.Background(), pluginutil.SetPluginInput{
+		Name:    pluginName,
+		Type:    consts.PluginTypeDatabase,
+		Command: command,
+		Args:    []string{"--test"},
+		Env:     []string{"FOO=BAR"},
+		Sha256:  []byte{'1'},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -163,7 +174,15 @@ func TestPluginCatalog_VersionedCRUD(t *testing.T) {
 	const name = "mysql-database-plugin"
 	const version = "1.0.0"
 	command := fmt.Sprintf("%s", filepath.Base(file.Name()))
-	err = core.pluginCatalog.Set(context.Background(), name, consts.PluginTypeDatabase, version, command, []string{"--test"}, []string{"FOO=BAR"}, []byte{'1'})
+	err = core.pluginCatalog.Set(context.Background(), pluginutil.SetPluginInput{
+		Name:    name,
+		Type:    consts.PluginTypeDatabase,
+		Version: version,
+		Command: command,
+		Args:    []string{"--test"},
+		Env:     []string{"FOO=BAR"},
+		Sha256:  []byte{'1'},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -270,13 +289,27 @@ func TestPluginCatalog_List(t *testing.T) {
 	defer file.Close()
 
 	command := filepath.Base(file.Name())
-	err = core.pluginCatalog.Set(context.Background(), "mysql-database-plugin", consts.PluginTypeDatabase, "", command, []string{"--test"}, []string{}, []byte{'1'})
+	err = core.pluginCatalog.Set(context.Background(), pluginutil.SetPluginInput{
+		Name:    "mysql-database-plugin",
+		Type:    consts.PluginTypeDatabase,
+		Command: command,
+		Args:    []string{"--test"},
+		Env:     []string{},
+		Sha256:  []byte{'1'},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Set another plugin
-	err = core.pluginCatalog.Set(context.Background(), "aaaaaaa", consts.PluginTypeDatabase, "", command, []string{"--test"}, []string{}, []byte{'1'})
+	err = core.pluginCatalog.Set(context.Background(), pluginutil.SetPluginInput{
+		Name:    "aaaaaaa",
+		Type:    consts.PluginTypeDatabase,
+		Command: command,
+		Args:    []string{"--test"},
+		Env:     []string{},
+		Sha256:  []byte{'1'},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -341,31 +374,28 @@ func TestPluginCatalog_ListVersionedPlugins(t *testing.T) {
 	defer file.Close()
 
 	command := filepath.Base(file.Name())
-	err = core.pluginCatalog.Set(
-		context.Background(),
-		"mysql-database-plugin",
-		consts.PluginTypeDatabase,
-		"",
-		command,
-		[]string{"--test"},
-		[]string{},
-		[]byte{'1'},
-	)
+	err = core.pluginCatalog.Set(context.Background(), pluginutil.SetPluginInput{
+		Name:    "mysql-database-plugin",
+		Type:    consts.PluginTypeDatabase,
+		Command: command,
+		Args:    []string{"--test"},
+		Env:     []string{},
+		Sha256:  []byte{'1'},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Set another plugin, with version information
-	err = core.pluginCatalog.Set(
-		context.Background(),
-		"aaaaaaa",
-		consts.PluginTypeDatabase,
-		"1.1.0",
-		command,
-		[]string{"--test"},
-		[]string{},
-		[]byte{'1'},
-	)
+	err = core.pluginCatalog.Set(context.Background(), pluginutil.SetPluginInput{
+		Name:    "aaaaaaa",
+		Type:    consts.PluginTypeDatabase,
+		Version: "1.1.0",
+		Command: command,
+		Args:    []string{"--test"},
+		Env:     []string{},
+		Sha256:  []byte{'1'},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -458,7 +488,12 @@ func TestPluginCatalog_ListHandlesPluginNamesWithSlashes(t *testing.T) {
 		},
 	}
 	for _, entry := range pluginsToRegister {
-		err = core.pluginCatalog.Set(ctx, entry.Name, consts.PluginTypeCredential, entry.Version, command, nil, nil, nil)
+		err = core.pluginCatalog.Set(ctx, pluginutil.SetPluginInput{
+			Name:    entry.Name,
+			Type:    consts.PluginTypeCredential,
+			Version: entry.Version,
+			Command: command,
+		})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -578,6 +613,234 @@ func TestPluginCatalog_NewPluginClient(t *testing.T) {
 	}
 }
 
+// testWASMPlugin is a WASM plugin module that responds to every request with
+// an empty response.
+var testWASMPlugin = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	// Types: (i32) -> i32, (i32, i32) -> i64
+	0x01, 0x0c, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e,
+	// Functions
+	0x03, 0x03, 0x02, 0x00, 0x01,
+	// Memory
+	0x05, 0x03, 0x01, 0x00, 0x01,
+	// Exports: memory, vault_alloc, vault_handle_request
+	0x07, 0x2f, 0x03,
+	0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+	0x0b, 'v', 'a', 'u', 'l', 't', '_', 'a', 'l', 'l', 'o', 'c', 0x00, 0x00,
+	0x14, 'v', 'a', 'u', 'l', 't', '_', 'h', 'a', 'n', 'd', 'l', 'e', '_', 'r', 'e', 'q', 'u', 'e', 's', 't', 0x00, 0x01,
+	// Code: vault_alloc returns 1024, vault_handle_request returns the
+	// response at 0 of length 2
+	0x0a, 0x0c, 0x02,
+	0x05, 0x00, 0x41, 0x80, 0x08, 0x0b,
+	0x04, 0x00, 0x42, 0x02, 0x0b,
+	// Data: "{}" at 0
+	0x0b, 0x08, 0x01, 0x00, 0x41, 0x00, 0x0b, 0x02, '{', '}',
+}
+
+func TestPluginCatalog_WASM(t *testing.T) {
+	core, _, root := TestCoreUnsealed(t)
+	tempDir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	core.pluginCatalog.directory = tempDir
+
+	if err := os.WriteFile(filepath.Join(tempDir, "plugin.wasm"), testWASMPlugin, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(testWASMPlugin)
+
+	ctx := namespace.RootContext(nil)
+	err = core.pluginCatalog.Set(ctx, pluginutil.SetPluginInput{
+		Name:    "wasm-plugin",
+		Type:    consts.PluginTypeSecrets,
+		Command: "plugin.wasm",
+		Sha256:  sum[:],
+		Runtime: "unknown",
+	})
+	if !errors.Is(err, ErrPluginBadRuntime) {
+		t.Fatalf("expected %v, got: %v", ErrPluginBadRuntime, err)
+	}
+	err = core.pluginCatalog.Set(ctx, pluginutil.SetPluginInput{
+		Name:    "wasm-plugin",
+		Type:    consts.PluginTypeDatabase,
+		Command: "plugin.wasm",
+		Sha256:  sum[:],
+		Runtime: pluginutil.RuntimeWASM,
+	})
+	if !errors.Is(err, ErrPluginBadRuntime) {
+		t.Fatalf("expected %v, got: %v", ErrPluginBadRuntime, err)
+	}
+	err = core.pluginCatalog.Set(ctx, pluginutil.SetPluginInput{
+		Name:    "wasm-plugin",
+		Type:    consts.PluginTypeSecrets,
+		Command: "plugin.wasm",
+		Sha256:  []byte{'1'},
+		Runtime: pluginutil.RuntimeWASM,
+	})
+	if !errors.Is(err, ErrPluginInvalidWASM) {
+		t.Fatalf("expected %v, got: %v", ErrPluginInvalidWASM, err)
+	}
+	err = core.pluginCatalog.Set(ctx, pluginutil.SetPluginInput{
+		Name:        "wasm-plugin",
+		Type:        consts.PluginTypeSecrets,
+		Command:     "plugin.wasm",
+		Sha256:      sum[:],
+		Runtime:     pluginutil.RuntimeWASM,
+		MemoryLimit: 1 << 20,
+	})
+	if !errors.Is(err, ErrPluginBadLimits) {
+		t.Fatalf("expected %v, got: %v", ErrPluginBadLimits, err)
+	}
+
+	err = core.pluginCatalog.Set(ctx, pluginutil.SetPluginInput{
+		Name:    "wasm-plugin",
+		Type:    consts.PluginTypeSecrets,
+		Version: "v1.0.0",
+		Command: "plugin.wasm",
+		Sha256:  sum[:],
+		Runtime: pluginutil.RuntimeWASM,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := core.pluginCatalog.Get(ctx, "wasm-plugin", consts.PluginTypeSecrets, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Runtime != pluginutil.RuntimeWASM || p.Version != "v1.0.0" {
+		t.Fatalf("bad: %#v", p)
+	}
+
+	err = core.mount(ctx, &MountEntry{
+		Table:   mountTableType,
+		Path:    "wasm/",
+		Type:    "wasm-plugin",
+		Version: "v1.0.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := core.HandleRequest(ctx, &logical.Request{
+		Operation:   logical.ReadOperation,
+		Path:        "wasm/foo",
+		ClientToken: root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("bad: %#v", resp)
+	}
+}
+
+func TestPluginCatalog_RestartBackoff(t *testing.T) {
+	core, _, _ := TestCoreUnsealed(t)
+	c := core.pluginCatalog
+	key := externalPluginsKey{name: "foo", typ: consts.PluginTypeSecrets}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.recordPluginStart(key); err != nil {
+		t.Fatal(err)
+	}
+	c.recordPluginCrash(key, 100)
+	// The same process is only counted once
+	c.recordPluginCrash(key, 100)
+
+	status := c.runtimeStatus[key]
+	if status.starts != 1 || status.crashes != 1 {
+		t.Fatalf("bad: %#v", status)
+	}
+	if backoff := time.Until(status.backoffUntil); backoff <= 0 || backoff > pluginRestartBackoffMin {
+		t.Fatalf("bad backoff: %s", backoff)
+	}
+	if err := c.recordPluginStart(key); !errors.Is(err, ErrPluginRestartBackoff) {
+		t.Fatalf("expected %v, got: %v", ErrPluginRestartBackoff, err)
+	}
+
+	// The backoff doubles while the plugin keeps crashing
+	status.backoffUntil = time.Now()
+	if err := c.recordPluginStart(key); err != nil {
+		t.Fatal(err)
+	}
+	c.recordPluginCrash(key, 101)
+	if status.starts != 2 || status.crashes != 2 {
+		t.Fatalf("bad: %#v", status)
+	}
+	if backoff := time.Until(status.backoffUntil); backoff <= pluginRestartBackoffMin || backoff > 2*pluginRestartBackoffMin {
+		t.Fatalf("bad backoff: %s", backoff)
+	}
+
+	// It is reset once the plugin stops crashing
+	status.lastCrash = time.Now().Add(-pluginCrashLoopWindow - time.Minute)
+	c.recordPluginCrash(key, 102)
+	if backoff := time.Until(status.backoffUntil); backoff > pluginRestartBackoffMin {
+		t.Fatalf("bad backoff: %s", backoff)
+	}
+
+	for i := 0; i < 64; i++ {
+		c.recordPluginCrash(key, 200+i)
+	}
+	if backoff := time.Until(status.backoffUntil); backoff <= 0 || backoff > pluginRestartBackoffMax {
+		t.Fatalf("bad backoff: %s", backoff)
+	}
+
+	// The processes run while registering plugins are not tracked
+	unknown := externalPluginsKey{name: "foo", typ: consts.PluginTypeUnknown}
+	if err := c.recordPluginStart(unknown); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.runtimeStatus[unknown]; ok {
+		t.Fatal("expected the plugin not to be tracked")
+	}
+}
+
+func TestPluginCatalog_LimitPluginProcess(t *testing.T) {
+	core, _, _ := TestCoreUnsealed(t)
+	c := core.pluginCatalog
+
+	runner := &pluginutil.PluginRunner{
+		Name: "foo",
+		Type: consts.PluginTypeSecrets,
+	}
+	dir, err := c.limitPluginProcess(runner, "id", 100)
+	if err != nil || dir != "" {
+		t.Fatalf("expected the process not to be limited, got: %q, %v", dir, err)
+	}
+
+	runner.MemoryLimit = 256 << 20
+	runner.CPULimit = 0.5
+	if _, err := c.limitPluginProcess(runner, "id", 100); !errors.Is(err, ErrPluginBadLimits) {
+		t.Fatalf("expected %v, got: %v", ErrPluginBadLimits, err)
+	}
+
+	c.cgroupParent = t.TempDir()
+	dir, err = c.limitPluginProcess(runner, "id", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join(c.cgroupParent, "secret-foo-id"); dir != expected {
+		t.Fatalf("expected %s, got: %s", expected, dir)
+	}
+
+	for file, expected := range map[string]string{
+		"memory.max":   "268435456",
+		"cpu.max":      "50000 100000",
+		"cgroup.procs": "100",
+	} {
+		actual, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != expected {
+			t.Fatalf("expected %s to be %q, got: %q", file, expected, actual)
+		}
+	}
+}
+
 func TestPluginCatalog_MakeExternalPluginsKey_Comparable(t *testing.T) {
 	var plugins []pluginutil.PluginRunner
 	hasher := sha256.New()
@@ -249,6 +249,11 @@ func (c *Core) reloadBackendCommon(ctx context.Context, entry *MountEntry, isAut
 				return err
 			}
 			re.loginPaths.Store(loginPathsEntry)
+			streamingPathsEntry, err := parseUnauthenticatedPaths(paths.Streaming)
+			if err != nil {
+				return err
+			}
+			re.streamingPaths.Store(streamingPathsEntry)
 		}
 	}
 
@@ -0,0 +1,383 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/vault/sdk/helper/consts"
+	"github.com/hashicorp/vault/sdk/helper/pluginutil"
+	"github.com/shirou/gopsutil/v3/process"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// pluginHealthCheckInterval is how often the plugin processes are probed.
+	pluginHealthCheckInterval = 10 * time.Second
+
+	// pluginHealthCheckTimeout is how long a plugin process has to answer a
+	// probe.
+	pluginHealthCheckTimeout = 5 * time.Second
+
+	// pluginHealthCheckThreshold is the number of consecutive failed probes
+	// after which a plugin process is killed. It is restarted on its next
+	// request.
+	pluginHealthCheckThreshold = 3
+
+	// pluginRestartBackoffMin and pluginRestartBackoffMax bound the time a
+	// crashed plugin is not restarted for. The backoff doubles with every
+	// crash that happens within pluginCrashLoopWindow of the previous one.
+	pluginRestartBackoffMin = 1 * time.Second
+	pluginRestartBackoffMax = 5 * time.Minute
+	pluginCrashLoopWindow   = 10 * time.Minute
+
+	// cgroupCPUPeriod is the period, in microseconds, of the CPU limit of the
+	// plugin cgroups.
+	cgroupCPUPeriod = 100000
+)
+
+var ErrPluginRestartBackoff = errors.New("plugin is backing off after crashing")
+
+// pluginProcess holds the state of a plugin process, shared by the
+// connections to it.
+type pluginProcess struct {
+	// cgroup is the directory of the cgroup limiting the process, if any.
+	cgroup string
+
+	healthCheckFailures int
+}
+
+// pluginRuntimeStatus records the restarts and crashes of a plugin.
+type pluginRuntimeStatus struct {
+	starts       int
+	crashes      int
+	lastCrash    time.Time
+	lastCrashPID int
+	backoffUntil time.Time
+
+	// recentCrashes is the number of crashes in the current crash loop.
+	recentCrashes int
+}
+
+// recordPluginStart counts a new process of the plugin, unless it is backing
+// off after crashing. This should be called with the write lock held.
+func (c *PluginCatalog) recordPluginStart(key externalPluginsKey) error {
+	// The processes run to determine the type and version of plugins being
+	// registered are not tracked.
+	if key.typ == consts.PluginTypeUnknown {
+		return nil
+	}
+
+	if c.runtimeStatus == nil {
+		c.runtimeStatus = make(map[externalPluginsKey]*pluginRuntimeStatus)
+	}
+	status, ok := c.runtimeStatus[key]
+	if !ok {
+		status = &pluginRuntimeStatus{}
+		c.runtimeStatus[key] = status
+	}
+
+	if wait := time.Until(status.backoffUntil); wait > 0 {
+		return fmt.Errorf("%w: retrying in %s", ErrPluginRestartBackoff, wait.Round(time.Second))
+	}
+	status.starts++
+	return nil
+}
+
+// recordPluginCrash counts a crash of the plugin, and backs off restarting
+// it. Crashes of the same process are only counted once. This should be
+// called with the write lock held.
+func (c *PluginCatalog) recordPluginCrash(key externalPluginsKey, pid int) {
+	status, ok := c.runtimeStatus[key]
+	if !ok || (pid != 0 && pid == status.lastCrashPID) {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(status.lastCrash) > pluginCrashLoopWindow {
+		status.recentCrashes = 0
+	}
+	status.crashes++
+	status.recentCrashes++
+	status.lastCrash = now
+	status.lastCrashPID = pid
+
+	backoff := pluginRestartBackoffMax
+	if status.recentCrashes <= 16 {
+		backoff = pluginRestartBackoffMin << (status.recentCrashes - 1)
+		if backoff > pluginRestartBackoffMax {
+			backoff = pluginRestartBackoffMax
+		}
+	}
+	status.backoffUntil = now.Add(backoff)
+
+	c.logger.Warn("plugin process crashed", "plugin", key.name, "type", key.typ.String(), "version", key.version,
+		"pid", pid, "crashes", status.crashes, "backoff", backoff)
+}
+
+// killPluginProcess kills the process of the plugin client, counting it as a
+// crash if it already exited, and removes its cgroup. This should be called
+// with the write lock held.
+func (c *PluginCatalog) killPluginProcess(key externalPluginsKey, pc *pluginClient) {
+	if pc.client.Exited() {
+		c.recordPluginCrash(key, pc.pid)
+	}
+	pc.client.Kill()
+
+	if pc.process != nil && pc.process.cgroup != "" {
+		if err := os.Remove(pc.process.cgroup); err != nil && !os.IsNotExist(err) {
+			c.logger.Warn("error removing the plugin cgroup", "cgroup", pc.process.cgroup, "error", err)
+		}
+		pc.process.cgroup = ""
+	}
+}
+
+// limitPluginProcess moves the plugin process into a new cgroup enforcing the
+// resource limits of the plugin, and returns its directory. The process is
+// not limited if the plugin has no limits.
+func (c *PluginCatalog) limitPluginProcess(runner *pluginutil.PluginRunner, id string, pid int) (string, error) {
+	if runner.MemoryLimit == 0 && runner.CPULimit == 0 {
+		return "", nil
+	}
+	if c.cgroupParent == "" {
+		return "", fmt.Errorf("%w: plugin_cgroup_parent is not configured", ErrPluginBadLimits)
+	}
+
+	dir := filepath.Join(c.cgroupParent, fmt.Sprintf("%s-%s-%s", runner.Type.String(), runner.Name, id))
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	// The process is moved last, so that it is never in the new cgroup
+	// without its limits.
+	var files [][2]string
+	if runner.MemoryLimit > 0 {
+		files = append(files, [2]string{"memory.max", strconv.FormatInt(runner.MemoryLimit, 10)})
+	}
+	if runner.CPULimit > 0 {
+		quota := int64(runner.CPULimit * cgroupCPUPeriod)
+		files = append(files, [2]string{"cpu.max", fmt.Sprintf("%d %d", quota, cgroupCPUPeriod)})
+	}
+	files = append(files, [2]string{"cgroup.procs", strconv.Itoa(pid)})
+
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file[0]), []byte(file[1]), 0o644); err != nil {
+			os.Remove(dir)
+			return "", fmt.Errorf("error writing %s of cgroup %s: %w", file[0], dir, err)
+		}
+	}
+
+	return dir, nil
+}
+
+// supervise probes the health of the plugin processes until the context is
+// done.
+func (c *PluginCatalog) supervise(ctx context.Context) {
+	ticker := time.NewTicker(pluginHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkPluginHealth(ctx)
+		}
+	}
+}
+
+// supervisedProcess is a plugin process and one of the connections to it.
+type supervisedProcess struct {
+	key externalPluginsKey
+	pc  *pluginClient
+}
+
+// pluginProcesses returns a connection to each plugin process. This should be
+// called with the lock held.
+func (c *PluginCatalog) pluginProcesses() []supervisedProcess {
+	var ret []supervisedProcess
+	for key, extPlugin := range c.externalPlugins {
+		seen := make(map[*plugin.Client]bool)
+		for _, pc := range extPlugin.connections {
+			if seen[pc.client] {
+				continue
+			}
+			seen[pc.client] = true
+			ret = append(ret, supervisedProcess{key: key, pc: pc})
+		}
+	}
+	return ret
+}
+
+// checkPluginHealth probes every plugin process, and kills the ones that
+// failed pluginHealthCheckThreshold probes in a row. Processes that exited
+// are counted as crashed.
+func (c *PluginCatalog) checkPluginHealth(ctx context.Context) {
+	c.lock.RLock()
+	procs := c.pluginProcesses()
+	c.lock.RUnlock()
+
+	for _, p := range procs {
+		var err error
+		if !p.pc.client.Exited() {
+			err = probePluginProcess(ctx, p.pc)
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		c.lock.Lock()
+		// The process may have been cleaned up while it was probed
+		if extPlugin, ok := c.externalPlugins[p.key]; !ok || extPlugin.connections[p.pc.id] != p.pc {
+			c.lock.Unlock()
+			continue
+		}
+		switch {
+		case p.pc.client.Exited():
+			c.recordPluginCrash(p.key, p.pc.pid)
+		case err != nil:
+			p.pc.process.healthCheckFailures++
+			c.logger.Warn("plugin health check failed", "plugin", p.key.name, "pid", p.pc.pid,
+				"failures", p.pc.process.healthCheckFailures, "error", err)
+			if p.pc.process.healthCheckFailures >= pluginHealthCheckThreshold {
+				// The backend reloads the plugin on its next request.
+				c.logger.Error("killing unhealthy plugin process", "plugin", p.key.name, "pid", p.pc.pid)
+				c.recordPluginCrash(p.key, p.pc.pid)
+				p.pc.client.Kill()
+			}
+		default:
+			p.pc.process.healthCheckFailures = 0
+		}
+		c.lock.Unlock()
+	}
+}
+
+func probePluginProcess(ctx context.Context, pc *pluginClient) error {
+	ctx, cancel := context.WithTimeout(ctx, pluginHealthCheckTimeout)
+	defer cancel()
+
+	resp, err := grpc_health_v1.NewHealthClient(pc.clientConn).Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: plugin.GRPCServiceName,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("plugin status is %s", resp.Status)
+	}
+	return nil
+}
+
+// PluginRuntimeStatus returns the status of the plugins run by the catalog
+// and of their processes.
+func (c *PluginCatalog) PluginRuntimeStatus() []map[string]interface{} {
+	type processStatus struct {
+		pid                 int
+		running             bool
+		healthCheckFailures int
+	}
+
+	c.lock.RLock()
+	keys := make([]externalPluginsKey, 0, len(c.runtimeStatus))
+	for key := range c.runtimeStatus {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		if keys[i].typ != keys[j].typ {
+			return keys[i].typ < keys[j].typ
+		}
+		return keys[i].version < keys[j].version
+	})
+
+	ret := make([]map[string]interface{}, 0, len(keys))
+	procs := make([][]processStatus, 0, len(keys))
+	for _, key := range keys {
+		status := c.runtimeStatus[key]
+
+		var (
+			multiplexed bool
+			connections int
+			keyProcs    []processStatus
+		)
+		if extPlugin, ok := c.externalPlugins[key]; ok {
+			multiplexed = extPlugin.multiplexingSupport
+			connections = len(extPlugin.connections)
+			seen := make(map[*plugin.Client]bool)
+			for _, pc := range extPlugin.connections {
+				if seen[pc.client] {
+					continue
+				}
+				seen[pc.client] = true
+				keyProcs = append(keyProcs, processStatus{
+					pid:                 pc.pid,
+					running:             !pc.client.Exited(),
+					healthCheckFailures: pc.process.healthCheckFailures,
+				})
+			}
+		}
+		sort.Slice(keyProcs, func(i, j int) bool { return keyProcs[i].pid < keyProcs[j].pid })
+		procs = append(procs, keyProcs)
+
+		entry := map[string]interface{}{
+			"name":         key.name,
+			"type":         key.typ.String(),
+			"version":      key.version,
+			"multiplexed":  multiplexed,
+			"connections":  connections,
+			"starts":       status.starts,
+			"restarts":     0,
+			"crashes":      status.crashes,
+			"memory_limit": key.memoryLimit,
+			"cpu_limit":    key.cpuLimit,
+		}
+		if status.starts > 1 {
+			entry["restarts"] = status.starts - 1
+		}
+		if !status.lastCrash.IsZero() {
+			entry["last_crash"] = status.lastCrash.UTC().Format(time.RFC3339)
+		}
+		if status.backoffUntil.After(time.Now()) {
+			entry["backoff_until"] = status.backoffUntil.UTC().Format(time.RFC3339)
+		}
+		ret = append(ret, entry)
+	}
+	c.lock.RUnlock()
+
+	// The resource usage is read without holding the lock
+	for i, keyProcs := range procs {
+		processes := make([]map[string]interface{}, 0, len(keyProcs))
+		for _, p := range keyProcs {
+			ps := map[string]interface{}{
+				"pid":                   p.pid,
+				"running":               p.running,
+				"health_check_failures": p.healthCheckFailures,
+			}
+			if p.running {
+				if proc, err := process.NewProcess(int32(p.pid)); err == nil {
+					if mem, err := proc.MemoryInfo(); err == nil {
+						ps["memory_usage"] = mem.RSS
+					}
+					if times, err := proc.Times(); err == nil {
+						ps["cpu_seconds"] = times.User + times.System
+					}
+				}
+			}
+			processes = append(processes, ps)
+		}
+		ret[i]["processes"] = processes
+	}
+
+	return ret
+}
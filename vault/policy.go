@@ -64,8 +64,10 @@ const (
 	PolicyTypeRGP
 	PolicyTypeEGP
 
-	// Triggers a lookup in the map to figure out if ACL or RGP
+	// Triggers a lookup in the map to figure out if ACL, RGP or Rego
 	PolicyTypeToken
+
+	PolicyTypeRego
 )
 
 func (p PolicyType) String() string {
@@ -76,6 +78,8 @@ func (p PolicyType) String() string {
 		return "rgp"
 	case PolicyTypeEGP:
 		return "egp"
+	case PolicyTypeRego:
+		return "rego"
 	}
 
 	return ""
@@ -106,6 +110,9 @@ type Policy struct {
 	Type      PolicyType
 	Templated bool
 	namespace *namespace.Namespace
+
+	// regoModule is the compiled module of a Rego policy
+	regoModule *regoModule
 }
 
 // ShallowClone returns a shallow clone of the policy. This should not be used
@@ -119,6 +126,7 @@ func (p *Policy) ShallowClone() *Policy {
 		Type:           p.Type,
 		Templated:      p.Templated,
 		namespace:      p.namespace,
+		regoModule:     p.regoModule,
 	}
 }
 
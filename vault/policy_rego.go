@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/vault/helper/identity"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+const (
+	// regoAllowRule is the rule of a Rego policy that must be true for the
+	// requests that the ACL policies of the token allow to be allowed
+	regoAllowRule = "allow"
+
+	// regoDenyRule is the rule of a Rego policy that denies requests. It is
+	// either a boolean or a set of messages explaining the denial.
+	regoDenyRule = "deny"
+)
+
+// regoUnsafeBuiltins are the built-in functions Rego policies can't call,
+// since policies are evaluated on every request they apply to and must not
+// reach out to other systems or read the environment of the server.
+var regoUnsafeBuiltins = map[string]struct{}{
+	ast.HTTPSend.Name:               {},
+	ast.NetLookupIPAddr.Name:        {},
+	ast.OPARuntime.Name:             {},
+	ast.RegoParseModule.Name:        {},
+	ast.Trace.Name:                  {},
+	ast.ProvidersAWSSignReqObj.Name: {},
+}
+
+// regoModule is a Rego policy module prepared for evaluation by the embedded
+// Open Policy Agent engine. The allow and deny queries are nil if the module
+// doesn't define the rule.
+type regoModule struct {
+	allow *rego.PreparedEvalQuery
+	deny  *rego.PreparedEvalQuery
+}
+
+// parseRegoPolicy parses the module of a Rego policy, which must define an
+// allow or a deny rule in its package, and prepares the queries of these
+// rules.
+func parseRegoPolicy(raw string) (*regoModule, error) {
+	module, err := ast.ParseModule("policy.rego", raw)
+	if err != nil {
+		return nil, err
+	}
+	if module == nil {
+		return nil, errors.New("empty module")
+	}
+
+	var allowDefined, denyDefined bool
+	for _, rule := range module.Rules {
+		switch rule.Head.Ref()[0].String() {
+		case regoAllowRule:
+			allowDefined = true
+		case regoDenyRule:
+			denyDefined = true
+		}
+	}
+	if !allowDefined && !denyDefined {
+		return nil, fmt.Errorf("module must define an %q or a %q rule", regoAllowRule, regoDenyRule)
+	}
+
+	prepare := func(rule string) (*rego.PreparedEvalQuery, error) {
+		query, err := rego.New(
+			rego.Query(module.Package.Path.String()+"."+rule),
+			rego.ParsedModule(module),
+			rego.UnsafeBuiltins(regoUnsafeBuiltins),
+			rego.StrictBuiltinErrors(true),
+		).PrepareForEval(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return &query, nil
+	}
+
+	ret := &regoModule{}
+	if allowDefined {
+		if ret.allow, err = prepare(regoAllowRule); err != nil {
+			return nil, err
+		}
+	}
+	if denyDefined {
+		if ret.deny, err = prepare(regoDenyRule); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+// eval evaluates the query against the input, returning false if the rule
+// is undefined
+func (m *regoModule) eval(ctx context.Context, query *rego.PreparedEvalQuery, input map[string]interface{}) (interface{}, bool, error) {
+	rs, err := query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil, false, nil
+	}
+	return rs[0].Expressions[0].Value, true, nil
+}
+
+func (ps *PolicyStore) getRegoView(ns *namespace.Namespace) *BarrierView {
+	return ps.core.namespacedView(ns, ps.regoView)
+}
+
+// performRegoPolicyChecks evaluates the Rego policies of the ACL against a
+// request that the ACL policies allow. Rego policies can only further
+// restrict requests: any policy whose deny rule is true or non-empty, or whose
+// allow rule is defined but not true, denies the request.
+func (c *Core) performRegoPolicyChecks(ctx context.Context, acl *ACL, te *logical.TokenEntry, req *logical.Request, entity *identity.Entity, ret *AuthResults) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		ret.Error = multierror.Append(ret.Error, err)
+		ret.ACLResults.Allowed = false
+		ret.DeniedError = true
+		return
+	}
+	input := regoPolicyInput(ns, te, req, entity)
+
+	for _, policy := range acl.regoPolicies {
+		module := policy.regoModule
+		if module.deny != nil {
+			value, defined, err := module.eval(ctx, module.deny, input)
+			if err != nil {
+				c.logger.Error("failed to evaluate Rego policy", "policy", policy.Name, "error", err)
+				ret.Error = multierror.Append(ret.Error, fmt.Errorf("failed to evaluate Rego policy %q", policy.Name))
+				break
+			}
+			if defined {
+				switch value := value.(type) {
+				case bool:
+					if value {
+						ret.Error = multierror.Append(ret.Error, fmt.Errorf("denied by Rego policy %q", policy.Name))
+					}
+				case []interface{}:
+					for _, msg := range value {
+						ret.Error = multierror.Append(ret.Error, fmt.Errorf("denied by Rego policy %q: %v", policy.Name, msg))
+					}
+				case nil:
+				default:
+					ret.Error = multierror.Append(ret.Error, fmt.Errorf("denied by Rego policy %q: %v", policy.Name, value))
+				}
+			}
+		}
+
+		if module.allow != nil {
+			value, _, err := module.eval(ctx, module.allow, input)
+			if err != nil {
+				c.logger.Error("failed to evaluate Rego policy", "policy", policy.Name, "error", err)
+				ret.Error = multierror.Append(ret.Error, fmt.Errorf("failed to evaluate Rego policy %q", policy.Name))
+				break
+			}
+			// An undefined allow rule doesn't allow the request
+			if allowed, ok := value.(bool); !ok || !allowed {
+				ret.Error = multierror.Append(ret.Error, fmt.Errorf("not allowed by Rego policy %q", policy.Name))
+			}
+		}
+	}
+
+	if ret.Error.ErrorOrNil() != nil {
+		ret.ACLResults.Allowed = false
+		ret.DeniedError = true
+	}
+}
+
+// regoPolicyInput builds the input document of Rego policies
+func regoPolicyInput(ns *namespace.Namespace, te *logical.TokenEntry, req *logical.Request, entity *identity.Entity) map[string]interface{} {
+	input := map[string]interface{}{
+		"operation": string(req.Operation),
+		"path":      req.Path,
+		"namespace": ns.Path,
+		"data":      req.Data,
+	}
+	if req.Connection != nil {
+		input["remote_address"] = req.Connection.RemoteAddr
+	}
+
+	if te != nil {
+		input["token"] = map[string]interface{}{
+			"display_name": te.DisplayName,
+			"policies":     te.Policies,
+			"meta":         te.Meta,
+			"entity_id":    te.EntityID,
+			"type":         te.Type.String(),
+		}
+	}
+
+	if entity != nil {
+		aliases := make([]map[string]interface{}, 0, len(entity.Aliases))
+		for _, alias := range entity.Aliases {
+			aliases = append(aliases, map[string]interface{}{
+				"id":             alias.ID,
+				"name":           alias.Name,
+				"mount_accessor": alias.MountAccessor,
+				"mount_type":     alias.MountType,
+				"metadata":       alias.Metadata,
+			})
+		}
+		input["entity"] = map[string]interface{}{
+			"id":       entity.ID,
+			"name":     entity.Name,
+			"metadata": entity.Metadata,
+			"policies": entity.Policies,
+			"aliases":  aliases,
+		}
+	}
+
+	return input
+}
@@ -0,0 +1,211 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const testRegoPolicy = `
+package vault
+
+import future.keywords
+
+# Everything the ACL policies allow, except writes to public secrets
+default allow := true
+
+allow := false if {
+	startswith(input.path, "secret/public/")
+	input.operation != "read"
+}
+
+deny contains msg if {
+	input.operation in {"create", "update"}
+	not input.data.owner
+	msg := "secrets must have an owner"
+}
+`
+
+func TestPolicyStore_Rego(t *testing.T) {
+	_, ps := mockPolicyWithCore(t, false)
+	ctx := namespace.RootContext(nil)
+
+	module, err := parseRegoPolicy(testRegoPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := &Policy{
+		Name:       "Rules",
+		Raw:        testRegoPolicy,
+		Type:       PolicyTypeRego,
+		namespace:  namespace.RootNamespace,
+		regoModule: module,
+	}
+	if err := ps.SetPolicy(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+
+	// Token policies are resolved to the Rego policy
+	p, err := ps.GetPolicy(ctx, "rules", PolicyTypeToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil || p.Type != PolicyTypeRego || p.regoModule == nil {
+		t.Fatalf("bad: %#v", p)
+	}
+
+	keys, err := ps.ListPolicies(ctx, PolicyTypeRego)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "rules" {
+		t.Fatalf("bad: %v", keys)
+	}
+
+	// Names are shared with ACL policies
+	acl, _ := ParseACLPolicy(namespace.RootNamespace, aclPolicy)
+	acl.Name = "rules"
+	if err := ps.SetPolicy(ctx, acl); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if err := ps.DeletePolicy(ctx, "rules", PolicyTypeRego); err != nil {
+		t.Fatal(err)
+	}
+	p, err = ps.GetPolicy(ctx, "rules", PolicyTypeToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != nil {
+		t.Fatalf("bad: %#v", p)
+	}
+}
+
+func TestCore_HandleRequest_RegoPolicy(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	for path, policy := range map[string]string{
+		"sys/policies/acl/writer": `
+path "secret/*" { capabilities = ["create", "update", "read"] }
+path "secret/denied/*" { capabilities = ["deny"] }
+`,
+		"sys/policies/rego/rules": testRegoPolicy,
+	} {
+		resp, err := c.HandleRequest(ctx, &logical.Request{
+			Operation:   logical.UpdateOperation,
+			Path:        path,
+			Data:        map[string]interface{}{"policy": policy},
+			ClientToken: root,
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v, resp: %v", err, resp)
+		}
+	}
+
+	// Invalid modules, and modules calling unsafe built-in functions, are
+	// rejected
+	for _, policy := range []string{
+		"package vault\nfoo := true",
+		"package vault\nallow := http.send({\"method\": \"get\", \"url\": \"http://127.0.0.1\"}).status_code == 200",
+	} {
+		resp, err := c.HandleRequest(ctx, &logical.Request{
+			Operation:   logical.UpdateOperation,
+			Path:        "sys/policies/rego/invalid",
+			Data:        map[string]interface{}{"policy": policy},
+			ClientToken: root,
+		})
+		if err == nil && !resp.IsError() {
+			t.Fatalf("expected error, resp: %v", resp)
+		}
+	}
+
+	testMakeServiceTokenViaCore(t, c, root, "writer", "", []string{"writer", "rules"})
+	testMakeServiceTokenViaCore(t, c, root, "reader", "", []string{"rules"})
+
+	cases := []struct {
+		name   string
+		token  string
+		op     logical.Operation
+		path   string
+		data   map[string]interface{}
+		denied string
+	}{
+		{
+			name:  "allowed by acl and rego",
+			token: "writer",
+			op:    logical.UpdateOperation,
+			path:  "secret/foo",
+			data:  map[string]interface{}{"owner": "payments"},
+		},
+		{
+			name:   "denied by rego deny rule",
+			token:  "writer",
+			op:     logical.UpdateOperation,
+			path:   "secret/foo",
+			data:   map[string]interface{}{"foo": "bar"},
+			denied: "secrets must have an owner",
+		},
+		{
+			name:   "not allowed by rego allow rule",
+			token:  "writer",
+			op:     logical.UpdateOperation,
+			path:   "secret/public/foo",
+			data:   map[string]interface{}{"owner": "payments"},
+			denied: `not allowed by Rego policy "rules"`,
+		},
+		{
+			name:  "read allowed by acl and rego",
+			token: "writer",
+			op:    logical.ReadOperation,
+			path:  "secret/public/foo",
+		},
+		{
+			// The Rego policy allows the request, but the ACL policies
+			// explicitly deny it
+			name:   "denied by acl",
+			token:  "writer",
+			op:     logical.ReadOperation,
+			path:   "secret/denied/foo",
+			denied: logical.ErrPermissionDenied.Error(),
+		},
+		{
+			// The Rego policy allows the request, but no ACL policy
+			// grants it
+			name:   "not granted by acl",
+			token:  "reader",
+			op:     logical.ReadOperation,
+			path:   "secret/public/foo",
+			denied: logical.ErrPermissionDenied.Error(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := c.HandleRequest(ctx, &logical.Request{
+				Operation:   tc.op,
+				Path:        tc.path,
+				Data:        tc.data,
+				ClientToken: tc.token,
+			})
+			if tc.denied == "" {
+				if err != nil {
+					t.Fatalf("err: %v, resp: %v", err, resp)
+				}
+				return
+			}
+			if err == nil || !errwrap.Contains(err, logical.ErrPermissionDenied.Error()) {
+				t.Fatalf("expected permission denied, err: %v, resp: %v", err, resp)
+			}
+			if !strings.Contains(err.Error(), tc.denied) {
+				t.Fatalf("expected %q in error: %v", tc.denied, err)
+			}
+		})
+	}
+}
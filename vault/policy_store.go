@@ -29,6 +29,9 @@ const (
 	policyRGPSubPath = "policy-rgp/"
 	policyEGPSubPath = "policy-egp/"
 
+	// policyRegoSubPath is the sub-path used for Rego policies
+	policyRegoSubPath = "policy-rego/"
+
 	// policyCacheSize is the number of policies that are kept cached
 	policyCacheSize = 1024
 
@@ -140,6 +143,14 @@ path "sys/wrapping/unwrap" {
     capabilities = ["update"]
 }
 
+# Allow a token to list and unwrap the responses delivered to its entity
+path "sys/wrapping/inbox" {
+    capabilities = ["list"]
+}
+path "sys/wrapping/inbox/*" {
+    capabilities = ["update"]
+}
+
 # Allow general purpose tools
 path "sys/tools/hash" {
     capabilities = ["update"]
@@ -183,6 +194,8 @@ type PolicyStore struct {
 	rgpView *BarrierView
 	egpView *BarrierView
 
+	regoView *BarrierView
+
 	tokenPoliciesLRU *lru.TwoQueueCache
 	egpLRU           *lru.TwoQueueCache
 
@@ -191,7 +204,7 @@ type PolicyStore struct {
 	// long as there aren't concurrent writes.
 	modifyLock *sync.RWMutex
 
-	// Stores whether a token policy is ACL or RGP
+	// Stores whether a token policy is ACL, RGP or Rego
 	policyTypeMap sync.Map
 
 	// logger is the server logger copied over from core
@@ -215,6 +228,7 @@ func NewPolicyStore(ctx context.Context, core *Core, baseView *BarrierView, syst
 		aclView:    baseView.SubView(policyACLSubPath),
 		rgpView:    baseView.SubView(policyRGPSubPath),
 		egpView:    baseView.SubView(policyEGPSubPath),
+		regoView:   baseView.SubView(policyRegoSubPath),
 		modifyLock: new(sync.RWMutex),
 		logger:     logger,
 		core:       core,
@@ -240,6 +254,17 @@ func NewPolicyStore(ctx context.Context, core *Core, baseView *BarrierView, syst
 		ps.policyTypeMap.Store(index, PolicyTypeACL)
 	}
 
+	regoView := ps.getRegoView(namespace.RootNamespace)
+	keys, err = logical.CollectKeys(namespace.RootContext(ctx), regoView)
+	if err != nil {
+		ps.logger.Error("error collecting Rego policy keys", "error", err)
+		return nil, err
+	}
+	for _, key := range keys {
+		index := ps.cacheKey(namespace.RootNamespace, ps.sanitizeName(key))
+		ps.policyTypeMap.Store(index, PolicyTypeRego)
+	}
+
 	if err := ps.loadNamespacePolicies(ctx, core); err != nil {
 		return nil, err
 	}
@@ -312,7 +337,7 @@ func (ps *PolicyStore) invalidate(ctx context.Context, name string, policyType P
 	// We don't lock before removing from the LRU here because the worst that
 	// can happen is we load again if something since added it
 	switch policyType {
-	case PolicyTypeACL, PolicyTypeRGP:
+	case PolicyTypeACL, PolicyTypeRGP, PolicyTypeRego:
 		if ps.tokenPoliciesLRU != nil {
 			ps.tokenPoliciesLRU.Remove(index)
 		}
@@ -401,6 +426,14 @@ func (ps *PolicyStore) setPolicyInternal(ctx context.Context, p *Policy) error {
 		if rgp != nil {
 			return fmt.Errorf("cannot reuse policy names between ACLs and RGPs")
 		}
+		regoView := ps.getRegoView(p.namespace)
+		regoPolicy, err := regoView.Get(ctx, entry.Key)
+		if err != nil {
+			return fmt.Errorf("failed looking up conflicting policy: %w", err)
+		}
+		if regoPolicy != nil {
+			return fmt.Errorf("cannot reuse policy names between ACLs and Rego policies")
+		}
 
 		if err := view.Put(ctx, entry); err != nil {
 			return fmt.Errorf("failed to persist policy: %w", err)
@@ -445,6 +478,35 @@ func (ps *PolicyStore) setPolicyInternal(ctx context.Context, p *Policy) error {
 			ps.egpLRU.Add(index, p)
 		}
 
+	case PolicyTypeRego:
+		if p.regoModule == nil {
+			module, err := parseRegoPolicy(p.Raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse policy: %w", err)
+			}
+			p.regoModule = module
+		}
+
+		for _, other := range []*BarrierView{ps.getACLView(p.namespace), ps.getRGPView(p.namespace)} {
+			conflict, err := other.Get(ctx, entry.Key)
+			if err != nil {
+				return fmt.Errorf("failed looking up conflicting policy: %w", err)
+			}
+			if conflict != nil {
+				return fmt.Errorf("cannot reuse policy names between Rego policies and ACLs or RGPs")
+			}
+		}
+
+		if err := view.Put(ctx, entry); err != nil {
+			return fmt.Errorf("failed to persist policy: %w", err)
+		}
+
+		ps.policyTypeMap.Store(index, PolicyTypeRego)
+
+		if ps.tokenPoliciesLRU != nil {
+			ps.tokenPoliciesLRU.Add(index, p)
+		}
+
 	default:
 		return fmt.Errorf("unknown policy type, cannot set")
 	}
@@ -481,6 +543,9 @@ func (ps *PolicyStore) switchedGetPolicy(ctx context.Context, name string, polic
 	case PolicyTypeEGP:
 		cache = ps.egpLRU
 		view = ps.getEGPView(ns)
+	case PolicyTypeRego:
+		cache = ps.tokenPoliciesLRU
+		view = ps.getRegoView(ns)
 	case PolicyTypeToken:
 		cache = ps.tokenPoliciesLRU
 		val, ok := ps.policyTypeMap.Load(index)
@@ -494,6 +559,8 @@ func (ps *PolicyStore) switchedGetPolicy(ctx context.Context, name string, polic
 			view = ps.getACLView(ns)
 		case PolicyTypeRGP:
 			view = ps.getRGPView(ns)
+		case PolicyTypeRego:
+			view = ps.getRegoView(ns)
 		default:
 			return nil, fmt.Errorf("invalid type of policy in type map: %q", policyType)
 		}
@@ -585,6 +652,15 @@ func (ps *PolicyStore) switchedGetPolicy(ctx context.Context, name string, polic
 			return nil, err
 		}
 
+	case PolicyTypeRego:
+		module, err := parseRegoPolicy(policyEntry.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy: %w", err)
+		}
+		policy.regoModule = module
+
+		ps.policyTypeMap.Store(index, PolicyTypeRego)
+
 	default:
 		return nil, fmt.Errorf("unknown policy type %q", policyEntry.Type.String())
 	}
@@ -625,6 +701,8 @@ func (ps *PolicyStore) ListPolicies(ctx context.Context, policyType PolicyType)
 		return logical.CollectKeys(ctx, view)
 	case PolicyTypeEGP:
 		return logical.CollectKeys(ctx, view)
+	case PolicyTypeRego:
+		return logical.CollectKeys(ctx, view)
 	default:
 		return nil, fmt.Errorf("unknown policy type %q", policyType)
 	}
@@ -743,6 +821,21 @@ func (ps *PolicyStore) switchedDeletePolicy(ctx context.Context, name string, po
 		defer ps.core.invalidateSentinelPolicy(policyType, index)
 
 		ps.invalidateEGPTreePath(index)
+
+	case PolicyTypeRego:
+		if physicalDeletion {
+			err := view.Delete(ctx, name)
+			if err != nil {
+				return fmt.Errorf("failed to delete policy: %w", err)
+			}
+		}
+
+		if ps.tokenPoliciesLRU != nil {
+			// Clear the cache
+			ps.tokenPoliciesLRU.Remove(index)
+		}
+
+		ps.policyTypeMap.Delete(index)
 	}
 
 	return nil
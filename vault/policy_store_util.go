@@ -17,10 +17,35 @@ type entPolicyStore struct{}
 func (ps *PolicyStore) extraInit() {
 }
 
-func (ps *PolicyStore) loadNamespacePolicies(context.Context, *Core) error { return nil }
+func (ps *PolicyStore) loadNamespacePolicies(ctx context.Context, c *Core) error {
+	for _, ns := range c.collectNamespaces() {
+		if ns.ID == namespace.RootNamespaceID {
+			continue
+		}
+
+		keys, err := logical.CollectKeys(namespace.ContextWithNamespace(ctx, ns), ps.getACLView(ns))
+		if err != nil {
+			ps.logger.Error("error collecting acl policy keys", "namespace", ns.Path, "error", err)
+			return err
+		}
+		for _, key := range keys {
+			ps.policyTypeMap.Store(ps.cacheKey(ns, ps.sanitizeName(key)), PolicyTypeACL)
+		}
+
+		keys, err = logical.CollectKeys(namespace.ContextWithNamespace(ctx, ns), ps.getRegoView(ns))
+		if err != nil {
+			ps.logger.Error("error collecting Rego policy keys", "namespace", ns.Path, "error", err)
+			return err
+		}
+		for _, key := range keys {
+			ps.policyTypeMap.Store(ps.cacheKey(ns, ps.sanitizeName(key)), PolicyTypeRego)
+		}
+	}
+	return nil
+}
 
-func (ps *PolicyStore) getACLView(*namespace.Namespace) *BarrierView {
-	return ps.aclView
+func (ps *PolicyStore) getACLView(ns *namespace.Namespace) *BarrierView {
+	return ps.core.namespacedView(ns, ps.aclView)
 }
 
 func (ps *PolicyStore) getRGPView(ns *namespace.Namespace) *BarrierView {
@@ -31,7 +56,10 @@ func (ps *PolicyStore) getEGPView(ns *namespace.Namespace) *BarrierView {
 	return ps.egpView
 }
 
-func (ps *PolicyStore) getBarrierView(ns *namespace.Namespace, _ PolicyType) *BarrierView {
+func (ps *PolicyStore) getBarrierView(ns *namespace.Namespace, policyType PolicyType) *BarrierView {
+	if policyType == PolicyTypeRego {
+		return ps.getRegoView(ns)
+	}
 	return ps.getACLView(ns)
 }
 
@@ -46,5 +74,10 @@ func (ps *PolicyStore) invalidateEGPTreePath(string) {}
 func (ps *PolicyStore) pathsToEGPPaths(*Policy) ([]*egpPath, error) { return nil, nil }
 
 func (ps *PolicyStore) loadACLPolicyNamespaces(ctx context.Context, policyName, policyText string) error {
-	return ps.loadACLPolicyInternal(namespace.RootContext(ctx), policyName, policyText)
+	for _, ns := range ps.core.collectNamespaces() {
+		if err := ps.loadACLPolicyInternal(namespace.ContextWithNamespace(ctx, ns), policyName, policyText); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -72,6 +72,10 @@ const (
 
 type leaseWalkFunc func(context.Context, func(request *Request) bool) error
 
+// EntityResolveFunc returns the identifier of the entity of the given client
+// token, or an empty string if the token has no entity.
+type EntityResolveFunc func(ctx context.Context, clientToken string) string
+
 // String converts each quota type into its string equivalent value
 func (q Type) String() string {
 	switch q {
@@ -169,6 +173,10 @@ type Manager struct {
 	logger     log.Logger
 	metricSink *metricsutil.ClusterMetricSink
 
+	// entityResolver resolves the entity of requests to rate limit quotas
+	// grouping clients by entity.
+	entityResolver EntityResolveFunc
+
 	// quotaLock is a lock for manipulating quotas and anything not covered by a more specific lock
 	quotaLock *locking.DeadlockRWMutex
 
@@ -268,6 +276,19 @@ type Request struct {
 	// ClientAddress is client unique addressable string (e.g. IP address). It can
 	// be empty if the quota type does not need it.
 	ClientAddress string
+
+	// Method is the HTTP method of the request, with LIST for list requests. It
+	// can be empty if the quota type does not need it.
+	Method string
+
+	// ClientToken is the token of the request, used to resolve its entity if
+	// the entity ID isn't known yet. It can be empty if the quota type does not
+	// need it.
+	ClientToken string
+
+	// EntityID is the identifier of the entity making the request. If empty,
+	// it is resolved from the client token when a quota needs it.
+	EntityID string
 }
 
 // NewManager creates and initializes a new quota manager to hold all the quota
@@ -294,6 +315,15 @@ func NewManager(logger log.Logger, walkFunc leaseWalkFunc, ms *metricsutil.Clust
 	return manager, nil
 }
 
+// SetEntityResolver sets the function used to resolve the entity of requests
+// to rate limit quotas grouping clients by entity. Without it, requests are
+// treated as having no entity unless their entity ID is set.
+func (m *Manager) SetEntityResolver(f EntityResolveFunc) {
+	m.quotaConfigLock.Lock()
+	defer m.quotaConfigLock.Unlock()
+	m.entityResolver = f
+}
+
 // SetQuota adds or updates a quota rule.
 func (m *Manager) SetQuota(ctx context.Context, qType string, quota Quota, loading bool) error {
 	m.quotaLock.Lock()
@@ -431,8 +461,10 @@ func (m *Manager) quotaByNameLocked(qType string, name string) (Quota, error) {
 	return quotaRaw.(Quota), nil
 }
 
-// QuotaByFactors returns the quota rule that matches the provided factors
-func (m *Manager) QuotaByFactors(ctx context.Context, qType, nsPath, mountPath, pathSuffix, role string) (Quota, error) {
+// QuotaByFactors returns the quota rule that matches the provided factors.
+// Rate limit quotas restricted to methods other than the provided ones don't
+// match; no methods matches every quota.
+func (m *Manager) QuotaByFactors(ctx context.Context, qType, nsPath, mountPath, pathSuffix, role string, methods []string) (Quota, error) {
 	m.dbAndCacheLock.RLock()
 	defer m.dbAndCacheLock.RUnlock()
 
@@ -464,6 +496,9 @@ func (m *Manager) QuotaByFactors(ctx context.Context, qType, nsPath, mountPath,
 	}
 	var quotas []Quota
 	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if rlq, ok := raw.(*RateLimitQuota); ok && !methodsOverlap(rlq.Methods, methods) {
+			continue
+		}
 		quotas = append(quotas, raw.(Quota))
 	}
 	if len(quotas) > 1 {
@@ -494,6 +529,9 @@ func (m *Manager) QueryQuota(req *Request) (Quota, error) {
 // - mount specific quota takes precedence over namespace specific quota
 // - path suffix specific quota takes precedence over mount specific quota
 // - role based quota takes precedence over path suffix/mount specific quota
+//
+// Rate limit quotas restricted to methods other than the method of the request
+// are skipped, letting less specific quota rules apply.
 func (m *Manager) queryQuota(txn *memdb.Txn, req *Request) (Quota, error) {
 	if txn == nil {
 		txn = m.db.Txn(false)
@@ -515,6 +553,9 @@ func (m *Manager) queryQuota(txn *memdb.Txn, req *Request) (Quota, error) {
 		}
 		var quotas []Quota
 		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			if rlq, ok := raw.(*RateLimitQuota); ok && !rlq.appliesToMethod(req.Method) {
+				continue
+			}
 			quota := raw.(Quota)
 			quotas = append(quotas, quota)
 		}
@@ -663,6 +704,18 @@ func (m *Manager) ApplyQuota(ctx context.Context, req *Request) (Response, error
 		return resp, nil
 	}
 
+	// Resolve the entity of the request only when the quota needs it, as it
+	// requires a token lookup.
+	if rlq, ok := quota.(*RateLimitQuota); ok && rlq.groupsByEntity() && req.EntityID == "" && req.ClientToken != "" {
+		m.quotaConfigLock.RLock()
+		resolver := m.entityResolver
+		m.quotaConfigLock.RUnlock()
+
+		if resolver != nil {
+			req.EntityID = resolver(ctx, req.ClientToken)
+		}
+	}
+
 	return quota.allow(ctx, req)
 }
 
@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !enterprise
+
+package quotas
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/hashicorp/vault/sdk/helper/cryptoutil"
+)
+
+// Ensure that LeaseCountQuota implements the Quota interface
+var _ Quota = (*LeaseCountQuota)(nil)
+
+// LeaseCountQuota represents the quota rule properties that is used to limit the
+// number of leases created for a namespace, mount, path or login role.
+type LeaseCountQuota struct {
+	// ID is the identifier of the quota
+	ID string `json:"id"`
+
+	// Type of quota this represents
+	Type Type `json:"type"`
+
+	// Name of the quota rule
+	Name string `json:"name"`
+
+	// NamespacePath is the path of the namespace to which this quota is
+	// applicable.
+	NamespacePath string `json:"namespace_path"`
+
+	// MountPath is the path of the mount to which this quota is applicable
+	MountPath string `json:"mount_path"`
+
+	// Role is the role on an auth mount to apply the quota to upon /login requests
+	// Not applicable for use with path suffixes
+	Role string `json:"role"`
+
+	// PathSuffix is the path suffix to which this quota is applicable
+	PathSuffix string `json:"path_suffix"`
+
+	// MaxLeases is the maximum number of leases allowed at once by the quota
+	MaxLeases int `json:"max_leases"`
+
+	lock       *sync.Mutex
+	counter    int
+	pending    int
+	logger     log.Logger
+	metricSink *metricsutil.ClusterMetricSink
+}
+
+// NewLeaseCountQuota creates a quota checker for imposing limits on the number
+// of leases existing at once.
+func NewLeaseCountQuota(name, nsPath, mountPath, pathSuffix, role string, maxLeases int) *LeaseCountQuota {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		// Fall back to generating with a hash of the name, later in initialize
+		id = ""
+	}
+	return &LeaseCountQuota{
+		Name:          name,
+		ID:            id,
+		Type:          TypeLeaseCount,
+		NamespacePath: nsPath,
+		MountPath:     mountPath,
+		Role:          role,
+		PathSuffix:    pathSuffix,
+		MaxLeases:     maxLeases,
+	}
+}
+
+func (l *LeaseCountQuota) Clone() Quota {
+	return &LeaseCountQuota{
+		ID:            l.ID,
+		Name:          l.Name,
+		MountPath:     l.MountPath,
+		Role:          l.Role,
+		Type:          l.Type,
+		NamespacePath: l.NamespacePath,
+		PathSuffix:    l.PathSuffix,
+		MaxLeases:     l.MaxLeases,
+	}
+}
+
+// initialize ensures the namespace and max leases are initialized and sets the
+// ID if it's currently empty. The counter starts at zero, it is set when the
+// lease counts are recomputed.
+func (l *LeaseCountQuota) initialize(logger log.Logger, ms *metricsutil.ClusterMetricSink) error {
+	if l.lock == nil {
+		l.lock = new(sync.Mutex)
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	// Memdb requires a non-empty value for indexing
+	if l.NamespacePath == "" {
+		l.NamespacePath = "root"
+	}
+
+	if l.MaxLeases <= 0 {
+		return fmt.Errorf("invalid max leases: %d", l.MaxLeases)
+	}
+
+	if logger != nil {
+		l.logger = logger
+	}
+
+	if l.metricSink == nil {
+		l.metricSink = ms
+	}
+
+	if l.ID == "" {
+		// Performance standby nodes could call initialize() on their copy of
+		// the quota; for consistency we need to generate an ID that is
+		// deterministic.
+		l.ID = hex.EncodeToString(cryptoutil.Blake2b256Hash(l.Name))
+	}
+
+	return nil
+}
+
+func (l *LeaseCountQuota) quotaID() string {
+	return l.ID
+}
+
+// QuotaName returns the name of the quota rule
+func (l *LeaseCountQuota) QuotaName() string {
+	return l.Name
+}
+
+// allow reserves a lease for the request if the number of existing leases, and
+// of requests which may create one, is under the maximum. The reservation is
+// released once the request is acknowledged.
+func (l *LeaseCountQuota) allow(_ context.Context, _ *Request) (Response, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.counter+l.pending >= l.MaxLeases {
+		if l.metricSink != nil {
+			l.metricSink.IncrCounterWithLabels([]string{"quota", "lease_count", "violation"}, 1, []metrics.Label{{Name: "name", Value: l.Name}})
+		}
+		return Response{Allowed: false}, nil
+	}
+
+	l.pending++
+	return Response{
+		Allowed: true,
+		Access:  &access{quotaID: l.ID},
+	}, nil
+}
+
+// ack releases the lease reserved by allow. Leases which were created by the
+// request are counted once the expiration manager registers them.
+func (l *LeaseCountQuota) ack() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.pending > 0 {
+		l.pending--
+	}
+}
+
+func (l *LeaseCountQuota) inc() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.counter++
+	l.emitCountLocked()
+}
+
+func (l *LeaseCountQuota) dec() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.counter > 0 {
+		l.counter--
+	}
+	l.emitCountLocked()
+}
+
+func (l *LeaseCountQuota) setCount(count int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.counter = count
+	l.emitCountLocked()
+}
+
+// Count returns the number of leases counted by the quota.
+func (l *LeaseCountQuota) Count() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.counter
+}
+
+func (l *LeaseCountQuota) emitCountLocked() {
+	if l.metricSink == nil {
+		return
+	}
+	l.metricSink.SetGaugeWithLabels([]string{"quota", "lease_count", "counter"}, float32(l.counter), []metrics.Label{{Name: "name", Value: l.Name}})
+}
+
+func (l *LeaseCountQuota) close(_ context.Context) error {
+	return nil
+}
+
+func (l *LeaseCountQuota) handleRemount(mountPath, nsPath string) {
+	l.MountPath = mountPath
+	l.NamespacePath = nsPath
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !enterprise
+
+package quotas
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/hashicorp/vault/sdk/helper/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaseCountQuota_Allow(t *testing.T) {
+	ctx := context.Background()
+
+	// Two leases already exist on the mount
+	existing := []*Request{
+		{Path: "database/creds/app", MountPath: "database/"},
+		{Path: "database/creds/app", MountPath: "database/"},
+	}
+	walkFunc := func(_ context.Context, cb func(*Request) bool) error {
+		for _, req := range existing {
+			r := *req
+			if !cb(&r) {
+				break
+			}
+		}
+		return nil
+	}
+
+	qm, err := NewManager(logging.NewVaultLogger(log.Trace), walkFunc, metricsutil.BlackholeSink())
+	require.NoError(t, err)
+
+	quota := NewLeaseCountQuota("lcq", "", "database/", "", "", 3)
+	require.NoError(t, qm.SetQuota(ctx, TypeLeaseCount.String(), quota, false))
+	require.Equal(t, 2, quota.Count())
+
+	req := func() *Request {
+		return &Request{Type: TypeLeaseCount, Path: "database/creds/app", MountPath: "database/"}
+	}
+
+	// One more lease may be created
+	resp, err := qm.ApplyQuota(ctx, req())
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+	require.NotNil(t, resp.Access)
+
+	// The reservation of the pending request is counted
+	blocked, err := qm.ApplyQuota(ctx, req())
+	require.NoError(t, err)
+	require.False(t, blocked.Allowed)
+
+	// The request created a lease
+	require.NoError(t, qm.HandleLeases(LeaseActionCreated, []*Request{req()}))
+	require.NoError(t, qm.AckLease(resp.Access))
+	require.Equal(t, 3, quota.Count())
+
+	blocked, err = qm.ApplyQuota(ctx, req())
+	require.NoError(t, err)
+	require.False(t, blocked.Allowed)
+
+	// Paths which never created leases aren't limited
+	read, err := qm.ApplyQuota(ctx, &Request{Type: TypeLeaseCount, Path: "database/config/db", MountPath: "database/"})
+	require.NoError(t, err)
+	require.True(t, read.Allowed)
+
+	// Revoking a lease makes room for a new one
+	require.NoError(t, qm.HandleLeases(LeaseActionDeleted, []*Request{req()}))
+	resp, err = qm.ApplyQuota(ctx, req())
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+	require.NoError(t, qm.AckLease(resp.Access))
+	require.Equal(t, 2, quota.Count())
+}
+
+func TestLeaseCountQuota_Invalid(t *testing.T) {
+	qm, err := NewManager(logging.NewVaultLogger(log.Trace), nil, metricsutil.BlackholeSink())
+	require.NoError(t, err)
+
+	quota := NewLeaseCountQuota("lcq", "", "", "", "", 0)
+	require.Error(t, qm.SetQuota(context.Background(), TypeLeaseCount.String(), quota, false))
+}
@@ -8,7 +8,9 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/helper/metricsutil"
 	"github.com/hashicorp/vault/sdk/helper/cryptoutil"
+	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/sethvargo/go-limiter"
 	"github.com/sethvargo/go-limiter/httplimit"
 	"github.com/sethvargo/go-limiter/memorystore"
@@ -35,6 +38,33 @@ const (
 	EnvVaultEnableRateLimitAuditLogging = "VAULT_ENABLE_RATE_LIMIT_AUDIT_LOGGING"
 )
 
+const (
+	// GroupByIP applies the rate limit to each client IP address.
+	GroupByIP = "ip"
+
+	// GroupByNone applies the rate limit to all the requests the quota applies
+	// to together.
+	GroupByNone = "none"
+
+	// GroupByEntityThenIP applies the rate limit to each entity, falling back
+	// to the client IP address of requests without an entity.
+	GroupByEntityThenIP = "entity_then_ip"
+
+	// GroupByEntityThenNone applies the rate limit to each entity, falling back
+	// to a single limit shared by all the requests without an entity.
+	GroupByEntityThenNone = "entity_then_none"
+)
+
+// ValidRateLimitGroupBy returns whether the value is a supported way of
+// grouping the requests of a rate limit quota into clients.
+func ValidRateLimitGroupBy(groupBy string) bool {
+	switch groupBy {
+	case GroupByIP, GroupByNone, GroupByEntityThenIP, GroupByEntityThenNone:
+		return true
+	}
+	return false
+}
+
 // Ensure that RateLimitQuota implements the Quota interface
 var _ Quota = (*RateLimitQuota)(nil)
 
@@ -75,6 +105,19 @@ type RateLimitQuota struct {
 	// reaches the rate limit.
 	BlockInterval time.Duration `json:"block_interval"`
 
+	// GroupBy defines how requests are grouped into clients, each client
+	// having its own rate limiter. An empty value is the same as GroupByIP.
+	GroupBy string `json:"group_by,omitempty"`
+
+	// Burst defines the number of requests a client may make at once. Clients
+	// regain the ability to make requests at Rate per Interval, up to Burst.
+	// If zero, a client may make Rate requests in each Interval.
+	Burst int `json:"burst,omitempty"`
+
+	// Methods restricts the quota to requests with the given methods. If
+	// empty, the quota applies to requests with any method.
+	Methods []string `json:"methods,omitempty"`
+
 	lock                *sync.RWMutex
 	store               limiter.Store
 	logger              log.Logger
@@ -125,6 +168,9 @@ func (q *RateLimitQuota) Clone() Quota {
 		BlockInterval: q.BlockInterval,
 		Rate:          q.Rate,
 		Interval:      q.Interval,
+		GroupBy:       q.GroupBy,
+		Burst:         q.Burst,
+		Methods:       q.Methods,
 	}
 	return rlq
 }
@@ -158,6 +204,24 @@ func (rlq *RateLimitQuota) initialize(logger log.Logger, ms *metricsutil.Cluster
 		return fmt.Errorf("invalid block interval: %v", rlq.BlockInterval)
 	}
 
+	if rlq.GroupBy == "" {
+		rlq.GroupBy = GroupByIP
+	}
+
+	if !ValidRateLimitGroupBy(rlq.GroupBy) {
+		return fmt.Errorf("invalid group by: %v", rlq.GroupBy)
+	}
+
+	if rlq.Burst < 0 {
+		return fmt.Errorf("invalid burst: %v", rlq.Burst)
+	}
+
+	methods, err := NormalizeMethods(rlq.Methods)
+	if err != nil {
+		return err
+	}
+	rlq.Methods = methods
+
 	if logger != nil {
 		rlq.logger = logger
 	}
@@ -187,14 +251,19 @@ func (rlq *RateLimitQuota) initialize(logger log.Logger, ms *metricsutil.Cluster
 		rlq.staleAge = DefaultRateLimitStaleAge
 	}
 
-	rlStore, err := memorystore.New(&memorystore.Config{
-		Tokens:        uint64(math.Round(rlq.Rate)), // allow 'rlq.Rate' number of requests per 'Interval'
-		Interval:      rlq.Interval,                 // time interval in which to enforce rate limiting
-		SweepInterval: rlq.purgeInterval,            // how often stale clients are removed
-		SweepMinTTL:   rlq.staleAge,                 // how long since the last request a client is considered stale
-	})
-	if err != nil {
-		return err
+	var rlStore limiter.Store
+	if rlq.Burst > 0 {
+		rlStore = newBurstStore(rlq.Rate, rlq.Interval, uint64(rlq.Burst), rlq.purgeInterval, rlq.staleAge)
+	} else {
+		rlStore, err = memorystore.New(&memorystore.Config{
+			Tokens:        uint64(math.Round(rlq.Rate)), // allow 'rlq.Rate' number of requests per 'Interval'
+			Interval:      rlq.Interval,                 // time interval in which to enforce rate limiting
+			SweepInterval: rlq.purgeInterval,            // how often stale clients are removed
+			SweepMinTTL:   rlq.staleAge,                 // how long since the last request a client is considered stale
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	rlq.store = rlStore
@@ -275,17 +344,57 @@ func (rlq *RateLimitQuota) QuotaName() string {
 	return rlq.Name
 }
 
+// groupsByEntity returns whether the clients of the quota are identified by
+// the entity of the request.
+func (rlq *RateLimitQuota) groupsByEntity() bool {
+	return rlq.GroupBy == GroupByEntityThenIP || rlq.GroupBy == GroupByEntityThenNone
+}
+
+// appliesToMethod returns whether the quota applies to requests with the
+// given method.
+func (rlq *RateLimitQuota) appliesToMethod(method string) bool {
+	if len(rlq.Methods) == 0 {
+		return true
+	}
+	return methodsOverlap(rlq.Methods, []string{normalizeMethod(method)})
+}
+
+// clientKey returns the key of the rate limiter of the client making the
+// request, according to how the quota groups requests.
+func (rlq *RateLimitQuota) clientKey(req *Request) (string, error) {
+	switch rlq.GroupBy {
+	case GroupByNone:
+		return GroupByNone, nil
+	case GroupByEntityThenNone:
+		if req.EntityID != "" {
+			return "entity:" + req.EntityID, nil
+		}
+		return GroupByNone, nil
+	case GroupByEntityThenIP:
+		if req.EntityID != "" {
+			return "entity:" + req.EntityID, nil
+		}
+	}
+
+	if req.ClientAddress == "" {
+		return "", fmt.Errorf("missing request client address in quota request")
+	}
+	return req.ClientAddress, nil
+}
+
 // allow decides if the request is allowed by the quota. An error will be
-// returned if the request ID or address is empty. If the path is exempt, the
-// quota will not be evaluated. Otherwise, the client rate limiter is retrieved
-// by address and the rate limit quota is checked against that limiter.
+// returned if the client of the request can't be identified. Otherwise, the
+// client rate limiter is retrieved by the key of the client, as defined by
+// the grouping of the quota, and the rate limit quota is checked against that
+// limiter.
 func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, error) {
 	resp := Response{
 		Headers: make(map[string]string),
 	}
 
-	if req.ClientAddress == "" {
-		return resp, fmt.Errorf("missing request client address in quota request")
+	key, err := rlq.clientKey(req)
+	if err != nil {
+		return resp, err
 	}
 
 	var retryAfter string
@@ -302,20 +411,20 @@ func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, e
 	// of purging blocked clients may not yield a false negative. In other words,
 	// a client may no longer be considered blocked whereas the purging interval
 	// has yet to run.
-	if v, ok := rlq.blockedClients.Load(req.ClientAddress); ok {
+	if v, ok := rlq.blockedClients.Load(key); ok {
 		blockedAt := v.(time.Time)
 		if time.Since(blockedAt) >= rlq.BlockInterval {
 			// allow the request and remove the blocked client
-			rlq.blockedClients.Delete(req.ClientAddress)
+			rlq.blockedClients.Delete(key)
 		} else {
 			// deny the request and return early
 			resp.Allowed = false
-			retryAfter = strconv.Itoa(int(time.Until(blockedAt.Add(rlq.BlockInterval)).Seconds()))
+			retryAfter = retryAfterSeconds(blockedAt.Add(rlq.BlockInterval))
 			return resp, nil
 		}
 	}
 
-	limit, remaining, reset, allow, err := rlq.store.Take(ctx, req.ClientAddress)
+	limit, remaining, reset, allow, err := rlq.store.Take(ctx, key)
 	if err != nil {
 		return resp, err
 	}
@@ -324,19 +433,98 @@ func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, e
 	resp.Headers[httplimit.HeaderRateLimitLimit] = strconv.FormatUint(limit, 10)
 	resp.Headers[httplimit.HeaderRateLimitRemaining] = strconv.FormatUint(remaining, 10)
 	resp.Headers[httplimit.HeaderRateLimitReset] = strconv.Itoa(int(time.Until(time.Unix(0, int64(reset))).Seconds()))
-	retryAfter = resp.Headers[httplimit.HeaderRateLimitReset]
+	retryAfter = retryAfterSeconds(time.Unix(0, int64(reset)))
 
 	// If the request is not allowed (i.e. rate limit threshold reached) and blocking
 	// is enabled, we add the client to the set of blocked clients.
 	if !resp.Allowed && rlq.purgeBlocked {
 		blockedAt := time.Now()
-		retryAfter = strconv.Itoa(int(time.Until(blockedAt.Add(rlq.BlockInterval)).Seconds()))
-		rlq.blockedClients.Store(req.ClientAddress, blockedAt)
+		retryAfter = retryAfterSeconds(blockedAt.Add(rlq.BlockInterval))
+		rlq.blockedClients.Store(key, blockedAt)
 	}
 
 	return resp, nil
 }
 
+// retryAfterSeconds returns the value of the Retry-After header telling the
+// client to wait until the given time. The number of seconds is rounded up,
+// so that clients following the header don't retry too early.
+func retryAfterSeconds(t time.Time) string {
+	seconds := int(math.Ceil(time.Until(t).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.Itoa(seconds)
+}
+
+// NormalizeMethods validates the methods a rate limit quota applies to, and
+// returns them upper-cased, sorted and deduplicated. PUT is the same as POST,
+// as both map to the same operations.
+func NormalizeMethods(methods []string) ([]string, error) {
+	if len(methods) == 0 {
+		return nil, nil
+	}
+
+	set := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		normalized := normalizeMethod(method)
+		switch normalized {
+		case "GET", "LIST", "POST", "PATCH", "DELETE":
+		default:
+			return nil, fmt.Errorf("invalid method: %q", method)
+		}
+		set[normalized] = struct{}{}
+	}
+
+	ret := make([]string, 0, len(set))
+	for method := range set {
+		ret = append(ret, method)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// MethodForOperation returns the method of requests with the given operation,
+// for requests which don't come from the HTTP API.
+func MethodForOperation(op logical.Operation) string {
+	switch op {
+	case logical.ReadOperation:
+		return "GET"
+	case logical.ListOperation:
+		return "LIST"
+	case logical.PatchOperation:
+		return "PATCH"
+	case logical.DeleteOperation:
+		return "DELETE"
+	default:
+		return "POST"
+	}
+}
+
+func normalizeMethod(method string) string {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "PUT" {
+		return "POST"
+	}
+	return method
+}
+
+// methodsOverlap returns whether two method restrictions have a method in
+// common. An empty restriction matches every method.
+func methodsOverlap(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // close stops the current running client purge loop.
 // It should be called with the write lock held.
 func (rlq *RateLimitQuota) close(ctx context.Context) error {
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package quotas
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sethvargo/go-limiter"
+)
+
+// Ensure that burstStore implements the limiter.Store interface
+var _ limiter.Store = (*burstStore)(nil)
+
+// burstStore is a limiter.Store of token buckets holding up to a burst of
+// tokens, which are refilled continuously at the rate of the quota. Unlike
+// memorystore, whose buckets are refilled all at once at the start of each
+// interval, this lets clients make bursts of requests without raising their
+// sustained rate.
+type burstStore struct {
+	// fillRate is the number of tokens added to a bucket per nanosecond.
+	fillRate float64
+	burst    uint64
+	staleAge time.Duration
+
+	lock    sync.Mutex
+	buckets map[string]*burstBucket
+	stopped bool
+	stopCh  chan struct{}
+}
+
+type burstBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// newBurstStore creates a store of buckets holding up to burst tokens and
+// refilled with rate tokens per interval. Buckets left untouched for staleAge
+// are removed every purgeInterval.
+func newBurstStore(rate float64, interval time.Duration, burst uint64, purgeInterval, staleAge time.Duration) *burstStore {
+	s := &burstStore{
+		fillRate: rate / float64(interval),
+		burst:    burst,
+		staleAge: staleAge,
+		buckets:  make(map[string]*burstBucket),
+		stopCh:   make(chan struct{}),
+	}
+	go s.purge(purgeInterval)
+	return s
+}
+
+// refill updates the tokens of the bucket of the key up to now, creating a
+// full bucket if there is none. It must be called with the lock held.
+func (s *burstStore) refill(key string, now time.Time) *burstBucket {
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &burstBucket{tokens: float64(s.burst), lastSeen: now}
+		s.buckets[key] = b
+		return b
+	}
+
+	b.tokens = math.Min(float64(s.burst), b.tokens+float64(now.Sub(b.lastSeen))*s.fillRate)
+	b.lastSeen = now
+	return b
+}
+
+// Take takes a token from the bucket of the key. The reset time is when the
+// bucket will have a token again.
+func (s *burstStore) Take(_ context.Context, key string) (uint64, uint64, uint64, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stopped {
+		return 0, 0, 0, false, limiter.ErrStopped
+	}
+
+	now := time.Now()
+	b := s.refill(key, now)
+
+	ok := b.tokens >= 1
+	if ok {
+		b.tokens--
+	}
+
+	reset := now
+	if b.tokens < 1 {
+		reset = now.Add(time.Duration((1 - b.tokens) / s.fillRate))
+	}
+
+	return s.burst, uint64(b.tokens), uint64(reset.UnixNano()), ok, nil
+}
+
+// Get returns the burst and the remaining tokens of the bucket of the key.
+func (s *burstStore) Get(_ context.Context, key string) (uint64, uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stopped {
+		return 0, 0, limiter.ErrStopped
+	}
+
+	if _, ok := s.buckets[key]; !ok {
+		return 0, 0, nil
+	}
+	return s.burst, uint64(s.refill(key, time.Now()).tokens), nil
+}
+
+// Set fills the bucket of the key with the given tokens. The interval can't
+// be changed per key.
+func (s *burstStore) Set(_ context.Context, key string, tokens uint64, _ time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.buckets[key] = &burstBucket{tokens: float64(tokens), lastSeen: time.Now()}
+	return nil
+}
+
+// Burst adds the given tokens to the bucket of the key.
+func (s *burstStore) Burst(_ context.Context, key string, tokens uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.refill(key, time.Now()).tokens += float64(tokens)
+	return nil
+}
+
+// Close stops purging stale buckets. Taking tokens from a closed store fails.
+func (s *burstStore) Close(_ context.Context) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.stopped {
+		s.stopped = true
+		s.buckets = make(map[string]*burstBucket)
+		close(s.stopCh)
+	}
+	return nil
+}
+
+func (s *burstStore) purge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.lock.Lock()
+			for key, b := range s.buckets {
+				if now.Sub(b.lastSeen) >= s.staleAge {
+					delete(s.buckets, key)
+				}
+			}
+			s.lock.Unlock()
+
+		case <-s.stopCh:
+			return
+		}
+	}
+}
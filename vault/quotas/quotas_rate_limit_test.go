@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/helper/metricsutil"
 	"github.com/hashicorp/vault/sdk/helper/logging"
+	"github.com/sethvargo/go-limiter/httplimit"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 	"go.uber.org/goleak"
@@ -227,3 +229,161 @@ func TestRateLimitQuota_Update(t *testing.T) {
 
 	require.Nil(t, quota.close(context.Background()))
 }
+
+func TestRateLimitQuota_Allow_Burst(t *testing.T) {
+	rlq := NewRateLimitQuota("test-rate-limiter", "qa", "/foo/bar", "", "", 1, time.Hour, 0)
+	rlq.Burst = 5
+	require.NoError(t, rlq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+	defer rlq.close(context.Background())
+
+	// A client can use its whole burst at once, and must then wait for the
+	// bucket to refill at the rate of the quota.
+	for i := 0; i < 5; i++ {
+		resp, err := rlq.allow(context.Background(), &Request{ClientAddress: "127.0.0.1"})
+		require.NoError(t, err)
+		require.True(t, resp.Allowed, "request %d", i)
+	}
+
+	resp, err := rlq.allow(context.Background(), &Request{ClientAddress: "127.0.0.1"})
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+	require.Equal(t, "5", resp.Headers[httplimit.HeaderRateLimitLimit])
+	require.Equal(t, "0", resp.Headers[httplimit.HeaderRateLimitRemaining])
+
+	retryAfter, err := strconv.Atoi(resp.Headers[httplimit.HeaderRetryAfter])
+	require.NoError(t, err)
+	require.InDelta(t, time.Hour.Seconds(), retryAfter, 60)
+
+	// Other clients have their own burst.
+	resp, err = rlq.allow(context.Background(), &Request{ClientAddress: "127.0.0.2"})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+}
+
+func TestRateLimitQuota_Allow_GroupBy(t *testing.T) {
+	testCases := []struct {
+		groupBy string
+		// requests are sent in order, each with an entity ID and an address;
+		// a single request is allowed per client.
+		requests [][2]string
+		allowed  []bool
+	}{
+		{
+			groupBy:  GroupByIP,
+			requests: [][2]string{{"e1", "127.0.0.1"}, {"e2", "127.0.0.1"}, {"e1", "127.0.0.2"}},
+			allowed:  []bool{true, false, true},
+		},
+		{
+			groupBy:  GroupByNone,
+			requests: [][2]string{{"e1", "127.0.0.1"}, {"e2", "127.0.0.2"}},
+			allowed:  []bool{true, false},
+		},
+		{
+			groupBy:  GroupByEntityThenIP,
+			requests: [][2]string{{"e1", "127.0.0.1"}, {"e2", "127.0.0.1"}, {"e1", "127.0.0.2"}, {"", "127.0.0.1"}, {"", "127.0.0.1"}},
+			allowed:  []bool{true, true, false, true, false},
+		},
+		{
+			groupBy:  GroupByEntityThenNone,
+			requests: [][2]string{{"e1", "127.0.0.1"}, {"e2", "127.0.0.1"}, {"", "127.0.0.1"}, {"", "127.0.0.2"}},
+			allowed:  []bool{true, true, true, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.groupBy, func(t *testing.T) {
+			rlq := NewRateLimitQuota("test-rate-limiter", "qa", "/foo/bar", "", "", 1, time.Hour, 0)
+			rlq.GroupBy = tc.groupBy
+			require.NoError(t, rlq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+			defer rlq.close(context.Background())
+
+			for i, req := range tc.requests {
+				resp, err := rlq.allow(context.Background(), &Request{EntityID: req[0], ClientAddress: req[1]})
+				require.NoError(t, err)
+				require.Equal(t, tc.allowed[i], resp.Allowed, "request %d", i)
+			}
+		})
+	}
+}
+
+func TestQuotas_RateLimit_EntityResolver(t *testing.T) {
+	qm, err := NewManager(logging.NewVaultLogger(log.Trace), nil, metricsutil.BlackholeSink())
+	require.NoError(t, err)
+
+	var resolved []string
+	qm.SetEntityResolver(func(_ context.Context, token string) string {
+		resolved = append(resolved, token)
+		return "entity-" + token
+	})
+
+	quota := NewRateLimitQuota("by-ip", "", "ip/", "", "", 1, time.Hour, 0)
+	require.NoError(t, qm.SetQuota(context.Background(), TypeRateLimit.String(), quota, false))
+	quota = NewRateLimitQuota("by-entity", "", "entity/", "", "", 1, time.Hour, 0)
+	quota.GroupBy = GroupByEntityThenNone
+	require.NoError(t, qm.SetQuota(context.Background(), TypeRateLimit.String(), quota, false))
+
+	// Tokens are only resolved for quotas grouping clients by entity.
+	_, err = qm.ApplyQuota(context.Background(), &Request{Type: TypeRateLimit, MountPath: "ip/", ClientAddress: "127.0.0.1", ClientToken: "t1"})
+	require.NoError(t, err)
+	require.Empty(t, resolved)
+
+	for _, token := range []string{"t1", "t2"} {
+		resp, err := qm.ApplyQuota(context.Background(), &Request{Type: TypeRateLimit, MountPath: "entity/", ClientAddress: "127.0.0.1", ClientToken: token})
+		require.NoError(t, err)
+		require.True(t, resp.Allowed)
+	}
+	require.Equal(t, []string{"t1", "t2"}, resolved)
+
+	resp, err := qm.ApplyQuota(context.Background(), &Request{Type: TypeRateLimit, MountPath: "entity/", ClientAddress: "127.0.0.1", ClientToken: "t1"})
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+}
+
+func TestQuotas_RateLimit_Methods(t *testing.T) {
+	qm, err := NewManager(logging.NewVaultLogger(log.Trace), nil, metricsutil.BlackholeSink())
+	require.NoError(t, err)
+
+	setQuotaFunc := func(t *testing.T, name, pathSuffix string, methods ...string) Quota {
+		t.Helper()
+		quota := NewRateLimitQuota(name, "", "kv/", pathSuffix, "", 10, time.Second, 0)
+		quota.Methods = methods
+		require.NoError(t, qm.SetQuota(context.Background(), TypeRateLimit.String(), quota, false))
+		return quota
+	}
+
+	mountQuota := setQuotaFunc(t, "mount", "")
+	writeQuota := setQuotaFunc(t, "write", "data/*", "put", "DELETE")
+	readQuota := setQuotaFunc(t, "read", "data/*", "GET", "LIST")
+	require.Equal(t, []string{"DELETE", "POST"}, writeQuota.(*RateLimitQuota).Methods)
+
+	for method, expected := range map[string]Quota{
+		"GET":    readQuota,
+		"LIST":   readQuota,
+		"POST":   writeQuota,
+		"PUT":    writeQuota,
+		"DELETE": writeQuota,
+		"PATCH":  mountQuota,
+	} {
+		quota, err := qm.QueryQuota(&Request{
+			Type:      TypeRateLimit,
+			Path:      "kv/data/foo",
+			MountPath: "kv/",
+			Method:    method,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, quota, method)
+	}
+
+	// Quotas with the same factors conflict only if their methods overlap.
+	quota, err := qm.QuotaByFactors(context.Background(), TypeRateLimit.String(), "", "kv/", "data/*", "", []string{"PATCH"})
+	require.NoError(t, err)
+	require.Nil(t, quota)
+	quota, err = qm.QuotaByFactors(context.Background(), TypeRateLimit.String(), "", "kv/", "data/*", "", []string{"POST"})
+	require.NoError(t, err)
+	require.Equal(t, writeQuota, quota)
+
+	_, err = NormalizeMethods([]string{"HEAD"})
+	require.Error(t, err)
+}
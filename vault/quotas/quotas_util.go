@@ -7,9 +7,7 @@ package quotas
 
 import (
 	"context"
-
-	log "github.com/hashicorp/go-hclog"
-	"github.com/hashicorp/vault/helper/metricsutil"
+	"sync"
 
 	memdb "github.com/hashicorp/go-memdb"
 )
@@ -17,56 +15,141 @@ import (
 func quotaTypes() []string {
 	return []string{
 		TypeRateLimit.String(),
+		TypeLeaseCount.String(),
 	}
 }
 
-func (m *Manager) init(walkFunc leaseWalkFunc) {}
+func (m *Manager) init(walkFunc leaseWalkFunc) {
+	m.leaseWalkFunc = walkFunc
+	m.leasePaths = make(map[string]struct{})
+}
 
+// recomputeLeaseCounts walks all the leases and counts them against the lease
+// count quota rules in the given transaction.
 func (m *Manager) recomputeLeaseCounts(ctx context.Context, txn *memdb.Txn) error {
+	if m.leaseWalkFunc == nil {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	var queryErr error
+	err := m.leaseWalkFunc(ctx, func(req *Request) bool {
+		req.Type = TypeLeaseCount
+		m.addLeasePath(req.Path)
+
+		quota, err := m.queryQuota(txn, req)
+		if err != nil {
+			queryErr = err
+			return false
+		}
+		if quota != nil {
+			counts[quota.quotaID()]++
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if queryErr != nil {
+		return queryErr
+	}
+
+	iter, err := txn.Get(TypeLeaseCount.String(), indexID)
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		quota := raw.(*LeaseCountQuota)
+		quota.setCount(counts[quota.ID])
+	}
+
 	return nil
 }
 
 func (m *Manager) setIsPerfStandby(quota Quota) {}
 
 func (m *Manager) inLeasePathCache(path string) bool {
-	return false
-}
+	m.leasePathsLock.RLock()
+	defer m.leasePathsLock.RUnlock()
 
-type entManager struct {
-	isPerfStandby bool
-	isDRSecondary bool
+	_, ok := m.leasePaths[path]
+	return ok
 }
 
-func (*entManager) Reset() error {
-	return nil
+func (m *Manager) addLeasePath(path string) {
+	m.leasePathsLock.Lock()
+	defer m.leasePathsLock.Unlock()
+
+	m.leasePaths[path] = struct{}{}
 }
 
-type LeaseCountQuota struct{}
+// HandleLeases updates the lease count quota rules with the leases the
+// expiration manager loaded, created or deleted. The request paths of created
+// and loaded leases are remembered, so that lease count quotas only apply to
+// requests which may create leases.
+func (m *Manager) HandleLeases(action LeaseAction, reqs []*Request) error {
+	m.dbAndCacheLock.RLock()
+	defer m.dbAndCacheLock.RUnlock()
 
-func (l LeaseCountQuota) allow(_ context.Context, _ *Request) (Response, error) {
-	panic("implement me")
-}
+	if m.isDRSecondary {
+		return nil
+	}
 
-func (l LeaseCountQuota) quotaID() string {
-	panic("implement me")
-}
+	txn := m.db.Txn(false)
+	for _, req := range reqs {
+		req.Type = TypeLeaseCount
+		if action == LeaseActionCreated || action == LeaseActionLoaded {
+			m.addLeasePath(req.Path)
+		}
+
+		quota, err := m.queryQuota(txn, req)
+		if err != nil {
+			return err
+		}
+		if quota == nil {
+			continue
+		}
+
+		lcq := quota.(*LeaseCountQuota)
+		switch action {
+		case LeaseActionCreated, LeaseActionLoaded:
+			lcq.inc()
+		case LeaseActionDeleted:
+			lcq.dec()
+		}
+	}
 
-func (l LeaseCountQuota) QuotaName() string {
-	panic("implement me")
+	return nil
 }
 
-func (l LeaseCountQuota) initialize(logger log.Logger, sink *metricsutil.ClusterMetricSink) error {
-	panic("implement me")
-}
+// AckLease releases the lease reserved for a request allowed by a lease count
+// quota rule.
+func (m *Manager) AckLease(acc Access) error {
+	quota, err := m.QuotaByID(TypeLeaseCount.String(), acc.QuotaID())
+	if err != nil {
+		return err
+	}
 
-func (l LeaseCountQuota) close(_ context.Context) error {
-	panic("implement me")
+	// The quota rule may have been deleted while the request was processed
+	if lcq, ok := quota.(*LeaseCountQuota); ok {
+		lcq.ack()
+	}
+	return nil
 }
 
-func (l LeaseCountQuota) Clone() Quota {
-	panic("implement me")
+type entManager struct {
+	isPerfStandby bool
+	isDRSecondary bool
+
+	leaseWalkFunc  leaseWalkFunc
+	leasePathsLock sync.RWMutex
+	leasePaths     map[string]struct{}
 }
 
-func (l LeaseCountQuota) handleRemount(mountPath, nsPath string) {
-	panic("implement me")
+func (e *entManager) Reset() error {
+	e.leasePathsLock.Lock()
+	defer e.leasePathsLock.Unlock()
+
+	e.leasePaths = make(map[string]struct{})
+	return nil
 }
@@ -330,6 +330,9 @@ func (c *Core) setupRaftActiveNode(ctx context.Context) error {
 	if err := c.monitorUndoLogs(); err != nil {
 		return err
 	}
+	if err := c.startRaftAutoSnapshots(ctx); err != nil {
+		return err
+	}
 	return c.startPeriodicRaftTLSRotate(ctx)
 }
 
@@ -346,6 +349,7 @@ func (c *Core) stopRaftActiveNode() {
 	}
 
 	c.pendingRaftPeers = nil
+	c.stopRaftAutoSnapshots()
 	c.stopPeriodicRaftTLSRotate()
 }
 
@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/vault/physical/raft"
+)
+
+// raftCompactPollInterval is how often the applied index of a follower is
+// checked while waiting for it to compact its database.
+const raftCompactPollInterval = time.Second
+
+// raftCompactResult describes a compaction of the raft cluster.
+type raftCompactResult struct {
+	// Nodes lists the nodes that were compacted, in order
+	Nodes []string
+
+	// LeadershipTransferred is true if the active node handed leadership
+	// over to another node, and compacts its database in the background.
+	LeadershipTransferred bool
+
+	// Stats is set when the active node compacted its database in place.
+	Stats *raft.CompactStats
+}
+
+// compactRaftStorage compacts the FSM database of every node of the raft
+// cluster, one node at a time. The followers are compacted first. The active
+// node then hands leadership over to another voter, so that writes aren't
+// blocked while its database is rewritten, unless there is no other voter.
+func (c *Core) compactRaftStorage(ctx context.Context) (*raftCompactResult, error) {
+	raftBackend := c.getRaftBackend()
+	if raftBackend == nil {
+		return nil, errors.New("raft storage is not in use")
+	}
+
+	config, err := raftBackend.GetConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &raftCompactResult{}
+	localID := raftBackend.NodeID()
+	var otherVoters bool
+	for _, server := range config.Servers {
+		if server.NodeID == localID {
+			continue
+		}
+		if server.Voter {
+			otherVoters = true
+		}
+
+		if err := c.compactRaftFollower(ctx, raftBackend, server.NodeID); err != nil {
+			return result, fmt.Errorf("failed to compact node %q: %w", server.NodeID, err)
+		}
+		result.Nodes = append(result.Nodes, server.NodeID)
+	}
+
+	if !otherVoters {
+		stats, err := raftBackend.Compact()
+		if err != nil {
+			return result, fmt.Errorf("failed to compact node %q: %w", localID, err)
+		}
+		result.Nodes = append(result.Nodes, localID)
+		result.Stats = stats
+		return result, nil
+	}
+
+	if err := raftBackend.TransferLeadership(); err != nil {
+		return result, fmt.Errorf("failed to transfer leadership: %w", err)
+	}
+	result.LeadershipTransferred = true
+	result.Nodes = append(result.Nodes, localID)
+
+	// This node is a follower now, and steps down once the request is done.
+	// Don't hold the request, and the step down, for the compaction.
+	go func() {
+		if _, err := raftBackend.Compact(); err != nil {
+			c.logger.Error("failed to compact raft storage", "error", err)
+		}
+	}()
+
+	return result, nil
+}
+
+// compactRaftFollower asks a follower to compact its database and waits until
+// it did so.
+func (c *Core) compactRaftFollower(ctx context.Context, raftBackend *raft.RaftBackend, nodeID string) error {
+	if _, ok := c.raftFollowerStates.AppliedIndex(nodeID); !ok {
+		return errors.New("node has not reported its state")
+	}
+
+	index, err := raftBackend.RequestCompaction(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(raftCompactPollInterval)
+	defer ticker.Stop()
+	for {
+		if applied, ok := c.raftFollowerStates.AppliedIndex(nodeID); ok && applied >= index {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the compaction, which may still be in progress: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,406 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/vault/sdk/helper/jsonutil"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault/snapshotstore"
+)
+
+const (
+	// raftAutoSnapshotConfigPath is the storage prefix of the automatic
+	// snapshot configurations.
+	raftAutoSnapshotConfigPath = "core/raft/snapshot-auto/config/"
+
+	defaultRaftAutoSnapshotFilePrefix = "vault-snapshot"
+	defaultRaftAutoSnapshotRetain     = 1
+)
+
+// raftAutoSnapshotConfig configures snapshots of the raft storage taken and
+// saved periodically by the active node.
+type raftAutoSnapshotConfig struct {
+	Name          string            `json:"name"`
+	Interval      time.Duration     `json:"interval"`
+	Retain        int               `json:"retain"`
+	PathPrefix    string            `json:"path_prefix"`
+	FilePrefix    string            `json:"file_prefix"`
+	StorageType   string            `json:"storage_type"`
+	LocalMaxSpace int64             `json:"local_max_space"`
+	StorageConfig map[string]string `json:"storage_config"`
+}
+
+func (c *raftAutoSnapshotConfig) newStore(logger hclog.Logger) (snapshotstore.Store, error) {
+	return snapshotstore.New(c.StorageType, c.PathPrefix, c.StorageConfig, logger)
+}
+
+// raftAutoSnapshotStatus reports the outcome of the snapshots of a
+// configuration. The last snapshot fields describe the last attempt, whether
+// it succeeded or not.
+type raftAutoSnapshotStatus struct {
+	ConsecutiveErrors int       `json:"consecutive_errors"`
+	LastSnapshotStart time.Time `json:"last_snapshot_start"`
+	LastSnapshotEnd   time.Time `json:"last_snapshot_end"`
+	LastSnapshotError string    `json:"last_snapshot_error"`
+	LastSnapshotURL   string    `json:"last_snapshot_url"`
+	NextSnapshotStart time.Time `json:"next_snapshot_start"`
+
+	// SnapshotStart and SnapshotURL describe the last successful snapshot
+	SnapshotStart time.Time `json:"snapshot_start"`
+	SnapshotURL   string    `json:"snapshot_url"`
+}
+
+// raftAutoSnapshotSchedule takes the snapshots of a configuration.
+type raftAutoSnapshotSchedule struct {
+	config *raftAutoSnapshotConfig
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	statusLock sync.RWMutex
+	status     raftAutoSnapshotStatus
+}
+
+// raftAutoSnapshots runs the automatic snapshot schedules on the active node.
+type raftAutoSnapshots struct {
+	logger hclog.Logger
+
+	// snapshot writes a snapshot of the raft storage
+	snapshot func(w io.Writer) error
+
+	lock      sync.Mutex
+	schedules map[string]*raftAutoSnapshotSchedule
+}
+
+func newRaftAutoSnapshots(logger hclog.Logger, snapshot func(w io.Writer) error) *raftAutoSnapshots {
+	return &raftAutoSnapshots{
+		logger:    logger,
+		snapshot:  snapshot,
+		schedules: make(map[string]*raftAutoSnapshotSchedule),
+	}
+}
+
+// set starts taking the snapshots of the configuration, replacing the
+// schedule of the configuration with the same name if there is one.
+func (a *raftAutoSnapshots) set(config *raftAutoSnapshotConfig) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if existing, ok := a.schedules[config.Name]; ok {
+		existing.stop()
+	}
+
+	schedule := &raftAutoSnapshotSchedule{
+		config: config,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	schedule.status.NextSnapshotStart = time.Now().Add(config.Interval)
+	a.schedules[config.Name] = schedule
+
+	go a.run(schedule)
+}
+
+// remove stops taking the snapshots of the named configuration.
+func (a *raftAutoSnapshots) remove(name string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if schedule, ok := a.schedules[name]; ok {
+		schedule.stop()
+		delete(a.schedules, name)
+	}
+}
+
+// stopAll stops every schedule, waiting for the snapshots in progress.
+func (a *raftAutoSnapshots) stopAll() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for name, schedule := range a.schedules {
+		schedule.stop()
+		delete(a.schedules, name)
+	}
+}
+
+// status returns the status of the named configuration, if it is scheduled.
+func (a *raftAutoSnapshots) status(name string) (raftAutoSnapshotStatus, bool) {
+	a.lock.Lock()
+	schedule, ok := a.schedules[name]
+	a.lock.Unlock()
+	if !ok {
+		return raftAutoSnapshotStatus{}, false
+	}
+
+	schedule.statusLock.RLock()
+	defer schedule.statusLock.RUnlock()
+	return schedule.status, true
+}
+
+func (s *raftAutoSnapshotSchedule) stop() {
+	close(s.stopCh)
+	<-s.doneCh
+}
+
+func (a *raftAutoSnapshots) run(schedule *raftAutoSnapshotSchedule) {
+	defer close(schedule.doneCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-schedule.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	timer := time.NewTimer(schedule.config.Interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-schedule.stopCh:
+			return
+		case <-timer.C:
+			a.takeSnapshot(ctx, schedule)
+			timer.Reset(schedule.config.Interval)
+		}
+	}
+}
+
+// takeSnapshot takes a snapshot of the raft storage, saves it and removes the
+// snapshots exceeding the retention of the configuration.
+func (a *raftAutoSnapshots) takeSnapshot(ctx context.Context, schedule *raftAutoSnapshotSchedule) {
+	config := schedule.config
+	logger := a.logger.With("name", config.Name)
+	labels := []metrics.Label{{Name: "config", Value: config.Name}}
+	start := time.Now()
+
+	url, err := a.saveSnapshot(ctx, config, logger, labels, start)
+	end := time.Now()
+
+	schedule.statusLock.Lock()
+	defer schedule.statusLock.Unlock()
+	schedule.status.LastSnapshotStart = start
+	schedule.status.LastSnapshotEnd = end
+	schedule.status.LastSnapshotURL = url
+	schedule.status.NextSnapshotStart = end.Add(config.Interval)
+	if err != nil {
+		logger.Error("failed to take automatic snapshot", "error", err)
+		metrics.IncrCounterWithLabels([]string{"autosnapshots", "save", "errors"}, 1, labels)
+		schedule.status.ConsecutiveErrors++
+		schedule.status.LastSnapshotError = err.Error()
+		return
+	}
+
+	logger.Info("took automatic snapshot", "url", url)
+	metrics.MeasureSinceWithLabels([]string{"autosnapshots", "save", "duration"}, start, labels)
+	metrics.SetGaugeWithLabels([]string{"autosnapshots", "last", "success", "time"}, float32(end.Unix()), labels)
+	schedule.status.ConsecutiveErrors = 0
+	schedule.status.LastSnapshotError = ""
+	schedule.status.SnapshotStart = start
+	schedule.status.SnapshotURL = url
+}
+
+func (a *raftAutoSnapshots) saveSnapshot(ctx context.Context, config *raftAutoSnapshotConfig, logger hclog.Logger, labels []metrics.Label, start time.Time) (string, error) {
+	store, err := config.newStore(logger)
+	if err != nil {
+		return "", fmt.Errorf("failed to set up storage: %w", err)
+	}
+
+	// Spool the snapshot to disk, so that its size is known before uploading
+	// it and the raft storage isn't held for the duration of the upload
+	f, err := os.CreateTemp("", "vault-raft-snapshot-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	if err := a.snapshot(f); err != nil {
+		return "", fmt.Errorf("failed to take snapshot: %w", err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	metrics.AddSampleWithLabels([]string{"autosnapshots", "snapshot", "size"}, float32(size), labels)
+
+	if config.StorageType == snapshotstore.TypeLocal && config.LocalMaxSpace > 0 {
+		existing, err := store.List(ctx, config.FilePrefix+"-")
+		if err != nil {
+			return "", fmt.Errorf("failed to list snapshots: %w", err)
+		}
+		var used int64
+		for _, snapshot := range existing {
+			used += snapshot.Size
+		}
+		// Retention applies after the new snapshot is saved, so the
+		// snapshots it removes still count towards the allowance
+		if used+size > config.LocalMaxSpace {
+			return "", fmt.Errorf("snapshot of %d bytes would exceed local_max_space of %d bytes, %d of which are used", size, config.LocalMaxSpace, used)
+		}
+	}
+
+	name := fmt.Sprintf("%s-%d.snap", config.FilePrefix, start.UnixNano())
+	url, err := store.Put(ctx, name, f, size)
+	if err != nil {
+		return "", fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	if err := applyRaftAutoSnapshotRetention(ctx, store, config, labels); err != nil {
+		// The snapshot was saved, so only report the failure
+		logger.Warn("failed to remove old automatic snapshots", "error", err)
+	}
+
+	return url, nil
+}
+
+// applyRaftAutoSnapshotRetention removes the oldest snapshots of the
+// configuration beyond its retention.
+func applyRaftAutoSnapshotRetention(ctx context.Context, store snapshotstore.Store, config *raftAutoSnapshotConfig, labels []metrics.Label) error {
+	defer metrics.MeasureSinceWithLabels([]string{"autosnapshots", "rotate", "duration"}, time.Now(), labels)
+
+	snapshots, err := store.List(ctx, config.FilePrefix+"-")
+	if err != nil {
+		return err
+	}
+
+	var retErr *multierror.Error
+	if config.Retain > 0 && len(snapshots) > config.Retain {
+		for _, snapshot := range snapshots[:len(snapshots)-config.Retain] {
+			if err := store.Delete(ctx, snapshot.Name); err != nil {
+				retErr = multierror.Append(retErr, fmt.Errorf("failed to delete %q: %w", snapshot.Name, err))
+			}
+		}
+		snapshots = snapshots[len(snapshots)-config.Retain:]
+	}
+
+	metrics.SetGaugeWithLabels([]string{"autosnapshots", "snapshots", "in", "storage"}, float32(len(snapshots)), labels)
+	if config.StorageType == snapshotstore.TypeLocal {
+		var used int64
+		for _, snapshot := range snapshots {
+			used += snapshot.Size
+		}
+		metrics.SetGaugeWithLabels([]string{"autosnapshots", "total", "snapshot", "size"}, float32(used), labels)
+		if config.LocalMaxSpace > 0 {
+			metrics.SetGaugeWithLabels([]string{"autosnapshots", "percent", "maxspace", "used"}, float32(used)*100/float32(config.LocalMaxSpace), labels)
+		}
+	}
+
+	return retErr.ErrorOrNil()
+}
+
+// loadRaftAutoSnapshotConfig reads the named automatic snapshot
+// configuration, returning nil if it doesn't exist.
+func (c *Core) loadRaftAutoSnapshotConfig(ctx context.Context, name string) (*raftAutoSnapshotConfig, error) {
+	entry, err := c.barrier.Get(ctx, raftAutoSnapshotConfigPath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var config raftAutoSnapshotConfig
+	if err := jsonutil.DecodeJSON(entry.Value, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// startRaftAutoSnapshots starts taking the automatic snapshots configured in
+// storage. It is a no-op if raft isn't the storage backend.
+func (c *Core) startRaftAutoSnapshots(ctx context.Context) error {
+	raftBackend := c.getRaftBackend()
+	if raftBackend == nil || c.isRaftHAOnly() {
+		return nil
+	}
+
+	logger := c.logger.Named("raft-snapshot-auto")
+	c.AddLogger(logger)
+	autoSnapshots := newRaftAutoSnapshots(logger, func(w io.Writer) error {
+		return raftBackend.Snapshot(w, c.seal.GetAccess())
+	})
+
+	names, err := c.barrier.List(ctx, raftAutoSnapshotConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to list automatic snapshot configurations: %w", err)
+	}
+	for _, name := range names {
+		config, err := c.loadRaftAutoSnapshotConfig(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to load automatic snapshot configuration %q: %w", name, err)
+		}
+		if config != nil {
+			autoSnapshots.set(config)
+		}
+	}
+
+	c.raftAutoSnapshotsLock.Lock()
+	c.raftAutoSnapshots = autoSnapshots
+	c.raftAutoSnapshotsLock.Unlock()
+	return nil
+}
+
+// stopRaftAutoSnapshots stops taking automatic snapshots.
+func (c *Core) stopRaftAutoSnapshots() {
+	c.raftAutoSnapshotsLock.Lock()
+	autoSnapshots := c.raftAutoSnapshots
+	c.raftAutoSnapshots = nil
+	c.raftAutoSnapshotsLock.Unlock()
+
+	if autoSnapshots != nil {
+		autoSnapshots.stopAll()
+	}
+}
+
+// getRaftAutoSnapshots returns the automatic snapshot schedules, or nil if
+// they are not running on this node.
+func (c *Core) getRaftAutoSnapshots() *raftAutoSnapshots {
+	c.raftAutoSnapshotsLock.RLock()
+	defer c.raftAutoSnapshotsLock.RUnlock()
+	return c.raftAutoSnapshots
+}
+
+func (c *Core) putRaftAutoSnapshotConfig(ctx context.Context, config *raftAutoSnapshotConfig) error {
+	entry, err := logical.StorageEntryJSON(raftAutoSnapshotConfigPath+config.Name, config)
+	if err != nil {
+		return err
+	}
+	if err := c.barrier.Put(ctx, entry); err != nil {
+		return err
+	}
+
+	if autoSnapshots := c.getRaftAutoSnapshots(); autoSnapshots != nil {
+		autoSnapshots.set(config)
+	}
+	return nil
+}
+
+func (c *Core) deleteRaftAutoSnapshotConfig(ctx context.Context, name string) error {
+	if err := c.barrier.Delete(ctx, raftAutoSnapshotConfigPath+name); err != nil {
+		return err
+	}
+
+	if autoSnapshots := c.getRaftAutoSnapshots(); autoSnapshots != nil {
+		autoSnapshots.remove(name)
+	}
+	return nil
+}
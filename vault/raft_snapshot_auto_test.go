@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/vault/snapshotstore"
+)
+
+func testRaftAutoSnapshots(t *testing.T, snapshot string) *raftAutoSnapshots {
+	t.Helper()
+
+	a := newRaftAutoSnapshots(hclog.NewNullLogger(), func(w io.Writer) error {
+		_, err := io.WriteString(w, snapshot)
+		return err
+	})
+	t.Cleanup(a.stopAll)
+	return a
+}
+
+func TestRaftAutoSnapshots_retention(t *testing.T) {
+	dir := t.TempDir()
+	a := testRaftAutoSnapshots(t, "snapshot")
+
+	a.set(&raftAutoSnapshotConfig{
+		Name:        "local",
+		Interval:    20 * time.Millisecond,
+		Retain:      2,
+		PathPrefix:  dir,
+		FilePrefix:  "vault-snapshot",
+		StorageType: snapshotstore.TypeLocal,
+	})
+
+	// A file not matching the prefix is never removed
+	if err := os.WriteFile(filepath.Join(dir, "other.snap"), []byte("other"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for more snapshots than are retained
+	urls := make(map[string]bool)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(urls) < 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for snapshots, got %d", len(urls))
+		}
+		status, ok := a.status("local")
+		if !ok {
+			t.Fatal("expected the configuration to be scheduled")
+		}
+		if status.LastSnapshotError != "" {
+			t.Fatalf("unexpected error: %s", status.LastSnapshotError)
+		}
+		if status.LastSnapshotURL != "" {
+			urls[status.LastSnapshotURL] = true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	a.remove("local")
+	if _, ok := a.status("local"); ok {
+		t.Fatal("expected the configuration to be unscheduled")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if len(names) != 3 || names[0] != "other.snap" || !strings.HasPrefix(names[1], "vault-snapshot-") || !strings.HasPrefix(names[2], "vault-snapshot-") {
+		t.Fatalf("bad snapshots: %v", names)
+	}
+}
+
+func TestRaftAutoSnapshots_localMaxSpace(t *testing.T) {
+	a := testRaftAutoSnapshots(t, "snapshot")
+	schedule := &raftAutoSnapshotSchedule{
+		config: &raftAutoSnapshotConfig{
+			Name:          "local",
+			Interval:      time.Hour,
+			PathPrefix:    t.TempDir(),
+			FilePrefix:    "vault-snapshot",
+			StorageType:   snapshotstore.TypeLocal,
+			LocalMaxSpace: int64(len("snapshot")) + 1,
+			Retain:        5,
+		},
+	}
+
+	a.takeSnapshot(context.Background(), schedule)
+	if schedule.status.LastSnapshotError != "" || schedule.status.ConsecutiveErrors != 0 {
+		t.Fatalf("unexpected error: %s", schedule.status.LastSnapshotError)
+	}
+
+	// There is no space left for a second snapshot
+	for i := 1; i <= 2; i++ {
+		a.takeSnapshot(context.Background(), schedule)
+		if !strings.Contains(schedule.status.LastSnapshotError, "local_max_space") {
+			t.Fatalf("expected the snapshot to exceed the space allowance, got %q", schedule.status.LastSnapshotError)
+		}
+		if schedule.status.ConsecutiveErrors != i {
+			t.Fatalf("expected %d consecutive errors, got %d", i, schedule.status.ConsecutiveErrors)
+		}
+	}
+}
@@ -672,6 +672,10 @@ func (c *Core) handleCancelableRequest(ctx context.Context, req *logical.Request
 		return nil, logical.CodedError(403, "namespaces feature not enabled")
 	}
 
+	if ns.ID != namespace.RootNamespaceID && strings.HasPrefix(req.Path, "sys/") && !namespaceSysPaths.HasPath(req.Path) {
+		return nil, logical.CodedError(403, fmt.Sprintf("path %q is only available in the root namespace", req.Path))
+	}
+
 	walState := &logical.WALState{}
 	ctx = logical.IndexStateContext(ctx, walState)
 	var auth *logical.Auth
@@ -1567,6 +1571,10 @@ func (c *Core) handleLoginRequest(ctx context.Context, req *logical.Request) (re
 
 			auth.EntityID = entity.ID
 			auth.EntityCreated = entityCreated
+			auth.GroupAliases, err = c.identityStore.resolveGroupAliases(ctx, auth.Alias, auth.GroupAliases)
+			if err != nil {
+				return nil, nil, err
+			}
 			validAliases, err := c.identityStore.refreshExternalGroupMembershipsByEntityID(ctx, auth.EntityID, auth.GroupAliases, req.MountAccessor)
 			if err != nil {
 				return nil, nil, err
@@ -1616,7 +1624,7 @@ func (c *Core) handleLoginRequest(ctx context.Context, req *logical.Request) (re
 			// run single-phase login MFA check, else run two-phase login MFA check
 			if len(matchedMfaEnforcementList) > 0 && len(req.MFACreds) > 0 {
 				for _, eConfig := range matchedMfaEnforcementList {
-					err = c.validateLoginMFA(ctx, eConfig, entity, req.Connection.RemoteAddr, req.MFACreds)
+					err = c.validateLoginMFA(ctx, eConfig, entity, req.Connection.RemoteAddr, req.MFACreds, auth)
 					if err != nil {
 						return nil, nil, logical.ErrPermissionDenied
 					}
@@ -7,6 +7,7 @@ package vault
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -18,12 +19,40 @@ func waitForReplicationState(context.Context, *Core, *logical.Request) (*sync.Wa
 	return nil, nil
 }
 
-func checkNeedsCG(context.Context, *Core, *logical.Request, *logical.Auth, error, []string) (error, *logical.Response, *logical.Auth, error) {
-	return nil, nil, nil, nil
+// checkNeedsCG creates a control group request if the request requires the
+// authorization of a control group, and returns the response wrapping its
+// token
+func checkNeedsCG(ctx context.Context, c *Core, req *logical.Request, auth *logical.Auth, err error, nonHMACReqDataKeys []string) (error, *logical.Response, *logical.Auth, error) {
+	var cgErr *controlGroupRequiredError
+	if !errors.As(err, &cgErr) {
+		return nil, nil, nil, nil
+	}
+
+	resp, err := c.createControlGroupRequest(ctx, req, auth, cgErr.controlGroup)
+	if err != nil {
+		return err, nil, nil, nil
+	}
+
+	logInput := &logical.LogInput{
+		Auth:               auth,
+		Request:            req,
+		Response:           resp,
+		NonHMACReqDataKeys: nonHMACReqDataKeys,
+	}
+	if err := c.auditBroker.LogRequest(ctx, logInput, c.auditedHeaders); err != nil {
+		c.logger.Error("failed to audit request", "path", req.Path, "error", err)
+		return nil, nil, auth, ErrInternalError
+	}
+	if err := c.auditBroker.LogResponse(ctx, logInput, c.auditedHeaders); err != nil {
+		c.logger.Error("failed to audit response", "path", req.Path, "error", err)
+		return nil, nil, auth, ErrInternalError
+	}
+
+	return nil, resp, auth, nil
 }
 
 func checkErrControlGroupTokenNeedsCreated(err error) bool {
-	return false
+	return errors.Is(err, errControlGroupRequired)
 }
 
 func shouldForward(c *Core, resp *logical.Response, err error) bool {
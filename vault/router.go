@@ -5,6 +5,7 @@ package vault
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -29,6 +30,15 @@ var deniedPassthroughRequestHeaders = []string{
 // matches when '+' is next to a non-slash char
 var wcAdjacentNonSlashRegEx = regexp.MustCompile(`\+[^/]|[^/]\+`).MatchString
 
+// namespaceSharedMounts are the mounts of the root namespace that also serve
+// every other namespace.
+var namespaceSharedMounts = []string{
+	"sys/",
+	credentialRoutePrefix + "token/",
+	"identity/",
+	"cubbyhole/",
+}
+
 // Router is used to do prefix based routing of a request to a logical backend
 type Router struct {
 	l                  sync.RWMutex
@@ -40,7 +50,14 @@ type Router struct {
 	// to the backend. This is used to map a key back into the backend that owns it.
 	// For example, logical/uuid1/foobar -> secrets/ (kv backend) + foobar
 	storagePrefix *radix.Tree
+	// storageRoutes is a copy of storagePrefix replaced on every change, so
+	// that the storage layer can look up route entries without taking the
+	// router lock, which is held while backends are cleaned up.
+	storageRoutes atomic.Value // *radix.Tree
 	logger        hclog.Logger
+
+	// routeMetrics records the latency of the routed requests, if set
+	routeMetrics *routeMetrics
 }
 
 // NewRouter returns a new router
@@ -53,19 +70,22 @@ func NewRouter() *Router {
 		// this will get replaced in production with a real logger but it's useful to have a default in place for tests
 		logger: hclog.NewNullLogger(),
 	}
+	r.storageRoutes.Store(radix.New())
 	return r
 }
 
 // routeEntry is used to represent a mount point in the router
 type routeEntry struct {
-	tainted       bool
-	backend       logical.Backend
-	mountEntry    *MountEntry
-	storageView   logical.Storage
-	storagePrefix string
-	rootPaths     atomic.Value
-	loginPaths    atomic.Value
-	l             sync.RWMutex
+	tainted        bool
+	backend        logical.Backend
+	mountEntry     *MountEntry
+	storageView    logical.Storage
+	storagePrefix  string
+	rootPaths      atomic.Value
+	loginPaths     atomic.Value
+	streamingPaths atomic.Value
+	sealWrapPaths  atomic.Value
+	l              sync.RWMutex
 }
 
 type wildcardPath struct {
@@ -75,7 +95,8 @@ type wildcardPath struct {
 	isPrefix bool
 }
 
-// loginPathsEntry is used to hold the routeEntry loginPaths
+// loginPathsEntry is used to hold the routeEntry loginPaths and
+// streamingPaths
 type loginPathsEntry struct {
 	paths         *radix.Tree
 	wildcardPaths []wildcardPath
@@ -95,6 +116,7 @@ func (r *Router) reset() {
 	r.storagePrefix = radix.New()
 	r.mountUUIDCache = radix.New()
 	r.mountAccessorCache = radix.New()
+	r.storageRoutes.Store(radix.New())
 }
 
 func (r *Router) GetRecords(tag string) ([]map[string]interface{}, error) {
@@ -164,6 +186,21 @@ func (re *routeEntry) SaltID(id string) string {
 	return salt.SaltID(re.mountEntry.UUID, id, salt.SHA1Hash)
 }
 
+// namespaceRoutePath returns the path a request of the namespace is routed
+// with. The mounts of the root namespace that are shared by every namespace
+// serve the requests of the other namespaces, and scope them to the namespace
+// of the request.
+func namespaceRoutePath(ns *namespace.Namespace, path string) string {
+	if ns.ID != namespace.RootNamespaceID {
+		for _, prefix := range namespaceSharedMounts {
+			if strings.HasPrefix(path, prefix) {
+				return path
+			}
+		}
+	}
+	return ns.Path + path
+}
+
 // Mount is used to expose a logical backend at a given prefix, using a unique salt,
 // and the barrier view for that path.
 func (r *Router) Mount(backend logical.Backend, prefix string, mountEntry *MountEntry, storageView *BarrierView) error {
@@ -201,6 +238,12 @@ func (r *Router) Mount(backend logical.Backend, prefix string, mountEntry *Mount
 		return err
 	}
 	re.loginPaths.Store(loginPathsEntry)
+	streamingPathsEntry, err := parseUnauthenticatedPaths(paths.Streaming)
+	if err != nil {
+		return err
+	}
+	re.streamingPaths.Store(streamingPathsEntry)
+	re.sealWrapPaths.Store(pathsToRadix(paths.SealWrapStorage))
 
 	switch {
 	case prefix == "":
@@ -215,6 +258,7 @@ func (r *Router) Mount(backend logical.Backend, prefix string, mountEntry *Mount
 
 	r.root.Insert(prefix, re)
 	r.storagePrefix.Insert(re.storagePrefix, re)
+	r.storageRoutes.Store(radix.NewFromMap(r.storagePrefix.ToMap()))
 	r.mountUUIDCache.Insert(re.mountEntry.UUID, re.mountEntry)
 	r.mountAccessorCache.Insert(re.mountEntry.Accessor, re.mountEntry)
 
@@ -247,6 +291,7 @@ func (r *Router) Unmount(ctx context.Context, prefix string) error {
 	// Purge from the radix trees
 	r.root.Delete(prefix)
 	r.storagePrefix.Delete(re.storagePrefix)
+	r.storageRoutes.Store(radix.NewFromMap(r.storagePrefix.ToMap()))
 	r.mountUUIDCache.Delete(re.mountEntry.UUID)
 	r.mountAccessorCache.Delete(re.mountEntry.Accessor)
 
@@ -312,6 +357,17 @@ func (r *Router) Untaint(ctx context.Context, path string) error {
 	return nil
 }
 
+// storageRouteEntry returns the route entry whose storage prefix is the
+// longest prefix of the storage path, along with the path relative to it.
+// It doesn't take the router lock.
+func (r *Router) storageRouteEntry(path string) (*routeEntry, string, bool) {
+	prefix, raw, ok := r.storageRoutes.Load().(*radix.Tree).LongestPrefix(path)
+	if !ok {
+		return nil, "", false
+	}
+	return raw.(*routeEntry), strings.TrimPrefix(path, prefix), true
+}
+
 func (r *Router) MatchingMountByUUID(mountID string) *MountEntry {
 	if mountID == "" {
 		return nil
@@ -360,7 +416,7 @@ func (r *Router) matchingMountInternal(ctx context.Context, path string) string
 	if err != nil {
 		return ""
 	}
-	path = ns.Path + path
+	path = namespaceRoutePath(ns, path)
 
 	mount, _, ok := r.root.LongestPrefix(path)
 	if !ok {
@@ -417,7 +473,11 @@ func (r *Router) matchingStorage(ctx context.Context, path string, apiPath bool)
 	if err != nil {
 		return nil
 	}
-	path = ns.Path + path
+	if apiPath {
+		path = namespaceRoutePath(ns, path)
+	} else {
+		path = ns.Path + path
+	}
 
 	var raw interface{}
 	var ok bool
@@ -440,7 +500,7 @@ func (r *Router) MatchingMountEntry(ctx context.Context, path string) *MountEntr
 	if err != nil {
 		return nil
 	}
-	path = ns.Path + path
+	path = namespaceRoutePath(ns, path)
 
 	r.l.RLock()
 	_, raw, ok := r.root.LongestPrefix(path)
@@ -457,7 +517,7 @@ func (r *Router) MatchingBackend(ctx context.Context, path string) logical.Backe
 	if err != nil {
 		return nil
 	}
-	path = ns.Path + path
+	path = namespaceRoutePath(ns, path)
 
 	r.l.RLock()
 	_, raw, ok := r.root.LongestPrefix(path)
@@ -479,7 +539,7 @@ func (r *Router) MatchingSystemView(ctx context.Context, path string) logical.Sy
 	if err != nil {
 		return nil
 	}
-	path = ns.Path + path
+	path = namespaceRoutePath(ns, path)
 
 	r.l.RLock()
 	_, raw, ok := r.root.LongestPrefix(path)
@@ -504,7 +564,7 @@ func (r *Router) MatchingStoragePrefixByAPIPath(ctx context.Context, path string
 	if err != nil {
 		return "", false
 	}
-	path = ns.Path + path
+	path = namespaceRoutePath(ns, path)
 
 	_, prefix, found := r.matchingMountEntryByPath(ctx, path, true)
 	return prefix, found
@@ -549,7 +609,11 @@ func (r *Router) matchingMountEntryByPath(ctx context.Context, path string, apiP
 
 // Route is used to route a given request
 func (r *Router) Route(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+	start := time.Now()
 	resp, _, _, err := r.routeCommon(ctx, req, false)
+	if r.routeMetrics != nil && !errors.Is(err, logical.ErrUnsupportedPath) {
+		r.routeMetrics.observe(req, resp, err, time.Since(start))
+	}
 	return resp, err
 }
 
@@ -568,12 +632,12 @@ func (r *Router) routeCommon(ctx context.Context, req *logical.Request, existenc
 	// Find the mount point
 	r.l.RLock()
 	adjustedPath := req.Path
-	mount, raw, ok := r.root.LongestPrefix(ns.Path + adjustedPath)
+	mount, raw, ok := r.root.LongestPrefix(namespaceRoutePath(ns, adjustedPath))
 	if !ok && !strings.HasSuffix(adjustedPath, "/") {
 		// Re-check for a backend by appending a slash. This lets "foo" mean
 		// "foo/" at the root level which is almost always what we want.
 		adjustedPath += "/"
-		mount, raw, ok = r.root.LongestPrefix(ns.Path + adjustedPath)
+		mount, raw, ok = r.root.LongestPrefix(namespaceRoutePath(ns, adjustedPath))
 	}
 	r.l.RUnlock()
 	if !ok {
@@ -615,7 +679,7 @@ func (r *Router) routeCommon(ctx context.Context, req *logical.Request, existenc
 
 	// Adjust the path to exclude the routing prefix
 	originalPath := req.Path
-	req.Path = strings.TrimPrefix(ns.Path+req.Path, mount)
+	req.Path = strings.TrimPrefix(namespaceRoutePath(ns, req.Path), mount)
 	req.MountPoint = mount
 	req.MountType = re.mountEntry.Type
 	req.SetMountRunningSha256(re.mountEntry.RunningSha256)
@@ -846,7 +910,7 @@ func (r *Router) RootPath(ctx context.Context, path string) bool {
 		return false
 	}
 
-	adjustedPath := ns.Path + path
+	adjustedPath := namespaceRoutePath(ns, path)
 
 	r.l.RLock()
 	mount, raw, ok := r.root.LongestPrefix(adjustedPath)
@@ -882,12 +946,28 @@ func (r *Router) RootPath(ctx context.Context, path string) bool {
 //  2. exact
 //  3. wildcard
 func (r *Router) LoginPath(ctx context.Context, path string) bool {
+	return r.specialPath(ctx, path, func(re *routeEntry) *loginPathsEntry {
+		return re.loginPaths.Load().(*loginPathsEntry)
+	})
+}
+
+// StreamingPath checks if the given path is one whose request body and
+// response are streamed. Paths are matched like in LoginPath.
+func (r *Router) StreamingPath(ctx context.Context, path string) bool {
+	return r.specialPath(ctx, path, func(re *routeEntry) *loginPathsEntry {
+		return re.streamingPaths.Load().(*loginPathsEntry)
+	})
+}
+
+// specialPath checks if the given path matches one of the special paths
+// returned by pathsFn for the backend of the path.
+func (r *Router) specialPath(ctx context.Context, path string, pathsFn func(*routeEntry) *loginPathsEntry) bool {
 	ns, err := namespace.FromContext(ctx)
 	if err != nil {
 		return false
 	}
 
-	adjustedPath := ns.Path + path
+	adjustedPath := namespaceRoutePath(ns, path)
 
 	r.l.RLock()
 	mount, raw, ok := r.root.LongestPrefix(adjustedPath)
@@ -900,8 +980,8 @@ func (r *Router) LoginPath(ctx context.Context, path string) bool {
 	// Trim to get remaining path
 	remain := strings.TrimPrefix(adjustedPath, mount)
 
-	// Check the loginPaths of this backend
-	pe := re.loginPaths.Load().(*loginPathsEntry)
+	// Check the special paths of this backend
+	pe := pathsFn(re)
 	match, raw, ok := pe.paths.LongestPrefix(remain)
 	if !ok && len(pe.wildcardPaths) == 0 {
 		// no match found
@@ -920,7 +1000,7 @@ func (r *Router) LoginPath(ctx context.Context, path string) bool {
 		}
 	}
 
-	// check Login Paths containing wildcards
+	// check special paths containing wildcards
 	reqPathParts := strings.Split(remain, "/")
 	for _, w := range pe.wildcardPaths {
 		if pathMatchesWildcardPath(reqPathParts, w.segments, w.isPrefix) {
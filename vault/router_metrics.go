@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// routeMetricsMaxMountsDefault is the number of mounts the route metrics
+	// are labeled with when not configured.
+	routeMetricsMaxMountsDefault = 100
+
+	// routeMetricsOtherMount is the mount label of the requests to the
+	// mounts over the cardinality cap.
+	routeMetricsOtherMount = "other"
+)
+
+// routeMetricsBuckets are the upper bounds, in milliseconds, of the buckets
+// of the request latency histograms. Slower requests fall in an implicit
+// last bucket.
+var routeMetricsBuckets = []float64{1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// routeMetrics records the latency of the requests routed to each mount,
+// labeled by mount, operation and response status. The number of mounts
+// used as labels is capped; requests to any other mount are recorded with
+// the "other" mount label.
+type routeMetrics struct {
+	sink      metricsutil.Metrics
+	maxMounts int
+
+	l          sync.Mutex
+	mounts     map[string]struct{}
+	histograms map[routeMetricsKey]*routeHistogram
+}
+
+type routeMetricsKey struct {
+	mount     string
+	operation logical.Operation
+	status    int
+}
+
+type routeHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+func newRouteMetrics(sink *metricsutil.ClusterMetricSink) *routeMetrics {
+	maxMounts := sink.TelemetryConsts.RouteMetricsMaxMounts
+	if maxMounts <= 0 {
+		maxMounts = routeMetricsMaxMountsDefault
+	}
+
+	return &routeMetrics{
+		sink:       sink,
+		maxMounts:  maxMounts,
+		mounts:     make(map[string]struct{}),
+		histograms: make(map[routeMetricsKey]*routeHistogram),
+	}
+}
+
+// observe records a request routed to the mount.
+func (m *routeMetrics) observe(req *logical.Request, resp *logical.Response, err error, elapsed time.Duration) {
+	status, _ := logical.RespondErrorCommon(req, resp, err)
+	if status == 0 {
+		status = 200
+		if resp == nil {
+			status = 204
+		}
+	}
+	ms := float64(elapsed) / float64(time.Millisecond)
+
+	m.l.Lock()
+	mount := req.MountPoint
+	if _, ok := m.mounts[mount]; !ok {
+		if len(m.mounts) < m.maxMounts {
+			m.mounts[mount] = struct{}{}
+		} else {
+			mount = routeMetricsOtherMount
+		}
+	}
+
+	key := routeMetricsKey{mount: mount, operation: req.Operation, status: status}
+	histogram, ok := m.histograms[key]
+	if !ok {
+		histogram = &routeHistogram{buckets: make([]uint64, len(routeMetricsBuckets)+1)}
+		m.histograms[key] = histogram
+	}
+	histogram.buckets[sort.SearchFloat64s(routeMetricsBuckets, ms)]++
+	histogram.count++
+	histogram.sum += ms
+	m.l.Unlock()
+
+	m.sink.AddSampleWithLabels([]string{"route", "request"}, float32(ms), []metricsutil.Label{
+		{Name: "mount", Value: mount},
+		{Name: "operation", Value: string(req.Operation)},
+		{Name: "status", Value: strconv.Itoa(status)},
+	})
+}
+
+// summary returns the request count, error count and latency percentiles of
+// each mount and operation.
+func (m *routeMetrics) summary() []map[string]interface{} {
+	type summaryKey struct {
+		mount     string
+		operation logical.Operation
+	}
+
+	m.l.Lock()
+	merged := make(map[summaryKey]*routeHistogram)
+	errorCounts := make(map[summaryKey]uint64)
+	statuses := make(map[summaryKey]map[string]uint64)
+	for key, histogram := range m.histograms {
+		sk := summaryKey{mount: key.mount, operation: key.operation}
+		total, ok := merged[sk]
+		if !ok {
+			total = &routeHistogram{buckets: make([]uint64, len(routeMetricsBuckets)+1)}
+			merged[sk] = total
+			statuses[sk] = make(map[string]uint64)
+		}
+		for i, count := range histogram.buckets {
+			total.buckets[i] += count
+		}
+		total.count += histogram.count
+		total.sum += histogram.sum
+		statuses[sk][strconv.Itoa(key.status)] += histogram.count
+		if key.status >= 400 {
+			errorCounts[sk] += histogram.count
+		}
+	}
+	m.l.Unlock()
+
+	keys := make([]summaryKey, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].mount != keys[j].mount {
+			return keys[i].mount < keys[j].mount
+		}
+		return keys[i].operation < keys[j].operation
+	})
+
+	ret := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		histogram := merged[key]
+		ret = append(ret, map[string]interface{}{
+			"mount":     key.mount,
+			"operation": string(key.operation),
+			"count":     histogram.count,
+			"errors":    errorCounts[key],
+			"statuses":  statuses[key],
+			"mean_ms":   histogram.sum / float64(histogram.count),
+			"p50_ms":    histogram.quantile(0.5),
+			"p95_ms":    histogram.quantile(0.95),
+			"p99_ms":    histogram.quantile(0.99),
+		})
+	}
+	return ret
+}
+
+// quantile returns the upper bound of the bucket the quantile falls in. The
+// requests slower than the last bucket are reported as its upper bound.
+func (h *routeHistogram) quantile(q float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(h.count))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var cumulative uint64
+	for i, count := range h.buckets {
+		cumulative += count
+		if cumulative >= rank {
+			if i < len(routeMetricsBuckets) {
+				return routeMetricsBuckets[i]
+			}
+			break
+		}
+	}
+	return routeMetricsBuckets[len(routeMetricsBuckets)-1]
+}
@@ -7,8 +7,10 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/metricsutil"
 	"github.com/hashicorp/vault/helper/namespace"
 	"github.com/hashicorp/vault/sdk/logical"
 )
@@ -450,6 +452,50 @@ func TestRouter_LoginPath(t *testing.T) {
 	}
 }
 
+func TestRouter_StreamingPath(t *testing.T) {
+	r := NewRouter()
+	_, barrier, _ := mockBarrier(t)
+	view := NewBarrierView(barrier, "logical/")
+
+	meUUID, err := uuid.GenerateUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &NoopBackend{
+		Streaming: []string{
+			"download",
+			"upload/*",
+			"+/stream",
+		},
+	}
+	err = r.Mount(n, "prod/aws/", &MountEntry{UUID: meUUID, Accessor: "awsaccessor", NamespaceID: namespace.RootNamespaceID, namespace: namespace.RootNamespace}, view)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	tcases := []struct {
+		path   string
+		expect bool
+	}{
+		{"random", false},
+		{"prod/aws/foo", false},
+		{"prod/aws/download", true},
+		{"prod/aws/download/", false},
+		{"prod/aws/upload", false},
+		{"prod/aws/upload/", true},
+		{"prod/aws/upload/foo", true},
+		{"prod/aws/foo/stream", true},
+		{"prod/aws/foo/stream/", false},
+		{"prod/invalid/download", false},
+	}
+
+	for _, tc := range tcases {
+		if out := r.StreamingPath(namespace.RootContext(nil), tc.path); out != tc.expect {
+			t.Fatalf("bad: path: %s expect: %v got %v", tc.path, tc.expect, out)
+		}
+	}
+}
+
 func TestRouter_Taint(t *testing.T) {
 	r := NewRouter()
 	_, barrier, _ := mockBarrier(t)
@@ -631,3 +677,66 @@ func TestParseUnauthenticatedPaths_Error(t *testing.T) {
 		}
 	}
 }
+
+func TestRouter_RouteMetrics(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	for _, req := range []*logical.Request{
+		{Operation: logical.UpdateOperation, Path: "secret/foo", Data: map[string]interface{}{"bar": "baz"}},
+		{Operation: logical.ReadOperation, Path: "secret/foo"},
+		{Operation: logical.ReadOperation, Path: "secret/foo"},
+	} {
+		req.ClientToken = root
+		if _, err := c.HandleRequest(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := c.HandleRequest(ctx, &logical.Request{
+		Operation:   logical.ReadOperation,
+		Path:        "sys/metrics/routes",
+		ClientToken: root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, route := range resp.Data["routes"].([]map[string]interface{}) {
+		if route["mount"] != "secret/" || route["operation"] != string(logical.ReadOperation) {
+			continue
+		}
+		found = true
+		if route["count"].(uint64) != 2 || route["errors"].(uint64) != 0 {
+			t.Fatalf("bad: %#v", route)
+		}
+		if route["statuses"].(map[string]uint64)["200"] != 2 {
+			t.Fatalf("bad: %#v", route)
+		}
+	}
+	if !found {
+		t.Fatalf("no metrics for secret/ reads: %#v", resp.Data)
+	}
+}
+
+func TestRouteMetrics_MaxMounts(t *testing.T) {
+	sink := metricsutil.BlackholeSink()
+	sink.TelemetryConsts.RouteMetricsMaxMounts = 1
+	m := newRouteMetrics(sink)
+
+	for _, mount := range []string{"secret/", "kv/", "pki/"} {
+		m.observe(&logical.Request{Operation: logical.ReadOperation, MountPoint: mount}, nil, logical.ErrPermissionDenied, time.Millisecond)
+	}
+
+	summary := m.summary()
+	if len(summary) != 2 {
+		t.Fatalf("bad: %#v", summary)
+	}
+	if summary[0]["mount"] != routeMetricsOtherMount || summary[0]["count"].(uint64) != 2 || summary[0]["errors"].(uint64) != 2 {
+		t.Fatalf("bad: %#v", summary[0])
+	}
+	if summary[1]["mount"] != "secret/" || summary[1]["p99_ms"].(float64) != 1 {
+		t.Fatalf("bad: %#v", summary[1])
+	}
+}
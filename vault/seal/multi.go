@@ -0,0 +1,365 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package seal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	wrapping "github.com/hashicorp/go-kms-wrapping/v2"
+	"github.com/hashicorp/go-multierror"
+	"google.golang.org/protobuf/proto"
+)
+
+// multiSealMechanism is the mechanism of the blobs encrypted by a
+// MultiAccess, to tell them apart from the blobs encrypted by a single seal
+// before other seals were configured.
+const multiSealMechanism uint64 = 0x6d756c7469
+
+// SealWrapper is a seal taking part in a MultiAccess.
+type SealWrapper struct {
+	Access
+
+	// Name identifies the seal within the blobs it encrypted, it must not
+	// change once blobs were encrypted with the seal.
+	Name string
+
+	// Priority orders the seals, decryption is attempted with the seal with
+	// the lowest priority first.
+	Priority int
+}
+
+// SealBackendStatus describes the health of a seal taking part in a
+// MultiAccess.
+type SealBackendStatus struct {
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Priority  int       `json:"priority"`
+	Healthy   bool      `json:"healthy"`
+	LastSeen  time.Time `json:"last_seen"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+type multiSealBlob struct {
+	Name string `json:"name"`
+	Blob []byte `json:"blob"`
+}
+
+type sealHealth struct {
+	healthy   bool
+	lastSeen  time.Time
+	lastError error
+}
+
+// MultiAccess is a seal access encrypting values with several seals, so that
+// they can be decrypted as long as one of the seals is available.
+type MultiAccess struct {
+	logger hclog.Logger
+	seals  []*SealWrapper
+
+	l      sync.RWMutex
+	health map[string]*sealHealth
+}
+
+var _ Access = (*MultiAccess)(nil)
+
+// NewMultiAccess returns a seal access encrypting values with each of the
+// given seals.
+func NewMultiAccess(logger hclog.Logger, seals []*SealWrapper) (*MultiAccess, error) {
+	if len(seals) == 0 {
+		return nil, errors.New("no seals given")
+	}
+
+	sorted := make([]*SealWrapper, len(seals))
+	copy(sorted, seals)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+
+	health := make(map[string]*sealHealth, len(sorted))
+	for _, s := range sorted {
+		if s.Name == "" {
+			return nil, errors.New("seals must be named")
+		}
+		if _, ok := health[s.Name]; ok {
+			return nil, fmt.Errorf("duplicate seal name %q", s.Name)
+		}
+		health[s.Name] = &sealHealth{healthy: true, lastSeen: time.Now()}
+	}
+
+	return &MultiAccess{
+		logger: logger,
+		seals:  sorted,
+		health: health,
+	}, nil
+}
+
+// Seals returns the seals of the access, ordered by priority.
+func (m *MultiAccess) Seals() []*SealWrapper {
+	return m.seals
+}
+
+// KeyId returns the key IDs of all the seals. Blobs which weren't encrypted
+// with every seal have a different key ID, so that the stored keys are
+// re-encrypted when upgraded.
+func (m *MultiAccess) KeyId(ctx context.Context) (string, error) {
+	ids := make([]string, 0, len(m.seals))
+	for _, s := range m.seals {
+		id, err := s.KeyId(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get key ID of seal %q: %w", s.Name, err)
+		}
+		ids = append(ids, s.Name+":"+id)
+	}
+	return strings.Join(ids, ","), nil
+}
+
+func (m *MultiAccess) SetConfig(ctx context.Context, options ...wrapping.Option) (*wrapping.WrapperConfig, error) {
+	return nil, errors.New("multiple seals can't be configured at once")
+}
+
+// GetWrapper returns the wrapper of the seal with the lowest priority.
+func (m *MultiAccess) GetWrapper() wrapping.Wrapper {
+	return m.seals[0].GetWrapper()
+}
+
+// Type returns the type of the seal with the lowest priority, which is the
+// type of the barrier.
+func (m *MultiAccess) Type(ctx context.Context) (wrapping.WrapperType, error) {
+	return m.seals[0].Type(ctx)
+}
+
+func (m *MultiAccess) Init(ctx context.Context, options ...wrapping.Option) error {
+	for _, s := range m.seals {
+		if err := s.Init(ctx, options...); err != nil {
+			return fmt.Errorf("failed to initialize seal %q: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *MultiAccess) Finalize(ctx context.Context, options ...wrapping.Option) error {
+	var retErr *multierror.Error
+	for _, s := range m.seals {
+		if err := s.Finalize(ctx, options...); err != nil {
+			retErr = multierror.Append(retErr, fmt.Errorf("failed to finalize seal %q: %w", s.Name, err))
+		}
+	}
+	return retErr.ErrorOrNil()
+}
+
+// Encrypt encrypts the plaintext with every available seal. It only fails if
+// none of the seals are available.
+func (m *MultiAccess) Encrypt(ctx context.Context, plaintext []byte, options ...wrapping.Option) (*wrapping.BlobInfo, error) {
+	var blobs []multiSealBlob
+	var keyIDs []string
+	var retErr *multierror.Error
+	for _, s := range m.seals {
+		blob, err := s.Encrypt(ctx, plaintext, options...)
+		if err == nil {
+			var data []byte
+			data, err = proto.Marshal(blob)
+			if err == nil {
+				blobs = append(blobs, multiSealBlob{Name: s.Name, Blob: data})
+				keyID := ""
+				if blob.KeyInfo != nil {
+					keyID = blob.KeyInfo.KeyId
+				}
+				keyIDs = append(keyIDs, s.Name+":"+keyID)
+			}
+		}
+		m.recordHealth(s, err)
+		if err != nil {
+			retErr = multierror.Append(retErr, fmt.Errorf("seal %q: %w", s.Name, err))
+		}
+	}
+	if len(blobs) == 0 {
+		return nil, fmt.Errorf("failed to encrypt with any seal: %w", retErr)
+	}
+	if retErr != nil {
+		m.logger.Warn("failed to encrypt with some seals, the value is only encrypted with the available seals", "error", retErr)
+	}
+
+	ciphertext, err := json.Marshal(blobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wrapping.BlobInfo{
+		Ciphertext: ciphertext,
+		KeyInfo: &wrapping.KeyInfo{
+			Mechanism: multiSealMechanism,
+			KeyId:     strings.Join(keyIDs, ","),
+		},
+	}, nil
+}
+
+// Decrypt decrypts the blob with the first available seal the blob was
+// encrypted with, in priority order. Blobs encrypted with a single seal are
+// decrypted with the first seal which succeeds to.
+func (m *MultiAccess) Decrypt(ctx context.Context, data *wrapping.BlobInfo, options ...wrapping.Option) ([]byte, error) {
+	if data.KeyInfo == nil || data.KeyInfo.Mechanism != multiSealMechanism {
+		return m.decryptSingle(ctx, data, options...)
+	}
+
+	var blobs []multiSealBlob
+	if err := json.Unmarshal(data.Ciphertext, &blobs); err != nil {
+		return nil, fmt.Errorf("failed to decode multi-seal blob: %w", err)
+	}
+	byName := make(map[string][]byte, len(blobs))
+	for _, b := range blobs {
+		byName[b.Name] = b.Blob
+	}
+
+	var retErr *multierror.Error
+	for _, s := range m.seals {
+		raw, ok := byName[s.Name]
+		if !ok {
+			continue
+		}
+
+		blob := new(wrapping.BlobInfo)
+		if err := proto.Unmarshal(raw, blob); err != nil {
+			retErr = multierror.Append(retErr, fmt.Errorf("seal %q: failed to decode blob: %w", s.Name, err))
+			continue
+		}
+
+		pt, err := s.Decrypt(ctx, blob, options...)
+		m.recordHealth(s, err)
+		if err == nil {
+			return pt, nil
+		}
+		retErr = multierror.Append(retErr, fmt.Errorf("seal %q: %w", s.Name, err))
+	}
+	if retErr == nil {
+		return nil, errors.New("value was not encrypted with any of the configured seals")
+	}
+	return nil, fmt.Errorf("failed to decrypt with any seal: %w", retErr)
+}
+
+func (m *MultiAccess) decryptSingle(ctx context.Context, data *wrapping.BlobInfo, options ...wrapping.Option) ([]byte, error) {
+	var retErr *multierror.Error
+	for _, s := range m.seals {
+		pt, err := s.Decrypt(ctx, data, options...)
+		if err == nil {
+			return pt, nil
+		}
+		retErr = multierror.Append(retErr, fmt.Errorf("seal %q: %w", s.Name, err))
+	}
+	return nil, fmt.Errorf("failed to decrypt with any seal: %w", retErr)
+}
+
+// CheckHealth tests each seal by encrypting and decrypting a value, and
+// returns the seals which became healthy again. The returned error holds the
+// failures of the unhealthy seals, use Healthy to tell whether the access is
+// still usable.
+func (m *MultiAccess) CheckHealth(ctx context.Context, value []byte) ([]string, error) {
+	var recovered []string
+	var retErr *multierror.Error
+	for _, s := range m.seals {
+		wasHealthy := m.isHealthy(s.Name)
+
+		err := func() error {
+			blob, err := s.Encrypt(ctx, value, nil)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt: %w", err)
+			}
+			pt, err := s.Decrypt(ctx, blob, nil)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt: %w", err)
+			}
+			if string(pt) != string(value) {
+				return errors.New("test value failed to decrypt to expected value")
+			}
+			return nil
+		}()
+		m.recordHealth(s, err)
+		if err != nil {
+			retErr = multierror.Append(retErr, fmt.Errorf("seal %q: %w", s.Name, err))
+			continue
+		}
+		if !wasHealthy {
+			recovered = append(recovered, s.Name)
+		}
+	}
+	return recovered, retErr.ErrorOrNil()
+}
+
+// Status returns the health of each seal, ordered by priority.
+func (m *MultiAccess) Status(ctx context.Context) []SealBackendStatus {
+	m.l.RLock()
+	defer m.l.RUnlock()
+
+	statuses := make([]SealBackendStatus, 0, len(m.seals))
+	for _, s := range m.seals {
+		h := m.health[s.Name]
+		status := SealBackendStatus{
+			Name:     s.Name,
+			Priority: s.Priority,
+			Healthy:  h.healthy,
+			LastSeen: h.lastSeen,
+		}
+		if typ, err := s.Type(ctx); err == nil {
+			status.Type = typ.String()
+		}
+		if h.lastError != nil {
+			status.LastError = h.lastError.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Healthy returns true if at least one of the seals passed its last health
+// test, as values can then still be encrypted and decrypted.
+func (m *MultiAccess) Healthy() bool {
+	m.l.RLock()
+	defer m.l.RUnlock()
+
+	for _, h := range m.health {
+		if h.healthy {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MultiAccess) isHealthy(name string) bool {
+	m.l.RLock()
+	defer m.l.RUnlock()
+
+	return m.health[name].healthy
+}
+
+func (m *MultiAccess) recordHealth(s *SealWrapper, err error) {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	h := m.health[s.Name]
+	if err != nil {
+		if h.healthy {
+			m.logger.Warn("seal is unavailable", "seal", s.Name, "error", err)
+		}
+		h.healthy = false
+		h.lastError = err
+		metrics.SetGaugeWithLabels([]string{"seal", "healthy"}, 0, []metrics.Label{{Name: "seal", Value: s.Name}})
+		return
+	}
+
+	if !h.healthy {
+		m.logger.Info("seal is available again", "seal", s.Name, "downtime", time.Since(h.lastSeen).String())
+	}
+	h.healthy = true
+	h.lastSeen = time.Now()
+	h.lastError = nil
+	metrics.SetGaugeWithLabels([]string{"seal", "healthy"}, 1, []metrics.Label{{Name: "seal", Value: s.Name}})
+}
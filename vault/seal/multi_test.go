@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package seal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestMultiAccess_Failover(t *testing.T) {
+	ctx := context.Background()
+
+	primary, setPrimaryErr := NewToggleableTestSeal(&TestSealOpts{Secret: []byte("primary")})
+	secondary, setSecondaryErr := NewToggleableTestSeal(&TestSealOpts{Secret: []byte("secondary")})
+
+	multi, err := NewMultiAccess(hclog.NewNullLogger(), []*SealWrapper{
+		{Access: secondary, Name: "secondary", Priority: 2},
+		{Access: primary, Name: "primary", Priority: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if multi.Seals()[0].Name != "primary" {
+		t.Fatalf("expected seals to be ordered by priority, got %q first", multi.Seals()[0].Name)
+	}
+
+	input := []byte("test")
+	blob, err := multi.Encrypt(ctx, input, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Either seal is enough to decrypt the value
+	setPrimaryErr(errors.New("primary unavailable"))
+	output, err := multi.Decrypt(ctx, blob, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(input, output) {
+		t.Fatalf("expected %q, got %q", input, output)
+	}
+
+	statuses := multi.Status(ctx)
+	if statuses[0].Healthy || statuses[0].LastError == "" {
+		t.Fatalf("expected primary seal to be unhealthy: %#v", statuses[0])
+	}
+	if !statuses[1].Healthy {
+		t.Fatalf("expected secondary seal to be healthy: %#v", statuses[1])
+	}
+
+	// Values encrypted while a seal is unavailable are still usable
+	partial, err := multi.Encrypt(ctx, input, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setPrimaryErr(nil)
+	setSecondaryErr(errors.New("secondary unavailable"))
+	if _, err := multi.Decrypt(ctx, partial, nil); err == nil {
+		t.Fatal("expected decryption to fail without any seal the value was encrypted with")
+	}
+	if _, err := multi.Decrypt(ctx, blob, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if !multi.Healthy() {
+		t.Fatal("expected the multi-seal to be healthy while one seal is available")
+	}
+
+	setSecondaryErr(nil)
+	recovered, err := multi.CheckHealth(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recovered) != 1 || recovered[0] != "secondary" {
+		t.Fatalf("expected the secondary seal to have recovered, got %v", recovered)
+	}
+}
+
+func TestMultiAccess_DecryptSingleSealBlob(t *testing.T) {
+	ctx := context.Background()
+
+	primary, _ := NewToggleableTestSeal(&TestSealOpts{Secret: []byte("primary")})
+	secondary, setSecondaryErr := NewToggleableTestSeal(&TestSealOpts{Secret: []byte("secondary")})
+	setSecondaryErr(errors.New("secondary unavailable"))
+
+	input := []byte("test")
+	blob, err := primary.Encrypt(ctx, input, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	multi, err := NewMultiAccess(hclog.NewNullLogger(), []*SealWrapper{
+		{Access: secondary, Name: "secondary"},
+		{Access: primary, Name: "primary"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := multi.Decrypt(ctx, blob, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(input, output) {
+		t.Fatalf("expected %q, got %q", input, output)
+	}
+}
+
+func TestNewMultiAccess_DuplicateNames(t *testing.T) {
+	a, _ := NewToggleableTestSeal(nil)
+	b, _ := NewToggleableTestSeal(nil)
+
+	_, err := NewMultiAccess(hclog.NewNullLogger(), []*SealWrapper{
+		{Access: a, Name: "kms"},
+		{Access: b, Name: "kms"},
+	})
+	if err == nil {
+		t.Fatal("expected an error for duplicate seal names")
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package pkcs11 implements a seal wrapper protecting the root key with a key
+// stored in an HSM reachable through a PKCS#11 library.
+package pkcs11
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+)
+
+// Environment variables overriding the values of the seal configuration.
+const (
+	EnvHSMLib         = "VAULT_HSM_LIB"
+	EnvHSMSlot        = "VAULT_HSM_SLOT"
+	EnvHSMTokenLabel  = "VAULT_HSM_TOKEN_LABEL"
+	EnvHSMPin         = "VAULT_HSM_PIN"
+	EnvHSMKeyLabel    = "VAULT_HSM_KEY_LABEL"
+	EnvHSMKeyID       = "VAULT_HSM_KEY_ID"
+	EnvHSMMechanism   = "VAULT_HSM_MECHANISM"
+	EnvHSMGenerateKey = "VAULT_HSM_GENERATE_KEY"
+	EnvHSMRSAKeyBits  = "VAULT_HSM_RSA_KEY_BITS"
+)
+
+// Mechanisms supported to wrap the data encryption keys.
+const (
+	MechanismRSAPKCSOAEP uint = 0x0009
+	MechanismAESGCM      uint = 0x1087
+)
+
+const defaultRSAKeyBits = 2048
+
+type config struct {
+	lib         string
+	slot        *uint
+	tokenLabel  string
+	pin         string
+	keyLabel    string
+	keyID       []byte
+	mechanism   uint
+	generateKey bool
+	rsaKeyBits  int
+}
+
+func parseConfig(m map[string]string) (*config, error) {
+	get := func(key, env string) string {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+		return m[key]
+	}
+
+	c := &config{
+		lib:        get("lib", EnvHSMLib),
+		tokenLabel: get("token_label", EnvHSMTokenLabel),
+		pin:        get("pin", EnvHSMPin),
+		keyLabel:   get("key_label", EnvHSMKeyLabel),
+		rsaKeyBits: defaultRSAKeyBits,
+	}
+
+	switch {
+	case c.lib == "":
+		return nil, errors.New("'lib' must be set to the path of the PKCS#11 library")
+	case c.pin == "":
+		return nil, errors.New("'pin' must be set")
+	case c.keyLabel == "":
+		return nil, errors.New("'key_label' must be set")
+	}
+
+	if v := get("slot", EnvHSMSlot); v != "" {
+		slot, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'slot': %w", err)
+		}
+		s := uint(slot)
+		c.slot = &s
+	}
+	if c.slot == nil && c.tokenLabel == "" {
+		return nil, errors.New("either 'slot' or 'token_label' must be set")
+	}
+
+	if v := get("key_id", EnvHSMKeyID); v != "" {
+		id, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(v), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'key_id' as a hexadecimal string: %w", err)
+		}
+		c.keyID = id
+	}
+
+	if v := get("mechanism", EnvHSMMechanism); v != "" {
+		mechanism, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'mechanism': %w", err)
+		}
+		switch uint(mechanism) {
+		case MechanismAESGCM, MechanismRSAPKCSOAEP:
+			c.mechanism = uint(mechanism)
+		default:
+			return nil, fmt.Errorf("unsupported mechanism %#x, only CKM_AES_GCM (0x1087) and CKM_RSA_PKCS_OAEP (0x0009) are supported", mechanism)
+		}
+	}
+
+	if v := get("generate_key", EnvHSMGenerateKey); v != "" {
+		generate, err := parseutil.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'generate_key': %w", err)
+		}
+		c.generateKey = generate
+	}
+
+	if v := get("rsa_key_bits", EnvHSMRSAKeyBits); v != "" {
+		bits, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'rsa_key_bits': %w", err)
+		}
+		if bits < 2048 {
+			return nil, errors.New("'rsa_key_bits' must be at least 2048")
+		}
+		c.rsaKeyBits = bits
+	}
+
+	return c, nil
+}
+
+// metadata returns the configuration reported by SetConfig, the PIN is left
+// out.
+func (c *config) metadata() map[string]string {
+	m := map[string]string{
+		"lib":          c.lib,
+		"key_label":    c.keyLabel,
+		"generate_key": strconv.FormatBool(c.generateKey),
+	}
+	if c.slot != nil {
+		m["slot"] = strconv.FormatUint(uint64(*c.slot), 10)
+	}
+	if c.tokenLabel != "" {
+		m["token_label"] = c.tokenLabel
+	}
+	if len(c.keyID) > 0 {
+		m["key_id"] = "0x" + hex.EncodeToString(c.keyID)
+	}
+	if c.mechanism != 0 {
+		m["mechanism"] = fmt.Sprintf("%#04x", c.mechanism)
+	}
+	return m
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := parseConfig(map[string]string{
+		"lib":          "/usr/lib/softhsm/libsofthsm2.so",
+		"slot":         "0x2000000000000001",
+		"pin":          "1234",
+		"key_label":    "vault-hsm-key",
+		"key_id":       "0x3334",
+		"mechanism":    "0x1087",
+		"generate_key": "true",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.slot == nil || *c.slot != 0x2000000000000001 {
+		t.Fatalf("unexpected slot: %v", c.slot)
+	}
+	if !bytes.Equal(c.keyID, []byte{0x33, 0x34}) {
+		t.Fatalf("unexpected key ID: %x", c.keyID)
+	}
+	if c.mechanism != MechanismAESGCM {
+		t.Fatalf("unexpected mechanism: %#x", c.mechanism)
+	}
+	if !c.generateKey {
+		t.Fatal("expected key generation to be enabled")
+	}
+	if _, ok := c.metadata()["pin"]; ok {
+		t.Fatal("expected the PIN to be left out of the metadata")
+	}
+}
+
+func TestParseConfig_Env(t *testing.T) {
+	t.Setenv(EnvHSMKeyLabel, "from-env")
+
+	c, err := parseConfig(map[string]string{
+		"lib":         "/usr/lib/softhsm/libsofthsm2.so",
+		"token_label": "vault",
+		"pin":         "1234",
+		"key_label":   "from-config",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.keyLabel != "from-env" {
+		t.Fatalf("expected the environment to take precedence, got %q", c.keyLabel)
+	}
+}
+
+func TestParseConfig_Invalid(t *testing.T) {
+	base := func() map[string]string {
+		return map[string]string{
+			"lib":       "/usr/lib/softhsm/libsofthsm2.so",
+			"slot":      "0",
+			"pin":       "1234",
+			"key_label": "vault-hsm-key",
+		}
+	}
+
+	cases := map[string]func(map[string]string){
+		"missing lib":           func(m map[string]string) { delete(m, "lib") },
+		"missing slot":          func(m map[string]string) { delete(m, "slot") },
+		"missing pin":           func(m map[string]string) { delete(m, "pin") },
+		"unsupported mechanism": func(m map[string]string) { m["mechanism"] = "0x1082" },
+		"invalid key id":        func(m map[string]string) { m["key_id"] = "0xzz" },
+		"small rsa key":         func(m map[string]string) { m["rsa_key_bits"] = "1024" },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := base()
+			mutate(m)
+			if _, err := parseConfig(m); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build cgo
+
+package pkcs11
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	p11 "github.com/miekg/pkcs11"
+)
+
+const gcmIVSize = 12
+
+type hsmKey struct {
+	mechanism uint
+
+	// encrypt and decrypt are the same object for AES keys, and the public
+	// and private keys of the pair for RSA keys.
+	encrypt p11.ObjectHandle
+	decrypt p11.ObjectHandle
+}
+
+// hsm is a logged in session with the HSM. PKCS#11 sessions may not be used
+// concurrently, so every operation holds the lock.
+type hsm struct {
+	config *config
+
+	l       sync.Mutex
+	ctx     *p11.Ctx
+	session p11.SessionHandle
+	keys    map[string]*hsmKey
+}
+
+func openHSM(c *config) (*hsm, error) {
+	ctx := p11.New(c.lib)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 library %q", c.lib)
+	}
+	if err := ctx.Initialize(); err != nil && !isError(err, p11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("failed to initialize PKCS#11 library: %w", err)
+	}
+
+	h := &hsm{
+		config: c,
+		ctx:    ctx,
+		keys:   make(map[string]*hsmKey),
+	}
+	if err := h.login(); err != nil {
+		ctx.Finalize()
+		ctx.Destroy()
+		return nil, err
+	}
+
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	key, err := h.findKey(c.keyLabel, c.keyID)
+	if err == nil && key == nil {
+		if !c.generateKey {
+			err = fmt.Errorf("no key labeled %q found, and key generation is disabled", c.keyLabel)
+		} else {
+			key, err = h.generateKey()
+		}
+	}
+	if err != nil {
+		h.closeLocked()
+		return nil, err
+	}
+	h.keys[c.keyLabel] = key
+
+	return h, nil
+}
+
+func (h *hsm) login() error {
+	slot, err := h.findSlot()
+	if err != nil {
+		return err
+	}
+
+	session, err := h.ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION|p11.CKF_RW_SESSION)
+	if err != nil {
+		return fmt.Errorf("failed to open session on slot %d: %w", slot, err)
+	}
+	if err := h.ctx.Login(session, p11.CKU_USER, h.config.pin); err != nil && !isError(err, p11.CKR_USER_ALREADY_LOGGED_IN) {
+		h.ctx.CloseSession(session)
+		return fmt.Errorf("failed to log in to slot %d: %w", slot, err)
+	}
+	h.session = session
+	return nil
+}
+
+func (h *hsm) findSlot() (uint, error) {
+	if h.config.slot != nil {
+		return *h.config.slot, nil
+	}
+
+	slots, err := h.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list slots: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := h.ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get token info of slot %d: %w", slot, err)
+		}
+		if info.Label == h.config.tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("no token labeled %q found", h.config.tokenLabel)
+}
+
+// findKey returns the key with the given label, or nil if there is none. The
+// mechanism is chosen from the type of the key unless one is configured.
+func (h *hsm) findKey(label string, id []byte) (*hsmKey, error) {
+	mechanism := h.config.mechanism
+
+	if mechanism == 0 || mechanism == MechanismAESGCM {
+		secret, err := h.findObject(p11.CKO_SECRET_KEY, label, id)
+		if err != nil {
+			return nil, err
+		}
+		if secret != nil {
+			return &hsmKey{mechanism: MechanismAESGCM, encrypt: *secret, decrypt: *secret}, nil
+		}
+	}
+
+	if mechanism == 0 || mechanism == MechanismRSAPKCSOAEP {
+		private, err := h.findObject(p11.CKO_PRIVATE_KEY, label, id)
+		if err != nil {
+			return nil, err
+		}
+		if private != nil {
+			public, err := h.findObject(p11.CKO_PUBLIC_KEY, label, id)
+			if err != nil {
+				return nil, err
+			}
+			if public == nil {
+				return nil, fmt.Errorf("no public key labeled %q found for the private key", label)
+			}
+			return &hsmKey{mechanism: MechanismRSAPKCSOAEP, encrypt: *public, decrypt: *private}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (h *hsm) findObject(class uint, label string, id []byte) (*p11.ObjectHandle, error) {
+	template := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, class),
+		p11.NewAttribute(p11.CKA_LABEL, label),
+	}
+	if len(id) > 0 {
+		template = append(template, p11.NewAttribute(p11.CKA_ID, id))
+	}
+
+	if err := h.ctx.FindObjectsInit(h.session, template); err != nil {
+		return nil, fmt.Errorf("failed to search for key %q: %w", label, err)
+	}
+	objects, _, err := h.ctx.FindObjects(h.session, 2)
+	if finalErr := h.ctx.FindObjectsFinal(h.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for key %q: %w", label, err)
+	}
+
+	switch len(objects) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &objects[0], nil
+	default:
+		return nil, fmt.Errorf("more than one key labeled %q found, set 'key_id' to select one", label)
+	}
+}
+
+// generateKey generates a non-extractable key on the token, an AES key unless
+// the RSA mechanism is configured.
+func (h *hsm) generateKey() (*hsmKey, error) {
+	common := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_TOKEN, true),
+		p11.NewAttribute(p11.CKA_LABEL, h.config.keyLabel),
+	}
+	if len(h.config.keyID) > 0 {
+		common = append(common, p11.NewAttribute(p11.CKA_ID, h.config.keyID))
+	}
+
+	if h.config.mechanism == MechanismRSAPKCSOAEP {
+		public := append([]*p11.Attribute{
+			p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PUBLIC_KEY),
+			p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_RSA),
+			p11.NewAttribute(p11.CKA_ENCRYPT, true),
+			p11.NewAttribute(p11.CKA_MODULUS_BITS, h.config.rsaKeyBits),
+			p11.NewAttribute(p11.CKA_PUBLIC_EXPONENT, []byte{1, 0, 1}),
+		}, common...)
+		private := append([]*p11.Attribute{
+			p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PRIVATE_KEY),
+			p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_RSA),
+			p11.NewAttribute(p11.CKA_DECRYPT, true),
+			p11.NewAttribute(p11.CKA_PRIVATE, true),
+			p11.NewAttribute(p11.CKA_SENSITIVE, true),
+			p11.NewAttribute(p11.CKA_EXTRACTABLE, false),
+		}, common...)
+
+		pub, priv, err := h.ctx.GenerateKeyPair(h.session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_RSA_PKCS_KEY_PAIR_GEN, nil)}, public, private)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate RSA key pair: %w", err)
+		}
+		return &hsmKey{mechanism: MechanismRSAPKCSOAEP, encrypt: pub, decrypt: priv}, nil
+	}
+
+	secret := append([]*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_SECRET_KEY),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_AES),
+		p11.NewAttribute(p11.CKA_VALUE_LEN, 32),
+		p11.NewAttribute(p11.CKA_ENCRYPT, true),
+		p11.NewAttribute(p11.CKA_DECRYPT, true),
+		p11.NewAttribute(p11.CKA_PRIVATE, true),
+		p11.NewAttribute(p11.CKA_SENSITIVE, true),
+		p11.NewAttribute(p11.CKA_EXTRACTABLE, false),
+	}, common...)
+
+	key, err := h.ctx.GenerateKey(h.session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_AES_KEY_GEN, nil)}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate AES key: %w", err)
+	}
+	return &hsmKey{mechanism: MechanismAESGCM, encrypt: key, decrypt: key}, nil
+}
+
+func (h *hsm) wrapKey(plaintext []byte) (uint, []byte, error) {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	key := h.keys[h.config.keyLabel]
+
+	switch key.mechanism {
+	case MechanismAESGCM:
+		iv := make([]byte, gcmIVSize)
+		if _, err := rand.Read(iv); err != nil {
+			return 0, nil, err
+		}
+		params := p11.NewGCMParams(iv, nil, 128)
+		defer params.Free()
+
+		if err := h.ctx.EncryptInit(h.session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_AES_GCM, params)}, key.encrypt); err != nil {
+			return 0, nil, err
+		}
+		ciphertext, err := h.ctx.Encrypt(h.session, plaintext)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		// Some HSMs ignore the given IV and use their own
+		if actual := params.IV(); len(actual) == gcmIVSize {
+			iv = actual
+		}
+		return key.mechanism, append(iv, ciphertext...), nil
+
+	case MechanismRSAPKCSOAEP:
+		if err := h.ctx.EncryptInit(h.session, []*p11.Mechanism{oaepMechanism()}, key.encrypt); err != nil {
+			return 0, nil, err
+		}
+		ciphertext, err := h.ctx.Encrypt(h.session, plaintext)
+		if err != nil {
+			return 0, nil, err
+		}
+		return key.mechanism, ciphertext, nil
+	}
+
+	return 0, nil, fmt.Errorf("unsupported mechanism %#x", key.mechanism)
+}
+
+func (h *hsm) unwrapKey(label string, mechanism uint, ciphertext []byte) ([]byte, error) {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	key, ok := h.keys[label]
+	if !ok {
+		var err error
+		key, err = h.findKey(label, nil)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			return nil, fmt.Errorf("no key labeled %q found", label)
+		}
+		h.keys[label] = key
+	}
+	if mechanism != key.mechanism {
+		return nil, fmt.Errorf("value was encrypted with mechanism %#x, but key %q uses mechanism %#x", mechanism, label, key.mechanism)
+	}
+
+	var m *p11.Mechanism
+	switch mechanism {
+	case MechanismAESGCM:
+		if len(ciphertext) < gcmIVSize {
+			return nil, errors.New("encrypted key is too short")
+		}
+		params := p11.NewGCMParams(ciphertext[:gcmIVSize], nil, 128)
+		defer params.Free()
+		m = p11.NewMechanism(p11.CKM_AES_GCM, params)
+		ciphertext = ciphertext[gcmIVSize:]
+
+	case MechanismRSAPKCSOAEP:
+		m = oaepMechanism()
+
+	default:
+		return nil, fmt.Errorf("unsupported mechanism %#x", mechanism)
+	}
+
+	if err := h.ctx.DecryptInit(h.session, []*p11.Mechanism{m}, key.decrypt); err != nil {
+		return nil, err
+	}
+	return h.ctx.Decrypt(h.session, ciphertext)
+}
+
+func (h *hsm) close() error {
+	h.l.Lock()
+	defer h.l.Unlock()
+
+	return h.closeLocked()
+}
+
+func (h *hsm) closeLocked() error {
+	var retErr error
+	if err := h.ctx.Logout(h.session); err != nil && !isError(err, p11.CKR_USER_NOT_LOGGED_IN) {
+		retErr = fmt.Errorf("failed to log out: %w", err)
+	}
+	if err := h.ctx.CloseSession(h.session); err != nil && retErr == nil {
+		retErr = fmt.Errorf("failed to close session: %w", err)
+	}
+	if err := h.ctx.Finalize(); err != nil && retErr == nil {
+		retErr = fmt.Errorf("failed to finalize PKCS#11 library: %w", err)
+	}
+	h.ctx.Destroy()
+	return retErr
+}
+
+func oaepMechanism() *p11.Mechanism {
+	return p11.NewMechanism(p11.CKM_RSA_PKCS_OAEP, p11.NewOAEPParams(p11.CKM_SHA256, p11.CKG_MGF1_SHA256, p11.CKZ_DATA_SPECIFIED, nil))
+}
+
+func isError(err error, code uint) bool {
+	var p11Err p11.Error
+	return errors.As(err, &p11Err) && uint(p11Err) == code
+}
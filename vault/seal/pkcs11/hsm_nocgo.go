@@ -0,0 +1,27 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !cgo
+
+package pkcs11
+
+import "errors"
+
+// hsm is unavailable without cgo, as PKCS#11 libraries are loaded through it.
+type hsm struct{}
+
+func openHSM(_ *config) (*hsm, error) {
+	return nil, errors.New("the pkcs11 seal requires Vault to be built with cgo enabled")
+}
+
+func (h *hsm) wrapKey(_ []byte) (uint, []byte, error) {
+	return 0, nil, errors.New("not supported")
+}
+
+func (h *hsm) unwrapKey(_ string, _ uint, _ []byte) ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+func (h *hsm) close() error {
+	return nil
+}
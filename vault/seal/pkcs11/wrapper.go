@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	wrapping "github.com/hashicorp/go-kms-wrapping/v2"
+)
+
+// Wrapper encrypts values with a data encryption key which is itself
+// encrypted by a key stored in the HSM.
+type Wrapper struct {
+	config *config
+
+	l   sync.Mutex
+	hsm *hsm
+}
+
+var (
+	_ wrapping.Wrapper       = (*Wrapper)(nil)
+	_ wrapping.InitFinalizer = (*Wrapper)(nil)
+)
+
+// NewWrapper returns an unconfigured PKCS#11 wrapper.
+func NewWrapper() *Wrapper {
+	return &Wrapper{}
+}
+
+func (w *Wrapper) Type(_ context.Context) (wrapping.WrapperType, error) {
+	return wrapping.WrapperTypePkcs11, nil
+}
+
+// KeyId returns the label of the key the data encryption keys are encrypted
+// with.
+func (w *Wrapper) KeyId(_ context.Context) (string, error) {
+	if w.config == nil {
+		return "", errors.New("wrapper is not configured")
+	}
+	return w.config.keyLabel, nil
+}
+
+// SetConfig parses the configuration of the wrapper, the HSM is only
+// connected to once the wrapper is used.
+func (w *Wrapper) SetConfig(_ context.Context, opt ...wrapping.Option) (*wrapping.WrapperConfig, error) {
+	opts, err := wrapping.GetOpts(opt...)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := parseConfig(opts.WithConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	w.config = c
+
+	return &wrapping.WrapperConfig{Metadata: c.metadata()}, nil
+}
+
+// Init connects to the HSM, and looks up or generates the key.
+func (w *Wrapper) Init(_ context.Context, _ ...wrapping.Option) error {
+	_, err := w.session()
+	return err
+}
+
+// Finalize logs out of the HSM and unloads the PKCS#11 library.
+func (w *Wrapper) Finalize(_ context.Context, _ ...wrapping.Option) error {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if w.hsm == nil {
+		return nil
+	}
+	err := w.hsm.close()
+	w.hsm = nil
+	return err
+}
+
+func (w *Wrapper) Encrypt(_ context.Context, plaintext []byte, opt ...wrapping.Option) (*wrapping.BlobInfo, error) {
+	h, err := w.session()
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := wrapping.EnvelopeEncrypt(plaintext, opt...)
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping data: %w", err)
+	}
+
+	mechanism, wrappedKey, err := h.wrapKey(env.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data key with the HSM: %w", err)
+	}
+
+	return &wrapping.BlobInfo{
+		Ciphertext: env.Ciphertext,
+		Iv:         env.Iv,
+		KeyInfo: &wrapping.KeyInfo{
+			Mechanism:  uint64(mechanism),
+			KeyId:      w.config.keyLabel,
+			WrappedKey: wrappedKey,
+		},
+	}, nil
+}
+
+func (w *Wrapper) Decrypt(_ context.Context, in *wrapping.BlobInfo, opt ...wrapping.Option) ([]byte, error) {
+	if in == nil {
+		return nil, errors.New("given input for decryption is nil")
+	}
+	if in.KeyInfo == nil {
+		return nil, errors.New("key info is nil")
+	}
+
+	h, err := w.session()
+	if err != nil {
+		return nil, err
+	}
+
+	// Blobs keep the label of the key they were encrypted with, so that they
+	// can still be decrypted once the key label is changed
+	label := in.KeyInfo.KeyId
+	if label == "" {
+		label = w.config.keyLabel
+	}
+
+	key, err := h.unwrapKey(label, uint(in.KeyInfo.Mechanism), in.KeyInfo.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key with the HSM: %w", err)
+	}
+
+	return wrapping.EnvelopeDecrypt(&wrapping.EnvelopeInfo{
+		Key:        key,
+		Iv:         in.Iv,
+		Ciphertext: in.Ciphertext,
+	}, opt...)
+}
+
+func (w *Wrapper) session() (*hsm, error) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if w.config == nil {
+		return nil, errors.New("wrapper is not configured")
+	}
+	if w.hsm != nil {
+		return w.hsm, nil
+	}
+
+	h, err := openHSM(w.config)
+	if err != nil {
+		return nil, err
+	}
+	w.hsm = h
+	return h, nil
+}
@@ -48,6 +48,9 @@ type autoSeal struct {
 
 	hcLock          sync.Mutex
 	healthCheckStop chan struct{}
+
+	// unhealthy is set when the last health test of the seal failed
+	unhealthy atomic.Bool
 }
 
 // Ensure we are implementing the Seal interface
@@ -564,8 +567,20 @@ func (d *autoSeal) StartHealthCheck() {
 				healthCheck.Reset(sealHealthTestIntervalUnhealthy)
 			}
 			lastTestOk = false
+			d.unhealthy.Store(true)
 			d.core.MetricSink().SetGauge(autoSealUnavailableDuration, float32(time.Since(lastSeenOk).Milliseconds()))
 		}
+		pass := func(t time.Time) {
+			d.logger.Debug("seal health test passed")
+			if !lastTestOk {
+				d.logger.Info("seal backend is now healthy again", "downtime", t.Sub(lastSeenOk).String())
+				healthCheck.Reset(sealHealthTestIntervalNominal)
+			}
+			lastTestOk = true
+			lastSeenOk = t
+			d.unhealthy.Store(false)
+			d.core.MetricSink().SetGauge(autoSealUnavailableDuration, 0)
+		}
 		for {
 			select {
 			case <-healthCheckStop:
@@ -580,6 +595,31 @@ func (d *autoSeal) StartHealthCheck() {
 					defer cancel()
 
 					testVal := fmt.Sprintf("Heartbeat %d", mathrand.Intn(1000))
+
+					// Each seal of a multi-seal is tested on its own, and the
+					// stored keys are encrypted again with the seals which
+					// became available again. The multi-seal is only
+					// unhealthy once none of its seals are available.
+					if multi, ok := d.Access.(*seal.MultiAccess); ok {
+						recovered, err := multi.CheckHealth(ctx, []byte(testVal))
+						if len(recovered) > 0 {
+							d.logger.Info("re-encrypting stored keys with recovered seals", "seals", recovered)
+							if err := d.UpgradeKeys(ctx); err != nil {
+								d.logger.Warn("failed to re-encrypt stored keys", "error", err)
+							}
+						}
+						switch {
+						case !multi.Healthy():
+							fail("seal health test failed, all seal backends may be unreachable", "error", err)
+						case err != nil:
+							d.logger.Warn("seal health test failed for some seals, their backends may be unreachable", "error", err)
+							pass(t)
+						default:
+							pass(t)
+						}
+						return
+					}
+
 					ciphertext, err := d.Access.Encrypt(ctx, []byte(testVal), nil)
 
 					if err != nil {
@@ -595,14 +635,7 @@ func (d *autoSeal) StartHealthCheck() {
 							if !bytes.Equal([]byte(testVal), plaintext) {
 								fail("seal health test value failed to decrypt to expected value")
 							} else {
-								d.logger.Debug("seal health test passed")
-								if !lastTestOk {
-									d.logger.Info("seal backend is now healthy again", "downtime", t.Sub(lastSeenOk).String())
-									healthCheck.Reset(sealHealthTestIntervalNominal)
-								}
-								lastTestOk = true
-								lastSeenOk = t
-								d.core.MetricSink().SetGauge(autoSealUnavailableDuration, 0)
+								pass(t)
 							}
 						}()
 					}
@@ -612,6 +645,11 @@ func (d *autoSeal) StartHealthCheck() {
 	}()
 }
 
+// Healthy returns false if the last health test of the seal failed.
+func (d *autoSeal) Healthy() bool {
+	return !d.unhealthy.Load()
+}
+
 func (d *autoSeal) StopHealthCheck() {
 	d.hcLock.Lock()
 	defer d.hcLock.Unlock()
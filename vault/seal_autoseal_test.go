@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/vault/helper/metricsutil"
 
 	proto "github.com/golang/protobuf/proto"
@@ -236,3 +237,58 @@ func TestAutoSeal_HealthCheck(t *testing.T) {
 		}
 	}
 }
+
+func TestAutoSeal_HealthCheck_MultiSeal(t *testing.T) {
+	primary, setPrimaryErr := seal.NewToggleableTestSeal(&seal.TestSealOpts{Secret: []byte("primary")})
+	secondary, setSecondaryErr := seal.NewToggleableTestSeal(&seal.TestSealOpts{Secret: []byte("secondary")})
+	multi, err := seal.NewMultiAccess(hclog.NewNullLogger(), []*seal.SealWrapper{
+		{Access: primary, Name: "primary", Priority: 1},
+		{Access: secondary, Name: "secondary", Priority: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	core, _, _ := TestCoreUnsealed(t)
+	sealHealthTestIntervalNominal = 10 * time.Millisecond
+	sealHealthTestIntervalUnhealthy = 10 * time.Millisecond
+	autoSeal, err := NewAutoSeal(multi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	autoSeal.SetCore(core)
+	autoSeal.StartHealthCheck()
+	defer autoSeal.StopHealthCheck()
+
+	waitFor := func(cond func() bool) bool {
+		for tries := 50; tries > 0; tries-- {
+			if cond() {
+				return true
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		return false
+	}
+
+	// One unavailable seal doesn't make the multi-seal unhealthy
+	setPrimaryErr(errors.New("disconnected"))
+	if !waitFor(func() bool { return !multi.Status(context.Background())[0].Healthy }) {
+		t.Fatal("expected the primary seal to be reported unhealthy")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !autoSeal.Healthy() {
+		t.Fatal("expected the seal to be healthy while the secondary seal is available")
+	}
+
+	// The multi-seal is unhealthy once no seal is available
+	setSecondaryErr(errors.New("disconnected"))
+	if !waitFor(func() bool { return !autoSeal.Healthy() }) {
+		t.Fatal("expected the seal to be unhealthy without any available seal")
+	}
+
+	setPrimaryErr(nil)
+	if !waitFor(autoSeal.Healthy) {
+		t.Fatal("expected the seal to be healthy again once a seal is available")
+	}
+}
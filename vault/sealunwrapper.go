@@ -15,8 +15,20 @@ import (
 	wrapping "github.com/hashicorp/go-kms-wrapping/v2"
 	"github.com/hashicorp/vault/sdk/helper/locksutil"
 	"github.com/hashicorp/vault/sdk/physical"
+	"github.com/hashicorp/vault/vault/seal"
 )
 
+// sealWrapPolicy decides which storage entries are seal wrapped.
+type sealWrapPolicy interface {
+	// sealWrapKey returns whether the entry with the key should be seal
+	// wrapped.
+	sealWrapKey(key string) bool
+
+	// sealWrapAccess returns the access to the seal if it supports seal
+	// wrapping, or nil otherwise.
+	sealWrapAccess() seal.Access
+}
+
 // NewSealUnwrapper creates a new seal unwrapper
 func NewSealUnwrapper(underlying physical.Backend, logger log.Logger) physical.Backend {
 	ret := &sealUnwrapper{
@@ -46,6 +58,10 @@ type sealUnwrapper struct {
 	logger       log.Logger
 	locks        []*locksutil.LockEntry
 	allowUnwraps *uint32
+
+	// policy is nil until the core is set up, and no entries are seal
+	// wrapped without it.
+	policy sealWrapPolicy
 }
 
 // transactionalSealUnwrapper is a seal unwrapper that wraps a physical that is transactional
@@ -62,6 +78,11 @@ func (d *sealUnwrapper) Put(ctx context.Context, entry *physical.Entry) error {
 	locksutil.LockForKey(d.locks, entry.Key).Lock()
 	defer locksutil.LockForKey(d.locks, entry.Key).Unlock()
 
+	entry, err := d.wrap(ctx, entry)
+	if err != nil {
+		return err
+	}
+
 	return d.underlying.Put(ctx, entry)
 }
 
@@ -74,23 +95,13 @@ func (d *sealUnwrapper) Get(ctx context.Context, key string) (*physical.Entry, e
 		return nil, nil
 	}
 
-	var performUnwrap bool
-	se := &wrapping.BlobInfo{}
-	// If the value ends in our canary value, try to decode the bytes.
-	eLen := len(entry.Value)
-	if eLen > 0 && entry.Value[eLen-1] == 's' {
-		if err := proto.Unmarshal(entry.Value[:eLen-1], se); err == nil {
-			// We unmarshaled successfully which means we need to store it as a
-			// non-proto message
-			performUnwrap = true
-		}
-	}
+	se, performUnwrap := decodeSealWrapped(entry.Value)
 	if !performUnwrap {
 		return entry, nil
 	}
-	// It's actually encrypted and we can't read it
+	// It's actually encrypted under the seal
 	if se.Wrapped {
-		return nil, fmt.Errorf("cannot decode sealwrapped storage entry %q", entry.Key)
+		return d.unwrap(ctx, entry.Key, se)
 	}
 	if atomic.LoadUint32(d.allowUnwraps) != 1 {
 		return &physical.Entry{
@@ -111,24 +122,12 @@ func (d *sealUnwrapper) Get(ctx context.Context, key string) (*physical.Entry, e
 		return nil, nil
 	}
 
-	performUnwrap = false
-	se = &wrapping.BlobInfo{}
-	// If the value ends in our canary value, try to decode the bytes.
-	eLen = len(entry.Value)
-	if eLen > 0 && entry.Value[eLen-1] == 's' {
-		// We ignore an error because the canary is not a guarantee; if it
-		// doesn't decode, proceed normally
-		if err := proto.Unmarshal(entry.Value[:eLen-1], se); err == nil {
-			// We unmarshaled successfully which means we need to store it as a
-			// non-proto message
-			performUnwrap = true
-		}
-	}
+	se, performUnwrap = decodeSealWrapped(entry.Value)
 	if !performUnwrap {
 		return entry, nil
 	}
 	if se.Wrapped {
-		return nil, fmt.Errorf("cannot decode sealwrapped storage entry %q", entry.Key)
+		return d.unwrap(ctx, entry.Key, se)
 	}
 
 	entry = &physical.Entry{
@@ -165,13 +164,176 @@ func (d *transactionalSealUnwrapper) Transaction(ctx context.Context, txns []*ph
 		defer l.Unlock()
 	}
 
-	if err := d.Transactional.Transaction(ctx, txns); err != nil {
+	wrappedTxns := make([]*physical.TxnEntry, 0, len(txns))
+	for _, curr := range txns {
+		if curr.Operation == physical.PutOperation {
+			entry, err := d.wrap(ctx, curr.Entry)
+			if err != nil {
+				return err
+			}
+			curr = &physical.TxnEntry{
+				Operation: curr.Operation,
+				Entry:     entry,
+			}
+		}
+		wrappedTxns = append(wrappedTxns, curr)
+	}
+
+	if err := d.Transactional.Transaction(ctx, wrappedTxns); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// decodeSealWrapped decodes the value of an entry stored by a seal wrapping
+// storage layer, returning false if it isn't one.
+func decodeSealWrapped(value []byte) (*wrapping.BlobInfo, bool) {
+	// If the value ends in our canary value, try to decode the bytes. We
+	// ignore an error because the canary is not a guarantee; if it doesn't
+	// decode, proceed normally
+	eLen := len(value)
+	if eLen == 0 || value[eLen-1] != 's' {
+		return nil, false
+	}
+	se := &wrapping.BlobInfo{}
+	if err := proto.Unmarshal(value[:eLen-1], se); err != nil {
+		return nil, false
+	}
+	return se, true
+}
+
+// sealWrapEntry encrypts the value of the entry under the seal.
+func sealWrapEntry(ctx context.Context, access seal.Access, entry *physical.Entry) (*physical.Entry, error) {
+	se, err := access.Encrypt(ctx, entry.Value)
+	if err != nil {
+		return nil, &ErrEncrypt{Err: fmt.Errorf("failed to seal wrap storage entry %q: %w", entry.Key, err)}
+	}
+	se.Wrapped = true
+
+	value, err := proto.Marshal(se)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal seal wrapped storage entry %q: %w", entry.Key, err)
+	}
+
+	return &physical.Entry{
+		Key:      entry.Key,
+		Value:    append(value, 's'),
+		SealWrap: true,
+	}, nil
+}
+
+// wrap returns the entry seal wrapped if the policy requires it.
+func (d *sealUnwrapper) wrap(ctx context.Context, entry *physical.Entry) (*physical.Entry, error) {
+	if d.policy == nil || !d.policy.sealWrapKey(entry.Key) {
+		return entry, nil
+	}
+	access := d.policy.sealWrapAccess()
+	if access == nil {
+		return entry, nil
+	}
+	return sealWrapEntry(ctx, access, entry)
+}
+
+// unwrap decrypts the value of a seal wrapped entry.
+func (d *sealUnwrapper) unwrap(ctx context.Context, key string, se *wrapping.BlobInfo) (*physical.Entry, error) {
+	var access seal.Access
+	if d.policy != nil {
+		access = d.policy.sealWrapAccess()
+	}
+	if access == nil {
+		return nil, fmt.Errorf("cannot decode sealwrapped storage entry %q", key)
+	}
+
+	pt, err := access.Decrypt(ctx, se)
+	if err != nil {
+		return nil, &ErrDecrypt{Err: fmt.Errorf("failed to unwrap sealwrapped storage entry %q: %w", key, err)}
+	}
+
+	return &physical.Entry{
+		Key:      key,
+		Value:    pt,
+		SealWrap: true,
+	}, nil
+}
+
+// rewrap brings the stored entry with the key in line with the policy: it
+// seal wraps the entry if it should be, wraps it again if it was wrapped with
+// another key of the seal, and unwraps it if it shouldn't be wrapped anymore.
+// It returns whether the entry was rewritten.
+func (d *sealUnwrapper) rewrap(ctx context.Context, key string) (bool, error) {
+	locksutil.LockForKey(d.locks, key).Lock()
+	defer locksutil.LockForKey(d.locks, key).Unlock()
+
+	entry, err := d.underlying.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if entry == nil {
+		return false, nil
+	}
+
+	var wrapped, legacy bool
+	var keyID string
+	if se, ok := decodeSealWrapped(entry.Value); ok {
+		if se.Wrapped {
+			unwrapped, err := d.unwrap(ctx, key, se)
+			if err != nil {
+				return false, err
+			}
+			entry = unwrapped
+			wrapped = true
+			if se.KeyInfo != nil {
+				keyID = se.KeyInfo.KeyId
+			}
+		} else {
+			entry = &physical.Entry{Key: key, Value: se.Ciphertext}
+			legacy = true
+		}
+	}
+
+	var access seal.Access
+	if d.policy != nil && d.policy.sealWrapKey(key) {
+		access = d.policy.sealWrapAccess()
+	}
+
+	switch {
+	case access != nil && wrapped:
+		currentKeyID, err := access.KeyId(ctx)
+		if err != nil {
+			return false, err
+		}
+		if keyID == currentKeyID {
+			return false, nil
+		}
+	case access == nil && !wrapped && !legacy:
+		return false, nil
+	}
+
+	if access != nil {
+		entry, err = sealWrapEntry(ctx, access, entry)
+		if err != nil {
+			return false, err
+		}
+	} else {
+		entry.SealWrap = false
+	}
+
+	return true, d.underlying.Put(ctx, entry)
+}
+
+// sealUnwrapperFor returns the seal unwrapper of a physical backend created
+// by NewSealUnwrapper.
+func sealUnwrapperFor(b physical.Backend) *sealUnwrapper {
+	switch d := b.(type) {
+	case *sealUnwrapper:
+		return d
+	case *transactionalSealUnwrapper:
+		return d.sealUnwrapper
+	}
+	return nil
+}
+
 // This should only run during preSeal which ensures that it can't be run
 // concurrently and that it will be run only by the active node
 func (d *sealUnwrapper) stopUnwraps() {
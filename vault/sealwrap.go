@@ -0,0 +1,280 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/armon/go-radix"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/vault/seal"
+)
+
+// sealWrapConfigPath is the barrier path of the seal wrap configuration. It
+// isn't under the storage prefix of any mount, so it is never seal wrapped.
+const sealWrapConfigPath = "core/sealwrap/config"
+
+var errSealWrapNotSupported = errors.New("seal wrapping requires an auto seal that supports it")
+
+// sealWrapConfig selects the storage entries seal wrapped, on top of being
+// encrypted by the barrier. Nothing is seal wrapped unless it is enabled.
+type sealWrapConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// Paths are prefixes of mount paths followed by the storage keys of the
+	// mount. A mount path selects every entry of the mount.
+	Paths []string `json:"paths"`
+
+	// WrapPluginStorage seal wraps the storage paths the plugins of the
+	// mounts ask to be seal wrapped.
+	WrapPluginStorage bool `json:"wrap_plugin_storage"`
+}
+
+// sealWrapRules is the seal wrap configuration prepared for the lookups of
+// the storage layer.
+type sealWrapRules struct {
+	paths             *radix.Tree
+	wrapPluginStorage bool
+}
+
+// sealWrapRewrap is the state of the job rewrapping the stored entries.
+type sealWrapRewrap struct {
+	l       sync.Mutex
+	running bool
+	// again is set when the job is started while running, so that entries
+	// it already walked past are rewrapped under the latest rules.
+	again bool
+
+	processed uint64
+	succeeded uint64
+	failed    uint64
+}
+
+// sealWrapKey returns whether the entry with the physical key should be seal
+// wrapped under the current rules.
+func (c *Core) sealWrapKey(key string) bool {
+	if c.disableSealWrap {
+		return false
+	}
+	rules, _ := c.sealWrapRules.Load().(*sealWrapRules)
+	if rules == nil {
+		return false
+	}
+
+	re, rest, ok := c.router.storageRouteEntry(key)
+	if !ok {
+		return false
+	}
+	if re.mountEntry.SealWrap && re.mountEntry.Type != systemMountType {
+		return true
+	}
+	if rules.wrapPluginStorage {
+		if paths, ok := re.sealWrapPaths.Load().(*radix.Tree); ok && paths != nil {
+			if _, _, ok := paths.LongestPrefix(rest); ok {
+				return true
+			}
+		}
+	}
+	_, _, ok = rules.paths.LongestPrefix(re.mountEntry.APIPath() + rest)
+	return ok
+}
+
+// sealWrapAccess returns the access to the seal if it supports seal wrapping.
+func (c *Core) sealWrapAccess() seal.Access {
+	if c.disableSealWrap || c.seal == nil || !c.seal.SealWrapable() {
+		return nil
+	}
+	return c.seal.GetAccess()
+}
+
+func (c *Core) sealWrapConfig(ctx context.Context) (*sealWrapConfig, error) {
+	entry, err := c.barrier.Get(ctx, sealWrapConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config := new(sealWrapConfig)
+	if entry == nil {
+		return config, nil
+	}
+	if err := entry.DecodeJSON(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// setSealWrapConfig validates and stores the configuration, and applies it to
+// the entries written from then on.
+func (c *Core) setSealWrapConfig(ctx context.Context, config *sealWrapConfig) error {
+	if config.Enabled {
+		if c.disableSealWrap {
+			return errors.New("seal wrapping is disabled by the disable_sealwrap server option")
+		}
+		if c.seal == nil || !c.seal.SealWrapable() {
+			return errSealWrapNotSupported
+		}
+	}
+	for i, path := range config.Paths {
+		path = strings.TrimPrefix(path, "/")
+		if path == "" {
+			return errors.New("paths must not be empty")
+		}
+		config.Paths[i] = path
+	}
+
+	entry, err := logical.StorageEntryJSON(sealWrapConfigPath, config)
+	if err != nil {
+		return err
+	}
+	if err := c.barrier.Put(ctx, entry); err != nil {
+		return err
+	}
+
+	c.applySealWrapConfig(config)
+	return nil
+}
+
+// loadSealWrapConfig loads the seal wrap configuration on unseal. It must run
+// before the mounts are set up, so that their writes follow the rules.
+func (c *Core) loadSealWrapConfig(ctx context.Context) error {
+	config, err := c.sealWrapConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load seal wrap config: %w", err)
+	}
+	if config.Enabled && (c.disableSealWrap || c.seal == nil || !c.seal.SealWrapable()) {
+		c.logger.Warn("seal wrapping is enabled but not supported by the seal, entries are stored without it")
+	}
+
+	c.applySealWrapConfig(config)
+	return nil
+}
+
+func (c *Core) applySealWrapConfig(config *sealWrapConfig) {
+	if !config.Enabled {
+		c.sealWrapRules.Store((*sealWrapRules)(nil))
+		return
+	}
+
+	paths := radix.New()
+	for _, path := range config.Paths {
+		paths.Insert(path, true)
+	}
+	c.sealWrapRules.Store(&sealWrapRules{
+		paths:             paths,
+		wrapPluginStorage: config.WrapPluginStorage,
+	})
+}
+
+// startSealRewrap starts rewrapping the entries of every mount in the
+// background, returning false if it was already running.
+func (c *Core) startSealRewrap() bool {
+	c.sealRewrap.l.Lock()
+	defer c.sealRewrap.l.Unlock()
+
+	if c.sealRewrap.running {
+		c.sealRewrap.again = true
+		return false
+	}
+	c.sealRewrap.running = true
+	c.sealRewrap.processed = 0
+	c.sealRewrap.succeeded = 0
+	c.sealRewrap.failed = 0
+
+	go c.runSealRewrap(c.activeContext)
+	return true
+}
+
+// sealRewrapStatus returns whether the rewrap job is running, along with the
+// entries it processed, succeeded and failed to rewrap.
+func (c *Core) sealRewrapStatus() (bool, uint64, uint64, uint64) {
+	c.sealRewrap.l.Lock()
+	defer c.sealRewrap.l.Unlock()
+
+	return c.sealRewrap.running, c.sealRewrap.processed, c.sealRewrap.succeeded, c.sealRewrap.failed
+}
+
+func (c *Core) runSealRewrap(ctx context.Context) {
+	logger := c.logger.Named("sealwrap")
+
+	for {
+		logger.Info("rewrapping seal wrapped entries")
+		if err := c.rewrapAll(ctx); err != nil {
+			logger.Error("failed to rewrap seal wrapped entries", "error", err)
+		}
+
+		c.sealRewrap.l.Lock()
+		if !c.sealRewrap.again || ctx.Err() != nil {
+			c.sealRewrap.running = false
+			c.sealRewrap.again = false
+			logger.Info("finished rewrapping seal wrapped entries", "processed", c.sealRewrap.processed, "succeeded", c.sealRewrap.succeeded, "failed", c.sealRewrap.failed)
+			c.sealRewrap.l.Unlock()
+			return
+		}
+		c.sealRewrap.again = false
+		c.sealRewrap.l.Unlock()
+	}
+}
+
+// rewrapAll brings the entries under the storage prefix of every mount in
+// line with the rules of the seal wrap configuration.
+func (c *Core) rewrapAll(ctx context.Context) error {
+	d := sealUnwrapperFor(c.sealUnwrapper)
+	if d == nil {
+		return errors.New("storage does not support seal wrapping")
+	}
+
+	var prefixes []string
+	c.router.storageRoutes.Load().(*radix.Tree).Walk(func(prefix string, _ interface{}) bool {
+		prefixes = append(prefixes, prefix)
+		return false
+	})
+
+	for _, prefix := range prefixes {
+		if err := c.rewrapPrefix(ctx, d, prefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Core) rewrapPrefix(ctx context.Context, d *sealUnwrapper, prefix string) error {
+	keys, err := d.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(key, "/") {
+			if err := c.rewrapPrefix(ctx, d, prefix+key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := d.rewrap(ctx, prefix+key)
+
+		c.sealRewrap.l.Lock()
+		c.sealRewrap.processed++
+		if err != nil {
+			c.sealRewrap.failed++
+		} else {
+			c.sealRewrap.succeeded++
+		}
+		c.sealRewrap.l.Unlock()
+
+		if err != nil {
+			c.logger.Named("sealwrap").Error("failed to rewrap entry", "key", prefix+key, "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/helper/logging"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/hashicorp/vault/sdk/physical"
+	"github.com/hashicorp/vault/sdk/physical/inmem"
+)
+
+func TestCore_SealWrapPaths(t *testing.T) {
+	phys, err := inmem.NewInmem(nil, logging.NewVaultLogger(log.Trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _, root := TestCoreUnsealedBackend(t, phys)
+	ctx := namespace.RootContext(nil)
+
+	mustHandle := func(t *testing.T, op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := c.HandleRequest(ctx, &logical.Request{
+			Operation:   op,
+			Path:        path,
+			Data:        data,
+			ClientToken: root,
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v, resp: %#v", err, resp)
+		}
+		return resp
+	}
+	waitRewrap := func(t *testing.T) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			resp := mustHandle(t, logical.ReadOperation, "sys/sealwrap/rewrap", nil)
+			if !resp.Data["is_running"].(bool) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("rewrap did not finish")
+	}
+	// storedWrapped returns whether the secret is seal wrapped in the
+	// physical backend, below the barrier.
+	storedWrapped := func(t *testing.T, path string) bool {
+		t.Helper()
+		prefix, ok := c.router.MatchingStoragePrefixByAPIPath(ctx, path)
+		if !ok {
+			t.Fatalf("no mount for %s", path)
+		}
+		key := prefix + path[len(c.router.MatchingMount(ctx, path)):]
+		entry, err := phys.Get(context.Background(), key)
+		if err != nil || entry == nil {
+			t.Fatalf("err: %v, entry: %v", err, entry)
+		}
+		se, ok := decodeSealWrapped(entry.Value)
+		return ok && se.Wrapped
+	}
+
+	mustHandle(t, logical.UpdateOperation, "sys/mounts/kv", map[string]interface{}{
+		"type": "kv",
+	})
+	mustHandle(t, logical.UpdateOperation, "kv/plain", map[string]interface{}{"foo": "bar"})
+	mustHandle(t, logical.UpdateOperation, "kv/hsm/before", map[string]interface{}{"foo": "bar"})
+
+	resp := mustHandle(t, logical.ReadOperation, "sys/sealwrap/config", nil)
+	if resp.Data["enabled"].(bool) || !resp.Data["supported"].(bool) {
+		t.Fatalf("unexpected config: %#v", resp.Data)
+	}
+
+	mustHandle(t, logical.UpdateOperation, "sys/sealwrap/config", map[string]interface{}{
+		"enabled": true,
+		"paths":   "kv/hsm/",
+	})
+	waitRewrap(t)
+
+	mustHandle(t, logical.UpdateOperation, "kv/hsm/after", map[string]interface{}{"foo": "bar"})
+
+	for path, wrapped := range map[string]bool{
+		"kv/plain":      false,
+		"kv/hsm/before": true,
+		"kv/hsm/after":  true,
+	} {
+		if got := storedWrapped(t, path); got != wrapped {
+			t.Fatalf("expected %s to be seal wrapped: %t, got: %t", path, wrapped, got)
+		}
+
+		c.physicalCache.Purge(ctx)
+		resp := mustHandle(t, logical.ReadOperation, path, nil)
+		if resp == nil || resp.Data["foo"] != "bar" {
+			t.Fatalf("unexpected response reading %s: %#v", path, resp)
+		}
+	}
+
+	resp = mustHandle(t, logical.ReadOperation, "sys/sealwrap/rewrap", nil)
+	entries := resp.Data["entries"].(map[string]interface{})
+	if entries["failed"].(uint64) != 0 || entries["succeeded"].(uint64) == 0 {
+		t.Fatalf("unexpected rewrap status: %#v", resp.Data)
+	}
+
+	// Disabling seal wrapping unwraps the entries
+	mustHandle(t, logical.UpdateOperation, "sys/sealwrap/config", map[string]interface{}{
+		"enabled": false,
+	})
+	waitRewrap(t)
+
+	for _, path := range []string{"kv/hsm/before", "kv/hsm/after"} {
+		if storedWrapped(t, path) {
+			t.Fatalf("expected %s to be unwrapped", path)
+		}
+	}
+}
+
+func TestSealUnwrapper_Rewrap(t *testing.T) {
+	phys, err := inmem.NewInmem(nil, logging.NewVaultLogger(log.Trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _, _ := TestCoreUnsealedBackend(t, phys)
+	ctx := context.Background()
+	d := sealUnwrapperFor(c.sealUnwrapper)
+
+	entry := &physical.Entry{Key: "core/test", Value: []byte("value")}
+	wrapped, err := sealWrapEntry(ctx, c.sealWrapAccess(), entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.underlying.Put(ctx, wrapped); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := d.Get(ctx, "core/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Value, entry.Value) {
+		t.Fatalf("expected %q, got %q", entry.Value, got.Value)
+	}
+
+	// core/ isn't under a mount, so the entry is unwrapped
+	rewritten, err := d.rewrap(ctx, "core/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rewritten {
+		t.Fatal("expected the entry to be rewritten")
+	}
+	raw, err := d.underlying.Get(ctx, "core/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(raw.Value, entry.Value) {
+		t.Fatalf("expected %q, got %q", entry.Value, raw.Value)
+	}
+
+	rewritten, err = d.rewrap(ctx, "core/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewritten {
+		t.Fatal("expected the entry to be left as is")
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshotstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/Azure/go-autorest/autorest/azure"
+	log "github.com/hashicorp/go-hclog"
+)
+
+// AzureStore saves snapshots to an Azure Blob Storage container.
+type AzureStore struct {
+	container  azblob.ContainerURL
+	pathPrefix string
+}
+
+var _ Store = (*AzureStore)(nil)
+
+// NewAzureStore creates a store saving snapshots to an Azure Blob Storage
+// container, authenticating with the shared key of the storage account.
+func NewAzureStore(pathPrefix string, conf map[string]string, _ log.Logger) (Store, error) {
+	containerName := conf["azure_container_name"]
+	if containerName == "" {
+		return nil, errors.New("azure_container_name must be set")
+	}
+	accountName := conf["azure_account_name"]
+	if accountName == "" {
+		return nil, errors.New("azure_account_name must be set")
+	}
+	accountKey := conf["azure_account_key"]
+	if accountKey == "" {
+		return nil, errors.New("azure_account_key must be set")
+	}
+
+	var containerURL *url.URL
+	var err error
+	if endpoint := conf["azure_endpoint"]; endpoint != "" {
+		containerURL, err = url.Parse(strings.TrimSuffix(endpoint, "/") + "/" + containerName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid azure_endpoint: %w", err)
+		}
+	} else {
+		environmentName := conf["azure_blob_environment"]
+		if environmentName == "" {
+			environmentName = "AzurePublicCloud"
+		}
+		environment, err := azure.EnvironmentFromName(environmentName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up Azure environment descriptor for name %q: %w", environmentName, err)
+		}
+		containerURL, err = url.Parse(fmt.Sprintf("https://%s.blob.%s/%s", accountName, environment.StorageEndpointSuffix, containerName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Azure client: %w", err)
+		}
+	}
+
+	credential, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure client: %w", err)
+	}
+
+	return &AzureStore{
+		container:  azblob.NewContainerURL(*containerURL, azblob.NewPipeline(credential, azblob.PipelineOptions{})),
+		pathPrefix: strings.Trim(pathPrefix, "/"),
+	}, nil
+}
+
+func (s *AzureStore) blob(name string) azblob.BlockBlobURL {
+	return s.container.NewBlockBlobURL(path.Join(s.pathPrefix, name))
+}
+
+func (s *AzureStore) Put(ctx context.Context, name string, r io.ReadSeeker, _ int64) (string, error) {
+	blob := s.blob(name)
+	_, err := azblob.UploadStreamToBlockBlob(ctx, r, blob, azblob.UploadStreamToBlockBlobOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	u := blob.URL()
+	return u.String(), nil
+}
+
+func (s *AzureStore) List(ctx context.Context, prefix string) ([]Snapshot, error) {
+	listPrefix := path.Join(s.pathPrefix, prefix)
+	if s.pathPrefix != "" && prefix == "" {
+		listPrefix += "/"
+	}
+
+	var snapshots []Snapshot
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		segment, err := s.container.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{
+			Prefix: listPrefix,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range segment.Segment.BlobItems {
+			name := strings.TrimPrefix(strings.TrimPrefix(item.Name, s.pathPrefix), "/")
+			// Nested blobs aren't snapshots of this store
+			if strings.Contains(name, "/") {
+				continue
+			}
+			var size int64
+			if item.Properties.ContentLength != nil {
+				size = *item.Properties.ContentLength
+			}
+			snapshots = append(snapshots, Snapshot{
+				Name: name,
+				Size: size,
+			})
+		}
+		marker = segment.NextMarker
+	}
+	sortSnapshots(snapshots)
+
+	return snapshots, nil
+}
+
+func (s *AzureStore) Delete(ctx context.Context, name string) error {
+	_, err := s.blob(name).Delete(ctx, azblob.DeleteSnapshotsOptionInclude, azblob.BlobAccessConditions{})
+	var storageErr azblob.StorageError
+	if errors.As(err, &storageErr) && storageErr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshotstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/vault/helper/useragent"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// GCSStore saves snapshots to a Google Cloud Storage bucket.
+type GCSStore struct {
+	client     *storage.Client
+	bucket     string
+	pathPrefix string
+}
+
+var _ Store = (*GCSStore)(nil)
+
+// NewGCSStore creates a store saving snapshots to a Google Cloud Storage
+// bucket. Application default credentials are used unless a service account
+// key is configured.
+func NewGCSStore(pathPrefix string, conf map[string]string, _ log.Logger) (Store, error) {
+	bucket := conf["google_gcs_bucket"]
+	if bucket == "" {
+		return nil, errors.New("google_gcs_bucket must be set")
+	}
+
+	opts := []option.ClientOption{option.WithUserAgent(useragent.String())}
+	if key := conf["google_service_account_key"]; key != "" {
+		opts = append(opts, option.WithCredentialsJSON([]byte(key)))
+	}
+	if endpoint := conf["google_endpoint"]; endpoint != "" {
+		if raw := conf["google_disable_tls"]; raw != "" {
+			disableTLS, err := parseutil.ParseBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid boolean set for google_disable_tls: %q", raw)
+			}
+			if disableTLS {
+				endpoint = "http://" + strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+			}
+		}
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	client, err := storage.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	return &GCSStore{
+		client:     client,
+		bucket:     bucket,
+		pathPrefix: strings.Trim(pathPrefix, "/"),
+	}, nil
+}
+
+func (s *GCSStore) object(name string) string {
+	return path.Join(s.pathPrefix, name)
+}
+
+func (s *GCSStore) Put(ctx context.Context, name string, r io.ReadSeeker, _ int64) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Canceling the context aborts the upload if it could not be completed
+	w := s.client.Bucket(s.bucket).Object(s.object(name)).NewWriter(ctx)
+	if _, err := io.Copy(w, r); err != nil {
+		cancel()
+		w.Close()
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("gs://%s/%s", s.bucket, s.object(name)), nil
+}
+
+func (s *GCSStore) List(ctx context.Context, prefix string) ([]Snapshot, error) {
+	query := &storage.Query{
+		Prefix: s.object(prefix),
+	}
+	if s.pathPrefix != "" && prefix == "" {
+		query.Prefix += "/"
+	}
+
+	var snapshots []Snapshot
+	it := s.client.Bucket(s.bucket).Objects(ctx, query)
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(attrs.Name, s.pathPrefix), "/")
+		// Nested objects aren't snapshots of this store
+		if strings.Contains(name, "/") {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{
+			Name: name,
+			Size: attrs.Size,
+		})
+	}
+	sortSnapshots(snapshots)
+
+	return snapshots, nil
+}
+
+func (s *GCSStore) Delete(ctx context.Context, name string) error {
+	err := s.client.Bucket(s.bucket).Object(s.object(name)).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshotstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+)
+
+// LocalStore saves snapshots to a directory of the local filesystem.
+type LocalStore struct {
+	dir string
+}
+
+var _ Store = (*LocalStore)(nil)
+
+// NewLocalStore creates a store saving snapshots to the path prefix
+// directory, which is created if it doesn't exist.
+func NewLocalStore(pathPrefix string, _ map[string]string, _ log.Logger) (Store, error) {
+	if pathPrefix == "" {
+		return nil, errors.New("path_prefix must be set for local storage")
+	}
+	if err := os.MkdirAll(pathPrefix, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &LocalStore{dir: pathPrefix}, nil
+}
+
+func (s *LocalStore) Put(_ context.Context, name string, r io.ReadSeeker, _ int64) (string, error) {
+	path := filepath.Join(s.dir, name)
+
+	// Write to a temporary file first, so that an interrupted snapshot never
+	// looks like a complete one
+	f, err := os.CreateTemp(s.dir, "."+name+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", err
+	}
+
+	return "file://" + path, nil
+}
+
+func (s *LocalStore) List(_ context.Context, prefix string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, Snapshot{
+			Name: entry.Name(),
+			Size: info.Size(),
+		})
+	}
+	sortSnapshots(snapshots)
+
+	return snapshots, nil
+}
+
+func (s *LocalStore) Delete(_ context.Context, name string) error {
+	err := os.Remove(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshotstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "snapshots")
+
+	store, err := New(TypeLocal, dir, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"vault-snapshot-2.snap", "vault-snapshot-1.snap", "other-1.snap"} {
+		url, err := store.Put(ctx, name, strings.NewReader(name), int64(len(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if url != "file://"+filepath.Join(dir, name) {
+			t.Fatalf("bad url: %q", url)
+		}
+	}
+
+	// Temporary files are cleaned up
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(entries))
+	}
+
+	snapshots, err := store.List(ctx, "vault-snapshot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "vault-snapshot-1.snap" || snapshots[0].Size != int64(len("vault-snapshot-1.snap")) {
+		t.Fatalf("bad snapshots: %v", snapshots)
+	}
+
+	if err := store.Delete(ctx, "vault-snapshot-1.snap"); err != nil {
+		t.Fatal(err)
+	}
+	// Deleting a missing snapshot is not an error
+	if err := store.Delete(ctx, "vault-snapshot-1.snap"); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err = store.List(ctx, "vault-snapshot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Name != "vault-snapshot-2.snap" {
+		t.Fatalf("bad snapshots: %v", snapshots)
+	}
+}
+
+func TestNew(t *testing.T) {
+	cases := map[string]struct {
+		storageType string
+		pathPrefix  string
+		conf        map[string]string
+	}{
+		"unknown type":          {storageType: "ftp", pathPrefix: "/tmp"},
+		"local without path":    {storageType: TypeLocal},
+		"s3 without bucket":     {storageType: TypeAWSS3},
+		"s3 invalid bool":       {storageType: TypeAWSS3, conf: map[string]string{"aws_s3_bucket": "b", "aws_s3_disable_tls": "maybe"}},
+		"gcs without bucket":    {storageType: TypeGoogleGCS},
+		"azure without key":     {storageType: TypeAzureBlob, conf: map[string]string{"azure_container_name": "c", "azure_account_name": "a"}},
+		"azure bad environment": {storageType: TypeAzureBlob, conf: map[string]string{"azure_container_name": "c", "azure_account_name": "a", "azure_account_key": "a2V5", "azure_blob_environment": "Mars"}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(tc.storageType, tc.pathPrefix, tc.conf, nil); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
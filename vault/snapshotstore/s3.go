@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshotstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/go-cleanhttp"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-secure-stdlib/awsutil"
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+)
+
+// S3Store saves snapshots to an AWS S3 bucket.
+type S3Store struct {
+	client     *s3.S3
+	bucket     string
+	pathPrefix string
+
+	serverSideEncryption bool
+	enableKMS            bool
+	kmsKey               string
+}
+
+var _ Store = (*S3Store)(nil)
+
+// NewS3Store creates a store saving snapshots to an AWS S3 bucket.
+func NewS3Store(pathPrefix string, conf map[string]string, logger log.Logger) (Store, error) {
+	bucket := conf["aws_s3_bucket"]
+	if bucket == "" {
+		return nil, errors.New("aws_s3_bucket must be set")
+	}
+
+	region := conf["aws_s3_region"]
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	var disableTLS, forcePathStyle, serverSideEncryption, enableKMS bool
+	for key, value := range map[string]*bool{
+		"aws_s3_disable_tls":            &disableTLS,
+		"aws_s3_force_path_style":       &forcePathStyle,
+		"aws_s3_server_side_encryption": &serverSideEncryption,
+		"aws_s3_enable_kms":             &enableKMS,
+	} {
+		if raw, ok := conf[key]; ok && raw != "" {
+			b, err := parseutil.ParseBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid boolean set for %s: %q", key, raw)
+			}
+			*value = b
+		}
+	}
+	if serverSideEncryption && enableKMS {
+		return nil, errors.New("aws_s3_server_side_encryption cannot be used with aws_s3_enable_kms")
+	}
+
+	credsConfig := &awsutil.CredentialsConfig{
+		AccessKey:    conf["aws_access_key_id"],
+		SecretKey:    conf["aws_secret_access_key"],
+		SessionToken: conf["aws_session_token"],
+		Logger:       logger,
+	}
+	creds, err := credsConfig.GenerateCredentialChain()
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: creds,
+		HTTPClient: &http.Client{
+			Transport: cleanhttp.DefaultPooledTransport(),
+		},
+		Endpoint:         aws.String(conf["aws_s3_endpoint"]),
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(forcePathStyle),
+		DisableSSL:       aws.Bool(disableTLS),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Store{
+		client:               s3.New(sess),
+		bucket:               bucket,
+		pathPrefix:           strings.Trim(pathPrefix, "/"),
+		serverSideEncryption: serverSideEncryption,
+		enableKMS:            enableKMS,
+		kmsKey:               conf["aws_s3_kms_key"],
+	}, nil
+}
+
+func (s *S3Store) key(name string) string {
+	return path.Join(s.pathPrefix, name)
+}
+
+func (s *S3Store) Put(ctx context.Context, name string, r io.ReadSeeker, size int64) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(s.key(name)),
+		Body:          r,
+		ContentLength: aws.Int64(size),
+	}
+	switch {
+	case s.enableKMS:
+		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+		if s.kmsKey != "" {
+			input.SSEKMSKeyId = aws.String(s.kmsKey)
+		}
+	case s.serverSideEncryption:
+		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+	}
+
+	if _, err := s.client.PutObjectWithContext(ctx, input); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("s3://%s/%s", s.bucket, s.key(name)), nil
+}
+
+func (s *S3Store) List(ctx context.Context, prefix string) ([]Snapshot, error) {
+	listPrefix := s.key(prefix)
+	if s.pathPrefix != "" && prefix == "" {
+		listPrefix += "/"
+	}
+
+	var snapshots []Snapshot
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(listPrefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, object := range page.Contents {
+			name := strings.TrimPrefix(aws.StringValue(object.Key), s.pathPrefix)
+			name = strings.TrimPrefix(name, "/")
+			// Nested objects aren't snapshots of this store
+			if strings.Contains(name, "/") {
+				continue
+			}
+			snapshots = append(snapshots, Snapshot{
+				Name: name,
+				Size: aws.Int64Value(object.Size),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortSnapshots(snapshots)
+
+	return snapshots, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, name string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	return err
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package snapshotstore implements the destinations automatic raft snapshots
+// are saved to.
+package snapshotstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	log "github.com/hashicorp/go-hclog"
+)
+
+// Storage types of the supported destinations.
+const (
+	TypeLocal     = "local"
+	TypeAWSS3     = "aws-s3"
+	TypeGoogleGCS = "google-gcs"
+	TypeAzureBlob = "azure-blob"
+)
+
+// Snapshot describes a snapshot saved to a store.
+type Snapshot struct {
+	// Name of the snapshot, relative to the path prefix of the store
+	Name string
+
+	// Size of the snapshot in bytes
+	Size int64
+}
+
+// Store saves snapshots to a destination, under a path prefix.
+type Store interface {
+	// Put saves the snapshot under the name, returning the URL it can be
+	// retrieved from.
+	Put(ctx context.Context, name string, r io.ReadSeeker, size int64) (string, error)
+
+	// List returns the snapshots whose name starts with the prefix, sorted by
+	// name.
+	List(ctx context.Context, prefix string) ([]Snapshot, error)
+
+	// Delete removes the named snapshot.
+	Delete(ctx context.Context, name string) error
+}
+
+// Factory creates a store from its configuration.
+type Factory func(pathPrefix string, conf map[string]string, logger log.Logger) (Store, error)
+
+// Factories holds the factories of the supported storage types.
+var Factories = map[string]Factory{
+	TypeLocal:     NewLocalStore,
+	TypeAWSS3:     NewS3Store,
+	TypeGoogleGCS: NewGCSStore,
+	TypeAzureBlob: NewAzureStore,
+}
+
+// ConfigKeys holds the configuration keys understood by each storage type.
+var ConfigKeys = map[string][]string{
+	TypeLocal: {},
+	TypeAWSS3: {
+		"aws_s3_bucket",
+		"aws_s3_region",
+		"aws_s3_endpoint",
+		"aws_s3_disable_tls",
+		"aws_s3_force_path_style",
+		"aws_s3_server_side_encryption",
+		"aws_s3_enable_kms",
+		"aws_s3_kms_key",
+		"aws_access_key_id",
+		"aws_secret_access_key",
+		"aws_session_token",
+	},
+	TypeGoogleGCS: {
+		"google_gcs_bucket",
+		"google_endpoint",
+		"google_disable_tls",
+		"google_service_account_key",
+	},
+	TypeAzureBlob: {
+		"azure_container_name",
+		"azure_account_name",
+		"azure_account_key",
+		"azure_blob_environment",
+		"azure_endpoint",
+	},
+}
+
+// SensitiveConfigKeys holds the configuration keys that must not be returned
+// when reading a configuration.
+var SensitiveConfigKeys = []string{
+	"aws_secret_access_key",
+	"aws_session_token",
+	"google_service_account_key",
+	"azure_account_key",
+}
+
+// New creates a store of the storage type.
+func New(storageType, pathPrefix string, conf map[string]string, logger log.Logger) (Store, error) {
+	factory, ok := Factories[storageType]
+	if !ok {
+		return nil, fmt.Errorf("unknown storage type %q", storageType)
+	}
+	return factory(pathPrefix, conf, logger)
+}
+
+func sortSnapshots(snapshots []Snapshot) {
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+}
@@ -411,6 +411,155 @@ func (ts *TokenStore) paths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(tokenCreateHelp),
 		},
 
+		{
+			Pattern: "exchange$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixToken,
+				OperationVerb:   "exchange",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"grant_type": {
+					Type:        framework.TypeString,
+					Description: "Grant type of the request; if set, must be " + tokenExchangeGrantType,
+				},
+				"subject_token": {
+					Type:        framework.TypeString,
+					Description: "Token to exchange. Defaults to the calling token",
+				},
+				"subject_token_type": {
+					Type:        framework.TypeString,
+					Default:     tokenTypeAccessToken,
+					Description: "Type of the subject token: " + tokenTypeAccessToken + " for Vault tokens, or " + tokenTypeIDToken + " for identity tokens",
+				},
+				"requested_token_type": {
+					Type:        framework.TypeString,
+					Description: "Type of the requested token; if set, must be " + tokenTypeAccessToken,
+				},
+				"audience": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Intended audience of the exchanged token",
+				},
+				"policies": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "List of policies for the exchanged token; must be a subset of the policies of the subject token",
+				},
+				"ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Time to live for the exchanged token",
+				},
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: ts.handleExchange,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(tokenExchangeHelp),
+			HelpDescription: strings.TrimSpace(tokenExchangeDesc),
+		},
+
+		{
+			Pattern: "batch-tokens/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixToken,
+				OperationVerb:   "list",
+				OperationSuffix: "batch-tokens",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"role": {
+					Type:        framework.TypeString,
+					Description: "Only list the batch tokens issued by this role",
+					Query:       true,
+				},
+				"entity_id": {
+					Type:        framework.TypeString,
+					Description: "Only list the batch tokens issued to this entity",
+					Query:       true,
+				},
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: ts.handleBatchTokensList,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(tokenBatchTokensHelp),
+			HelpDescription: strings.TrimSpace(tokenBatchTokensDesc),
+		},
+
+		{
+			Pattern: "batch-tokens/revoke$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixToken,
+				OperationVerb:   "revoke",
+				OperationSuffix: "batch-tokens",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"issued_before": {
+					Type:        framework.TypeTime,
+					Description: "Revoke the batch tokens issued before this time. Defaults to now",
+				},
+				"role": {
+					Type:        framework.TypeString,
+					Description: "Only revoke the batch tokens issued by this role",
+				},
+				"entity_id": {
+					Type:        framework.TypeString,
+					Description: "Only revoke the batch tokens issued to this entity",
+				},
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: ts.handleBatchTokensRevoke,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(tokenBatchRevokeHelp),
+			HelpDescription: strings.TrimSpace(tokenBatchRevokeDesc),
+		},
+
+		{
+			Pattern: "batch-tokens/revocations/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixToken,
+				OperationSuffix: "batch-token-revocations",
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: ts.handleBatchRevocationsList,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(tokenBatchRevocationsHelp),
+			HelpDescription: strings.TrimSpace(tokenBatchRevocationsHelp),
+		},
+
+		{
+			Pattern: "batch-tokens/revocations/" + framework.GenericNameRegex("id") + "$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixToken,
+				OperationSuffix: "batch-token-revocation",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"id": {
+					Type:        framework.TypeString,
+					Description: "ID of the batch token revocation",
+				},
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.DeleteOperation: ts.handleBatchRevocationDelete,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(tokenBatchRevocationsHelp),
+			HelpDescription: strings.TrimSpace(tokenBatchRevocationsHelp),
+		},
+
 		{
 			Pattern: "lookup",
 
@@ -861,6 +1010,13 @@ type TokenStore struct {
 	// number of times all nodes in the cluster have stepped down. Currently the only sync
 	// point is a DR cluster promoting to the primary.
 	sscTokensGenerationCounter SSCTokenGenerationCounter
+
+	// batchTokens keeps track of the batch tokens issued by this node
+	batchTokens *batchTokenTracker
+
+	// batchRevocationsCache holds the batch token revocations by namespace ID
+	batchRevocationsLock  sync.RWMutex
+	batchRevocationsCache map[string][]*batchTokenRevocation
 }
 
 // NewTokenStore is used to construct a token store that is
@@ -887,6 +1043,8 @@ func NewTokenStore(ctx context.Context, logger log.Logger, core *Core, config *l
 		tidyLock:              new(uint32),
 		quitContext:           core.activeContext,
 		salts:                 make(map[string]*salt.Salt),
+		batchTokens:           newBatchTokenTracker(),
+		batchRevocationsCache: make(map[string][]*batchTokenRevocation),
 	}
 
 	// Setup the framework endpoints
@@ -897,6 +1055,7 @@ func NewTokenStore(ctx context.Context, logger log.Logger, core *Core, config *l
 			Root: []string{
 				"revoke-orphan/*",
 				"accessors*",
+				"batch-tokens*",
 			},
 
 			// Most token store items are local since tokens are local, but a
@@ -928,6 +1087,10 @@ func (ts *TokenStore) Invalidate(ctx context.Context, key string) {
 		ts.saltLock.Lock()
 		ts.salts = make(map[string]*salt.Salt)
 		ts.saltLock.Unlock()
+	default:
+		if strings.Contains(key, tokenSubPath+batchRevocationPrefix) {
+			ts.invalidateBatchRevocations()
+		}
 	}
 }
 
@@ -1322,6 +1485,7 @@ func (ts *TokenStore) create(ctx context.Context, entry *logical.TokenEntry) err
 			entry.ID = fmt.Sprintf("%s.%s", entry.ID, tokenNS.ID)
 		}
 
+		ts.batchTokens.track(entry)
 		return nil
 
 	default:
@@ -1646,6 +1810,14 @@ func (ts *TokenStore) lookupBatchToken(ctx context.Context, id string) (*logical
 		return nil, nil
 	}
 
+	revoked, err := ts.batchTokenRevoked(ctx, te)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, nil
+	}
+
 	if te.Parent != "" {
 		pte, err := ts.Lookup(ctx, te.Parent)
 		if err != nil {
@@ -4303,6 +4475,7 @@ as revocation of tokens. The tokens are renewable if associated with a lease.`
 	tokenCreateHelp          = `The token create path is used to create new tokens.`
 	tokenCreateOrphanHelp    = `The token create path is used to create new orphan tokens.`
 	tokenCreateRoleHelp      = `This token create path is used to create new tokens adhering to the given role.`
+	tokenExchangeHelp        = `This endpoint exchanges a token for a delegated token with a subset of its policies.`
 	tokenListRolesHelp       = `This endpoint lists configured roles.`
 	tokenLookupAccessorHelp  = `This endpoint will lookup a token associated with the given accessor and its properties. Response will not contain the token ID.`
 	tokenRenewAccessorHelp   = `This endpoint will renew a token associated with the given accessor and its properties. Response will not contain the token ID.`
@@ -4349,7 +4522,31 @@ no effect on the token being renewed.`
 	tokenRenewableHelp = `Tokens created via this role will be
 renewable or not according to this value.
 Defaults to "true".`
-	tokenListAccessorsHelp = `List token accessors, which can then be
+	tokenExchangeDesc = `
+This endpoint implements OAuth 2.0 token exchange (RFC 8693). The subject token,
+which is either a Vault token or an identity token, is exchanged for a new
+non-renewable token with the requested subset of policies and audience. Vault
+tokens are exchanged for a child of the subject token; identity tokens are
+exchanged for a child of the calling token. The subject, the actor and the chain
+of actors of the exchange are recorded in the metadata of the new token.
+`
+	tokenBatchTokensHelp = `List the outstanding batch tokens issued by this node.`
+	tokenBatchTokensDesc = `
+Batch tokens aren't stored, so they have no accessors. Instead, each node keeps
+track of the batch tokens it issued since it was unsealed, grouped by the role
+that issued them and the entity they were issued to. Because this can be used
+to discover the entities using Vault, this endpoint requires 'sudo' capability.
+`
+	tokenBatchRevokeHelp = `Revoke the batch tokens issued before a cutoff.`
+	tokenBatchRevokeDesc = `
+Revokes the batch tokens of the namespace issued before issued_before, which
+defaults to now, optionally only those issued by a role or to an entity. Batch
+tokens are revoked on every node, including those issued by other nodes. Token
+creation times have a precision of seconds, so tokens issued within the second
+of the cutoff are revoked too.
+`
+	tokenBatchRevocationsHelp = `List or delete the revocations of batch tokens.`
+	tokenListAccessorsHelp    = `List token accessors, which can then be
 be used to iterate and discover their properties
 or revoke them. Because this can be used to
 cause a denial of service, this endpoint
@@ -0,0 +1,388 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// batchRevocationPrefix is the prefix used to store the revocations of batch
+// tokens. It isn't local, so batch tokens issued by a primary cluster are
+// also revoked on its secondaries.
+const batchRevocationPrefix = "batch-revocation/"
+
+// batchTokenRevocation invalidates the batch tokens of a namespace issued
+// before a cutoff, optionally only those issued by a role or to an entity.
+// Batch tokens aren't stored, so they are checked against the revocations
+// every time they are looked up.
+type batchTokenRevocation struct {
+	ID           string    `json:"id"`
+	NamespaceID  string    `json:"namespace_id"`
+	Role         string    `json:"role"`
+	EntityID     string    `json:"entity_id"`
+	IssuedBefore time.Time `json:"issued_before"`
+	CreationTime time.Time `json:"creation_time"`
+}
+
+// revokes returns whether the batch token is revoked. Token creation times
+// only have a precision of seconds, so tokens issued within the second of the
+// cutoff are revoked too.
+func (r *batchTokenRevocation) revokes(te *logical.TokenEntry) bool {
+	if te.NamespaceID != r.NamespaceID {
+		return false
+	}
+	if r.Role != "" && te.Role != r.Role {
+		return false
+	}
+	if r.EntityID != "" && te.EntityID != r.EntityID {
+		return false
+	}
+	return te.CreationTime <= r.IssuedBefore.Unix()
+}
+
+// batchTokenGroup tracks the batch tokens issued by a node with the same
+// role to the same entity.
+type batchTokenGroup struct {
+	NamespaceID    string
+	Role           string
+	EntityID       string
+	Count          int
+	FirstIssueTime time.Time
+	LastIssueTime  time.Time
+	LatestExpiry   time.Time
+}
+
+type batchTokenGroupKey struct {
+	namespaceID string
+	role        string
+	entityID    string
+}
+
+// batchTokenTracker is the bookkeeping of the batch tokens issued by a node,
+// standing in for the accessors of service tokens. Tokens are only counted
+// per group, and groups are dropped once all their tokens expired.
+type batchTokenTracker struct {
+	l      sync.Mutex
+	groups map[batchTokenGroupKey]*batchTokenGroup
+}
+
+func newBatchTokenTracker() *batchTokenTracker {
+	return &batchTokenTracker{
+		groups: make(map[batchTokenGroupKey]*batchTokenGroup),
+	}
+}
+
+func (t *batchTokenTracker) track(te *logical.TokenEntry) {
+	issued := time.Unix(te.CreationTime, 0)
+	expiry := issued.Add(te.TTL)
+	key := batchTokenGroupKey{
+		namespaceID: te.NamespaceID,
+		role:        te.Role,
+		entityID:    te.EntityID,
+	}
+
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	group, ok := t.groups[key]
+	if !ok {
+		group = &batchTokenGroup{
+			NamespaceID:    te.NamespaceID,
+			Role:           te.Role,
+			EntityID:       te.EntityID,
+			FirstIssueTime: issued,
+		}
+		t.groups[key] = group
+	}
+	group.Count++
+	group.LastIssueTime = issued
+	if expiry.After(group.LatestExpiry) {
+		group.LatestExpiry = expiry
+	}
+}
+
+// revoke drops the groups whose tokens are all revoked by the revocation.
+// The tokens of the other groups can't be told apart, so their count is kept.
+func (t *batchTokenTracker) revoke(r *batchTokenRevocation) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	for key, group := range t.groups {
+		if key.namespaceID != r.NamespaceID ||
+			(r.Role != "" && key.role != r.Role) ||
+			(r.EntityID != "" && key.entityID != r.EntityID) {
+			continue
+		}
+		if group.LastIssueTime.Unix() <= r.IssuedBefore.Unix() {
+			delete(t.groups, key)
+		}
+	}
+}
+
+// list returns copies of the groups of the namespace with outstanding tokens,
+// dropping the expired groups.
+func (t *batchTokenTracker) list(nsID, role, entityID string) []batchTokenGroup {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	now := time.Now()
+	var groups []batchTokenGroup
+	for key, group := range t.groups {
+		if now.After(group.LatestExpiry) {
+			delete(t.groups, key)
+			continue
+		}
+		if key.namespaceID != nsID ||
+			(role != "" && key.role != role) ||
+			(entityID != "" && key.entityID != entityID) {
+			continue
+		}
+		groups = append(groups, *group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Role != groups[j].Role {
+			return groups[i].Role < groups[j].Role
+		}
+		return groups[i].EntityID < groups[j].EntityID
+	})
+	return groups
+}
+
+func (ts *TokenStore) batchRevocationView(ns *namespace.Namespace) *BarrierView {
+	return ts.baseView(ns).SubView(batchRevocationPrefix)
+}
+
+// batchRevocations returns the batch token revocations of the namespace,
+// loading them from storage unless they are cached.
+func (ts *TokenStore) batchRevocations(ctx context.Context, ns *namespace.Namespace) ([]*batchTokenRevocation, error) {
+	ts.batchRevocationsLock.RLock()
+	revocations, ok := ts.batchRevocationsCache[ns.ID]
+	ts.batchRevocationsLock.RUnlock()
+	if ok {
+		return revocations, nil
+	}
+
+	ts.batchRevocationsLock.Lock()
+	defer ts.batchRevocationsLock.Unlock()
+	if revocations, ok := ts.batchRevocationsCache[ns.ID]; ok {
+		return revocations, nil
+	}
+
+	view := ts.batchRevocationView(ns)
+	ids, err := view.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	revocations = make([]*batchTokenRevocation, 0, len(ids))
+	for _, id := range ids {
+		entry, err := view.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+		revocation := new(batchTokenRevocation)
+		if err := entry.DecodeJSON(revocation); err != nil {
+			return nil, fmt.Errorf("failed to decode batch token revocation %q: %w", id, err)
+		}
+		revocations = append(revocations, revocation)
+	}
+
+	ts.batchRevocationsCache[ns.ID] = revocations
+	return revocations, nil
+}
+
+// batchTokenRevoked returns whether the batch token was revoked.
+func (ts *TokenStore) batchTokenRevoked(ctx context.Context, te *logical.TokenEntry) (bool, error) {
+	ns, err := ts.core.NamespaceByID(ctx, te.NamespaceID)
+	if err != nil {
+		return false, err
+	}
+	if ns == nil {
+		return false, nil
+	}
+
+	revocations, err := ts.batchRevocations(ctx, ns)
+	if err != nil {
+		return false, err
+	}
+	for _, revocation := range revocations {
+		if revocation.revokes(te) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (ts *TokenStore) invalidateBatchRevocations() {
+	ts.batchRevocationsLock.Lock()
+	ts.batchRevocationsCache = make(map[string][]*batchTokenRevocation)
+	ts.batchRevocationsLock.Unlock()
+}
+
+// batchRevocationObsolete returns whether every token the revocation applies
+// to has expired, as batch tokens can't outlive the max TTL of the token store.
+func (ts *TokenStore) batchRevocationObsolete(r *batchTokenRevocation) bool {
+	return time.Since(r.IssuedBefore) > ts.System().MaxLeaseTTL()
+}
+
+func (ts *TokenStore) handleBatchTokensList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := ts.batchTokens.list(ns.ID, d.Get("role").(string), d.Get("entity_id").(string))
+
+	ret := make([]map[string]interface{}, 0, len(groups))
+	for _, group := range groups {
+		ret = append(ret, map[string]interface{}{
+			"role":             group.Role,
+			"entity_id":        group.EntityID,
+			"count":            group.Count,
+			"first_issue_time": group.FirstIssueTime.Format(time.RFC3339),
+			"last_issue_time":  group.LastIssueTime.Format(time.RFC3339),
+			"expire_time":      group.LatestExpiry.Format(time.RFC3339),
+		})
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"groups": ret,
+		},
+	}, nil
+}
+
+func (ts *TokenStore) handleBatchTokensRevoke(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	issuedBefore := now
+	if raw, ok := d.GetOk("issued_before"); ok {
+		issuedBefore = raw.(time.Time)
+		if issuedBefore.After(now) {
+			return logical.ErrorResponse("issued_before must not be in the future"), logical.ErrInvalidRequest
+		}
+	}
+
+	revocation := &batchTokenRevocation{
+		NamespaceID:  ns.ID,
+		Role:         d.Get("role").(string),
+		EntityID:     d.Get("entity_id").(string),
+		IssuedBefore: issuedBefore,
+		CreationTime: now,
+	}
+	if ts.batchRevocationObsolete(revocation) {
+		return logical.ErrorResponse("every batch token issued before issued_before has already expired"), logical.ErrInvalidRequest
+	}
+
+	if err := ts.revokeBatchTokens(ctx, ns, revocation); err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"id": revocation.ID,
+		},
+	}, nil
+}
+
+// revokeBatchTokens stores the revocation, assigning it an ID, and drops the
+// revocations of the namespace no token can match anymore while at it.
+func (ts *TokenStore) revokeBatchTokens(ctx context.Context, ns *namespace.Namespace, revocation *batchTokenRevocation) error {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return err
+	}
+	revocation.ID = id
+
+	view := ts.batchRevocationView(ns)
+	entry, err := logical.StorageEntryJSON(id, revocation)
+	if err != nil {
+		return err
+	}
+	if err := view.Put(ctx, entry); err != nil {
+		return err
+	}
+
+	revocations, err := ts.batchRevocations(ctx, ns)
+	if err != nil {
+		return err
+	}
+	for _, r := range revocations {
+		if ts.batchRevocationObsolete(r) {
+			if err := view.Delete(ctx, r.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	ts.invalidateBatchRevocations()
+	ts.batchTokens.revoke(revocation)
+	return nil
+}
+
+func (ts *TokenStore) handleBatchRevocationsList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revocations, err := ts.batchRevocations(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	keyInfo := make(map[string]interface{})
+	for _, r := range revocations {
+		if ts.batchRevocationObsolete(r) {
+			continue
+		}
+		keys = append(keys, r.ID)
+		keyInfo[r.ID] = map[string]interface{}{
+			"role":          r.Role,
+			"entity_id":     r.EntityID,
+			"issued_before": r.IssuedBefore.Format(time.RFC3339),
+			"creation_time": r.CreationTime.Format(time.RFC3339),
+		}
+	}
+	sort.Strings(keys)
+
+	return logical.ListResponseWithInfo(keys, keyInfo), nil
+}
+
+func (ts *TokenStore) handleBatchRevocationDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := d.Get("id").(string)
+	if id == "" || strings.Contains(id, "/") {
+		return logical.ErrorResponse("invalid id"), logical.ErrInvalidRequest
+	}
+
+	if err := ts.batchRevocationView(ns).Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	ts.invalidateBatchRevocations()
+
+	return nil, nil
+}
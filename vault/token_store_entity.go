@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/vault/helper/namespace"
+)
+
+// EntityTokens reports the tokens of an entity that were revoked, or would be
+// revoked by a dry run.
+type EntityTokens struct {
+	// Accessors are the accessors of the service tokens of the entity.
+	Accessors []string
+
+	// Leases is the number of leases created with the service tokens, which
+	// are revoked along with them.
+	Leases int
+
+	// BatchTokens is the number of unexpired batch tokens of the entity
+	// issued by this node. Batch tokens issued by other nodes are revoked
+	// too, but can't be counted.
+	BatchTokens int
+}
+
+// RevokeEntityTokens revokes every token of the entity along with their
+// leases. Nothing is revoked on a dry run.
+func (c *Core) RevokeEntityTokens(ctx context.Context, entityID string, dryRun bool) (*EntityTokens, error) {
+	if c.tokenStore == nil {
+		return nil, errors.New("unable to revoke tokens with nil token store")
+	}
+
+	return c.tokenStore.revokeEntityTokens(ctx, entityID, dryRun)
+}
+
+// revokeEntityTokens expects the context to carry the namespace of the
+// entity.
+func (ts *TokenStore) revokeEntityTokens(ctx context.Context, entityID string, dryRun bool) (*EntityTokens, error) {
+	if entityID == "" {
+		return nil, errors.New("missing entity ID")
+	}
+
+	entityNS, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &EntityTokens{
+		Accessors: []string{},
+	}
+	now := time.Now()
+
+	// Batch tokens aren't stored, so the revocation of those issued by
+	// other nodes is only recorded in the namespaces the entity is known to
+	// hold tokens in, rather than in every namespace.
+	revokeNamespaces := map[string]bool{
+		entityNS.ID: true,
+	}
+
+	// Tokens can be issued to an entity in the namespaces below its own, so
+	// the tokens of every namespace are scanned.
+	namespaces := ts.core.ListNamespaces(false)
+	for _, ns := range namespaces {
+		nsCtx := namespace.ContextWithNamespace(ctx, ns)
+
+		saltedAccessors, err := ts.accessorView(ns).List(nsCtx, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch accessor index entries: %w", err)
+		}
+
+		for _, saltedAccessor := range saltedAccessors {
+			aEntry, err := ts.lookupByAccessor(nsCtx, saltedAccessor, true, false)
+			if err != nil || aEntry == nil || aEntry.TokenID == "" {
+				continue
+			}
+
+			te, err := ts.Lookup(nsCtx, aEntry.TokenID)
+			if err != nil {
+				return nil, err
+			}
+			// The token may have been revoked along with its parent
+			if te == nil || te.EntityID != entityID {
+				continue
+			}
+
+			leases, err := ts.expiration.lookupLeasesByToken(nsCtx, te)
+			if err != nil {
+				return nil, err
+			}
+			ret.Accessors = append(ret.Accessors, te.Accessor)
+			ret.Leases += len(leases)
+			revokeNamespaces[te.NamespaceID] = true
+
+			if dryRun {
+				continue
+			}
+
+			tokenNS, err := NamespaceByID(nsCtx, te.NamespaceID, ts.core)
+			if err != nil {
+				return nil, err
+			}
+			if tokenNS == nil {
+				return nil, namespace.ErrNoNamespace
+			}
+
+			revokeCtx := namespace.ContextWithNamespace(ts.quitContext, tokenNS)
+			leaseID, err := ts.expiration.CreateOrFetchRevocationLeaseByToken(revokeCtx, te)
+			if err != nil {
+				return nil, err
+			}
+			if err := ts.expiration.Revoke(revokeCtx, leaseID); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, group := range ts.batchTokens.list(ns.ID, "", entityID) {
+			ret.BatchTokens += group.Count
+			revokeNamespaces[ns.ID] = true
+		}
+	}
+
+	if !dryRun {
+		for _, ns := range namespaces {
+			if !revokeNamespaces[ns.ID] {
+				continue
+			}
+
+			if err := ts.revokeBatchTokens(namespace.ContextWithNamespace(ctx, ns), ns, &batchTokenRevocation{
+				NamespaceID:  ns.ID,
+				EntityID:     entityID,
+				IssuedBefore: now,
+				CreationTime: now,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Strings(ret.Accessors)
+	return ret, nil
+}
@@ -0,0 +1,248 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/hashicorp/vault/helper/metricsutil"
+	"github.com/hashicorp/vault/helper/namespace"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/policyutil"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+const (
+	// tokenExchangeGrantType is the OAuth 2.0 grant type of token exchange
+	// requests, see RFC 8693 section 2.1
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	// tokenTypeAccessToken identifies Vault tokens in token exchange requests
+	tokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+	// tokenTypeIDToken identifies identity tokens in token exchange requests
+	tokenTypeIDToken = "urn:ietf:params:oauth:token-type:id_token"
+
+	// tokenTypeJWT is accepted as an alias of tokenTypeIDToken
+	tokenTypeJWT = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// Metadata keys recording the exchange on the tokens it issues
+const (
+	tokenExchangeMetaSubject  = "exchange_subject"
+	tokenExchangeMetaActor    = "exchange_actor"
+	tokenExchangeMetaAudience = "exchange_audience"
+	tokenExchangeMetaChain    = "exchange_chain"
+)
+
+// handleExchange implements token exchange: the subject token, which is either
+// a Vault token or an identity token, is traded for a new, non-renewable child
+// token with a subset of the policies of the token it is derived from. The
+// token is derived from the subject token if it's a Vault token, and from the
+// calling token if it's an identity token. The subject, the actors and the
+// audience of the exchange are recorded in the metadata of the new token.
+func (ts *TokenStore) handleExchange(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if grantType := d.Get("grant_type").(string); grantType != "" && grantType != tokenExchangeGrantType {
+		return logical.ErrorResponse("unsupported grant_type %q", grantType), logical.ErrInvalidRequest
+	}
+	if requested := d.Get("requested_token_type").(string); requested != "" && requested != tokenTypeAccessToken {
+		return logical.ErrorResponse("unsupported requested_token_type %q", requested), logical.ErrInvalidRequest
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := ts.Lookup(ctx, req.ClientToken)
+	if err != nil {
+		return nil, fmt.Errorf("actor token lookup failed: %w", err)
+	}
+	if actor == nil {
+		return logical.ErrorResponse("actor token lookup failed: no token found"), logical.ErrInvalidRequest
+	}
+
+	// base is the token the new token is derived from
+	var base *logical.TokenEntry
+	var subject string
+
+	subjectToken := d.Get("subject_token").(string)
+	switch subjectTokenType := d.Get("subject_token_type").(string); subjectTokenType {
+	case "", tokenTypeAccessToken:
+		if subjectToken == "" {
+			base = actor
+		} else {
+			base, err = ts.Lookup(ctx, subjectToken)
+			if err != nil {
+				return nil, fmt.Errorf("subject token lookup failed: %w", err)
+			}
+			if base == nil {
+				return logical.ErrorResponse("invalid subject token"), logical.ErrInvalidRequest
+			}
+		}
+		subject = tokenExchangeIdentity(base)
+
+	case tokenTypeIDToken, tokenTypeJWT:
+		if subjectToken == "" {
+			return logical.ErrorResponse("subject_token must be specified for identity tokens"), logical.ErrInvalidRequest
+		}
+		entity, invalidReason, err := ts.core.identityStore.validateIdentityToken(ctx, ts.core.identityStore.view, subjectToken, "")
+		if err != nil {
+			return nil, err
+		}
+		if invalidReason != "" {
+			return logical.ErrorResponse("invalid subject token: %s", invalidReason), logical.ErrInvalidRequest
+		}
+		base = actor
+		subject = entity.ID
+
+	default:
+		return logical.ErrorResponse("unsupported subject_token_type %q", subjectTokenType), logical.ErrInvalidRequest
+	}
+
+	if base.Type == logical.TokenTypeBatch {
+		return logical.ErrorResponse("batch tokens cannot be exchanged"), logical.ErrInvalidRequest
+	}
+	if base.NumUses > 0 {
+		return logical.ErrorResponse("restricted use tokens cannot be exchanged"), logical.ErrInvalidRequest
+	}
+	if base.NamespaceID != ns.ID {
+		return logical.ErrorResponse("tokens can only be exchanged in their own namespace"), logical.ErrInvalidRequest
+	}
+
+	// The policies must be a subset of the policies of the base token. Unlike
+	// token creation, the default policy is only added when requested.
+	basePolicies := policyutil.SanitizePolicies(base.Policies, policyutil.DoNotAddDefaultPolicy)
+	policies := policyutil.SanitizePolicies(d.Get("policies").([]string), policyutil.DoNotAddDefaultPolicy)
+	if len(policies) == 0 {
+		policies = basePolicies
+	}
+	if !strutil.StrListContains(basePolicies, "root") && !strutil.StrListSubset(basePolicies, policies) {
+		return logical.ErrorResponse("exchanged token policies must be subset of the subject token policies"), logical.ErrInvalidRequest
+	}
+	for _, policy := range policies {
+		if strutil.StrListContains(nonAssignablePolicies, policy) {
+			return logical.ErrorResponse("cannot assign policy %q", policy), logical.ErrInvalidRequest
+		}
+	}
+	if strutil.StrListContains(policies, "root") {
+		return logical.ErrorResponse("root tokens cannot be exchanged for root tokens"), logical.ErrInvalidRequest
+	}
+
+	audience := d.Get("audience").([]string)
+
+	chain := tokenExchangeIdentity(actor)
+	if prev := base.Meta[tokenExchangeMetaChain]; prev != "" {
+		chain = prev + "," + chain
+	}
+
+	te := logical.TokenEntry{
+		Parent:       base.ID,
+		Path:         "auth/token/exchange",
+		Policies:     policies,
+		DisplayName:  "token-exchange",
+		CreationTime: time.Now().Unix(),
+		NamespaceID:  ns.ID,
+		Type:         logical.TokenTypeService,
+		EntityID:     base.EntityID,
+		BoundCIDRs:   base.BoundCIDRs,
+		Meta: map[string]string{
+			tokenExchangeMetaSubject: subject,
+			tokenExchangeMetaActor:   tokenExchangeIdentity(actor),
+			tokenExchangeMetaChain:   chain,
+		},
+	}
+	if len(audience) > 0 {
+		te.Meta[tokenExchangeMetaAudience] = strings.Join(audience, ",")
+	}
+
+	resp := &logical.Response{}
+
+	sysView := ts.System().(extendedSystemView)
+	ttl, warnings, err := framework.CalculateTTL(sysView, 0, time.Duration(d.Get("ttl").(int))*time.Second, 0, 0, 0, time.Unix(te.CreationTime, 0))
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		resp.AddWarning(warning)
+	}
+
+	// The exchanged token must not outlive the token it is derived from
+	baseLeaseTimes, err := ts.expiration.FetchLeaseTimesByToken(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	if baseLeaseTimes != nil && !baseLeaseTimes.ExpireTime.IsZero() {
+		remaining := time.Until(baseLeaseTimes.ExpireTime)
+		if remaining <= 0 {
+			return logical.ErrorResponse("subject token has expired"), logical.ErrInvalidRequest
+		}
+		if ttl == 0 || ttl > remaining {
+			ttl = remaining.Truncate(time.Second)
+			resp.AddWarning(fmt.Sprintf("TTL of exchanged token capped to the remaining TTL of the subject token, %d seconds", int64(ttl.Seconds())))
+		}
+	}
+	te.TTL = ttl
+	te.ExplicitMaxTTL = ttl
+
+	if ts.core.perfStandby {
+		forwardedTokenEntry, err := forwardCreateTokenRegisterAuth(ctx, ts.core, &te, "", false, 0, ttl)
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), ErrInternalError
+		}
+		te = *forwardedTokenEntry
+	} else {
+		if err := ts.create(ctx, &te); err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+	}
+
+	ts.core.metricSink.IncrCounterWithLabels(
+		[]string{"token", "exchange"},
+		1,
+		[]metrics.Label{
+			metricsutil.NamespaceLabel(ns),
+			{Name: "creation_ttl", Value: metricsutil.TTLBucket(te.TTL)},
+		},
+	)
+
+	resp.Auth = &logical.Auth{
+		DisplayName: te.DisplayName,
+		Policies:    te.Policies,
+		Metadata:    te.Meta,
+		LeaseOptions: logical.LeaseOptions{
+			TTL:       te.TTL,
+			Renewable: false,
+		},
+		ClientToken:    te.ID,
+		Accessor:       te.Accessor,
+		EntityID:       te.EntityID,
+		ExplicitMaxTTL: te.ExplicitMaxTTL,
+		CreationPath:   te.Path,
+		TokenType:      te.Type,
+	}
+	if ts.core.perfStandby && te.ExternalID != "" {
+		resp.Auth.ClientToken = te.ExternalID
+	}
+	resp.Data = map[string]interface{}{
+		"issued_token_type": tokenTypeAccessToken,
+	}
+
+	return resp, nil
+}
+
+// tokenExchangeIdentity returns the identifier of the party holding a token
+// that is recorded by token exchange: the entity ID, or the display name of
+// tokens without an entity.
+func tokenExchangeIdentity(te *logical.TokenEntry) string {
+	if te.EntityID != "" {
+		return te.EntityID
+	}
+	return te.DisplayName
+}
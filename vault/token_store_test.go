@@ -6179,3 +6179,163 @@ func TestTokenStore_Collectors(t *testing.T) {
 	// Need to set up router for this to work, TODO
 	// ts.gaugeCollectorByMethod( ctx )
 }
+
+func TestTokenStore_HandleRequest_Exchange(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	testMakeTokenViaCore(t, c, root, "subject", "1h", "", []string{"foo", "bar"}, false, nil)
+
+	exchange := func(clientToken string, data map[string]interface{}) (*logical.Response, error) {
+		req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/exchange")
+		req.ClientToken = clientToken
+		req.Data = data
+		return c.HandleRequest(ctx, req)
+	}
+
+	// The root token exchanges the subject token for one with less policies
+	resp, err := exchange(root, map[string]interface{}{
+		"grant_type":    tokenExchangeGrantType,
+		"subject_token": "subject",
+		"audience":      "billing",
+		"policies":      "foo",
+		"ttl":           "2h",
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if !reflect.DeepEqual(resp.Auth.TokenPolicies, []string{"foo"}) {
+		t.Fatalf("bad policies: %#v", resp.Auth.TokenPolicies)
+	}
+	if resp.Auth.Renewable {
+		t.Fatal("expected exchanged token to not be renewable")
+	}
+	if resp.Auth.TTL > time.Hour {
+		t.Fatalf("expected TTL to be capped to the subject token TTL, got %s", resp.Auth.TTL)
+	}
+	if resp.Auth.Metadata[tokenExchangeMetaAudience] != "billing" || resp.Auth.Metadata[tokenExchangeMetaActor] != "root" {
+		t.Fatalf("bad metadata: %#v", resp.Auth.Metadata)
+	}
+	delegated := resp.Auth.ClientToken
+
+	te, err := c.tokenStore.Lookup(ctx, delegated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te == nil || te.Parent != "subject" {
+		t.Fatalf("expected the exchanged token to be a child of the subject token: %#v", te)
+	}
+
+	// Exchanged tokens can be exchanged again, which extends the chain
+	resp, err = exchange(root, map[string]interface{}{
+		"subject_token": delegated,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err: %v\nresp: %#v", err, resp)
+	}
+	if resp.Auth.Metadata[tokenExchangeMetaChain] != "root,root" {
+		t.Fatalf("bad chain: %q", resp.Auth.Metadata[tokenExchangeMetaChain])
+	}
+
+	// Policies cannot be escalated
+	resp, err = exchange(root, map[string]interface{}{
+		"subject_token": "subject",
+		"policies":      "foo,baz",
+	})
+	if !errwrap.Contains(err, logical.ErrInvalidRequest.Error()) {
+		t.Fatalf("expected invalid request, got %v: %#v", err, resp)
+	}
+
+	// Unknown subject tokens are rejected
+	resp, err = exchange(root, map[string]interface{}{
+		"subject_token": "unknown",
+	})
+	if !errwrap.Contains(err, logical.ErrInvalidRequest.Error()) {
+		t.Fatalf("expected invalid request, got %v: %#v", err, resp)
+	}
+
+	// Revoking the subject token revokes the exchanged token
+	if err := c.tokenStore.revokeTree(ctx, &leaseEntry{ClientToken: "subject", namespace: namespace.RootNamespace}); err != nil {
+		t.Fatal(err)
+	}
+	te, err = c.tokenStore.Lookup(ctx, delegated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te != nil {
+		t.Fatalf("expected the exchanged token to be revoked: %#v", te)
+	}
+}
+
+func TestTokenStore_Batch_Revocations(t *testing.T) {
+	core, _, root := TestCoreUnsealed(t)
+	ts := core.tokenStore
+	ctx := namespace.RootContext(nil)
+
+	mustHandle := func(t *testing.T, op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := ts.HandleRequest(ctx, &logical.Request{
+			Operation:   op,
+			Path:        path,
+			Data:        data,
+			ClientToken: root,
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v, resp: %#v", err, resp)
+		}
+		return resp
+	}
+	mustLookup := func(t *testing.T, token string, valid bool) {
+		t.Helper()
+		te, err := ts.Lookup(ctx, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (te != nil) != valid {
+			t.Fatalf("expected token to be valid: %t, got: %#v", valid, te)
+		}
+	}
+
+	mustHandle(t, logical.UpdateOperation, "roles/batch", map[string]interface{}{
+		"token_type":       "batch",
+		"orphan":           true,
+		"allowed_policies": "policy1",
+	})
+	roleToken := mustHandle(t, logical.UpdateOperation, "create/batch", map[string]interface{}{
+		"policies": "policy1",
+	}).Auth.ClientToken
+	plainToken := mustHandle(t, logical.UpdateOperation, "create", map[string]interface{}{
+		"type":     "batch",
+		"policies": "policy1",
+	}).Auth.ClientToken
+
+	resp := mustHandle(t, logical.ReadOperation, "batch-tokens", nil)
+	if groups := resp.Data["groups"].([]map[string]interface{}); len(groups) != 2 || groups[0]["count"] != 1 || groups[1]["role"] != "batch" {
+		t.Fatalf("unexpected groups: %#v", resp.Data)
+	}
+
+	resp = mustHandle(t, logical.UpdateOperation, "batch-tokens/revoke", map[string]interface{}{
+		"role": "batch",
+	})
+	id := resp.Data["id"].(string)
+
+	mustLookup(t, roleToken, false)
+	mustLookup(t, plainToken, true)
+
+	resp = mustHandle(t, logical.ReadOperation, "batch-tokens", nil)
+	if groups := resp.Data["groups"].([]map[string]interface{}); len(groups) != 1 || groups[0]["role"] != "" {
+		t.Fatalf("unexpected groups: %#v", resp.Data)
+	}
+
+	resp = mustHandle(t, logical.ListOperation, "batch-tokens/revocations/", nil)
+	if keys := resp.Data["keys"].([]string); len(keys) != 1 || keys[0] != id {
+		t.Fatalf("unexpected revocations: %#v", resp.Data)
+	}
+
+	// Deleting the revocation makes the tokens valid again
+	mustHandle(t, logical.DeleteOperation, "batch-tokens/revocations/"+id, nil)
+	mustLookup(t, roleToken, true)
+
+	mustHandle(t, logical.UpdateOperation, "batch-tokens/revoke", nil)
+	mustLookup(t, roleToken, false)
+	mustLookup(t, plainToken, false)
+}
@@ -10,21 +10,21 @@ import (
 )
 
 func (ts *TokenStore) baseView(ns *namespace.Namespace) *BarrierView {
-	return ts.baseBarrierView
+	return ts.core.namespacedView(ns, ts.baseBarrierView)
 }
 
 func (ts *TokenStore) idView(ns *namespace.Namespace) *BarrierView {
-	return ts.idBarrierView
+	return ts.core.namespacedView(ns, ts.idBarrierView)
 }
 
 func (ts *TokenStore) accessorView(ns *namespace.Namespace) *BarrierView {
-	return ts.accessorBarrierView
+	return ts.core.namespacedView(ns, ts.accessorBarrierView)
 }
 
 func (ts *TokenStore) parentView(ns *namespace.Namespace) *BarrierView {
-	return ts.parentBarrierView
+	return ts.core.namespacedView(ns, ts.parentBarrierView)
 }
 
 func (ts *TokenStore) rolesView(ns *namespace.Namespace) *BarrierView {
-	return ts.rolesBarrierView
+	return ts.core.namespacedView(ns, ts.rolesBarrierView)
 }